@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"yuruppu/cmd/cli/mock"
+	"yuruppu/cmd/cli/prompter"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/groupprofile"
+	"yuruppu/internal/history"
+	"yuruppu/internal/reminder"
+	"yuruppu/internal/userprofile"
+)
+
+// toolSchema is the dump-tools representation of one tool as the model receives it.
+type toolSchema struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Parameters  json.RawMessage `json:"parameters"`
+	Response    json.RawMessage `json:"response"`
+}
+
+// nopGenerator stands in for the description writer agent; dump-tools never runs a tool.
+type nopGenerator struct{}
+
+func (nopGenerator) Generate(ctx context.Context, history []agent.Message) (*agent.AssistantMessage, error) {
+	return nil, errors.New("dump-tools does not call the LLM")
+}
+
+// dumpTools builds the full toolset and writes every tool's name, description, and schemas to stdout as indented JSON.
+// Everything is kept in memory and no LLM is called, so no environment variables are needed.
+// Returns error naming the tool if a schema is not valid JSON.
+func dumpTools(stdin io.Reader, stdout, stderr io.Writer) error {
+	logger := slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	newStore := func(string) storage { return mock.NewMemoryStorage() }
+
+	userProfileService, err := userprofile.NewService(newStore("userprofile/"), logger)
+	if err != nil {
+		return fmt.Errorf("failed to create user profile service: %w", err)
+	}
+	groupProfileService, err := groupprofile.NewService(newStore("groupprofile/"), logger, groupprofile.WithMembershipIndex(newStore("groupmembership/")))
+	if err != nil {
+		return fmt.Errorf("failed to create group profile service: %w", err)
+	}
+	historyService, err := history.NewService(newStore("history/"))
+	if err != nil {
+		return fmt.Errorf("failed to create history service: %w", err)
+	}
+	reminderService, err := reminder.NewService(newStore("reminder/"))
+	if err != nil {
+		return fmt.Errorf("failed to create reminder service: %w", err)
+	}
+	lineClient := mock.NewLineClient(prompter.NewPrompter(bufio.NewScanner(stdin), stderr), &nopGroupSim{}, mock.WithOutput(stdout))
+
+	toolset, err := newToolset(lineClient, userProfileService, groupProfileService, historyService, reminderService, nopGenerator{}, newStore, logger)
+	if err != nil {
+		return err
+	}
+
+	schemas := make([]toolSchema, 0, len(toolset))
+	for _, t := range toolset {
+		params, resp := t.ParametersJsonSchema(), t.ResponseJsonSchema()
+		if !json.Valid(params) {
+			return fmt.Errorf("tool %s has an invalid parameters schema", t.Name())
+		}
+		if !json.Valid(resp) {
+			return fmt.Errorf("tool %s has an invalid response schema", t.Name())
+		}
+		schemas = append(schemas, toolSchema{
+			Name:        t.Name(),
+			Description: t.Description(),
+			Parameters:  params,
+			Response:    resp,
+		})
+	}
+
+	data, err := json.MarshalIndent(schemas, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode tool schemas: %w", err)
+	}
+	if _, err := fmt.Fprintf(stdout, "%s\n", data); err != nil {
+		return fmt.Errorf("failed to write tool schemas: %w", err)
+	}
+	return nil
+}
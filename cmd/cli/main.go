@@ -23,9 +23,18 @@ import (
 	"yuruppu/internal/history"
 	"yuruppu/internal/line"
 	"yuruppu/internal/media"
+	"yuruppu/internal/reminder"
+	"yuruppu/internal/toolset/displayname"
 	"yuruppu/internal/toolset/event"
+	"yuruppu/internal/toolset/event/card"
+	"yuruppu/internal/toolset/event/suggest"
+	"yuruppu/internal/toolset/grouplanguage"
+	"yuruppu/internal/toolset/groupreplymode"
+	"yuruppu/internal/toolset/grouptimezone"
+	"yuruppu/internal/toolset/remind"
 	"yuruppu/internal/toolset/reply"
 	"yuruppu/internal/toolset/skip"
+	"yuruppu/internal/toolset/snooze"
 	"yuruppu/internal/toolset/weather"
 	"yuruppu/internal/userprofile"
 	"yuruppu/internal/yuruppu"
@@ -44,6 +53,21 @@ type envConfig struct {
 	llmModel     string
 }
 
+// storage is the storage interface shared by all CLI services.
+type storage interface {
+	Read(ctx context.Context, key string) (data []byte, generation int64, err error)
+	Write(ctx context.Context, key, mimetype string, data []byte, expectedGeneration int64) (newGeneration int64, err error)
+	GetSignedURL(ctx context.Context, key, method string, ttl time.Duration) (string, error)
+}
+
+// newStorage returns in-memory storage when ephemeral is set, otherwise file storage under dataDir.
+func newStorage(ephemeral bool, dataDir, keyPrefix string) storage {
+	if ephemeral {
+		return mock.NewMemoryStorage()
+	}
+	return mock.NewFileStorage(dataDir, keyPrefix)
+}
+
 // nopGroupSim is a no-op implementation of mock.GroupSim for non-group mode.
 type nopGroupSim struct{}
 
@@ -82,6 +106,83 @@ func runSingleTurn(ctx context.Context, handler *bot.Handler, groupService *grou
 	return nil
 }
 
+// newToolset creates every tool the agent is offered.
+// newStore returns the storage for a key prefix.
+func newToolset(lineClient *mock.LineClient, userProfileService *userprofile.Service, groupProfileService *groupprofile.Service, historyService *history.Service, reminderService *reminder.Service, writer suggest.Generator, newStore func(keyPrefix string) storage, logger *slog.Logger) ([]agent.Tool, error) {
+	replyTool, err := reply.NewTool(lineClient, historyService, logger, reply.WithMarkdownConversion())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reply tool: %w", err)
+	}
+
+	weatherProvider, err := weather.NewProvider(weather.ProviderWttr, http.DefaultClient, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create weather provider: %w", err)
+	}
+	weatherTool, err := weather.NewTool(weatherProvider, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create weather tool: %w", err)
+	}
+
+	skipTool, err := skip.NewTool(logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create skip tool: %w", err)
+	}
+
+	// Create event service and tools
+	eventStorage := newStore("event/")
+	eventService, err := eventdomain.NewService(eventStorage, eventdomain.WithLogger(logger))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create event service: %w", err)
+	}
+	icsStorage := newStore("ics/")
+	eventTools, err := event.NewTools(eventService, lineClient, userProfileService, groupProfileService, icsStorage, weatherProvider, event.ToolsConfig{
+		TextLimits:        eventdomain.DefaultTextLimits,
+		ListMaxPeriodDays: 366,
+		ListLimit:         5,
+		CardOptions:       []card.Option{card.WithTemplate(yuruppu.EventCardTemplate)},
+	}, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create event tools: %w", err)
+	}
+
+	displayNameTool, err := displayname.NewTool(userProfileService, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create set_display_name tool: %w", err)
+	}
+
+	groupTimezoneTool, err := grouptimezone.NewTool(groupProfileService, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create set_group_timezone tool: %w", err)
+	}
+
+	groupReplyModeTool, err := groupreplymode.NewTool(groupProfileService, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create set_group_reply_mode tool: %w", err)
+	}
+
+	groupLanguageTool, err := grouplanguage.NewTool(groupProfileService, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create set_group_language tool: %w", err)
+	}
+
+	// Create set_reminder and snooze_reminder tools
+	remindTool, err := remind.NewTool(reminderService, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create set_reminder tool: %w", err)
+	}
+	snoozeTool, err := snooze.NewTool(reminderService, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create snooze_reminder tool: %w", err)
+	}
+
+	suggestTool, err := suggest.New(writer, eventdomain.DefaultTextLimits, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create suggest_description tool: %w", err)
+	}
+
+	return append([]agent.Tool{replyTool, weatherTool, skipTool, displayNameTool, groupTimezoneTool, groupReplyModeTool, groupLanguageTool, remindTool, snoozeTool, suggestTool}, eventTools...), nil
+}
+
 func loadEnvConfig() (*envConfig, error) {
 	cfg := &envConfig{
 		gcpProjectID: os.Getenv("GCP_PROJECT_ID"),
@@ -110,6 +211,7 @@ func main() {
 }
 
 // run implements the CLI logic with testable I/O.
+// If the first argument is dump-tools, it prints the tool schemas instead of starting a conversation.
 func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
 	// Validate I/O
 	if stdin == nil {
@@ -122,6 +224,11 @@ func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
 		return errors.New("stderr cannot be nil")
 	}
 
+	// Subcommands need neither flags nor environment variables
+	if len(args) > 1 && args[1] == "dump-tools" {
+		return dumpTools(stdin, stdout, stderr)
+	}
+
 	// Parse flags
 	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
 	fs.SetOutput(stderr)
@@ -130,6 +237,8 @@ func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
 	dataDir := fs.String("data-dir", ".yuruppu/", "Data directory for storage")
 	message := fs.String("message", "", "Single message to send (single-turn mode)")
 	groupID := fs.String("group-id", "", "Group ID for group chat simulation")
+	ephemeral := fs.Bool("ephemeral", false, "Keep all data in memory and write nothing to disk")
+	verboseTools := fs.Bool("verbose-tools", false, "Print each tool call's arguments and result to stderr")
 
 	if err := fs.Parse(args[1:]); err != nil {
 		return err
@@ -152,24 +261,26 @@ func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
 	}
 
 	// Ensure data directory exists
-	if err := setup.EnsureDataDir(*dataDir, stdin, stderr); err != nil {
-		return err
+	if !*ephemeral {
+		if err := setup.EnsureDataDir(*dataDir, stdin, stderr); err != nil {
+			return err
+		}
 	}
 
 	// Create shared scanner for stdin
 	scanner := bufio.NewScanner(stdin)
 
-	// Create FileStorage instances with key prefixes
-	userProfileStorage := mock.NewFileStorage(*dataDir, "userprofile/")
-	historyStorage := mock.NewFileStorage(*dataDir, "history/")
-	mediaStorage := mock.NewFileStorage(*dataDir, "media/")
+	// Create storage instances with key prefixes
+	userProfileStorage := newStorage(*ephemeral, *dataDir, "userprofile/")
+	historyStorage := newStorage(*ephemeral, *dataDir, "history/")
+	mediaStorage := newStorage(*ephemeral, *dataDir, "media/")
 
 	// Handle group mode if -group-id is specified
 	ctx := context.Background()
 	var groupService *groupsim.Service
 	if *groupID != "" {
 		var err error
-		groupService, err = setup.EnsureGroup(ctx, *dataDir, *groupID, *userID)
+		groupService, err = setup.EnsureGroup(ctx, newStorage(*ephemeral, *dataDir, "groupsim/"), *groupID, *userID)
 		if err != nil {
 			return err
 		}
@@ -182,18 +293,20 @@ func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
 	}
 
 	// Create group profile service
-	groupProfileStorage := mock.NewFileStorage(*dataDir, "groupprofile/")
-	groupProfileService, err := groupprofile.NewService(groupProfileStorage, logger)
+	groupProfileStorage := newStorage(*ephemeral, *dataDir, "groupprofile/")
+	groupMembershipStorage := newStorage(*ephemeral, *dataDir, "groupmembership/")
+	groupProfileService, err := groupprofile.NewService(groupProfileStorage, logger, groupprofile.WithMembershipIndex(groupMembershipStorage))
 	if err != nil {
 		return fmt.Errorf("failed to create group profile service: %w", err)
 	}
 
-	// Create mock LINE client with prompter and group simulator
+	// Create mock LINE client with prompter and group simulator.
+	// Bot messages go to stdout; profile prompts and diagnostics go to stderr.
 	var groupSim mock.GroupSim = &nopGroupSim{}
 	if groupService != nil {
 		groupSim = groupService
 	}
-	lineClient := mock.NewLineClient(prompter.NewPrompter(scanner, stderr), groupSim)
+	lineClient := mock.NewLineClient(prompter.NewPrompter(scanner, stderr), groupSim, mock.WithOutput(stdout))
 
 	// Create history service
 	historyService, err := history.NewService(historyStorage)
@@ -207,51 +320,65 @@ func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
 		return fmt.Errorf("failed to create media service: %w", err)
 	}
 
-	// Create tools
-	replyTool, err := reply.NewTool(lineClient, historyService, logger)
+	// Create reminder service and a dispatcher driven by /now instead of a ticker
+	reminderService, err := reminder.NewService(newStorage(*ephemeral, *dataDir, "reminder/"))
 	if err != nil {
-		return fmt.Errorf("failed to create reply tool: %w", err)
+		return fmt.Errorf("failed to create reminder service: %w", err)
 	}
-
-	weatherTool, err := weather.NewTool(http.DefaultClient, logger)
+	reminderDispatcher, err := reminder.NewDispatcher(reminderService, lineClient, time.Minute, logger, reminder.WithGroupProfiles(groupProfileService))
 	if err != nil {
-		return fmt.Errorf("failed to create weather tool: %w", err)
+		return fmt.Errorf("failed to create reminder dispatcher: %w", err)
 	}
 
-	skipTool, err := skip.NewTool(logger)
+	// Create the agent behind suggest_description; it has no tools of its own
+	writerAgent, err := agent.NewGeminiAgent(ctx, agent.GeminiConfig{
+		ProjectID:        envCfg.gcpProjectID,
+		Region:           envCfg.gcpRegion,
+		Model:            envCfg.llmModel,
+		SystemPrompt:     suggest.SystemPrompt,
+		CacheDisplayName: "yuruppu-cli-description-writer",
+		CacheTTL:         1 * time.Hour,
+	}, logger)
 	if err != nil {
-		return fmt.Errorf("failed to create skip tool: %w", err)
+		return fmt.Errorf("failed to create description writer agent: %w", err)
 	}
+	defer func() { _ = writerAgent.Close(ctx) }()
 
-	// Create event service and tools
-	eventStorage := mock.NewFileStorage(*dataDir, "event/")
-	eventService, err := eventdomain.NewService(eventStorage)
-	if err != nil {
-		return fmt.Errorf("failed to create event service: %w", err)
-	}
-	eventTools, err := event.NewTools(eventService, lineClient, userProfileService, 366, 5, logger)
+	// Create tools
+	toolset, err := newToolset(lineClient, userProfileService, groupProfileService, historyService, reminderService, writerAgent, func(keyPrefix string) storage {
+		return newStorage(*ephemeral, *dataDir, keyPrefix)
+	}, logger)
 	if err != nil {
-		return fmt.Errorf("failed to create event tools: %w", err)
+		return err
 	}
 
-	// Collect all tools
-	toolset := append([]agent.Tool{replyTool, weatherTool, skipTool}, eventTools...)
+	// Time-dependent tools and reminders follow a simulated clock that /now can move
+	simClock := mock.NewClock()
 
 	// Create GeminiAgent with tools
-	systemPrompt, err := yuruppu.GetSystemPrompt()
+	systemPrompt, err := yuruppu.GetSystemPrompt(yuruppu.PromptVars{
+		BotName: yuruppu.DefaultBotName,
+	})
 	if err != nil {
 		return fmt.Errorf("failed to get system prompt: %w", err)
 	}
-	geminiAgent, err := agent.NewGeminiAgent(ctx, agent.GeminiConfig{
+	agentConfig := agent.GeminiConfig{
 		ProjectID:        envCfg.gcpProjectID,
 		Region:           envCfg.gcpRegion,
 		Model:            envCfg.llmModel,
 		SystemPrompt:     systemPrompt,
 		Tools:            toolset,
 		FunctionCallOnly: true,
+		AnswerTools:      []string{"reply"},
 		CacheDisplayName: "yuruppu-cli",
 		CacheTTL:         1 * time.Hour,
-	}, logger)
+	}
+	if *verboseTools {
+		tracer := repl.NewToolTracer(stderr)
+		agentConfig.OnToolCall = tracer.OnToolCall
+		agentConfig.OnToolResult = tracer.OnToolResult
+	}
+	geminiAgent, err := agent.NewGeminiAgent(ctx, agentConfig, logger)
 	if err != nil {
 		return fmt.Errorf("failed to create Gemini agent with tools: %w", err)
 	}
@@ -261,6 +388,7 @@ func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
 	handlerConfig := bot.HandlerConfig{
 		TypingIndicatorDelay:   3 * time.Second,
 		TypingIndicatorTimeout: 30 * time.Second,
+		Now:                    simClock.Now,
 	}
 	handler, err := bot.NewHandler(lineClient, userProfileService, groupProfileService, historyService, mediaService, geminiAgent, handlerConfig, logger)
 	if err != nil {
@@ -286,7 +414,7 @@ func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
 	}
 
 	// REPL mode
-	r, err := repl.NewRunner(*userID, *groupID, userProfileService, groupService, handler, logger, scanner, stdout)
+	r, err := repl.NewRunner(*userID, *groupID, userProfileService, groupService, handler, logger, scanner, stdout, repl.WithClock(simClock, reminderDispatcher))
 	if err != nil {
 		return fmt.Errorf("failed to create REPL: %w", err)
 	}
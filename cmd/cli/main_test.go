@@ -3,7 +3,9 @@ package main
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"yuruppu/cmd/cli/groupsim"
@@ -427,6 +429,69 @@ func TestRun_DataDirDefault(t *testing.T) {
 	})
 }
 
+// TestRun_Ephemeral tests that ephemeral mode writes nothing to disk
+func TestRun_Ephemeral(t *testing.T) {
+	t.Run("should not create data directory or files", func(t *testing.T) {
+		// Given
+		t.Setenv("GCP_PROJECT_ID", "test-project")
+		t.Setenv("GCP_REGION", "test-region")
+		t.Setenv("LLM_MODEL", "test-model")
+
+		dataDir := filepath.Join(t.TempDir(), "data")
+
+		args := []string{
+			"yuruppu-cli",
+			"--user-id", "testuser",
+			"--data-dir", dataDir,
+			"--group-id", "mygroup",
+			"--ephemeral",
+			"--message", "Hello",
+		}
+		stdin := strings.NewReader("")
+		stdout := &bytes.Buffer{}
+		stderr := &bytes.Buffer{}
+
+		// When
+		err := run(args, stdin, stdout, stderr)
+
+		// Then: no directory prompt, and nothing written to disk
+		if err != nil {
+			assert.NotContains(t, err.Error(), "unexpected EOF")
+		}
+		assert.NotContains(t, stderr.String(), "does not exist. Create it?")
+		assert.NoDirExists(t, dataDir)
+	})
+
+	t.Run("should leave existing data directory empty", func(t *testing.T) {
+		// Given
+		t.Setenv("GCP_PROJECT_ID", "test-project")
+		t.Setenv("GCP_REGION", "test-region")
+		t.Setenv("LLM_MODEL", "test-model")
+
+		dataDir := t.TempDir()
+
+		args := []string{
+			"yuruppu-cli",
+			"--user-id", "testuser",
+			"--data-dir", dataDir,
+			"--group-id", "mygroup",
+			"--ephemeral",
+			"--message", "Hello",
+		}
+		stdin := strings.NewReader("")
+		stdout := &bytes.Buffer{}
+		stderr := &bytes.Buffer{}
+
+		// When
+		_ = run(args, stdin, stdout, stderr)
+
+		// Then
+		entries, err := os.ReadDir(dataDir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+}
+
 // TestRun_VerboseLogging tests verbose logging to stderr
 // FR-011: CLI outputs verbose logs to stderr
 func TestRun_VerboseLogging(t *testing.T) {
@@ -1109,3 +1174,39 @@ func TestRun_GroupID_Validation(t *testing.T) {
 		})
 	}
 }
+
+// TestRun_DumpTools tests that dump-tools prints every tool schema without LLM configuration
+func TestRun_DumpTools(t *testing.T) {
+	t.Run("prints known tools with valid JSON schemas", func(t *testing.T) {
+		// Given: no LLM environment variables
+		t.Setenv("GCP_PROJECT_ID", "")
+		t.Setenv("GCP_REGION", "")
+		t.Setenv("LLM_MODEL", "")
+		stdout := &bytes.Buffer{}
+		stderr := &bytes.Buffer{}
+
+		// When
+		err := run([]string{"yuruppu-cli", "dump-tools"}, strings.NewReader(""), stdout, stderr)
+
+		// Then
+		require.NoError(t, err)
+		var tools []struct {
+			Name        string         `json:"name"`
+			Description string         `json:"description"`
+			Parameters  map[string]any `json:"parameters"`
+			Response    map[string]any `json:"response"`
+		}
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &tools), "output should be a JSON array")
+
+		names := make([]string, 0, len(tools))
+		for _, tool := range tools {
+			names = append(names, tool.Name)
+			assert.NotEmpty(t, tool.Description, "tool %s should have a description", tool.Name)
+			assert.Equal(t, "object", tool.Parameters["type"], "tool %s parameters should be an object schema", tool.Name)
+			assert.Equal(t, "object", tool.Response["type"], "tool %s response should be an object schema", tool.Name)
+		}
+		for _, want := range []string{"reply", "skip", "get_weather", "set_display_name", "set_reminder", "snooze_reminder", "create_event", "list_events", "cancel_rsvp"} {
+			assert.Contains(t, names, want)
+		}
+	})
+}
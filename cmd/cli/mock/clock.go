@@ -0,0 +1,32 @@
+package mock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is a simulated clock for the CLI.
+// It follows the real clock until Set moves it, and then keeps running from the new time.
+type Clock struct {
+	mu     sync.Mutex
+	offset time.Duration
+}
+
+// NewClock creates a Clock that starts at the real time.
+func NewClock() *Clock {
+	return &Clock{}
+}
+
+// Now returns the simulated current time.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return time.Now().Add(c.offset)
+}
+
+// Set moves the simulated current time to now, forward or backward.
+func (c *Clock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.offset = time.Until(now)
+}
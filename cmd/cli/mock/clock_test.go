@@ -0,0 +1,39 @@
+package mock_test
+
+import (
+	"testing"
+	"time"
+	"yuruppu/cmd/cli/mock"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClock(t *testing.T) {
+	t.Run("follows the real clock by default", func(t *testing.T) {
+		c := mock.NewClock()
+
+		assert.WithinDuration(t, time.Now(), c.Now(), time.Second)
+	})
+
+	t.Run("keeps running from the time it was set to", func(t *testing.T) {
+		c := mock.NewClock()
+		target := time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC)
+
+		c.Set(target)
+		first := c.Now()
+		time.Sleep(10 * time.Millisecond)
+		second := c.Now()
+
+		assert.WithinDuration(t, target, first, time.Second)
+		assert.True(t, second.After(first), "simulated time should keep running")
+	})
+
+	t.Run("can be set backward", func(t *testing.T) {
+		c := mock.NewClock()
+		target := time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC)
+
+		c.Set(target)
+
+		assert.WithinDuration(t, target, c.Now(), time.Second)
+	})
+}
@@ -3,6 +3,10 @@ package mock
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
 	"time"
 
 	lineclient "yuruppu/internal/line/client"
@@ -19,21 +23,46 @@ type GroupSim interface {
 	GetMembers(ctx context.Context, groupID string) ([]string, error)
 }
 
+// MulticastCall records the arguments of one Multicast call.
+type MulticastCall struct {
+	To       []string
+	Messages []string
+}
+
+// Option configures a LineClient.
+type Option func(*LineClient)
+
+// WithOutput sets where messages sent by the bot are printed.
+// Without it, sent messages are discarded.
+func WithOutput(w io.Writer) Option {
+	return func(c *LineClient) {
+		c.output = w
+	}
+}
+
 // LineClient is a mock implementation of LINE client interfaces for CLI testing.
 type LineClient struct {
 	fetcher  Fetcher
 	groupSim GroupSim
+	output   io.Writer
+
+	mu             sync.Mutex
+	multicastCalls []MulticastCall
 }
 
 // NewLineClient creates a new mock LINE client with the given fetcher and group simulator.
-func NewLineClient(fetcher Fetcher, groupSim GroupSim) *LineClient {
+func NewLineClient(fetcher Fetcher, groupSim GroupSim, opts ...Option) *LineClient {
 	if fetcher == nil {
 		panic("fetcher cannot be nil")
 	}
 	if groupSim == nil {
 		panic("groupSim cannot be nil")
 	}
-	return &LineClient{fetcher: fetcher, groupSim: groupSim}
+	c := &LineClient{fetcher: fetcher, groupSim: groupSim, output: io.Discard}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // GetMessageContent returns an error indicating that media operations are not supported in mock mode.
@@ -51,16 +80,48 @@ func (c *LineClient) GetGroupSummary(ctx context.Context, groupID string) (*line
 	return c.fetcher.FetchGroupSummary(ctx, groupID)
 }
 
-// SendReply is a no-op in CLI mode since bot output is already logged.
-func (c *LineClient) SendReply(replyToken string, text string) error {
+// Send prints the message to the output.
+func (c *LineClient) Send(ctx context.Context, text string) error {
+	_, _ = fmt.Fprintln(c.output, text)
+	return nil
+}
+
+// SendFlex prints the alt text of the flex message to the output.
+func (c *LineClient) SendFlex(ctx context.Context, altText string, flexJSON []byte) error {
+	_, _ = fmt.Fprintf(c.output, "[flex] %s\n", altText)
+	return nil
+}
+
+// SendFlexPush prints the destination and alt text of the flex message to the output.
+func (c *LineClient) SendFlexPush(to string, altText string, flexJSON []byte) error {
+	_, _ = fmt.Fprintf(c.output, "[flex to %s] %s\n", to, altText)
+	return nil
+}
+
+// SendPush prints the destination and text of the message to the output.
+func (c *LineClient) SendPush(to string, text string) error {
+	_, _ = fmt.Fprintf(c.output, "[push to %s] %s\n", to, text)
 	return nil
 }
 
-// SendFlexReply is a no-op in CLI mode since bot output is already logged.
-func (c *LineClient) SendFlexReply(replyToken string, altText string, flexJSON []byte) error {
+// Multicast records the call instead of sending anything.
+func (c *LineClient) Multicast(ctx context.Context, to []string, messages ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.multicastCalls = append(c.multicastCalls, MulticastCall{
+		To:       slices.Clone(to),
+		Messages: slices.Clone(messages),
+	})
 	return nil
 }
 
+// MulticastCalls returns the Multicast calls recorded so far.
+func (c *LineClient) MulticastCalls() []MulticastCall {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.multicastCalls)
+}
+
 // ShowLoadingAnimation is a no-op in CLI mode since bot output is already logged.
 func (c *LineClient) ShowLoadingAnimation(ctx context.Context, chatID string, timeout time.Duration) error {
 	return nil
@@ -74,3 +135,12 @@ func (c *LineClient) GetGroupMemberCount(ctx context.Context, groupID string) (i
 	}
 	return len(members), nil
 }
+
+// IsGroupMember reports whether a user is a member of a group via GroupSim.
+func (c *LineClient) IsGroupMember(ctx context.Context, groupID, userID string) (bool, error) {
+	members, err := c.groupSim.GetMembers(ctx, groupID)
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(members, userID), nil
+}
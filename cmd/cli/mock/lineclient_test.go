@@ -1,6 +1,7 @@
 package mock_test
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"testing"
@@ -152,18 +153,79 @@ func TestLineClient_GetGroupSummary(t *testing.T) {
 	})
 }
 
-// TestLineClient_SendReply tests the SendReply method
-func TestLineClient_SendReply(t *testing.T) {
+// TestLineClient_Send tests the Send method
+func TestLineClient_Send(t *testing.T) {
 	t.Run("should return nil (no-op)", func(t *testing.T) {
 		// Given
 		client := mock.NewLineClient(&mockFetcher{}, &mockGroupSim{})
 
 		// When
-		err := client.SendReply("token123", "Hello, user!")
+		err := client.Send(t.Context(), "Hello, user!")
 
 		// Then
 		require.NoError(t, err)
 	})
+
+	t.Run("should print the message to the output", func(t *testing.T) {
+		// Given
+		out := &bytes.Buffer{}
+		client := mock.NewLineClient(&mockFetcher{}, &mockGroupSim{}, mock.WithOutput(out))
+
+		// When
+		err := client.Send(t.Context(), "Hello, user!")
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, "Hello, user!\n", out.String())
+	})
+}
+
+// TestLineClient_SendFlex tests the SendFlex method
+func TestLineClient_SendFlex(t *testing.T) {
+	t.Run("should print the alt text to the output", func(t *testing.T) {
+		// Given
+		out := &bytes.Buffer{}
+		client := mock.NewLineClient(&mockFetcher{}, &mockGroupSim{}, mock.WithOutput(out))
+
+		// When
+		err := client.SendFlex(t.Context(), "Upcoming events", []byte(`{"type":"bubble"}`))
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, "[flex] Upcoming events\n", out.String())
+	})
+}
+
+// TestLineClient_SendFlexPush tests the SendFlexPush method
+func TestLineClient_SendFlexPush(t *testing.T) {
+	t.Run("should print the destination and alt text to the output", func(t *testing.T) {
+		// Given
+		out := &bytes.Buffer{}
+		client := mock.NewLineClient(&mockFetcher{}, &mockGroupSim{}, mock.WithOutput(out))
+
+		// When
+		err := client.SendFlexPush("group-1", "New event", []byte(`{"type":"bubble"}`))
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, "[flex to group-1] New event\n", out.String())
+	})
+}
+
+// TestLineClient_SendPush tests the SendPush method
+func TestLineClient_SendPush(t *testing.T) {
+	t.Run("should print the destination and text to the output", func(t *testing.T) {
+		// Given
+		out := &bytes.Buffer{}
+		client := mock.NewLineClient(&mockFetcher{}, &mockGroupSim{}, mock.WithOutput(out))
+
+		// When
+		err := client.SendPush("user-1", "You got a spot")
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, "[push to user-1] You got a spot\n", out.String())
+	})
 }
 
 // TestLineClient_GetGroupMemberCount tests the GetGroupMemberCount method
@@ -209,6 +271,39 @@ func TestLineClient_GetGroupMemberCount(t *testing.T) {
 	})
 }
 
+// TestLineClient_Multicast tests that Multicast calls are recorded
+func TestLineClient_Multicast(t *testing.T) {
+	t.Run("should record each call", func(t *testing.T) {
+		// Given
+		client := mock.NewLineClient(&mockFetcher{}, &mockGroupSim{})
+
+		// When
+		err1 := client.Multicast(context.Background(), []string{"user-1", "user-2"}, "hello", "world")
+		err2 := client.Multicast(context.Background(), []string{"user-3"}, "bye")
+
+		// Then
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		assert.Equal(t, []mock.MulticastCall{
+			{To: []string{"user-1", "user-2"}, Messages: []string{"hello", "world"}},
+			{To: []string{"user-3"}, Messages: []string{"bye"}},
+		}, client.MulticastCalls())
+	})
+
+	t.Run("should not be affected by later changes to the recipients", func(t *testing.T) {
+		// Given
+		client := mock.NewLineClient(&mockFetcher{}, &mockGroupSim{})
+		to := []string{"user-1"}
+
+		// When
+		require.NoError(t, client.Multicast(context.Background(), to, "hello"))
+		to[0] = "changed"
+
+		// Then
+		assert.Equal(t, []string{"user-1"}, client.MulticastCalls()[0].To)
+	})
+}
+
 // TestLineClient_InterfaceCompliance verifies that LineClient implements required interfaces
 func TestLineClient_InterfaceCompliance(t *testing.T) {
 	t.Run("should implement bot.LineClient interface", func(t *testing.T) {
@@ -229,7 +324,30 @@ func TestLineClient_InterfaceCompliance(t *testing.T) {
 
 		// When/Then
 		var _ interface {
-			SendReply(replyToken string, text string) error
+			Send(ctx context.Context, text string) error
 		} = client
 	})
 }
+
+// TestLineClient_IsGroupMember tests membership lookups via GroupSim
+func TestLineClient_IsGroupMember(t *testing.T) {
+	t.Run("should report members and non-members", func(t *testing.T) {
+		client := mock.NewLineClient(&mockFetcher{}, &mockGroupSim{members: []string{"alice", "bob"}})
+
+		isMember, err := client.IsGroupMember(t.Context(), "group-1", "bob")
+		require.NoError(t, err)
+		assert.True(t, isMember)
+
+		isMember, err = client.IsGroupMember(t.Context(), "group-1", "carol")
+		require.NoError(t, err)
+		assert.False(t, isMember)
+	})
+
+	t.Run("should return error from GroupSim", func(t *testing.T) {
+		client := mock.NewLineClient(&mockFetcher{}, &mockGroupSim{err: errors.New("group not found")})
+
+		_, err := client.IsGroupMember(t.Context(), "group-1", "bob")
+
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,77 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// MemoryStorage provides in-memory storage that is discarded when the process exits.
+// It uses a monotonically increasing counter as the generation number.
+type MemoryStorage struct {
+	mu         sync.Mutex
+	objects    map[string]memoryObject
+	generation int64
+}
+
+type memoryObject struct {
+	data       []byte
+	generation int64
+}
+
+// NewMemoryStorage creates a new empty MemoryStorage instance.
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{
+		objects: make(map[string]memoryObject),
+	}
+}
+
+// Read retrieves data for a key from memory.
+// Returns nil, 0, nil if the key doesn't exist.
+func (ms *MemoryStorage) Read(_ context.Context, key string) ([]byte, int64, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	obj, ok := ms.objects[key]
+	if !ok {
+		return nil, 0, nil
+	}
+	return append([]byte(nil), obj.data...), obj.generation, nil
+}
+
+// Write stores data for a key with optional generation precondition.
+// If expectedGeneration is 0, creates new object (fails if exists).
+// If expectedGeneration > 0, updates only if generation matches (fails if mismatch).
+// Returns the new generation number of the written object.
+func (ms *MemoryStorage) Write(_ context.Context, key, _ string, data []byte, expectedGeneration int64) (int64, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	obj, exists := ms.objects[key]
+	if expectedGeneration == 0 {
+		if exists {
+			return 0, errors.New("object already exists")
+		}
+	} else {
+		if !exists {
+			return 0, errors.New("object does not exist")
+		}
+		if obj.generation != expectedGeneration {
+			return 0, errors.New("generation mismatch")
+		}
+	}
+
+	ms.generation++
+	ms.objects[key] = memoryObject{
+		data:       append([]byte(nil), data...),
+		generation: ms.generation,
+	}
+	return ms.generation, nil
+}
+
+// GetSignedURL generates a mem:// URL for the object.
+// The method and ttl parameters are ignored for in-memory storage.
+func (ms *MemoryStorage) GetSignedURL(_ context.Context, key, _ string, _ time.Duration) (string, error) {
+	return "mem://" + key, nil
+}
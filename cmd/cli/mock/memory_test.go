@@ -0,0 +1,95 @@
+package mock_test
+
+import (
+	"testing"
+	"time"
+	"yuruppu/cmd/cli/mock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStorage_Read(t *testing.T) {
+	t.Run("should return nil, 0, nil when key does not exist", func(t *testing.T) {
+		storage := mock.NewMemoryStorage()
+
+		data, gen, err := storage.Read(t.Context(), "missing")
+
+		require.NoError(t, err)
+		assert.Nil(t, data)
+		assert.Equal(t, int64(0), gen)
+	})
+
+	t.Run("should return a copy of stored data", func(t *testing.T) {
+		storage := mock.NewMemoryStorage()
+		_, err := storage.Write(t.Context(), "key", "text/plain", []byte("hello"), 0)
+		require.NoError(t, err)
+
+		data, _, err := storage.Read(t.Context(), "key")
+		require.NoError(t, err)
+		data[0] = 'j'
+
+		again, _, err := storage.Read(t.Context(), "key")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("hello"), again)
+	})
+}
+
+func TestMemoryStorage_Write(t *testing.T) {
+	t.Run("should create and update with generation precondition", func(t *testing.T) {
+		storage := mock.NewMemoryStorage()
+
+		gen1, err := storage.Write(t.Context(), "key", "text/plain", []byte("v1"), 0)
+		require.NoError(t, err)
+		assert.Positive(t, gen1)
+
+		gen2, err := storage.Write(t.Context(), "key", "text/plain", []byte("v2"), gen1)
+		require.NoError(t, err)
+		assert.Greater(t, gen2, gen1)
+
+		data, gen, err := storage.Read(t.Context(), "key")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("v2"), data)
+		assert.Equal(t, gen2, gen)
+	})
+
+	t.Run("should fail to create when key exists", func(t *testing.T) {
+		storage := mock.NewMemoryStorage()
+		_, err := storage.Write(t.Context(), "key", "text/plain", []byte("v1"), 0)
+		require.NoError(t, err)
+
+		_, err = storage.Write(t.Context(), "key", "text/plain", []byte("v2"), 0)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "already exists")
+	})
+
+	t.Run("should fail to update when key does not exist", func(t *testing.T) {
+		storage := mock.NewMemoryStorage()
+
+		_, err := storage.Write(t.Context(), "key", "text/plain", []byte("v1"), 1)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not exist")
+	})
+
+	t.Run("should fail on generation mismatch", func(t *testing.T) {
+		storage := mock.NewMemoryStorage()
+		gen, err := storage.Write(t.Context(), "key", "text/plain", []byte("v1"), 0)
+		require.NoError(t, err)
+
+		_, err = storage.Write(t.Context(), "key", "text/plain", []byte("v2"), gen+1)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "generation mismatch")
+	})
+}
+
+func TestMemoryStorage_GetSignedURL(t *testing.T) {
+	storage := mock.NewMemoryStorage()
+
+	url, err := storage.GetSignedURL(t.Context(), "media/abc", "GET", time.Minute)
+
+	require.NoError(t, err)
+	assert.Equal(t, "mem://media/abc", url)
+}
@@ -5,11 +5,39 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"slices"
 	"strings"
+	"time"
 
 	lineclient "yuruppu/internal/line/client"
 )
 
+const (
+	// DefaultLanguage is used when the language prompt is left empty.
+	DefaultLanguage = "ja"
+	// DefaultTimezone is used when the timezone prompt is left empty.
+	DefaultTimezone = "Asia/Tokyo"
+)
+
+// supportedLanguages lists the language codes accepted during onboarding.
+var supportedLanguages = []string{"ja", "en", "ko", "zh-TW", "th", "id"}
+
+// ValidateLanguage returns an error if language is not a supported language code.
+func ValidateLanguage(language string) error {
+	if !slices.Contains(supportedLanguages, language) {
+		return fmt.Errorf("unsupported language %q (supported: %s)", language, strings.Join(supportedLanguages, "/"))
+	}
+	return nil
+}
+
+// ValidateTimezone returns an error if timezone is not a name in the tz database.
+func ValidateTimezone(timezone string) error {
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return fmt.Errorf("unknown timezone %q", timezone)
+	}
+	return nil
+}
+
 // Prompter prompts for profile information via stdin.
 // Implements mock.Fetcher interface.
 type Prompter struct {
@@ -34,6 +62,7 @@ func NewPrompter(scanner *bufio.Scanner, w io.Writer) *Prompter {
 // FetchUserProfile prompts the user for profile information.
 // Display name is required (re-prompts if empty).
 // Picture URL and status message are optional.
+// Language and timezone fall back to defaults when empty and re-prompt when invalid.
 func (p *Prompter) FetchUserProfile(ctx context.Context, userID string) (*lineclient.UserProfile, error) {
 	// Display name (required)
 	var displayName string
@@ -80,13 +109,61 @@ func (p *Prompter) FetchUserProfile(ctx context.Context, userID string) (*linecl
 	}
 	statusMessage := strings.TrimSpace(p.scanner.Text())
 
+	// Language (defaulted, validated against allowlist)
+	language, err := p.promptValidated(ctx,
+		fmt.Sprintf("Enter user language [%s] (default %s): ", strings.Join(supportedLanguages, "/"), DefaultLanguage),
+		DefaultLanguage,
+		ValidateLanguage,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Timezone (defaulted, validated via tz database)
+	timezone, err := p.promptValidated(ctx,
+		fmt.Sprintf("Enter user timezone (default %s): ", DefaultTimezone),
+		DefaultTimezone,
+		ValidateTimezone,
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	return &lineclient.UserProfile{
 		DisplayName:   displayName,
 		PictureURL:    pictureURL,
 		StatusMessage: statusMessage,
+		Language:      language,
+		Timezone:      timezone,
 	}, nil
 }
 
+// promptValidated reads a line, returning def for empty input.
+// Re-prompts until validate accepts the value.
+func (p *Prompter) promptValidated(ctx context.Context, prompt, def string, validate func(string) error) (string, error) {
+	for {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		_, _ = fmt.Fprint(p.writer, prompt)
+		if !p.scanner.Scan() {
+			if err := p.scanner.Err(); err != nil {
+				return "", err
+			}
+			return "", io.EOF
+		}
+		value := strings.TrimSpace(p.scanner.Text())
+		if value == "" {
+			return def, nil
+		}
+		if err := validate(value); err != nil {
+			_, _ = fmt.Fprintf(p.writer, "%v\n", err)
+			continue
+		}
+		return value, nil
+	}
+}
+
 // FetchGroupSummary prompts the user for group information.
 func (p *Prompter) FetchGroupSummary(ctx context.Context, groupID string) (*lineclient.GroupSummary, error) {
 	// Group name (required)
@@ -49,7 +49,7 @@ func TestNewPrompter(t *testing.T) {
 func TestPrompter_FetchUserProfile(t *testing.T) {
 	t.Run("should prompt for profile and return it", func(t *testing.T) {
 		// Given
-		input := "Test User\nhttps://example.com/pic.jpg\nHello world\n"
+		input := "Test User\nhttps://example.com/pic.jpg\nHello world\n\n\n"
 		scanner := bufio.NewScanner(strings.NewReader(input))
 		var writer bytes.Buffer
 		p := prompter.NewPrompter(scanner, &writer)
@@ -68,7 +68,7 @@ func TestPrompter_FetchUserProfile(t *testing.T) {
 
 	t.Run("should re-prompt if display name is empty", func(t *testing.T) {
 		// Given
-		input := "\n\nValid Name\n\n\n"
+		input := "\n\nValid Name\n\n\n\n\n"
 		scanner := bufio.NewScanner(strings.NewReader(input))
 		var writer bytes.Buffer
 		p := prompter.NewPrompter(scanner, &writer)
@@ -85,7 +85,7 @@ func TestPrompter_FetchUserProfile(t *testing.T) {
 
 	t.Run("should allow empty optional fields", func(t *testing.T) {
 		// Given
-		input := "Test User\n\n\n"
+		input := "Test User\n\n\n\n\n"
 		scanner := bufio.NewScanner(strings.NewReader(input))
 		var writer bytes.Buffer
 		p := prompter.NewPrompter(scanner, &writer)
@@ -100,6 +100,8 @@ func TestPrompter_FetchUserProfile(t *testing.T) {
 		assert.Equal(t, "Test User", profile.DisplayName)
 		assert.Empty(t, profile.PictureURL)
 		assert.Empty(t, profile.StatusMessage)
+		assert.Equal(t, prompter.DefaultLanguage, profile.Language)
+		assert.Equal(t, prompter.DefaultTimezone, profile.Timezone)
 	})
 
 	t.Run("should return EOF if input ends early", func(t *testing.T) {
@@ -120,7 +122,7 @@ func TestPrompter_FetchUserProfile(t *testing.T) {
 
 	t.Run("should display prompts to writer", func(t *testing.T) {
 		// Given
-		input := "Test User\n\n\n"
+		input := "Test User\n\n\n\n\n"
 		scanner := bufio.NewScanner(strings.NewReader(input))
 		var writer bytes.Buffer
 		p := prompter.NewPrompter(scanner, &writer)
@@ -134,11 +136,81 @@ func TestPrompter_FetchUserProfile(t *testing.T) {
 		assert.Contains(t, output, "Enter user display name:")
 		assert.Contains(t, output, "Enter user picture URL")
 		assert.Contains(t, output, "Enter user status message")
+		assert.Contains(t, output, "Enter user language")
+		assert.Contains(t, output, "Enter user timezone")
+	})
+
+	t.Run("should accept valid language and timezone", func(t *testing.T) {
+		// Given
+		input := "Test User\n\n\nen\nAmerica/New_York\n"
+		scanner := bufio.NewScanner(strings.NewReader(input))
+		var writer bytes.Buffer
+		p := prompter.NewPrompter(scanner, &writer)
+		ctx := context.Background()
+
+		// When
+		profile, err := p.FetchUserProfile(ctx, "user123")
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, "en", profile.Language)
+		assert.Equal(t, "America/New_York", profile.Timezone)
+	})
+
+	t.Run("should re-prompt on invalid timezone", func(t *testing.T) {
+		// Given
+		input := "Test User\n\n\n\nMars/Olympus_Mons\nEurope/London\n"
+		scanner := bufio.NewScanner(strings.NewReader(input))
+		var writer bytes.Buffer
+		p := prompter.NewPrompter(scanner, &writer)
+		ctx := context.Background()
+
+		// When
+		profile, err := p.FetchUserProfile(ctx, "user123")
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, "Europe/London", profile.Timezone)
+		assert.Contains(t, writer.String(), `unknown timezone "Mars/Olympus_Mons"`)
+		assert.Equal(t, 2, strings.Count(writer.String(), "Enter user timezone"))
+	})
+
+	t.Run("should re-prompt on unsupported language", func(t *testing.T) {
+		// Given
+		input := "Test User\n\n\nklingon\nko\n\n"
+		scanner := bufio.NewScanner(strings.NewReader(input))
+		var writer bytes.Buffer
+		p := prompter.NewPrompter(scanner, &writer)
+		ctx := context.Background()
+
+		// When
+		profile, err := p.FetchUserProfile(ctx, "user123")
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, "ko", profile.Language)
+		assert.Contains(t, writer.String(), `unsupported language "klingon"`)
+	})
+
+	t.Run("should return EOF if input ends during timezone re-prompt", func(t *testing.T) {
+		// Given
+		input := "Test User\n\n\n\nInvalid/Zone\n"
+		scanner := bufio.NewScanner(strings.NewReader(input))
+		var writer bytes.Buffer
+		p := prompter.NewPrompter(scanner, &writer)
+		ctx := context.Background()
+
+		// When
+		profile, err := p.FetchUserProfile(ctx, "user123")
+
+		// Then
+		require.Error(t, err)
+		assert.Nil(t, profile)
 	})
 
 	t.Run("should trim whitespace from input", func(t *testing.T) {
 		// Given
-		input := "  Test User  \n  https://example.com  \n  Hello  \n"
+		input := "  Test User  \n  https://example.com  \n  Hello  \n\n\n"
 		scanner := bufio.NewScanner(strings.NewReader(input))
 		var writer bytes.Buffer
 		p := prompter.NewPrompter(scanner, &writer)
@@ -156,7 +228,7 @@ func TestPrompter_FetchUserProfile(t *testing.T) {
 
 	t.Run("should return error when context is cancelled", func(t *testing.T) {
 		// Given
-		input := "Test User\n\n\n"
+		input := "Test User\n\n\n\n\n"
 		scanner := bufio.NewScanner(strings.NewReader(input))
 		var writer bytes.Buffer
 		p := prompter.NewPrompter(scanner, &writer)
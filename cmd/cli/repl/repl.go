@@ -8,6 +8,8 @@ import (
 	"io"
 	"log/slog"
 	"strings"
+	"time"
+	"yuruppu/cmd/cli/prompter"
 	"yuruppu/internal/line"
 	"yuruppu/internal/userprofile"
 
@@ -25,8 +27,15 @@ type MessageHandler interface {
 
 type UserProfileService interface {
 	GetUserProfile(ctx context.Context, userID string) (*userprofile.UserProfile, error)
+	UpdateUserProfile(ctx context.Context, userID string, update func(*userprofile.UserProfile)) error
 }
 
+// nowUsage is printed when /now is given a time that is not RFC3339.
+const nowUsage = "usage: /now <RFC3339>"
+
+// setUsage is printed when /set is given an unknown field or an invalid value.
+const setUsage = "usage: /set <display_name|language|timezone> <value>"
+
 type GroupSimService interface {
 	GetMembers(ctx context.Context, groupID string) ([]string, error)
 	IsMember(ctx context.Context, groupID, userID string) (bool, error)
@@ -35,6 +44,28 @@ type GroupSimService interface {
 	AddBot(ctx context.Context, groupID string) error
 }
 
+// Clock is the simulated clock that /now reads and moves.
+type Clock interface {
+	Now() time.Time
+	Set(now time.Time)
+}
+
+// ReminderDispatcher pushes the reminders due at a given time.
+type ReminderDispatcher interface {
+	DispatchDue(ctx context.Context, now time.Time) error
+}
+
+// Option configures optional Runner behavior.
+type Option func(*Runner)
+
+// WithClock enables /now, which moves clock and then dispatches the reminders due at the new time.
+func WithClock(clock Clock, dispatcher ReminderDispatcher) Option {
+	return func(r *Runner) {
+		r.clock = clock
+		r.dispatcher = dispatcher
+	}
+}
+
 type Runner struct {
 	userID             string
 	groupID            string
@@ -44,8 +75,13 @@ type Runner struct {
 	logger             *slog.Logger
 	scanner            *bufio.Scanner
 	writer             io.Writer
+	clock              Clock
+	dispatcher         ReminderDispatcher
 }
 
+// NewRunner creates a REPL runner.
+// The prompt is written to writer (stdout); command errors and other diagnostics go to logger,
+// which the CLI writes to stderr, so that stdout carries only prompts and bot replies.
 func NewRunner(
 	userID string,
 	groupID string,
@@ -55,6 +91,7 @@ func NewRunner(
 	logger *slog.Logger,
 	scanner *bufio.Scanner,
 	writer io.Writer,
+	opts ...Option,
 ) (*Runner, error) {
 	if userID == "" {
 		return nil, errors.New("userID must not be empty")
@@ -72,7 +109,7 @@ func NewRunner(
 		return nil, errors.New("writer must not be nil")
 	}
 
-	return &Runner{
+	r := &Runner{
 		userID:             userID,
 		groupID:            groupID,
 		userProfileService: userProfileService,
@@ -81,7 +118,14 @@ func NewRunner(
 		logger:             logger,
 		scanner:            scanner,
 		writer:             writer,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if (r.clock == nil) != (r.dispatcher == nil) {
+		return nil, errors.New("clock and dispatcher must be set together")
+	}
+	return r, nil
 }
 
 func (r *Runner) formatUser(ctx context.Context, userID string) string {
@@ -202,6 +246,93 @@ func (r *Runner) handleInviteBot(ctx context.Context) {
 	r.logger.InfoContext(ctx, "bot invited to group")
 }
 
+func (r *Runner) handleProfile(ctx context.Context) {
+	if r.userProfileService == nil {
+		r.logger.WarnContext(ctx, "/profile is not available")
+		return
+	}
+
+	p, err := r.userProfileService.GetUserProfile(ctx, r.userID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to get profile", slog.Any("error", err))
+		return
+	}
+
+	r.logger.InfoContext(ctx, "user profile",
+		slog.String("userID", r.userID),
+		slog.String("displayName", p.DisplayName),
+		slog.String("language", p.Language),
+		slog.String("timezone", p.Timezone),
+	)
+}
+
+func (r *Runner) handleSet(ctx context.Context, args string) {
+	if r.userProfileService == nil {
+		r.logger.WarnContext(ctx, "/set is not available")
+		return
+	}
+
+	field, value, _ := strings.Cut(strings.TrimSpace(args), " ")
+	value = strings.TrimSpace(value)
+	if value == "" {
+		r.logger.WarnContext(ctx, setUsage)
+		return
+	}
+
+	var update func(*userprofile.UserProfile)
+	switch field {
+	case "display_name":
+		update = func(p *userprofile.UserProfile) { p.DisplayName = value }
+	case "language":
+		if err := prompter.ValidateLanguage(value); err != nil {
+			r.logger.WarnContext(ctx, setUsage, slog.Any("error", err))
+			return
+		}
+		update = func(p *userprofile.UserProfile) { p.Language = value }
+	case "timezone":
+		if err := prompter.ValidateTimezone(value); err != nil {
+			r.logger.WarnContext(ctx, setUsage, slog.Any("error", err))
+			return
+		}
+		update = func(p *userprofile.UserProfile) { p.Timezone = value }
+	default:
+		r.logger.WarnContext(ctx, setUsage, slog.String("field", field))
+		return
+	}
+
+	if err := r.userProfileService.UpdateUserProfile(ctx, r.userID, update); err != nil {
+		r.logger.ErrorContext(ctx, "failed to update profile", slog.Any("error", err))
+		return
+	}
+
+	r.logger.InfoContext(ctx, "profile updated", slog.String("field", field), slog.String("value", value))
+}
+
+func (r *Runner) handleNow(ctx context.Context, args string) {
+	if r.clock == nil {
+		r.logger.WarnContext(ctx, "/now is not available")
+		return
+	}
+
+	args = strings.TrimSpace(args)
+	if args == "" {
+		r.logger.InfoContext(ctx, "current time", slog.Time("now", r.clock.Now()))
+		return
+	}
+	now, err := time.Parse(time.RFC3339, args)
+	if err != nil {
+		r.logger.WarnContext(ctx, nowUsage, slog.Any("error", err))
+		return
+	}
+
+	r.clock.Set(now)
+	r.logger.InfoContext(ctx, "clock set", slog.Time("now", now))
+
+	if err := r.dispatcher.DispatchDue(ctx, now); err != nil {
+		r.logger.ErrorContext(ctx, "failed to dispatch reminders", slog.Any("error", err))
+	}
+}
+
 func (r *Runner) handleText(ctx context.Context, text string) {
 	msgCtx := r.buildMessageContext(ctx)
 
@@ -282,6 +413,25 @@ func (r *Runner) Run(ctx context.Context) error {
 			continue
 		}
 
+		if trimmed == "/profile" {
+			r.handleProfile(ctx)
+			continue
+		}
+
+		if args, ok := strings.CutPrefix(trimmed, "/set "); ok {
+			r.handleSet(ctx, args)
+			continue
+		}
+		if trimmed == "/set" {
+			r.logger.WarnContext(ctx, setUsage)
+			continue
+		}
+
+		if args, ok := strings.CutPrefix(trimmed, "/now"); ok && (args == "" || strings.HasPrefix(args, " ")) {
+			r.handleNow(ctx, args)
+			continue
+		}
+
 		if trimmed == "/invite-bot" {
 			r.handleInviteBot(ctx)
 			continue
@@ -11,8 +11,11 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
+	"yuruppu/cmd/cli/mock"
 	"yuruppu/cmd/cli/repl"
 	"yuruppu/internal/line"
+	"yuruppu/internal/reminder"
 	"yuruppu/internal/userprofile"
 
 	"github.com/stretchr/testify/assert"
@@ -134,8 +137,9 @@ func (m *mockHandler) memberJoinedCallCount() int {
 }
 
 type mockProfileService struct {
-	profiles map[string]*userprofile.UserProfile
-	err      error
+	profiles    map[string]*userprofile.UserProfile
+	err         error
+	updateCount int
 }
 
 func (m *mockProfileService) GetUserProfile(_ context.Context, userID string) (*userprofile.UserProfile, error) {
@@ -148,6 +152,19 @@ func (m *mockProfileService) GetUserProfile(_ context.Context, userID string) (*
 	return nil, fmt.Errorf("profile not found: %s", userID)
 }
 
+func (m *mockProfileService) UpdateUserProfile(_ context.Context, userID string, update func(*userprofile.UserProfile)) error {
+	if m.err != nil {
+		return m.err
+	}
+	p, ok := m.profiles[userID]
+	if !ok {
+		return fmt.Errorf("profile not found: %s", userID)
+	}
+	update(p)
+	m.updateCount++
+	return nil
+}
+
 type mockGroupSimService struct {
 	members    map[string][]string
 	botInGroup map[string]bool
@@ -495,6 +512,37 @@ func TestRun_HandlerError(t *testing.T) {
 	})
 }
 
+// TestRun_OutputRouting tests that stdout carries only prompts and diagnostics go to stderr.
+func TestRun_OutputRouting(t *testing.T) {
+	t.Run("should write command errors to stderr and only prompts to stdout", func(t *testing.T) {
+		scanner := bufio.NewScanner(strings.NewReader("/invite\n/users\nhello\n/quit\n"))
+		stdout := &bytes.Buffer{}
+		stderr := &bytes.Buffer{}
+		handler := &mockHandler{
+			returnErr: errors.New("handler processing error"),
+		}
+
+		r, err := repl.NewRunner(
+			"test-user",
+			"",
+			nil,
+			nil,
+			handler,
+			slog.New(slog.NewTextHandler(stderr, nil)),
+			scanner,
+			stdout,
+		)
+		require.NoError(t, err)
+
+		err = r.Run(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, strings.Repeat("(test-user)> ", 4), stdout.String())
+		assert.Contains(t, stderr.String(), "usage: /invite <user-id>")
+		assert.Contains(t, stderr.String(), "/users is not available")
+		assert.Contains(t, stderr.String(), "handler processing error")
+	})
+}
+
 // TestRun_MultipleMessages tests multiple message exchanges.
 func TestRun_MultipleMessages(t *testing.T) {
 	t.Run("should handle multiple messages in sequence", func(t *testing.T) {
@@ -1502,3 +1550,262 @@ func TestRun_InviteCommand_HandleMemberJoinedError(t *testing.T) {
 		assert.Contains(t, logBuf.String(), "HandleMemberJoined processing error")
 	})
 }
+
+// TestRun_ProfileCommand tests /profile shows the current user's profile.
+func TestRun_ProfileCommand(t *testing.T) {
+	t.Run("should show the current user's profile", func(t *testing.T) {
+		scanner := bufio.NewScanner(strings.NewReader("/profile\n/quit\n"))
+		stdout := &bytes.Buffer{}
+		logBuf := &bytes.Buffer{}
+		handler := &mockHandler{}
+
+		profileService := &mockProfileService{
+			profiles: map[string]*userprofile.UserProfile{
+				"alice": {DisplayName: "Alice", Language: "en", Timezone: "Europe/London"},
+			},
+		}
+
+		r, err := repl.NewRunner("alice", "", profileService, nil, handler, slog.New(slog.NewTextHandler(logBuf, nil)), scanner, stdout)
+		require.NoError(t, err)
+
+		err = r.Run(context.Background())
+		require.NoError(t, err)
+		assert.Contains(t, logBuf.String(), "user profile")
+		assert.Contains(t, logBuf.String(), "displayName=Alice")
+		assert.Contains(t, logBuf.String(), "language=en")
+		assert.Contains(t, logBuf.String(), "timezone=Europe/London")
+		assert.Equal(t, 0, handler.callCount(), "/profile should not be sent to the bot")
+	})
+
+	t.Run("should show error when the profile is missing", func(t *testing.T) {
+		scanner := bufio.NewScanner(strings.NewReader("/profile\n/quit\n"))
+		logBuf := &bytes.Buffer{}
+
+		r, err := repl.NewRunner("alice", "", &mockProfileService{}, nil, &mockHandler{}, slog.New(slog.NewTextHandler(logBuf, nil)), scanner, &bytes.Buffer{})
+		require.NoError(t, err)
+
+		err = r.Run(context.Background())
+		require.NoError(t, err)
+		assert.Contains(t, logBuf.String(), "failed to get profile")
+	})
+}
+
+// TestRun_SetCommand tests /set updates the current user's profile.
+func TestRun_SetCommand(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  userprofile.UserProfile
+	}{
+		{
+			name:  "should set display name with spaces",
+			input: "/set display_name Alice Liddell",
+			want:  userprofile.UserProfile{DisplayName: "Alice Liddell", Language: "ja", Timezone: "Asia/Tokyo"},
+		},
+		{
+			name:  "should set language",
+			input: "/set language en",
+			want:  userprofile.UserProfile{DisplayName: "Alice", Language: "en", Timezone: "Asia/Tokyo"},
+		},
+		{
+			name:  "should set timezone",
+			input: "/set timezone America/New_York",
+			want:  userprofile.UserProfile{DisplayName: "Alice", Language: "ja", Timezone: "America/New_York"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner := bufio.NewScanner(strings.NewReader(tt.input + "\n/quit\n"))
+			stdout := &bytes.Buffer{}
+			logBuf := &bytes.Buffer{}
+			handler := &mockHandler{}
+
+			profileService := &mockProfileService{
+				profiles: map[string]*userprofile.UserProfile{
+					"alice": {DisplayName: "Alice", Language: "ja", Timezone: "Asia/Tokyo"},
+				},
+			}
+
+			r, err := repl.NewRunner("alice", "", profileService, nil, handler, slog.New(slog.NewTextHandler(logBuf, nil)), scanner, stdout)
+			require.NoError(t, err)
+
+			err = r.Run(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, *profileService.profiles["alice"])
+			assert.Contains(t, logBuf.String(), "profile updated")
+			assert.Equal(t, 0, handler.callCount(), "/set should not be sent to the bot")
+		})
+	}
+}
+
+// TestRun_SetCommand_Invalid tests /set rejects unknown fields and invalid values.
+func TestRun_SetCommand_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "unknown field", input: "/set nickname Ally"},
+		{name: "unsupported language", input: "/set language klingon"},
+		{name: "unknown timezone", input: "/set timezone Mars/Olympus_Mons"},
+		{name: "missing value", input: "/set language"},
+		{name: "missing field", input: "/set"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner := bufio.NewScanner(strings.NewReader(tt.input + "\n/quit\n"))
+			stdout := &bytes.Buffer{}
+			logBuf := &bytes.Buffer{}
+			handler := &mockHandler{}
+
+			profileService := &mockProfileService{
+				profiles: map[string]*userprofile.UserProfile{
+					"alice": {DisplayName: "Alice", Language: "ja", Timezone: "Asia/Tokyo"},
+				},
+			}
+
+			r, err := repl.NewRunner("alice", "", profileService, nil, handler, slog.New(slog.NewTextHandler(logBuf, nil)), scanner, stdout)
+			require.NoError(t, err)
+
+			err = r.Run(context.Background())
+			require.NoError(t, err)
+			assert.Contains(t, logBuf.String(), "usage: /set")
+			assert.Equal(t, 0, profileService.updateCount)
+			assert.Equal(t, userprofile.UserProfile{DisplayName: "Alice", Language: "ja", Timezone: "Asia/Tokyo"}, *profileService.profiles["alice"])
+			assert.NotContains(t, stdout.String(), "usage", "usage errors should not go to stdout")
+			assert.Equal(t, 0, handler.callCount())
+		})
+	}
+}
+
+// pushRecorder records reminders pushed by the dispatcher.
+type pushRecorder struct {
+	mu     sync.Mutex
+	pushes []string
+}
+
+func (p *pushRecorder) SendPush(to string, text string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pushes = append(p.pushes, to+": "+text)
+	return nil
+}
+
+func (p *pushRecorder) getPushes() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string{}, p.pushes...)
+}
+
+// TestRun_NowCommand tests that /now moves the clock and dispatches reminders due at the new time.
+func TestRun_NowCommand(t *testing.T) {
+	notifyAt := time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		input      string
+		wantPushes []string
+	}{
+		{
+			name:       "jumping past the notify time dispatches the reminder",
+			input:      "/now 2030-01-01T18:30:00+09:00",
+			wantPushes: []string{"group-1: meetup starts soon"},
+		},
+		{
+			name:       "jumping to just before the notify time dispatches nothing",
+			input:      "/now 2030-01-01T17:59:59+09:00",
+			wantPushes: []string{},
+		},
+		{
+			name:       "a reminder fires only once",
+			input:      "/now 2030-01-01T18:00:00+09:00\n/now 2030-01-02T00:00:00+09:00",
+			wantPushes: []string{"group-1: meetup starts soon"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			reminderService, err := reminder.NewService(mock.NewMemoryStorage())
+			require.NoError(t, err)
+			require.NoError(t, reminderService.Create(ctx, &reminder.Reminder{ChatRoomID: "group-1", NotifyAt: notifyAt, Text: "meetup starts soon"}))
+			sender := &pushRecorder{}
+			dispatcher, err := reminder.NewDispatcher(reminderService, sender, time.Minute, slog.New(slog.DiscardHandler))
+			require.NoError(t, err)
+			clock := mock.NewClock()
+
+			scanner := bufio.NewScanner(strings.NewReader(tt.input + "\n/quit\n"))
+			logBuf := &bytes.Buffer{}
+			handler := &mockHandler{}
+			r, err := repl.NewRunner("alice", "", nil, nil, handler, slog.New(slog.NewTextHandler(logBuf, nil)), scanner, &bytes.Buffer{}, repl.WithClock(clock, dispatcher))
+			require.NoError(t, err)
+
+			err = r.Run(ctx)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPushes, sender.getPushes())
+			assert.Contains(t, logBuf.String(), "clock set")
+			assert.Equal(t, 0, handler.callCount(), "/now should not be sent to the bot")
+		})
+	}
+
+	t.Run("moves the clock used for messages", func(t *testing.T) {
+		clock := mock.NewClock()
+		dispatcher := &stubDispatcher{}
+		scanner := bufio.NewScanner(strings.NewReader("/now 2030-01-01T18:30:00+09:00\n/quit\n"))
+		r, err := repl.NewRunner("alice", "", nil, nil, &mockHandler{}, slog.New(slog.DiscardHandler), scanner, &bytes.Buffer{}, repl.WithClock(clock, dispatcher))
+		require.NoError(t, err)
+
+		require.NoError(t, r.Run(context.Background()))
+
+		want := time.Date(2030, 1, 1, 9, 30, 0, 0, time.UTC)
+		assert.WithinDuration(t, want, clock.Now(), time.Minute)
+		require.Len(t, dispatcher.calls, 1)
+		assert.True(t, want.Equal(dispatcher.calls[0]))
+	})
+
+	t.Run("rejects a time that is not RFC3339", func(t *testing.T) {
+		dispatcher := &stubDispatcher{}
+		logBuf := &bytes.Buffer{}
+		scanner := bufio.NewScanner(strings.NewReader("/now tomorrow\n/quit\n"))
+		r, err := repl.NewRunner("alice", "", nil, nil, &mockHandler{}, slog.New(slog.NewTextHandler(logBuf, nil)), scanner, &bytes.Buffer{}, repl.WithClock(mock.NewClock(), dispatcher))
+		require.NoError(t, err)
+
+		require.NoError(t, r.Run(context.Background()))
+
+		assert.Contains(t, logBuf.String(), "usage: /now <RFC3339>")
+		assert.Empty(t, dispatcher.calls)
+	})
+
+	t.Run("is not available without a clock", func(t *testing.T) {
+		logBuf := &bytes.Buffer{}
+		handler := &mockHandler{}
+		scanner := bufio.NewScanner(strings.NewReader("/now 2030-01-01T18:30:00+09:00\n/quit\n"))
+		r, err := repl.NewRunner("alice", "", nil, nil, handler, slog.New(slog.NewTextHandler(logBuf, nil)), scanner, &bytes.Buffer{})
+		require.NoError(t, err)
+
+		require.NoError(t, r.Run(context.Background()))
+
+		assert.Contains(t, logBuf.String(), "/now is not available")
+		assert.Equal(t, 0, handler.callCount())
+	})
+
+	t.Run("requires a dispatcher with the clock", func(t *testing.T) {
+		scanner := bufio.NewScanner(strings.NewReader(""))
+		r, err := repl.NewRunner("alice", "", nil, nil, &mockHandler{}, slog.New(slog.DiscardHandler), scanner, &bytes.Buffer{}, repl.WithClock(mock.NewClock(), nil))
+
+		require.Error(t, err)
+		assert.Nil(t, r)
+	})
+}
+
+// stubDispatcher records the times it was asked to dispatch at.
+type stubDispatcher struct {
+	calls []time.Time
+}
+
+func (d *stubDispatcher) DispatchDue(ctx context.Context, now time.Time) error {
+	d.calls = append(d.calls, now)
+	return nil
+}
@@ -0,0 +1,51 @@
+package repl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ToolTracer prints tool invocations and results for the -verbose-tools flag.
+// Its methods match the agent's OnToolCall and OnToolResult hooks.
+type ToolTracer struct {
+	mu     sync.Mutex
+	writer io.Writer
+}
+
+// NewToolTracer creates a tracer that writes to w.
+func NewToolTracer(w io.Writer) *ToolTracer {
+	if w == nil {
+		panic("writer cannot be nil")
+	}
+	return &ToolTracer{writer: w}
+}
+
+// OnToolCall prints the tool name and its arguments.
+func (t *ToolTracer) OnToolCall(name string, args map[string]any) {
+	t.printf("[tool] %s args=%s\n", name, toJSON(args))
+}
+
+// OnToolResult prints the tool's result, or its error.
+func (t *ToolTracer) OnToolResult(name string, result map[string]any, err error) {
+	if err != nil {
+		t.printf("[tool] %s error=%v\n", name, err)
+		return
+	}
+	t.printf("[tool] %s result=%s\n", name, toJSON(result))
+}
+
+func (t *ToolTracer) printf(format string, a ...any) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, _ = fmt.Fprintf(t.writer, format, a...)
+}
+
+func toJSON(v map[string]any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
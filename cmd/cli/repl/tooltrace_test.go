@@ -0,0 +1,57 @@
+package repl_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"yuruppu/cmd/cli/repl"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stubAgent invokes tool hooks the way the function-calling loop does.
+type stubAgent struct {
+	onToolCall   func(name string, args map[string]any)
+	onToolResult func(name string, result map[string]any, err error)
+}
+
+func (a *stubAgent) callTool(name string, args, result map[string]any, err error) {
+	a.onToolCall(name, args)
+	a.onToolResult(name, result, err)
+}
+
+// =============================================================================
+// ToolTracer Tests
+// =============================================================================
+
+func TestNewToolTracer(t *testing.T) {
+	t.Run("should panic when writer is nil", func(t *testing.T) {
+		assert.Panics(t, func() {
+			repl.NewToolTracer(nil)
+		})
+	})
+}
+
+func TestToolTracer(t *testing.T) {
+	t.Run("prints tool name, args, and result", func(t *testing.T) {
+		var buf bytes.Buffer
+		tracer := repl.NewToolTracer(&buf)
+		ag := &stubAgent{onToolCall: tracer.OnToolCall, onToolResult: tracer.OnToolResult}
+
+		ag.callTool("get_weather", map[string]any{"location": "Tokyo"}, map[string]any{"temp": 20}, nil)
+
+		out := buf.String()
+		assert.Contains(t, out, `[tool] get_weather args={"location":"Tokyo"}`)
+		assert.Contains(t, out, `[tool] get_weather result={"temp":20}`)
+	})
+
+	t.Run("prints error", func(t *testing.T) {
+		var buf bytes.Buffer
+		tracer := repl.NewToolTracer(&buf)
+		ag := &stubAgent{onToolCall: tracer.OnToolCall, onToolResult: tracer.OnToolResult}
+
+		ag.callTool("get_weather", map[string]any{}, nil, errors.New("upstream down"))
+
+		assert.Contains(t, buf.String(), "[tool] get_weather error=upstream down")
+	})
+}
@@ -9,7 +9,6 @@ import (
 	"os"
 	"strings"
 	"yuruppu/cmd/cli/groupsim"
-	"yuruppu/cmd/cli/mock"
 )
 
 // EnsureDataDir checks if dataDir exists. If not, prompts user for confirmation.
@@ -68,8 +67,7 @@ func EnsureDataDir(dataDir string, stdin io.Reader, stderr io.Writer) error {
 
 // EnsureGroup handles group creation and membership validation.
 // Precondition: groupID must not be empty.
-func EnsureGroup(ctx context.Context, dataDir, groupID, userID string) (*groupsim.Service, error) {
-	groupSimStorage := mock.NewFileStorage(dataDir, "groupsim/")
+func EnsureGroup(ctx context.Context, groupSimStorage groupsim.Storage, groupID, userID string) (*groupsim.Service, error) {
 	groupService, err := groupsim.NewService(groupSimStorage)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create group service: %w", err)
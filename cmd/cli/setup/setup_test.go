@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"yuruppu/cmd/cli/groupsim"
+	"yuruppu/cmd/cli/mock"
 	"yuruppu/cmd/cli/setup"
 
 	"github.com/stretchr/testify/assert"
@@ -192,3 +194,56 @@ func TestEnsureDataDir_PathIsFile(t *testing.T) {
 		require.Error(t, err, "should return error when path is a file")
 	})
 }
+
+// TestEnsureGroup tests group creation and membership validation at startup
+// FR-004: Non-members of an existing group are rejected before the session starts
+func TestEnsureGroup(t *testing.T) {
+	t.Run("should create the group with the user as first member when it does not exist", func(t *testing.T) {
+		// Given
+		store := mock.NewMemoryStorage()
+
+		// When
+		groupService, err := setup.EnsureGroup(t.Context(), store, "newgroup", "alice")
+
+		// Then
+		require.NoError(t, err)
+		isMember, err := groupService.IsMember(t.Context(), "newgroup", "alice")
+		require.NoError(t, err)
+		assert.True(t, isMember)
+	})
+
+	t.Run("should start as a member of an existing group", func(t *testing.T) {
+		// Given
+		store := mock.NewMemoryStorage()
+		existing, err := groupsim.NewService(store)
+		require.NoError(t, err)
+		require.NoError(t, existing.Create(t.Context(), "mygroup", "alice"))
+		require.NoError(t, existing.AddMember(t.Context(), "mygroup", "bob"))
+
+		// When
+		groupService, err := setup.EnsureGroup(t.Context(), store, "mygroup", "bob")
+
+		// Then
+		require.NoError(t, err)
+		require.NotNil(t, groupService)
+	})
+
+	t.Run("should reject a user who is not a member of an existing group", func(t *testing.T) {
+		// Given
+		store := mock.NewMemoryStorage()
+		existing, err := groupsim.NewService(store)
+		require.NoError(t, err)
+		require.NoError(t, existing.Create(t.Context(), "mygroup", "alice"))
+
+		// When
+		groupService, err := setup.EnsureGroup(t.Context(), store, "mygroup", "charlie")
+
+		// Then
+		require.Error(t, err)
+		assert.Nil(t, groupService)
+		assert.Contains(t, err.Error(), "user 'charlie' is not a member of group 'mygroup'")
+		members, err := existing.GetMembers(t.Context(), "mygroup")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"alice"}, members, "should not add the non-member")
+	})
+}
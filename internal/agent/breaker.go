@@ -0,0 +1,84 @@
+package agent
+
+import (
+	"sync"
+	"time"
+)
+
+// circuitBreaker fails generations fast while the LLM backend keeps failing.
+// After threshold consecutive failures it opens for cooldown, during which every request is rejected.
+// Once the cooldown has passed it lets a single trial request through (half-open);
+// a success closes the breaker and a failure opens it again for another cooldown.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// newCircuitBreaker returns nil if threshold is 0, which disables the breaker.
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	if threshold == 0 {
+		return nil
+	}
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+	}
+}
+
+// allow reports whether a request may proceed and whether it is the half-open trial.
+// A caller that is allowed must report the outcome with record.
+func (b *circuitBreaker) allow() (bool, bool) {
+	if b == nil {
+		return true, false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return true, false
+	}
+	if b.probing || time.Now().Before(b.openUntil) {
+		return false, false
+	}
+	b.probing = true
+	return true, true
+}
+
+// cancel reports that the caller gave up on an allowed request.
+// The outcome says nothing about the backend, so the failure count is left as is
+// and only the half-open trial is released for the next caller.
+func (b *circuitBreaker) cancel(probe bool) {
+	if b == nil || !probe {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// record reports the outcome of an allowed request.
+// It returns true if this failure opened the breaker.
+func (b *circuitBreaker) record(probe, failed bool) bool {
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if probe {
+		b.probing = false
+	}
+	if !failed {
+		b.failures = 0
+		return false
+	}
+	b.failures++
+	if b.failures < b.threshold {
+		return false
+	}
+	b.openUntil = time.Now().Add(b.cooldown)
+	return b.failures == b.threshold || probe
+}
@@ -6,6 +6,7 @@ type ctxKey int
 
 const (
 	ctxKeyModelName ctxKey = iota
+	ctxKeyToolsDisabled
 )
 
 // WithModelName returns a new context with the model name set.
@@ -19,3 +20,15 @@ func ModelNameFromContext(ctx context.Context) (string, bool) {
 	v, ok := ctx.Value(ctxKeyModelName).(string)
 	return v, ok
 }
+
+// WithToolsDisabled returns a new context that makes Generate offer no tools to the model.
+// Use it for turns that clearly need no tool, such as plain chit-chat.
+func WithToolsDisabled(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ctxKeyToolsDisabled, true)
+}
+
+// ToolsDisabledFromContext reports whether tools are disabled for the request.
+func ToolsDisabledFromContext(ctx context.Context) bool {
+	v, _ := ctx.Value(ctxKeyToolsDisabled).(bool)
+	return v
+}
@@ -6,11 +6,14 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"net/http"
 	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"google.golang.org/genai"
 )
@@ -18,6 +21,56 @@ import (
 // minCacheTokens is the minimum token count required for Gemini context caching.
 const minCacheTokens = 1024
 
+// toolBudgetExhaustedNote is sent to the model when the per-turn tool call budget runs out.
+const toolBudgetExhaustedNote = "[The tool call budget for this turn has been used up. Do not call any more tools. Answer with what you have and mention that you stopped early.]"
+
+// toolBudgetExhaustedAnswerToolsNote replaces toolBudgetExhaustedNote when answer tools remain callable.
+const toolBudgetExhaustedAnswerToolsNote = "[The tool call budget for this turn has been used up. Only the tools for answering can still be called. Answer with what you have and mention that you stopped early.]"
+
+// needsInputNote is sent to the model after a tool returns a needs_input result.
+// The %s verbs are the tool name and the prompt to relay.
+const needsInputNote = "[The %s tool needs more information from the user before it can proceed. This is not an error. Ask the user: %s]"
+
+// invalidNote is sent to the model after a tool rejects its arguments with an invalid result.
+// The %s verbs are the tool name and the violated constraints.
+const invalidNote = "[The %s tool rejected the arguments: %s. Fix them and call the tool again. Ask the user only if you cannot tell what they meant.]"
+
+// userErrorNote is sent to the model after a tool fails with a UserError.
+// The %s verbs are the tool name and the error message to relay.
+const userErrorNote = "[The %s tool could not do what was asked because of the request itself. Tell the user why so that they can correct it: %s]"
+
+// systemErrorMessage is the error the model sees when a tool fails with a SystemError.
+// The details are logged instead, since they mean nothing to the user.
+const systemErrorMessage = "a temporary system problem prevented this; apologize and ask the user to try again later"
+
+// Error types reported in the error_type field of a failed tool response.
+const (
+	toolErrorTypeUser   = "user"
+	toolErrorTypeSystem = "system"
+)
+
+// ClosedError is returned by Generate after Close has been called.
+type ClosedError struct {
+	Model string
+}
+
+func (e *ClosedError) Error() string {
+	return fmt.Sprintf("agent for %s is closed", e.Model)
+}
+
+// UnavailableError is returned by Generate without calling the backend while the circuit breaker is open.
+type UnavailableError struct {
+	Model string
+}
+
+func (e *UnavailableError) Error() string {
+	return fmt.Sprintf("LLM backend is temporarily unavailable: %s", e.Model)
+}
+
+// DefaultMaxSystemPromptLength is the system prompt length limit, in characters, used when
+// GeminiConfig.MaxSystemPromptLength is 0. It is several times the size of the built-in prompt.
+const DefaultMaxSystemPromptLength = 32000
+
 // GeminiConfig holds configuration for GeminiAgent.
 type GeminiConfig struct {
 	ProjectID        string
@@ -25,9 +78,57 @@ type GeminiConfig struct {
 	Model            string
 	SystemPrompt     string
 	Tools            []Tool
-	FunctionCallOnly bool
+	FunctionCallOnly bool // Force a tool call on every turn; has no effect on requests with tools disabled
 	CacheDisplayName string
 	CacheTTL         time.Duration
+
+	// MaxSystemPromptLength caps the system prompt length in characters, after trimming.
+	// A longer prompt fails construction instead of silently eating the context window on every request.
+	// 0 uses DefaultMaxSystemPromptLength.
+	MaxSystemPromptLength int
+
+	// MaxToolCallsPerTurn caps the total tool invocations in one Generate call, counted across
+	// iterations and parallel calls. Once it is reached the model must answer with one of
+	// AnswerTools, or without tools if AnswerTools is empty.
+	// 0 means unlimited.
+	MaxToolCallsPerTurn int
+
+	// AnswerTools names the tools the model may still call once MaxToolCallsPerTurn is reached,
+	// such as the tool that sends the reply. Set it when the caller only delivers tool output,
+	// e.g. with FunctionCallOnly, so the final answer is not lost as plain text.
+	// Every name must be one of Tools.
+	AnswerTools []string
+
+	// ToolRetries is how many more times a tool call failing with a SystemError is attempted.
+	// Only enable it when every tool is safe to repeat after a system failure.
+	// 0 disables retries.
+	ToolRetries int
+
+	// BreakerThreshold is the number of consecutive failed generations after which Generate
+	// fails fast with *UnavailableError for BreakerCooldown. After the cooldown a single trial
+	// generation is let through; it closes the breaker on success and reopens it on failure.
+	// 0 disables the breaker.
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+
+	// Labels are attached to every generation request so that usage can be broken down in
+	// GCP billing, e.g. by environment or deployment. Keys and values must satisfy GCP's label
+	// constraints. nil or empty means no labels.
+	Labels map[string]string
+
+	// HTTPClient, if set, is used for Vertex AI requests and must handle authentication itself.
+	// Defaults to a client using Application Default Credentials.
+	HTTPClient *http.Client
+
+	// LogPayloads, if set, logs the full prompt (system instruction and contents) and the raw
+	// response of every model call at DEBUG level. The payloads may contain PII, so keep it off in production.
+	LogPayloads bool
+
+	// OnToolCall, if set, is called before each tool dispatch.
+	// OnToolResult, if set, is called after it with the response or error.
+	// Both may be called concurrently when the model requests several tools at once.
+	OnToolCall   func(name string, args map[string]any)
+	OnToolResult func(name string, result map[string]any, err error)
 }
 
 // GeminiAgent is an implementation of Agent using Google Gemini via Vertex AI.
@@ -36,10 +137,24 @@ type GeminiAgent struct {
 	model                     string
 	contentConfigWithCache    *genai.GenerateContentConfig
 	contentConfigWithoutCache *genai.GenerateContentConfig
+	contentConfigWithoutTools *genai.GenerateContentConfig
+	contentConfigFinalAnswer  *genai.GenerateContentConfig
+	maxToolCallsPerTurn       int
+	answerTools               []string
+	toolRetries               int
+	breaker                   *circuitBreaker
 	toolMap                   map[string]tool
+	offeredTools              []string
+	onToolCall                func(name string, args map[string]any)
+	onToolResult              func(name string, result map[string]any, err error)
+	systemPrompt              string
+	logPayloads               bool
 	logger                    *slog.Logger
 
-	closed             atomic.Bool
+	// mu guards closed so that no generation starts after Close begins waiting.
+	mu                 sync.RWMutex
+	closed             bool
+	inflight           sync.WaitGroup
 	cancelCacheRefresh context.CancelFunc
 	cacheName          atomic.Value // string
 }
@@ -77,18 +192,53 @@ func NewGeminiAgent(ctx context.Context, cfg GeminiConfig, logger *slog.Logger)
 	if systemPrompt == "" {
 		return nil, errors.New("systemPrompt is required")
 	}
+	if cfg.MaxSystemPromptLength < 0 {
+		return nil, errors.New("maxSystemPromptLength cannot be negative")
+	}
+	maxSystemPromptLength := cfg.MaxSystemPromptLength
+	if maxSystemPromptLength == 0 {
+		maxSystemPromptLength = DefaultMaxSystemPromptLength
+	}
+	if n := utf8.RuneCountInString(systemPrompt); n > maxSystemPromptLength {
+		return nil, fmt.Errorf("systemPrompt is too long: %d characters, the maximum is %d; check the prompt template and any externally loaded prompt", n, maxSystemPromptLength)
+	}
 	if cacheDisplayName == "" {
 		return nil, errors.New("cacheDisplayName is required")
 	}
 	if cfg.CacheTTL <= 0 {
 		return nil, errors.New("cacheTTL must be positive")
 	}
+	if cfg.MaxToolCallsPerTurn < 0 {
+		return nil, errors.New("maxToolCallsPerTurn cannot be negative")
+	}
+	for _, name := range cfg.AnswerTools {
+		if !slices.ContainsFunc(cfg.Tools, func(t Tool) bool { return t.Name() == name }) {
+			return nil, fmt.Errorf("answer tool %s is not one of the tools", name)
+		}
+	}
+	if cfg.ToolRetries < 0 {
+		return nil, errors.New("toolRetries cannot be negative")
+	}
+	if cfg.BreakerThreshold < 0 {
+		return nil, errors.New("breakerThreshold cannot be negative")
+	}
+	if cfg.BreakerThreshold > 0 && cfg.BreakerCooldown <= 0 {
+		return nil, errors.New("breakerCooldown must be positive when the breaker is enabled")
+	}
+	if err := ValidateLabels(cfg.Labels); err != nil {
+		return nil, err
+	}
+	var labels map[string]string
+	if len(cfg.Labels) > 0 {
+		labels = maps.Clone(cfg.Labels)
+	}
 
 	// Create Vertex AI client
 	client, err := genai.NewClient(ctx, &genai.ClientConfig{
-		Project:  projectID,
-		Location: region,
-		Backend:  genai.BackendVertexAI,
+		Project:    projectID,
+		Location:   region,
+		Backend:    genai.BackendVertexAI,
+		HTTPClient: cfg.HTTPClient,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Vertex AI client: %w", err)
@@ -118,8 +268,15 @@ func NewGeminiAgent(ctx context.Context, cfg GeminiConfig, logger *slog.Logger)
 	var genaiTools []*genai.Tool
 	var toolConfig *genai.ToolConfig
 	var toolMap map[string]tool
+	var offeredTools []string
 	if len(cfg.Tools) > 0 {
-		genaiTools = []*genai.Tool{toGenaiTool(cfg.Tools)}
+		genaiTool := toGenaiTool(cfg.Tools)
+		genaiTools = []*genai.Tool{genaiTool}
+		if genaiTool != nil {
+			for _, decl := range genaiTool.FunctionDeclarations {
+				offeredTools = append(offeredTools, decl.Name)
+			}
+		}
 		toolMap = make(map[string]tool, len(cfg.Tools))
 		for _, t := range cfg.Tools {
 			wrapped, err := newTool(t)
@@ -137,19 +294,68 @@ func NewGeminiAgent(ctx context.Context, cfg GeminiConfig, logger *slog.Logger)
 		}
 	}
 
+	// Once the tool call budget is used up, only the answer tools may be called.
+	// Without answer tools, new calls are forbidden and the model answers in text; the tool
+	// declarations are kept because earlier calls refer to them.
+	contentConfigFinalAnswer := &genai.GenerateContentConfig{
+		SystemInstruction: systemInstruction,
+		Tools:             genaiTools,
+		ToolConfig: &genai.ToolConfig{
+			FunctionCallingConfig: &genai.FunctionCallingConfig{
+				Mode: genai.FunctionCallingConfigModeNone,
+			},
+		},
+		Labels: labels,
+	}
+	if len(cfg.AnswerTools) > 0 {
+		contentConfigFinalAnswer = &genai.GenerateContentConfig{
+			SystemInstruction: systemInstruction,
+			Tools:             genaiTools,
+			ToolConfig: &genai.ToolConfig{
+				FunctionCallingConfig: &genai.FunctionCallingConfig{
+					Mode:                 genai.FunctionCallingConfigModeAny,
+					AllowedFunctionNames: slices.Clone(cfg.AnswerTools),
+				},
+			},
+			Labels: labels,
+		}
+	}
+
 	agent := &GeminiAgent{
 		client: client,
 		model:  model,
 		// Do not duplicate fields already set in cachedContentConfig.
 		// Duplicating them will cause an error.
-		contentConfigWithCache: &genai.GenerateContentConfig{},
+		contentConfigWithCache: &genai.GenerateContentConfig{
+			Labels: labels,
+		},
 		contentConfigWithoutCache: &genai.GenerateContentConfig{
 			SystemInstruction: systemInstruction,
 			Tools:             genaiTools,
 			ToolConfig:        toolConfig,
+			Labels:            labels,
+		},
+		// The cached content carries the tools, so requests without tools cannot use the cache.
+		contentConfigWithoutTools: &genai.GenerateContentConfig{
+			SystemInstruction: systemInstruction,
+			Labels:            labels,
 		},
-		toolMap: toolMap,
-		logger:  logger,
+		contentConfigFinalAnswer: contentConfigFinalAnswer,
+		maxToolCallsPerTurn:      cfg.MaxToolCallsPerTurn,
+		answerTools:              slices.Clone(cfg.AnswerTools),
+		toolRetries:              cfg.ToolRetries,
+		breaker:                  newCircuitBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown),
+		toolMap:                  toolMap,
+		offeredTools:             offeredTools,
+		onToolCall:               cfg.OnToolCall,
+		onToolResult:             cfg.OnToolResult,
+		systemPrompt:             systemPrompt,
+		logPayloads:              cfg.LogPayloads,
+		logger:                   logger,
+	}
+
+	if cfg.LogPayloads {
+		logger.Warn("LLM payload logging is enabled; prompts and responses may contain PII and are logged at DEBUG level")
 	}
 
 	if tokenCount < minCacheTokens {
@@ -173,9 +379,17 @@ func NewGeminiAgent(ctx context.Context, cfg GeminiConfig, logger *slog.Logger)
 
 // Generate generates a response for the conversation history.
 // The last message in history must be the user message to respond to.
+// If ctx was created with WithToolsDisabled, no tools are offered and the response is text only.
+// Returns *UnavailableError without calling the backend while the circuit breaker is open.
 func (g *GeminiAgent) Generate(ctx context.Context, history []Message) (*AssistantMessage, error) {
-	if g.closed.Load() {
-		return nil, errors.New("agent is closed")
+	if !g.acquire() {
+		return nil, &ClosedError{Model: g.model}
+	}
+	defer g.inflight.Done()
+
+	allowed, probe := g.breaker.allow()
+	if !allowed {
+		return nil, &UnavailableError{Model: g.model}
 	}
 
 	g.logger.Debug("generating text",
@@ -186,21 +400,43 @@ func (g *GeminiAgent) Generate(ctx context.Context, history []Message) (*Assista
 	contents := g.buildContents(history)
 
 	var config *genai.GenerateContentConfig
+	offeredTools := g.offeredTools
 	cacheName, _ := g.cacheName.Load().(string)
-	if cacheName == "" {
+	switch {
+	case ToolsDisabledFromContext(ctx):
+		config = g.contentConfigWithoutTools
+		offeredTools = nil
+	case cacheName == "":
 		config = g.contentConfigWithoutCache
-	} else {
+	default:
 		configCopy := *g.contentConfigWithCache
 		configCopy.CachedContent = cacheName
 		config = &configCopy
 	}
 
-	addedContents, err := g.generateWithToolLoop(ctx, g.model, contents, config)
+	loop, err := g.generateWithToolLoop(ctx, g.model, contents, config)
+	// A caller giving up says nothing about the backend; a deadline still counts as a failure.
+	if errors.Is(err, context.Canceled) {
+		g.breaker.cancel(probe)
+		return nil, err
+	}
+	failed := err != nil
+	if g.breaker.record(probe, failed) {
+		g.logger.WarnContext(ctx, "circuit breaker opened, failing fast until cooldown ends",
+			slog.String("model", g.model),
+			slog.Duration("cooldown", g.breaker.cooldown),
+			slog.Any("error", err),
+		)
+	} else if probe && !failed {
+		g.logger.InfoContext(ctx, "circuit breaker closed", slog.String("model", g.model))
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	parts := g.extractAssistantParts(addedContents)
+	g.logToolUsage(ctx, offeredTools, loop.contents)
+
+	parts := g.extractAssistantParts(loop.contents)
 
 	g.logger.Info("response generated successfully",
 		slog.String("model", g.model),
@@ -208,21 +444,69 @@ func (g *GeminiAgent) Generate(ctx context.Context, history []Message) (*Assista
 	)
 
 	return &AssistantMessage{
-		Parts: parts,
+		Parts:             parts,
+		Final:             loop.final,
+		FinishReason:      finishReason(loop.last),
+		SafetyBlocked:     safetyBlocked(loop.last),
+		BlockedCategories: blockedCategories(loop.last),
 	}, nil
 }
 
+// logToolUsage logs at DEBUG level the tools offered to the model and the ones it called,
+// so that offered-versus-used can be correlated when tuning the toolset.
+func (g *GeminiAgent) logToolUsage(ctx context.Context, offeredTools []string, addedContents []*genai.Content) {
+	if !g.logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+	calledTools := []string{}
+	for _, content := range addedContents {
+		if content == nil || content.Role != genai.RoleModel {
+			continue
+		}
+		for _, part := range content.Parts {
+			if part != nil && part.FunctionCall != nil && !slices.Contains(calledTools, part.FunctionCall.Name) {
+				calledTools = append(calledTools, part.FunctionCall.Name)
+			}
+		}
+	}
+	g.logger.DebugContext(ctx, "tool usage",
+		slog.String("model", g.model),
+		slog.Any("offeredTools", offeredTools),
+		slog.Any("calledTools", calledTools),
+	)
+}
+
+// toolLoopResult is the outcome of generateWithToolLoop.
+type toolLoopResult struct {
+	contents []*genai.Content               // all contents added after the initial contents
+	final    bool                           // whether a final tool ended the loop
+	last     *genai.GenerateContentResponse // the last model response
+}
+
 // generateWithToolLoop handles multi-turn conversation with tool calling.
-// Returns all contents added after initialContents.
-func (g *GeminiAgent) generateWithToolLoop(ctx context.Context, model string, initialContents []*genai.Content, config *genai.GenerateContentConfig) ([]*genai.Content, error) {
+func (g *GeminiAgent) generateWithToolLoop(ctx context.Context, model string, initialContents []*genai.Content, config *genai.GenerateContentConfig) (*toolLoopResult, error) {
 	var addedContents []*genai.Content
+	toolCalls := 0
 
 	for {
 		allContents := slices.Concat(initialContents, addedContents)
+		if g.logPayloads {
+			g.logger.DebugContext(ctx, "LLM request (may contain PII)",
+				slog.String("model", model),
+				slog.String("systemPrompt", g.systemPrompt),
+				slog.Any("contents", allContents),
+			)
+		}
 		resp, err := g.client.Models.GenerateContent(ctx, model, allContents, config)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate content: %w", err)
 		}
+		if g.logPayloads {
+			g.logger.DebugContext(ctx, "LLM response (may contain PII)",
+				slog.String("model", model),
+				slog.Any("response", resp),
+			)
+		}
 
 		// Append model's response
 		if len(resp.Candidates) > 0 && resp.Candidates[0].Content != nil {
@@ -232,15 +516,30 @@ func (g *GeminiAgent) generateWithToolLoop(ctx context.Context, model string, in
 		// Check for function calls
 		functionCalls := resp.FunctionCalls()
 		if len(functionCalls) == 0 {
-			return addedContents, nil
+			return &toolLoopResult{contents: addedContents, last: resp}, nil
 		}
 
+		// Calls beyond the budget are answered with an error instead of being executed
+		allowed := len(functionCalls)
+		if g.maxToolCallsPerTurn > 0 {
+			allowed = min(allowed, g.maxToolCallsPerTurn-toolCalls)
+		}
+		toolCalls += allowed
+
 		// Execute all function calls in parallel
 		toolCtx := WithModelName(ctx, resp.ModelVersion)
 		funcResps := make([]*genai.FunctionResponse, len(functionCalls))
 		finals := make([]bool, len(functionCalls))
 		var wg sync.WaitGroup
 		for i, call := range functionCalls {
+			if i >= allowed {
+				funcResps[i] = &genai.FunctionResponse{
+					Name:     call.Name,
+					ID:       call.ID,
+					Response: map[string]any{"error": "tool call budget exhausted"},
+				}
+				continue
+			}
 			wg.Add(1)
 			go func(i int, call *genai.FunctionCall) {
 				defer wg.Done()
@@ -260,12 +559,124 @@ func (g *GeminiAgent) generateWithToolLoop(ctx context.Context, model string, in
 			)
 			funcRespParts[i] = genai.NewPartFromFunctionResponse(funcResp.Name, funcResp.Response)
 		}
+		funcRespParts = append(funcRespParts, g.needsInputNotes(ctx, funcResps)...)
+		funcRespParts = append(funcRespParts, g.invalidNotes(ctx, funcResps)...)
+		funcRespParts = append(funcRespParts, userErrorNotes(funcResps)...)
+		if slices.Contains(finals, true) {
+			addedContents = append(addedContents, genai.NewContentFromParts(funcRespParts, genai.RoleUser))
+			return &toolLoopResult{contents: addedContents, final: true, last: resp}, nil
+		}
+
+		if g.maxToolCallsPerTurn > 0 && toolCalls >= g.maxToolCallsPerTurn {
+			g.logger.WarnContext(ctx, "tool call budget exhausted, generating final answer",
+				slog.String("model", model),
+				slog.Int("maxToolCallsPerTurn", g.maxToolCallsPerTurn),
+				slog.Int("requested", toolCalls+len(functionCalls)-allowed),
+				slog.Any("answerTools", g.answerTools),
+			)
+			note := toolBudgetExhaustedNote
+			if len(g.answerTools) > 0 {
+				note = toolBudgetExhaustedAnswerToolsNote
+			}
+			funcRespParts = append(funcRespParts, genai.NewPartFromText(note))
+			addedContents = append(addedContents, genai.NewContentFromParts(funcRespParts, genai.RoleUser))
+			return g.finalAnswer(ctx, model, initialContents, addedContents)
+		}
+
 		addedContents = append(addedContents, genai.NewContentFromParts(funcRespParts, genai.RoleUser))
+	}
+}
 
-		if slices.Contains(finals, true) {
-			return addedContents, nil
+// finalAnswer asks the model for its last response of a turn whose tool call budget is used up.
+// Calls to the answer tools are executed so that the answer reaches the user; any other call
+// is refused.
+func (g *GeminiAgent) finalAnswer(ctx context.Context, model string, initialContents, addedContents []*genai.Content) (*toolLoopResult, error) {
+	resp, err := g.client.Models.GenerateContent(ctx, model, slices.Concat(initialContents, addedContents), g.contentConfigFinalAnswer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate content: %w", err)
+	}
+	if len(resp.Candidates) > 0 && resp.Candidates[0].Content != nil {
+		addedContents = append(addedContents, resp.Candidates[0].Content)
+	}
+
+	toolCtx := WithModelName(ctx, resp.ModelVersion)
+	final := false
+	var funcRespParts []*genai.Part
+	for _, call := range resp.FunctionCalls() {
+		funcResp := &genai.FunctionResponse{
+			Name:     call.Name,
+			ID:       call.ID,
+			Response: map[string]any{"error": "tool call budget exhausted"},
+		}
+		if slices.Contains(g.answerTools, call.Name) {
+			var isFinal bool
+			funcResp, isFinal = g.executeTool(toolCtx, call)
+			final = final || isFinal
+		}
+		funcRespParts = append(funcRespParts, genai.NewPartFromFunctionResponse(funcResp.Name, funcResp.Response))
+	}
+	if len(funcRespParts) > 0 {
+		addedContents = append(addedContents, genai.NewContentFromParts(funcRespParts, genai.RoleUser))
+	}
+	return &toolLoopResult{contents: addedContents, final: final, last: resp}, nil
+}
+
+// finishReason returns why the model stopped generating resp.
+// A blocked prompt has no candidates, so the block reason is reported instead.
+func finishReason(resp *genai.GenerateContentResponse) string {
+	if len(resp.Candidates) > 0 && resp.Candidates[0].FinishReason != "" {
+		return string(resp.Candidates[0].FinishReason)
+	}
+	if resp.PromptFeedback != nil && resp.PromptFeedback.BlockReason != "" {
+		return string(resp.PromptFeedback.BlockReason)
+	}
+	return ""
+}
+
+// safetyFinishReasons are the finish reasons that mean a safety filter blocked the response.
+// Recitation and other non-safety stops are not included.
+var safetyFinishReasons = []genai.FinishReason{
+	genai.FinishReasonSafety,
+	genai.FinishReasonBlocklist,
+	genai.FinishReasonProhibitedContent,
+	genai.FinishReasonSPII,
+	genai.FinishReasonImageSafety,
+	genai.FinishReasonImageProhibitedContent,
+}
+
+// safetyBlockReasons are the prompt block reasons that mean a safety filter blocked the prompt.
+var safetyBlockReasons = []genai.BlockedReason{
+	genai.BlockedReasonSafety,
+	genai.BlockedReasonBlocklist,
+	genai.BlockedReasonProhibitedContent,
+	genai.BlockedReasonImageSafety,
+	genai.BlockedReasonJailbreak,
+}
+
+// safetyBlocked reports whether a safety filter blocked resp or the prompt it answers.
+func safetyBlocked(resp *genai.GenerateContentResponse) bool {
+	if len(resp.Candidates) > 0 && slices.Contains(safetyFinishReasons, resp.Candidates[0].FinishReason) {
+		return true
+	}
+	return resp.PromptFeedback != nil && slices.Contains(safetyBlockReasons, resp.PromptFeedback.BlockReason)
+}
+
+// blockedCategories returns the harm categories whose ratings blocked resp or its prompt.
+func blockedCategories(resp *genai.GenerateContentResponse) []string {
+	var ratings []*genai.SafetyRating
+	if len(resp.Candidates) > 0 {
+		ratings = append(ratings, resp.Candidates[0].SafetyRatings...)
+	}
+	if resp.PromptFeedback != nil {
+		ratings = append(ratings, resp.PromptFeedback.SafetyRatings...)
+	}
+	var categories []string
+	for _, rating := range ratings {
+		if rating != nil && rating.Blocked && !slices.Contains(categories, string(rating.Category)) {
+			categories = append(categories, string(rating.Category))
 		}
 	}
+	return categories
 }
 
 // executeTool executes a tool and returns the function response.
@@ -275,15 +686,32 @@ func (g *GeminiAgent) executeTool(ctx context.Context, call *genai.FunctionCall)
 		ID:   call.ID,
 	}
 
+	if g.onToolCall != nil {
+		g.onToolCall(call.Name, call.Args)
+	}
+
 	t, ok := g.toolMap[call.Name]
 	if !ok {
-		resp.Response = map[string]any{"error": fmt.Sprintf("unknown tool: %s", call.Name)}
+		err := fmt.Errorf("unknown tool: %s", call.Name)
+		g.notifyToolResult(call.Name, nil, err)
+		resp.Response = map[string]any{"error": err.Error()}
 		return resp, false
 	}
 
 	result, err := t.Use(ctx, call.Args)
+	var systemErr *SystemError
+	for attempt := 1; attempt <= g.toolRetries && errors.As(err, &systemErr) && ctx.Err() == nil; attempt++ {
+		g.logger.WarnContext(ctx, "tool failed with a system error, retrying",
+			slog.String("tool", call.Name),
+			slog.Int("attempt", attempt),
+			slog.String("error", systemErr.Error()),
+			slog.Any("cause", systemErr.Unwrap()),
+		)
+		result, err = t.Use(ctx, call.Args)
+	}
+	g.notifyToolResult(call.Name, result.Response, err)
 	if err != nil {
-		resp.Response = map[string]any{"error": err.Error()}
+		resp.Response = g.toolErrorResponse(ctx, call.Name, err)
 		return resp, false
 	}
 
@@ -291,13 +719,124 @@ func (g *GeminiAgent) executeTool(ctx context.Context, call *genai.FunctionCall)
 	return resp, result.Final
 }
 
-// Close releases any resources held by the agent.
+// toolErrorResponse converts a tool error into the function response the model sees.
+// User errors are passed with their message to relay, system errors are logged and hidden,
+// and unclassified errors are passed as is.
+func (g *GeminiAgent) toolErrorResponse(ctx context.Context, name string, err error) map[string]any {
+	var userErr *UserError
+	if errors.As(err, &userErr) {
+		return map[string]any{"error": userErr.Error(), "error_type": toolErrorTypeUser}
+	}
+	var systemErr *SystemError
+	if errors.As(err, &systemErr) {
+		g.logger.ErrorContext(ctx, "tool failed with a system error",
+			slog.String("tool", name),
+			slog.String("error", systemErr.Error()),
+			slog.Any("cause", systemErr.Unwrap()),
+		)
+		return map[string]any{"error": systemErrorMessage, "error_type": toolErrorTypeSystem}
+	}
+	return map[string]any{"error": err.Error()}
+}
+
+// userErrorNotes returns a note asking the model to relay the message of every user error response.
+func userErrorNotes(funcResps []*genai.FunctionResponse) []*genai.Part {
+	var notes []*genai.Part
+	for _, funcResp := range funcResps {
+		if errorType, _ := funcResp.Response["error_type"].(string); errorType != toolErrorTypeUser {
+			continue
+		}
+		msg, _ := funcResp.Response["error"].(string)
+		notes = append(notes, genai.NewPartFromText(fmt.Sprintf(userErrorNote, funcResp.Name, msg)))
+	}
+	return notes
+}
+
+// needsInputNotes returns a note asking the model to relay the prompt of every needs_input response.
+func (g *GeminiAgent) needsInputNotes(ctx context.Context, funcResps []*genai.FunctionResponse) []*genai.Part {
+	var notes []*genai.Part
+	for _, funcResp := range funcResps {
+		if !isNeedsInput(funcResp.Response) {
+			continue
+		}
+		prompt, _ := funcResp.Response["prompt"].(string)
+		g.logger.InfoContext(ctx, "tool needs user input",
+			slog.String("tool", funcResp.Name),
+			slog.Any("missing", funcResp.Response["missing"]),
+		)
+		notes = append(notes, genai.NewPartFromText(fmt.Sprintf(needsInputNote, funcResp.Name, prompt)))
+	}
+	return notes
+}
+
+// invalidNotes returns a note asking the model to fix the arguments of every invalid response.
+func (g *GeminiAgent) invalidNotes(ctx context.Context, funcResps []*genai.FunctionResponse) []*genai.Part {
+	var notes []*genai.Part
+	for _, funcResp := range funcResps {
+		if !isInvalid(funcResp.Response) {
+			continue
+		}
+		errs, _ := funcResp.Response["errors"].([]any)
+		msgs := make([]string, 0, len(errs))
+		for _, e := range errs {
+			if msg, ok := e.(string); ok {
+				msgs = append(msgs, msg)
+			}
+		}
+		g.logger.InfoContext(ctx, "tool rejected invalid arguments",
+			slog.String("tool", funcResp.Name),
+			slog.Any("errors", msgs),
+		)
+		notes = append(notes, genai.NewPartFromText(fmt.Sprintf(invalidNote, funcResp.Name, strings.Join(msgs, "; "))))
+	}
+	return notes
+}
+
+// notifyToolResult invokes the OnToolResult hook if set.
+func (g *GeminiAgent) notifyToolResult(name string, result map[string]any, err error) {
+	if g.onToolResult != nil {
+		g.onToolResult(name, result, err)
+	}
+}
+
+// acquire registers an in-flight generation.
+// Returns false if the agent is closed.
+func (g *GeminiAgent) acquire() bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if g.closed {
+		return false
+	}
+	g.inflight.Add(1)
+	return true
+}
+
+// Close rejects new generations and waits for in-flight ones to finish
+// before releasing resources held by the agent.
+// Returns an error if ctx is done before in-flight generations drain.
 func (g *GeminiAgent) Close(ctx context.Context) error {
-	if !g.closed.CompareAndSwap(false, true) {
+	g.mu.Lock()
+	if g.closed {
+		g.mu.Unlock()
 		return nil
 	}
+	g.closed = true
+	g.mu.Unlock()
+
 	if g.cancelCacheRefresh != nil {
-		g.cancelCacheRefresh()
+		defer g.cancelCacheRefresh()
+	}
+
+	drained := make(chan struct{})
+	go func() {
+		g.inflight.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		return fmt.Errorf("timed out waiting for in-flight generations: %w", ctx.Err())
 	}
 
 	g.logger.Debug("agent closed", slog.String("model", g.model))
@@ -8,6 +8,7 @@ import (
 	"log/slog"
 	"os"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -185,3 +186,166 @@ func TestGeminiAgent_Integration_CloseIdempotent(t *testing.T) {
 		assert.NoError(t, err, "Close call %d should not error", i+1)
 	}
 }
+
+// slowTool blocks until released and ends the tool loop.
+type slowTool struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *slowTool) Name() string        { return "wait_for_release" }
+func (s *slowTool) Description() string { return "Always call this tool." }
+func (s *slowTool) ParametersJsonSchema() []byte {
+	return []byte(`{"type":"object","properties":{},"additionalProperties":false}`)
+}
+
+func (s *slowTool) ResponseJsonSchema() []byte {
+	return []byte(`{"type":"object","properties":{"status":{"type":"string"}},"required":["status"]}`)
+}
+
+func (s *slowTool) Callback(ctx context.Context, _ map[string]any) (map[string]any, error) {
+	close(s.started)
+	<-s.release
+	return map[string]any{"status": "done"}, nil
+}
+
+func (s *slowTool) IsFinal(map[string]any) bool { return true }
+
+func TestGeminiAgent_Integration_CloseWaitsForInflight(t *testing.T) {
+	projectID, region, model := requireGCPCredentials(t)
+	ctx := context.Background()
+
+	tool := &slowTool{started: make(chan struct{}), release: make(chan struct{})}
+	cfg := agent.GeminiConfig{
+		ProjectID:        projectID,
+		Region:           region,
+		Model:            model,
+		CacheTTL:         5 * time.Minute,
+		CacheDisplayName: "test-cache-inflight",
+		SystemPrompt:     "You are a helpful assistant.",
+		Tools:            []agent.Tool{tool},
+		FunctionCallOnly: true,
+	}
+	logger := slog.New(slog.DiscardHandler)
+	a, err := agent.NewGeminiAgent(ctx, cfg, logger)
+	require.NoError(t, err)
+
+	history := []agent.Message{
+		&agent.UserMessage{Parts: []agent.UserPart{&agent.UserTextPart{Text: "hello"}}},
+	}
+
+	// Start a slow generation
+	genErr := make(chan error, 1)
+	go func() {
+		_, err := a.Generate(ctx, history)
+		genErr <- err
+	}()
+	<-tool.started
+
+	// Close concurrently; it must block while the generation is in flight
+	closeErr := make(chan error, 1)
+	go func() {
+		closeErr <- a.Close(ctx)
+	}()
+	select {
+	case <-closeErr:
+		t.Fatal("Close returned before in-flight generation finished")
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	// New generations are rejected while closing
+	_, err = a.Generate(ctx, history)
+	require.ErrorAs(t, err, new(*agent.ClosedError))
+
+	// Release the generation; it completes and Close returns
+	close(tool.release)
+	require.NoError(t, <-genErr)
+	require.NoError(t, <-closeErr)
+
+	// Post-close calls error
+	_, err = a.Generate(ctx, history)
+	assert.ErrorAs(t, err, new(*agent.ClosedError))
+}
+
+func TestGeminiAgent_Integration_CloseTimeout(t *testing.T) {
+	projectID, region, model := requireGCPCredentials(t)
+	ctx := context.Background()
+
+	tool := &slowTool{started: make(chan struct{}), release: make(chan struct{})}
+	cfg := agent.GeminiConfig{
+		ProjectID:        projectID,
+		Region:           region,
+		Model:            model,
+		CacheTTL:         5 * time.Minute,
+		CacheDisplayName: "test-cache-close-timeout",
+		SystemPrompt:     "You are a helpful assistant.",
+		Tools:            []agent.Tool{tool},
+		FunctionCallOnly: true,
+	}
+	logger := slog.New(slog.DiscardHandler)
+	a, err := agent.NewGeminiAgent(ctx, cfg, logger)
+	require.NoError(t, err)
+	defer close(tool.release)
+
+	history := []agent.Message{
+		&agent.UserMessage{Parts: []agent.UserPart{&agent.UserTextPart{Text: "hello"}}},
+	}
+	go func() { _, _ = a.Generate(ctx, history) }()
+	<-tool.started
+
+	closeCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	err = a.Close(closeCtx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestGeminiAgent_Integration_ToolHooks(t *testing.T) {
+	projectID, region, model := requireGCPCredentials(t)
+	ctx := context.Background()
+
+	tool := &slowTool{started: make(chan struct{}), release: make(chan struct{})}
+	close(tool.release)
+
+	var mu sync.Mutex
+	var calledName, resultName string
+	var calledArgs, gotResult map[string]any
+	var gotErr error
+	cfg := agent.GeminiConfig{
+		ProjectID:        projectID,
+		Region:           region,
+		Model:            model,
+		CacheTTL:         5 * time.Minute,
+		CacheDisplayName: "test-cache-hooks",
+		SystemPrompt:     "You are a helpful assistant.",
+		Tools:            []agent.Tool{tool},
+		FunctionCallOnly: true,
+		OnToolCall: func(name string, args map[string]any) {
+			mu.Lock()
+			defer mu.Unlock()
+			calledName, calledArgs = name, args
+		},
+		OnToolResult: func(name string, result map[string]any, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			resultName, gotResult, gotErr = name, result, err
+		},
+	}
+	logger := slog.New(slog.DiscardHandler)
+	a, err := agent.NewGeminiAgent(ctx, cfg, logger)
+	require.NoError(t, err)
+	defer a.Close(ctx)
+
+	history := []agent.Message{
+		&agent.UserMessage{Parts: []agent.UserPart{&agent.UserTextPart{Text: "hello"}}},
+	}
+	_, err = a.Generate(ctx, history)
+	require.NoError(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, "wait_for_release", calledName)
+	assert.Empty(t, calledArgs)
+	assert.Equal(t, "wait_for_release", resultName)
+	assert.Equal(t, map[string]any{"status": "done"}, gotResult)
+	assert.NoError(t, gotErr)
+}
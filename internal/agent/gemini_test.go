@@ -0,0 +1,1225 @@
+package agent_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	"yuruppu/internal/agent"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// Tools Disabled Tests
+// =============================================================================
+
+func TestGeminiAgent_Generate_ToolsDisabled(t *testing.T) {
+	t.Run("sends no tool declarations when tools are disabled", func(t *testing.T) {
+		transport := &fakeVertexTransport{}
+		a := newFakeAgent(t, transport)
+
+		ctx := agent.WithToolsDisabled(t.Context())
+		resp, err := a.Generate(ctx, userHistory("hello"))
+
+		require.NoError(t, err)
+		require.Len(t, resp.Parts, 1)
+		req := transport.lastGenerateRequest(t)
+		assert.NotContains(t, req, "tools")
+		assert.NotContains(t, req, "toolConfig")
+	})
+
+	t.Run("sends tool declarations by default", func(t *testing.T) {
+		transport := &fakeVertexTransport{}
+		a := newFakeAgent(t, transport)
+
+		_, err := a.Generate(t.Context(), userHistory("hello"))
+
+		require.NoError(t, err)
+		req := transport.lastGenerateRequest(t)
+		assert.Contains(t, req, "tools")
+		assert.Contains(t, req, "toolConfig")
+	})
+}
+
+func TestToolsDisabledFromContext(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, agent.ToolsDisabledFromContext(context.Background()))
+	assert.True(t, agent.ToolsDisabledFromContext(agent.WithToolsDisabled(context.Background())))
+}
+
+// =============================================================================
+// Payload Logging Tests
+// =============================================================================
+
+func TestGeminiAgent_Generate_LogPayloads(t *testing.T) {
+	t.Run("logs prompt and raw response at debug level when enabled", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		a := newFakeAgentWithLogger(t, &fakeVertexTransport{}, true, logger)
+
+		_, err := a.Generate(t.Context(), userHistory("my secret question"))
+
+		require.NoError(t, err)
+		request := findLogRecord(t, buf.String(), "LLM request (may contain PII)")
+		require.NotNil(t, request)
+		assert.Equal(t, "DEBUG", request["level"])
+		assert.Equal(t, "You are a test bot.", request["systemPrompt"])
+		contents, err := json.Marshal(request["contents"])
+		require.NoError(t, err)
+		assert.Contains(t, string(contents), "my secret question")
+		response := findLogRecord(t, buf.String(), "LLM response (may contain PII)")
+		require.NotNil(t, response)
+		assert.Equal(t, "DEBUG", response["level"])
+		raw, err := json.Marshal(response["response"])
+		require.NoError(t, err)
+		assert.Contains(t, string(raw), `"hi"`)
+	})
+
+	t.Run("logs no payloads at info level even when enabled", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+		a := newFakeAgentWithLogger(t, &fakeVertexTransport{}, true, logger)
+
+		_, err := a.Generate(t.Context(), userHistory("my secret question"))
+
+		require.NoError(t, err)
+		assert.NotContains(t, buf.String(), "my secret question")
+		assert.NotContains(t, buf.String(), "You are a test bot.")
+		assert.Contains(t, buf.String(), "may contain PII")
+	})
+
+	t.Run("logs no payloads by default", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		a := newFakeAgentWithLogger(t, &fakeVertexTransport{}, false, logger)
+
+		_, err := a.Generate(t.Context(), userHistory("my secret question"))
+
+		require.NoError(t, err)
+		assert.Nil(t, findLogRecord(t, buf.String(), "LLM request (may contain PII)"))
+		assert.Nil(t, findLogRecord(t, buf.String(), "LLM response (may contain PII)"))
+		assert.NotContains(t, buf.String(), "my secret question")
+	})
+}
+
+// =============================================================================
+// Tool Usage Logging Tests
+// =============================================================================
+
+func TestGeminiAgent_Generate_LogToolUsage(t *testing.T) {
+	t.Run("logs every offered tool and the called ones at debug level", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		transport := &fakeVertexTransport{firstCall: "echo"}
+		a := newFakeAgentWithTools(t, transport, logger, &echoTool{}, &namedTool{name: "weather"}, &namedTool{name: "list_events"})
+
+		_, err := a.Generate(t.Context(), userHistory("hello"))
+
+		require.NoError(t, err)
+		record := findLogRecord(t, buf.String(), "tool usage")
+		require.NotNil(t, record)
+		assert.Equal(t, "DEBUG", record["level"])
+		assert.ElementsMatch(t, []any{"echo", "weather", "list_events"}, record["offeredTools"])
+		assert.Equal(t, []any{"echo"}, record["calledTools"])
+	})
+
+	t.Run("logs no offered tools when tools are disabled", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		a := newFakeAgentWithLogger(t, &fakeVertexTransport{}, false, logger)
+
+		_, err := a.Generate(agent.WithToolsDisabled(t.Context()), userHistory("hello"))
+
+		require.NoError(t, err)
+		record := findLogRecord(t, buf.String(), "tool usage")
+		require.NotNil(t, record)
+		assert.Nil(t, record["offeredTools"])
+		assert.Empty(t, record["calledTools"])
+	})
+
+	t.Run("logs nothing at info level", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+		a := newFakeAgentWithLogger(t, &fakeVertexTransport{}, false, logger)
+
+		_, err := a.Generate(t.Context(), userHistory("hello"))
+
+		require.NoError(t, err)
+		assert.Nil(t, findLogRecord(t, buf.String(), "tool usage"))
+	})
+}
+
+// =============================================================================
+// Tool Call Budget Tests
+// =============================================================================
+
+func TestGeminiAgent_Generate_ToolCallBudget(t *testing.T) {
+	t.Run("stops calling tools once the budget is used up across iterations", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+		transport := &fakeVertexTransport{repeatCalls: []string{"count", "count", "count"}}
+		tool := &countingTool{}
+		a := newFakeAgentWithConfig(t, transport, logger, func(cfg *agent.GeminiConfig) {
+			cfg.Tools = []agent.Tool{tool}
+			cfg.MaxToolCallsPerTurn = 5
+		})
+
+		resp, err := a.Generate(t.Context(), userHistory("do everything"))
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(5), tool.calls.Load(), "should execute exactly the budget")
+		require.Len(t, transport.generateRequests, 3, "two tool rounds and one final answer")
+		final := transport.lastGenerateRequest(t)
+		assert.True(t, callsForbidden(final), "final request should forbid function calls")
+		contents, err := json.Marshal(final["contents"])
+		require.NoError(t, err)
+		assert.Contains(t, string(contents), "tool call budget exhausted")
+		assert.Contains(t, string(contents), "stopped early")
+		require.Len(t, resp.Parts, 1)
+		record := findLogRecord(t, buf.String(), "tool call budget exhausted, generating final answer")
+		require.NotNil(t, record)
+		assert.Equal(t, "WARN", record["level"])
+		assert.InDelta(t, 5, record["maxToolCallsPerTurn"], 0)
+	})
+
+	t.Run("answers through the answer tools once the budget is used up", func(t *testing.T) {
+		transport := &fakeVertexTransport{repeatCalls: []string{"count", "count", "count"}}
+		tool := &countingTool{}
+		a := newFakeAgentWithConfig(t, transport, slog.New(slog.DiscardHandler), func(cfg *agent.GeminiConfig) {
+			cfg.Tools = []agent.Tool{tool, &finalTool{}}
+			cfg.MaxToolCallsPerTurn = 3
+			cfg.AnswerTools = []string{"done"}
+		})
+
+		resp, err := a.Generate(t.Context(), userHistory("do everything"))
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), tool.calls.Load())
+		require.Len(t, transport.generateRequests, 2, "one tool round and one final answer")
+		final := transport.lastGenerateRequest(t)
+		assert.Equal(t, []any{"done"}, allowedFunctionNames(final))
+		contents, err := json.Marshal(final["contents"])
+		require.NoError(t, err)
+		assert.Contains(t, string(contents), "Only the tools for answering can still be called")
+		assert.True(t, resp.Final, "the answer tool should end the turn")
+	})
+
+	t.Run("rejects an answer tool that is not one of the tools", func(t *testing.T) {
+		_, err := agent.NewGeminiAgent(t.Context(), agent.GeminiConfig{
+			ProjectID:        "test-project",
+			Region:           "us-central1",
+			Model:            "test-model",
+			SystemPrompt:     "You are a test bot.",
+			CacheDisplayName: "test-cache",
+			CacheTTL:         time.Hour,
+			HTTPClient:       &http.Client{Transport: &fakeVertexTransport{}},
+			Tools:            []agent.Tool{&countingTool{}},
+			AnswerTools:      []string{"reply"},
+		}, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "answer tool reply is not one of the tools")
+	})
+
+	t.Run("executes every call when the budget is unlimited", func(t *testing.T) {
+		transport := &fakeVertexTransport{firstCall: "count"}
+		tool := &countingTool{}
+		a := newFakeAgentWithConfig(t, transport, slog.New(slog.DiscardHandler), func(cfg *agent.GeminiConfig) {
+			cfg.Tools = []agent.Tool{tool}
+		})
+
+		_, err := a.Generate(t.Context(), userHistory("hello"))
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), tool.calls.Load())
+		assert.False(t, callsForbidden(transport.lastGenerateRequest(t)))
+	})
+
+	t.Run("rejects a negative budget", func(t *testing.T) {
+		_, err := agent.NewGeminiAgent(t.Context(), agent.GeminiConfig{
+			ProjectID:           "test-project",
+			Region:              "us-central1",
+			Model:               "test-model",
+			SystemPrompt:        "You are a test bot.",
+			CacheDisplayName:    "test-cache",
+			CacheTTL:            time.Hour,
+			HTTPClient:          &http.Client{Transport: &fakeVertexTransport{}},
+			MaxToolCallsPerTurn: -1,
+		}, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "maxToolCallsPerTurn")
+	})
+}
+
+// =============================================================================
+// Needs Input Tests
+// =============================================================================
+
+func TestGeminiAgent_Generate_NeedsInput(t *testing.T) {
+	t.Run("relays the prompt to the model without logging an error", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		transport := &fakeVertexTransport{firstCall: "ask"}
+		a := newFakeAgentWithTools(t, transport, logger, &needsInputTool{
+			result: agent.NeedsInput("When does the event start?", "start_time"),
+		})
+
+		_, err := a.Generate(t.Context(), userHistory("make an event"))
+
+		require.NoError(t, err)
+		require.Len(t, transport.generateRequests, 2, "the model should get a turn to ask the user")
+		contents, err := json.Marshal(transport.lastGenerateRequest(t)["contents"])
+		require.NoError(t, err)
+		assert.Contains(t, string(contents), `"status":"needs_input"`)
+		assert.Contains(t, string(contents), "Ask the user: When does the event start?")
+		assert.NotContains(t, string(contents), "invalid response")
+
+		record := findLogRecord(t, buf.String(), "tool needs user input")
+		require.NotNil(t, record)
+		assert.Equal(t, "INFO", record["level"])
+		assert.Equal(t, "ask", record["tool"])
+		assert.Equal(t, []any{"start_time"}, record["missing"])
+		assert.NotContains(t, buf.String(), `"level":"ERROR"`)
+	})
+
+	t.Run("rejects a needs_input result without a prompt", func(t *testing.T) {
+		transport := &fakeVertexTransport{firstCall: "ask"}
+		a := newFakeAgentWithTools(t, transport, slog.New(slog.DiscardHandler), &needsInputTool{
+			result: map[string]any{"status": agent.StatusNeedsInput, "missing": []any{"start_time"}},
+		})
+
+		_, err := a.Generate(t.Context(), userHistory("make an event"))
+
+		require.NoError(t, err)
+		contents, err := json.Marshal(transport.lastGenerateRequest(t)["contents"])
+		require.NoError(t, err)
+		assert.Contains(t, string(contents), "invalid needs_input response")
+		assert.NotContains(t, string(contents), "Ask the user")
+	})
+}
+
+// =============================================================================
+// Invalid Arguments Tests
+// =============================================================================
+
+func TestGeminiAgent_Generate_Invalid(t *testing.T) {
+	t.Run("asks the model to fix the arguments without logging an error", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		transport := &fakeVertexTransport{firstCall: "ask"}
+		a := newFakeAgentWithTools(t, transport, logger, &needsInputTool{
+			result: agent.Invalid("capacity must not be negative", "end_time must be after start_time"),
+		})
+
+		_, err := a.Generate(t.Context(), userHistory("make an event"))
+
+		require.NoError(t, err)
+		require.Len(t, transport.generateRequests, 2, "the model should get a turn to retry")
+		contents, err := json.Marshal(transport.lastGenerateRequest(t)["contents"])
+		require.NoError(t, err)
+		assert.Contains(t, string(contents), `"status":"invalid"`)
+		assert.Contains(t, string(contents), "rejected the arguments: capacity must not be negative; end_time must be after start_time")
+		assert.NotContains(t, string(contents), "invalid response")
+
+		record := findLogRecord(t, buf.String(), "tool rejected invalid arguments")
+		require.NotNil(t, record)
+		assert.Equal(t, "INFO", record["level"])
+		assert.Equal(t, "ask", record["tool"])
+		assert.NotContains(t, buf.String(), `"level":"ERROR"`)
+	})
+
+	t.Run("rejects an invalid result without errors", func(t *testing.T) {
+		transport := &fakeVertexTransport{firstCall: "ask"}
+		a := newFakeAgentWithTools(t, transport, slog.New(slog.DiscardHandler), &needsInputTool{
+			result: agent.Invalid(),
+		})
+
+		_, err := a.Generate(t.Context(), userHistory("make an event"))
+
+		require.NoError(t, err)
+		contents, err := json.Marshal(transport.lastGenerateRequest(t)["contents"])
+		require.NoError(t, err)
+		assert.Contains(t, string(contents), "malformed invalid response")
+		assert.NotContains(t, string(contents), "rejected the arguments")
+	})
+}
+
+// =============================================================================
+// Tool Error Tests
+// =============================================================================
+
+func TestGeminiAgent_Generate_ToolErrors(t *testing.T) {
+	t.Run("relays a user error to the user", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+		transport := &fakeVertexTransport{firstCall: "fail"}
+		a := newFakeAgentWithTools(t, transport, logger, &failingTool{
+			errs: []error{agent.NewUserError("start_time must be in the future")},
+		})
+
+		_, err := a.Generate(t.Context(), userHistory("make an event yesterday"))
+
+		require.NoError(t, err)
+		contents, err := json.Marshal(transport.lastGenerateRequest(t)["contents"])
+		require.NoError(t, err)
+		assert.Contains(t, string(contents), `"error":"start_time must be in the future"`)
+		assert.Contains(t, string(contents), `"error_type":"user"`)
+		assert.Contains(t, string(contents), "Tell the user why so that they can correct it: start_time must be in the future")
+		assert.NotContains(t, buf.String(), `"level":"ERROR"`)
+	})
+
+	t.Run("logs a system error without passing it to the model", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+		transport := &fakeVertexTransport{firstCall: "fail"}
+		tool := &failingTool{
+			errs: []error{agent.NewSystemError("failed to create event", errors.New("gcs: 503 backend unavailable"))},
+		}
+		a := newFakeAgentWithTools(t, transport, logger, tool)
+
+		_, err := a.Generate(t.Context(), userHistory("make an event"))
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), tool.calls.Load(), "should not retry by default")
+		contents, err := json.Marshal(transport.lastGenerateRequest(t)["contents"])
+		require.NoError(t, err)
+		assert.Contains(t, string(contents), `"error_type":"system"`)
+		assert.Contains(t, string(contents), "try again later")
+		assert.NotContains(t, string(contents), "failed to create event")
+		assert.NotContains(t, string(contents), "gcs: 503")
+		assert.NotContains(t, string(contents), "Tell the user why")
+
+		record := findLogRecord(t, buf.String(), "tool failed with a system error")
+		require.NotNil(t, record)
+		assert.Equal(t, "ERROR", record["level"])
+		assert.Equal(t, "fail", record["tool"])
+		assert.Equal(t, "failed to create event", record["error"])
+		assert.Equal(t, "gcs: 503 backend unavailable", record["cause"])
+	})
+
+	t.Run("retries a system error when configured", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+		transport := &fakeVertexTransport{firstCall: "fail"}
+		tool := &failingTool{
+			errs: []error{agent.NewSystemError("failed to create event", nil), nil},
+		}
+		a := newFakeAgentWithConfig(t, transport, logger, func(cfg *agent.GeminiConfig) {
+			cfg.Tools = []agent.Tool{tool}
+			cfg.ToolRetries = 2
+		})
+
+		_, err := a.Generate(t.Context(), userHistory("make an event"))
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), tool.calls.Load(), "should stop retrying once the call succeeds")
+		contents, err := json.Marshal(transport.lastGenerateRequest(t)["contents"])
+		require.NoError(t, err)
+		assert.NotContains(t, string(contents), `"error"`)
+		record := findLogRecord(t, buf.String(), "tool failed with a system error, retrying")
+		require.NotNil(t, record)
+		assert.Equal(t, "WARN", record["level"])
+		assert.Nil(t, findLogRecord(t, buf.String(), "tool failed with a system error"))
+	})
+
+	t.Run("does not retry a user error", func(t *testing.T) {
+		transport := &fakeVertexTransport{firstCall: "fail"}
+		tool := &failingTool{
+			errs: []error{agent.NewUserError("only the event creator can update the event"), nil},
+		}
+		a := newFakeAgentWithConfig(t, transport, slog.New(slog.DiscardHandler), func(cfg *agent.GeminiConfig) {
+			cfg.Tools = []agent.Tool{tool}
+			cfg.ToolRetries = 2
+		})
+
+		_, err := a.Generate(t.Context(), userHistory("update the event"))
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), tool.calls.Load())
+	})
+
+	t.Run("passes an unclassified error as is", func(t *testing.T) {
+		transport := &fakeVertexTransport{firstCall: "fail"}
+		a := newFakeAgentWithTools(t, transport, slog.New(slog.DiscardHandler), &failingTool{
+			errs: []error{errors.New("something odd")},
+		})
+
+		_, err := a.Generate(t.Context(), userHistory("hello"))
+
+		require.NoError(t, err)
+		contents, err := json.Marshal(transport.lastGenerateRequest(t)["contents"])
+		require.NoError(t, err)
+		assert.Contains(t, string(contents), `"error":"something odd"`)
+		assert.NotContains(t, string(contents), "error_type")
+	})
+
+	t.Run("rejects negative retries", func(t *testing.T) {
+		_, err := agent.NewGeminiAgent(t.Context(), agent.GeminiConfig{
+			ProjectID:        "test-project",
+			Region:           "us-central1",
+			Model:            "test-model",
+			SystemPrompt:     "You are a test bot.",
+			CacheDisplayName: "test-cache",
+			CacheTTL:         time.Hour,
+			HTTPClient:       &http.Client{Transport: &fakeVertexTransport{}},
+			ToolRetries:      -1,
+		}, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "toolRetries")
+	})
+}
+
+// =============================================================================
+// Turn End Tests
+// =============================================================================
+
+func TestGeminiAgent_Generate_TurnEnd(t *testing.T) {
+	t.Run("reports a turn ended by a final tool", func(t *testing.T) {
+		transport := &fakeVertexTransport{firstCall: "done"}
+		a := newFakeAgentWithTools(t, transport, slog.New(slog.DiscardHandler), &finalTool{})
+
+		resp, err := a.Generate(t.Context(), userHistory("hello"))
+
+		require.NoError(t, err)
+		assert.True(t, resp.Final)
+		require.Len(t, transport.generateRequests, 1, "a final tool should end the loop")
+	})
+
+	t.Run("reports a text answer as not final", func(t *testing.T) {
+		transport := &fakeVertexTransport{}
+		a := newFakeAgent(t, transport)
+
+		resp, err := a.Generate(t.Context(), userHistory("hello"))
+
+		require.NoError(t, err)
+		assert.False(t, resp.Final)
+	})
+
+	t.Run("reports the finish reason of the last response", func(t *testing.T) {
+		transport := &fakeVertexTransport{
+			answer: `{"candidates": [{"content": {"role": "model", "parts": [{"text": " "}]}, "finishReason": "SAFETY"}]}`,
+		}
+		a := newFakeAgent(t, transport)
+
+		resp, err := a.Generate(t.Context(), userHistory("hello"))
+
+		require.NoError(t, err)
+		assert.False(t, resp.Final)
+		assert.Equal(t, "SAFETY", resp.FinishReason)
+	})
+
+	t.Run("reports a safety block with the blocked categories", func(t *testing.T) {
+		transport := &fakeVertexTransport{
+			answer: `{"candidates": [{"finishReason": "SAFETY", "safetyRatings": [
+				{"category": "HARM_CATEGORY_HARASSMENT", "probability": "HIGH", "blocked": true},
+				{"category": "HARM_CATEGORY_HATE_SPEECH", "probability": "LOW"},
+				{"category": "HARM_CATEGORY_DANGEROUS_CONTENT", "probability": "HIGH", "blocked": true}
+			]}]}`,
+		}
+		a := newFakeAgent(t, transport)
+
+		resp, err := a.Generate(t.Context(), userHistory("hello"))
+
+		require.NoError(t, err)
+		assert.True(t, resp.SafetyBlocked)
+		assert.Equal(t, []string{"HARM_CATEGORY_HARASSMENT", "HARM_CATEGORY_DANGEROUS_CONTENT"}, resp.BlockedCategories)
+	})
+
+	t.Run("does not report recitation as a safety block", func(t *testing.T) {
+		transport := &fakeVertexTransport{
+			answer: `{"candidates": [{"finishReason": "RECITATION"}]}`,
+		}
+		a := newFakeAgent(t, transport)
+
+		resp, err := a.Generate(t.Context(), userHistory("hello"))
+
+		require.NoError(t, err)
+		assert.Equal(t, "RECITATION", resp.FinishReason)
+		assert.False(t, resp.SafetyBlocked)
+		assert.Empty(t, resp.BlockedCategories)
+	})
+
+	t.Run("does not report a normal stop as a safety block", func(t *testing.T) {
+		transport := &fakeVertexTransport{
+			answer: `{"candidates": [{"content": {"role": "model", "parts": [{"text": "hi"}]}, "finishReason": "STOP"}]}`,
+		}
+		a := newFakeAgent(t, transport)
+
+		resp, err := a.Generate(t.Context(), userHistory("hello"))
+
+		require.NoError(t, err)
+		assert.Equal(t, "STOP", resp.FinishReason)
+		assert.False(t, resp.SafetyBlocked)
+	})
+
+	t.Run("reports the block reason of a blocked prompt", func(t *testing.T) {
+		transport := &fakeVertexTransport{
+			answer: `{"promptFeedback": {"blockReason": "PROHIBITED_CONTENT", "safetyRatings": [
+				{"category": "HARM_CATEGORY_SEXUALLY_EXPLICIT", "probability": "HIGH", "blocked": true}
+			]}}`,
+		}
+		a := newFakeAgent(t, transport)
+
+		resp, err := a.Generate(t.Context(), userHistory("hello"))
+
+		require.NoError(t, err)
+		assert.Empty(t, resp.Parts)
+		assert.Equal(t, "PROHIBITED_CONTENT", resp.FinishReason)
+		assert.True(t, resp.SafetyBlocked)
+		assert.Equal(t, []string{"HARM_CATEGORY_SEXUALLY_EXPLICIT"}, resp.BlockedCategories)
+	})
+}
+
+// =============================================================================
+// Circuit Breaker Tests
+// =============================================================================
+
+func TestGeminiAgent_Generate_CircuitBreaker(t *testing.T) {
+	newBreakerAgent := func(t *testing.T, transport *fakeVertexTransport, logger *slog.Logger, cooldown time.Duration) *agent.GeminiAgent {
+		t.Helper()
+		return newFakeAgentWithConfig(t, transport, logger, func(cfg *agent.GeminiConfig) {
+			cfg.Tools = nil
+			cfg.BreakerThreshold = 2
+			cfg.BreakerCooldown = cooldown
+		})
+	}
+	generateRequests := func(transport *fakeVertexTransport) int {
+		transport.mu.Lock()
+		defer transport.mu.Unlock()
+		return len(transport.generateRequests)
+	}
+
+	t.Run("fails fast without calling the backend once the threshold is reached", func(t *testing.T) {
+		transport := &fakeVertexTransport{}
+		transport.unavailable.Store(true)
+		var buf bytes.Buffer
+		a := newBreakerAgent(t, transport, slog.New(slog.NewJSONHandler(&buf, nil)), time.Hour)
+
+		for range 2 {
+			_, err := a.Generate(t.Context(), userHistory("hello"))
+			require.Error(t, err)
+			assert.NotErrorAs(t, err, new(*agent.UnavailableError))
+		}
+		require.Equal(t, 2, generateRequests(transport))
+
+		_, err := a.Generate(t.Context(), userHistory("hello"))
+
+		require.ErrorAs(t, err, new(*agent.UnavailableError))
+		assert.Equal(t, 2, generateRequests(transport), "open breaker should not call the backend")
+		record := findLogRecord(t, buf.String(), "circuit breaker opened, failing fast until cooldown ends")
+		require.NotNil(t, record)
+		assert.Equal(t, "WARN", record["level"])
+	})
+
+	t.Run("a success resets the consecutive failure count", func(t *testing.T) {
+		transport := &fakeVertexTransport{}
+		a := newBreakerAgent(t, transport, slog.New(slog.DiscardHandler), time.Hour)
+
+		transport.unavailable.Store(true)
+		_, err := a.Generate(t.Context(), userHistory("hello"))
+		require.Error(t, err)
+		transport.unavailable.Store(false)
+		_, err = a.Generate(t.Context(), userHistory("hello"))
+		require.NoError(t, err)
+		transport.unavailable.Store(true)
+		_, err = a.Generate(t.Context(), userHistory("hello"))
+		require.Error(t, err)
+
+		_, err = a.Generate(t.Context(), userHistory("hello"))
+
+		require.Error(t, err)
+		assert.NotErrorAs(t, err, new(*agent.UnavailableError), "failures were not consecutive")
+		assert.Equal(t, 4, generateRequests(transport))
+	})
+
+	t.Run("canceled requests do not count as failures", func(t *testing.T) {
+		transport := &fakeVertexTransport{}
+		a := newBreakerAgent(t, transport, slog.New(slog.DiscardHandler), time.Hour)
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		for range 3 {
+			_, err := a.Generate(ctx, userHistory("hello"))
+			require.Error(t, err)
+			assert.NotErrorAs(t, err, new(*agent.UnavailableError))
+		}
+	})
+
+	t.Run("canceled requests do not reset the failure count", func(t *testing.T) {
+		transport := &fakeVertexTransport{}
+		transport.unavailable.Store(true)
+		a := newBreakerAgent(t, transport, slog.New(slog.DiscardHandler), time.Hour)
+		canceled, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		_, err := a.Generate(t.Context(), userHistory("hello"))
+		require.Error(t, err)
+		_, err = a.Generate(canceled, userHistory("hello"))
+		require.ErrorIs(t, err, context.Canceled)
+		_, err = a.Generate(t.Context(), userHistory("hello"))
+		require.Error(t, err)
+
+		_, err = a.Generate(t.Context(), userHistory("hello"))
+
+		require.ErrorAs(t, err, new(*agent.UnavailableError), "failures around the cancellation are consecutive")
+	})
+
+	t.Run("a canceled half-open trial lets the next caller probe", func(t *testing.T) {
+		transport := &fakeVertexTransport{}
+		transport.unavailable.Store(true)
+		a := newBreakerAgent(t, transport, slog.New(slog.DiscardHandler), 50*time.Millisecond)
+		for range 2 {
+			_, err := a.Generate(t.Context(), userHistory("hello"))
+			require.Error(t, err)
+		}
+		time.Sleep(60 * time.Millisecond)
+		canceled, cancel := context.WithCancel(t.Context())
+		cancel()
+		_, err := a.Generate(canceled, userHistory("hello"))
+		require.ErrorIs(t, err, context.Canceled)
+
+		_, err = a.Generate(t.Context(), userHistory("hello"))
+		require.Error(t, err)
+		assert.NotErrorAs(t, err, new(*agent.UnavailableError), "the next caller should become the trial")
+
+		_, err = a.Generate(t.Context(), userHistory("hello"))
+
+		require.ErrorAs(t, err, new(*agent.UnavailableError), "the failed trial should reopen the breaker")
+		assert.Equal(t, 3, generateRequests(transport))
+	})
+
+	t.Run("half-open trial closes the breaker on success", func(t *testing.T) {
+		transport := &fakeVertexTransport{}
+		transport.unavailable.Store(true)
+		a := newBreakerAgent(t, transport, slog.New(slog.DiscardHandler), 50*time.Millisecond)
+		for range 2 {
+			_, err := a.Generate(t.Context(), userHistory("hello"))
+			require.Error(t, err)
+		}
+		_, err := a.Generate(t.Context(), userHistory("hello"))
+		require.ErrorAs(t, err, new(*agent.UnavailableError))
+
+		transport.unavailable.Store(false)
+		time.Sleep(60 * time.Millisecond)
+		_, err = a.Generate(t.Context(), userHistory("hello"))
+		require.NoError(t, err)
+
+		_, err = a.Generate(t.Context(), userHistory("hello"))
+
+		require.NoError(t, err)
+		assert.Equal(t, 4, generateRequests(transport))
+	})
+
+	t.Run("half-open trial reopens the breaker on failure", func(t *testing.T) {
+		transport := &fakeVertexTransport{}
+		transport.unavailable.Store(true)
+		a := newBreakerAgent(t, transport, slog.New(slog.DiscardHandler), 50*time.Millisecond)
+		for range 2 {
+			_, err := a.Generate(t.Context(), userHistory("hello"))
+			require.Error(t, err)
+		}
+
+		time.Sleep(60 * time.Millisecond)
+		_, err := a.Generate(t.Context(), userHistory("hello"))
+		require.Error(t, err)
+		assert.NotErrorAs(t, err, new(*agent.UnavailableError), "trial should reach the backend")
+
+		_, err = a.Generate(t.Context(), userHistory("hello"))
+
+		require.ErrorAs(t, err, new(*agent.UnavailableError))
+		assert.Equal(t, 3, generateRequests(transport))
+	})
+
+	t.Run("rejects invalid settings", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			threshold int
+			cooldown  time.Duration
+			wantErr   string
+		}{
+			{name: "negative threshold", threshold: -1, cooldown: time.Second, wantErr: "breakerThreshold"},
+			{name: "enabled without cooldown", threshold: 3, cooldown: 0, wantErr: "breakerCooldown"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := agent.NewGeminiAgent(t.Context(), agent.GeminiConfig{
+					ProjectID:        "test-project",
+					Region:           "us-central1",
+					Model:            "test-model",
+					SystemPrompt:     "You are a test bot.",
+					CacheDisplayName: "test-cache",
+					CacheTTL:         time.Hour,
+					HTTPClient:       &http.Client{Transport: &fakeVertexTransport{}},
+					BreakerThreshold: tt.threshold,
+					BreakerCooldown:  tt.cooldown,
+				}, slog.New(slog.DiscardHandler))
+
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			})
+		}
+	})
+}
+
+func TestNewGeminiAgent_SystemPromptLength(t *testing.T) {
+	newAgent := func(t *testing.T, prompt string, maxLength int) (*agent.GeminiAgent, error) {
+		t.Helper()
+		a, err := agent.NewGeminiAgent(t.Context(), agent.GeminiConfig{
+			ProjectID:             "test-project",
+			Region:                "us-central1",
+			Model:                 "test-model",
+			SystemPrompt:          prompt,
+			CacheDisplayName:      "test-cache",
+			CacheTTL:              time.Hour,
+			HTTPClient:            &http.Client{Transport: &fakeVertexTransport{}},
+			MaxSystemPromptLength: maxLength,
+		}, slog.New(slog.DiscardHandler))
+		if a != nil {
+			t.Cleanup(func() { _ = a.Close(context.Background()) })
+		}
+		return a, err
+	}
+
+	t.Run("accepts a prompt at the limit counting characters", func(t *testing.T) {
+		a, err := newAgent(t, strings.Repeat("あ", 100), 100)
+
+		require.NoError(t, err)
+		assert.NotNil(t, a)
+	})
+
+	t.Run("rejects a prompt over the limit", func(t *testing.T) {
+		a, err := newAgent(t, strings.Repeat("あ", 101), 100)
+
+		require.Error(t, err)
+		assert.Nil(t, a)
+		assert.Contains(t, err.Error(), "systemPrompt is too long: 101 characters, the maximum is 100")
+	})
+
+	t.Run("applies the default limit when unset", func(t *testing.T) {
+		_, err := newAgent(t, "You are a test bot.", 0)
+		require.NoError(t, err)
+
+		_, err = newAgent(t, strings.Repeat("a", agent.DefaultMaxSystemPromptLength+1), 0)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "systemPrompt is too long")
+	})
+
+	t.Run("rejects a negative limit", func(t *testing.T) {
+		_, err := newAgent(t, "You are a test bot.", -1)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "maxSystemPromptLength cannot be negative")
+	})
+}
+
+// =============================================================================
+// Labels Tests
+// =============================================================================
+
+func TestNewGeminiAgent_Labels(t *testing.T) {
+	newAgent := func(t *testing.T, labels map[string]string) (*agent.GeminiAgent, error) {
+		t.Helper()
+		a, err := agent.NewGeminiAgent(t.Context(), agent.GeminiConfig{
+			ProjectID:        "test-project",
+			Region:           "us-central1",
+			Model:            "test-model",
+			SystemPrompt:     "You are a test bot.",
+			CacheDisplayName: "test-cache",
+			CacheTTL:         time.Hour,
+			HTTPClient:       &http.Client{Transport: &fakeVertexTransport{}},
+			Labels:           labels,
+		}, slog.New(slog.DiscardHandler))
+		if a != nil {
+			t.Cleanup(func() { _ = a.Close(context.Background()) })
+		}
+		return a, err
+	}
+
+	t.Run("accepts valid labels", func(t *testing.T) {
+		a, err := newAgent(t, map[string]string{
+			"environment": "prod",
+			"deployment":  "blue-2",
+			"team_name":   "",
+			"ラベル":         "値",
+		})
+
+		require.NoError(t, err)
+		assert.NotNil(t, a)
+	})
+
+	tests := []struct {
+		name    string
+		labels  map[string]string
+		wantErr string
+	}{
+		{name: "empty key", labels: map[string]string{"": "prod"}, wantErr: `invalid label key ""`},
+		{name: "uppercase key", labels: map[string]string{"Environment": "prod"}, wantErr: `invalid label key "Environment"`},
+		{name: "key starting with a digit", labels: map[string]string{"1env": "prod"}, wantErr: `invalid label key "1env"`},
+		{name: "key too long", labels: map[string]string{"a" + strings.Repeat("b", 63): "prod"}, wantErr: "invalid label key"},
+		{name: "key with a dot", labels: map[string]string{"env.name": "prod"}, wantErr: `invalid label key "env.name"`},
+		{name: "uppercase value", labels: map[string]string{"environment": "Prod"}, wantErr: `invalid value for label "environment"`},
+		{name: "value too long", labels: map[string]string{"environment": strings.Repeat("a", 64)}, wantErr: `invalid value for label "environment"`},
+	}
+	for _, tt := range tests {
+		t.Run("rejects "+tt.name, func(t *testing.T) {
+			a, err := newAgent(t, tt.labels)
+
+			require.Error(t, err)
+			assert.Nil(t, a)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	t.Run("rejects more than 64 labels", func(t *testing.T) {
+		labels := make(map[string]string, 65)
+		for i := range 65 {
+			labels[fmt.Sprintf("key%d", i)] = "v"
+		}
+
+		_, err := newAgent(t, labels)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "too many labels: 65, the maximum is 64")
+	})
+}
+
+func TestGeminiAgent_Generate_Labels(t *testing.T) {
+	t.Run("attaches labels to every generation request", func(t *testing.T) {
+		transport := &fakeVertexTransport{firstCall: "echo"}
+		a := newFakeAgentWithConfig(t, transport, slog.New(slog.DiscardHandler), func(cfg *agent.GeminiConfig) {
+			cfg.Labels = map[string]string{"environment": "prod", "deployment": "blue"}
+		})
+
+		_, err := a.Generate(t.Context(), userHistory("hello"))
+
+		require.NoError(t, err)
+		require.Len(t, transport.generateRequests, 2)
+		for _, req := range transport.generateRequests {
+			assert.Equal(t, map[string]any{"environment": "prod", "deployment": "blue"}, req["labels"])
+		}
+	})
+
+	t.Run("attaches labels when tools are disabled", func(t *testing.T) {
+		transport := &fakeVertexTransport{}
+		a := newFakeAgentWithConfig(t, transport, slog.New(slog.DiscardHandler), func(cfg *agent.GeminiConfig) {
+			cfg.Labels = map[string]string{"environment": "prod"}
+		})
+
+		_, err := a.Generate(agent.WithToolsDisabled(t.Context()), userHistory("hello"))
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"environment": "prod"}, transport.lastGenerateRequest(t)["labels"])
+	})
+
+	t.Run("sends no labels when unset", func(t *testing.T) {
+		transport := &fakeVertexTransport{}
+		a := newFakeAgent(t, transport)
+
+		_, err := a.Generate(t.Context(), userHistory("hello"))
+
+		require.NoError(t, err)
+		assert.NotContains(t, transport.lastGenerateRequest(t), "labels")
+	})
+}
+
+// =============================================================================
+// Helpers
+// =============================================================================
+
+func newFakeAgent(t *testing.T, transport http.RoundTripper) *agent.GeminiAgent {
+	t.Helper()
+	return newFakeAgentWithLogger(t, transport, false, slog.New(slog.DiscardHandler))
+}
+
+func newFakeAgentWithLogger(t *testing.T, transport http.RoundTripper, logPayloads bool, logger *slog.Logger) *agent.GeminiAgent {
+	t.Helper()
+	return newFakeAgentWithConfig(t, transport, logger, func(cfg *agent.GeminiConfig) {
+		cfg.LogPayloads = logPayloads
+	})
+}
+
+func newFakeAgentWithTools(t *testing.T, transport http.RoundTripper, logger *slog.Logger, tools ...agent.Tool) *agent.GeminiAgent {
+	t.Helper()
+	return newFakeAgentWithConfig(t, transport, logger, func(cfg *agent.GeminiConfig) {
+		cfg.Tools = tools
+	})
+}
+
+// newFakeAgentWithConfig creates an agent backed by transport; configure adjusts the default config.
+func newFakeAgentWithConfig(t *testing.T, transport http.RoundTripper, logger *slog.Logger, configure func(cfg *agent.GeminiConfig)) *agent.GeminiAgent {
+	t.Helper()
+	cfg := agent.GeminiConfig{
+		ProjectID:        "test-project",
+		Region:           "us-central1",
+		Model:            "test-model",
+		SystemPrompt:     "You are a test bot.",
+		Tools:            []agent.Tool{&echoTool{}},
+		FunctionCallOnly: true,
+		CacheDisplayName: "test-cache",
+		CacheTTL:         time.Hour,
+		HTTPClient:       &http.Client{Transport: transport},
+	}
+	configure(&cfg)
+	a, err := agent.NewGeminiAgent(t.Context(), cfg, logger)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = a.Close(context.Background()) })
+	return a
+}
+
+// findLogRecord returns the first JSON log record with the given message, or nil.
+func findLogRecord(t *testing.T, logs, msg string) map[string]any {
+	t.Helper()
+	for line := range strings.SplitSeq(strings.TrimSpace(logs), "\n") {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		if record["msg"] == msg {
+			return record
+		}
+	}
+	return nil
+}
+
+func userHistory(text string) []agent.Message {
+	return []agent.Message{
+		&agent.UserMessage{Parts: []agent.UserPart{&agent.UserTextPart{Text: text}}},
+	}
+}
+
+// fakeVertexTransport answers Vertex AI requests locally and records generateContent bodies.
+// countTokens reports a small prompt so that context caching is skipped.
+// If firstCall is set, the first generateContent response calls that tool.
+// If repeatCalls is set, every response calls those tools in parallel unless the request forbids function calls;
+// a request that allows only some functions gets a call to the first of them.
+// While unavailable is set, generateContent is recorded and answered with 503.
+// If answer is set, it replaces the default text answer body.
+type fakeVertexTransport struct {
+	answer           string
+	firstCall        string
+	repeatCalls      []string
+	unavailable      atomic.Bool
+	mu               sync.Mutex
+	generateRequests []map[string]any
+}
+
+func (f *fakeVertexTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body string
+	switch {
+	case strings.HasSuffix(req.URL.Path, ":countTokens"):
+		body = `{"totalTokens": 10}`
+	case strings.HasSuffix(req.URL.Path, ":generateContent"):
+		if err := req.Context().Err(); err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		var decoded map[string]any
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			return nil, err
+		}
+		f.mu.Lock()
+		f.generateRequests = append(f.generateRequests, decoded)
+		first := len(f.generateRequests) == 1
+		f.mu.Unlock()
+		if f.unavailable.Load() {
+			return &http.Response{
+				StatusCode: http.StatusServiceUnavailable,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       io.NopCloser(strings.NewReader(`{"error": {"code": 503, "message": "unavailable", "status": "UNAVAILABLE"}}`)),
+				Request:    req,
+			}, nil
+		}
+		body = `{"candidates": [{"content": {"role": "model", "parts": [{"text": "hi"}]}}]}`
+		if f.answer != "" {
+			body = f.answer
+		}
+		switch {
+		case first && f.firstCall != "":
+			body = functionCallResponse(f.firstCall)
+		case len(f.repeatCalls) > 0 && len(allowedFunctionNames(decoded)) > 0:
+			name, _ := allowedFunctionNames(decoded)[0].(string)
+			body = functionCallResponse(name)
+		case len(f.repeatCalls) > 0 && !callsForbidden(decoded):
+			body = functionCallResponse(f.repeatCalls...)
+		}
+	default:
+		return &http.Response{
+			StatusCode: http.StatusNotFound,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{}`)),
+			Request:    req,
+		}, nil
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+		Request:    req,
+	}, nil
+}
+
+// functionCallResponse builds a generateContent response body that calls the named tools.
+func functionCallResponse(names ...string) string {
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = `{"functionCall": {"name": "` + name + `", "args": {}}}`
+	}
+	return `{"candidates": [{"content": {"role": "model", "parts": [` + strings.Join(parts, ", ") + `]}}]}`
+}
+
+// callsForbidden reports whether a generateContent request sets the function calling mode to NONE.
+func callsForbidden(req map[string]any) bool {
+	toolConfig, _ := req["toolConfig"].(map[string]any)
+	callingConfig, _ := toolConfig["functionCallingConfig"].(map[string]any)
+	return callingConfig["mode"] == "NONE"
+}
+
+// allowedFunctionNames returns the function names a generateContent request restricts calls to.
+func allowedFunctionNames(req map[string]any) []any {
+	toolConfig, _ := req["toolConfig"].(map[string]any)
+	callingConfig, _ := toolConfig["functionCallingConfig"].(map[string]any)
+	names, _ := callingConfig["allowedFunctionNames"].([]any)
+	return names
+}
+
+func (f *fakeVertexTransport) lastGenerateRequest(t *testing.T) map[string]any {
+	t.Helper()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	require.NotEmpty(t, f.generateRequests)
+	return f.generateRequests[len(f.generateRequests)-1]
+}
+
+type echoTool struct{}
+
+func (e *echoTool) Name() string        { return "echo" }
+func (e *echoTool) Description() string { return "Echoes the input." }
+func (e *echoTool) ParametersJsonSchema() []byte {
+	return []byte(`{"type": "object", "properties": {"text": {"type": "string"}}}`)
+}
+
+func (e *echoTool) ResponseJsonSchema() []byte {
+	return []byte(`{"type": "object", "properties": {"text": {"type": "string"}}}`)
+}
+
+func (e *echoTool) Callback(_ context.Context, args map[string]any) (map[string]any, error) {
+	return args, nil
+}
+
+// needsInputTool returns a fixed result; its response schema only allows {"ok": true}.
+type needsInputTool struct {
+	result map[string]any
+}
+
+func (n *needsInputTool) Name() string        { return "ask" }
+func (n *needsInputTool) Description() string { return "Needs more input." }
+func (n *needsInputTool) ParametersJsonSchema() []byte {
+	return []byte(`{"type": "object"}`)
+}
+
+func (n *needsInputTool) ResponseJsonSchema() []byte {
+	return []byte(`{"type": "object", "properties": {"ok": {"type": "boolean"}}, "required": ["ok"], "additionalProperties": false}`)
+}
+
+func (n *needsInputTool) Callback(_ context.Context, _ map[string]any) (map[string]any, error) {
+	return n.result, nil
+}
+
+// failingTool returns errs in order, one per call, and succeeds once they run out or on a nil entry.
+type failingTool struct {
+	errs  []error
+	calls atomic.Int32
+}
+
+func (f *failingTool) Name() string        { return "fail" }
+func (f *failingTool) Description() string { return "Fails." }
+func (f *failingTool) ParametersJsonSchema() []byte {
+	return []byte(`{"type": "object"}`)
+}
+
+func (f *failingTool) ResponseJsonSchema() []byte {
+	return []byte(`{"type": "object"}`)
+}
+
+func (f *failingTool) Callback(_ context.Context, _ map[string]any) (map[string]any, error) {
+	i := int(f.calls.Add(1)) - 1
+	if i < len(f.errs) && f.errs[i] != nil {
+		return nil, f.errs[i]
+	}
+	return map[string]any{}, nil
+}
+
+// finalTool ends the tool loop like reply does.
+type finalTool struct{}
+
+func (f *finalTool) Name() string        { return "done" }
+func (f *finalTool) Description() string { return "Ends the turn." }
+func (f *finalTool) ParametersJsonSchema() []byte {
+	return []byte(`{"type": "object"}`)
+}
+
+func (f *finalTool) ResponseJsonSchema() []byte {
+	return []byte(`{"type": "object"}`)
+}
+
+func (f *finalTool) Callback(_ context.Context, _ map[string]any) (map[string]any, error) {
+	return map[string]any{}, nil
+}
+
+func (f *finalTool) IsFinal(_ map[string]any) bool {
+	return true
+}
+
+// namedTool is a no-op tool with a configurable name.
+type namedTool struct {
+	name string
+}
+
+func (n *namedTool) Name() string        { return n.name }
+func (n *namedTool) Description() string { return "Does nothing." }
+func (n *namedTool) ParametersJsonSchema() []byte {
+	return []byte(`{"type": "object"}`)
+}
+
+func (n *namedTool) ResponseJsonSchema() []byte {
+	return []byte(`{"type": "object"}`)
+}
+
+func (n *namedTool) Callback(_ context.Context, _ map[string]any) (map[string]any, error) {
+	return map[string]any{}, nil
+}
+
+// countingTool counts how many times it is called.
+type countingTool struct {
+	calls atomic.Int32
+}
+
+func (c *countingTool) Name() string        { return "count" }
+func (c *countingTool) Description() string { return "Counts calls." }
+func (c *countingTool) ParametersJsonSchema() []byte {
+	return []byte(`{"type": "object"}`)
+}
+
+func (c *countingTool) ResponseJsonSchema() []byte {
+	return []byte(`{"type": "object"}`)
+}
+
+func (c *countingTool) Callback(_ context.Context, _ map[string]any) (map[string]any, error) {
+	c.calls.Add(1)
+	return map[string]any{}, nil
+}
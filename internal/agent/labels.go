@@ -0,0 +1,35 @@
+package agent
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// maxLabels is the number of labels GCP allows on a resource.
+const maxLabels = 64
+
+var (
+	// labelKeyPattern matches GCP label keys: 1-63 lowercase letters, digits, underscores,
+	// or dashes, starting with a lowercase letter. International lowercase letters are allowed.
+	labelKeyPattern = regexp.MustCompile(`^[\p{Ll}\p{Lo}][\p{Ll}\p{Lo}\p{N}_-]{0,62}$`)
+	// labelValuePattern matches GCP label values, which follow the key rules but may be empty
+	// or start with any allowed character.
+	labelValuePattern = regexp.MustCompile(`^[\p{Ll}\p{Lo}\p{N}_-]{0,63}$`)
+)
+
+// ValidateLabels returns an error naming the first label that GCP would reject.
+// nil or empty labels are valid.
+func ValidateLabels(labels map[string]string) error {
+	if len(labels) > maxLabels {
+		return fmt.Errorf("too many labels: %d, the maximum is %d", len(labels), maxLabels)
+	}
+	for key, value := range labels {
+		if !labelKeyPattern.MatchString(key) {
+			return fmt.Errorf("invalid label key %q: must be 1-63 lowercase letters, digits, underscores, or dashes, starting with a lowercase letter", key)
+		}
+		if !labelValuePattern.MatchString(value) {
+			return fmt.Errorf("invalid value for label %q: must be at most 63 lowercase letters, digits, underscores, or dashes", key)
+		}
+	}
+	return nil
+}
@@ -81,6 +81,18 @@ func (*UserMessage) message() {}
 // AssistantMessage represents a message from an assistant.
 type AssistantMessage struct {
 	Parts []AssistantPart
+	// Final reports whether a final tool, such as reply, ended the turn.
+	// Only set on messages returned by Generate.
+	Final bool
+	// FinishReason is why the model stopped generating its last response, e.g. "STOP" or "SAFETY".
+	// Empty if the backend did not report one. Only set on messages returned by Generate.
+	FinishReason string
+	// SafetyBlocked reports whether a safety filter blocked the response or the prompt,
+	// as opposed to the model stopping for another reason. Only set on messages returned by Generate.
+	SafetyBlocked bool
+	// BlockedCategories lists the harm categories that caused a safety block, e.g. "HARM_CATEGORY_HARASSMENT".
+	// Only set on messages returned by Generate.
+	BlockedCategories []string
 }
 
 func (*AssistantMessage) message() {}
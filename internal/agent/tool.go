@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
 
 	"github.com/santhosh-tekuri/jsonschema/v6"
 )
@@ -24,9 +25,136 @@ type Tool interface {
 	ResponseJsonSchema() []byte
 
 	// Callback is invoked by the LLM with validated arguments.
+	// When the tool cannot proceed until the user supplies more information, it returns
+	// NeedsInput instead of an error. Such results are validated against the needs_input
+	// convention rather than ResponseJsonSchema, and the agent asks the model to relay the prompt.
+	// Likewise, arguments that break a constraint the model can fix on its own are reported with
+	// Invalid, so that the model corrects them and calls the tool again in the same turn.
+	// Errors should be classified with NewUserError or NewSystemError; unclassified errors are
+	// passed to the model as is.
 	Callback(ctx context.Context, validatedArgs map[string]any) (map[string]any, error)
 }
 
+// StatusNeedsInput is the status of a tool result that asks the user for more information.
+const StatusNeedsInput = "needs_input"
+
+// needsInputSchema is the JSON Schema every needs_input result must satisfy.
+const needsInputSchema = `{
+  "type": "object",
+  "properties": {
+    "status": {"const": "needs_input"},
+    "missing": {"type": "array", "items": {"type": "string"}, "minItems": 1},
+    "prompt": {"type": "string", "minLength": 1}
+  },
+  "required": ["status", "missing", "prompt"],
+  "additionalProperties": false
+}`
+
+// needsInputValidator compiles needsInputSchema once.
+var needsInputValidator = sync.OnceValues(func() (Validator, error) {
+	return compileSchema([]byte(needsInputSchema))
+})
+
+// NeedsInput returns the result a tool gives when it cannot proceed until the user supplies more information.
+// prompt is the question to relay to the user and missing names the parameters that are absent.
+func NeedsInput(prompt string, missing ...string) map[string]any {
+	names := make([]any, len(missing))
+	for i, name := range missing {
+		names[i] = name
+	}
+	return map[string]any{
+		"status":  StatusNeedsInput,
+		"missing": names,
+		"prompt":  prompt,
+	}
+}
+
+// isNeedsInput reports whether result claims the needs_input status.
+func isNeedsInput(result map[string]any) bool {
+	status, ok := result["status"].(string)
+	return ok && status == StatusNeedsInput
+}
+
+// StatusInvalid is the status of a tool result that rejects the arguments the model passed.
+const StatusInvalid = "invalid"
+
+// invalidSchema is the JSON Schema every invalid result must satisfy.
+const invalidSchema = `{
+  "type": "object",
+  "properties": {
+    "status": {"const": "invalid"},
+    "errors": {"type": "array", "items": {"type": "string", "minLength": 1}, "minItems": 1}
+  },
+  "required": ["status", "errors"],
+  "additionalProperties": false
+}`
+
+// invalidValidator compiles invalidSchema once.
+var invalidValidator = sync.OnceValues(func() (Validator, error) {
+	return compileSchema([]byte(invalidSchema))
+})
+
+// Invalid returns the result a tool gives when the arguments break a constraint the model can fix,
+// such as a negative capacity or an end time before the start time.
+// errs describe each violated constraint.
+func Invalid(errs ...string) map[string]any {
+	messages := make([]any, len(errs))
+	for i, msg := range errs {
+		messages[i] = msg
+	}
+	return map[string]any{
+		"status": StatusInvalid,
+		"errors": messages,
+	}
+}
+
+// isInvalid reports whether result claims the invalid status.
+func isInvalid(result map[string]any) bool {
+	status, ok := result["status"].(string)
+	return ok && status == StatusInvalid
+}
+
+// UserError is a tool error caused by the request itself, such as a missing permission or an event that does not exist.
+// The agent asks the model to relay its message to the user so that they can correct the request.
+type UserError struct {
+	msg string
+}
+
+// NewUserError returns a UserError with the given message, which is shown to the user.
+func NewUserError(msg string) error {
+	return &UserError{msg: msg}
+}
+
+// UserErrorf returns a UserError with a message formatted as in fmt.Sprintf.
+func UserErrorf(format string, args ...any) error {
+	return &UserError{msg: fmt.Sprintf(format, args...)}
+}
+
+func (e *UserError) Error() string {
+	return e.msg
+}
+
+// SystemError is a tool error caused by something outside the user's control, such as storage being unavailable.
+// The agent logs it, retries the call if configured to, and gives the model only a generic message.
+type SystemError struct {
+	msg string
+	err error
+}
+
+// NewSystemError returns a SystemError describing the failed operation.
+// err is the underlying cause; it is logged by the agent but left out of the message. It may be nil.
+func NewSystemError(msg string, err error) error {
+	return &SystemError{msg: msg, err: err}
+}
+
+func (e *SystemError) Error() string {
+	return e.msg
+}
+
+func (e *SystemError) Unwrap() error {
+	return e.err
+}
+
 // FinalAction is an optional interface for tools that can end the tool loop.
 // If a tool implements this interface, IsFinal is called after successful execution.
 type FinalAction interface {
@@ -81,6 +209,28 @@ func (t *tool) Use(ctx context.Context, args map[string]any) (UseResult, error)
 		return UseResult{}, err
 	}
 
+	if isNeedsInput(result) {
+		validator, err := needsInputValidator()
+		if err != nil {
+			return UseResult{}, fmt.Errorf("invalid needs_input schema: %w", err)
+		}
+		if err := validator.Validate(result); err != nil {
+			return UseResult{}, fmt.Errorf("invalid needs_input response: %w", err)
+		}
+		return UseResult{Response: result}, nil
+	}
+
+	if isInvalid(result) {
+		validator, err := invalidValidator()
+		if err != nil {
+			return UseResult{}, fmt.Errorf("malformed invalid schema: %w", err)
+		}
+		if err := validator.Validate(result); err != nil {
+			return UseResult{}, fmt.Errorf("malformed invalid response: %w", err)
+		}
+		return UseResult{Response: result}, nil
+	}
+
 	if err := t.responseValidator.Validate(result); err != nil {
 		return UseResult{}, fmt.Errorf("invalid response: %w", err)
 	}
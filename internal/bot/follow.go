@@ -24,6 +24,8 @@ func (h *Handler) HandleFollow(ctx context.Context) error {
 		DisplayName:   lineProfile.DisplayName,
 		PictureURL:    lineProfile.PictureURL,
 		StatusMessage: lineProfile.StatusMessage,
+		Language:      lineProfile.Language,
+		Timezone:      lineProfile.Timezone,
 	}
 
 	if p.PictureURL != "" {
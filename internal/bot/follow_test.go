@@ -24,6 +24,8 @@ func TestHandler_HandleFollow(t *testing.T) {
 				DisplayName:   "Alice",
 				PictureURL:    "",
 				StatusMessage: "Hello!",
+				Language:      "en",
+				Timezone:      "Europe/London",
 			},
 		}
 		mockPS := &mockProfileService{}
@@ -41,6 +43,8 @@ func TestHandler_HandleFollow(t *testing.T) {
 		require.NotNil(t, mockPS.profile)
 		assert.Equal(t, "Alice", mockPS.profile.DisplayName)
 		assert.Equal(t, "Hello!", mockPS.profile.StatusMessage)
+		assert.Equal(t, "en", mockPS.profile.Language)
+		assert.Equal(t, "Europe/London", mockPS.profile.Timezone)
 	})
 
 	t.Run("returns error when userID not in context", func(t *testing.T) {
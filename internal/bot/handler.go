@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"strings"
 	"time"
 	"yuruppu/internal/agent"
 	"yuruppu/internal/groupprofile"
@@ -24,14 +25,31 @@ type LineClient interface {
 	GetGroupSummary(ctx context.Context, groupID string) (*lineclient.GroupSummary, error)
 	GetGroupMemberCount(ctx context.Context, groupID string) (int, error)
 	ShowLoadingAnimation(ctx context.Context, chatID string, timeout time.Duration) error
+	Send(ctx context.Context, text string) error
 }
 
 // HandlerConfig holds handler configuration.
 type HandlerConfig struct {
-	TypingIndicatorDelay   time.Duration // time to wait before showing indicator (default 3s)
-	TypingIndicatorTimeout time.Duration // indicator display duration (5-60s)
+	TypingIndicatorDelay   time.Duration    // time to wait before showing indicator (default 3s)
+	TypingIndicatorTimeout time.Duration    // indicator display duration (5-60s)
+	UnsupportedReply       string           // reply for message types the agent cannot read (empty = pass to agent as before)
+	PostbackNonceTTL       time.Duration    // how long consumed postback nonces are remembered (default 24h)
+	EmptyResponseReply     string           // reply when the agent ends a turn with no output (default DefaultEmptyResponseReply)
+	SafetyBlockedReply     string           // reply when a safety filter blocked the agent's output (default DefaultSafetyBlockedReply)
+	Now                    func() time.Time // clock that fixes each turn's time (default time.Now); the CLI injects a simulated one
+	SkipInactiveGroups     bool             // ignore group messages while the group profile says the bot has left, as the CLI does for groups the bot is not in
+	MaxHistoryTurns        int              // most recent turns of history sent to the agent, each starting at a user message (0 = all); stored history is kept in full
 }
 
+const (
+	// DefaultEmptyResponseReply is sent when the agent ends a turn without replying or producing any text,
+	// so that the user is not left without an answer.
+	DefaultEmptyResponseReply = "ごめん、うまく答えられなかった"
+	// DefaultSafetyBlockedReply is sent instead of DefaultEmptyResponseReply when the output was empty
+	// because a safety filter blocked it.
+	DefaultSafetyBlockedReply = "ごめんね、その話にはうまく答えられないんだ"
+)
+
 // UserProfileService provides access to user profiles.
 type UserProfileService interface {
 	GetUserProfile(ctx context.Context, userID string) (*userprofile.UserProfile, error)
@@ -42,6 +60,7 @@ type UserProfileService interface {
 type HistoryService interface {
 	GetHistory(ctx context.Context, sourceID string) ([]history.Message, int64, error)
 	PutHistory(ctx context.Context, sourceID string, messages []history.Message, expectedGeneration int64) (int64, error)
+	Append(ctx context.Context, sourceID string, messages ...history.Message) ([]history.Message, error)
 }
 
 // MediaService provides media storage functionality.
@@ -54,6 +73,7 @@ type MediaService interface {
 type GroupProfileService interface {
 	GetGroupProfile(ctx context.Context, groupID string) (*groupprofile.GroupProfile, error)
 	SetGroupProfile(ctx context.Context, groupID string, profile *groupprofile.GroupProfile) error
+	UpdateGroupProfile(ctx context.Context, groupID string, update func(*groupprofile.GroupProfile)) error
 }
 
 // Handler implements the server.Handler interface for handling LINE messages.
@@ -65,6 +85,8 @@ type Handler struct {
 	media               MediaService
 	agent               Agent
 	config              HandlerConfig
+	postbackNonces      *nonceCache
+	turnLocks           *sourceLocks
 	logger              *slog.Logger
 }
 
@@ -92,6 +114,19 @@ func NewHandler(lineClient LineClient, userProfileSvc UserProfileService, groupP
 	if logger == nil {
 		return nil, errors.New("logger is required")
 	}
+	nonceTTL := config.PostbackNonceTTL
+	if nonceTTL <= 0 {
+		nonceTTL = defaultPostbackNonceTTL
+	}
+	if strings.TrimSpace(config.EmptyResponseReply) == "" {
+		config.EmptyResponseReply = DefaultEmptyResponseReply
+	}
+	if strings.TrimSpace(config.SafetyBlockedReply) == "" {
+		config.SafetyBlockedReply = DefaultSafetyBlockedReply
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
 	return &Handler{
 		lineClient:          lineClient,
 		userProfileService:  userProfileSvc,
@@ -100,6 +135,8 @@ func NewHandler(lineClient LineClient, userProfileSvc UserProfileService, groupP
 		media:               mediaSvc,
 		agent:               agent,
 		config:              config,
+		postbackNonces:      newNonceCache(nonceTTL, maxPostbackNonces),
+		turnLocks:           newSourceLocks(),
 		logger:              logger,
 	}, nil
 }
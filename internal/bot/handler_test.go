@@ -3,10 +3,12 @@ package bot_test
 import (
 	"context"
 	"log/slog"
+	"slices"
 	"testing"
 	"time"
 	"yuruppu/internal/agent"
 	"yuruppu/internal/bot"
+	"yuruppu/internal/clock"
 	"yuruppu/internal/groupprofile"
 	"yuruppu/internal/history"
 	"yuruppu/internal/line"
@@ -155,6 +157,8 @@ type testHandlerBuilder struct {
 	media        *mockMediaService
 	agent        *mockAgent
 	storage      *mockStorage
+	now          func() time.Time
+	skipInactive bool
 }
 
 // newTestHandler creates a new test handler builder with sensible defaults
@@ -223,12 +227,30 @@ func (b *testHandlerBuilder) WithInitialGroupProfile(profile *groupprofile.Group
 	return b
 }
 
+// WithUserProfile sets the profile that GetUserProfile will return
+func (b *testHandlerBuilder) WithUserProfile(profile *userprofile.UserProfile) *testHandlerBuilder {
+	b.profile.profile = profile
+	return b
+}
+
 // WithAgent sets a custom agent mock
 func (b *testHandlerBuilder) WithAgent(ag *mockAgent) *testHandlerBuilder {
 	b.agent = ag
 	return b
 }
 
+// WithNow sets the clock that fixes each turn's time
+func (b *testHandlerBuilder) WithNow(now func() time.Time) *testHandlerBuilder {
+	b.now = now
+	return b
+}
+
+// WithSkipInactiveGroups makes the handler ignore messages in groups marked inactive
+func (b *testHandlerBuilder) WithSkipInactiveGroups() *testHandlerBuilder {
+	b.skipInactive = true
+	return b
+}
+
 // WithStorage sets a custom storage mock
 func (b *testHandlerBuilder) WithStorage(s *mockStorage) *testHandlerBuilder {
 	b.storage = s
@@ -240,6 +262,9 @@ func (b *testHandlerBuilder) Build() *bot.Handler {
 	historyRepo, err := history.NewService(b.storage)
 	require.NoError(b.t, err)
 
+	config := validHandlerConfig()
+	config.Now = b.now
+	config.SkipInactiveGroups = b.skipInactive
 	handler, err := bot.NewHandler(
 		b.lineClient,
 		b.profile,
@@ -247,7 +272,7 @@ func (b *testHandlerBuilder) Build() *bot.Handler {
 		historyRepo,
 		b.media,
 		b.agent,
-		validHandlerConfig(),
+		config,
 		slog.New(slog.DiscardHandler),
 	)
 	require.NoError(b.t, err)
@@ -265,13 +290,22 @@ func (b *testHandlerBuilder) BuildWithMocks() (*bot.Handler, *mockLineClient, *m
 
 type mockAgent struct {
 	response            string
+	final               bool // Reported as if a final tool such as reply ended the turn
+	finishReason        string
+	safetyBlocked       bool
+	blockedCategories   []string
 	err                 error
 	lastUserMessageText string
-	lastContextText     string        // Captures the first message if it's a context message
-	processDelay        time.Duration // Delay to simulate slow processing
+	lastContextText     string          // Captures the first message if it's a context message
+	lastNow             time.Time       // Captures the turn's time fixed in the context
+	processDelay        time.Duration   // Delay to simulate slow processing
+	lastHistory         []agent.Message // Captures the messages the agent received
 }
 
 func (m *mockAgent) Generate(ctx context.Context, hist []agent.Message) (*agent.AssistantMessage, error) {
+	m.lastNow = clock.Now(ctx)
+	m.lastHistory = hist
+
 	// Extract context from first message if it looks like a context message
 	m.extractContextFromHistory(hist)
 
@@ -289,7 +323,11 @@ func (m *mockAgent) Generate(ctx context.Context, hist []agent.Message) (*agent.
 		return nil, m.err
 	}
 	return &agent.AssistantMessage{
-		Parts: []agent.AssistantPart{&agent.AssistantTextPart{Text: m.response}},
+		Parts:             []agent.AssistantPart{&agent.AssistantTextPart{Text: m.response}},
+		Final:             m.final,
+		FinishReason:      m.finishReason,
+		SafetyBlocked:     m.safetyBlocked,
+		BlockedCategories: m.blockedCategories,
 	}, nil
 }
 
@@ -353,6 +391,11 @@ type mockLineClient struct {
 	// GroupMemberCount tracking
 	groupMemberCount    int
 	groupMemberCountErr error
+	// Send tracking
+	sendReplyCalled bool
+	lastReplyToken  string
+	lastReplyText   string
+	sendReplyErr    error
 }
 
 func (m *mockLineClient) GetMessageContent(messageID string) ([]byte, string, error) {
@@ -416,6 +459,13 @@ func (m *mockLineClient) ShowLoadingAnimation(ctx context.Context, chatID string
 	return m.showLoadingErr
 }
 
+func (m *mockLineClient) Send(ctx context.Context, text string) error {
+	m.sendReplyCalled = true
+	m.lastReplyToken, _ = line.ReplyTokenFromContext(ctx)
+	m.lastReplyText = text
+	return m.sendReplyErr
+}
+
 type mockProfileService struct {
 	profile    *userprofile.UserProfile
 	getErr     error
@@ -444,6 +494,22 @@ func (m *mockProfileService) SetUserProfile(ctx context.Context, userID string,
 	return m.setErr
 }
 
+func (m *mockGroupProfileService) UpdateGroupProfile(ctx context.Context, groupID string, update func(*groupprofile.GroupProfile)) error {
+	m.lastGroupID = groupID
+	m.updateCalls++
+	if m.updateErr != nil {
+		return m.updateErr
+	}
+	var p groupprofile.GroupProfile
+	if m.profile != nil {
+		p = *m.profile
+		p.MemberIDs = slices.Clone(m.profile.MemberIDs)
+	}
+	update(&p)
+	m.profile = &p
+	return nil
+}
+
 // writeResult represents a single Write call result
 type writeResult struct {
 	gen int64
@@ -553,11 +619,15 @@ type mockGroupProfileService struct {
 	profile     *groupprofile.GroupProfile
 	getErr      error
 	setErr      error
+	updateErr   error
 	lastGroupID string
+	getCalls    int
+	updateCalls int
 }
 
 func (m *mockGroupProfileService) GetGroupProfile(ctx context.Context, groupID string) (*groupprofile.GroupProfile, error) {
 	m.lastGroupID = groupID
+	m.getCalls++
 	if m.getErr != nil {
 		return nil, m.getErr
 	}
@@ -10,6 +10,7 @@ import (
 )
 
 // HandleJoin handles the bot being added to a group.
+// A group the bot left before keeps its saved settings; only its summary is refreshed and it is marked active again.
 func (h *Handler) HandleJoin(ctx context.Context) error {
 	chatType, ok := line.ChatTypeFromContext(ctx)
 	if !ok {
@@ -30,11 +31,18 @@ func (h *Handler) HandleJoin(ctx context.Context) error {
 		return fmt.Errorf("failed to get group summary: %w", err)
 	}
 
-	profile := &groupprofile.GroupProfile{
-		DisplayName: summary.GroupName,
-		PictureURL:  summary.PictureURL,
-		UserCount:   1, // fallback
+	profile := &groupprofile.GroupProfile{}
+	if existing, err := h.groupProfileService.GetGroupProfile(ctx, sourceID); err == nil {
+		// Copied so that the cached profile is not changed before it is saved
+		*profile = *existing
+	} else if !errors.As(err, new(*groupprofile.NotFoundError)) {
+		return fmt.Errorf("failed to get group profile: %w", err)
 	}
+	profile.DisplayName = summary.GroupName
+	profile.PictureURL = summary.PictureURL
+	profile.PictureMIMEType = ""
+	profile.UserCount = 1 // fallback
+	profile.Inactive = false
 
 	// Fetch member count (FR-001)
 	if count, err := h.lineClient.GetGroupMemberCount(ctx, sourceID); err != nil {
@@ -70,6 +70,36 @@ func TestHandler_HandleJoin(t *testing.T) {
 		assert.Nil(t, mockGPS.profile, "no partial data should be saved on LINE API failure")
 	})
 
+	t.Run("should return error when the saved profile cannot be read", func(t *testing.T) {
+		mockGPS := &mockGroupProfileService{getErr: errors.New("GCS read failed")}
+		handler := newTestHandler(t).
+			WithGroupSummary("G1234567890abcdef", "Test Group", "").
+			WithGroupProfile(mockGPS).
+			Build()
+
+		ctx := withJoinContext(t.Context(), "G1234567890abcdef")
+		err := handler.HandleJoin(ctx)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to get group profile")
+		assert.Nil(t, mockGPS.profile, "saved settings should not be overwritten with a fresh profile")
+	})
+
+	t.Run("should create a new profile when none is saved", func(t *testing.T) {
+		mockGPS := &mockGroupProfileService{getErr: &groupprofile.NotFoundError{GroupID: "G1234567890abcdef"}}
+		handler := newTestHandler(t).
+			WithGroupSummary("G1234567890abcdef", "New Group", "").
+			WithGroupProfile(mockGPS).
+			Build()
+
+		ctx := withJoinContext(t.Context(), "G1234567890abcdef")
+		err := handler.HandleJoin(ctx)
+
+		require.NoError(t, err)
+		require.NotNil(t, mockGPS.profile)
+		assert.Equal(t, "New Group", mockGPS.profile.DisplayName)
+	})
+
 	// AC-002: Handle storage failure gracefully [FR-002, Error]
 	t.Run("should return error when storage fails", func(t *testing.T) {
 		storageError := errors.New("GCS write quota exceeded")
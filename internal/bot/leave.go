@@ -0,0 +1,38 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"yuruppu/internal/line"
+)
+
+// HandleLeave handles the bot being removed from a group.
+// The group is marked inactive so that scheduled jobs skip it; HandleJoin reactivates it.
+func (h *Handler) HandleLeave(ctx context.Context) error {
+	chatType, ok := line.ChatTypeFromContext(ctx)
+	if !ok {
+		return errors.New("chatType not found in context")
+	}
+	sourceID, ok := line.SourceIDFromContext(ctx)
+	if !ok {
+		return errors.New("sourceID not found in context")
+	}
+
+	h.logger.InfoContext(ctx, "bot left group",
+		slog.String("chatType", string(chatType)),
+		slog.String("sourceID", sourceID),
+	)
+
+	profile, err := h.groupProfileService.GetGroupProfile(ctx, sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to get group profile: %w", err)
+	}
+	profile.Inactive = true
+	if err := h.groupProfileService.SetGroupProfile(ctx, sourceID, profile); err != nil {
+		return fmt.Errorf("failed to mark group inactive: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package bot_test
+
+import (
+	"errors"
+	"testing"
+	"yuruppu/internal/groupprofile"
+	"yuruppu/internal/line"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// HandleLeave Tests
+// =============================================================================
+
+func TestHandler_HandleLeave(t *testing.T) {
+	t.Run("should mark group inactive when bot leaves", func(t *testing.T) {
+		mockGPS := &mockGroupProfileService{profile: &groupprofile.GroupProfile{
+			DisplayName: "Engineering Team",
+			UserCount:   5,
+			Language:    "en",
+		}}
+		handler := newTestHandler(t).
+			WithGroupProfile(mockGPS).
+			Build()
+
+		ctx := withJoinContext(t.Context(), "G-leave")
+		err := handler.HandleLeave(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, "G-leave", mockGPS.lastGroupID)
+		require.NotNil(t, mockGPS.profile)
+		assert.True(t, mockGPS.profile.Inactive)
+		assert.Equal(t, "Engineering Team", mockGPS.profile.DisplayName, "other fields should be kept")
+		assert.Equal(t, "en", mockGPS.profile.Language)
+	})
+
+	t.Run("should reactivate group when bot is added back", func(t *testing.T) {
+		mockGPS := &mockGroupProfileService{profile: &groupprofile.GroupProfile{
+			DisplayName:     "Old Team Name",
+			UserCount:       4,
+			DefaultTimezone: "Europe/London",
+			MemberIDs:       []string{"U-admin", "U-member"},
+			ReplyMode:       groupprofile.ReplyModeMentionOnly,
+			Language:        "en",
+		}}
+		handler := newTestHandler(t).
+			WithGroupSummary("G-leave", "Engineering Team", "").
+			WithGroupProfile(mockGPS).
+			Build()
+		ctx := withJoinContext(t.Context(), "G-leave")
+		require.NoError(t, handler.HandleLeave(ctx))
+		require.True(t, mockGPS.profile.Inactive)
+
+		err := handler.HandleJoin(ctx)
+
+		require.NoError(t, err)
+		assert.False(t, mockGPS.profile.Inactive)
+		// The summary is refreshed and the saved settings survive
+		assert.Equal(t, "Engineering Team", mockGPS.profile.DisplayName)
+		assert.Equal(t, "Europe/London", mockGPS.profile.DefaultTimezone)
+		assert.Equal(t, []string{"U-admin", "U-member"}, mockGPS.profile.MemberIDs)
+		assert.Equal(t, groupprofile.ReplyModeMentionOnly, mockGPS.profile.ReplyMode)
+		assert.Equal(t, "en", mockGPS.profile.Language)
+	})
+
+	t.Run("should return error when group profile cannot be read", func(t *testing.T) {
+		mockGPS := &mockGroupProfileService{getErr: errors.New("storage error")}
+		handler := newTestHandler(t).
+			WithGroupProfile(mockGPS).
+			Build()
+
+		ctx := withJoinContext(t.Context(), "G-leave")
+		err := handler.HandleLeave(ctx)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to get group profile")
+	})
+
+	t.Run("should return error when group profile cannot be saved", func(t *testing.T) {
+		mockGPS := &mockGroupProfileService{setErr: errors.New("storage error")}
+		handler := newTestHandler(t).
+			WithGroupProfile(mockGPS).
+			Build()
+
+		ctx := withJoinContext(t.Context(), "G-leave")
+		err := handler.HandleLeave(ctx)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to mark group inactive")
+	})
+
+	t.Run("should return error when sourceID is missing", func(t *testing.T) {
+		handler := newTestHandler(t).Build()
+
+		ctx := line.WithChatType(t.Context(), line.ChatTypeGroup)
+		err := handler.HandleLeave(ctx)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "sourceID not found")
+	})
+}
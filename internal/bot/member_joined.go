@@ -24,7 +24,7 @@ func (h *Handler) HandleMemberJoined(ctx context.Context, joinedUserIDs []string
 		slog.Any("joinedUserIDs", joinedUserIDs),
 	)
 
-	// Increment member count (FR-002)
+	// Increment member count and record the new members (FR-002)
 	profile, err := h.groupProfileService.GetGroupProfile(ctx, sourceID)
 	if err != nil {
 		h.logger.WarnContext(ctx, "failed to get group profile for member count update",
@@ -34,6 +34,7 @@ func (h *Handler) HandleMemberJoined(ctx context.Context, joinedUserIDs []string
 		return nil
 	}
 	profile.UserCount += len(joinedUserIDs)
+	profile.AddMembers(joinedUserIDs...)
 	if err := h.groupProfileService.SetGroupProfile(ctx, sourceID, profile); err != nil {
 		h.logger.WarnContext(ctx, "failed to update member count",
 			slog.String("sourceID", sourceID),
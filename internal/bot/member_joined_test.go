@@ -40,6 +40,22 @@ func TestHandleMemberJoined(t *testing.T) {
 		assert.Equal(t, 13, mockGPS.profile.UserCount, "UserCount should be 10 + 3 = 13")
 	})
 
+	t.Run("should record joined users as members", func(t *testing.T) {
+		mockGPS := &mockGroupProfileService{profile: &groupprofile.GroupProfile{
+			UserCount: 2,
+			MemberIDs: []string{"U-existing"},
+		}}
+		handler := newTestHandler(t).
+			WithGroupProfile(mockGPS).
+			Build()
+
+		ctx := withJoinContext(t.Context(), "G-members")
+		err := handler.HandleMemberJoined(ctx, []string{"U-new-1", "U-new-2"})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"U-existing", "U-new-1", "U-new-2"}, mockGPS.profile.MemberIDs)
+	})
+
 	// AC-002: Single member join
 	t.Run("should increment member count by 1 when single member joins", func(t *testing.T) {
 		groupID := "G-single-join"
@@ -24,7 +24,7 @@ func (h *Handler) HandleMemberLeft(ctx context.Context, leftUserIDs []string) er
 		slog.Any("leftUserIDs", leftUserIDs),
 	)
 
-	// Decrement member count (FR-003)
+	// Decrement member count and forget the departed members (FR-003)
 	profile, err := h.groupProfileService.GetGroupProfile(ctx, sourceID)
 	if err != nil {
 		h.logger.WarnContext(ctx, "failed to get group profile for member count update",
@@ -34,6 +34,7 @@ func (h *Handler) HandleMemberLeft(ctx context.Context, leftUserIDs []string) er
 		return nil
 	}
 	profile.UserCount -= len(leftUserIDs)
+	profile.RemoveMembers(leftUserIDs...)
 	if err := h.groupProfileService.SetGroupProfile(ctx, sourceID, profile); err != nil {
 		h.logger.WarnContext(ctx, "failed to update member count",
 			slog.String("sourceID", sourceID),
@@ -40,6 +40,22 @@ func TestHandleMemberLeft(t *testing.T) {
 		assert.Equal(t, 7, mockGPS.profile.UserCount, "UserCount should be 10 - 3 = 7")
 	})
 
+	t.Run("should forget users who left", func(t *testing.T) {
+		mockGPS := &mockGroupProfileService{profile: &groupprofile.GroupProfile{
+			UserCount: 3,
+			MemberIDs: []string{"U-stay", "U-leave", "U-other"},
+		}}
+		handler := newTestHandler(t).
+			WithGroupProfile(mockGPS).
+			Build()
+
+		ctx := withJoinContext(t.Context(), "G-members")
+		err := handler.HandleMemberLeft(ctx, []string{"U-leave"})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"U-stay", "U-other"}, mockGPS.profile.MemberIDs)
+	})
+
 	// AC-003: Single member leave
 	t.Run("should decrement member count by 1 when single member leaves", func(t *testing.T) {
 		groupID := "G-single-leave"
@@ -7,10 +7,13 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"sync"
 	"text/template"
 	"time"
 	"yuruppu/internal/agent"
+	"yuruppu/internal/clock"
+	"yuruppu/internal/groupprofile"
 	"yuruppu/internal/history"
 	"yuruppu/internal/line"
 
@@ -33,6 +36,13 @@ var jst = time.FixedZone("JST", 9*60*60)
 
 const signedURLTTL = 60 * time.Second
 
+// formatCurrentLocalTime renders now as RFC3339 in JST with the weekday,
+// matching the format event tools expect so the model can pass it through as-is.
+func formatCurrentLocalTime(now time.Time) string {
+	now = now.In(jst)
+	return now.Format(time.RFC3339) + " (" + now.Format("Mon") + ")"
+}
+
 func (h *Handler) HandleText(ctx context.Context, messageID, text string) error {
 	userID, ok := line.UserIDFromContext(ctx)
 	if !ok {
@@ -85,6 +95,9 @@ func (h *Handler) HandleImage(ctx context.Context, messageID string) error {
 }
 
 func (h *Handler) HandleSticker(ctx context.Context, messageID, packageID, stickerID string) error {
+	if h.config.UnsupportedReply != "" {
+		return h.replyUnsupported(ctx)
+	}
 	userID, ok := line.UserIDFromContext(ctx)
 	if !ok {
 		return errors.New("userID not found in context")
@@ -99,6 +112,9 @@ func (h *Handler) HandleSticker(ctx context.Context, messageID, packageID, stick
 }
 
 func (h *Handler) HandleVideo(ctx context.Context, messageID string) error {
+	if h.config.UnsupportedReply != "" {
+		return h.replyUnsupported(ctx)
+	}
 	userID, ok := line.UserIDFromContext(ctx)
 	if !ok {
 		return errors.New("userID not found in context")
@@ -113,6 +129,9 @@ func (h *Handler) HandleVideo(ctx context.Context, messageID string) error {
 }
 
 func (h *Handler) HandleAudio(ctx context.Context, messageID string) error {
+	if h.config.UnsupportedReply != "" {
+		return h.replyUnsupported(ctx)
+	}
 	userID, ok := line.UserIDFromContext(ctx)
 	if !ok {
 		return errors.New("userID not found in context")
@@ -127,6 +146,9 @@ func (h *Handler) HandleAudio(ctx context.Context, messageID string) error {
 }
 
 func (h *Handler) HandleLocation(ctx context.Context, messageID string, latitude, longitude float64) error {
+	if h.config.UnsupportedReply != "" {
+		return h.replyUnsupported(ctx)
+	}
 	userID, ok := line.UserIDFromContext(ctx)
 	if !ok {
 		return errors.New("userID not found in context")
@@ -141,6 +163,9 @@ func (h *Handler) HandleLocation(ctx context.Context, messageID string, latitude
 }
 
 func (h *Handler) HandleFile(ctx context.Context, messageID, fileName string, fileSize int64) error {
+	if h.config.UnsupportedReply != "" {
+		return h.replyUnsupported(ctx)
+	}
 	userID, ok := line.UserIDFromContext(ctx)
 	if !ok {
 		return errors.New("userID not found in context")
@@ -154,6 +179,15 @@ func (h *Handler) HandleFile(ctx context.Context, messageID, fileName string, fi
 	return h.handleMessage(ctx, userMsg)
 }
 
+// replyUnsupported replies with the configured message instead of invoking the agent.
+// Used for message types other than text and images.
+func (h *Handler) replyUnsupported(ctx context.Context) error {
+	if err := h.lineClient.Send(ctx, h.config.UnsupportedReply); err != nil {
+		return fmt.Errorf("failed to send unsupported message reply: %w", err)
+	}
+	return nil
+}
+
 func (h *Handler) handleMessage(ctx context.Context, userMsg *history.UserMessage) error {
 	chatType, ok := line.ChatTypeFromContext(ctx)
 	if !ok {
@@ -164,6 +198,51 @@ func (h *Handler) handleMessage(ctx context.Context, userMsg *history.UserMessag
 		return errors.New("sourceID not found in context")
 	}
 
+	// Serialize turns per conversation so history updates are not interleaved
+	unlock, err := h.turnLocks.lock(ctx, sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to wait for previous turn: %w", err)
+	}
+	defer unlock()
+
+	// Read the group profile once per turn; the presence check, the reply mode, and the chat context all use it.
+	// Reading it under the turn lock picks up settings a previous turn's tools changed.
+	var groupProfile *groupprofile.GroupProfile
+	if chatType == line.ChatTypeGroup {
+		groupProfile, err = h.groupProfileService.GetGroupProfile(ctx, sourceID)
+		if err != nil {
+			h.logger.WarnContext(ctx, "failed to get group profile",
+				slog.String("sourceID", sourceID),
+				slog.Any("error", err),
+			)
+			groupProfile = nil
+		}
+	}
+
+	if chatType == line.ChatTypeGroup && h.config.SkipInactiveGroups && !botInGroup(groupProfile) {
+		h.logger.DebugContext(ctx, "skipping group message while the bot is marked as not in the group",
+			slog.String("sourceID", sourceID),
+			slog.String("messageID", userMsg.MessageID),
+		)
+		return nil
+	}
+
+	if chatType == line.ChatTypeGroup && !repliesInGroup(ctx, groupProfile) {
+		h.logger.DebugContext(ctx, "skipping group message that does not mention the bot",
+			slog.String("sourceID", sourceID),
+			slog.String("messageID", userMsg.MessageID),
+		)
+		return nil
+	}
+
+	// Members present before the bot joined are only learned when they speak
+	if groupProfile != nil && userMsg.UserID != "" && !slices.Contains(groupProfile.MemberIDs, userMsg.UserID) {
+		h.recordGroupMember(ctx, sourceID, userMsg.UserID)
+	}
+
+	// Fix the time for this turn so the chat context and every tool agree on "now"
+	ctx = clock.WithNow(ctx, h.config.Now())
+
 	// Delayed loading indicator (FR-001, FR-002, FR-006, NFR-001, NFR-002)
 	done := make(chan struct{})
 	defer close(done)
@@ -191,19 +270,15 @@ func (h *Handler) handleMessage(ctx context.Context, userMsg *history.UserMessag
 		}()
 	}
 
-	// Step 1: Load history
-	hist, gen, err := h.history.GetHistory(ctx, sourceID)
-	if err != nil {
-		return fmt.Errorf("failed to load history: %w", err)
-	}
-
-	// Step 2: Save user message to history
-	hist = append(hist, userMsg)
-	_, err = h.history.PutHistory(ctx, sourceID, hist, gen)
+	// Steps 1-2: Load history and save user message to it, retrying if another message lands in between
+	hist, err := h.history.Append(ctx, sourceID, userMsg)
 	if err != nil {
 		return fmt.Errorf("failed to save user message to history: %w", err)
 	}
 
+	// Only the recent turns are sent; the stored history keeps everything
+	hist = recentTurns(hist, h.config.MaxHistoryTurns)
+
 	// Step 3: Build context message and convert history to agent format
 	usernameCache := make(map[string]string)
 	getUsername := func(userID string) string {
@@ -229,7 +304,7 @@ func (h *Handler) handleMessage(ctx context.Context, userMsg *history.UserMessag
 	g, gCtx := errgroup.WithContext(ctx)
 	g.Go(func() error {
 		var err error
-		contextParts, err = h.buildContextParts(gCtx, userMsg.UserID)
+		contextParts, err = h.buildContextParts(gCtx, userMsg.UserID, groupProfile)
 		return err
 	})
 	g.Go(func() error {
@@ -256,28 +331,97 @@ func (h *Handler) handleMessage(ctx context.Context, userMsg *history.UserMessag
 		slog.Any("response", response),
 	)
 
+	// Step 5: Make sure the user gets something when the model produced nothing
+	if !isEmptyResponse(response) {
+		return nil
+	}
+	fallback := h.config.EmptyResponseReply
+	if response.SafetyBlocked {
+		// Logged apart from empty responses so that blocks can be monitored separately from failures
+		h.logger.WarnContext(ctx, "agent response blocked by safety filter, sending fallback reply",
+			slog.String("sourceID", sourceID),
+			slog.String("finishReason", response.FinishReason),
+			slog.Any("blockedCategories", response.BlockedCategories),
+		)
+		fallback = h.config.SafetyBlockedReply
+	} else {
+		h.logger.WarnContext(ctx, "agent produced an empty response, sending fallback reply",
+			slog.String("sourceID", sourceID),
+			slog.String("finishReason", response.FinishReason),
+		)
+	}
+	if err := h.lineClient.Send(ctx, fallback); err != nil {
+		return fmt.Errorf("failed to send empty response fallback: %w", err)
+	}
+
 	return nil
 }
 
-func (h *Handler) buildContextParts(ctx context.Context, userID string) ([]agent.UserPart, error) {
+// botInGroup reports whether profile still has the bot in the group.
+// A nil profile, i.e. one that could not be loaded, counts as present, so messages are not dropped on a storage error.
+func botInGroup(profile *groupprofile.GroupProfile) bool {
+	return profile == nil || !profile.Inactive
+}
+
+// repliesInGroup reports whether the bot should answer the current message in a group with profile.
+// Groups in mention-only mode are answered only when the message @-mentions the bot.
+// A nil profile, i.e. one that could not be loaded, falls back to always answering.
+func repliesInGroup(ctx context.Context, profile *groupprofile.GroupProfile) bool {
+	if line.BotMentionedFromContext(ctx) {
+		return true
+	}
+	return profile == nil || profile.ReplyMode != groupprofile.ReplyModeMentionOnly
+}
+
+// recordGroupMember adds userID to the members of groupID.
+// The stored profile is updated in place so that settings other writers changed meanwhile are kept.
+func (h *Handler) recordGroupMember(ctx context.Context, groupID, userID string) {
+	err := h.groupProfileService.UpdateGroupProfile(ctx, groupID, func(p *groupprofile.GroupProfile) {
+		p.AddMembers(userID)
+	})
+	if err != nil {
+		h.logger.WarnContext(ctx, "failed to record group member",
+			slog.String("sourceID", groupID),
+			slog.String("userID", userID),
+			slog.Any("error", err),
+		)
+	}
+}
+
+// isEmptyResponse reports whether response ended the turn without a final tool.
+// Text and files in the response are never delivered, so only a final tool such as reply reaches the user.
+func isEmptyResponse(response *agent.AssistantMessage) bool {
+	return !response.Final
+}
+
+// buildContextParts builds the context message for the turn.
+// groupProfile is the profile handleMessage loaded for a group chat; it is nil for other chats or when it could not be loaded.
+func (h *Handler) buildContextParts(ctx context.Context, userID string, groupProfile *groupprofile.GroupProfile) ([]agent.UserPart, error) {
 	chatType, ok := line.ChatTypeFromContext(ctx)
 	if !ok {
 		return nil, errors.New("chatType not found in context")
 	}
-	sourceID, ok := line.SourceIDFromContext(ctx)
-	if !ok {
-		return nil, errors.New("sourceID not found in context")
-	}
 
-	// Get user count for group chats (FR-005)
+	// Get user count and language for group chats (FR-005)
 	var userCount int
-	if chatType == line.ChatTypeGroup {
-		profile, err := h.groupProfileService.GetGroupProfile(ctx, sourceID)
-		if err != nil {
-			slog.WarnContext(ctx, "failed to get group profile for user count", "error", err)
-		} else {
-			userCount = profile.UserCount
-		}
+	var groupLanguage string
+	if groupProfile != nil {
+		userCount = groupProfile.UserCount
+		groupLanguage = groupProfile.Language
+	}
+
+	p, err := h.userProfileService.GetUserProfile(ctx, userID)
+	if err != nil {
+		h.logger.WarnContext(ctx, "failed to get user profile",
+			slog.String("userID", userID),
+			slog.Any("error", err),
+		)
+	}
+
+	// A group's language takes precedence over the sender's own
+	replyLanguage := groupLanguage
+	if replyLanguage == "" && p != nil {
+		replyLanguage = p.Language
 	}
 
 	var buf bytes.Buffer
@@ -285,21 +429,18 @@ func (h *Handler) buildContextParts(ctx context.Context, userID string) ([]agent
 		CurrentLocalTime string
 		ChatType         line.ChatType
 		UserCount        int
+		ReplyLanguage    string
 	}{
-		CurrentLocalTime: time.Now().In(jst).Format("2006 Jan 2(Mon) 3:04PM"),
+		CurrentLocalTime: formatCurrentLocalTime(clock.Now(ctx)),
 		ChatType:         chatType,
 		UserCount:        userCount,
+		ReplyLanguage:    replyLanguage,
 	}); err != nil {
 		return nil, fmt.Errorf("failed to execute chat context template: %w", err)
 	}
 	parts := []agent.UserPart{&agent.UserTextPart{Text: buf.String()}}
 
-	p, err := h.userProfileService.GetUserProfile(ctx, userID)
-	if err != nil {
-		h.logger.WarnContext(ctx, "failed to get user profile",
-			slog.String("userID", userID),
-			slog.Any("error", err),
-		)
+	if p == nil {
 		return parts, nil
 	}
 
@@ -320,6 +461,23 @@ func (h *Handler) buildContextParts(ctx context.Context, userID string) ([]agent
 	return parts, nil
 }
 
+// recentTurns returns the last maxTurns turns of hist, where a turn starts at a user message.
+// The latest user message is always kept. maxTurns <= 0 keeps the whole history.
+func recentTurns(hist []history.Message, maxTurns int) []history.Message {
+	if maxTurns <= 0 {
+		return hist
+	}
+	for i := len(hist) - 1; i >= 0; i-- {
+		if _, ok := hist[i].(*history.UserMessage); !ok {
+			continue
+		}
+		if maxTurns--; maxTurns == 0 {
+			return hist[i:]
+		}
+	}
+	return hist
+}
+
 // convertToAgentHistory converts history.Message slice to agent.Message slice.
 // Fetches signed URLs in parallel for all file parts.
 func (h *Handler) convertToAgentHistory(ctx context.Context, hist []history.Message, getUsername func(string) string) ([]agent.Message, error) {
@@ -1,14 +1,21 @@
 package bot_test
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"regexp"
+	"strings"
 	"testing"
 	"time"
+	"yuruppu/internal/agent"
 	"yuruppu/internal/bot"
 	"yuruppu/internal/groupprofile"
 	"yuruppu/internal/history"
+	"yuruppu/internal/line"
+	"yuruppu/internal/userprofile"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -460,6 +467,239 @@ func TestHandler_HandleSticker(t *testing.T) {
 	})
 }
 
+// =============================================================================
+// UnsupportedReply Tests
+// =============================================================================
+
+func TestHandler_UnsupportedReply(t *testing.T) {
+	t.Run("replies and skips agent when enabled", func(t *testing.T) {
+		mockStore := newMockStorage()
+		mockClient := &mockLineClient{}
+		mockAg := &mockAgent{response: "Nice sticker!"}
+		historyRepo, err := history.NewService(mockStore)
+		require.NoError(t, err)
+		config := validHandlerConfig()
+		config.UnsupportedReply = "I can only read text right now"
+		logger := slog.New(slog.DiscardHandler)
+		h, err := bot.NewHandler(mockClient, &mockProfileService{}, &mockGroupProfileService{}, historyRepo, &mockMediaService{}, mockAg, config, logger)
+		require.NoError(t, err)
+
+		ctx := withLineContext(t.Context(), "reply-token", "user-123", "user-123")
+		err = h.HandleSticker(ctx, "test-msg-id", "pkg-1", "stk-2")
+
+		require.NoError(t, err)
+		assert.True(t, mockClient.sendReplyCalled)
+		assert.Equal(t, "reply-token", mockClient.lastReplyToken)
+		assert.Equal(t, "I can only read text right now", mockClient.lastReplyText)
+		assert.Empty(t, mockAg.lastUserMessageText)
+	})
+
+	t.Run("does not reply to images when enabled", func(t *testing.T) {
+		mockStore := newMockStorage()
+		mockClient := &mockLineClient{}
+		mockAg := &mockAgent{response: "Nice image!", final: true}
+		historyRepo, err := history.NewService(mockStore)
+		require.NoError(t, err)
+		config := validHandlerConfig()
+		config.UnsupportedReply = "I can only read text right now"
+		logger := slog.New(slog.DiscardHandler)
+		h, err := bot.NewHandler(mockClient, &mockProfileService{}, &mockGroupProfileService{}, historyRepo, &mockMediaService{}, mockAg, config, logger)
+		require.NoError(t, err)
+
+		ctx := withLineContext(t.Context(), "reply-token", "user-123", "user-123")
+		err = h.HandleImage(ctx, "test-msg-id")
+
+		require.NoError(t, err)
+		assert.False(t, mockClient.sendReplyCalled)
+	})
+
+	t.Run("stays silent and passes to agent when disabled", func(t *testing.T) {
+		mockStore := newMockStorage()
+		mockClient := &mockLineClient{}
+		mockAg := &mockAgent{response: "I see a video!", final: true}
+		historyRepo, err := history.NewService(mockStore)
+		require.NoError(t, err)
+		logger := slog.New(slog.DiscardHandler)
+		h, err := bot.NewHandler(mockClient, &mockProfileService{}, &mockGroupProfileService{}, historyRepo, &mockMediaService{}, mockAg, validHandlerConfig(), logger)
+		require.NoError(t, err)
+
+		ctx := withLineContext(t.Context(), "reply-token", "user-123", "user-123")
+		err = h.HandleVideo(ctx, "test-msg-id")
+
+		require.NoError(t, err)
+		assert.False(t, mockClient.sendReplyCalled)
+		assert.Equal(t, "[User sent a video]", mockAg.lastUserMessageText)
+	})
+
+	t.Run("returns error when reply fails", func(t *testing.T) {
+		mockStore := newMockStorage()
+		mockClient := &mockLineClient{sendReplyErr: errors.New("LINE API error")}
+		historyRepo, err := history.NewService(mockStore)
+		require.NoError(t, err)
+		config := validHandlerConfig()
+		config.UnsupportedReply = "I can only read text right now"
+		logger := slog.New(slog.DiscardHandler)
+		h, err := bot.NewHandler(mockClient, &mockProfileService{}, &mockGroupProfileService{}, historyRepo, &mockMediaService{}, &mockAgent{}, config, logger)
+		require.NoError(t, err)
+
+		ctx := withLineContext(t.Context(), "reply-token", "user-123", "user-123")
+		err = h.HandleFile(ctx, "test-msg-id", "doc.pdf", 1024)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to send unsupported message reply")
+	})
+}
+
+// =============================================================================
+// EmptyResponseReply Tests
+// =============================================================================
+
+func TestHandler_EmptyResponseReply(t *testing.T) {
+	newHandler := func(t *testing.T, client *mockLineClient, ag *mockAgent, config bot.HandlerConfig, logger *slog.Logger) *bot.Handler {
+		t.Helper()
+		historyRepo, err := history.NewService(newMockStorage())
+		require.NoError(t, err)
+		h, err := bot.NewHandler(client, &mockProfileService{}, &mockGroupProfileService{}, historyRepo, &mockMediaService{}, ag, config, logger)
+		require.NoError(t, err)
+		return h
+	}
+
+	t.Run("sends the default fallback when the agent produces nothing", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+		mockClient := &mockLineClient{}
+		h := newHandler(t, mockClient, &mockAgent{response: "", finishReason: "SAFETY"}, validHandlerConfig(), logger)
+
+		ctx := withLineContext(t.Context(), "reply-token", "user-123", "user-123")
+		err := h.HandleText(ctx, "msg-1", "hello")
+
+		require.NoError(t, err)
+		assert.True(t, mockClient.sendReplyCalled)
+		assert.Equal(t, bot.DefaultEmptyResponseReply, mockClient.lastReplyText)
+		assert.Contains(t, buf.String(), "agent produced an empty response")
+		assert.Contains(t, buf.String(), `"finishReason":"SAFETY"`)
+	})
+
+	t.Run("treats whitespace-only output as empty", func(t *testing.T) {
+		mockClient := &mockLineClient{}
+		h := newHandler(t, mockClient, &mockAgent{response: " \n\t "}, validHandlerConfig(), slog.New(slog.DiscardHandler))
+
+		ctx := withLineContext(t.Context(), "reply-token", "user-123", "user-123")
+		err := h.HandleText(ctx, "msg-1", "hello")
+
+		require.NoError(t, err)
+		assert.Equal(t, bot.DefaultEmptyResponseReply, mockClient.lastReplyText)
+	})
+
+	t.Run("sends the configured fallback", func(t *testing.T) {
+		mockClient := &mockLineClient{}
+		config := validHandlerConfig()
+		config.EmptyResponseReply = "Sorry, I have no answer"
+		h := newHandler(t, mockClient, &mockAgent{response: ""}, config, slog.New(slog.DiscardHandler))
+
+		ctx := withLineContext(t.Context(), "reply-token", "user-123", "user-123")
+		err := h.HandleText(ctx, "msg-1", "hello")
+
+		require.NoError(t, err)
+		assert.Equal(t, "Sorry, I have no answer", mockClient.lastReplyText)
+	})
+
+	t.Run("treats text without a final tool as empty because text is never delivered", func(t *testing.T) {
+		mockClient := &mockLineClient{}
+		h := newHandler(t, mockClient, &mockAgent{response: "Hello!"}, validHandlerConfig(), slog.New(slog.DiscardHandler))
+
+		ctx := withLineContext(t.Context(), "reply-token", "user-123", "user-123")
+		err := h.HandleText(ctx, "msg-1", "hello")
+
+		require.NoError(t, err)
+		assert.Equal(t, bot.DefaultEmptyResponseReply, mockClient.lastReplyText)
+	})
+
+	t.Run("does not send a fallback when a final tool ended the turn", func(t *testing.T) {
+		mockClient := &mockLineClient{}
+		h := newHandler(t, mockClient, &mockAgent{response: "", final: true}, validHandlerConfig(), slog.New(slog.DiscardHandler))
+
+		ctx := withLineContext(t.Context(), "reply-token", "user-123", "user-123")
+		err := h.HandleText(ctx, "msg-1", "hello")
+
+		require.NoError(t, err)
+		assert.False(t, mockClient.sendReplyCalled)
+	})
+
+	t.Run("returns error when the fallback cannot be sent", func(t *testing.T) {
+		mockClient := &mockLineClient{sendReplyErr: errors.New("line error")}
+		h := newHandler(t, mockClient, &mockAgent{response: ""}, validHandlerConfig(), slog.New(slog.DiscardHandler))
+
+		ctx := withLineContext(t.Context(), "reply-token", "user-123", "user-123")
+		err := h.HandleText(ctx, "msg-1", "hello")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to send empty response fallback")
+	})
+}
+
+// =============================================================================
+// SafetyBlockedReply Tests
+// =============================================================================
+
+func TestHandler_SafetyBlockedReply(t *testing.T) {
+	newHandler := func(t *testing.T, client *mockLineClient, ag *mockAgent, config bot.HandlerConfig, logger *slog.Logger) *bot.Handler {
+		t.Helper()
+		historyRepo, err := history.NewService(newMockStorage())
+		require.NoError(t, err)
+		h, err := bot.NewHandler(client, &mockProfileService{}, &mockGroupProfileService{}, historyRepo, &mockMediaService{}, ag, config, logger)
+		require.NoError(t, err)
+		return h
+	}
+	blockedAgent := func() *mockAgent {
+		return &mockAgent{
+			finishReason:      "SAFETY",
+			safetyBlocked:     true,
+			blockedCategories: []string{"HARM_CATEGORY_HARASSMENT"},
+		}
+	}
+
+	t.Run("sends the default safety reply and logs the blocked categories", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+		mockClient := &mockLineClient{}
+		h := newHandler(t, mockClient, blockedAgent(), validHandlerConfig(), logger)
+
+		ctx := withLineContext(t.Context(), "reply-token", "user-123", "user-123")
+		err := h.HandleText(ctx, "msg-1", "hello")
+
+		require.NoError(t, err)
+		assert.Equal(t, bot.DefaultSafetyBlockedReply, mockClient.lastReplyText)
+		assert.Contains(t, buf.String(), "agent response blocked by safety filter")
+		assert.Contains(t, buf.String(), `"blockedCategories":["HARM_CATEGORY_HARASSMENT"]`)
+		assert.NotContains(t, buf.String(), "agent produced an empty response")
+	})
+
+	t.Run("sends the configured safety reply", func(t *testing.T) {
+		mockClient := &mockLineClient{}
+		config := validHandlerConfig()
+		config.SafetyBlockedReply = "Let's talk about something else"
+		h := newHandler(t, mockClient, blockedAgent(), config, slog.New(slog.DiscardHandler))
+
+		ctx := withLineContext(t.Context(), "reply-token", "user-123", "user-123")
+		err := h.HandleText(ctx, "msg-1", "hello")
+
+		require.NoError(t, err)
+		assert.Equal(t, "Let's talk about something else", mockClient.lastReplyText)
+	})
+
+	t.Run("uses the empty response reply for non-safety stops", func(t *testing.T) {
+		mockClient := &mockLineClient{}
+		h := newHandler(t, mockClient, &mockAgent{finishReason: "RECITATION"}, validHandlerConfig(), slog.New(slog.DiscardHandler))
+
+		ctx := withLineContext(t.Context(), "reply-token", "user-123", "user-123")
+		err := h.HandleText(ctx, "msg-1", "hello")
+
+		require.NoError(t, err)
+		assert.Equal(t, bot.DefaultEmptyResponseReply, mockClient.lastReplyText)
+	})
+}
+
 // =============================================================================
 // HandleVideo Tests
 // =============================================================================
@@ -622,6 +862,115 @@ func TestHandler_HistoryIntegration(t *testing.T) {
 	})
 }
 
+func TestHandler_MaxHistoryTurns(t *testing.T) {
+	// storedHistory holds three earlier turns, each a user message and a reply
+	storedHistory := func() []history.Message {
+		var messages []history.Message
+		for i := 1; i <= 3; i++ {
+			messages = append(messages,
+				&history.UserMessage{
+					MessageID: fmt.Sprintf("msg-%d", i),
+					UserID:    "user-123",
+					Parts:     []history.UserPart{&history.UserTextPart{Text: fmt.Sprintf("question-%d", i)}},
+					Timestamp: time.Now().Add(-time.Duration(10-2*i) * time.Minute),
+				},
+				&history.AssistantMessage{
+					ModelName: "test-model",
+					Parts:     []history.AssistantPart{&history.AssistantTextPart{Text: fmt.Sprintf("answer-%d", i)}},
+					Timestamp: time.Now().Add(-time.Duration(9-2*i) * time.Minute),
+				},
+			)
+		}
+		return messages
+	}
+
+	tests := []struct {
+		name       string
+		maxTurns   int
+		wantSent   []string
+		wantUnsent []string
+	}{
+		{
+			name:     "sends the whole history when unlimited",
+			maxTurns: 0,
+			wantSent: []string{"question-1", "answer-1", "question-2", "answer-2", "question-3", "answer-3", "question-4"},
+		},
+		{
+			name:       "sends only the configured number of recent turns",
+			maxTurns:   2,
+			wantSent:   []string{"question-3", "answer-3", "question-4"},
+			wantUnsent: []string{"question-1", "answer-1", "question-2", "answer-2"},
+		},
+		{
+			name:       "always sends the latest user message",
+			maxTurns:   1,
+			wantSent:   []string{"question-4"},
+			wantUnsent: []string{"question-1", "answer-1", "question-2", "answer-2", "question-3", "answer-3"},
+		},
+		{
+			name:     "sends everything when the history is shorter than the limit",
+			maxTurns: 10,
+			wantSent: []string{"question-1", "answer-1", "question-2", "answer-2", "question-3", "answer-3", "question-4"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := newMockStorage()
+			historyRepo, err := history.NewService(mockStore)
+			require.NoError(t, err)
+			ctx := withLineContext(t.Context(), "reply-token", "user-123", "user-123")
+			_, err = historyRepo.PutHistory(ctx, "user-123", storedHistory(), 0)
+			require.NoError(t, err)
+
+			mockAg := &mockAgent{response: "Hello!"}
+			config := validHandlerConfig()
+			config.MaxHistoryTurns = tt.maxTurns
+			h, err := bot.NewHandler(&mockLineClient{}, &mockProfileService{}, &mockGroupProfileService{}, historyRepo, &mockMediaService{}, mockAg, config, slog.New(slog.DiscardHandler))
+			require.NoError(t, err)
+
+			err = h.HandleText(ctx, "msg-4", "question-4")
+
+			require.NoError(t, err)
+			// The first message is the context message built for every turn
+			require.NotEmpty(t, mockAg.lastContextText)
+			sent := historyTexts(mockAg.lastHistory[1:])
+			assert.Equal(t, tt.wantSent, sent)
+			for _, text := range tt.wantUnsent {
+				assert.NotContains(t, sent, text)
+			}
+
+			// The stored history is not trimmed
+			stored, _, err := historyRepo.GetHistory(ctx, "user-123")
+			require.NoError(t, err)
+			assert.Len(t, stored, 7)
+		})
+	}
+}
+
+// historyTexts returns the message texts of hist in order, skipping the sender headers.
+func historyTexts(hist []agent.Message) []string {
+	var texts []string
+	for _, msg := range hist {
+		switch m := msg.(type) {
+		case *agent.UserMessage:
+			if len(m.Parts) < 2 {
+				continue
+			}
+			if p, ok := m.Parts[len(m.Parts)-1].(*agent.UserTextPart); ok {
+				texts = append(texts, p.Text)
+			}
+		case *agent.AssistantMessage:
+			for _, part := range m.Parts {
+				if p, ok := part.(*agent.AssistantTextPart); ok {
+					texts = append(texts, p.Text)
+				}
+			}
+		}
+	}
+	return texts
+}
+
 // =============================================================================
 // Error Chain Tests (errors.Is verification)
 // =============================================================================
@@ -665,7 +1014,7 @@ func TestHandler_ErrorChain(t *testing.T) {
 		// Verify error chain preserves original error
 		assert.True(t, errors.Is(err, storageErr), "error chain should contain original storage error")
 		// Verify wrapping context is present
-		assert.Contains(t, err.Error(), "failed to load history")
+		assert.Contains(t, err.Error(), "failed to read history")
 	})
 
 	t.Run("storage write error is wrapped and preserves original error", func(t *testing.T) {
@@ -724,6 +1073,92 @@ func TestHandleMessage_GroupMemberCount(t *testing.T) {
 		assert.Contains(t, mockAg.lastContextText, "chat_type: group", "context should indicate group chat")
 	})
 
+	t.Run("group message records the sender as a member", func(t *testing.T) {
+		mockGroupProfile := &mockGroupProfileService{
+			profile: &groupprofile.GroupProfile{
+				DisplayName: "Test Group",
+				UserCount:   3,
+				MemberIDs:   []string{"user-456"},
+			},
+		}
+
+		h := newTestHandler(t).
+			WithGroupProfile(mockGroupProfile).
+			WithAgent(&mockAgent{response: "Hello group!"}).
+			Build()
+
+		ctx := withLineContext(t.Context(), "reply-token", "group-789", "user-123")
+		err := h.HandleText(ctx, "test-msg-id", "Hi everyone!")
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"user-456", "user-123"}, mockGroupProfile.profile.MemberIDs)
+		assert.Equal(t, 1, mockGroupProfile.updateCalls)
+	})
+
+	t.Run("group message reads the group profile once", func(t *testing.T) {
+		mockGroupProfile := &mockGroupProfileService{
+			profile: &groupprofile.GroupProfile{
+				DisplayName: "Test Group",
+				UserCount:   3,
+				ReplyMode:   groupprofile.ReplyModeAlways,
+				Language:    "en",
+			},
+		}
+		mockAg := &mockAgent{response: "Hello group!"}
+
+		h := newTestHandler(t).
+			WithGroupProfile(mockGroupProfile).
+			WithAgent(mockAg).
+			WithSkipInactiveGroups().
+			Build()
+
+		ctx := withLineContext(t.Context(), "reply-token", "group-789", "user-123")
+		err := h.HandleText(ctx, "test-msg-id", "Hi everyone!")
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, mockGroupProfile.getCalls)
+		assert.Contains(t, mockAg.lastContextText, "user_count: 3")
+	})
+
+	t.Run("group message from a known member does not write the group profile", func(t *testing.T) {
+		mockGroupProfile := &mockGroupProfileService{
+			profile: &groupprofile.GroupProfile{
+				DisplayName: "Test Group",
+				MemberIDs:   []string{"user-123"},
+			},
+		}
+
+		h := newTestHandler(t).
+			WithGroupProfile(mockGroupProfile).
+			WithAgent(&mockAgent{response: "Hello group!"}).
+			Build()
+
+		ctx := withLineContext(t.Context(), "reply-token", "group-789", "user-123")
+		err := h.HandleText(ctx, "test-msg-id", "Hi everyone!")
+
+		require.NoError(t, err)
+		assert.Zero(t, mockGroupProfile.updateCalls)
+	})
+
+	t.Run("group message continues when recording the member fails", func(t *testing.T) {
+		mockGroupProfile := &mockGroupProfileService{
+			profile:   &groupprofile.GroupProfile{DisplayName: "Test Group", UserCount: 3},
+			updateErr: errors.New("precondition failed"),
+		}
+		mockAg := &mockAgent{response: "Hello group!"}
+
+		h := newTestHandler(t).
+			WithGroupProfile(mockGroupProfile).
+			WithAgent(mockAg).
+			Build()
+
+		ctx := withLineContext(t.Context(), "reply-token", "group-789", "user-123")
+		err := h.HandleText(ctx, "test-msg-id", "Hi everyone!")
+
+		require.NoError(t, err)
+		assert.Contains(t, mockAg.lastContextText, "user_count: 3")
+	})
+
 	// AC-005: Handle missing member count gracefully [FR-005]
 	t.Run("AC-005: group message continues when group profile unavailable", func(t *testing.T) {
 		// Given: A group message is received but member count is not stored
@@ -868,6 +1303,84 @@ func TestHandleMessage_GroupMemberCount(t *testing.T) {
 	})
 }
 
+// =============================================================================
+// Reply Language Context Tests
+// =============================================================================
+
+func TestHandleMessage_ReplyLanguage(t *testing.T) {
+	t.Run("group language takes precedence over the user's language", func(t *testing.T) {
+		mockAg := &mockAgent{response: "Hello group!"}
+		h := newTestHandler(t).
+			WithInitialGroupProfile(&groupprofile.GroupProfile{DisplayName: "Test Group", Language: "en"}).
+			WithUserProfile(&userprofile.UserProfile{DisplayName: "Taro", Language: "ja"}).
+			WithAgent(mockAg).
+			Build()
+
+		ctx := withLineContext(t.Context(), "reply-token", "group-789", "user-123")
+		err := h.HandleText(ctx, "test-msg-id", "こんにちは")
+
+		require.NoError(t, err)
+		assert.Contains(t, mockAg.lastContextText, "reply_language: en")
+		assert.NotContains(t, mockAg.lastContextText, "reply_language: ja")
+	})
+
+	t.Run("group without a language falls back to the user's language", func(t *testing.T) {
+		mockAg := &mockAgent{response: "Hello group!"}
+		h := newTestHandler(t).
+			WithInitialGroupProfile(&groupprofile.GroupProfile{DisplayName: "Test Group"}).
+			WithUserProfile(&userprofile.UserProfile{DisplayName: "Taro", Language: "ja"}).
+			WithAgent(mockAg).
+			Build()
+
+		ctx := withLineContext(t.Context(), "reply-token", "group-789", "user-123")
+		err := h.HandleText(ctx, "test-msg-id", "こんにちは")
+
+		require.NoError(t, err)
+		assert.Contains(t, mockAg.lastContextText, "reply_language: ja")
+	})
+
+	t.Run("1:1 chat uses the user's language", func(t *testing.T) {
+		mockAg := &mockAgent{response: "Hello!"}
+		h := newTestHandler(t).
+			WithUserProfile(&userprofile.UserProfile{DisplayName: "Taro", Language: "ko"}).
+			WithAgent(mockAg).
+			Build()
+
+		ctx := withLineContext(t.Context(), "reply-token", "user-123", "user-123")
+		err := h.HandleText(ctx, "test-msg-id", "안녕하세요")
+
+		require.NoError(t, err)
+		assert.Contains(t, mockAg.lastContextText, "reply_language: ko")
+	})
+
+	t.Run("group language applies when the user profile is unavailable", func(t *testing.T) {
+		mockAg := &mockAgent{response: "Hello group!"}
+		b := newTestHandler(t).
+			WithInitialGroupProfile(&groupprofile.GroupProfile{DisplayName: "Test Group", Language: "en"}).
+			WithAgent(mockAg)
+		b.profile.getErr = errors.New("profile not found")
+		h := b.Build()
+
+		ctx := withLineContext(t.Context(), "reply-token", "group-789", "user-123")
+		err := h.HandleText(ctx, "test-msg-id", "Hi!")
+
+		require.NoError(t, err)
+		assert.Contains(t, mockAg.lastContextText, "reply_language: en")
+	})
+
+	t.Run("no language is given when none is known", func(t *testing.T) {
+		mockAg := &mockAgent{response: "Hello!"}
+		h := newTestHandler(t).WithAgent(mockAg).Build()
+
+		ctx := withLineContext(t.Context(), "reply-token", "user-123", "user-123")
+		err := h.HandleText(ctx, "test-msg-id", "Hi!")
+
+		require.NoError(t, err)
+		require.NotEmpty(t, mockAg.lastContextText)
+		assert.NotContains(t, mockAg.lastContextText, "reply_language:")
+	})
+}
+
 // =============================================================================
 // Context Format Verification Tests
 // =============================================================================
@@ -926,4 +1439,206 @@ func TestHandleMessage_ContextFormat(t *testing.T) {
 		assert.Contains(t, context, "chat_type: 1-on-1", "should contain chat type")
 		assert.NotContains(t, context, "user_count:", "should not contain user_count for 1:1 chat")
 	})
+
+	t.Run("context contains current date-time in JST", func(t *testing.T) {
+		mockAg := &mockAgent{response: "Hello!"}
+		h := newTestHandler(t).WithAgent(mockAg).Build()
+
+		jst := time.FixedZone("JST", 9*60*60)
+		before := time.Now().In(jst).Truncate(time.Second)
+		ctx := withLineContext(t.Context(), "reply-token", "user-123", "user-123")
+		err := h.HandleText(ctx, "test-msg-id", "Hi!")
+		after := time.Now().In(jst)
+
+		require.NoError(t, err)
+		m := regexp.MustCompile(`current_local_time: (\S+) \((\w{3})\)`).FindStringSubmatch(mockAg.lastContextText)
+		require.Len(t, m, 3, "current_local_time should be RFC3339 followed by weekday")
+		got, err := time.Parse(time.RFC3339, m[1])
+		require.NoError(t, err)
+		assert.True(t, strings.HasSuffix(m[1], "+09:00"), "should be in JST")
+		assert.False(t, got.Before(before) || got.After(after), "should be the current time")
+		assert.Equal(t, got.In(jst).Format("Mon"), m[2])
+	})
+}
+
+// =============================================================================
+// Reply Mode Tests
+// =============================================================================
+
+func TestHandleMessage_InjectedClock(t *testing.T) {
+	t.Run("fixes the turn's time from the configured clock", func(t *testing.T) {
+		simulated := time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC)
+		mockAg := &mockAgent{response: "Hi!"}
+		h := newTestHandler(t).
+			WithAgent(mockAg).
+			WithNow(func() time.Time { return simulated }).
+			Build()
+
+		ctx := withLineContext(t.Context(), "reply-token", "user-123", "user-123")
+		err := h.HandleText(ctx, "test-msg-id", "What time is it?")
+
+		require.NoError(t, err)
+		assert.True(t, simulated.Equal(mockAg.lastNow), "got %v", mockAg.lastNow)
+	})
+
+	t.Run("defaults to the real clock", func(t *testing.T) {
+		mockAg := &mockAgent{response: "Hi!"}
+		h := newTestHandler(t).WithAgent(mockAg).Build()
+
+		ctx := withLineContext(t.Context(), "reply-token", "user-123", "user-123")
+		err := h.HandleText(ctx, "test-msg-id", "What time is it?")
+
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now(), mockAg.lastNow, time.Minute)
+	})
+}
+
+func TestHandleMessage_ReplyMode(t *testing.T) {
+	mentionOnly := func() *mockGroupProfileService {
+		return &mockGroupProfileService{
+			profile: &groupprofile.GroupProfile{
+				DisplayName: "Test Group",
+				ReplyMode:   groupprofile.ReplyModeMentionOnly,
+			},
+		}
+	}
+
+	t.Run("mention_only skips group messages that do not mention the bot", func(t *testing.T) {
+		mockAg := &mockAgent{response: "Hello group!"}
+		h := newTestHandler(t).
+			WithGroupProfile(mentionOnly()).
+			WithAgent(mockAg).
+			Build()
+
+		ctx := withLineContext(t.Context(), "reply-token", "group-789", "user-123")
+		err := h.HandleText(ctx, "test-msg-id", "Hi everyone!")
+
+		require.NoError(t, err)
+		assert.Empty(t, mockAg.lastUserMessageText, "agent should not be called")
+	})
+
+	t.Run("mention_only processes group messages that mention the bot", func(t *testing.T) {
+		mockAg := &mockAgent{response: "Hello group!"}
+		h := newTestHandler(t).
+			WithGroupProfile(mentionOnly()).
+			WithAgent(mockAg).
+			Build()
+
+		ctx := withLineContext(t.Context(), "reply-token", "group-789", "user-123")
+		ctx = line.WithBotMentioned(ctx, true)
+		err := h.HandleText(ctx, "test-msg-id", "@Yuruppu hi!")
+
+		require.NoError(t, err)
+		assert.Contains(t, mockAg.lastUserMessageText, "@Yuruppu hi!")
+	})
+
+	t.Run("always processes group messages without a mention", func(t *testing.T) {
+		mockAg := &mockAgent{response: "Hello group!"}
+		h := newTestHandler(t).
+			WithGroupProfile(&mockGroupProfileService{
+				profile: &groupprofile.GroupProfile{ReplyMode: groupprofile.ReplyModeAlways},
+			}).
+			WithAgent(mockAg).
+			Build()
+
+		ctx := withLineContext(t.Context(), "reply-token", "group-789", "user-123")
+		err := h.HandleText(ctx, "test-msg-id", "Hi everyone!")
+
+		require.NoError(t, err)
+		assert.Contains(t, mockAg.lastUserMessageText, "Hi everyone!")
+	})
+
+	t.Run("one-on-one messages are always processed", func(t *testing.T) {
+		mockAg := &mockAgent{response: "Hello!"}
+		h := newTestHandler(t).
+			WithGroupProfile(mentionOnly()).
+			WithAgent(mockAg).
+			Build()
+
+		ctx := withLineContext(t.Context(), "reply-token", "user-123", "user-123")
+		err := h.HandleText(ctx, "test-msg-id", "Hi!")
+
+		require.NoError(t, err)
+		assert.Contains(t, mockAg.lastUserMessageText, "Hi!")
+	})
+}
+
+func TestHandleMessage_BotPresence(t *testing.T) {
+	t.Run("processes messages in groups the bot is in", func(t *testing.T) {
+		mockAg := &mockAgent{response: "Hello group!"}
+		h := newTestHandler(t).
+			WithInitialGroupProfile(&groupprofile.GroupProfile{DisplayName: "Test Group"}).
+			WithSkipInactiveGroups().
+			WithAgent(mockAg).
+			Build()
+
+		ctx := withLineContext(t.Context(), "reply-token", "group-789", "user-123")
+		err := h.HandleText(ctx, "test-msg-id", "Hi everyone!")
+
+		require.NoError(t, err)
+		assert.Contains(t, mockAg.lastUserMessageText, "Hi everyone!")
+	})
+
+	t.Run("skips messages in groups the bot is marked as having left", func(t *testing.T) {
+		mockAg := &mockAgent{response: "Hello group!"}
+		storage := newMockStorage()
+		h := newTestHandler(t).
+			WithInitialGroupProfile(&groupprofile.GroupProfile{DisplayName: "Test Group", Inactive: true}).
+			WithSkipInactiveGroups().
+			WithAgent(mockAg).
+			WithStorage(storage).
+			Build()
+
+		ctx := withLineContext(t.Context(), "reply-token", "group-789", "user-123")
+		ctx = line.WithBotMentioned(ctx, true)
+		err := h.HandleText(ctx, "test-msg-id", "@Yuruppu hi!")
+
+		require.NoError(t, err)
+		assert.Empty(t, mockAg.lastUserMessageText, "agent should not be called")
+		assert.Empty(t, storage.data, "history should not be saved")
+	})
+
+	t.Run("processes messages in inactive groups when the check is off", func(t *testing.T) {
+		mockAg := &mockAgent{response: "Hello group!"}
+		h := newTestHandler(t).
+			WithInitialGroupProfile(&groupprofile.GroupProfile{DisplayName: "Test Group", Inactive: true}).
+			WithAgent(mockAg).
+			Build()
+
+		ctx := withLineContext(t.Context(), "reply-token", "group-789", "user-123")
+		err := h.HandleText(ctx, "test-msg-id", "Hi everyone!")
+
+		require.NoError(t, err)
+		assert.Contains(t, mockAg.lastUserMessageText, "Hi everyone!")
+	})
+
+	t.Run("processes messages when the group profile is unavailable", func(t *testing.T) {
+		mockAg := &mockAgent{response: "Hello group!"}
+		h := newTestHandler(t).
+			WithGroupProfileError(errors.New("profile not found"), nil).
+			WithSkipInactiveGroups().
+			WithAgent(mockAg).
+			Build()
+
+		ctx := withLineContext(t.Context(), "reply-token", "group-789", "user-123")
+		err := h.HandleText(ctx, "test-msg-id", "Hi everyone!")
+
+		require.NoError(t, err)
+		assert.Contains(t, mockAg.lastUserMessageText, "Hi everyone!")
+	})
+
+	t.Run("one-on-one messages are unaffected", func(t *testing.T) {
+		mockAg := &mockAgent{response: "Hello!"}
+		h := newTestHandler(t).
+			WithInitialGroupProfile(&groupprofile.GroupProfile{Inactive: true}).
+			WithSkipInactiveGroups().
+			WithAgent(mockAg).
+			Build()
+
+		ctx := withLineContext(t.Context(), "reply-token", "user-123", "user-123")
+		err := h.HandleText(ctx, "test-msg-id", "Hi!")
+
+		require.NoError(t, err)
+		assert.Contains(t, mockAg.lastUserMessageText, "Hi!")
+	})
 }
@@ -0,0 +1,69 @@
+package bot
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	defaultPostbackNonceTTL = 24 * time.Hour
+	maxPostbackNonces       = 10000
+)
+
+// nonceCache remembers consumed postback nonces for a bounded time.
+type nonceCache struct {
+	mu   sync.Mutex
+	ttl  time.Duration
+	max  int
+	seen map[string]time.Time // nonce -> expiry
+}
+
+func newNonceCache(ttl time.Duration, maxEntries int) *nonceCache {
+	return &nonceCache{
+		ttl:  ttl,
+		max:  maxEntries,
+		seen: make(map[string]time.Time),
+	}
+}
+
+// consume marks nonce as used. Returns false if it was already used and has not expired.
+// The mark is taken atomically, so concurrent deliveries of the same nonce are processed once.
+func (c *nonceCache) consume(nonce string, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if expiry, ok := c.seen[nonce]; ok && now.Before(expiry) {
+		return false
+	}
+
+	for k, expiry := range c.seen {
+		if !now.Before(expiry) {
+			delete(c.seen, k)
+		}
+	}
+	if len(c.seen) >= c.max {
+		c.evictOldest()
+	}
+
+	c.seen[nonce] = now.Add(c.ttl)
+	return true
+}
+
+// release forgets nonce so a postback whose handling failed can be sent again.
+func (c *nonceCache) release(nonce string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.seen, nonce)
+}
+
+// evictOldest removes the entry closest to expiry.
+func (c *nonceCache) evictOldest() {
+	var oldestKey string
+	var oldest time.Time
+	for k, expiry := range c.seen {
+		if oldestKey == "" || expiry.Before(oldest) {
+			oldestKey, oldest = k, expiry
+		}
+	}
+	delete(c.seen, oldestKey)
+}
@@ -0,0 +1,60 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"yuruppu/internal/history"
+	"yuruppu/internal/line"
+)
+
+// HandlePostback forwards a postback to the agent as a user message.
+// Picker values are rendered in RFC3339 so the agent can pass them to tools as-is.
+// Postbacks sealed with line.AddPostbackNonce are processed at most once per user; replays are ignored.
+// The nonce is keyed by user because a card shown in a group is tapped by several members,
+// and it is released when handling fails so the user can tap the button again.
+func (h *Handler) HandlePostback(ctx context.Context, data string) error {
+	userID, ok := line.UserIDFromContext(ctx)
+	if !ok {
+		return errors.New("userID not found in context")
+	}
+
+	data, nonce := line.SplitPostbackNonce(data)
+	nonceKey := userID + ":" + nonce
+	if nonce != "" && !h.postbackNonces.consume(nonceKey, time.Now()) {
+		h.logger.InfoContext(ctx, "ignoring replayed postback",
+			slog.String("userID", userID),
+			slog.String("data", data),
+		)
+		return nil
+	}
+
+	lines := []string{fmt.Sprintf("[User sent a postback: %s]", data)}
+	if params, ok := line.PostbackParamsFromContext(ctx); ok {
+		if params.DateTime != nil {
+			lines = append(lines, fmt.Sprintf("[User selected a date and time: %s]", params.DateTime.Format(time.RFC3339)))
+		}
+		if params.Date != nil {
+			lines = append(lines, fmt.Sprintf("[User selected a date: %s]", params.Date.Format(time.DateOnly)))
+		}
+		if params.Time != nil {
+			lines = append(lines, fmt.Sprintf("[User selected a time: %s]", params.Time.Format("15:04")))
+		}
+	}
+
+	userMsg := &history.UserMessage{
+		UserID:    userID,
+		Parts:     []history.UserPart{&history.UserTextPart{Text: strings.Join(lines, "\n")}},
+		Timestamp: time.Now(),
+	}
+	if err := h.handleMessage(ctx, userMsg); err != nil {
+		if nonce != "" {
+			h.postbackNonces.release(nonceKey)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package bot_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"yuruppu/internal/line"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// HandlePostback Tests
+// =============================================================================
+
+func TestHandler_HandlePostback(t *testing.T) {
+	t.Run("forwards postback data to agent", func(t *testing.T) {
+		mockAg := &mockAgent{response: "OK"}
+		h := newTestHandler(t).WithAgent(mockAg).Build()
+
+		ctx := withLineContext(t.Context(), "reply-token", "group-123", "user-456")
+		err := h.HandlePostback(ctx, "action=menu")
+
+		require.NoError(t, err)
+		assert.Equal(t, "[User sent a postback: action=menu]", mockAg.lastUserMessageText)
+	})
+
+	t.Run("renders picked datetime in RFC3339", func(t *testing.T) {
+		mockAg := &mockAgent{response: "OK"}
+		h := newTestHandler(t).WithAgent(mockAg).Build()
+
+		picked := time.Date(2025, 12, 25, 19, 30, 0, 0, time.FixedZone("JST", 9*60*60))
+		ctx := withLineContext(t.Context(), "reply-token", "group-123", "user-456")
+		ctx = line.WithPostbackParams(ctx, line.PostbackParams{DateTime: &picked})
+		err := h.HandlePostback(ctx, "action=create_event")
+
+		require.NoError(t, err)
+		assert.Contains(t, mockAg.lastUserMessageText, "[User selected a date and time: 2025-12-25T19:30:00+09:00]")
+	})
+
+	t.Run("ignores second postback with the same nonce", func(t *testing.T) {
+		mockAg := &mockAgent{response: "OK"}
+		h := newTestHandler(t).WithAgent(mockAg).Build()
+
+		ctx := withLineContext(t.Context(), "reply-token", "group-123", "user-456")
+		err := h.HandlePostback(ctx, "action=rsvp&nonce=abc123")
+		require.NoError(t, err)
+		assert.Equal(t, "[User sent a postback: action=rsvp]", mockAg.lastUserMessageText)
+
+		mockAg.lastUserMessageText = ""
+		err = h.HandlePostback(ctx, "action=rsvp&nonce=abc123")
+
+		require.NoError(t, err)
+		assert.Empty(t, mockAg.lastUserMessageText)
+	})
+
+	t.Run("processes postback with a fresh nonce", func(t *testing.T) {
+		mockAg := &mockAgent{response: "OK"}
+		h := newTestHandler(t).WithAgent(mockAg).Build()
+
+		ctx := withLineContext(t.Context(), "reply-token", "group-123", "user-456")
+		first, err := line.AddPostbackNonce("action=rsvp")
+		require.NoError(t, err)
+		require.NoError(t, h.HandlePostback(ctx, first))
+
+		mockAg.lastUserMessageText = ""
+		second, err := line.AddPostbackNonce("action=rsvp")
+		require.NoError(t, err)
+		err = h.HandlePostback(ctx, second)
+
+		require.NoError(t, err)
+		assert.Equal(t, "[User sent a postback: action=rsvp]", mockAg.lastUserMessageText)
+	})
+
+	t.Run("processes the same nonce from a different user", func(t *testing.T) {
+		mockAg := &mockAgent{response: "OK"}
+		h := newTestHandler(t).WithAgent(mockAg).Build()
+
+		ctx := withLineContext(t.Context(), "reply-token", "group-123", "user-456")
+		require.NoError(t, h.HandlePostback(ctx, "action=join_event&nonce=abc123"))
+
+		mockAg.lastUserMessageText = ""
+		other := withLineContext(t.Context(), "reply-token", "group-123", "user-789")
+		err := h.HandlePostback(other, "action=join_event&nonce=abc123")
+
+		require.NoError(t, err)
+		assert.Equal(t, "[User sent a postback: action=join_event]", mockAg.lastUserMessageText)
+	})
+
+	t.Run("processes the same nonce again after handling failed", func(t *testing.T) {
+		mockAg := &mockAgent{err: errors.New("LLM failed")}
+		h := newTestHandler(t).WithAgent(mockAg).Build()
+
+		ctx := withLineContext(t.Context(), "reply-token", "group-123", "user-456")
+		require.Error(t, h.HandlePostback(ctx, "action=join_event&nonce=abc123"))
+
+		mockAg.err = nil
+		mockAg.response = "OK"
+		mockAg.lastUserMessageText = ""
+		err := h.HandlePostback(ctx, "action=join_event&nonce=abc123")
+
+		require.NoError(t, err)
+		assert.Equal(t, "[User sent a postback: action=join_event]", mockAg.lastUserMessageText)
+	})
+
+	t.Run("processes repeated postbacks without a nonce", func(t *testing.T) {
+		mockAg := &mockAgent{response: "OK"}
+		h := newTestHandler(t).WithAgent(mockAg).Build()
+
+		ctx := withLineContext(t.Context(), "reply-token", "group-123", "user-456")
+		require.NoError(t, h.HandlePostback(ctx, "action=vote&option=1"))
+
+		mockAg.lastUserMessageText = ""
+		err := h.HandlePostback(ctx, "action=vote&option=1")
+
+		require.NoError(t, err)
+		assert.Equal(t, "[User sent a postback: action=vote&option=1]", mockAg.lastUserMessageText)
+	})
+
+	t.Run("returns error when userID not in context", func(t *testing.T) {
+		h := newTestHandler(t).Build()
+
+		err := h.HandlePostback(t.Context(), "action=menu")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "userID not found")
+	})
+}
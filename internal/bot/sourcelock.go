@@ -0,0 +1,57 @@
+package bot
+
+import (
+	"context"
+	"sync"
+)
+
+// sourceLocks serializes conversation turns per source ID so that concurrent
+// messages in one chat do not race on its history. Different sources never block each other.
+// An entry lives only while a turn holds or waits for it, so idle sources use no memory.
+type sourceLocks struct {
+	mu    sync.Mutex
+	locks map[string]*sourceLock
+}
+
+type sourceLock struct {
+	held chan struct{} // capacity 1; full while the lock is held
+	refs int           // turns holding or waiting for the lock
+}
+
+func newSourceLocks() *sourceLocks {
+	return &sourceLocks{locks: make(map[string]*sourceLock)}
+}
+
+// lock waits until no other turn holds sourceID's lock.
+// Returns a function that releases the lock, or ctx's error if ctx is done first.
+func (l *sourceLocks) lock(ctx context.Context, sourceID string) (func(), error) {
+	l.mu.Lock()
+	sl, ok := l.locks[sourceID]
+	if !ok {
+		sl = &sourceLock{held: make(chan struct{}, 1)}
+		l.locks[sourceID] = sl
+	}
+	sl.refs++
+	l.mu.Unlock()
+
+	select {
+	case sl.held <- struct{}{}:
+		return func() {
+			<-sl.held
+			l.release(sourceID, sl)
+		}, nil
+	case <-ctx.Done():
+		l.release(sourceID, sl)
+		return nil, ctx.Err()
+	}
+}
+
+// release drops a reference to sl and evicts it once no turn holds or waits for it.
+func (l *sourceLocks) release(sourceID string, sl *sourceLock) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	sl.refs--
+	if sl.refs == 0 {
+		delete(l.locks, sourceID)
+	}
+}
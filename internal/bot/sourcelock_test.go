@@ -0,0 +1,190 @@
+package bot_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/bot"
+	"yuruppu/internal/history"
+	"yuruppu/internal/userprofile"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// Per-Source Turn Locking Tests
+// =============================================================================
+
+func TestHandler_TurnLocking(t *testing.T) {
+	t.Run("serializes turns for the same source", func(t *testing.T) {
+		storage := newSyncStorage()
+		ag := &concurrencyAgent{hold: 50 * time.Millisecond}
+		h, historyRepo := newTurnLockHandler(t, storage, ag)
+
+		var wg sync.WaitGroup
+		for _, text := range []string{"first", "second"} {
+			wg.Go(func() {
+				ctx := withLineContext(t.Context(), "reply-token", "user-1", "user-1")
+				assert.NoError(t, h.HandleText(ctx, "msg-"+text, text))
+			})
+		}
+		wg.Wait()
+
+		assert.Equal(t, 1, ag.maxActive)
+		assert.Equal(t, 2, ag.calls)
+		hist, _, err := historyRepo.GetHistory(t.Context(), "user-1")
+		require.NoError(t, err)
+		assert.Len(t, hist, 2, "both user messages should be saved without lost updates")
+	})
+
+	t.Run("runs turns for different sources concurrently", func(t *testing.T) {
+		storage := newSyncStorage()
+		ag := &concurrencyAgent{waitFor: 2, hold: time.Second}
+		h, _ := newTurnLockHandler(t, storage, ag)
+
+		var wg sync.WaitGroup
+		for _, sourceID := range []string{"user-1", "user-2"} {
+			wg.Go(func() {
+				ctx := withLineContext(t.Context(), "reply-token", sourceID, sourceID)
+				assert.NoError(t, h.HandleText(ctx, "msg-"+sourceID, "hello"))
+			})
+		}
+		wg.Wait()
+
+		assert.Equal(t, 2, ag.maxActive)
+	})
+
+	t.Run("gives up waiting when the context ends", func(t *testing.T) {
+		storage := newSyncStorage()
+		ag := &concurrencyAgent{hold: 200 * time.Millisecond}
+		h, _ := newTurnLockHandler(t, storage, ag)
+
+		started := make(chan struct{})
+		ag.onStart = func() { close(started) }
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			ctx := withLineContext(t.Context(), "reply-token", "user-1", "user-1")
+			assert.NoError(t, h.HandleText(ctx, "msg-1", "first"))
+		}()
+		<-started
+
+		ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+		defer cancel()
+		err := h.HandleText(withLineContext(ctx, "reply-token", "user-1", "user-1"), "msg-2", "second")
+
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		<-done
+		assert.Equal(t, 1, ag.calls)
+	})
+}
+
+// =============================================================================
+// Helpers
+// =============================================================================
+
+func newTurnLockHandler(t *testing.T, storage *syncStorage, ag *concurrencyAgent) (*bot.Handler, *history.Service) {
+	t.Helper()
+	historyRepo, err := history.NewService(storage)
+	require.NoError(t, err)
+	h, err := bot.NewHandler(&mockLineClient{}, fixedProfileService{}, &mockGroupProfileService{}, historyRepo, &mockMediaService{}, ag, validHandlerConfig(), slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+	return h, historyRepo
+}
+
+// concurrencyAgent records how many Generate calls overlap.
+// Each call waits until waitFor calls are active (if set) or hold elapses.
+type concurrencyAgent struct {
+	waitFor int
+	hold    time.Duration
+	onStart func()
+
+	mu        sync.Mutex
+	active    int
+	maxActive int
+	calls     int
+	peers     chan struct{}
+}
+
+func (a *concurrencyAgent) Generate(ctx context.Context, hist []agent.Message) (*agent.AssistantMessage, error) {
+	a.mu.Lock()
+	a.calls++
+	a.active++
+	a.maxActive = max(a.maxActive, a.active)
+	if a.peers == nil {
+		a.peers = make(chan struct{})
+	}
+	if a.waitFor > 0 && a.active == a.waitFor {
+		close(a.peers)
+	}
+	peers := a.peers
+	onStart := a.onStart
+	a.mu.Unlock()
+	if onStart != nil {
+		onStart()
+	}
+
+	if a.waitFor > 0 {
+		select {
+		case <-peers:
+		case <-time.After(a.hold):
+		}
+	} else {
+		time.Sleep(a.hold)
+	}
+
+	a.mu.Lock()
+	a.active--
+	a.mu.Unlock()
+	return &agent.AssistantMessage{
+		Parts: []agent.AssistantPart{&agent.AssistantTextPart{Text: "ok"}},
+	}, nil
+}
+
+// fixedProfileService returns the same profile for every user and is safe for concurrent use.
+type fixedProfileService struct{}
+
+func (fixedProfileService) GetUserProfile(ctx context.Context, userID string) (*userprofile.UserProfile, error) {
+	return &userprofile.UserProfile{DisplayName: "Test User"}, nil
+}
+
+func (fixedProfileService) SetUserProfile(ctx context.Context, userID string, p *userprofile.UserProfile) error {
+	return nil
+}
+
+func newSyncStorage() *syncStorage {
+	return &syncStorage{data: make(map[string][]byte), generation: make(map[string]int64)}
+}
+
+// syncStorage is a history storage that is safe for concurrent use and enforces generations.
+type syncStorage struct {
+	mu         sync.Mutex
+	data       map[string][]byte
+	generation map[string]int64
+}
+
+func (s *syncStorage) Read(ctx context.Context, key string) ([]byte, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data[key], s.generation[key], nil
+}
+
+func (s *syncStorage) Write(ctx context.Context, key, mimetype string, data []byte, expectedGeneration int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.generation[key] != expectedGeneration {
+		return 0, errors.New("generation mismatch")
+	}
+	s.data[key] = data
+	s.generation[key]++
+	return s.generation[key], nil
+}
+
+func (s *syncStorage) GetSignedURL(ctx context.Context, key, method string, ttl time.Duration) (string, error) {
+	return "https://example.com/signed/" + key, nil
+}
@@ -0,0 +1,23 @@
+// Package clock carries the current time of a request in its context,
+// so that every tool called while handling one message agrees on "now" and "today".
+package clock
+
+import (
+	"context"
+	"time"
+)
+
+type ctxKey struct{}
+
+// WithNow returns a new context whose Now is fixed to now.
+func WithNow(ctx context.Context, now time.Time) context.Context {
+	return context.WithValue(ctx, ctxKey{}, now)
+}
+
+// Now returns the time fixed by WithNow, or time.Now() if the context carries none.
+func Now(ctx context.Context) time.Time {
+	if now, ok := ctx.Value(ctxKey{}).(time.Time); ok {
+		return now
+	}
+	return time.Now()
+}
@@ -0,0 +1,28 @@
+package clock_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"yuruppu/internal/clock"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNow(t *testing.T) {
+	t.Run("returns the time fixed in the context", func(t *testing.T) {
+		fixed := time.Date(2026, 2, 15, 23, 59, 0, 0, time.UTC)
+		ctx := clock.WithNow(context.Background(), fixed)
+
+		assert.True(t, fixed.Equal(clock.Now(ctx)))
+		assert.True(t, fixed.Equal(clock.Now(ctx)), "repeated calls return the same time")
+	})
+
+	t.Run("falls back to the real clock", func(t *testing.T) {
+		before := time.Now()
+		now := clock.Now(context.Background())
+
+		assert.False(t, now.Before(before))
+		assert.WithinDuration(t, time.Now(), now, time.Second)
+	})
+}
@@ -4,12 +4,17 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"slices"
 	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
+	"yuruppu/internal/sanitize"
 )
 
 // Storage defines the storage interface required by event service.
@@ -20,17 +25,176 @@ type Storage interface {
 
 const storageKey = "all"
 
+// NotFoundError is returned when no event exists for the requested chat room.
+type NotFoundError struct {
+	ChatRoomID string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("event not found: %s", e.ChatRoomID)
+}
+
+// AlreadyExistsError is returned when creating an event in a chat room that already has one.
+type AlreadyExistsError struct {
+	ChatRoomID string
+}
+
+func (e *AlreadyExistsError) Error() string {
+	return fmt.Sprintf("event already exists: %s", e.ChatRoomID)
+}
+
+// NotAttendingError is returned when the user is neither attending nor waitlisted.
+type NotAttendingError struct {
+	ChatRoomID string
+	UserID     string
+}
+
+func (e *NotAttendingError) Error() string {
+	return fmt.Sprintf("user %s is not attending the event in %s", e.UserID, e.ChatRoomID)
+}
+
 // Event represents an event in a chat room.
 type Event struct {
-	ChatRoomID  string    `json:"chatRoomId"`
-	CreatorID   string    `json:"creatorId"`
-	Title       string    `json:"title"`
-	StartTime   time.Time `json:"startTime"`
-	EndTime     time.Time `json:"endTime"`
-	Fee         string    `json:"fee"`
-	Capacity    int       `json:"capacity"`
-	Description string    `json:"description"`
-	ShowCreator bool      `json:"showCreator"`
+	ChatRoomID      string         `json:"chatRoomId"`
+	CreatorID       string         `json:"creatorId"`
+	Title           string         `json:"title"`
+	StartTime       time.Time      `json:"startTime"`
+	EndTime         time.Time      `json:"endTime"`
+	Fee             string         `json:"fee"`
+	Capacity        int            `json:"capacity"` // 0 means unlimited
+	Description     string         `json:"description"`
+	ShowCreator     bool           `json:"showCreator"`
+	ImageURL        string         `json:"imageUrl,omitempty"`        // cover image shown on event cards; empty means none
+	Timezone        string         `json:"timezone,omitempty"`        // IANA name the event's times are shown in; empty means DefaultTimezone
+	Venue           string         `json:"venue,omitempty"`           // city or place the event is held in, used for weather forecasts; empty means unknown
+	LocationName    string         `json:"locationName,omitempty"`    // place name for the maps link on event cards; empty means none
+	Coordinates     *Coordinates   `json:"coordinates,omitempty"`     // exact position for the maps link, preferred over LocationName; nil means none
+	CreatorLocation string         `json:"creatorLocation,omitempty"` // where the creator says they are, used to report the current weather there; empty means unknown
+	Attendees       []string       `json:"attendees,omitempty"`
+	Waitlist        []string       `json:"waitlist,omitempty"`
+	Comments        []EventComment `json:"comments,omitempty"` // oldest first, at most MaxComments
+}
+
+// Coordinates is a position in decimal degrees (WGS 84).
+type Coordinates struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// Check returns an error if the latitude or longitude is out of range.
+func (c Coordinates) Check() error {
+	if c.Latitude < -90 || c.Latitude > 90 {
+		return fmt.Errorf("latitude must be between -90 and 90: %g", c.Latitude)
+	}
+	if c.Longitude < -180 || c.Longitude > 180 {
+		return fmt.Errorf("longitude must be between -180 and 180: %g", c.Longitude)
+	}
+	return nil
+}
+
+// EventComment is a note a chat member left on an event, such as a question for the organizer.
+type EventComment struct {
+	UserID    string    `json:"userId"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+const (
+	// MaxCommentLength is the longest comment accepted, in runes.
+	MaxCommentLength = 500
+	// MaxComments is how many comments an event keeps; older ones are dropped when more are added.
+	MaxComments = 100
+)
+
+// DefaultTimezone is the timezone of events that do not specify one.
+const DefaultTimezone = "Asia/Tokyo"
+
+// defaultLocation is DefaultTimezone as a fixed zone, so that it does not depend on the tz database.
+var defaultLocation = time.FixedZone(DefaultTimezone, 9*60*60)
+
+// ValidateTimezone returns an error if timezone is not a name in the tz database.
+// "Local" is rejected because it depends on the server rather than naming a region.
+func ValidateTimezone(timezone string) error {
+	if timezone == "" || timezone == "Local" {
+		return fmt.Errorf("invalid timezone: %q", timezone)
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return fmt.Errorf("invalid timezone: %w", err)
+	}
+	return nil
+}
+
+// Location returns the location the event's times are shown in.
+// It falls back to DefaultTimezone if Timezone is empty or unknown.
+func (e *Event) Location() *time.Location {
+	if e.Timezone != "" {
+		if loc, err := time.LoadLocation(e.Timezone); err == nil {
+			return loc
+		}
+	}
+	return defaultLocation
+}
+
+// TextLimits bounds the length of an event's title and description, counted in runes.
+// Event cards show both in full, and LINE rejects flex messages that grow too large.
+type TextLimits struct {
+	MaxTitle       int
+	MaxDescription int
+}
+
+// DefaultTextLimits are generous for any ordinary event while keeping a carousel of
+// event cards well within LINE's flex message size limit.
+var DefaultTextLimits = TextLimits{MaxTitle: 200, MaxDescription: 2000}
+
+// Validate returns an error if a limit is not positive.
+func (l TextLimits) Validate() error {
+	if l.MaxTitle <= 0 {
+		return errors.New("max title length must be positive")
+	}
+	if l.MaxDescription <= 0 {
+		return errors.New("max description length must be positive")
+	}
+	return nil
+}
+
+// CheckTitle returns an error naming the limit if title is longer than MaxTitle runes.
+func (l TextLimits) CheckTitle(title string) error {
+	if n := utf8.RuneCountInString(title); n > l.MaxTitle {
+		return fmt.Errorf("title is too long: %d characters, the maximum is %d", n, l.MaxTitle)
+	}
+	return nil
+}
+
+// CheckDescription returns an error naming the limit if description is longer than MaxDescription runes.
+func (l TextLimits) CheckDescription(description string) error {
+	if n := utf8.RuneCountInString(description); n > l.MaxDescription {
+		return fmt.Errorf("description is too long: %d characters, the maximum is %d", n, l.MaxDescription)
+	}
+	return nil
+}
+
+// CheckComment returns an error if text is empty or longer than MaxCommentLength runes.
+func CheckComment(text string) error {
+	n := utf8.RuneCountInString(text)
+	if n == 0 {
+		return errors.New("comment cannot be empty")
+	}
+	if n > MaxCommentLength {
+		return fmt.Errorf("comment is too long: %d characters, the maximum is %d", n, MaxCommentLength)
+	}
+	return nil
+}
+
+// CheckLeadTime returns an error if startTime is not in the future, or is less than minLeadTime after now.
+// A zero minLeadTime only requires the start to be in the future.
+func CheckLeadTime(startTime, now time.Time, minLeadTime time.Duration) error {
+	if !startTime.After(now) {
+		return errors.New("start_time must be in the future")
+	}
+	if startTime.Before(now.Add(minLeadTime)) {
+		return fmt.Errorf("start_time must be at least %d minutes from now", int(minLeadTime.Minutes()))
+	}
+	return nil
 }
 
 // ListOptions specifies filtering and pagination options for listing events.
@@ -41,22 +205,43 @@ type ListOptions struct {
 	Limit     int        // Max items to return (0 = no limit)
 }
 
+// Option configures optional Service behavior.
+type Option func(*Service)
+
+// WithLogger sets the logger used to report corrupt records.
+// Defaults to a logger that discards everything.
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *Service) {
+		s.logger = logger
+	}
+}
+
 // Service provides event management operations.
 type Service struct {
 	storage Storage
+	logger  *slog.Logger
 }
 
 // NewService creates a new Service with the given storage backend.
 // Returns error if storage is nil.
-func NewService(s Storage) (*Service, error) {
+func NewService(s Storage, opts ...Option) (*Service, error) {
 	if s == nil {
 		return nil, errors.New("storage cannot be nil")
 	}
-	return &Service{storage: s}, nil
+	svc := &Service{storage: s, logger: slog.New(slog.DiscardHandler)}
+	for _, opt := range opts {
+		opt(svc)
+	}
+	if svc.logger == nil {
+		return nil, errors.New("logger cannot be nil")
+	}
+	return svc, nil
 }
 
 // Create creates a new event.
-// Returns error if an event already exists for the chat room or if storage operations fail.
+// Control characters other than newline and tab are stripped from the title, fee, description, venue, and location name.
+// Returns error if EndTime is not after StartTime,
+// *AlreadyExistsError if an event already exists for the chat room, and error if storage operations fail.
 func (s *Service) Create(ctx context.Context, ev *Event) error {
 	if ev == nil {
 		return errors.New("event cannot be nil")
@@ -64,6 +249,20 @@ func (s *Service) Create(ctx context.Context, ev *Event) error {
 	if ev.ChatRoomID == "" {
 		return errors.New("chatRoomID cannot be empty")
 	}
+	if !ev.EndTime.After(ev.StartTime) {
+		return fmt.Errorf("end time %s must be after start time %s: %s", ev.EndTime.Format(time.RFC3339), ev.StartTime.Format(time.RFC3339), ev.ChatRoomID)
+	}
+	ev.Title = sanitize.Text(ev.Title)
+	ev.Fee = sanitize.Text(ev.Fee)
+	ev.Description = sanitize.Text(ev.Description)
+	ev.Venue = sanitize.Text(ev.Venue)
+	ev.LocationName = sanitize.Text(ev.LocationName)
+	ev.CreatorLocation = sanitize.Text(ev.CreatorLocation)
+	if ev.Coordinates != nil {
+		if err := ev.Coordinates.Check(); err != nil {
+			return err
+		}
+	}
 
 	// Read existing events
 	events, generation, err := s.readEvents(ctx)
@@ -74,7 +273,7 @@ func (s *Service) Create(ctx context.Context, ev *Event) error {
 	// Check for duplicate ChatRoomID
 	for _, existing := range events {
 		if existing.ChatRoomID == ev.ChatRoomID {
-			return fmt.Errorf("event already exists: %s", ev.ChatRoomID)
+			return &AlreadyExistsError{ChatRoomID: ev.ChatRoomID}
 		}
 	}
 
@@ -90,7 +289,7 @@ func (s *Service) Create(ctx context.Context, ev *Event) error {
 }
 
 // Get retrieves an event by chat room ID.
-// Returns error if the event is not found or if storage operations fail.
+// Returns *NotFoundError if the event is not found, or error if storage operations fail.
 func (s *Service) Get(ctx context.Context, chatRoomID string) (*Event, error) {
 	if chatRoomID == "" {
 		return nil, errors.New("chatRoomID cannot be empty")
@@ -107,7 +306,7 @@ func (s *Service) Get(ctx context.Context, chatRoomID string) (*Event, error) {
 		}
 	}
 
-	return nil, fmt.Errorf("event not found: %s", chatRoomID)
+	return nil, &NotFoundError{ChatRoomID: chatRoomID}
 }
 
 // List retrieves events with optional filtering and sorting.
@@ -134,8 +333,109 @@ func (s *Service) List(ctx context.Context, opts ListOptions) ([]*Event, error)
 	return filtered, nil
 }
 
+// DefaultPageSize is the page size ListPage uses when ListOptions.Limit is not positive.
+const DefaultPageSize = 50
+
+// pageCursor identifies the last event of a page by its sort key.
+type pageCursor struct {
+	StartTime  time.Time `json:"s"`
+	ChatRoomID string    `json:"c"`
+}
+
+// ListPage retrieves one page of events with the same filters and sort direction as List.
+// Events with equal StartTime are ordered by ChatRoomID, so the order is stable across calls.
+// The page holds at most opts.Limit events (DefaultPageSize if Limit is not positive),
+// whether or not Start and End are both specified.
+// Pass an empty cursor for the first page and the returned cursor for the next one;
+// the returned cursor is empty when there are no more events.
+// Because the cursor records the last event's position rather than an offset,
+// events created or removed between calls do not cause duplicates or gaps among the rest.
+// Returns error if cursor was not produced by ListPage.
+func (s *Service) ListPage(ctx context.Context, opts ListOptions, cursor string) ([]*Event, string, error) {
+	var after *pageCursor
+	if cursor != "" {
+		c, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		after = c
+	}
+
+	events, _, err := s.readEvents(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read events: %w", err)
+	}
+
+	filtered := filterEvents(events, opts)
+	descending := opts.End != nil && opts.Start == nil
+	less := func(a, b *Event) bool {
+		if !a.StartTime.Equal(b.StartTime) {
+			if descending {
+				return a.StartTime.After(b.StartTime)
+			}
+			return a.StartTime.Before(b.StartTime)
+		}
+		return a.ChatRoomID < b.ChatRoomID
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return less(filtered[i], filtered[j])
+	})
+
+	if after != nil {
+		key := &Event{StartTime: after.StartTime, ChatRoomID: after.ChatRoomID}
+		start := sort.Search(len(filtered), func(i int) bool {
+			return less(key, filtered[i])
+		})
+		filtered = filtered[start:]
+	}
+
+	size := opts.Limit
+	if size <= 0 {
+		size = DefaultPageSize
+	}
+	if len(filtered) <= size {
+		return filtered, "", nil
+	}
+
+	page := filtered[:size]
+	last := page[len(page)-1]
+	next, err := encodeCursor(pageCursor{StartTime: last.StartTime, ChatRoomID: last.ChatRoomID})
+	if err != nil {
+		return nil, "", err
+	}
+	return page, next, nil
+}
+
+// encodeCursor encodes c as an opaque URL-safe string.
+func encodeCursor(c pageCursor) (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor reverses encodeCursor.
+func decodeCursor(cursor string) (*pageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid page cursor %q: %w", cursor, err)
+	}
+	var c pageCursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("invalid page cursor %q: %w", cursor, err)
+	}
+	if c.ChatRoomID == "" {
+		return nil, fmt.Errorf("invalid page cursor %q: no chat room ID", cursor)
+	}
+	return &c, nil
+}
+
 // readEvents reads and parses events from storage.
 // Returns empty slice and generation 0 if no events exist.
+// Corrupt lines are skipped and logged; since writes serialize only the parsed events,
+// the next successful write drops them from storage.
+// Events whose EndTime is not after StartTime are returned as is and logged.
 func (s *Service) readEvents(ctx context.Context) ([]*Event, int64, error) {
 	data, generation, err := s.storage.Read(ctx, storageKey)
 	if err != nil {
@@ -146,10 +446,25 @@ func (s *Service) readEvents(ctx context.Context) ([]*Event, int64, error) {
 		return []*Event{}, generation, nil
 	}
 
-	events, err := parseJSONL(data)
+	events, skipped, err := parseJSONL(data)
 	if err != nil {
 		return nil, 0, err
 	}
+	if skipped > 0 {
+		s.logger.WarnContext(ctx, "skipped corrupt event records",
+			slog.Int("skipped", skipped),
+			slog.Int64("generation", generation),
+		)
+	}
+	for _, ev := range events {
+		if !ev.EndTime.After(ev.StartTime) {
+			s.logger.WarnContext(ctx, "stored event has invalid time range",
+				slog.String("chatRoomID", ev.ChatRoomID),
+				slog.Time("startTime", ev.StartTime),
+				slog.Time("endTime", ev.EndTime),
+			)
+		}
+	}
 
 	return events, generation, nil
 }
@@ -166,8 +481,10 @@ func (s *Service) writeEvents(ctx context.Context, events []*Event, expectedGene
 }
 
 // parseJSONL parses JSONL data into a slice of events.
-func parseJSONL(data []byte) ([]*Event, error) {
+// Lines that are not valid event JSON are skipped; their count is returned.
+func parseJSONL(data []byte) ([]*Event, int, error) {
 	var events []*Event
+	skipped := 0
 	scanner := bufio.NewScanner(bytes.NewReader(data))
 
 	for scanner.Scan() {
@@ -178,16 +495,17 @@ func parseJSONL(data []byte) ([]*Event, error) {
 
 		var ev Event
 		if err := json.Unmarshal([]byte(line), &ev); err != nil {
-			return nil, err
+			skipped++
+			continue
 		}
 		events = append(events, &ev)
 	}
 
 	if err := scanner.Err(); err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	return events, nil
+	return events, skipped, nil
 }
 
 // serializeJSONL serializes events to JSONL format.
@@ -262,7 +580,8 @@ func applyLimit(events *[]*Event, opts ListOptions) {
 }
 
 // Update updates the description of an existing event.
-// Returns error if the event is not found or if storage operations fail.
+// Control characters other than newline and tab are stripped from the description.
+// Returns *NotFoundError if the event is not found, or error if storage operations fail.
 func (s *Service) Update(ctx context.Context, chatRoomID string, description string) error {
 	if chatRoomID == "" {
 		return errors.New("chatRoomID cannot be empty")
@@ -276,14 +595,118 @@ func (s *Service) Update(ctx context.Context, chatRoomID string, description str
 	found := false
 	for _, ev := range events {
 		if ev.ChatRoomID == chatRoomID {
-			ev.Description = description
+			ev.Description = sanitize.Text(description)
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return &NotFoundError{ChatRoomID: chatRoomID}
+	}
+
+	if err := s.writeEvents(ctx, events, generation); err != nil {
+		return fmt.Errorf("failed to write events: %w", err)
+	}
+
+	return nil
+}
+
+// Transfer makes newCreatorID the creator of an existing event.
+// The creator location is cleared because it described where the previous creator was.
+// The write is conditioned on the generation that was read, so a concurrent change makes it fail.
+// Returns *NotFoundError if the event does not exist.
+func (s *Service) Transfer(ctx context.Context, chatRoomID, newCreatorID string) error {
+	if chatRoomID == "" {
+		return errors.New("chatRoomID cannot be empty")
+	}
+	if newCreatorID == "" {
+		return errors.New("newCreatorID cannot be empty")
+	}
+
+	events, generation, err := s.readEvents(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read events: %w", err)
+	}
+
+	found := false
+	for _, ev := range events {
+		if ev.ChatRoomID == chatRoomID {
+			ev.CreatorID = newCreatorID
+			ev.CreatorLocation = ""
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return &NotFoundError{ChatRoomID: chatRoomID}
+	}
+
+	if err := s.writeEvents(ctx, events, generation); err != nil {
+		return fmt.Errorf("failed to write events: %w", err)
+	}
+
+	return nil
+}
+
+// ToggleShowCreator flips whether an existing event shows its creator and returns the new setting.
+// The write is conditioned on the generation that was read, so a concurrent change makes it fail
+// rather than flipping the flag twice.
+// Returns *NotFoundError if the event does not exist.
+func (s *Service) ToggleShowCreator(ctx context.Context, chatRoomID string) (bool, error) {
+	if chatRoomID == "" {
+		return false, errors.New("chatRoomID cannot be empty")
+	}
+
+	events, generation, err := s.readEvents(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to read events: %w", err)
+	}
+
+	var target *Event
+	for _, ev := range events {
+		if ev.ChatRoomID == chatRoomID {
+			target = ev
+			break
+		}
+	}
+	if target == nil {
+		return false, &NotFoundError{ChatRoomID: chatRoomID}
+	}
+	target.ShowCreator = !target.ShowCreator
+
+	if err := s.writeEvents(ctx, events, generation); err != nil {
+		return false, fmt.Errorf("failed to write events: %w", err)
+	}
+
+	return target.ShowCreator, nil
+}
+
+// SetImage sets the cover image URL of an existing event. An empty imageURL removes the image.
+// The URL is stored as given; callers are responsible for validating it.
+// Returns *NotFoundError if the event does not exist.
+func (s *Service) SetImage(ctx context.Context, chatRoomID, imageURL string) error {
+	if chatRoomID == "" {
+		return errors.New("chatRoomID cannot be empty")
+	}
+
+	events, generation, err := s.readEvents(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read events: %w", err)
+	}
+
+	found := false
+	for _, ev := range events {
+		if ev.ChatRoomID == chatRoomID {
+			ev.ImageURL = imageURL
 			found = true
 			break
 		}
 	}
 
 	if !found {
-		return fmt.Errorf("event not found: %s", chatRoomID)
+		return &NotFoundError{ChatRoomID: chatRoomID}
 	}
 
 	if err := s.writeEvents(ctx, events, generation); err != nil {
@@ -293,8 +716,53 @@ func (s *Service) Update(ctx context.Context, chatRoomID string, description str
 	return nil
 }
 
-// Remove removes an event from storage.
-// Returns error if the event is not found or if storage operations fail.
+// AddComment appends comment to an existing event, dropping the oldest comments beyond MaxComments.
+// Control characters other than newline and tab are stripped and surrounding whitespace is trimmed
+// before the text is checked with CheckComment.
+// The write is conditioned on the generation that was read, so a concurrent change makes it fail.
+// Returns *NotFoundError if the event does not exist.
+func (s *Service) AddComment(ctx context.Context, chatRoomID string, comment EventComment) error {
+	if chatRoomID == "" {
+		return errors.New("chatRoomID cannot be empty")
+	}
+	if comment.UserID == "" {
+		return errors.New("userID cannot be empty")
+	}
+	comment.Text = strings.TrimSpace(sanitize.Text(comment.Text))
+	if err := CheckComment(comment.Text); err != nil {
+		return err
+	}
+
+	events, generation, err := s.readEvents(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read events: %w", err)
+	}
+
+	found := false
+	for _, ev := range events {
+		if ev.ChatRoomID == chatRoomID {
+			ev.Comments = append(ev.Comments, comment)
+			if len(ev.Comments) > MaxComments {
+				ev.Comments = ev.Comments[len(ev.Comments)-MaxComments:]
+			}
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return &NotFoundError{ChatRoomID: chatRoomID}
+	}
+
+	if err := s.writeEvents(ctx, events, generation); err != nil {
+		return fmt.Errorf("failed to write events: %w", err)
+	}
+
+	return nil
+}
+
+// Remove removes an event from storage, along with its comments.
+// Returns *NotFoundError if the event is not found, or error if storage operations fail.
 func (s *Service) Remove(ctx context.Context, chatRoomID string) error {
 	if chatRoomID == "" {
 		return errors.New("chatRoomID cannot be empty")
@@ -316,7 +784,7 @@ func (s *Service) Remove(ctx context.Context, chatRoomID string) error {
 	}
 
 	if !found {
-		return fmt.Errorf("event not found: %s", chatRoomID)
+		return &NotFoundError{ChatRoomID: chatRoomID}
 	}
 
 	if err := s.writeEvents(ctx, newEvents, generation); err != nil {
@@ -325,3 +793,133 @@ func (s *Service) Remove(ctx context.Context, chatRoomID string) error {
 
 	return nil
 }
+
+// RemoveEndedBefore removes every event whose EndTime is before cutoff and returns their chat room IDs.
+// The removal is a single generation-checked write, so it fails rather than overwriting a concurrent change.
+// Storage is not written when no event qualifies.
+func (s *Service) RemoveEndedBefore(ctx context.Context, cutoff time.Time) ([]string, error) {
+	events, generation, err := s.readEvents(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read events: %w", err)
+	}
+
+	var removed []string
+	kept := make([]*Event, 0, len(events))
+	for _, ev := range events {
+		if ev.EndTime.Before(cutoff) {
+			removed = append(removed, ev.ChatRoomID)
+			continue
+		}
+		kept = append(kept, ev)
+	}
+
+	if len(removed) == 0 {
+		return nil, nil
+	}
+
+	if err := s.writeEvents(ctx, kept, generation); err != nil {
+		return nil, fmt.Errorf("failed to write events: %w", err)
+	}
+
+	return removed, nil
+}
+
+// AddAttendee signs a user up for an event.
+// The user becomes an attendee while the event has room and is waitlisted once it is full.
+// A user already attending or waitlisted keeps their place, and storage is not written.
+// Returns whether the user ended up on the waitlist.
+// Returns *NotFoundError if the event does not exist.
+func (s *Service) AddAttendee(ctx context.Context, chatRoomID, userID string) (bool, error) {
+	if chatRoomID == "" {
+		return false, errors.New("chatRoomID cannot be empty")
+	}
+	if userID == "" {
+		return false, errors.New("userID cannot be empty")
+	}
+
+	events, generation, err := s.readEvents(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to read events: %w", err)
+	}
+
+	var target *Event
+	for _, ev := range events {
+		if ev.ChatRoomID == chatRoomID {
+			target = ev
+			break
+		}
+	}
+	if target == nil {
+		return false, &NotFoundError{ChatRoomID: chatRoomID}
+	}
+
+	switch {
+	case slices.Contains(target.Attendees, userID):
+		return false, nil
+	case slices.Contains(target.Waitlist, userID):
+		return true, nil
+	}
+
+	waitlisted := target.Capacity > 0 && len(target.Attendees) >= target.Capacity
+	if waitlisted {
+		target.Waitlist = append(target.Waitlist, userID)
+	} else {
+		target.Attendees = append(target.Attendees, userID)
+	}
+
+	if err := s.writeEvents(ctx, events, generation); err != nil {
+		return false, fmt.Errorf("failed to write events: %w", err)
+	}
+
+	return waitlisted, nil
+}
+
+// RemoveAttendee withdraws a user from an event's attendees or waitlist.
+// If an attendee leaves and a spot opens up, the first waitlisted user is promoted.
+// Returns the promoted user's ID, or empty if nobody was promoted.
+// Returns *NotFoundError if the event does not exist and *NotAttendingError if the user is on neither list.
+func (s *Service) RemoveAttendee(ctx context.Context, chatRoomID, userID string) (string, error) {
+	if chatRoomID == "" {
+		return "", errors.New("chatRoomID cannot be empty")
+	}
+	if userID == "" {
+		return "", errors.New("userID cannot be empty")
+	}
+
+	events, generation, err := s.readEvents(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to read events: %w", err)
+	}
+
+	var target *Event
+	for _, ev := range events {
+		if ev.ChatRoomID == chatRoomID {
+			target = ev
+			break
+		}
+	}
+	if target == nil {
+		return "", &NotFoundError{ChatRoomID: chatRoomID}
+	}
+
+	promoted := ""
+	switch {
+	case slices.Contains(target.Attendees, userID):
+		target.Attendees = slices.DeleteFunc(target.Attendees, func(id string) bool { return id == userID })
+		if len(target.Waitlist) > 0 && (target.Capacity == 0 || len(target.Attendees) < target.Capacity) {
+			promoted = target.Waitlist[0]
+			target.Waitlist = target.Waitlist[1:]
+			target.Attendees = append(target.Attendees, promoted)
+		}
+	case slices.Contains(target.Waitlist, userID):
+		target.Waitlist = slices.DeleteFunc(target.Waitlist, func(id string) bool { return id == userID })
+	default:
+		return "", &NotAttendingError{ChatRoomID: chatRoomID, UserID: userID}
+	}
+
+	if err := s.writeEvents(ctx, events, generation); err != nil {
+		return "", fmt.Errorf("failed to write events: %w", err)
+	}
+
+	return promoted, nil
+}
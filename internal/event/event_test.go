@@ -1,9 +1,12 @@
 package event_test
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"log/slog"
 	"strings"
 	"testing"
 	"time"
@@ -179,6 +182,118 @@ func TestService_Create_InvalidInput(t *testing.T) {
 	})
 }
 
+func TestService_Create_TimeRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		startTime time.Time
+		endTime   time.Time
+		wantErr   bool
+	}{
+		{name: "end before start", startTime: testTime2, endTime: testTime1, wantErr: true},
+		{name: "equal times", startTime: testTime1, endTime: testTime1, wantErr: true},
+		{name: "end after start", startTime: testTime1, endTime: testTime2, wantErr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStorage()
+			svc, err := event.NewService(store)
+			require.NoError(t, err)
+
+			err = svc.Create(context.Background(), &event.Event{
+				ChatRoomID: "chatroom-001",
+				CreatorID:  "user-123",
+				Title:      "Test Event",
+				StartTime:  tt.startTime,
+				EndTime:    tt.endTime,
+			})
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "must be after start time")
+				assert.Equal(t, 0, store.writeCallCount)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, 1, store.writeCallCount)
+		})
+	}
+
+	t.Run("logs stored events with an invalid time range instead of failing", func(t *testing.T) {
+		stored, err := json.Marshal(&event.Event{ChatRoomID: "chatroom-bad", CreatorID: "user-123", Title: "Bad", StartTime: testTime2, EndTime: testTime1})
+		require.NoError(t, err)
+		store := newMockStorage()
+		store.data["all"] = append(stored, '\n')
+		store.generation["all"] = 1
+		var buf bytes.Buffer
+		svc, err := event.NewService(store, event.WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))))
+		require.NoError(t, err)
+
+		ev, err := svc.Get(context.Background(), "chatroom-bad")
+
+		require.NoError(t, err)
+		assert.Equal(t, "Bad", ev.Title)
+		assert.Contains(t, buf.String(), "stored event has invalid time range")
+		assert.Contains(t, buf.String(), "chatroom-bad")
+	})
+}
+
+func TestService_Create_Location(t *testing.T) {
+	t.Run("stores the location name and coordinates", func(t *testing.T) {
+		store := newMockStorage()
+		svc, err := event.NewService(store)
+		require.NoError(t, err)
+
+		err = svc.Create(context.Background(), &event.Event{
+			ChatRoomID:   "chatroom-001",
+			CreatorID:    "user-123",
+			Title:        "Test Event",
+			StartTime:    testTime1,
+			EndTime:      testTime2,
+			LocationName: "Yoyogi Park\x07",
+			Coordinates:  &event.Coordinates{Latitude: 35.6717, Longitude: 139.6949},
+		})
+		require.NoError(t, err)
+
+		got, err := svc.Get(context.Background(), "chatroom-001")
+		require.NoError(t, err)
+		assert.Equal(t, "Yoyogi Park", got.LocationName)
+		assert.Equal(t, &event.Coordinates{Latitude: 35.6717, Longitude: 139.6949}, got.Coordinates)
+	})
+
+	tests := []struct {
+		name        string
+		coordinates event.Coordinates
+		wantErr     string
+	}{
+		{name: "latitude too low", coordinates: event.Coordinates{Latitude: -90.5}, wantErr: "latitude must be between -90 and 90"},
+		{name: "latitude too high", coordinates: event.Coordinates{Latitude: 91}, wantErr: "latitude must be between -90 and 90"},
+		{name: "longitude too low", coordinates: event.Coordinates{Longitude: -181}, wantErr: "longitude must be between -180 and 180"},
+		{name: "longitude too high", coordinates: event.Coordinates{Longitude: 180.1}, wantErr: "longitude must be between -180 and 180"},
+	}
+
+	for _, tt := range tests {
+		t.Run("rejects "+tt.name, func(t *testing.T) {
+			store := newMockStorage()
+			svc, err := event.NewService(store)
+			require.NoError(t, err)
+
+			err = svc.Create(context.Background(), &event.Event{
+				ChatRoomID:  "chatroom-001",
+				CreatorID:   "user-123",
+				Title:       "Test Event",
+				StartTime:   testTime1,
+				EndTime:     testTime2,
+				Coordinates: &tt.coordinates,
+			})
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.Equal(t, 0, store.writeCallCount)
+		})
+	}
+}
+
 // AC-003: Cannot create duplicate event in same chat room (FR-004)
 func TestService_Create_DuplicateChatRoom(t *testing.T) {
 	t.Run("returns error when ChatRoomID already exists", func(t *testing.T) {
@@ -218,8 +333,7 @@ func TestService_Create_DuplicateChatRoom(t *testing.T) {
 		err = svc.Create(context.Background(), duplicate)
 
 		// Then: Should return error
-		require.Error(t, err)
-		assert.Contains(t, err.Error(), "already exists")
+		require.ErrorAs(t, err, new(*event.AlreadyExistsError))
 		assert.Contains(t, err.Error(), "chatroom-001")
 	})
 }
@@ -430,6 +544,7 @@ func TestService_Get_NotFound(t *testing.T) {
 
 		// Then: Should return error
 		require.Error(t, err)
+		require.ErrorAs(t, err, new(*event.NotFoundError))
 		assert.Contains(t, err.Error(), "event not found")
 		assert.Contains(t, err.Error(), "non-existent")
 		assert.Nil(t, got)
@@ -1092,6 +1207,145 @@ func TestService_List_StorageError(t *testing.T) {
 	})
 }
 
+func TestService_ListPage(t *testing.T) {
+	// Two events share testTime2 so that the ChatRoomID tie-break is exercised across the page boundary.
+	seed := func(t *testing.T) *mockStorage {
+		t.Helper()
+		store := newMockStorage()
+		events := []*event.Event{
+			{ChatRoomID: "chatroom-005", Title: "Event 5", StartTime: testTime4, EndTime: testTime5},
+			{ChatRoomID: "chatroom-003", Title: "Event 3", StartTime: testTime2, EndTime: testTime3},
+			{ChatRoomID: "chatroom-001", Title: "Event 1", StartTime: testTime1, EndTime: testTime2},
+			{ChatRoomID: "chatroom-004", Title: "Event 4", StartTime: testTime3, EndTime: testTime4},
+			{ChatRoomID: "chatroom-002", Title: "Event 2", StartTime: testTime2, EndTime: testTime3},
+		}
+		lines := make([]string, 0, len(events))
+		for _, ev := range events {
+			jsonData, _ := json.Marshal(ev)
+			lines = append(lines, string(jsonData))
+		}
+		store.data["all"] = []byte(strings.Join(lines, "\n"))
+		store.generation["all"] = 1
+		return store
+	}
+
+	ids := func(events []*event.Event) []string {
+		result := make([]string, 0, len(events))
+		for _, ev := range events {
+			result = append(result, ev.ChatRoomID)
+		}
+		return result
+	}
+
+	t.Run("pages through all events in two calls without duplicates or gaps", func(t *testing.T) {
+		svc, err := event.NewService(seed(t))
+		require.NoError(t, err)
+		opts := event.ListOptions{Limit: 3}
+
+		first, cursor, err := svc.ListPage(context.Background(), opts, "")
+		require.NoError(t, err)
+		require.NotEmpty(t, cursor)
+
+		second, cursor, err := svc.ListPage(context.Background(), opts, cursor)
+		require.NoError(t, err)
+		assert.Empty(t, cursor)
+
+		assert.Equal(t, []string{"chatroom-001", "chatroom-002", "chatroom-003"}, ids(first))
+		assert.Equal(t, []string{"chatroom-004", "chatroom-005"}, ids(second))
+	})
+
+	t.Run("pages in descending order when only End is specified", func(t *testing.T) {
+		svc, err := event.NewService(seed(t))
+		require.NoError(t, err)
+		end := testTime6
+		opts := event.ListOptions{End: &end, Limit: 2}
+
+		var got []string
+		cursor := ""
+		for {
+			page, next, err := svc.ListPage(context.Background(), opts, cursor)
+			require.NoError(t, err)
+			got = append(got, ids(page)...)
+			if next == "" {
+				break
+			}
+			cursor = next
+		}
+
+		assert.Equal(t, []string{"chatroom-005", "chatroom-004", "chatroom-002", "chatroom-003", "chatroom-001"}, got)
+	})
+
+	t.Run("does not repeat or skip events when one is created between calls", func(t *testing.T) {
+		store := seed(t)
+		svc, err := event.NewService(store)
+		require.NoError(t, err)
+		opts := event.ListOptions{Limit: 3}
+
+		first, cursor, err := svc.ListPage(context.Background(), opts, "")
+		require.NoError(t, err)
+
+		err = svc.Create(context.Background(), &event.Event{
+			ChatRoomID: "chatroom-000", Title: "Earlier", StartTime: testTime1.Add(-time.Hour), EndTime: testTime1,
+		})
+		require.NoError(t, err)
+
+		second, cursor, err := svc.ListPage(context.Background(), opts, cursor)
+		require.NoError(t, err)
+		assert.Empty(t, cursor)
+
+		assert.Equal(t, []string{"chatroom-001", "chatroom-002", "chatroom-003"}, ids(first))
+		assert.Equal(t, []string{"chatroom-004", "chatroom-005"}, ids(second))
+	})
+
+	t.Run("uses DefaultPageSize when Limit is not positive", func(t *testing.T) {
+		svc, err := event.NewService(seed(t))
+		require.NoError(t, err)
+
+		page, cursor, err := svc.ListPage(context.Background(), event.ListOptions{}, "")
+
+		require.NoError(t, err)
+		assert.Len(t, page, 5)
+		assert.Empty(t, cursor)
+	})
+
+	t.Run("bounds the page even when Start and End are both specified", func(t *testing.T) {
+		svc, err := event.NewService(seed(t))
+		require.NoError(t, err)
+		start, end := testTime1, testTime6
+
+		page, cursor, err := svc.ListPage(context.Background(), event.ListOptions{Start: &start, End: &end, Limit: 2}, "")
+
+		require.NoError(t, err)
+		assert.Len(t, page, 2)
+		assert.NotEmpty(t, cursor)
+	})
+
+	t.Run("returns error for a malformed cursor", func(t *testing.T) {
+		svc, err := event.NewService(seed(t))
+		require.NoError(t, err)
+
+		page, cursor, err := svc.ListPage(context.Background(), event.ListOptions{Limit: 2}, "not a cursor!")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid page cursor")
+		assert.Nil(t, page)
+		assert.Empty(t, cursor)
+	})
+
+	t.Run("returns error when storage read fails", func(t *testing.T) {
+		store := newMockStorage()
+		store.readErr = errors.New("storage read error")
+		svc, err := event.NewService(store)
+		require.NoError(t, err)
+
+		page, _, err := svc.ListPage(context.Background(), event.ListOptions{}, "")
+
+		require.Error(t, err)
+		assert.Nil(t, page)
+		assert.Contains(t, err.Error(), "failed to read")
+	})
+}
+
 // =============================================================================
 // Update Tests (FR-002, FR-003, FR-006, NFR-001)
 // =============================================================================
@@ -1407,6 +1661,448 @@ func TestService_Update_StorageErrors(t *testing.T) {
 	})
 }
 
+// =============================================================================
+// Corrupt Record Tests
+// =============================================================================
+
+func TestService_CorruptRecords(t *testing.T) {
+	newCorruptStore := func(t *testing.T) *mockStorage {
+		t.Helper()
+		first, err := json.Marshal(&event.Event{ChatRoomID: "chatroom-001", CreatorID: "user-123", Title: "First", StartTime: testTime1, EndTime: testTime2})
+		require.NoError(t, err)
+		second, err := json.Marshal(&event.Event{ChatRoomID: "chatroom-002", CreatorID: "user-456", Title: "Second", StartTime: testTime3, EndTime: testTime4})
+		require.NoError(t, err)
+		store := newMockStorage()
+		store.data["all"] = []byte(string(first) + "\n" + `{"chatRoomId":"chatroom-bad","title":` + "\n" + string(second) + "\n" + "not json\n")
+		store.generation["all"] = 1
+		return store
+	}
+
+	t.Run("returns valid events and skips corrupt lines", func(t *testing.T) {
+		var buf bytes.Buffer
+		svc, err := event.NewService(newCorruptStore(t), event.WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))))
+		require.NoError(t, err)
+
+		events, err := svc.List(context.Background(), event.ListOptions{})
+
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		assert.Equal(t, "chatroom-001", events[0].ChatRoomID)
+		assert.Equal(t, "chatroom-002", events[1].ChatRoomID)
+		assert.Contains(t, buf.String(), "skipped corrupt event records")
+		assert.Contains(t, buf.String(), `"skipped":2`)
+	})
+
+	t.Run("gets a valid event next to corrupt lines", func(t *testing.T) {
+		svc, err := event.NewService(newCorruptStore(t))
+		require.NoError(t, err)
+
+		ev, err := svc.Get(context.Background(), "chatroom-002")
+
+		require.NoError(t, err)
+		assert.Equal(t, "Second", ev.Title)
+	})
+
+	t.Run("next write drops corrupt lines", func(t *testing.T) {
+		store := newCorruptStore(t)
+		svc, err := event.NewService(store)
+		require.NoError(t, err)
+
+		require.NoError(t, svc.Update(context.Background(), "chatroom-001", "Updated"))
+
+		lines := strings.Split(strings.TrimSpace(string(store.lastWriteData)), "\n")
+		require.Len(t, lines, 2)
+		for _, line := range lines {
+			var ev event.Event
+			require.NoError(t, json.Unmarshal([]byte(line), &ev))
+		}
+	})
+
+	t.Run("returns error when logger option is nil", func(t *testing.T) {
+		svc, err := event.NewService(newMockStorage(), event.WithLogger(nil))
+
+		require.Error(t, err)
+		assert.Nil(t, svc)
+		assert.Contains(t, err.Error(), "logger cannot be nil")
+	})
+}
+
+// =============================================================================
+// Transfer Tests
+// =============================================================================
+
+func TestService_Transfer(t *testing.T) {
+	newStore := func() *mockStorage {
+		store := newMockStorage()
+		existingEvent := &event.Event{
+			ChatRoomID:      "chatroom-001",
+			CreatorID:       "user-123",
+			Title:           "Event",
+			StartTime:       testTime1,
+			EndTime:         testTime2,
+			Attendees:       []string{"user-456"},
+			CreatorLocation: "Sapporo",
+		}
+		existingJSON, _ := json.Marshal(existingEvent)
+		store.data["all"] = existingJSON
+		store.generation["all"] = 5
+		return store
+	}
+
+	t.Run("changes the creator and keeps other fields", func(t *testing.T) {
+		store := newStore()
+		svc, err := event.NewService(store)
+		require.NoError(t, err)
+
+		err = svc.Transfer(context.Background(), "chatroom-001", "user-456")
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(6), store.generation["all"])
+		var updated event.Event
+		require.NoError(t, json.Unmarshal([]byte(strings.TrimSpace(string(store.lastWriteData))), &updated))
+		assert.Equal(t, "user-456", updated.CreatorID)
+		assert.Equal(t, "Event", updated.Title)
+		assert.Equal(t, []string{"user-456"}, updated.Attendees)
+		assert.Empty(t, updated.CreatorLocation, "the previous creator's location should not carry over")
+	})
+
+	t.Run("creator filter reflects the transfer", func(t *testing.T) {
+		store := newStore()
+		svc, err := event.NewService(store)
+		require.NoError(t, err)
+
+		require.NoError(t, svc.Transfer(context.Background(), "chatroom-001", "user-456"))
+
+		oldCreator, newCreator := "user-123", "user-456"
+		events, err := svc.List(context.Background(), event.ListOptions{CreatorID: &oldCreator})
+		require.NoError(t, err)
+		assert.Empty(t, events)
+		events, err = svc.List(context.Background(), event.ListOptions{CreatorID: &newCreator})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, "chatroom-001", events[0].ChatRoomID)
+	})
+
+	t.Run("returns NotFoundError when event does not exist", func(t *testing.T) {
+		store := newStore()
+		svc, err := event.NewService(store)
+		require.NoError(t, err)
+
+		err = svc.Transfer(context.Background(), "chatroom-999", "user-456")
+
+		require.ErrorAs(t, err, new(*event.NotFoundError))
+		assert.Equal(t, 0, store.writeCallCount)
+	})
+
+	t.Run("returns error for empty arguments", func(t *testing.T) {
+		svc, err := event.NewService(newStore())
+		require.NoError(t, err)
+
+		require.Error(t, svc.Transfer(context.Background(), "", "user-456"))
+		require.Error(t, svc.Transfer(context.Background(), "chatroom-001", ""))
+	})
+
+	t.Run("fails on a concurrent write", func(t *testing.T) {
+		store := newStore()
+		store.simulateConcurrentWrite = true
+		svc, err := event.NewService(store)
+		require.NoError(t, err)
+
+		err1 := svc.Transfer(context.Background(), "chatroom-001", "user-456")
+		err2 := svc.Transfer(context.Background(), "chatroom-001", "user-789")
+
+		if err1 == nil {
+			require.Error(t, err2)
+			assert.Contains(t, err2.Error(), "generation mismatch")
+		} else {
+			require.NoError(t, err2)
+			assert.Contains(t, err1.Error(), "generation mismatch")
+		}
+	})
+}
+
+// =============================================================================
+// ToggleShowCreator Tests
+// =============================================================================
+
+func TestService_ToggleShowCreator(t *testing.T) {
+	newStore := func() *mockStorage {
+		store := newMockStorage()
+		existingEvent := &event.Event{
+			ChatRoomID:  "chatroom-001",
+			CreatorID:   "user-123",
+			Title:       "Event",
+			StartTime:   testTime1,
+			EndTime:     testTime2,
+			ShowCreator: true,
+		}
+		existingJSON, _ := json.Marshal(existingEvent)
+		store.data["all"] = existingJSON
+		store.generation["all"] = 5
+		return store
+	}
+
+	t.Run("flips the flag back and forth and keeps other fields", func(t *testing.T) {
+		store := newStore()
+		svc, err := event.NewService(store)
+		require.NoError(t, err)
+
+		show, err := svc.ToggleShowCreator(context.Background(), "chatroom-001")
+
+		require.NoError(t, err)
+		assert.False(t, show)
+		assert.Equal(t, int64(6), store.generation["all"])
+		ev, err := svc.Get(context.Background(), "chatroom-001")
+		require.NoError(t, err)
+		assert.False(t, ev.ShowCreator)
+		assert.Equal(t, "Event", ev.Title)
+
+		show, err = svc.ToggleShowCreator(context.Background(), "chatroom-001")
+
+		require.NoError(t, err)
+		assert.True(t, show)
+		ev, err = svc.Get(context.Background(), "chatroom-001")
+		require.NoError(t, err)
+		assert.True(t, ev.ShowCreator)
+	})
+
+	t.Run("returns NotFoundError when event does not exist", func(t *testing.T) {
+		store := newStore()
+		svc, err := event.NewService(store)
+		require.NoError(t, err)
+
+		_, err = svc.ToggleShowCreator(context.Background(), "chatroom-999")
+
+		require.ErrorAs(t, err, new(*event.NotFoundError))
+		assert.Equal(t, 0, store.writeCallCount)
+	})
+
+	t.Run("returns error for empty chatRoomID", func(t *testing.T) {
+		svc, err := event.NewService(newStore())
+		require.NoError(t, err)
+
+		_, err = svc.ToggleShowCreator(context.Background(), "")
+
+		require.Error(t, err)
+	})
+
+	t.Run("fails on a concurrent write", func(t *testing.T) {
+		store := newStore()
+		store.simulateConcurrentWrite = true
+		svc, err := event.NewService(store)
+		require.NoError(t, err)
+
+		_, err1 := svc.ToggleShowCreator(context.Background(), "chatroom-001")
+		_, err2 := svc.ToggleShowCreator(context.Background(), "chatroom-001")
+
+		if err1 == nil {
+			require.Error(t, err2)
+			assert.Contains(t, err2.Error(), "generation mismatch")
+		} else {
+			require.NoError(t, err2)
+			assert.Contains(t, err1.Error(), "generation mismatch")
+		}
+	})
+}
+
+// =============================================================================
+// SetImage Tests
+// =============================================================================
+
+func TestService_SetImage(t *testing.T) {
+	newStore := func() *mockStorage {
+		store := newMockStorage()
+		existingEvent := &event.Event{
+			ChatRoomID: "chatroom-001",
+			CreatorID:  "user-123",
+			Title:      "Event",
+			StartTime:  testTime1,
+			EndTime:    testTime2,
+		}
+		existingJSON, _ := json.Marshal(existingEvent)
+		store.data["all"] = existingJSON
+		store.generation["all"] = 5
+		return store
+	}
+
+	t.Run("sets the image and keeps other fields", func(t *testing.T) {
+		store := newStore()
+		svc, err := event.NewService(store)
+		require.NoError(t, err)
+
+		err = svc.SetImage(context.Background(), "chatroom-001", "https://example.com/cover.jpg")
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(6), store.generation["all"])
+		ev, err := svc.Get(context.Background(), "chatroom-001")
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com/cover.jpg", ev.ImageURL)
+		assert.Equal(t, "Event", ev.Title)
+	})
+
+	t.Run("empty URL removes the image", func(t *testing.T) {
+		store := newStore()
+		svc, err := event.NewService(store)
+		require.NoError(t, err)
+		require.NoError(t, svc.SetImage(context.Background(), "chatroom-001", "https://example.com/cover.jpg"))
+
+		require.NoError(t, svc.SetImage(context.Background(), "chatroom-001", ""))
+
+		assert.NotContains(t, string(store.lastWriteData), "imageUrl")
+	})
+
+	t.Run("returns NotFoundError when event does not exist", func(t *testing.T) {
+		store := newStore()
+		svc, err := event.NewService(store)
+		require.NoError(t, err)
+
+		err = svc.SetImage(context.Background(), "chatroom-999", "https://example.com/cover.jpg")
+
+		require.ErrorAs(t, err, new(*event.NotFoundError))
+		assert.Equal(t, 0, store.writeCallCount)
+	})
+
+	t.Run("returns error for empty chatRoomID", func(t *testing.T) {
+		svc, err := event.NewService(newStore())
+		require.NoError(t, err)
+
+		require.Error(t, svc.SetImage(context.Background(), "", "https://example.com/cover.jpg"))
+	})
+}
+
+// =============================================================================
+// AddComment Tests
+// =============================================================================
+
+func TestService_AddComment(t *testing.T) {
+	newStore := func() *mockStorage {
+		store := newMockStorage()
+		existingEvent := &event.Event{
+			ChatRoomID: "chatroom-001",
+			CreatorID:  "user-123",
+			Title:      "Event",
+			StartTime:  testTime1,
+			EndTime:    testTime2,
+		}
+		existingJSON, _ := json.Marshal(existingEvent)
+		store.data["all"] = existingJSON
+		store.generation["all"] = 5
+		return store
+	}
+
+	t.Run("appends comments in order and keeps other fields", func(t *testing.T) {
+		store := newStore()
+		svc, err := event.NewService(store)
+		require.NoError(t, err)
+
+		require.NoError(t, svc.AddComment(context.Background(), "chatroom-001", event.EventComment{UserID: "user-456", Text: "Is parking available?", CreatedAt: testTime1}))
+		require.NoError(t, svc.AddComment(context.Background(), "chatroom-001", event.EventComment{UserID: "user-123", Text: "Yes, behind the hall", CreatedAt: testTime2}))
+
+		assert.Equal(t, int64(7), store.generation["all"])
+		ev, err := svc.Get(context.Background(), "chatroom-001")
+		require.NoError(t, err)
+		assert.Equal(t, "Event", ev.Title)
+		require.Len(t, ev.Comments, 2)
+		assert.Equal(t, "user-456", ev.Comments[0].UserID)
+		assert.Equal(t, "Is parking available?", ev.Comments[0].Text)
+		assert.True(t, testTime1.Equal(ev.Comments[0].CreatedAt))
+		assert.Equal(t, "Yes, behind the hall", ev.Comments[1].Text)
+	})
+
+	t.Run("sanitizes and trims the text", func(t *testing.T) {
+		store := newStore()
+		svc, err := event.NewService(store)
+		require.NoError(t, err)
+
+		err = svc.AddComment(context.Background(), "chatroom-001", event.EventComment{UserID: "user-456", Text: "  bring\x00 snacks\r\n  "})
+
+		require.NoError(t, err)
+		ev, err := svc.Get(context.Background(), "chatroom-001")
+		require.NoError(t, err)
+		require.Len(t, ev.Comments, 1)
+		assert.Equal(t, "bring snacks", ev.Comments[0].Text)
+	})
+
+	t.Run("rejects empty and too long comments", func(t *testing.T) {
+		store := newStore()
+		svc, err := event.NewService(store)
+		require.NoError(t, err)
+
+		err = svc.AddComment(context.Background(), "chatroom-001", event.EventComment{UserID: "user-456", Text: " \x00 "})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "comment cannot be empty")
+
+		err = svc.AddComment(context.Background(), "chatroom-001", event.EventComment{UserID: "user-456", Text: strings.Repeat("あ", event.MaxCommentLength+1)})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "comment is too long")
+		assert.Equal(t, 0, store.writeCallCount)
+	})
+
+	t.Run("keeps only the newest MaxComments comments", func(t *testing.T) {
+		store := newStore()
+		svc, err := event.NewService(store)
+		require.NoError(t, err)
+
+		for i := range event.MaxComments + 2 {
+			require.NoError(t, svc.AddComment(context.Background(), "chatroom-001", event.EventComment{UserID: "user-456", Text: fmt.Sprintf("comment %d", i)}))
+		}
+
+		ev, err := svc.Get(context.Background(), "chatroom-001")
+		require.NoError(t, err)
+		require.Len(t, ev.Comments, event.MaxComments)
+		assert.Equal(t, "comment 2", ev.Comments[0].Text)
+		assert.Equal(t, fmt.Sprintf("comment %d", event.MaxComments+1), ev.Comments[event.MaxComments-1].Text)
+	})
+
+	t.Run("comments are removed with the event", func(t *testing.T) {
+		store := newStore()
+		svc, err := event.NewService(store)
+		require.NoError(t, err)
+		require.NoError(t, svc.AddComment(context.Background(), "chatroom-001", event.EventComment{UserID: "user-456", Text: "See you there"}))
+
+		require.NoError(t, svc.Remove(context.Background(), "chatroom-001"))
+
+		assert.NotContains(t, string(store.lastWriteData), "See you there")
+	})
+
+	t.Run("returns NotFoundError when event does not exist", func(t *testing.T) {
+		store := newStore()
+		svc, err := event.NewService(store)
+		require.NoError(t, err)
+
+		err = svc.AddComment(context.Background(), "chatroom-999", event.EventComment{UserID: "user-456", Text: "Hello"})
+
+		require.ErrorAs(t, err, new(*event.NotFoundError))
+		assert.Equal(t, 0, store.writeCallCount)
+	})
+
+	t.Run("returns error for empty arguments", func(t *testing.T) {
+		svc, err := event.NewService(newStore())
+		require.NoError(t, err)
+
+		require.Error(t, svc.AddComment(context.Background(), "", event.EventComment{UserID: "user-456", Text: "Hello"}))
+		require.Error(t, svc.AddComment(context.Background(), "chatroom-001", event.EventComment{Text: "Hello"}))
+	})
+
+	t.Run("fails on a concurrent write", func(t *testing.T) {
+		store := newStore()
+		store.simulateConcurrentWrite = true
+		svc, err := event.NewService(store)
+		require.NoError(t, err)
+
+		err1 := svc.AddComment(context.Background(), "chatroom-001", event.EventComment{UserID: "user-456", Text: "first"})
+		err2 := svc.AddComment(context.Background(), "chatroom-001", event.EventComment{UserID: "user-789", Text: "second"})
+
+		if err1 == nil {
+			require.Error(t, err2)
+			assert.Contains(t, err2.Error(), "generation mismatch")
+		} else {
+			require.NoError(t, err2)
+			assert.Contains(t, err1.Error(), "generation mismatch")
+		}
+	})
+}
+
 // =============================================================================
 // Remove Tests (FR-007, FR-010, FR-011, NFR-001)
 // =============================================================================
@@ -1847,6 +2543,287 @@ func TestService_Remove_StorageErrors(t *testing.T) {
 	})
 }
 
+// =============================================================================
+// AddAttendee Tests
+// =============================================================================
+
+func TestService_AddAttendee(t *testing.T) {
+	setup := func(t *testing.T, ev *event.Event) (*event.Service, *mockStorage) {
+		t.Helper()
+		store := newMockStorage()
+		svc, err := event.NewService(store)
+		require.NoError(t, err)
+		ev.StartTime, ev.EndTime = testTime1, testTime2
+		require.NoError(t, svc.Create(context.Background(), ev))
+		return svc, store
+	}
+
+	t.Run("adds attendee while there is room", func(t *testing.T) {
+		svc, _ := setup(t, &event.Event{ChatRoomID: "chatroom-001", Capacity: 2, Attendees: []string{"user-1"}})
+
+		waitlisted, err := svc.AddAttendee(context.Background(), "chatroom-001", "user-2")
+
+		require.NoError(t, err)
+		assert.False(t, waitlisted)
+		ev, err := svc.Get(context.Background(), "chatroom-001")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"user-1", "user-2"}, ev.Attendees)
+		assert.Empty(t, ev.Waitlist)
+	})
+
+	t.Run("adds attendee without limit when capacity is 0", func(t *testing.T) {
+		svc, _ := setup(t, &event.Event{ChatRoomID: "chatroom-001", Attendees: []string{"user-1", "user-2"}})
+
+		waitlisted, err := svc.AddAttendee(context.Background(), "chatroom-001", "user-3")
+
+		require.NoError(t, err)
+		assert.False(t, waitlisted)
+		ev, err := svc.Get(context.Background(), "chatroom-001")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"user-1", "user-2", "user-3"}, ev.Attendees)
+	})
+
+	t.Run("waitlists user when the event is full", func(t *testing.T) {
+		svc, _ := setup(t, &event.Event{
+			ChatRoomID: "chatroom-001",
+			Capacity:   1,
+			Attendees:  []string{"user-1"},
+			Waitlist:   []string{"user-2"},
+		})
+
+		waitlisted, err := svc.AddAttendee(context.Background(), "chatroom-001", "user-3")
+
+		require.NoError(t, err)
+		assert.True(t, waitlisted)
+		ev, err := svc.Get(context.Background(), "chatroom-001")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"user-1"}, ev.Attendees)
+		assert.Equal(t, []string{"user-2", "user-3"}, ev.Waitlist)
+	})
+
+	t.Run("keeps the place of a user already signed up", func(t *testing.T) {
+		svc, store := setup(t, &event.Event{
+			ChatRoomID: "chatroom-001",
+			Capacity:   1,
+			Attendees:  []string{"user-1"},
+			Waitlist:   []string{"user-2"},
+		})
+		writes := store.writeCallCount
+
+		attendeeWaitlisted, err := svc.AddAttendee(context.Background(), "chatroom-001", "user-1")
+		require.NoError(t, err)
+		waitlistedWaitlisted, err := svc.AddAttendee(context.Background(), "chatroom-001", "user-2")
+		require.NoError(t, err)
+
+		assert.False(t, attendeeWaitlisted)
+		assert.True(t, waitlistedWaitlisted)
+		assert.Equal(t, writes, store.writeCallCount, "storage should not be written")
+	})
+
+	t.Run("returns NotFoundError for missing event", func(t *testing.T) {
+		svc, err := event.NewService(newMockStorage())
+		require.NoError(t, err)
+
+		_, err = svc.AddAttendee(context.Background(), "chatroom-404", "user-1")
+
+		require.ErrorAs(t, err, new(*event.NotFoundError))
+	})
+}
+
+// =============================================================================
+// RemoveAttendee Tests
+// =============================================================================
+
+func TestService_RemoveAttendee(t *testing.T) {
+	setup := func(t *testing.T, ev *event.Event) *event.Service {
+		t.Helper()
+		svc, err := event.NewService(newMockStorage())
+		require.NoError(t, err)
+		ev.StartTime, ev.EndTime = testTime1, testTime2
+		require.NoError(t, svc.Create(context.Background(), ev))
+		return svc
+	}
+
+	t.Run("removes attendee without promotion", func(t *testing.T) {
+		svc := setup(t, &event.Event{ChatRoomID: "chatroom-001", Capacity: 5, Attendees: []string{"user-1", "user-2"}})
+
+		promoted, err := svc.RemoveAttendee(context.Background(), "chatroom-001", "user-1")
+
+		require.NoError(t, err)
+		assert.Empty(t, promoted)
+		ev, err := svc.Get(context.Background(), "chatroom-001")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"user-2"}, ev.Attendees)
+	})
+
+	t.Run("promotes first waitlisted user when a spot opens", func(t *testing.T) {
+		svc := setup(t, &event.Event{
+			ChatRoomID: "chatroom-001",
+			Capacity:   2,
+			Attendees:  []string{"user-1", "user-2"},
+			Waitlist:   []string{"user-3", "user-4"},
+		})
+
+		promoted, err := svc.RemoveAttendee(context.Background(), "chatroom-001", "user-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, "user-3", promoted)
+		ev, err := svc.Get(context.Background(), "chatroom-001")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"user-2", "user-3"}, ev.Attendees)
+		assert.Equal(t, []string{"user-4"}, ev.Waitlist)
+	})
+
+	t.Run("removes waitlisted user without promotion", func(t *testing.T) {
+		svc := setup(t, &event.Event{
+			ChatRoomID: "chatroom-001",
+			Capacity:   1,
+			Attendees:  []string{"user-1"},
+			Waitlist:   []string{"user-2"},
+		})
+
+		promoted, err := svc.RemoveAttendee(context.Background(), "chatroom-001", "user-2")
+
+		require.NoError(t, err)
+		assert.Empty(t, promoted)
+		ev, err := svc.Get(context.Background(), "chatroom-001")
+		require.NoError(t, err)
+		assert.Empty(t, ev.Waitlist)
+	})
+
+	t.Run("returns NotAttendingError for unknown user", func(t *testing.T) {
+		svc := setup(t, &event.Event{ChatRoomID: "chatroom-001", Capacity: 5, Attendees: []string{"user-1"}})
+
+		_, err := svc.RemoveAttendee(context.Background(), "chatroom-001", "user-9")
+
+		require.ErrorAs(t, err, new(*event.NotAttendingError))
+	})
+
+	t.Run("returns NotFoundError for missing event", func(t *testing.T) {
+		svc, err := event.NewService(newMockStorage())
+		require.NoError(t, err)
+
+		_, err = svc.RemoveAttendee(context.Background(), "chatroom-404", "user-1")
+
+		require.ErrorAs(t, err, new(*event.NotFoundError))
+	})
+}
+
+// =============================================================================
+// Sanitization Tests
+// =============================================================================
+
+func TestService_SanitizesText(t *testing.T) {
+	t.Run("strips control characters on create", func(t *testing.T) {
+		store := newMockStorage()
+		svc, err := event.NewService(store)
+		require.NoError(t, err)
+
+		err = svc.Create(context.Background(), &event.Event{
+			ChatRoomID:  "chatroom-001",
+			CreatorID:   "user-123",
+			Title:       "花見\x00🌸\u0085",
+			StartTime:   testTime1,
+			EndTime:     testTime2,
+			Fee:         "500円\x1b",
+			Description: "line1\r\nline2\t👨‍👩‍👧\x07",
+		})
+		require.NoError(t, err)
+
+		got, err := svc.Get(context.Background(), "chatroom-001")
+		require.NoError(t, err)
+		assert.Equal(t, "花見🌸", got.Title)
+		assert.Equal(t, "500円", got.Fee)
+		assert.Equal(t, "line1\nline2\t👨‍👩‍👧", got.Description)
+	})
+
+	t.Run("strips control characters on update", func(t *testing.T) {
+		store := newMockStorage()
+		svc, err := event.NewService(store)
+		require.NoError(t, err)
+		require.NoError(t, svc.Create(context.Background(), &event.Event{
+			ChatRoomID: "chatroom-001",
+			CreatorID:  "user-123",
+			Title:      "Go Meetup",
+			StartTime:  testTime1,
+			EndTime:    testTime2,
+		}))
+
+		err = svc.Update(context.Background(), "chatroom-001", "持ち物\x00: 🍙\x1f")
+		require.NoError(t, err)
+
+		got, err := svc.Get(context.Background(), "chatroom-001")
+		require.NoError(t, err)
+		assert.Equal(t, "持ち物: 🍙", got.Description)
+	})
+}
+
+// =============================================================================
+// TextLimits Tests
+// =============================================================================
+
+func TestTextLimits(t *testing.T) {
+	limits := event.TextLimits{MaxTitle: 3, MaxDescription: 5}
+
+	t.Run("accepts text at the limit counting runes", func(t *testing.T) {
+		require.NoError(t, limits.CheckTitle("花見🌸"))
+		require.NoError(t, limits.CheckDescription("お花見会🎉"))
+	})
+
+	t.Run("rejects text over the limit", func(t *testing.T) {
+		err := limits.CheckTitle("abcd")
+		require.Error(t, err)
+		assert.Equal(t, "title is too long: 4 characters, the maximum is 3", err.Error())
+
+		err = limits.CheckDescription("お花見会🎉!")
+		require.Error(t, err)
+		assert.Equal(t, "description is too long: 6 characters, the maximum is 5", err.Error())
+	})
+
+	t.Run("validates limits", func(t *testing.T) {
+		require.NoError(t, event.DefaultTextLimits.Validate())
+		assert.Error(t, event.TextLimits{MaxTitle: 0, MaxDescription: 1}.Validate())
+		assert.Error(t, event.TextLimits{MaxTitle: 1, MaxDescription: -1}.Validate())
+	})
+}
+
+// =============================================================================
+// Location Tests
+// =============================================================================
+
+func TestEvent_Location(t *testing.T) {
+	at := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("uses the event timezone", func(t *testing.T) {
+		ev := &event.Event{Timezone: "America/New_York"}
+
+		assert.Equal(t, "America/New_York", ev.Location().String())
+		assert.Equal(t, 19, at.In(ev.Location()).Hour())
+	})
+
+	t.Run("falls back to the default timezone when empty", func(t *testing.T) {
+		ev := &event.Event{}
+
+		assert.Equal(t, event.DefaultTimezone, ev.Location().String())
+		assert.Equal(t, 9, at.In(ev.Location()).Hour())
+	})
+
+	t.Run("falls back to the default timezone when unknown", func(t *testing.T) {
+		ev := &event.Event{Timezone: "Mars/Olympus_Mons"}
+
+		assert.Equal(t, event.DefaultTimezone, ev.Location().String())
+	})
+}
+
+func TestValidateTimezone(t *testing.T) {
+	for _, tz := range []string{"Asia/Tokyo", "America/New_York", "UTC"} {
+		assert.NoError(t, event.ValidateTimezone(tz), tz)
+	}
+	for _, tz := range []string{"", "Local", "JST", "Mars/Olympus_Mons", "asia/tokyo "} {
+		assert.Error(t, event.ValidateTimezone(tz), tz)
+	}
+}
+
 // =============================================================================
 // Mock Storage
 // =============================================================================
@@ -0,0 +1,75 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// Sweeper periodically removes events that ended longer ago than the retention window.
+type Sweeper struct {
+	service   *Service
+	retention time.Duration
+	interval  time.Duration
+	logger    *slog.Logger
+}
+
+// NewSweeper creates a Sweeper that removes events ended more than retention ago, checking every interval.
+func NewSweeper(service *Service, retention, interval time.Duration, logger *slog.Logger) (*Sweeper, error) {
+	if service == nil {
+		return nil, errors.New("service cannot be nil")
+	}
+	if retention <= 0 {
+		return nil, errors.New("retention must be positive")
+	}
+	if interval <= 0 {
+		return nil, errors.New("interval must be positive")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil")
+	}
+	return &Sweeper{
+		service:   service,
+		retention: retention,
+		interval:  interval,
+		logger:    logger,
+	}, nil
+}
+
+// Run sweeps expired events every interval until ctx is cancelled.
+func (s *Sweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			// A sweep may not run into the next tick
+			runCtx, cancel := context.WithTimeout(ctx, s.interval)
+			if _, err := s.Sweep(runCtx, now); err != nil {
+				s.logger.ErrorContext(ctx, "failed to sweep expired events", slog.Any("error", err))
+			}
+			cancel()
+		}
+	}
+}
+
+// Sweep removes events whose EndTime is more than the retention window before now.
+// Events that have not ended yet are never removed. Returns the chat room IDs of removed events.
+// A sweep that loses a concurrent write returns the error and is retried on the next tick.
+func (s *Sweeper) Sweep(ctx context.Context, now time.Time) ([]string, error) {
+	removed, err := s.service.RemoveEndedBefore(ctx, now.Add(-s.retention))
+	if err != nil {
+		return nil, err
+	}
+	if len(removed) > 0 {
+		s.logger.InfoContext(ctx, "removed expired events",
+			slog.Int("count", len(removed)),
+			slog.Any("chatRoomIDs", removed),
+		)
+	}
+	return removed, nil
+}
@@ -0,0 +1,130 @@
+package event_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+	"yuruppu/internal/event"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// NewSweeper Tests
+// =============================================================================
+
+func TestNewSweeper(t *testing.T) {
+	svc, err := event.NewService(newMockStorage())
+	require.NoError(t, err)
+	logger := slog.New(slog.DiscardHandler)
+
+	tests := []struct {
+		name      string
+		service   *event.Service
+		retention time.Duration
+		interval  time.Duration
+		logger    *slog.Logger
+		wantErr   string
+	}{
+		{name: "nil service", service: nil, retention: time.Hour, interval: time.Hour, logger: logger, wantErr: "service cannot be nil"},
+		{name: "zero retention", service: svc, retention: 0, interval: time.Hour, logger: logger, wantErr: "retention must be positive"},
+		{name: "zero interval", service: svc, retention: time.Hour, interval: 0, logger: logger, wantErr: "interval must be positive"},
+		{name: "nil logger", service: svc, retention: time.Hour, interval: time.Hour, logger: nil, wantErr: "logger cannot be nil"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sweeper, err := event.NewSweeper(tt.service, tt.retention, tt.interval, tt.logger)
+
+			require.Error(t, err)
+			assert.Nil(t, sweeper)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+// =============================================================================
+// Sweep Tests
+// =============================================================================
+
+func TestSweeper_Sweep(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	retention := 7 * 24 * time.Hour
+
+	newStore := func(t *testing.T, events ...*event.Event) *mockStorage {
+		t.Helper()
+		lines := make([]string, 0, len(events))
+		for _, ev := range events {
+			data, err := json.Marshal(ev)
+			require.NoError(t, err)
+			lines = append(lines, string(data))
+		}
+		store := newMockStorage()
+		store.data["all"] = []byte(strings.Join(lines, "\n") + "\n")
+		store.generation["all"] = 3
+		return store
+	}
+	endedAt := func(chatRoomID string, end time.Time) *event.Event {
+		return &event.Event{ChatRoomID: chatRoomID, StartTime: end.Add(-time.Hour), EndTime: end}
+	}
+
+	t.Run("removes only events that ended before the retention window", func(t *testing.T) {
+		store := newStore(t,
+			endedAt("chatroom-old", now.Add(-retention-time.Minute)),
+			endedAt("chatroom-recent", now.Add(-retention+time.Minute)),
+			endedAt("chatroom-ongoing", now.Add(time.Hour)),
+			endedAt("chatroom-future", now.Add(48*time.Hour)),
+		)
+		svc, err := event.NewService(store)
+		require.NoError(t, err)
+		sweeper, err := event.NewSweeper(svc, retention, time.Hour, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		removed, err := sweeper.Sweep(context.Background(), now)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"chatroom-old"}, removed)
+		assert.Equal(t, int64(4), store.generation["all"], "should write against the read generation")
+		events, err := svc.List(context.Background(), event.ListOptions{})
+		require.NoError(t, err)
+		ids := make([]string, 0, len(events))
+		for _, ev := range events {
+			ids = append(ids, ev.ChatRoomID)
+		}
+		assert.ElementsMatch(t, []string{"chatroom-recent", "chatroom-ongoing", "chatroom-future"}, ids)
+	})
+
+	t.Run("does not write when nothing is expired", func(t *testing.T) {
+		store := newStore(t, endedAt("chatroom-recent", now.Add(-time.Hour)))
+		svc, err := event.NewService(store)
+		require.NoError(t, err)
+		sweeper, err := event.NewSweeper(svc, retention, time.Hour, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		removed, err := sweeper.Sweep(context.Background(), now)
+
+		require.NoError(t, err)
+		assert.Empty(t, removed)
+		assert.Equal(t, 0, store.writeCallCount)
+	})
+
+	t.Run("returns error when the write loses a concurrent change", func(t *testing.T) {
+		store := newStore(t, endedAt("chatroom-old", now.Add(-30*24*time.Hour)))
+		store.writeErr = errors.New("generation mismatch")
+		svc, err := event.NewService(store)
+		require.NoError(t, err)
+		sweeper, err := event.NewSweeper(svc, retention, time.Hour, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		removed, err := sweeper.Sweep(context.Background(), now)
+
+		require.Error(t, err)
+		assert.Nil(t, removed)
+		assert.Contains(t, err.Error(), "failed to write events")
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"sync"
 )
 
@@ -15,37 +16,103 @@ type Storage interface {
 	Write(ctx context.Context, key, mimetype string, data []byte, expectedGeneration int64) (newGeneration int64, err error)
 }
 
+// NotFoundError is returned when no profile has been saved for the group.
+type NotFoundError struct {
+	GroupID string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("group profile not found: %s", e.GroupID)
+}
+
+// ReplyMode controls which group messages the bot answers.
+type ReplyMode string
+
+const (
+	ReplyModeAlways      ReplyMode = "always"       // Answer every message
+	ReplyModeMentionOnly ReplyMode = "mention_only" // Answer only messages that @-mention the bot
+)
+
 // GroupProfile contains LINE group profile information.
 type GroupProfile struct {
-	DisplayName     string `json:"displayName"`
-	PictureURL      string `json:"pictureUrl,omitempty"`
-	PictureMIMEType string `json:"pictureMimeType,omitempty"`
-	UserCount       int    `json:"userCount,omitempty"`
+	DisplayName     string    `json:"displayName"`
+	PictureURL      string    `json:"pictureUrl,omitempty"`
+	PictureMIMEType string    `json:"pictureMimeType,omitempty"`
+	UserCount       int       `json:"userCount,omitempty"`
+	DefaultTimezone string    `json:"defaultTimezone,omitempty"` // IANA name new events in the group default to; empty means the global default
+	MemberIDs       []string  `json:"memberIds,omitempty"`       // Users known to be in the group; members who never joined or spoke after the bot arrived are missing
+	Inactive        bool      `json:"inactive,omitempty"`        // The bot has left the group; scheduled jobs skip it until the bot is added again
+	ReplyMode       ReplyMode `json:"replyMode,omitempty"`       // Which messages the bot answers; empty means ReplyModeAlways
+	Language        string    `json:"language,omitempty"`        // BCP 47 tag the bot replies in, overriding members' languages; empty means each member's own language
+}
+
+// AddMembers records userIDs as group members.
+// Returns true if any of them was not already recorded.
+func (p *GroupProfile) AddMembers(userIDs ...string) bool {
+	added := false
+	for _, userID := range userIDs {
+		if userID == "" || slices.Contains(p.MemberIDs, userID) {
+			continue
+		}
+		p.MemberIDs = append(p.MemberIDs, userID)
+		added = true
+	}
+	return added
 }
 
+// RemoveMembers forgets userIDs as group members.
+func (p *GroupProfile) RemoveMembers(userIDs ...string) {
+	p.MemberIDs = slices.DeleteFunc(p.MemberIDs, func(id string) bool {
+		return slices.Contains(userIDs, id)
+	})
+}
+
+// Option configures optional Service behavior.
+type Option func(*Service)
+
+// WithMembershipIndex keeps an index from each user to the groups they are a member of in storage,
+// so that GroupIDsForUser can find a user's groups.
+// Every profile written through the service records all of its MemberIDs in the index.
+// Entries are never removed, so callers should check the group's MemberIDs before relying on one.
+func WithMembershipIndex(storage Storage) Option {
+	return func(s *Service) {
+		s.index = storage
+	}
+}
+
+// maxIndexAttempts bounds how many times adding a group to a user's index entry is retried on conflicting writes.
+const maxIndexAttempts = 3
+
 // Service provides group profile management with caching and persistence.
 type Service struct {
 	storage Storage
+	index   Storage // userID -> group IDs; nil when the membership index is disabled
 	logger  *slog.Logger
 
-	cache sync.Map // groupID -> *GroupProfile
+	cache      sync.Map // groupID -> *GroupProfile
+	indexCache sync.Map // userID -> []string of group IDs known to be indexed
 }
 
 // NewService creates a new group profile service.
-func NewService(storage Storage, logger *slog.Logger) (*Service, error) {
+func NewService(storage Storage, logger *slog.Logger, opts ...Option) (*Service, error) {
 	if storage == nil {
 		return nil, errors.New("storage cannot be nil")
 	}
 	if logger == nil {
 		return nil, errors.New("logger cannot be nil")
 	}
-	return &Service{
+	s := &Service{
 		storage: storage,
 		logger:  logger,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
 }
 
 // GetGroupProfile retrieves group profile from cache or storage.
+// Returns *NotFoundError if no profile has been saved for the group.
 func (s *Service) GetGroupProfile(ctx context.Context, groupID string) (*GroupProfile, error) {
 	if cached, ok := s.cache.Load(groupID); ok {
 		if profile, ok := cached.(*GroupProfile); ok {
@@ -60,7 +127,7 @@ func (s *Service) GetGroupProfile(ctx context.Context, groupID string) (*GroupPr
 		return nil, fmt.Errorf("failed to read group profile: %w", err)
 	}
 	if data == nil {
-		return nil, fmt.Errorf("group profile not found: %s", groupID)
+		return nil, &NotFoundError{GroupID: groupID}
 	}
 
 	var profile GroupProfile
@@ -90,5 +157,121 @@ func (s *Service) SetGroupProfile(ctx context.Context, groupID string, profile *
 
 	// Update cache only after successful storage write
 	s.cache.Store(groupID, profile)
+	s.indexMembers(ctx, groupID, profile.MemberIDs)
 	return nil
 }
+
+// UpdateGroupProfile applies update to the stored profile and writes it back.
+// The write is conditioned on the generation that was read (optimistic locking).
+// Returns error if the profile does not exist or was modified concurrently.
+func (s *Service) UpdateGroupProfile(ctx context.Context, groupID string, update func(*GroupProfile)) error {
+	if update == nil {
+		return errors.New("update cannot be nil")
+	}
+
+	data, generation, err := s.storage.Read(ctx, groupID)
+	if err != nil {
+		return fmt.Errorf("failed to read group profile: %w", err)
+	}
+	if data == nil {
+		return fmt.Errorf("group profile not found: %s", groupID)
+	}
+
+	var profile GroupProfile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return fmt.Errorf("failed to unmarshal group profile: %w", err)
+	}
+
+	update(&profile)
+
+	data, err = json.Marshal(&profile)
+	if err != nil {
+		return fmt.Errorf("failed to marshal group profile: %w", err)
+	}
+
+	_, err = s.storage.Write(ctx, groupID, "application/json", data, generation)
+	if err != nil {
+		return fmt.Errorf("failed to write group profile: %w", err)
+	}
+
+	// Update cache only after successful storage write
+	s.cache.Store(groupID, &profile)
+	s.indexMembers(ctx, groupID, profile.MemberIDs)
+	return nil
+}
+
+// GroupIDsForUser returns the IDs of the groups userID has been recorded as a member of, in the order they were recorded.
+// The user may have left some of them since; check each group's MemberIDs.
+// Returns error if the membership index is not enabled.
+func (s *Service) GroupIDsForUser(ctx context.Context, userID string) ([]string, error) {
+	if s.index == nil {
+		return nil, errors.New("membership index is not enabled")
+	}
+	groupIDs, _, err := s.readIndex(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return groupIDs, nil
+}
+
+// indexMembers records groupID in the index entry of each of memberIDs.
+// Failures are logged rather than returned, since the profile itself has already been saved.
+func (s *Service) indexMembers(ctx context.Context, groupID string, memberIDs []string) {
+	if s.index == nil {
+		return
+	}
+	for _, userID := range memberIDs {
+		if err := s.addToIndex(ctx, userID, groupID); err != nil {
+			s.logger.WarnContext(ctx, "failed to index group member",
+				slog.String("groupID", groupID),
+				slog.String("userID", userID),
+				slog.Any("error", err),
+			)
+		}
+	}
+}
+
+// addToIndex adds groupID to userID's index entry unless it is already there,
+// retrying when another write to the entry gets in between.
+func (s *Service) addToIndex(ctx context.Context, userID, groupID string) error {
+	if cached, ok := s.indexCache.Load(userID); ok && slices.Contains(cached.([]string), groupID) {
+		return nil
+	}
+
+	var err error
+	for range maxIndexAttempts {
+		groupIDs, generation, readErr := s.readIndex(ctx, userID)
+		if readErr != nil {
+			return readErr
+		}
+		if !slices.Contains(groupIDs, groupID) {
+			groupIDs = append(groupIDs, groupID)
+			data, marshalErr := json.Marshal(groupIDs)
+			if marshalErr != nil {
+				return fmt.Errorf("failed to marshal membership index: %w", marshalErr)
+			}
+			if _, err = s.index.Write(ctx, userID, "application/json", data, generation); err != nil {
+				continue
+			}
+		}
+		s.indexCache.Store(userID, groupIDs)
+		return nil
+	}
+	return fmt.Errorf("failed to write membership index after %d attempts: %w", maxIndexAttempts, err)
+}
+
+// readIndex reads userID's index entry and its generation; a missing entry is empty with generation 0.
+func (s *Service) readIndex(ctx context.Context, userID string) ([]string, int64, error) {
+	data, generation, err := s.index.Read(ctx, userID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read membership index: %w", err)
+	}
+	if data == nil {
+		return nil, 0, nil
+	}
+	var groupIDs []string
+	if err := json.Unmarshal(data, &groupIDs); err != nil {
+		return nil, 0, fmt.Errorf("failed to unmarshal membership index: %w", err)
+	}
+	return groupIDs, generation, nil
+}
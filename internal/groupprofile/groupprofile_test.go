@@ -141,7 +141,7 @@ func TestService_GetGroupProfile(t *testing.T) {
 
 		got, err := svc.GetGroupProfile(t.Context(), "nonexistent")
 
-		require.Error(t, err)
+		require.ErrorAs(t, err, new(*groupprofile.NotFoundError))
 		assert.Nil(t, got)
 		assert.Contains(t, err.Error(), "group profile not found")
 	})
@@ -220,6 +220,185 @@ func TestService_SetGroupProfile(t *testing.T) {
 	})
 }
 
+// =============================================================================
+// UpdateGroupProfile Tests
+// =============================================================================
+
+func TestService_UpdateGroupProfile(t *testing.T) {
+	t.Run("updates stored profile with generation precondition", func(t *testing.T) {
+		store := newMockStorage()
+		store.data["group-123"] = []byte(`{"displayName":"Group A","memberIds":["user-1"]}`)
+		svc, _ := groupprofile.NewService(store, slog.New(slog.DiscardHandler))
+
+		err := svc.UpdateGroupProfile(t.Context(), "group-123", func(p *groupprofile.GroupProfile) {
+			p.DefaultTimezone = "Europe/London"
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), store.lastWriteGen)
+		var stored groupprofile.GroupProfile
+		require.NoError(t, json.Unmarshal(store.lastWriteData, &stored))
+		assert.Equal(t, "Europe/London", stored.DefaultTimezone)
+		assert.Equal(t, "Group A", stored.DisplayName)
+		assert.Equal(t, []string{"user-1"}, stored.MemberIDs)
+	})
+
+	t.Run("updates cache after write", func(t *testing.T) {
+		store := newMockStorage()
+		store.data["group-123"] = []byte(`{"displayName":"Group A"}`)
+		svc, _ := groupprofile.NewService(store, slog.New(slog.DiscardHandler))
+		_, err := svc.GetGroupProfile(t.Context(), "group-123")
+		require.NoError(t, err)
+
+		err = svc.UpdateGroupProfile(t.Context(), "group-123", func(p *groupprofile.GroupProfile) {
+			p.DefaultTimezone = "Europe/London"
+		})
+		require.NoError(t, err)
+
+		got, err := svc.GetGroupProfile(t.Context(), "group-123")
+		require.NoError(t, err)
+		assert.Equal(t, "Europe/London", got.DefaultTimezone)
+	})
+
+	t.Run("returns error when profile does not exist", func(t *testing.T) {
+		store := newMockStorage()
+		svc, _ := groupprofile.NewService(store, slog.New(slog.DiscardHandler))
+
+		err := svc.UpdateGroupProfile(t.Context(), "group-123", func(p *groupprofile.GroupProfile) {})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "group profile not found")
+		assert.Equal(t, 0, store.writeCallCount)
+	})
+
+	t.Run("does not update cache when storage write fails", func(t *testing.T) {
+		store := newMockStorage()
+		store.data["group-123"] = []byte(`{"displayName":"Group A"}`)
+		svc, _ := groupprofile.NewService(store, slog.New(slog.DiscardHandler))
+		_, err := svc.GetGroupProfile(t.Context(), "group-123")
+		require.NoError(t, err)
+		store.writeErr = errors.New("generation mismatch")
+
+		err = svc.UpdateGroupProfile(t.Context(), "group-123", func(p *groupprofile.GroupProfile) {
+			p.DefaultTimezone = "Europe/London"
+		})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to write group profile")
+		got, err := svc.GetGroupProfile(t.Context(), "group-123")
+		require.NoError(t, err)
+		assert.Empty(t, got.DefaultTimezone)
+	})
+}
+
+// =============================================================================
+// Membership Index Tests
+// =============================================================================
+
+func TestService_GroupIDsForUser(t *testing.T) {
+	t.Run("finds groups the user was saved as a member of", func(t *testing.T) {
+		index := newMockStorage()
+		store := newMockStorage()
+		store.data["group-2"] = []byte(`{"displayName":"Group B","memberIds":["user-2"]}`)
+		svc, err := groupprofile.NewService(store, slog.New(slog.DiscardHandler), groupprofile.WithMembershipIndex(index))
+		require.NoError(t, err)
+
+		require.NoError(t, svc.SetGroupProfile(t.Context(), "group-1", &groupprofile.GroupProfile{
+			DisplayName: "Group A",
+			MemberIDs:   []string{"user-1", "user-2"},
+		}))
+		require.NoError(t, svc.UpdateGroupProfile(t.Context(), "group-2", func(p *groupprofile.GroupProfile) {
+			p.AddMembers("user-1")
+		}))
+
+		got, err := svc.GroupIDsForUser(t.Context(), "user-1")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"group-1", "group-2"}, got)
+		got, err = svc.GroupIDsForUser(t.Context(), "user-2")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"group-1", "group-2"}, got)
+	})
+
+	t.Run("does not rewrite entries that already list the group", func(t *testing.T) {
+		index := newMockStorage()
+		svc, err := groupprofile.NewService(newMockStorage(), slog.New(slog.DiscardHandler), groupprofile.WithMembershipIndex(index))
+		require.NoError(t, err)
+		profile := &groupprofile.GroupProfile{DisplayName: "Group A", MemberIDs: []string{"user-1"}}
+
+		require.NoError(t, svc.SetGroupProfile(t.Context(), "group-1", profile))
+		profile.DefaultTimezone = "Europe/London"
+		require.NoError(t, svc.SetGroupProfile(t.Context(), "group-1", profile))
+
+		assert.Equal(t, 1, index.writeCallCount)
+	})
+
+	t.Run("returns nothing for a user in no group", func(t *testing.T) {
+		svc, err := groupprofile.NewService(newMockStorage(), slog.New(slog.DiscardHandler), groupprofile.WithMembershipIndex(newMockStorage()))
+		require.NoError(t, err)
+
+		got, err := svc.GroupIDsForUser(t.Context(), "user-1")
+
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("saves the profile even if the index cannot be written", func(t *testing.T) {
+		index := newMockStorage()
+		index.writeErr = errors.New("generation mismatch")
+		store := newMockStorage()
+		svc, err := groupprofile.NewService(store, slog.New(slog.DiscardHandler), groupprofile.WithMembershipIndex(index))
+		require.NoError(t, err)
+
+		err = svc.SetGroupProfile(t.Context(), "group-1", &groupprofile.GroupProfile{MemberIDs: []string{"user-1"}})
+
+		require.NoError(t, err)
+		assert.Contains(t, store.data, "group-1")
+		assert.Equal(t, 3, index.writeCallCount, "conflicting writes should be retried")
+	})
+
+	t.Run("returns error when the index is not enabled", func(t *testing.T) {
+		svc, err := groupprofile.NewService(newMockStorage(), slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		_, err = svc.GroupIDsForUser(t.Context(), "user-1")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "membership index is not enabled")
+	})
+}
+
+// =============================================================================
+// Member Tests
+// =============================================================================
+
+func TestGroupProfile_Members(t *testing.T) {
+	t.Run("AddMembers appends new members once", func(t *testing.T) {
+		profile := &groupprofile.GroupProfile{MemberIDs: []string{"user-1"}}
+
+		added := profile.AddMembers("user-1", "user-2", "user-2", "")
+
+		assert.True(t, added)
+		assert.Equal(t, []string{"user-1", "user-2"}, profile.MemberIDs)
+	})
+
+	t.Run("AddMembers reports false when all are known", func(t *testing.T) {
+		profile := &groupprofile.GroupProfile{MemberIDs: []string{"user-1"}}
+
+		added := profile.AddMembers("user-1")
+
+		assert.False(t, added)
+		assert.Equal(t, []string{"user-1"}, profile.MemberIDs)
+	})
+
+	t.Run("RemoveMembers drops the given members", func(t *testing.T) {
+		profile := &groupprofile.GroupProfile{MemberIDs: []string{"user-1", "user-2", "user-3"}}
+
+		profile.RemoveMembers("user-2", "user-4")
+
+		assert.Equal(t, []string{"user-1", "user-3"}, profile.MemberIDs)
+	})
+}
+
 // =============================================================================
 // Mocks
 // =============================================================================
@@ -233,6 +412,7 @@ type mockStorage struct {
 	lastWriteKey      string
 	lastWriteMIMEType string
 	lastWriteData     []byte
+	lastWriteGen      int64
 }
 
 func newMockStorage() *mockStorage {
@@ -258,6 +438,7 @@ func (m *mockStorage) Write(ctx context.Context, key, mimeType string, data []by
 	m.lastWriteKey = key
 	m.lastWriteMIMEType = mimeType
 	m.lastWriteData = data
+	m.lastWriteGen = expectedGen
 	if m.writeErr != nil {
 		return 0, m.writeErr
 	}
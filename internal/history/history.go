@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/fs"
 	"regexp"
+	"slices"
 	"strings"
+	"yuruppu/internal/line"
 )
 
 // Storage defines the storage interface required by history service.
@@ -16,30 +19,74 @@ type Storage interface {
 
 var invalidSourceIDPattern = regexp.MustCompile(`/|\.\.`)
 
+// Keying selects how conversation histories are separated within a chat.
+type Keying int
+
+const (
+	// KeyingShared keeps one history per source, shared by all members of a group.
+	KeyingShared Keying = iota
+	// KeyingPerUser keeps a separate history for each user within a group or room.
+	// 1:1 chats are unaffected because the source is the user.
+	KeyingPerUser
+)
+
+// ParseKeying parses a keying name: "shared" or "per_user".
+func ParseKeying(name string) (Keying, error) {
+	switch name {
+	case "shared":
+		return KeyingShared, nil
+	case "per_user":
+		return KeyingPerUser, nil
+	default:
+		return 0, fmt.Errorf("unknown history keying: %q (must be shared or per_user)", name)
+	}
+}
+
+// Option configures optional Service behavior.
+type Option func(*Service)
+
+// WithKeying sets the history keying strategy.
+// Defaults to KeyingShared.
+func WithKeying(k Keying) Option {
+	return func(s *Service) {
+		s.keying = k
+	}
+}
+
 // Service provides access to conversation history storage.
 type Service struct {
 	storage Storage
+	keying  Keying
 }
 
 // NewService creates a new Service with the given storage backend.
 // Returns error if storage is nil.
-func NewService(s Storage) (*Service, error) {
+func NewService(s Storage, opts ...Option) (*Service, error) {
 	if s == nil {
 		return nil, errors.New("storage cannot be nil")
 	}
-	return &Service{storage: s}, nil
+	svc := &Service{storage: s}
+	for _, opt := range opts {
+		opt(svc)
+	}
+	return svc, nil
 }
 
 // GetHistory retrieves conversation history for a source.
 // Returns messages and generation for optimistic locking.
-// Returns empty slice and generation 0 if no history exists.
+// Returns empty slice and generation 0 if no history exists, whether the storage reports it
+// with nil data or with an error wrapping fs.ErrNotExist, so the first PutHistory creates the object.
 // Returns error if sourceID is empty or contains invalid characters.
 func (s *Service) GetHistory(ctx context.Context, sourceID string) ([]Message, int64, error) {
-	if err := validateSourceID(sourceID); err != nil {
+	key, err := s.storageKey(ctx, sourceID)
+	if err != nil {
 		return nil, 0, err
 	}
 
-	data, generation, err := s.storage.Read(ctx, sourceID)
+	data, generation, err := s.storage.Read(ctx, key)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []Message{}, 0, nil
+	}
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to read history for %s: %w", sourceID, err)
 	}
@@ -57,11 +104,13 @@ func (s *Service) GetHistory(ctx context.Context, sourceID string) ([]Message, i
 }
 
 // PutHistory saves the given messages as the complete history for a source.
+// Control characters other than newline and tab are stripped from message text before it is stored.
 // Uses expectedGeneration for optimistic locking (from GetHistory).
 // Returns the new generation number of the saved history.
 // Returns error if sourceID is empty/invalid or if generation doesn't match (concurrent modification).
 func (s *Service) PutHistory(ctx context.Context, sourceID string, messages []Message, expectedGeneration int64) (int64, error) {
-	if err := validateSourceID(sourceID); err != nil {
+	key, err := s.storageKey(ctx, sourceID)
+	if err != nil {
 		return 0, err
 	}
 
@@ -72,7 +121,7 @@ func (s *Service) PutHistory(ctx context.Context, sourceID string, messages []Me
 	}
 
 	// Write with generation precondition
-	newGen, err := s.storage.Write(ctx, sourceID, "application/jsonl", data, expectedGeneration)
+	newGen, err := s.storage.Write(ctx, key, "application/jsonl", data, expectedGeneration)
 	if err != nil {
 		return 0, fmt.Errorf("failed to write history for %s: %w", sourceID, err)
 	}
@@ -80,6 +129,60 @@ func (s *Service) PutHistory(ctx context.Context, sourceID string, messages []Me
 	return newGen, nil
 }
 
+// maxAppendAttempts bounds how many writes Append tries before giving up on a contended history.
+const maxAppendAttempts = 5
+
+// Append adds messages to the end of the history for a source and returns the resulting history.
+// It reads the history, appends, and writes with the read generation, like GetHistory and PutHistory.
+// When the write fails because another writer changed the history in the meantime,
+// it appends to the latest history and tries again, up to maxAppendAttempts writes in total,
+// so concurrent appends keep each other's messages instead of overwriting them.
+// A write failure that leaves the stored generation unchanged is not a conflict and is returned as is.
+func (s *Service) Append(ctx context.Context, sourceID string, messages ...Message) ([]Message, error) {
+	hist, gen, err := s.GetHistory(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		updated := append(slices.Clip(hist), messages...)
+		_, writeErr := s.PutHistory(ctx, sourceID, updated, gen)
+		if writeErr == nil {
+			return updated, nil
+		}
+
+		// A lost race shows up as a newer generation; anything else is a real write failure
+		latest, latestGen, err := s.GetHistory(ctx, sourceID)
+		if err != nil || latestGen == gen {
+			return nil, writeErr
+		}
+		if attempt == maxAppendAttempts {
+			return nil, fmt.Errorf("gave up appending to history for %s after %d conflicting writes: %w", sourceID, attempt, writeErr)
+		}
+		hist, gen = latest, latestGen
+	}
+}
+
+// storageKey returns the storage key for the history of sourceID.
+// With KeyingPerUser, the user ID from ctx is appended when it differs from sourceID.
+// Without a user ID in ctx, the shared key is used.
+func (s *Service) storageKey(ctx context.Context, sourceID string) (string, error) {
+	if err := validateSourceID(sourceID); err != nil {
+		return "", err
+	}
+	if s.keying != KeyingPerUser {
+		return sourceID, nil
+	}
+	userID, ok := line.UserIDFromContext(ctx)
+	if !ok || userID == "" || userID == sourceID {
+		return sourceID, nil
+	}
+	if invalidSourceIDPattern.MatchString(userID) {
+		return "", errors.New("userID contains invalid characters")
+	}
+	return sourceID + "_" + userID, nil
+}
+
 // validateSourceID checks if sourceID is valid.
 // Rejects empty strings and path traversal attempts.
 func validateSourceID(sourceID string) error {
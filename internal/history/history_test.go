@@ -2,10 +2,14 @@ package history_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"sync"
 	"testing"
 	"time"
 	"yuruppu/internal/history"
+	"yuruppu/internal/line"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -106,6 +110,42 @@ func TestService_RoundTrip(t *testing.T) {
 		assert.Equal(t, "Hello", textPart.Text)
 	})
 
+	t.Run("round-trip strips control characters and keeps emoji", func(t *testing.T) {
+		storage := newMockStorage()
+		svc, err := history.NewService(storage)
+		require.NoError(t, err)
+
+		// Given: Messages whose text contains control characters
+		messages := []history.Message{
+			&history.UserMessage{
+				UserID:    "U123",
+				Parts:     []history.UserPart{&history.UserTextPart{Text: "こんにちは\x00🎉\r\n\x1b[31m👨‍👩‍👧\tok"}},
+				Timestamp: testTime1,
+			},
+			&history.AssistantMessage{
+				ModelName: "gemini-pro",
+				Parts:     []history.AssistantPart{&history.AssistantTextPart{Text: "やあ\x07🍣"}},
+				Timestamp: testTime2,
+			},
+		}
+
+		// When: Put and Get
+		_, err = svc.PutHistory(t.Context(), "source1", messages, 0)
+		require.NoError(t, err)
+
+		retrieved, _, err := svc.GetHistory(t.Context(), "source1")
+		require.NoError(t, err)
+
+		// Then: Control characters are gone, newlines, tabs and emoji remain
+		require.Len(t, retrieved, 2)
+		userMsg, ok := retrieved[0].(*history.UserMessage)
+		require.True(t, ok)
+		assert.Equal(t, "こんにちは🎉\n[31m👨‍👩‍👧\tok", userMsg.Parts[0].(*history.UserTextPart).Text)
+		assistantMsg, ok := retrieved[1].(*history.AssistantMessage)
+		require.True(t, ok)
+		assert.Equal(t, "やあ🍣", assistantMsg.Parts[0].(*history.AssistantTextPart).Text)
+	})
+
 	t.Run("round-trip with file data", func(t *testing.T) {
 		storage := newMockStorage()
 		svc, err := history.NewService(storage)
@@ -310,6 +350,57 @@ func TestService_KeyIsolation(t *testing.T) {
 	})
 }
 
+// TestService_FirstTurn tests a source with no history object against storage that reports not-found as an error.
+func TestService_FirstTurn(t *testing.T) {
+	t.Run("first read returns empty history with generation 0", func(t *testing.T) {
+		storage := &notFoundStorage{mockStorage: newMockStorage()}
+		svc, err := history.NewService(storage)
+		require.NoError(t, err)
+
+		retrieved, generation, err := svc.GetHistory(t.Context(), "new-user")
+
+		require.NoError(t, err)
+		assert.NotNil(t, retrieved)
+		assert.Empty(t, retrieved)
+		assert.Equal(t, int64(0), generation)
+	})
+
+	t.Run("first write creates the object with expected generation 0", func(t *testing.T) {
+		storage := &notFoundStorage{mockStorage: newMockStorage()}
+		svc, err := history.NewService(storage)
+		require.NoError(t, err)
+
+		_, generation, err := svc.GetHistory(t.Context(), "new-user")
+		require.NoError(t, err)
+		messages := []history.Message{
+			&history.UserMessage{
+				UserID:    "new-user",
+				Parts:     []history.UserPart{&history.UserTextPart{Text: "Hello"}},
+				Timestamp: testTime1,
+			},
+		}
+		newGen, err := svc.PutHistory(t.Context(), "new-user", messages, generation)
+
+		require.NoError(t, err)
+		assert.Equal(t, []int64{0}, storage.writeGenerations)
+		assert.Equal(t, int64(1), newGen)
+		retrieved, _, err := svc.GetHistory(t.Context(), "new-user")
+		require.NoError(t, err)
+		assert.Len(t, retrieved, 1)
+	})
+
+	t.Run("other read errors are still returned", func(t *testing.T) {
+		storage := &notFoundStorage{mockStorage: newMockStorage(), readErr: errors.New("permission denied")}
+		svc, err := history.NewService(storage)
+		require.NoError(t, err)
+
+		_, _, err = svc.GetHistory(t.Context(), "new-user")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "permission denied")
+	})
+}
+
 // =============================================================================
 // Optimistic Locking Tests
 // =============================================================================
@@ -427,6 +518,104 @@ func TestService_OptimisticLocking(t *testing.T) {
 	})
 }
 
+// =============================================================================
+// Append Tests
+// =============================================================================
+
+func TestService_Append(t *testing.T) {
+	t.Run("creates the history on the first append", func(t *testing.T) {
+		svc, err := history.NewService(newMockStorage())
+		require.NoError(t, err)
+
+		got, err := svc.Append(t.Context(), "source1", userText("Hello", testTime1))
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Hello"}, texts(got))
+		stored, gen, err := svc.GetHistory(t.Context(), "source1")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), gen)
+		assert.Equal(t, []string{"Hello"}, texts(stored))
+	})
+
+	t.Run("keeps a conflicting write and retries on top of it", func(t *testing.T) {
+		inner := newMockStorage()
+		other, err := history.NewService(inner)
+		require.NoError(t, err)
+		_, err = other.PutHistory(t.Context(), "source1", []history.Message{userText("First", testTime1)}, 0)
+		require.NoError(t, err)
+
+		storage := &conflictingStorage{mockStorage: inner, conflict: func() {
+			// Another handler appends its turn between our read and our write
+			_, err := other.Append(t.Context(), "source1", userText("Second", testTime2))
+			require.NoError(t, err)
+		}}
+		svc, err := history.NewService(storage)
+		require.NoError(t, err)
+
+		got, err := svc.Append(t.Context(), "source1", userText("Third", testTime3))
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"First", "Second", "Third"}, texts(got))
+		assert.Equal(t, 2, storage.writeCount)
+		stored, _, err := svc.GetHistory(t.Context(), "source1")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"First", "Second", "Third"}, texts(stored), "both turns survive")
+	})
+
+	t.Run("returns a write failure that is not a conflict without retrying", func(t *testing.T) {
+		storage := &conflictingStorage{mockStorage: newMockStorage(), writeErr: errors.New("bucket unavailable")}
+		svc, err := history.NewService(storage)
+		require.NoError(t, err)
+
+		got, err := svc.Append(t.Context(), "source1", userText("Hello", testTime1))
+
+		require.Error(t, err)
+		assert.Nil(t, got)
+		assert.Contains(t, err.Error(), "bucket unavailable")
+		assert.Equal(t, 1, storage.writeCount)
+	})
+
+	t.Run("gives up when every write conflicts", func(t *testing.T) {
+		inner := newMockStorage()
+		other, err := history.NewService(inner)
+		require.NoError(t, err)
+		storage := &conflictingStorage{mockStorage: inner, conflict: func() {
+			_, err := other.Append(t.Context(), "source1", userText("Other", testTime2))
+			require.NoError(t, err)
+		}, alwaysConflict: true}
+		svc, err := history.NewService(storage)
+		require.NoError(t, err)
+
+		got, err := svc.Append(t.Context(), "source1", userText("Mine", testTime1))
+
+		require.Error(t, err)
+		assert.Nil(t, got)
+		assert.Contains(t, err.Error(), "gave up appending to history for source1 after 5 conflicting writes")
+		assert.Contains(t, err.Error(), "generation mismatch")
+		assert.Equal(t, 5, storage.writeCount)
+	})
+
+	t.Run("concurrent appends all survive", func(t *testing.T) {
+		storage := &lockedStorage{mockStorage: newMockStorage()}
+		svc, err := history.NewService(storage)
+		require.NoError(t, err)
+
+		const writers = 4
+		var wg sync.WaitGroup
+		for i := range writers {
+			wg.Go(func() {
+				_, err := svc.Append(t.Context(), "source1", userText(fmt.Sprintf("turn %d", i), testTime1))
+				assert.NoError(t, err)
+			})
+		}
+		wg.Wait()
+
+		stored, _, err := svc.GetHistory(t.Context(), "source1")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"turn 0", "turn 1", "turn 2", "turn 3"}, texts(stored))
+	})
+}
+
 // =============================================================================
 // JSONL Parsing Error Tests
 // =============================================================================
@@ -517,6 +706,165 @@ func TestService_ParseErrors(t *testing.T) {
 	})
 }
 
+// =============================================================================
+// Keying Tests
+// =============================================================================
+
+func TestParseKeying(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    history.Keying
+		wantErr bool
+	}{
+		{"shared", history.KeyingShared, false},
+		{"per_user", history.KeyingPerUser, false},
+		{"unknown", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := history.ParseKeying(tt.name)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestService_Keying(t *testing.T) {
+	userMessage := func(text string) []history.Message {
+		return []history.Message{
+			&history.UserMessage{
+				UserID:    "user-1",
+				Parts:     []history.UserPart{&history.UserTextPart{Text: text}},
+				Timestamp: testTime1,
+			},
+		}
+	}
+	groupCtx := func(t *testing.T, userID string) context.Context {
+		t.Helper()
+		return line.WithUserID(t.Context(), userID)
+	}
+
+	t.Run("shared keying stores by source ID", func(t *testing.T) {
+		storage := newMockStorage()
+		svc, err := history.NewService(storage)
+		require.NoError(t, err)
+
+		_, err = svc.PutHistory(groupCtx(t, "user-1"), "group-1", userMessage("hi"), 0)
+
+		require.NoError(t, err)
+		assert.Contains(t, storage.data, "group-1")
+		assert.Len(t, storage.data, 1)
+	})
+
+	t.Run("per-user keying stores by source and user ID", func(t *testing.T) {
+		storage := newMockStorage()
+		svc, err := history.NewService(storage, history.WithKeying(history.KeyingPerUser))
+		require.NoError(t, err)
+
+		_, err = svc.PutHistory(groupCtx(t, "user-1"), "group-1", userMessage("hi"), 0)
+
+		require.NoError(t, err)
+		assert.Contains(t, storage.data, "group-1_user-1")
+		assert.NotContains(t, storage.data, "group-1")
+	})
+
+	t.Run("strategies produce distinct keys for the same context", func(t *testing.T) {
+		storage := newMockStorage()
+		shared, err := history.NewService(storage)
+		require.NoError(t, err)
+		perUser, err := history.NewService(storage, history.WithKeying(history.KeyingPerUser))
+		require.NoError(t, err)
+		ctx := groupCtx(t, "user-1")
+
+		_, err = shared.PutHistory(ctx, "group-1", userMessage("shared"), 0)
+		require.NoError(t, err)
+		_, err = perUser.PutHistory(ctx, "group-1", userMessage("private"), 0)
+		require.NoError(t, err)
+
+		assert.Len(t, storage.data, 2)
+		got, _, err := perUser.GetHistory(ctx, "group-1")
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		assert.Equal(t, "private", got[0].(*history.UserMessage).Parts[0].(*history.UserTextPart).Text)
+	})
+
+	t.Run("per-user keying separates members of the same group", func(t *testing.T) {
+		storage := newMockStorage()
+		svc, err := history.NewService(storage, history.WithKeying(history.KeyingPerUser))
+		require.NoError(t, err)
+
+		_, err = svc.PutHistory(groupCtx(t, "user-1"), "group-1", userMessage("from user-1"), 0)
+		require.NoError(t, err)
+
+		got, gen, err := svc.GetHistory(groupCtx(t, "user-2"), "group-1")
+		require.NoError(t, err)
+		assert.Empty(t, got)
+		assert.Equal(t, int64(0), gen)
+
+		got, gen, err = svc.GetHistory(groupCtx(t, "user-1"), "group-1")
+		require.NoError(t, err)
+		assert.Len(t, got, 1)
+		assert.Equal(t, int64(1), gen)
+	})
+
+	t.Run("per-user keying reads and writes consistently", func(t *testing.T) {
+		storage := newMockStorage()
+		svc, err := history.NewService(storage, history.WithKeying(history.KeyingPerUser))
+		require.NoError(t, err)
+		ctx := groupCtx(t, "user-1")
+
+		gen, err := svc.PutHistory(ctx, "group-1", userMessage("first"), 0)
+		require.NoError(t, err)
+		hist, readGen, err := svc.GetHistory(ctx, "group-1")
+		require.NoError(t, err)
+		assert.Equal(t, gen, readGen)
+
+		_, err = svc.PutHistory(ctx, "group-1", append(hist, userMessage("second")...), readGen)
+		require.NoError(t, err)
+		hist, _, err = svc.GetHistory(ctx, "group-1")
+		require.NoError(t, err)
+		assert.Len(t, hist, 2)
+	})
+
+	t.Run("per-user keying keeps 1:1 chats keyed by source ID", func(t *testing.T) {
+		storage := newMockStorage()
+		svc, err := history.NewService(storage, history.WithKeying(history.KeyingPerUser))
+		require.NoError(t, err)
+
+		_, err = svc.PutHistory(groupCtx(t, "user-1"), "user-1", userMessage("hi"), 0)
+
+		require.NoError(t, err)
+		assert.Contains(t, storage.data, "user-1")
+	})
+
+	t.Run("per-user keying falls back to source ID without a user ID", func(t *testing.T) {
+		storage := newMockStorage()
+		svc, err := history.NewService(storage, history.WithKeying(history.KeyingPerUser))
+		require.NoError(t, err)
+
+		_, err = svc.PutHistory(t.Context(), "group-1", userMessage("hi"), 0)
+
+		require.NoError(t, err)
+		assert.Contains(t, storage.data, "group-1")
+	})
+
+	t.Run("per-user keying rejects user ID with invalid characters", func(t *testing.T) {
+		svc, err := history.NewService(newMockStorage(), history.WithKeying(history.KeyingPerUser))
+		require.NoError(t, err)
+
+		_, _, err = svc.GetHistory(groupCtx(t, "../user"), "group-1")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "userID contains invalid characters")
+	})
+}
+
 // =============================================================================
 // Mock Storage
 // =============================================================================
@@ -555,3 +903,83 @@ func (m *mockStorage) Write(ctx context.Context, key, mimetype string, data []by
 func (m *mockStorage) GetSignedURL(ctx context.Context, key, method string, ttl time.Duration) (string, error) {
 	return "", nil
 }
+
+// notFoundStorage reports a missing key as an error wrapping fs.ErrNotExist instead of nil data,
+// and records the expected generation of every write.
+type notFoundStorage struct {
+	*mockStorage
+	readErr          error
+	writeGenerations []int64
+}
+
+func (m *notFoundStorage) Read(ctx context.Context, key string) ([]byte, int64, error) {
+	if m.readErr != nil {
+		return nil, 0, m.readErr
+	}
+	if _, exists := m.data[key]; !exists {
+		return nil, 0, fmt.Errorf("read %s: %w", key, fs.ErrNotExist)
+	}
+	return m.mockStorage.Read(ctx, key)
+}
+
+func (m *notFoundStorage) Write(ctx context.Context, key, mimetype string, data []byte, expectedGeneration int64) (int64, error) {
+	m.writeGenerations = append(m.writeGenerations, expectedGeneration)
+	return m.mockStorage.Write(ctx, key, mimetype, data, expectedGeneration)
+}
+
+// conflictingStorage runs conflict right before the first write (or every write if alwaysConflict),
+// fails every write with writeErr if set, and counts writes.
+type conflictingStorage struct {
+	*mockStorage
+	conflict       func()
+	alwaysConflict bool
+	writeErr       error
+	writeCount     int
+}
+
+func (m *conflictingStorage) Write(ctx context.Context, key, mimetype string, data []byte, expectedGeneration int64) (int64, error) {
+	m.writeCount++
+	if m.conflict != nil && (m.writeCount == 1 || m.alwaysConflict) {
+		m.conflict()
+	}
+	if m.writeErr != nil {
+		return 0, m.writeErr
+	}
+	return m.mockStorage.Write(ctx, key, mimetype, data, expectedGeneration)
+}
+
+// lockedStorage makes mockStorage safe for concurrent use.
+type lockedStorage struct {
+	*mockStorage
+	mu sync.Mutex
+}
+
+func (m *lockedStorage) Read(ctx context.Context, key string) ([]byte, int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.mockStorage.Read(ctx, key)
+}
+
+func (m *lockedStorage) Write(ctx context.Context, key, mimetype string, data []byte, expectedGeneration int64) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.mockStorage.Write(ctx, key, mimetype, data, expectedGeneration)
+}
+
+// userText returns a user message with a single text part.
+func userText(text string, at time.Time) history.Message {
+	return &history.UserMessage{
+		UserID:    "U123",
+		Parts:     []history.UserPart{&history.UserTextPart{Text: text}},
+		Timestamp: at,
+	}
+}
+
+// texts returns the text of the first part of each user message.
+func texts(messages []history.Message) []string {
+	var out []string
+	for _, msg := range messages {
+		out = append(out, msg.(*history.UserMessage).Parts[0].(*history.UserTextPart).Text)
+	}
+	return out
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"yuruppu/internal/sanitize"
 )
 
 func serializeJSONL(messages []Message) ([]byte, error) {
@@ -54,7 +55,7 @@ func convertUserPartsToJSON(parts []UserPart) ([]part, error) {
 		case *UserTextPart:
 			result = append(result, part{
 				Type: "text",
-				Text: v.Text,
+				Text: sanitize.Text(v.Text),
 			})
 		case *UserFileDataPart:
 			filePart := part{
@@ -85,7 +86,7 @@ func convertAssistantPartsToJSON(parts []AssistantPart) ([]part, error) {
 		case *AssistantTextPart:
 			result = append(result, part{
 				Type:             "text",
-				Text:             v.Text,
+				Text:             sanitize.Text(v.Text),
 				Thought:          v.Thought,
 				ThoughtSignature: v.ThoughtSignature,
 			})
@@ -13,16 +13,35 @@ import (
 
 // Client sends messages via LINE Messaging API.
 type Client struct {
-	api     *messaging_api.MessagingApiAPI
-	blobAPI *messaging_api.MessagingApiBlobAPI
-	logger  *slog.Logger
+	api            *messaging_api.MessagingApiAPI
+	blobAPI        *messaging_api.MessagingApiBlobAPI
+	deadLetterSink DeadLetterSink
+	replySources   replySources
+	sentReplies    sentReplies
+	logger         *slog.Logger
+}
+
+// Option configures optional Client behavior.
+type Option func(*options)
+
+type options struct {
+	apiEndpoint    string
+	deadLetterSink DeadLetterSink
+}
+
+// WithAPIEndpoint overrides the base URL of the Messaging API (not the blob API).
+// Defaults to the LINE production endpoint.
+func WithAPIEndpoint(endpoint string) Option {
+	return func(o *options) {
+		o.apiEndpoint = endpoint
+	}
 }
 
 // NewClient creates a new LINE messaging client.
 // channelToken is the LINE channel access token for API calls.
 // logger is the structured logger for the client.
 // Returns an error if channelToken is empty after trimming whitespace.
-func NewClient(channelToken string, logger *slog.Logger) (*Client, error) {
+func NewClient(channelToken string, logger *slog.Logger, opts ...Option) (*Client, error) {
 	channelToken = strings.TrimSpace(channelToken)
 	if channelToken == "" {
 		return nil, errors.New("missing required configuration: channelToken")
@@ -31,8 +50,20 @@ func NewClient(channelToken string, logger *slog.Logger) (*Client, error) {
 		return nil, errors.New("missing required configuration: logger")
 	}
 
+	o := options{deadLetterSink: NopDeadLetterSink{}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.deadLetterSink == nil {
+		o.deadLetterSink = NopDeadLetterSink{}
+	}
+	var apiOpts []messaging_api.MessagingApiAPIOption
+	if o.apiEndpoint != "" {
+		apiOpts = append(apiOpts, messaging_api.WithEndpoint(o.apiEndpoint))
+	}
+
 	// Create messaging API client using LINE SDK
-	api, err := messaging_api.NewMessagingApiAPI(channelToken)
+	api, err := messaging_api.NewMessagingApiAPI(channelToken, apiOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create LINE messaging API client: %w", err)
 	}
@@ -44,9 +75,10 @@ func NewClient(channelToken string, logger *slog.Logger) (*Client, error) {
 	}
 
 	return &Client{
-		api:     api,
-		blobAPI: blobAPI,
-		logger:  logger,
+		api:            api,
+		blobAPI:        blobAPI,
+		deadLetterSink: o.deadLetterSink,
+		logger:         logger,
 	}, nil
 }
 
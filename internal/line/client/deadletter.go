@@ -0,0 +1,76 @@
+package client
+
+import (
+	"encoding/json"
+	"log/slog"
+	"time"
+)
+
+// DeadLetter describes a message that the LINE API failed to accept.
+type DeadLetter struct {
+	Kind    string          `json:"kind"`    // reply, flex_reply, push, or multicast
+	Payload json.RawMessage `json:"payload"` // The request body sent to the LINE API
+	Reason  string          `json:"reason"`  // The error returned by the API call
+	At      time.Time       `json:"at"`
+}
+
+// DeadLetterSink receives messages that could not be sent so they can be audited or replayed.
+// Implementations must be safe for concurrent use and must not block for long.
+type DeadLetterSink interface {
+	Record(dl DeadLetter)
+}
+
+// NopDeadLetterSink discards dead letters. It is the default sink.
+type NopDeadLetterSink struct{}
+
+// Record does nothing.
+func (NopDeadLetterSink) Record(DeadLetter) {}
+
+// LogDeadLetterSink writes each dead letter as an error log with its full payload.
+type LogDeadLetterSink struct {
+	logger *slog.Logger
+}
+
+// NewLogDeadLetterSink creates a LogDeadLetterSink that writes to logger.
+func NewLogDeadLetterSink(logger *slog.Logger) *LogDeadLetterSink {
+	if logger == nil {
+		panic("logger cannot be nil")
+	}
+	return &LogDeadLetterSink{logger: logger}
+}
+
+// Record logs the dead letter.
+func (s *LogDeadLetterSink) Record(dl DeadLetter) {
+	s.logger.Error("message could not be sent",
+		slog.String("kind", dl.Kind),
+		slog.String("reason", dl.Reason),
+		slog.Time("at", dl.At),
+		slog.String("payload", string(dl.Payload)),
+	)
+}
+
+// WithDeadLetterSink sets where messages that fail to send are recorded.
+// Defaults to NopDeadLetterSink.
+func WithDeadLetterSink(sink DeadLetterSink) Option {
+	return func(o *options) {
+		o.deadLetterSink = sink
+	}
+}
+
+// recordDeadLetter passes a failed request to the dead letter sink.
+func (c *Client) recordDeadLetter(kind string, request any, err error) {
+	payload, marshalErr := json.Marshal(request)
+	if marshalErr != nil {
+		c.logger.Error("failed to marshal dead letter payload",
+			slog.String("kind", kind),
+			slog.Any("error", marshalErr),
+		)
+		payload = nil
+	}
+	c.deadLetterSink.Record(DeadLetter{
+		Kind:    kind,
+		Payload: payload,
+		Reason:  err.Error(),
+		At:      time.Now(),
+	})
+}
@@ -0,0 +1,158 @@
+package client_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+	"yuruppu/internal/line/client"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// Dead Letter Tests
+// =============================================================================
+
+func TestClient_DeadLetter(t *testing.T) {
+	t.Run("failed reply records payload and reason", func(t *testing.T) {
+		sink := &recordingSink{}
+		c := newDeadLetterClient(t, http.StatusTooManyRequests, sink)
+
+		err := c.SendReply("reply-token", "hello")
+
+		require.Error(t, err)
+		dl := sink.only(t)
+		assert.Equal(t, "reply", dl.Kind)
+		assert.Contains(t, dl.Reason, "429")
+		assert.WithinDuration(t, time.Now(), dl.At, time.Minute)
+		var payload struct {
+			ReplyToken string `json:"replyToken"`
+			Messages   []struct {
+				Text string `json:"text"`
+			} `json:"messages"`
+		}
+		require.NoError(t, json.Unmarshal(dl.Payload, &payload))
+		assert.Equal(t, "reply-token", payload.ReplyToken)
+		require.Len(t, payload.Messages, 1)
+		assert.Equal(t, "hello", payload.Messages[0].Text)
+	})
+
+	t.Run("failed flex reply records the flex payload", func(t *testing.T) {
+		sink := &recordingSink{}
+		c := newDeadLetterClient(t, http.StatusBadRequest, sink)
+
+		err := c.SendFlexReply("reply-token", "alt", []byte(`{"type":"bubble","body":{"type":"box","layout":"vertical","contents":[]}}`))
+
+		require.Error(t, err)
+		dl := sink.only(t)
+		assert.Equal(t, "flex_reply", dl.Kind)
+		assert.Contains(t, string(dl.Payload), `"altText":"alt"`)
+		assert.Contains(t, string(dl.Payload), `"bubble"`)
+	})
+
+	t.Run("failed push records the recipient and text", func(t *testing.T) {
+		sink := &recordingSink{}
+		c := newDeadLetterClient(t, http.StatusInternalServerError, sink)
+
+		err := c.SendPush("user-1", "reminder")
+
+		require.Error(t, err)
+		dl := sink.only(t)
+		assert.Equal(t, "push", dl.Kind)
+		assert.Contains(t, string(dl.Payload), `"to":"user-1"`)
+		assert.Contains(t, string(dl.Payload), `"reminder"`)
+		assert.Contains(t, dl.Reason, "LINE API push failed")
+	})
+
+	t.Run("successful send records nothing", func(t *testing.T) {
+		sink := &recordingSink{}
+		c := newDeadLetterClient(t, http.StatusOK, sink)
+
+		err := c.SendPush("user-1", "reminder")
+
+		require.NoError(t, err)
+		assert.Empty(t, sink.records)
+	})
+
+	t.Run("failed send without a sink still returns the error", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, `{}`, http.StatusInternalServerError)
+		}))
+		t.Cleanup(srv.Close)
+		c, err := client.NewClient("test-token", slog.New(slog.DiscardHandler), client.WithAPIEndpoint(srv.URL))
+		require.NoError(t, err)
+
+		err = c.SendPush("user-1", "reminder")
+
+		require.Error(t, err)
+	})
+}
+
+func TestLogDeadLetterSink(t *testing.T) {
+	t.Run("logs the payload and reason", func(t *testing.T) {
+		var buf bytes.Buffer
+		sink := client.NewLogDeadLetterSink(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+		sink.Record(client.DeadLetter{
+			Kind:    "push",
+			Payload: json.RawMessage(`{"to":"user-1"}`),
+			Reason:  "rate limited",
+			At:      time.Now(),
+		})
+
+		assert.Contains(t, buf.String(), `"level":"ERROR"`)
+		assert.Contains(t, buf.String(), `"kind":"push"`)
+		assert.Contains(t, buf.String(), `"reason":"rate limited"`)
+		assert.Contains(t, buf.String(), `user-1`)
+	})
+
+	t.Run("panics when logger is nil", func(t *testing.T) {
+		assert.Panics(t, func() { client.NewLogDeadLetterSink(nil) })
+	})
+}
+
+// =============================================================================
+// Helpers
+// =============================================================================
+
+type recordingSink struct {
+	mu      sync.Mutex
+	records []client.DeadLetter
+}
+
+func (s *recordingSink) Record(dl client.DeadLetter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, dl)
+}
+
+func (s *recordingSink) only(t *testing.T) client.DeadLetter {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	require.Len(t, s.records, 1)
+	return s.records[0]
+}
+
+// newDeadLetterClient creates a client against a fake LINE API that answers every request with status.
+func newDeadLetterClient(t *testing.T, status int, sink client.DeadLetterSink) *client.Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(srv.Close)
+	c, err := client.NewClient("test-token", slog.New(slog.DiscardHandler),
+		client.WithAPIEndpoint(srv.URL),
+		client.WithDeadLetterSink(sink),
+	)
+	require.NoError(t, err)
+	return c
+}
@@ -1,9 +1,14 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"time"
+	"yuruppu/internal/line"
 
+	"github.com/google/uuid"
 	"github.com/line/line-bot-sdk-go/v8/linebot/messaging_api"
 )
 
@@ -29,26 +34,7 @@ func (c *Client) SendReply(replyToken string, text string) error {
 		},
 	}
 
-	// Call LINE ReplyMessage API with HTTP info for x-line-request-id
-	httpResp, _, err := c.api.ReplyMessageWithHttpInfo(request)
-	if httpResp != nil && httpResp.Body != nil {
-		defer httpResp.Body.Close()
-	}
-
-	// Extract x-line-request-id for debugging (available even on error)
-	var requestID string
-	if httpResp != nil {
-		requestID = httpResp.Header.Get("X-Line-Request-Id")
-	}
-
-	if err != nil {
-		return fmt.Errorf("LINE API reply failed (x-line-request-id=%s): %w", requestID, err)
-	}
-
-	c.logger.Debug("reply sent successfully",
-		slog.String("x-line-request-id", requestID),
-	)
-	return nil
+	return c.reply("reply", request)
 }
 
 // SendFlexReply sends a flex message reply using the LINE Messaging API.
@@ -77,6 +63,111 @@ func (c *Client) SendFlexReply(replyToken string, altText string, flexJSON []byt
 		},
 	}
 
+	return c.reply("flex_reply", request)
+}
+
+// SendPush sends a text message to a user, group, or room using the LINE Messaging API.
+// Unlike SendReply, no reply token is needed, so it can be used outside of webhook handling.
+// to is the user, group, or room ID.
+// text is the message text to send.
+// Returns any error encountered during the API call.
+func (c *Client) SendPush(to string, text string) error {
+	c.logger.Debug("sending push",
+		slog.String("to", to),
+		slog.Int("textLength", len(text)),
+	)
+
+	request := &messaging_api.PushMessageRequest{
+		To: to,
+		Messages: []messaging_api.MessageInterface{
+			messaging_api.TextMessage{Text: text},
+		},
+	}
+
+	return c.push(context.Background(), request)
+}
+
+// SendFlexPush sends a flex message to a user, group, or room without a reply token.
+// to is the user, group, or room ID.
+// altText is the alternative text to display when flex message is not supported.
+// flexJSON is the flex message container JSON.
+// Returns any error encountered during the API call.
+func (c *Client) SendFlexPush(to string, altText string, flexJSON []byte) error {
+	container, err := messaging_api.UnmarshalFlexContainer(flexJSON)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal flex container: %w", err)
+	}
+	return c.push(context.Background(), &messaging_api.PushMessageRequest{
+		To: to,
+		Messages: []messaging_api.MessageInterface{
+			messaging_api.FlexMessage{
+				AltText:  altText,
+				Contents: container,
+			},
+		},
+	})
+}
+
+// Send sends a text message for the event in ctx.
+// It replies when ctx holds a fresh reply token and pushes to the source otherwise;
+// see line.ChooseDelivery for the rules.
+// Returns any error encountered during the API call.
+func (c *Client) Send(ctx context.Context, text string) error {
+	c.logger.DebugContext(ctx, "sending message",
+		slog.Int("textLength", len(text)),
+	)
+	return c.send(ctx, "reply", messaging_api.TextMessage{Text: text})
+}
+
+// SendFlex sends a flex message for the event in ctx, choosing reply or push like Send.
+// altText is the alternative text to display when flex message is not supported.
+// flexJSON is the flex message container JSON.
+// Returns any error encountered during the API call.
+func (c *Client) SendFlex(ctx context.Context, altText string, flexJSON []byte) error {
+	container, err := messaging_api.UnmarshalFlexContainer(flexJSON)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal flex container: %w", err)
+	}
+	return c.send(ctx, "flex_reply", messaging_api.FlexMessage{
+		AltText:  altText,
+		Contents: container,
+	})
+}
+
+// send delivers messages by the method line.ChooseDelivery picks for ctx.
+// replyKind names the message type for dead letters when replying.
+func (c *Client) send(ctx context.Context, replyKind string, messages ...messaging_api.MessageInterface) error {
+	delivery, err := line.ChooseDelivery(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	switch delivery.Method {
+	case line.DeliveryReply:
+		// Remember the source so an expired token still reaches it by push
+		c.RegisterReplySource(delivery.ReplyToken, delivery.To)
+		return c.reply(replyKind, &messaging_api.ReplyMessageRequest{
+			ReplyToken: delivery.ReplyToken,
+			Messages:   messages,
+		})
+	case line.DeliveryPush:
+		return c.push(ctx, &messaging_api.PushMessageRequest{
+			To:       delivery.To,
+			Messages: messages,
+		})
+	}
+	return fmt.Errorf("unknown delivery method: %s", delivery.Method)
+}
+
+// reply calls the LINE ReplyMessage API.
+// kind names the message type for dead letters and logs.
+// If the reply token has expired and its source is known, the messages are pushed instead.
+// A reply identical to one already delivered, or being delivered, with the same token is dropped without calling the API.
+func (c *Client) reply(kind string, request *messaging_api.ReplyMessageRequest) error {
+	release, ok := c.reserveReply(kind, request)
+	if !ok {
+		return nil
+	}
+
 	// Call LINE ReplyMessage API with HTTP info for x-line-request-id
 	httpResp, _, err := c.api.ReplyMessageWithHttpInfo(request)
 	if httpResp != nil && httpResp.Body != nil {
@@ -90,10 +181,60 @@ func (c *Client) SendFlexReply(replyToken string, altText string, flexJSON []byt
 	}
 
 	if err != nil {
-		return fmt.Errorf("LINE API reply failed (x-line-request-id=%s): %w", requestID, err)
+		err = fmt.Errorf("LINE API reply failed (x-line-request-id=%s): %w", requestID, err)
+		if wait, ok := retryAfter(httpResp, time.Now()); ok {
+			// The reply token may expire while waiting, so replies are never retried
+			c.logger.Warn("LINE API rate limited reply, not retrying",
+				slog.String("kind", kind),
+				slog.Duration("retryAfter", wait),
+			)
+		}
+		if isReplyTokenExpired(httpResp, err) {
+			if pushed, pushErr := c.pushReplyFallback(kind, request.ReplyToken, request.Messages, err); pushed {
+				if pushErr != nil {
+					release()
+				}
+				return pushErr
+			}
+		}
+		release()
+		c.recordDeadLetter(kind, request, err)
+		return err
+	}
+
+	c.logger.Debug("reply sent successfully",
+		slog.String("kind", kind),
+		slog.String("x-line-request-id", requestID),
+	)
+	return nil
+}
+
+// push calls the LINE PushMessage API.
+// Rate-limited requests are retried after the Retry-After LINE asks for, within ctx.
+// All attempts share one retry key, so LINE delivers the messages at most once.
+func (c *Client) push(ctx context.Context, request *messaging_api.PushMessageRequest) error {
+	// Call LINE PushMessage API with HTTP info for x-line-request-id
+	retryKey := uuid.NewString()
+	httpResp, err := c.withRateLimitRetry(ctx, "push", func() (*http.Response, error) {
+		httpResp, _, err := c.api.PushMessageWithHttpInfo(request, retryKey)
+		return httpResp, err
+	})
+	if httpResp != nil && httpResp.Body != nil {
+		defer httpResp.Body.Close()
+	}
+
+	var requestID string
+	if httpResp != nil {
+		requestID = httpResp.Header.Get("X-Line-Request-Id")
+	}
+
+	if err != nil {
+		err = fmt.Errorf("LINE API push failed (x-line-request-id=%s): %w", requestID, err)
+		c.recordDeadLetter("push", request, err)
+		return err
 	}
 
-	c.logger.Debug("flex reply sent successfully",
+	c.logger.Debug("push sent successfully",
 		slog.String("x-line-request-id", requestID),
 	)
 	return nil
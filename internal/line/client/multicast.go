@@ -0,0 +1,86 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/line/line-bot-sdk-go/v8/linebot/messaging_api"
+)
+
+// MaxMulticastRecipients is the maximum number of user IDs LINE accepts in one multicast request.
+const MaxMulticastRecipients = 500
+
+// Multicast sends the same text messages to many users using the LINE Messaging API.
+// to is the list of user IDs; group and room IDs are not accepted by LINE.
+// Recipients are sent in chunks of MaxMulticastRecipients. A failed chunk does not stop the remaining ones;
+// the errors of all failed chunks are joined and returned.
+func (c *Client) Multicast(ctx context.Context, to []string, messages ...string) error {
+	if len(messages) == 0 {
+		return errors.New("at least one message is required")
+	}
+	if len(to) == 0 {
+		return nil
+	}
+
+	lineMessages := make([]messaging_api.MessageInterface, len(messages))
+	for i, text := range messages {
+		lineMessages[i] = messaging_api.TextMessage{Text: text}
+	}
+
+	var errs []error
+	for start := 0; start < len(to); start += MaxMulticastRecipients {
+		end := min(start+MaxMulticastRecipients, len(to))
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, fmt.Errorf("multicast to recipients %d-%d skipped: %w", start, end-1, err))
+			break
+		}
+		if err := c.multicast(ctx, to[start:end], lineMessages); err != nil {
+			errs = append(errs, fmt.Errorf("multicast to recipients %d-%d failed: %w", start, end-1, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// multicast sends one multicast request to at most MaxMulticastRecipients users.
+// Rate-limited requests are retried like push.
+func (c *Client) multicast(ctx context.Context, to []string, messages []messaging_api.MessageInterface) error {
+	c.logger.Debug("sending multicast",
+		slog.Int("recipients", len(to)),
+		slog.Int("messages", len(messages)),
+	)
+
+	request := &messaging_api.MulticastRequest{
+		To:       to,
+		Messages: messages,
+	}
+
+	// Call LINE Multicast API with HTTP info for x-line-request-id
+	retryKey := uuid.NewString()
+	httpResp, err := c.withRateLimitRetry(ctx, "multicast", func() (*http.Response, error) {
+		httpResp, _, err := c.api.MulticastWithHttpInfo(request, retryKey)
+		return httpResp, err
+	})
+	if httpResp != nil && httpResp.Body != nil {
+		defer httpResp.Body.Close()
+	}
+
+	var requestID string
+	if httpResp != nil {
+		requestID = httpResp.Header.Get("X-Line-Request-Id")
+	}
+
+	if err != nil {
+		err = fmt.Errorf("LINE API multicast failed (x-line-request-id=%s): %w", requestID, err)
+		c.recordDeadLetter("multicast", request, err)
+		return err
+	}
+
+	c.logger.Debug("multicast sent successfully",
+		slog.String("x-line-request-id", requestID),
+	)
+	return nil
+}
@@ -0,0 +1,150 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"yuruppu/internal/line/client"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// Multicast Tests
+// =============================================================================
+
+func TestClient_Multicast(t *testing.T) {
+	t.Run("splits 1200 recipients into three requests", func(t *testing.T) {
+		srv := newMulticastServer(t, nil)
+		c := newTestClient(t, srv.URL)
+
+		err := c.Multicast(t.Context(), userIDs(1200), "hello")
+
+		require.NoError(t, err)
+		require.Len(t, srv.requests, 3)
+		assert.Len(t, srv.requests[0].To, 500)
+		assert.Len(t, srv.requests[1].To, 500)
+		assert.Len(t, srv.requests[2].To, 200)
+		assert.Equal(t, "user-0", srv.requests[0].To[0])
+		assert.Equal(t, "user-500", srv.requests[1].To[0])
+		assert.Equal(t, "user-1199", srv.requests[2].To[199])
+		for _, req := range srv.requests {
+			require.Len(t, req.Messages, 1)
+			assert.Equal(t, "hello", req.Messages[0].Text)
+		}
+	})
+
+	t.Run("continues after a failed chunk and aggregates errors", func(t *testing.T) {
+		srv := newMulticastServer(t, map[int]bool{1: true, 3: true})
+		c := newTestClient(t, srv.URL)
+
+		err := c.Multicast(t.Context(), userIDs(1600), "hello")
+
+		require.Error(t, err)
+		assert.Len(t, srv.requests, 4)
+		assert.Contains(t, err.Error(), "recipients 0-499")
+		assert.Contains(t, err.Error(), "recipients 1000-1499")
+		assert.NotContains(t, err.Error(), "recipients 500-999")
+		assert.NotContains(t, err.Error(), "recipients 1500-1599")
+	})
+
+	t.Run("sends nothing when there are no recipients", func(t *testing.T) {
+		srv := newMulticastServer(t, nil)
+		c := newTestClient(t, srv.URL)
+
+		err := c.Multicast(t.Context(), nil, "hello")
+
+		require.NoError(t, err)
+		assert.Empty(t, srv.requests)
+	})
+
+	t.Run("returns error when no messages are given", func(t *testing.T) {
+		srv := newMulticastServer(t, nil)
+		c := newTestClient(t, srv.URL)
+
+		err := c.Multicast(t.Context(), userIDs(3))
+
+		require.Error(t, err)
+		assert.Empty(t, srv.requests)
+	})
+
+	t.Run("stops sending when the context is canceled", func(t *testing.T) {
+		srv := newMulticastServer(t, nil)
+		c := newTestClient(t, srv.URL)
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		err := c.Multicast(ctx, userIDs(10), "hello")
+
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, srv.requests)
+	})
+}
+
+// =============================================================================
+// Helpers
+// =============================================================================
+
+type multicastRequest struct {
+	To       []string `json:"to"`
+	Messages []struct {
+		Text string `json:"text"`
+	} `json:"messages"`
+}
+
+type multicastServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []multicastRequest
+}
+
+// newMulticastServer starts a fake LINE API. Requests whose 1-based index is in failOn get a 500 response.
+func newMulticastServer(t *testing.T, failOn map[int]bool) *multicastServer {
+	t.Helper()
+	s := &multicastServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/bot/message/multicast" {
+			http.NotFound(w, r)
+			return
+		}
+		var req multicastRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.mu.Lock()
+		s.requests = append(s.requests, req)
+		n := len(s.requests)
+		s.mu.Unlock()
+
+		if failOn[n] {
+			http.Error(w, `{"message":"internal error"}`, http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func newTestClient(t *testing.T, endpoint string) *client.Client {
+	t.Helper()
+	c, err := client.NewClient("test-token", slog.New(slog.DiscardHandler), client.WithAPIEndpoint(endpoint))
+	require.NoError(t, err)
+	return c
+}
+
+func userIDs(n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("user-%d", i)
+	}
+	return ids
+}
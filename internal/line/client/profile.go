@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"yuruppu/internal/line"
 )
 
 // UserProfile contains LINE user profile information.
@@ -11,6 +13,8 @@ type UserProfile struct {
 	DisplayName   string
 	PictureURL    string
 	StatusMessage string
+	Language      string // BCP 47 tag; empty if the user has not shared it
+	Timezone      string // IANA name; LINE does not provide this, so it is empty for real users
 }
 
 // GroupSummary contains LINE group summary information.
@@ -35,6 +39,7 @@ func (c *Client) GetUserProfile(ctx context.Context, userID string) (*UserProfil
 		DisplayName:   resp.DisplayName,
 		PictureURL:    resp.PictureUrl,
 		StatusMessage: resp.StatusMessage,
+		Language:      resp.Language,
 	}
 
 	c.logger.DebugContext(ctx, "user profile fetched successfully",
@@ -45,6 +50,56 @@ func (c *Client) GetUserProfile(ctx context.Context, userID string) (*UserProfil
 	return profile, nil
 }
 
+// GetProfile fetches a user's display name and picture URL from LINE API.
+// In a group context (chat type and source ID in ctx), the group member profile endpoint is used,
+// which works for members who have not added the bot as a friend.
+// The picture URL is empty if the user has no profile image.
+func (c *Client) GetProfile(ctx context.Context, userID string) (string, string, error) {
+	chatType, _ := line.ChatTypeFromContext(ctx)
+	groupID, _ := line.SourceIDFromContext(ctx)
+	if chatType == line.ChatTypeGroup && groupID != "" {
+		c.logger.DebugContext(ctx, "fetching group member profile",
+			slog.String("groupID", groupID),
+			slog.String("userID", userID),
+		)
+		resp, err := c.api.GetGroupMemberProfile(groupID, userID)
+		if err != nil {
+			return "", "", fmt.Errorf("LINE API GetGroupMemberProfile failed: %w", err)
+		}
+		return resp.DisplayName, resp.PictureUrl, nil
+	}
+
+	c.logger.DebugContext(ctx, "fetching profile",
+		slog.String("userID", userID),
+	)
+	resp, err := c.api.GetProfile(userID)
+	if err != nil {
+		return "", "", fmt.Errorf("LINE API GetProfile failed: %w", err)
+	}
+	return resp.DisplayName, resp.PictureUrl, nil
+}
+
+// IsGroupMember reports whether userID is currently a member of the group.
+// LINE answers 404 for users who are not members, which is reported as false rather than an error.
+func (c *Client) IsGroupMember(ctx context.Context, groupID, userID string) (bool, error) {
+	c.logger.DebugContext(ctx, "checking group membership",
+		slog.String("groupID", groupID),
+		slog.String("userID", userID),
+	)
+
+	httpResp, _, err := c.api.GetGroupMemberProfileWithHttpInfo(groupID, userID)
+	if httpResp != nil && httpResp.Body != nil {
+		defer httpResp.Body.Close()
+	}
+	if err != nil {
+		if httpResp != nil && httpResp.StatusCode == http.StatusNotFound {
+			return false, nil
+		}
+		return false, fmt.Errorf("LINE API GetGroupMemberProfile failed: %w", err)
+	}
+	return true, nil
+}
+
 // GetGroupSummary fetches group summary from LINE API.
 func (c *Client) GetGroupSummary(ctx context.Context, groupID string) (*GroupSummary, error) {
 	c.logger.DebugContext(ctx, "fetching group summary",
@@ -89,3 +144,18 @@ func (c *Client) GetGroupMemberCount(ctx context.Context, groupID string) (int,
 
 	return count, nil
 }
+
+// GetBotUserID fetches the bot's own user ID from LINE API.
+// It identifies the bot among the mentionees of incoming messages.
+func (c *Client) GetBotUserID(ctx context.Context) (string, error) {
+	resp, err := c.api.GetBotInfo()
+	if err != nil {
+		return "", fmt.Errorf("LINE API GetBotInfo failed: %w", err)
+	}
+
+	c.logger.DebugContext(ctx, "bot info fetched successfully",
+		slog.String("botUserID", resp.UserId),
+	)
+
+	return resp.UserId, nil
+}
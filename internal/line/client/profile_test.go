@@ -0,0 +1,155 @@
+package client_test
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"yuruppu/internal/line"
+	"yuruppu/internal/line/client"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// GetProfile Tests
+// =============================================================================
+
+func TestClient_GetProfile(t *testing.T) {
+	newProfileClient := func(t *testing.T) (*client.Client, *[]string) {
+		t.Helper()
+		var paths []string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.Path)
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Path {
+			case "/v2/bot/profile/user-1":
+				_, _ = w.Write([]byte(`{"userId":"user-1","displayName":"Alice","pictureUrl":"https://example.com/alice.png"}`))
+			case "/v2/bot/group/group-1/member/user-1":
+				_, _ = w.Write([]byte(`{"userId":"user-1","displayName":"Alice in group"}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"message":"Not found"}`))
+			}
+		}))
+		t.Cleanup(srv.Close)
+		c, err := client.NewClient("test-token", slog.New(slog.DiscardHandler), client.WithAPIEndpoint(srv.URL))
+		require.NoError(t, err)
+		return c, &paths
+	}
+
+	t.Run("uses the profile endpoint outside a group", func(t *testing.T) {
+		c, paths := newProfileClient(t)
+
+		name, pictureURL, err := c.GetProfile(t.Context(), "user-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, "Alice", name)
+		assert.Equal(t, "https://example.com/alice.png", pictureURL)
+		assert.Equal(t, []string{"/v2/bot/profile/user-1"}, *paths)
+	})
+
+	t.Run("uses the group member endpoint in a group", func(t *testing.T) {
+		c, paths := newProfileClient(t)
+		ctx := line.WithChatType(t.Context(), line.ChatTypeGroup)
+		ctx = line.WithSourceID(ctx, "group-1")
+
+		name, pictureURL, err := c.GetProfile(ctx, "user-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, "Alice in group", name)
+		assert.Empty(t, pictureURL)
+		assert.Equal(t, []string{"/v2/bot/group/group-1/member/user-1"}, *paths)
+	})
+
+	t.Run("returns error for an unknown user", func(t *testing.T) {
+		c, _ := newProfileClient(t)
+
+		_, _, err := c.GetProfile(t.Context(), "user-404")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "GetProfile failed")
+	})
+}
+
+// =============================================================================
+// IsGroupMember Tests
+// =============================================================================
+
+func TestClient_IsGroupMember(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v2/bot/group/group-1/member/user-1":
+			_, _ = w.Write([]byte(`{"userId":"user-1","displayName":"Alice"}`))
+		case "/v2/bot/group/group-1/member/user-500":
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"message":"Internal error"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not found"}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	c, err := client.NewClient("test-token", slog.New(slog.DiscardHandler), client.WithAPIEndpoint(srv.URL))
+	require.NoError(t, err)
+
+	t.Run("returns true for a member", func(t *testing.T) {
+		isMember, err := c.IsGroupMember(t.Context(), "group-1", "user-1")
+
+		require.NoError(t, err)
+		assert.True(t, isMember)
+	})
+
+	t.Run("returns false for a non-member", func(t *testing.T) {
+		isMember, err := c.IsGroupMember(t.Context(), "group-1", "user-2")
+
+		require.NoError(t, err)
+		assert.False(t, isMember)
+	})
+
+	t.Run("returns error on other API failures", func(t *testing.T) {
+		_, err := c.IsGroupMember(t.Context(), "group-1", "user-500")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "GetGroupMemberProfile failed")
+	})
+}
+
+// =============================================================================
+// GetBotUserID Tests
+// =============================================================================
+
+func TestClient_GetBotUserID(t *testing.T) {
+	t.Run("returns the bot's user ID", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/v2/bot/info", r.URL.Path)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"userId":"Ubot","basicId":"@yuruppu","displayName":"Yuruppu","chatMode":"bot","markAsReadMode":"auto"}`))
+		}))
+		t.Cleanup(srv.Close)
+		c, err := client.NewClient("test-token", slog.New(slog.DiscardHandler), client.WithAPIEndpoint(srv.URL))
+		require.NoError(t, err)
+
+		userID, err := c.GetBotUserID(t.Context())
+
+		require.NoError(t, err)
+		assert.Equal(t, "Ubot", userID)
+	})
+
+	t.Run("returns error when the API fails", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"message":"Internal error"}`))
+		}))
+		t.Cleanup(srv.Close)
+		c, err := client.NewClient("test-token", slog.New(slog.DiscardHandler), client.WithAPIEndpoint(srv.URL))
+		require.NoError(t, err)
+
+		_, err = c.GetBotUserID(t.Context())
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "GetBotInfo failed")
+	})
+}
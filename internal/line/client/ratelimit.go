@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// maxRateLimitRetries is how many times a rate-limited push or multicast is retried.
+	maxRateLimitRetries = 2
+	// maxRetryAfter is the longest Retry-After the client waits for; longer requests fail immediately.
+	maxRetryAfter = 30 * time.Second
+)
+
+// retryAfter returns the wait LINE asked for in a 429 response.
+// Retry-After may be given in seconds or as an HTTP date.
+// Returns false if resp is not a 429 or carries no usable Retry-After.
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp == nil || resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	value := strings.TrimSpace(resp.Header.Get("Retry-After"))
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// withRateLimitRetry calls call and retries it while LINE answers 429 with a Retry-After,
+// waiting as asked. Retries stop after maxRateLimitRetries, when the wait exceeds maxRetryAfter,
+// or when ctx would end before the wait does; the last response and error are returned then.
+// Only use it for calls that are safe to repeat, never for replies whose token may expire.
+func (c *Client) withRateLimitRetry(ctx context.Context, kind string, call func() (*http.Response, error)) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		httpResp, err := call()
+		if err == nil || attempt > maxRateLimitRetries {
+			return httpResp, err
+		}
+		wait, ok := retryAfter(httpResp, time.Now())
+		if !ok {
+			return httpResp, err
+		}
+		if wait > maxRetryAfter {
+			c.logger.WarnContext(ctx, "LINE API rate limited, Retry-After too long to wait",
+				slog.String("kind", kind),
+				slog.Duration("retryAfter", wait),
+			)
+			return httpResp, err
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			c.logger.WarnContext(ctx, "LINE API rate limited, Retry-After exceeds the context deadline",
+				slog.String("kind", kind),
+				slog.Duration("retryAfter", wait),
+			)
+			return httpResp, err
+		}
+
+		c.logger.WarnContext(ctx, "LINE API rate limited, retrying",
+			slog.String("kind", kind),
+			slog.Duration("retryAfter", wait),
+			slog.Int("attempt", attempt),
+		)
+		if httpResp.Body != nil {
+			_ = httpResp.Body.Close()
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return httpResp, err
+		case <-timer.C:
+		}
+	}
+}
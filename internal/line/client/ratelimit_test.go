@@ -0,0 +1,148 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+	"yuruppu/internal/line"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// Rate Limit Retry Tests
+// =============================================================================
+
+func TestClient_RateLimitRetry(t *testing.T) {
+	t.Run("push retries after Retry-After", func(t *testing.T) {
+		srv := newRateLimitServer(t, 1, "1")
+		c := newTestClient(t, srv.URL)
+
+		start := time.Now()
+		err := c.SendPush("group-1", "hello")
+
+		require.NoError(t, err)
+		require.Len(t, srv.requests, 2)
+		assert.GreaterOrEqual(t, srv.requests[1].at.Sub(srv.requests[0].at), time.Second, "retry should wait for Retry-After")
+		assert.GreaterOrEqual(t, time.Since(start), time.Second)
+		assert.NotEmpty(t, srv.requests[0].retryKey)
+		assert.Equal(t, srv.requests[0].retryKey, srv.requests[1].retryKey, "attempts should share one retry key")
+	})
+
+	t.Run("multicast retries after Retry-After", func(t *testing.T) {
+		srv := newRateLimitServer(t, 1, "0")
+		c := newTestClient(t, srv.URL)
+
+		err := c.Multicast(t.Context(), []string{"user-1"}, "hello")
+
+		require.NoError(t, err)
+		require.Len(t, srv.requests, 2)
+		assert.Equal(t, "/v2/bot/message/multicast", srv.requests[1].path)
+	})
+
+	t.Run("reply fails fast without retrying", func(t *testing.T) {
+		srv := newRateLimitServer(t, 1, "1")
+		c := newTestClient(t, srv.URL)
+
+		start := time.Now()
+		err := c.SendReply("reply-token", "hello")
+
+		require.Error(t, err)
+		assert.Len(t, srv.requests, 1)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("push stops after the retry budget", func(t *testing.T) {
+		srv := newRateLimitServer(t, 10, "0")
+		c := newTestClient(t, srv.URL)
+
+		err := c.SendPush("group-1", "hello")
+
+		require.Error(t, err)
+		assert.Len(t, srv.requests, 3, "one attempt plus two retries")
+	})
+
+	t.Run("push does not wait past the context deadline", func(t *testing.T) {
+		srv := newRateLimitServer(t, 1, "5")
+		c := newTestClient(t, srv.URL)
+		ctx, cancel := context.WithTimeout(line.WithSourceID(t.Context(), "group-1"), 500*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		err := c.Send(ctx, "hello")
+
+		require.Error(t, err)
+		assert.Len(t, srv.requests, 1)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+	})
+
+	t.Run("push does not wait for an overly long Retry-After", func(t *testing.T) {
+		srv := newRateLimitServer(t, 1, "3600")
+		c := newTestClient(t, srv.URL)
+
+		err := c.SendPush("group-1", "hello")
+
+		require.Error(t, err)
+		assert.Len(t, srv.requests, 1)
+	})
+
+	t.Run("push does not retry a 429 without Retry-After", func(t *testing.T) {
+		srv := newRateLimitServer(t, 1, "")
+		c := newTestClient(t, srv.URL)
+
+		err := c.SendPush("group-1", "hello")
+
+		require.Error(t, err)
+		assert.Len(t, srv.requests, 1)
+	})
+}
+
+// =============================================================================
+// Helpers
+// =============================================================================
+
+type rateLimitRequest struct {
+	path     string
+	retryKey string
+	at       time.Time
+}
+
+type rateLimitServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []rateLimitRequest
+}
+
+// newRateLimitServer starts a fake LINE API that answers the first limited requests
+// with 429 and the given Retry-After header (omitted when empty), and accepts the rest.
+func newRateLimitServer(t *testing.T, limited int, retryAfter string) *rateLimitServer {
+	t.Helper()
+	s := &rateLimitServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, rateLimitRequest{
+			path:     r.URL.Path,
+			retryKey: r.Header.Get("X-Line-Retry-Key"),
+			at:       time.Now(),
+		})
+		n := len(s.requests)
+		s.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if n <= limited {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"message":"The API rate limit has been exceeded. Try again later."}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot/messaging_api"
+)
+
+// sentReplies remembers which contents have been replied with each reply token,
+// so that a buggy tool chain repeating the same reply within a turn sends it only once.
+type sentReplies struct {
+	mu      sync.Mutex
+	entries map[sentReplyKey]time.Time
+}
+
+type sentReplyKey struct {
+	replyToken string
+	digest     [sha256.Size]byte
+}
+
+// reserve claims key unless it is already taken, so that concurrent identical replies send only once.
+func (r *sentReplies) reserve(key sentReplyKey, now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.entries == nil {
+		r.entries = make(map[sentReplyKey]time.Time)
+	}
+	for k, sentAt := range r.entries {
+		if now.Sub(sentAt) > replySourceTTL {
+			delete(r.entries, k)
+		}
+	}
+	if _, ok := r.entries[key]; ok {
+		return false
+	}
+	r.entries[key] = now
+	return true
+}
+
+func (r *sentReplies) release(key sentReplyKey) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.entries, key)
+}
+
+// replyKey identifies a reply by its token and message contents.
+// Returns false if the messages cannot be encoded, in which case the reply is not deduplicated.
+func replyKey(request *messaging_api.ReplyMessageRequest) (sentReplyKey, bool) {
+	body, err := json.Marshal(request.Messages)
+	if err != nil {
+		return sentReplyKey{}, false
+	}
+	return sentReplyKey{replyToken: request.ReplyToken, digest: sha256.Sum256(body)}, true
+}
+
+// reserveReply claims the request's token and messages before the reply is sent.
+// It returns false if the same messages were already sent, or are being sent, with the token.
+// Calling release forgets the claim so that a reply that was not delivered can be sent again.
+func (c *Client) reserveReply(kind string, request *messaging_api.ReplyMessageRequest) (release func(), ok bool) {
+	key, ok := replyKey(request)
+	if !ok {
+		return func() {}, true
+	}
+	if !c.sentReplies.reserve(key, time.Now()) {
+		c.logger.Warn("duplicate reply suppressed",
+			slog.String("kind", kind),
+			slog.Int("messageCount", len(request.Messages)),
+		)
+		return nil, false
+	}
+	return func() { c.sentReplies.release(key) }, true
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot/messaging_api"
+)
+
+// replySourceTTL is how long a reply token's source is remembered.
+// Reply tokens expire after about a minute, so this comfortably covers slow LLM turns.
+const replySourceTTL = 10 * time.Minute
+
+type replySource struct {
+	sourceID   string
+	recordedAt time.Time
+}
+
+// replySources maps reply tokens to the user, group, or room they were issued for.
+type replySources struct {
+	mu      sync.Mutex
+	entries map[string]replySource
+}
+
+func (r *replySources) put(replyToken, sourceID string, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.entries == nil {
+		r.entries = make(map[string]replySource)
+	}
+	for token, e := range r.entries {
+		if now.Sub(e.recordedAt) > replySourceTTL {
+			delete(r.entries, token)
+		}
+	}
+	r.entries[replyToken] = replySource{sourceID: sourceID, recordedAt: now}
+}
+
+func (r *replySources) get(replyToken string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.entries[replyToken]
+	return e.sourceID, ok
+}
+
+// RegisterReplySource records the source a reply token was issued for.
+// When a later reply with that token fails because the token has expired,
+// the same messages are pushed to the source instead.
+// Intended to be passed to server.WithReplyTokenObserver.
+func (c *Client) RegisterReplySource(replyToken, sourceID string) {
+	if replyToken == "" || sourceID == "" {
+		return
+	}
+	c.replySources.put(replyToken, sourceID, time.Now())
+}
+
+// isReplyTokenExpired reports whether a reply API failure was caused by an expired
+// or already used reply token. LINE answers both with 400 "Invalid reply token".
+func isReplyTokenExpired(httpResp *http.Response, err error) bool {
+	if err == nil || httpResp == nil || httpResp.StatusCode != http.StatusBadRequest {
+		return false
+	}
+	return strings.Contains(err.Error(), "Invalid reply token")
+}
+
+// pushReplyFallback pushes messages whose reply failed due to an expired reply token.
+// Returns false without sending when the token's source is unknown.
+func (c *Client) pushReplyFallback(kind, replyToken string, messages []messaging_api.MessageInterface, replyErr error) (bool, error) {
+	to, ok := c.replySources.get(replyToken)
+	if !ok {
+		return false, nil
+	}
+	c.logger.Warn("reply token expired, falling back to push",
+		slog.String("kind", kind),
+		slog.String("to", to),
+		slog.Any("replyError", replyErr),
+	)
+	return true, c.push(context.Background(), &messaging_api.PushMessageRequest{To: to, Messages: messages})
+}
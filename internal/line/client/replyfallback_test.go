@@ -0,0 +1,133 @@
+package client_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"yuruppu/internal/line/client"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// Reply Token Expiry Fallback Tests
+// =============================================================================
+
+func TestClient_ReplyTokenExpiredFallback(t *testing.T) {
+	t.Run("pushes the same text to the source when the reply token has expired", func(t *testing.T) {
+		srv := newReplyFallbackServer(t, `{"message":"Invalid reply token"}`)
+		var logs bytes.Buffer
+		c := newReplyFallbackClient(t, srv.URL, &logs, nil)
+		c.RegisterReplySource("reply-token", "group-1")
+
+		err := c.SendReply("reply-token", "hello")
+
+		require.NoError(t, err)
+		require.Len(t, srv.replies, 1)
+		require.Len(t, srv.pushes, 1)
+		assert.Equal(t, "group-1", srv.pushes[0]["to"])
+		assert.JSONEq(t, rawJSON(t, srv.replies[0]["messages"]), rawJSON(t, srv.pushes[0]["messages"]))
+		assert.Contains(t, logs.String(), "reply token expired, falling back to push")
+	})
+
+	t.Run("pushes the same flex message to the source when the reply token has expired", func(t *testing.T) {
+		srv := newReplyFallbackServer(t, `{"message":"Invalid reply token"}`)
+		c := newReplyFallbackClient(t, srv.URL, io.Discard, nil)
+		c.RegisterReplySource("reply-token", "user-1")
+
+		err := c.SendFlexReply("reply-token", "alt", []byte(`{"type":"bubble","body":{"type":"box","layout":"vertical","contents":[]}}`))
+
+		require.NoError(t, err)
+		require.Len(t, srv.pushes, 1)
+		assert.Equal(t, "user-1", srv.pushes[0]["to"])
+		assert.JSONEq(t, rawJSON(t, srv.replies[0]["messages"]), rawJSON(t, srv.pushes[0]["messages"]))
+	})
+
+	t.Run("returns the reply error when the source is unknown", func(t *testing.T) {
+		srv := newReplyFallbackServer(t, `{"message":"Invalid reply token"}`)
+		sink := &recordingSink{}
+		c := newReplyFallbackClient(t, srv.URL, io.Discard, sink)
+
+		err := c.SendReply("reply-token", "hello")
+
+		require.Error(t, err)
+		assert.Empty(t, srv.pushes)
+		assert.Equal(t, "reply", sink.only(t).Kind)
+	})
+
+	t.Run("does not fall back on other bad requests", func(t *testing.T) {
+		srv := newReplyFallbackServer(t, `{"message":"The request body has 1 error(s)"}`)
+		c := newReplyFallbackClient(t, srv.URL, io.Discard, nil)
+		c.RegisterReplySource("reply-token", "user-1")
+
+		err := c.SendReply("reply-token", "hello")
+
+		require.Error(t, err)
+		assert.Empty(t, srv.pushes)
+	})
+}
+
+// =============================================================================
+// Helpers
+// =============================================================================
+
+type replyFallbackServer struct {
+	*httptest.Server
+	mu      sync.Mutex
+	replies []map[string]any
+	pushes  []map[string]any
+}
+
+// newReplyFallbackServer starts a fake LINE API that rejects every reply with a 400 and replyError,
+// and accepts every push.
+func newReplyFallbackServer(t *testing.T, replyError string) *replyFallbackServer {
+	t.Helper()
+	s := &replyFallbackServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		switch r.URL.Path {
+		case "/v2/bot/message/reply":
+			s.replies = append(s.replies, body)
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(replyError))
+		case "/v2/bot/message/push":
+			s.pushes = append(s.pushes, body)
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func newReplyFallbackClient(t *testing.T, endpoint string, logs io.Writer, sink client.DeadLetterSink) *client.Client {
+	t.Helper()
+	opts := []client.Option{client.WithAPIEndpoint(endpoint)}
+	if sink != nil {
+		opts = append(opts, client.WithDeadLetterSink(sink))
+	}
+	c, err := client.NewClient("test-token", slog.New(slog.NewTextHandler(logs, nil)), opts...)
+	require.NoError(t, err)
+	return c
+}
+
+func rawJSON(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return string(data)
+}
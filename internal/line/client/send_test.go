@@ -0,0 +1,206 @@
+package client_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"yuruppu/internal/line"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// Send Tests
+// =============================================================================
+
+func TestClient_Send(t *testing.T) {
+	t.Run("replies when the context has a reply token", func(t *testing.T) {
+		srv := newSendServer(t)
+		c := newTestClient(t, srv.URL)
+		ctx := line.WithReplyToken(line.WithSourceID(t.Context(), "group-1"), "reply-token")
+
+		err := c.Send(ctx, "hello")
+
+		require.NoError(t, err)
+		require.Len(t, srv.requests, 1)
+		assert.Equal(t, "/v2/bot/message/reply", srv.requests[0].path)
+		assert.Equal(t, "reply-token", srv.requests[0].body["replyToken"])
+		assert.JSONEq(t, `[{"type":"text","text":"hello"}]`, rawJSON(t, srv.requests[0].body["messages"]))
+	})
+
+	t.Run("pushes to the source when the context has no reply token", func(t *testing.T) {
+		srv := newSendServer(t)
+		c := newTestClient(t, srv.URL)
+		ctx := line.WithSourceID(t.Context(), "group-1")
+
+		err := c.Send(ctx, "hello")
+
+		require.NoError(t, err)
+		require.Len(t, srv.requests, 1)
+		assert.Equal(t, "/v2/bot/message/push", srv.requests[0].path)
+		assert.Equal(t, "group-1", srv.requests[0].body["to"])
+		assert.JSONEq(t, `[{"type":"text","text":"hello"}]`, rawJSON(t, srv.requests[0].body["messages"]))
+	})
+
+	t.Run("sends flex messages through the same dispatch", func(t *testing.T) {
+		srv := newSendServer(t)
+		c := newTestClient(t, srv.URL)
+		ctx := line.WithSourceID(t.Context(), "user-1")
+
+		err := c.SendFlex(ctx, "alt", []byte(`{"type":"bubble","body":{"type":"box","layout":"vertical","contents":[]}}`))
+
+		require.NoError(t, err)
+		require.Len(t, srv.requests, 1)
+		assert.Equal(t, "/v2/bot/message/push", srv.requests[0].path)
+		assert.Contains(t, rawJSON(t, srv.requests[0].body["messages"]), `"altText":"alt"`)
+	})
+
+	t.Run("pushes flex messages to a given destination", func(t *testing.T) {
+		srv := newSendServer(t)
+		c := newTestClient(t, srv.URL)
+
+		err := c.SendFlexPush("group-1", "alt", []byte(`{"type":"bubble","body":{"type":"box","layout":"vertical","contents":[]}}`))
+
+		require.NoError(t, err)
+		require.Len(t, srv.requests, 1)
+		assert.Equal(t, "/v2/bot/message/push", srv.requests[0].path)
+		assert.Equal(t, "group-1", srv.requests[0].body["to"])
+		assert.Contains(t, rawJSON(t, srv.requests[0].body["messages"]), `"altText":"alt"`)
+	})
+
+	t.Run("fails without a reply token or source ID", func(t *testing.T) {
+		srv := newSendServer(t)
+		c := newTestClient(t, srv.URL)
+
+		err := c.Send(t.Context(), "hello")
+
+		require.Error(t, err)
+		assert.Empty(t, srv.requests)
+	})
+}
+
+// =============================================================================
+// Reply Deduplication Tests
+// =============================================================================
+
+func TestClient_ReplyDeduplication(t *testing.T) {
+	t.Run("suppresses an identical reply with the same token", func(t *testing.T) {
+		srv := newSendServer(t)
+		c := newTestClient(t, srv.URL)
+		ctx := line.WithReplyToken(line.WithSourceID(t.Context(), "group-1"), "reply-token")
+
+		require.NoError(t, c.Send(ctx, "hello"))
+		require.NoError(t, c.Send(ctx, "hello"))
+		require.NoError(t, c.SendReply("reply-token", "hello"))
+
+		require.Len(t, srv.requests, 1)
+		assert.Equal(t, "/v2/bot/message/reply", srv.requests[0].path)
+	})
+
+	t.Run("sends a distinct reply with the same token", func(t *testing.T) {
+		srv := newSendServer(t)
+		c := newTestClient(t, srv.URL)
+		ctx := line.WithReplyToken(line.WithSourceID(t.Context(), "group-1"), "reply-token")
+
+		require.NoError(t, c.Send(ctx, "hello"))
+		require.NoError(t, c.Send(ctx, "goodbye"))
+
+		require.Len(t, srv.requests, 2)
+		assert.JSONEq(t, `[{"type":"text","text":"goodbye"}]`, rawJSON(t, srv.requests[1].body["messages"]))
+	})
+
+	t.Run("sends the same content with a different token", func(t *testing.T) {
+		srv := newSendServer(t)
+		c := newTestClient(t, srv.URL)
+
+		require.NoError(t, c.SendReply("reply-token-1", "hello"))
+		require.NoError(t, c.SendReply("reply-token-2", "hello"))
+
+		require.Len(t, srv.requests, 2)
+		assert.Equal(t, "reply-token-2", srv.requests[1].body["replyToken"])
+	})
+
+	t.Run("sends concurrent identical replies only once", func(t *testing.T) {
+		srv := newSendServer(t)
+		c := newTestClient(t, srv.URL)
+
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Go(func() {
+				assert.NoError(t, c.SendReply("reply-token", "hello"))
+			})
+		}
+		wg.Wait()
+
+		assert.Len(t, srv.requests, 1)
+	})
+
+	t.Run("sends a reply again after it failed", func(t *testing.T) {
+		srv := newSendServer(t)
+		srv.failures = 1
+		c := newTestClient(t, srv.URL)
+
+		require.Error(t, c.SendReply("reply-token", "hello"))
+		require.NoError(t, c.SendReply("reply-token", "hello"))
+
+		assert.Len(t, srv.requests, 2)
+	})
+
+	t.Run("does not deduplicate pushes", func(t *testing.T) {
+		srv := newSendServer(t)
+		c := newTestClient(t, srv.URL)
+		ctx := line.WithSourceID(t.Context(), "group-1")
+
+		require.NoError(t, c.Send(ctx, "hello"))
+		require.NoError(t, c.Send(ctx, "hello"))
+
+		assert.Len(t, srv.requests, 2)
+	})
+}
+
+// =============================================================================
+// Helpers
+// =============================================================================
+
+type sendRequest struct {
+	path string
+	body map[string]any
+}
+
+type sendServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []sendRequest
+	failures int // Number of upcoming requests answered with 500
+}
+
+// newSendServer starts a fake LINE API that records every request and accepts it unless failures remain.
+func newSendServer(t *testing.T) *sendServer {
+	t.Helper()
+	s := &sendServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.mu.Lock()
+		s.requests = append(s.requests, sendRequest{path: r.URL.Path, body: body})
+		fail := s.failures > 0
+		if fail {
+			s.failures--
+		}
+		s.mu.Unlock()
+		if fail {
+			http.Error(w, `{"message":"internal error"}`, http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
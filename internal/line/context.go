@@ -16,6 +16,9 @@ const (
 	ctxKeySourceID
 	ctxKeyUserID
 	ctxKeyReplyToken
+	ctxKeyPostbackParams
+	ctxKeyReplyTokenReceivedAt
+	ctxKeyBotMentioned
 )
 
 func WithChatType(ctx context.Context, chatType ChatType) context.Context {
@@ -53,3 +56,24 @@ func ReplyTokenFromContext(ctx context.Context) (string, bool) {
 	v, ok := ctx.Value(ctxKeyReplyToken).(string)
 	return v, ok
 }
+
+func WithPostbackParams(ctx context.Context, params PostbackParams) context.Context {
+	return context.WithValue(ctx, ctxKeyPostbackParams, params)
+}
+
+func PostbackParamsFromContext(ctx context.Context) (PostbackParams, bool) {
+	v, ok := ctx.Value(ctxKeyPostbackParams).(PostbackParams)
+	return v, ok
+}
+
+// WithBotMentioned records whether the message being handled @-mentions the bot.
+func WithBotMentioned(ctx context.Context, mentioned bool) context.Context {
+	return context.WithValue(ctx, ctxKeyBotMentioned, mentioned)
+}
+
+// BotMentionedFromContext reports whether the message being handled @-mentions the bot.
+// It is false when the message carries no mention information, such as non-text messages.
+func BotMentionedFromContext(ctx context.Context) bool {
+	v, _ := ctx.Value(ctxKeyBotMentioned).(bool)
+	return v
+}
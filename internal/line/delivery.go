@@ -0,0 +1,58 @@
+package line
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ReplyTokenTTL is how long after an event is received its reply token is treated as usable.
+// LINE rejects reply tokens after about a minute; the margin leaves time for the API call itself.
+const ReplyTokenTTL = 50 * time.Second
+
+// DeliveryMethod is the LINE API used to deliver a message.
+type DeliveryMethod string
+
+const (
+	// DeliveryReply answers an event with its reply token. Replies are free of charge.
+	DeliveryReply DeliveryMethod = "reply"
+	// DeliveryPush sends a message to a user, group, or room without a reply token.
+	DeliveryPush DeliveryMethod = "push"
+)
+
+// Delivery describes how a message should be sent.
+type Delivery struct {
+	Method     DeliveryMethod
+	ReplyToken string // set when Method is DeliveryReply
+	To         string // source ID; the push destination, or the reply fallback when known
+}
+
+// WithReplyTokenReceivedAt records when the event carrying the reply token was received.
+func WithReplyTokenReceivedAt(ctx context.Context, t time.Time) context.Context {
+	return context.WithValue(ctx, ctxKeyReplyTokenReceivedAt, t)
+}
+
+// ReplyTokenReceivedAtFromContext returns when the event carrying the reply token was received.
+func ReplyTokenReceivedAtFromContext(ctx context.Context) (time.Time, bool) {
+	v, ok := ctx.Value(ctxKeyReplyTokenReceivedAt).(time.Time)
+	return v, ok
+}
+
+// ChooseDelivery decides between reply and push for a message sent at now.
+// A reply is chosen when ctx holds a non-empty reply token that is still fresh;
+// a token without a received time is assumed fresh.
+// Otherwise the message is pushed to the source ID in ctx.
+// Returns an error if ctx holds neither a usable reply token nor a source ID.
+func ChooseDelivery(ctx context.Context, now time.Time) (Delivery, error) {
+	sourceID, _ := SourceIDFromContext(ctx)
+	if token, ok := ReplyTokenFromContext(ctx); ok && token != "" {
+		receivedAt, hasReceivedAt := ReplyTokenReceivedAtFromContext(ctx)
+		if !hasReceivedAt || now.Sub(receivedAt) < ReplyTokenTTL {
+			return Delivery{Method: DeliveryReply, ReplyToken: token, To: sourceID}, nil
+		}
+	}
+	if sourceID == "" {
+		return Delivery{}, errors.New("neither a fresh reply token nor a source ID found in context")
+	}
+	return Delivery{Method: DeliveryPush, To: sourceID}, nil
+}
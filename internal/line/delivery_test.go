@@ -0,0 +1,76 @@
+package line_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"yuruppu/internal/line"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChooseDelivery(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	withSource := line.WithSourceID(context.Background(), "group-1")
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		want    line.Delivery
+		wantErr bool
+	}{
+		{
+			name: "replies when a reply token is present",
+			ctx:  line.WithReplyToken(withSource, "reply-token"),
+			want: line.Delivery{Method: line.DeliveryReply, ReplyToken: "reply-token", To: "group-1"},
+		},
+		{
+			name: "replies when the reply token is fresh",
+			ctx:  line.WithReplyTokenReceivedAt(line.WithReplyToken(withSource, "reply-token"), now.Add(-10*time.Second)),
+			want: line.Delivery{Method: line.DeliveryReply, ReplyToken: "reply-token", To: "group-1"},
+		},
+		{
+			name: "replies without a source ID",
+			ctx:  line.WithReplyToken(context.Background(), "reply-token"),
+			want: line.Delivery{Method: line.DeliveryReply, ReplyToken: "reply-token"},
+		},
+		{
+			name: "pushes when there is no reply token",
+			ctx:  withSource,
+			want: line.Delivery{Method: line.DeliveryPush, To: "group-1"},
+		},
+		{
+			name: "pushes when the reply token is empty",
+			ctx:  line.WithReplyToken(withSource, ""),
+			want: line.Delivery{Method: line.DeliveryPush, To: "group-1"},
+		},
+		{
+			name: "pushes when the reply token is stale",
+			ctx:  line.WithReplyTokenReceivedAt(line.WithReplyToken(withSource, "reply-token"), now.Add(-line.ReplyTokenTTL)),
+			want: line.Delivery{Method: line.DeliveryPush, To: "group-1"},
+		},
+		{
+			name:    "fails without a reply token or source ID",
+			ctx:     context.Background(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := line.ChooseDelivery(tt.ctx, now)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
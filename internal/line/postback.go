@@ -0,0 +1,82 @@
+package line
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// jst is the location used to interpret picker values, which carry no offset.
+var jst = time.FixedZone("Asia/Tokyo", 9*60*60)
+
+// PostbackParams holds the typed values selected via a datetime picker action.
+// Each field is nil when the picker did not provide (or provided an invalid) value.
+type PostbackParams struct {
+	DateTime *time.Time // "datetime" mode, e.g. 2017-12-25T01:00
+	Date     *time.Time // "date" mode at 00:00, e.g. 2017-12-25
+	Time     *time.Time // "time" mode on the zero date, e.g. 01:00
+}
+
+// ParsePostbackParams parses the params object of a postback event.
+// Values are interpreted in JST. Invalid values are left nil and reported
+// in the returned error, so callers can keep the valid ones.
+func ParsePostbackParams(params map[string]string) (PostbackParams, error) {
+	var result PostbackParams
+	var errs []error
+
+	parse := func(key, layout string) *time.Time {
+		s, ok := params[key]
+		if !ok {
+			return nil
+		}
+		t, err := time.ParseInLocation(layout, s, jst)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s: %q", key, s))
+			return nil
+		}
+		return &t
+	}
+
+	result.DateTime = parse("datetime", "2006-01-02T15:04")
+	result.Date = parse("date", "2006-01-02")
+	result.Time = parse("time", "15:04")
+
+	return result, errors.Join(errs...)
+}
+
+// postbackNonceKey is the query key carrying a one-time nonce in postback data.
+const postbackNonceKey = "nonce"
+
+// AddPostbackNonce appends a fresh random nonce to query-formatted postback data
+// (e.g. "action=rsvp&event=abc"). Use it for actions that must not be processed twice;
+// idempotent actions can leave their data as-is.
+func AddPostbackNonce(data string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	pair := postbackNonceKey + "=" + hex.EncodeToString(b)
+	if data == "" {
+		return pair, nil
+	}
+	return data + "&" + pair, nil
+}
+
+// SplitPostbackNonce returns the postback data with its nonce removed, and the nonce.
+// The nonce is empty if data carries none.
+func SplitPostbackNonce(data string) (string, string) {
+	pairs := strings.Split(data, "&")
+	rest := make([]string, 0, len(pairs))
+	nonce := ""
+	for _, pair := range pairs {
+		if v, ok := strings.CutPrefix(pair, postbackNonceKey+"="); ok {
+			nonce = v
+			continue
+		}
+		rest = append(rest, pair)
+	}
+	return strings.Join(rest, "&"), nonce
+}
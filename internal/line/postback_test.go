@@ -0,0 +1,60 @@
+package line_test
+
+import (
+	"testing"
+	"yuruppu/internal/line"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddPostbackNonce(t *testing.T) {
+	t.Run("appends nonce to existing data", func(t *testing.T) {
+		data, err := line.AddPostbackNonce("action=rsvp&event=abc")
+		require.NoError(t, err)
+
+		rest, nonce := line.SplitPostbackNonce(data)
+		assert.Equal(t, "action=rsvp&event=abc", rest)
+		assert.Len(t, nonce, 32)
+	})
+
+	t.Run("generates a fresh nonce each time", func(t *testing.T) {
+		data1, err := line.AddPostbackNonce("action=rsvp")
+		require.NoError(t, err)
+		data2, err := line.AddPostbackNonce("action=rsvp")
+		require.NoError(t, err)
+
+		assert.NotEqual(t, data1, data2)
+	})
+
+	t.Run("works with empty data", func(t *testing.T) {
+		data, err := line.AddPostbackNonce("")
+		require.NoError(t, err)
+
+		rest, nonce := line.SplitPostbackNonce(data)
+		assert.Empty(t, rest)
+		assert.NotEmpty(t, nonce)
+	})
+}
+
+func TestSplitPostbackNonce(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		wantRest  string
+		wantNonce string
+	}{
+		{name: "no nonce", data: "action=vote&option=1", wantRest: "action=vote&option=1", wantNonce: ""},
+		{name: "nonce in the middle", data: "action=rsvp&nonce=abc&event=1", wantRest: "action=rsvp&event=1", wantNonce: "abc"},
+		{name: "free text", data: "hello", wantRest: "hello", wantNonce: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rest, nonce := line.SplitPostbackNonce(tt.data)
+
+			assert.Equal(t, tt.wantRest, rest)
+			assert.Equal(t, tt.wantNonce, nonce)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package server_test
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	"yuruppu/internal/line/server"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// concurrencyTestHandler records the peak number of concurrent HandleText calls.
+type concurrencyTestHandler struct {
+	stubHandler
+	active atomic.Int32
+	peak   atomic.Int32
+	wg     *sync.WaitGroup
+}
+
+func (h *concurrencyTestHandler) HandleText(ctx context.Context, messageID, text string) error {
+	defer h.wg.Done()
+	n := h.active.Add(1)
+	defer h.active.Add(-1)
+	for {
+		peak := h.peak.Load()
+		if n <= peak || h.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	time.Sleep(100 * time.Millisecond)
+	return nil
+}
+
+func sendText(t *testing.T, s *server.Server, channelSecret, messageID string) {
+	t.Helper()
+	body := fmt.Sprintf(`{
+		"events": [{
+			"type": "message",
+			"replyToken": "test-reply-token",
+			"source": {"type": "user", "userId": "U1234567890abcdef"},
+			"timestamp": 1625000000000,
+			"message": {"type": "text", "id": %q, "text": "Hello"}
+		}]
+	}`, messageID)
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	req.Header.Set("X-Line-Signature", computeSignature([]byte(body), channelSecret))
+
+	w := httptest.NewRecorder()
+	s.HandleWebhook(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+// =============================================================================
+// Concurrency Limit
+// =============================================================================
+
+func TestNewServer_NegativeMaxConcurrency(t *testing.T) {
+	t.Parallel()
+
+	s, err := server.NewServer("test-secret", 30*time.Second, slog.New(slog.DiscardHandler), server.WithMaxConcurrency(-1))
+
+	require.Error(t, err)
+	assert.Nil(t, s)
+}
+
+func TestHandleWebhook_MaxConcurrency(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		limit    int
+		wantPeak int32
+	}{
+		{name: "limit of 1 serializes handlers", limit: 1, wantPeak: 1},
+		{name: "unlimited runs handlers concurrently", limit: 0, wantPeak: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			channelSecret := "test-secret"
+			s, err := server.NewServer(channelSecret, 30*time.Second, slog.New(slog.DiscardHandler), server.WithMaxConcurrency(tt.limit))
+			require.NoError(t, err)
+
+			var wg sync.WaitGroup
+			wg.Add(2)
+			handler := &concurrencyTestHandler{wg: &wg}
+			s.RegisterHandler(handler)
+
+			sendText(t, s, channelSecret, "msg-1")
+			sendText(t, s, channelSecret, "msg-2")
+
+			done := make(chan struct{})
+			go func() {
+				wg.Wait()
+				close(done)
+			}()
+			select {
+			case <-done:
+			case <-time.After(2 * time.Second):
+				t.Fatal("handlers were not invoked")
+			}
+
+			assert.Equal(t, tt.wantPeak, handler.peak.Load())
+		})
+	}
+}
+
+func TestHandleWebhook_MaxConcurrency_DropsAfterTimeout(t *testing.T) {
+	t.Parallel()
+
+	channelSecret := "test-secret"
+	s, err := server.NewServer(channelSecret, 50*time.Millisecond, slog.New(slog.DiscardHandler), server.WithMaxConcurrency(1))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	handler := &concurrencyTestHandler{wg: &wg}
+	s.RegisterHandler(handler)
+
+	sendText(t, s, channelSecret, "msg-1")
+	sendText(t, s, channelSecret, "msg-2")
+
+	wg.Wait()
+	time.Sleep(200 * time.Millisecond)
+
+	// The second event could not get a slot within the timeout and was dropped
+	assert.Equal(t, int32(1), handler.peak.Load())
+	assert.Equal(t, int32(0), handler.active.Load())
+}
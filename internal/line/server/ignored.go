@@ -0,0 +1,18 @@
+package server
+
+import (
+	"log/slog"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot/webhook"
+)
+
+// logIgnored records an event the bot receives but deliberately does not act on,
+// such as videoPlayComplete and beacon, so that it is not mistaken for an unhandled event.
+func (s *Server) logIgnored(eventType string, source webhook.SourceInterface) {
+	_, sourceID, userID := extractSourceInfo(source)
+	s.logger.Debug("event ignored",
+		slog.String("type", eventType),
+		slog.String("sourceID", sourceID),
+		slog.String("userID", userID),
+	)
+}
@@ -0,0 +1,115 @@
+package server_test
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+	"yuruppu/internal/line/server"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingHandler counts every handler invocation, whatever the event type.
+type countingHandler struct {
+	calls atomic.Int32
+}
+
+func (h *countingHandler) count() error { h.calls.Add(1); return nil }
+
+func (h *countingHandler) HandleText(context.Context, string, string) error { return h.count() }
+func (h *countingHandler) HandleImage(context.Context, string) error        { return h.count() }
+func (h *countingHandler) HandleSticker(context.Context, string, string, string) error {
+	return h.count()
+}
+func (h *countingHandler) HandleVideo(context.Context, string) error { return h.count() }
+func (h *countingHandler) HandleAudio(context.Context, string) error { return h.count() }
+func (h *countingHandler) HandleLocation(context.Context, string, float64, float64) error {
+	return h.count()
+}
+func (h *countingHandler) HandleFile(context.Context, string, string, int64) error { return h.count() }
+func (h *countingHandler) HandleFollow(context.Context) error                      { return h.count() }
+func (h *countingHandler) HandleJoin(context.Context) error                        { return h.count() }
+func (h *countingHandler) HandleLeave(context.Context) error                       { return h.count() }
+func (h *countingHandler) HandleMemberJoined(context.Context, []string) error      { return h.count() }
+func (h *countingHandler) HandleMemberLeft(context.Context, []string) error        { return h.count() }
+func (h *countingHandler) HandlePostback(context.Context, string) error            { return h.count() }
+func (h *countingHandler) HandleUnsend(context.Context, string) error              { return h.count() }
+
+func TestIgnoredEvents(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		event     string
+		eventType string
+	}{
+		{
+			name: "videoPlayComplete",
+			event: `{
+				"type": "videoPlayComplete",
+				"mode": "active",
+				"replyToken": "test-reply-token",
+				"source": {"type": "user", "userId": "test-user-id"},
+				"timestamp": 1625000000000,
+				"webhookEventId": "01FZ74A0TDDPYRVKNK77XKC3ZR",
+				"deliveryContext": {"isRedelivery": false},
+				"videoPlayComplete": {"trackingId": "track-id"}
+			}`,
+			eventType: "videoPlayComplete",
+		},
+		{
+			name: "beacon",
+			event: `{
+				"type": "beacon",
+				"mode": "active",
+				"replyToken": "test-reply-token",
+				"source": {"type": "group", "groupId": "test-group-id", "userId": "test-user-id"},
+				"timestamp": 1625000000000,
+				"webhookEventId": "01FZ74A0TDDPYRVKNK77XKC3ZR",
+				"deliveryContext": {"isRedelivery": false},
+				"beacon": {"hwid": "d41d8cd98f", "type": "enter"}
+			}`,
+			eventType: "beacon",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			channelSecret := "test-secret"
+			logBuf := &lockedBuffer{}
+			s, err := server.NewServer(channelSecret, 30*time.Second, slog.New(slog.NewTextHandler(logBuf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+			require.NoError(t, err)
+			handler := &countingHandler{}
+			s.RegisterHandler(handler)
+
+			body := `{"events": [` + tt.event + `]}`
+			req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+			req.Header.Set("X-Line-Signature", computeSignature([]byte(body), channelSecret))
+			w := httptest.NewRecorder()
+			s.HandleWebhook(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Empty(t, w.Body.String())
+			require.Eventually(t, func() bool {
+				return strings.Contains(logBuf.String(), "event ignored")
+			}, 2*time.Second, 10*time.Millisecond)
+			logs := logBuf.String()
+			assert.Contains(t, logs, `level=DEBUG msg="event ignored" type=`+tt.eventType)
+			assert.Contains(t, logs, "userID=test-user-id")
+			assert.NotContains(t, logs, "level=ERROR")
+			assert.NotContains(t, logs, "level=WARN")
+
+			// Give a wrongly dispatched handler time to run before checking it was not called
+			time.Sleep(50 * time.Millisecond)
+			assert.Zero(t, handler.calls.Load())
+		})
+	}
+}
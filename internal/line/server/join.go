@@ -8,9 +8,10 @@ import (
 	"github.com/line/line-bot-sdk-go/v8/linebot/webhook"
 )
 
-// JoinHandler handles join and member events.
+// JoinHandler handles join, leave, and member events.
 type JoinHandler interface {
 	HandleJoin(ctx context.Context) error
+	HandleLeave(ctx context.Context) error
 	HandleMemberJoined(ctx context.Context, joinedUserIDs []string) error
 	HandleMemberLeft(ctx context.Context, leftUserIDs []string) error
 }
@@ -0,0 +1,37 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"yuruppu/internal/line"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot/webhook"
+)
+
+func (s *Server) invokeLeave(handler JoinHandler, leaveEvent webhook.LeaveEvent) {
+	chatType, sourceID, userID := extractSourceInfo(leaveEvent.Source)
+
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error("leave handler panicked",
+				slog.String("sourceID", sourceID),
+				slog.Any("panic", r),
+			)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.handlerTimeout)
+	defer cancel()
+
+	ctx = line.WithChatType(ctx, chatType)
+	ctx = line.WithSourceID(ctx, sourceID)
+	ctx = line.WithUserID(ctx, userID)
+
+	err := handler.HandleLeave(ctx)
+	if err != nil {
+		s.logger.Error("leave handler failed",
+			slog.String("sourceID", sourceID),
+			slog.Any("error", err),
+		)
+	}
+}
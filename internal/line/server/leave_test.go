@@ -0,0 +1,102 @@
+package server_test
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"yuruppu/internal/line"
+	"yuruppu/internal/line/server"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type leaveHandler struct {
+	stubHandler
+	called   bool
+	sourceID string
+	chatType line.ChatType
+	err      error
+	onCall   func()
+}
+
+func (h *leaveHandler) HandleLeave(ctx context.Context) error {
+	h.called = true
+	h.sourceID, _ = line.SourceIDFromContext(ctx)
+	h.chatType, _ = line.ChatTypeFromContext(ctx)
+	if h.onCall != nil {
+		h.onCall()
+	}
+	return h.err
+}
+
+const leaveBody = `{
+	"events": [{
+		"type": "leave",
+		"source": {"type": "group", "groupId": "C1234567890abcdef"},
+		"timestamp": 1625000000000,
+		"mode": "active",
+		"webhookEventId": "01H0000000000000000000000",
+		"deliveryContext": {"isRedelivery": false}
+	}]
+}`
+
+func TestLeave_ContextValues(t *testing.T) {
+	t.Parallel()
+
+	channelSecret := "test-secret"
+	s, err := server.NewServer(channelSecret, 30*time.Second, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	handler := &leaveHandler{onCall: func() { close(done) }}
+	s.RegisterHandler(handler)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(leaveBody))
+	req.Header.Set("X-Line-Signature", computeSignature([]byte(leaveBody), channelSecret))
+
+	w := httptest.NewRecorder()
+	s.HandleWebhook(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("handler was not invoked")
+	}
+
+	assert.True(t, handler.called)
+	assert.Equal(t, "C1234567890abcdef", handler.sourceID)
+	assert.Equal(t, line.ChatTypeGroup, handler.chatType)
+}
+
+func TestLeave_HandlerError(t *testing.T) {
+	t.Parallel()
+
+	channelSecret := "test-secret"
+	s, err := server.NewServer(channelSecret, 30*time.Second, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	handler := &leaveHandler{err: assert.AnError, onCall: func() { close(done) }}
+	s.RegisterHandler(handler)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(leaveBody))
+	req.Header.Set("X-Line-Signature", computeSignature([]byte(leaveBody), channelSecret))
+
+	w := httptest.NewRecorder()
+	s.HandleWebhook(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("handler was not invoked")
+	}
+}
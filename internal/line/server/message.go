@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"log/slog"
+	"time"
 	"yuruppu/internal/line"
 
 	"github.com/line/line-bot-sdk-go/v8/linebot/webhook"
@@ -23,7 +24,35 @@ type MessageHandler interface {
 	HandleFile(ctx context.Context, messageID, fileName string, fileSize int64) error
 }
 
-func (s *Server) invokeMessage(handler MessageHandler, msgEvent webhook.MessageEvent) {
+// logMessageType logs the type of every received message, including types no handler supports,
+// so that the mix of message types users send, and what is dropped, can be seen.
+func (s *Server) logMessageType(msgEvent webhook.MessageEvent) {
+	_, sourceID, userID := extractSourceInfo(msgEvent.Source)
+	switch msg := msgEvent.Message.(type) {
+	case webhook.TextMessageContent, webhook.ImageMessageContent, webhook.StickerMessageContent,
+		webhook.VideoMessageContent, webhook.AudioMessageContent, webhook.LocationMessageContent,
+		webhook.FileMessageContent:
+		s.logger.Info("message received",
+			slog.String("type", msg.GetType()),
+			slog.String("sourceID", sourceID),
+			slog.String("userID", userID),
+		)
+	case nil:
+		s.logger.Warn("message event without content",
+			slog.String("sourceID", sourceID),
+			slog.String("userID", userID),
+		)
+	default:
+		// Includes webhook.UnknownMessageContent, whose GetType returns the raw type string
+		s.logger.Warn("unhandled message type",
+			slog.String("type", msg.GetType()),
+			slog.String("sourceID", sourceID),
+			slog.String("userID", userID),
+		)
+	}
+}
+
+func (s *Server) invokeMessage(handler MessageHandler, msgEvent webhook.MessageEvent, receivedAt time.Time) {
 	chatType, sourceID, userID := extractSourceInfo(msgEvent.Source)
 
 	defer func() {
@@ -43,10 +72,13 @@ func (s *Server) invokeMessage(handler MessageHandler, msgEvent webhook.MessageE
 	ctx = line.WithSourceID(ctx, sourceID)
 	ctx = line.WithUserID(ctx, userID)
 	ctx = line.WithReplyToken(ctx, msgEvent.ReplyToken)
+	ctx = line.WithReplyTokenReceivedAt(ctx, receivedAt)
+	s.observeReplyToken(msgEvent.ReplyToken, sourceID)
 
 	var err error
 	switch msg := msgEvent.Message.(type) {
 	case webhook.TextMessageContent:
+		ctx = line.WithBotMentioned(ctx, s.mentionsBot(msg.Mention))
 		err = handler.HandleText(ctx, msg.Id, msg.Text)
 	case webhook.ImageMessageContent:
 		err = handler.HandleImage(ctx, msg.Id)
@@ -70,3 +102,22 @@ func (s *Server) invokeMessage(handler MessageHandler, msgEvent webhook.MessageE
 		)
 	}
 }
+
+// mentionsBot reports whether mention includes the bot itself, either flagged with isSelf
+// or by the bot's user ID.
+// An @All mention does not count, since it is not addressed to the bot in particular.
+func (s *Server) mentionsBot(mention *webhook.Mention) bool {
+	if mention == nil {
+		return false
+	}
+	for _, mentionee := range mention.Mentionees {
+		user, ok := mentionee.(webhook.UserMentionee)
+		if !ok {
+			continue
+		}
+		if user.IsSelf || (s.botUserID != "" && user.UserId == s.botUserID) {
+			return true
+		}
+	}
+	return false
+}
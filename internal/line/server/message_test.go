@@ -1,6 +1,7 @@
 package server_test
 
 import (
+	"bytes"
 	"context"
 	"log/slog"
 	"net/http"
@@ -35,6 +36,7 @@ type receivedMessage struct {
 	longitude   float64
 	fileName    string
 	fileSize    int64
+	mentioned   bool
 }
 
 func (h *messageHandler) HandleText(ctx context.Context, messageID, text string) error {
@@ -47,6 +49,7 @@ func (h *messageHandler) HandleText(ctx context.Context, messageID, text string)
 		replyToken:  replyToken,
 		sourceID:    sourceID,
 		text:        text,
+		mentioned:   line.BotMentionedFromContext(ctx),
 	})
 	h.mu.Unlock()
 	if h.onCall != nil {
@@ -210,6 +213,99 @@ func TestMessage_Text(t *testing.T) {
 	assert.Equal(t, "Hello, World!", handler.messages[0].text)
 }
 
+func TestMessage_TextMention(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		botUserID string
+		mention   string
+		mentioned bool
+	}{
+		{
+			name:      "no mention",
+			mention:   "",
+			mentioned: false,
+		},
+		{
+			name:      "mentions the bot",
+			mention:   `, "mention": {"mentionees": [{"index": 0, "length": 9, "type": "user", "userId": "bot-id", "isSelf": true}]}`,
+			mentioned: true,
+		},
+		{
+			name:      "mentions the bot's user ID without isSelf",
+			botUserID: "bot-id",
+			mention:   `, "mention": {"mentionees": [{"index": 0, "length": 9, "type": "user", "userId": "bot-id"}]}`,
+			mentioned: true,
+		},
+		{
+			name:      "mentions another user with the bot's user ID known",
+			botUserID: "bot-id",
+			mention:   `, "mention": {"mentionees": [{"index": 0, "length": 6, "type": "user", "userId": "other-id"}]}`,
+			mentioned: false,
+		},
+		{
+			name:      "mentions another user",
+			mention:   `, "mention": {"mentionees": [{"index": 0, "length": 6, "type": "user", "userId": "other-id", "isSelf": false}]}`,
+			mentioned: false,
+		},
+		{
+			name:      "mentions all",
+			mention:   `, "mention": {"mentionees": [{"index": 0, "length": 4, "type": "all"}]}`,
+			mentioned: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			channelSecret := "test-secret"
+			var opts []server.Option
+			if tt.botUserID != "" {
+				opts = append(opts, server.WithBotUserID(tt.botUserID))
+			}
+			s, err := server.NewServer(channelSecret, 30*time.Second, slog.New(slog.DiscardHandler), opts...)
+			require.NoError(t, err)
+
+			done := make(chan struct{})
+			handler := &messageHandler{onCall: func() { close(done) }}
+			s.RegisterHandler(handler)
+
+			body := `{
+				"events": [{
+					"type": "message",
+					"replyToken": "test-reply-token",
+					"source": {"type": "group", "groupId": "test-group-id", "userId": "test-user-id"},
+					"timestamp": 1625000000000,
+					"message": {"type": "text", "id": "12345", "text": "@Yuruppu hi"` + tt.mention + `}
+				}]
+			}`
+			signature := computeSignature([]byte(body), channelSecret)
+
+			req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+			req.Header.Set("X-Line-Signature", signature)
+
+			w := httptest.NewRecorder()
+			s.HandleWebhook(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			select {
+			case <-done:
+			case <-time.After(2 * time.Second):
+				t.Fatal("handler was not invoked")
+			}
+
+			handler.mu.Lock()
+			defer handler.mu.Unlock()
+
+			require.Len(t, handler.messages, 1)
+			assert.Equal(t, tt.mentioned, handler.messages[0].mentioned)
+		})
+	}
+}
+
 func TestMessage_Image(t *testing.T) {
 	t.Parallel()
 
@@ -808,3 +904,135 @@ func TestMessage_MultipleHandlers(t *testing.T) {
 		t.Fatal("not all handlers were invoked")
 	}
 }
+
+func TestMessage_ReplyTokenObserver(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	observed := map[string]string{}
+	channelSecret := "test-secret"
+	s, err := server.NewServer(channelSecret, 30*time.Second, slog.New(slog.DiscardHandler),
+		server.WithReplyTokenObserver(func(replyToken, sourceID string) {
+			mu.Lock()
+			defer mu.Unlock()
+			observed[replyToken] = sourceID
+		}),
+	)
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	handler := &messageHandler{onCall: func() { close(done) }}
+	s.RegisterHandler(handler)
+
+	body := `{
+		"events": [{
+			"type": "message",
+			"replyToken": "test-reply-token",
+			"source": {"type": "group", "groupId": "test-group-id", "userId": "test-user-id"},
+			"timestamp": 1625000000000,
+			"message": {"type": "text", "id": "12345", "text": "Hello"}
+		}]
+	}`
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	req.Header.Set("X-Line-Signature", computeSignature([]byte(body), channelSecret))
+
+	w := httptest.NewRecorder()
+	s.HandleWebhook(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("handler was not invoked")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, map[string]string{"test-reply-token": "test-group-id"}, observed)
+}
+
+func TestMessage_TypeLogging(t *testing.T) {
+	t.Parallel()
+
+	send := func(t *testing.T, message string) (*lockedBuffer, *messageHandler) {
+		t.Helper()
+		channelSecret := "test-secret"
+		logBuf := &lockedBuffer{}
+		s, err := server.NewServer(channelSecret, 30*time.Second, slog.New(slog.NewTextHandler(logBuf, nil)))
+		require.NoError(t, err)
+		handler := &messageHandler{}
+		s.RegisterHandler(handler)
+
+		body := `{
+			"events": [{
+				"type": "message",
+				"replyToken": "test-reply-token",
+				"source": {"type": "user", "userId": "test-user-id"},
+				"timestamp": 1625000000000,
+				"message": ` + message + `
+			}]
+		}`
+		req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+		req.Header.Set("X-Line-Signature", computeSignature([]byte(body), channelSecret))
+		w := httptest.NewRecorder()
+		s.HandleWebhook(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		return logBuf, handler
+	}
+	waitForLog := func(t *testing.T, logBuf *lockedBuffer, msg string) string {
+		t.Helper()
+		require.Eventually(t, func() bool {
+			return strings.Contains(logBuf.String(), msg)
+		}, 2*time.Second, 10*time.Millisecond)
+		return logBuf.String()
+	}
+
+	t.Run("logs handled message types with the user ID", func(t *testing.T) {
+		t.Parallel()
+
+		logBuf, _ := send(t, `{"type": "sticker", "id": "12345", "packageId": "1", "stickerId": "2", "stickerResourceType": "STATIC", "quoteToken": "q"}`)
+		logs := waitForLog(t, logBuf, "message received")
+
+		assert.Contains(t, logs, "level=INFO")
+		assert.Contains(t, logs, `msg="message received" type=sticker`)
+		assert.Contains(t, logs, "userID=test-user-id")
+	})
+
+	t.Run("warns about unhandled message types with the raw type", func(t *testing.T) {
+		t.Parallel()
+
+		logBuf, handler := send(t, `{"type": "hologram", "id": "12345"}`)
+		logs := waitForLog(t, logBuf, "unhandled message type")
+
+		assert.Contains(t, logs, "level=WARN")
+		assert.Contains(t, logs, `msg="unhandled message type" type=hologram`)
+		assert.Contains(t, logs, "userID=test-user-id")
+		assert.NotContains(t, logs, "message received")
+
+		// Give a wrongly dispatched handler time to run before checking it was not called
+		time.Sleep(50 * time.Millisecond)
+		handler.mu.Lock()
+		defer handler.mu.Unlock()
+		assert.Empty(t, handler.messages)
+	})
+}
+
+// lockedBuffer is a bytes.Buffer safe for the concurrent writes of event goroutines.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
@@ -0,0 +1,65 @@
+package server
+
+// Option configures optional Server behavior.
+type Option func(*Server)
+
+// WithSignatureHeader sets the header read for the webhook signature.
+// Defaults to X-Line-Signature.
+func WithSignatureHeader(name string) Option {
+	return func(s *Server) {
+		s.signatureHeader = name
+	}
+}
+
+// WithMaxConcurrency bounds the number of handler invocations running at once.
+// Excess events wait for a free slot up to the handler timeout and are dropped after that.
+// Defaults to 0 (unlimited).
+func WithMaxConcurrency(n int) Option {
+	return func(s *Server) {
+		s.maxConcurrency = n
+	}
+}
+
+// WithSignatureVerifier sets the signature verification scheme.
+// Defaults to HMACSHA256Verifier.
+func WithSignatureVerifier(v SignatureVerifier) Option {
+	return func(s *Server) {
+		s.verifier = v
+	}
+}
+
+// WithoutSignatureCheck makes the server accept webhooks without verifying their signature,
+// so developers can POST unsigned payloads locally. It is for local development only:
+// the server logs a warning at creation and on every request while it is active.
+func WithoutSignatureCheck() Option {
+	return func(s *Server) {
+		s.skipSignature = true
+	}
+}
+
+// WithReadinessGate makes the server answer webhooks with 503 until MarkReady is called,
+// so LINE retries events that arrive while dependencies are still initializing.
+// Without it the server is ready as soon as it is created.
+func WithReadinessGate() Option {
+	return func(s *Server) {
+		s.gated = true
+	}
+}
+
+// WithBotUserID sets the bot's own user ID, so a mention of that user counts as a mention
+// of the bot even when the webhook does not flag it with isSelf.
+// Without it only the isSelf flag is used.
+func WithBotUserID(userID string) Option {
+	return func(s *Server) {
+		s.botUserID = userID
+	}
+}
+
+// WithReplyTokenObserver registers fn to be called with the reply token and source ID
+// of every message and postback event before handlers run.
+// It lets the LINE client push to the source when a reply token has expired.
+func WithReplyTokenObserver(fn func(replyToken, sourceID string)) Option {
+	return func(s *Server) {
+		s.replyTokenObserver = fn
+	}
+}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"time"
+	"yuruppu/internal/line"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot/webhook"
+)
+
+// PostbackHandler handles postback events (e.g., from rich menus or datetime pickers).
+// Picker values are accessible via line.PostbackParamsFromContext.
+type PostbackHandler interface {
+	HandlePostback(ctx context.Context, data string) error
+}
+
+func (s *Server) invokePostback(handler PostbackHandler, postbackEvent webhook.PostbackEvent, receivedAt time.Time) {
+	chatType, sourceID, userID := extractSourceInfo(postbackEvent.Source)
+
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error("postback handler panicked",
+				slog.String("sourceID", sourceID),
+				slog.String("userID", userID),
+				slog.Any("panic", r),
+			)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.handlerTimeout)
+	defer cancel()
+
+	ctx = line.WithChatType(ctx, chatType)
+	ctx = line.WithSourceID(ctx, sourceID)
+	ctx = line.WithUserID(ctx, userID)
+	ctx = line.WithReplyToken(ctx, postbackEvent.ReplyToken)
+	ctx = line.WithReplyTokenReceivedAt(ctx, receivedAt)
+	s.observeReplyToken(postbackEvent.ReplyToken, sourceID)
+
+	var data string
+	if postbackEvent.Postback != nil {
+		data = postbackEvent.Postback.Data
+		if len(postbackEvent.Postback.Params) > 0 {
+			params, err := line.ParsePostbackParams(postbackEvent.Postback.Params)
+			if err != nil {
+				s.logger.Warn("invalid postback params, ignoring invalid values",
+					slog.String("sourceID", sourceID),
+					slog.String("userID", userID),
+					slog.Any("error", err),
+				)
+			}
+			ctx = line.WithPostbackParams(ctx, params)
+		}
+	}
+
+	err := handler.HandlePostback(ctx, data)
+	if err != nil {
+		s.logger.Error("postback handler failed",
+			slog.String("sourceID", sourceID),
+			slog.String("userID", userID),
+			slog.Any("error", err),
+		)
+	}
+}
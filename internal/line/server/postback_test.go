@@ -0,0 +1,128 @@
+package server_test
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"yuruppu/internal/line"
+	"yuruppu/internal/line/server"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type postbackHandler struct {
+	stubHandler
+	mu        sync.Mutex
+	postbacks []postbackEvent
+	onCall    func()
+}
+
+type postbackEvent struct {
+	data       string
+	replyToken string
+	params     line.PostbackParams
+	hasParams  bool
+}
+
+func (h *postbackHandler) HandlePostback(ctx context.Context, data string) error {
+	replyToken, _ := line.ReplyTokenFromContext(ctx)
+	params, hasParams := line.PostbackParamsFromContext(ctx)
+
+	h.mu.Lock()
+	h.postbacks = append(h.postbacks, postbackEvent{
+		data:       data,
+		replyToken: replyToken,
+		params:     params,
+		hasParams:  hasParams,
+	})
+	h.mu.Unlock()
+
+	if h.onCall != nil {
+		h.onCall()
+	}
+	return nil
+}
+
+func sendPostback(t *testing.T, postback string) postbackEvent {
+	t.Helper()
+
+	channelSecret := "test-secret"
+	s, err := server.NewServer(channelSecret, 30*time.Second, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	handler := &postbackHandler{onCall: func() { close(done) }}
+	s.RegisterHandler(handler)
+
+	body := `{
+		"events": [{
+			"type": "postback",
+			"replyToken": "test-reply-token",
+			"source": {"type": "group", "groupId": "C1234567890abcdef", "userId": "U9876543210fedcba"},
+			"timestamp": 1625000000000,
+			"postback": ` + postback + `
+		}]
+	}`
+	signature := computeSignature([]byte(body), channelSecret)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	req.Header.Set("X-Line-Signature", signature)
+
+	w := httptest.NewRecorder()
+	s.HandleWebhook(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("handler was not invoked")
+	}
+
+	handler.mu.Lock()
+	defer handler.mu.Unlock()
+	require.Len(t, handler.postbacks, 1)
+	return handler.postbacks[0]
+}
+
+func TestPostback_DatetimePicker(t *testing.T) {
+	t.Parallel()
+
+	got := sendPostback(t, `{"data": "action=create_event", "params": {"datetime": "2025-12-25T19:30"}}`)
+
+	assert.Equal(t, "action=create_event", got.data)
+	assert.Equal(t, "test-reply-token", got.replyToken)
+	require.True(t, got.hasParams)
+	require.NotNil(t, got.params.DateTime)
+	want := time.Date(2025, 12, 25, 19, 30, 0, 0, time.FixedZone("JST", 9*60*60))
+	assert.True(t, want.Equal(*got.params.DateTime), "got %s", got.params.DateTime)
+	assert.Nil(t, got.params.Date)
+	assert.Nil(t, got.params.Time)
+}
+
+func TestPostback_WithoutParams(t *testing.T) {
+	t.Parallel()
+
+	got := sendPostback(t, `{"data": "action=menu"}`)
+
+	assert.Equal(t, "action=menu", got.data)
+	assert.False(t, got.hasParams)
+}
+
+func TestPostback_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	got := sendPostback(t, `{"data": "action=create_event", "params": {"datetime": "not-a-date", "date": "2025-12-25"}}`)
+
+	assert.Equal(t, "action=create_event", got.data)
+	require.True(t, got.hasParams)
+	assert.Nil(t, got.params.DateTime)
+	require.NotNil(t, got.params.Date)
+	assert.Equal(t, "2025-12-25", got.params.Date.Format(time.DateOnly))
+}
@@ -0,0 +1,34 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// MarkReady lets the server accept webhooks.
+// Only needed when the server was created with WithReadinessGate.
+// Register handlers before calling MarkReady.
+func (s *Server) MarkReady() {
+	s.ready.Store(true)
+}
+
+// Ready reports whether the server accepts webhooks.
+func (s *Server) Ready() bool {
+	return s.ready.Load()
+}
+
+// healthResponse is the JSON body written by HandleHealth.
+type healthResponse struct {
+	Status string `json:"status"`
+}
+
+// HandleHealth reports readiness: 200 once ready, 503 while starting up.
+func (s *Server) HandleHealth(w http.ResponseWriter, r *http.Request) {
+	status, code := "ok", http.StatusOK
+	if !s.Ready() {
+		status, code = "starting", http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(healthResponse{Status: status})
+}
@@ -0,0 +1,72 @@
+package server_test
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"yuruppu/internal/line/server"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// Readiness
+// =============================================================================
+
+func TestReadiness_DefaultReady(t *testing.T) {
+	t.Parallel()
+
+	s, err := server.NewServer("test-secret", 30*time.Second, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+
+	assert.True(t, s.Ready())
+}
+
+func TestReadiness_WebhookTransitions(t *testing.T) {
+	t.Parallel()
+
+	channelSecret := "test-secret"
+	s, err := server.NewServer(channelSecret, 30*time.Second, slog.New(slog.DiscardHandler), server.WithReadinessGate())
+	require.NoError(t, err)
+
+	body := `{"events":[]}`
+	send := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+		req.Header.Set("X-Line-Signature", computeSignature([]byte(body), channelSecret))
+		w := httptest.NewRecorder()
+		s.HandleWebhook(w, req)
+		return w
+	}
+
+	w := send()
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.JSONEq(t, `{"error":"service not ready","code":503}`, w.Body.String())
+
+	s.MarkReady()
+
+	w = send()
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestReadiness_HealthTransitions(t *testing.T) {
+	t.Parallel()
+
+	s, err := server.NewServer("test-secret", 30*time.Second, slog.New(slog.DiscardHandler), server.WithReadinessGate())
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	s.HandleHealth(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.JSONEq(t, `{"status":"starting"}`, w.Body.String())
+
+	s.MarkReady()
+
+	w = httptest.NewRecorder()
+	s.HandleHealth(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, w.Body.String())
+}
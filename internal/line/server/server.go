@@ -1,38 +1,56 @@
 package server
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"strings"
+	"sync/atomic"
 	"time"
 	"yuruppu/internal/line"
 
 	"github.com/line/line-bot-sdk-go/v8/linebot/webhook"
 )
 
+// maxRequestBodySize is the maximum accepted size of a webhook request body.
+const maxRequestBodySize = 1 << 20 // 1MB
+
 // Handler combines all event handler interfaces.
 type Handler interface {
 	FollowHandler
 	JoinHandler
 	MessageHandler
+	PostbackHandler
 	UnsendHandler
 }
 
 // Server handles incoming LINE webhook requests and dispatches to handlers.
 type Server struct {
-	channelSecret  string
-	handlers       []Handler
-	handlerTimeout time.Duration
-	logger         *slog.Logger
+	channelSecret      string
+	signatureHeader    string
+	verifier           SignatureVerifier
+	skipSignature      bool // accept unsigned webhooks (local development only)
+	maxConcurrency     int
+	sem                chan struct{} // nil = unlimited
+	gated              bool
+	ready              atomic.Bool
+	handlers           []Handler
+	handlerTimeout     time.Duration
+	replyTokenObserver func(replyToken, sourceID string) // nil = none
+	botUserID          string                            // empty = rely on the isSelf flag only
+	logger             *slog.Logger
 }
 
 // NewServer creates a new LINE webhook server.
 // channelSecret is the LINE channel secret for signature verification.
 // timeout is the timeout for handler execution (must be positive).
 // logger is the structured logger for the server.
+// opts override the signature header and verification scheme.
 // Returns an error if channelSecret is empty or timeout is not positive.
-func NewServer(channelSecret string, timeout time.Duration, logger *slog.Logger) (*Server, error) {
+func NewServer(channelSecret string, timeout time.Duration, logger *slog.Logger, opts ...Option) (*Server, error) {
 	channelSecret = strings.TrimSpace(channelSecret)
 	if channelSecret == "" {
 		return nil, errors.New("missing required configuration: channelSecret")
@@ -44,11 +62,33 @@ func NewServer(channelSecret string, timeout time.Duration, logger *slog.Logger)
 		return nil, errors.New("missing required configuration: logger")
 	}
 
-	return &Server{
-		channelSecret:  channelSecret,
-		handlerTimeout: timeout,
-		logger:         logger,
-	}, nil
+	s := &Server{
+		channelSecret:   channelSecret,
+		signatureHeader: defaultSignatureHeader,
+		verifier:        HMACSHA256Verifier{},
+		handlerTimeout:  timeout,
+		logger:          logger,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if strings.TrimSpace(s.signatureHeader) == "" {
+		return nil, errors.New("missing required configuration: signatureHeader")
+	}
+	if s.verifier == nil {
+		return nil, errors.New("missing required configuration: verifier")
+	}
+	if s.maxConcurrency < 0 {
+		return nil, errors.New("invalid configuration: maxConcurrency must not be negative")
+	}
+	if s.maxConcurrency > 0 {
+		s.sem = make(chan struct{}, s.maxConcurrency)
+	}
+	if s.skipSignature {
+		s.logger.Warn("WEBHOOK SIGNATURE CHECK IS DISABLED: unsigned requests will be accepted; never use this outside local development")
+	}
+	s.ready.Store(!s.gated)
+	return s, nil
 }
 
 // RegisterHandler registers a message handler.
@@ -61,16 +101,33 @@ func (s *Server) RegisterHandler(handler Handler) {
 // HandleWebhook processes incoming LINE webhook requests.
 // Signature is verified synchronously.
 // Events are parsed synchronously.
-// HTTP 200 is returned synchronously.
+// HTTP 200 is returned synchronously with an empty body.
+// Error responses carry a JSON body (see writeError).
 // Handler methods are invoked asynchronously in goroutines.
+// Returns 503 while the server is not ready (see WithReadinessGate).
 func (s *Server) HandleWebhook(w http.ResponseWriter, r *http.Request) {
-	// Parse webhook request using LINE SDK (includes signature verification)
-	cb, err := webhook.ParseRequest(s.channelSecret, r)
+	if !s.Ready() {
+		s.logger.Warn("webhook rejected: server not ready")
+		writeError(w, http.StatusServiceUnavailable, "service not ready")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodySize)
+
+	cb, err := s.parseRequest(r)
 	if err != nil {
 		s.logger.Error("webhook parsing failed",
 			slog.Any("error", err),
 		)
-		http.Error(w, "Bad Request", http.StatusBadRequest)
+		var maxBytesErr *http.MaxBytesError
+		switch {
+		case errors.Is(err, webhook.ErrInvalidSignature):
+			writeError(w, http.StatusUnauthorized, "invalid signature")
+		case errors.As(err, &maxBytesErr):
+			writeError(w, http.StatusRequestEntityTooLarge, "request body too large")
+		default:
+			writeError(w, http.StatusBadRequest, "malformed request body")
+		}
 		return
 	}
 
@@ -87,28 +144,126 @@ func (s *Server) HandleWebhook(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// parseRequest reads the body, verifies its signature, and decodes the webhook events.
+func (s *Server) parseRequest(r *http.Request) (*webhook.CallbackRequest, error) {
+	defer func() { _ = r.Body.Close() }()
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	if s.skipSignature {
+		s.logger.Warn("webhook signature check skipped because it is disabled")
+	} else if !s.verifier.Verify(s.channelSecret, r.Header.Get(s.signatureHeader), body) {
+		return nil, webhook.ErrInvalidSignature
+	}
+
+	var cb webhook.CallbackRequest
+	if err := json.Unmarshal(body, &cb); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal request body: %w", err)
+	}
+	return &cb, nil
+}
+
+// errorResponse is the JSON body written for webhook error responses.
+type errorResponse struct {
+	Error string `json:"error"`
+	Code  int    `json:"code"`
+}
+
+// writeError writes a JSON error body with the given status code.
+// message must be a fixed, client-safe string; internal error details are logged instead.
+func writeError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(errorResponse{Error: message, Code: code})
+}
+
 func (s *Server) processEvent(event webhook.EventInterface) {
+	receivedAt := time.Now()
 	var invoker func(Handler)
 	switch e := event.(type) {
 	case webhook.FollowEvent:
 		invoker = func(h Handler) { s.invokeFollow(h, e) }
 	case webhook.JoinEvent:
 		invoker = func(h Handler) { s.invokeJoin(h, e) }
+	case webhook.LeaveEvent:
+		invoker = func(h Handler) { s.invokeLeave(h, e) }
 	case webhook.MemberJoinedEvent:
 		invoker = func(h Handler) { s.invokeMemberJoined(h, e) }
 	case webhook.MemberLeftEvent:
 		invoker = func(h Handler) { s.invokeMemberLeft(h, e) }
 	case webhook.MessageEvent:
-		invoker = func(h Handler) { s.invokeMessage(h, e) }
+		s.logMessageType(e)
+		invoker = func(h Handler) { s.invokeMessage(h, e, receivedAt) }
+	case webhook.PostbackEvent:
+		invoker = func(h Handler) { s.invokePostback(h, e, receivedAt) }
 	case webhook.UnsendEvent:
 		invoker = func(h Handler) { s.invokeUnsend(h, e) }
+	case webhook.VideoPlayCompleteEvent:
+		s.logIgnored(e.GetType(), e.Source)
+		return
+	case webhook.BeaconEvent:
+		s.logIgnored(e.GetType(), e.Source)
+		return
 	default:
 		return
 	}
 
 	for _, handler := range s.handlers {
-		go invoker(handler)
+		go func() {
+			if !s.acquire() {
+				return
+			}
+			defer s.release()
+			invoker(handler)
+		}()
+	}
+}
+
+// acquire waits for a free handler slot when a concurrency limit is set.
+// Events that cannot get a slot within the handler timeout are dropped;
+// LINE has already received HTTP 200, so it will not retry them.
+func (s *Server) acquire() bool {
+	if s.sem == nil {
+		return true
+	}
+	select {
+	case s.sem <- struct{}{}:
+		return true
+	default:
+	}
+
+	s.logger.Debug("handler queued: concurrency limit reached",
+		slog.Int("limit", cap(s.sem)),
+		slog.Int("inFlight", len(s.sem)),
+	)
+	timer := time.NewTimer(s.handlerTimeout)
+	defer timer.Stop()
+	select {
+	case s.sem <- struct{}{}:
+		return true
+	case <-timer.C:
+		s.logger.Warn("handler dropped: concurrency limit reached",
+			slog.Int("limit", cap(s.sem)),
+			slog.Int("inFlight", len(s.sem)),
+		)
+		return false
+	}
+}
+
+// release frees a handler slot acquired by acquire.
+func (s *Server) release() {
+	if s.sem != nil {
+		<-s.sem
+	}
+}
+
+// observeReplyToken notifies the reply token observer, if any, of an event's reply token.
+func (s *Server) observeReplyToken(replyToken, sourceID string) {
+	if s.replyTokenObserver == nil || replyToken == "" || sourceID == "" {
+		return
 	}
+	s.replyTokenObserver(replyToken, sourceID)
 }
 
 // extractSourceInfo returns (chatType, sourceID, userID).
@@ -35,8 +35,10 @@ func (stubHandler) HandleLocation(context.Context, string, float64, float64) err
 func (stubHandler) HandleFile(context.Context, string, string, int64) error        { return nil }
 func (stubHandler) HandleFollow(context.Context) error                             { return nil }
 func (stubHandler) HandleJoin(context.Context) error                               { return nil }
+func (stubHandler) HandleLeave(context.Context) error                              { return nil }
 func (stubHandler) HandleMemberJoined(context.Context, []string) error             { return nil }
 func (stubHandler) HandleMemberLeft(context.Context, []string) error               { return nil }
+func (stubHandler) HandlePostback(context.Context, string) error                   { return nil }
 func (stubHandler) HandleUnsend(context.Context, string) error                     { return nil }
 
 // =============================================================================
@@ -118,7 +120,9 @@ func TestHandleWebhook_InvalidSignature(t *testing.T) {
 	w := httptest.NewRecorder()
 	s.HandleWebhook(w, req)
 
-	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"error":"invalid signature","code":401}`, w.Body.String())
 }
 
 func TestHandleWebhook_MissingSignature(t *testing.T) {
@@ -133,7 +137,46 @@ func TestHandleWebhook_MissingSignature(t *testing.T) {
 	w := httptest.NewRecorder()
 	s.HandleWebhook(w, req)
 
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestHandleWebhook_MalformedJSON(t *testing.T) {
+	t.Parallel()
+
+	channelSecret := "test-secret"
+	s, err := server.NewServer(channelSecret, 30*time.Second, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+
+	body := `{"events":[`
+	signature := computeSignature([]byte(body), channelSecret)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	req.Header.Set("X-Line-Signature", signature)
+
+	w := httptest.NewRecorder()
+	s.HandleWebhook(w, req)
+
 	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"error":"malformed request body","code":400}`, w.Body.String())
+	assert.NotContains(t, w.Body.String(), "unmarshal")
+}
+
+func TestHandleWebhook_BodyTooLarge(t *testing.T) {
+	t.Parallel()
+
+	s, err := server.NewServer("test-secret", 30*time.Second, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+
+	body := strings.Repeat("a", 2<<20)
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	req.Header.Set("X-Line-Signature", "irrelevant")
+
+	w := httptest.NewRecorder()
+	s.HandleWebhook(w, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.JSONEq(t, `{"error":"request body too large","code":413}`, w.Body.String())
 }
 
 func TestHandleWebhook_EmptyEvents(t *testing.T) {
@@ -153,6 +196,7 @@ func TestHandleWebhook_EmptyEvents(t *testing.T) {
 	s.HandleWebhook(w, req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Body.String())
 }
 
 // =============================================================================
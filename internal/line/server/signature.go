@@ -0,0 +1,19 @@
+package server
+
+import "github.com/line/line-bot-sdk-go/v8/linebot/webhook"
+
+// defaultSignatureHeader is the header LINE uses to carry the webhook signature.
+const defaultSignatureHeader = "X-Line-Signature"
+
+// SignatureVerifier verifies a webhook request body against its signature.
+type SignatureVerifier interface {
+	Verify(channelSecret, signature string, body []byte) bool
+}
+
+// HMACSHA256Verifier verifies base64-encoded HMAC-SHA256 signatures as used by LINE.
+type HMACSHA256Verifier struct{}
+
+// Verify reports whether signature matches the HMAC-SHA256 of body keyed by channelSecret.
+func (HMACSHA256Verifier) Verify(channelSecret, signature string, body []byte) bool {
+	return webhook.ValidateSignature(channelSecret, signature, body)
+}
@@ -0,0 +1,194 @@
+package server_test
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"yuruppu/internal/line/server"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticVerifier struct {
+	gotSignature string
+}
+
+func (v *staticVerifier) Verify(_, signature string, _ []byte) bool {
+	v.gotSignature = signature
+	return signature == "ok"
+}
+
+// =============================================================================
+// Signature Options
+// =============================================================================
+
+func TestNewServer_SignatureOptions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opts []server.Option
+	}{
+		{
+			name: "empty signature header",
+			opts: []server.Option{server.WithSignatureHeader("  ")},
+		},
+		{
+			name: "nil verifier",
+			opts: []server.Option{server.WithSignatureVerifier(nil)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := server.NewServer("test-secret", 30*time.Second, slog.New(slog.DiscardHandler), tt.opts...)
+
+			require.Error(t, err)
+			assert.Nil(t, s)
+		})
+	}
+}
+
+func TestHandleWebhook_SignatureHeader(t *testing.T) {
+	t.Parallel()
+
+	channelSecret := "test-secret"
+	body := `{"events":[]}`
+	signature := computeSignature([]byte(body), channelSecret)
+
+	tests := []struct {
+		name     string
+		opts     []server.Option
+		header   string
+		wantCode int
+	}{
+		{
+			name:     "default header",
+			header:   "X-Line-Signature",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "custom header",
+			opts:     []server.Option{server.WithSignatureHeader("X-Forwarded-Line-Signature")},
+			header:   "X-Forwarded-Line-Signature",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "default header ignored when custom header is set",
+			opts:     []server.Option{server.WithSignatureHeader("X-Forwarded-Line-Signature")},
+			header:   "X-Line-Signature",
+			wantCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := server.NewServer(channelSecret, 30*time.Second, slog.New(slog.DiscardHandler), tt.opts...)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+			req.Header.Set(tt.header, signature)
+
+			w := httptest.NewRecorder()
+			s.HandleWebhook(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
+
+func TestHandleWebhook_SignatureVerifier(t *testing.T) {
+	t.Parallel()
+
+	verifier := &staticVerifier{}
+	s, err := server.NewServer("test-secret", 30*time.Second, slog.New(slog.DiscardHandler), server.WithSignatureVerifier(verifier))
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(`{"events":[]}`))
+	req.Header.Set("X-Line-Signature", "ok")
+
+	w := httptest.NewRecorder()
+	s.HandleWebhook(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "ok", verifier.gotSignature)
+}
+
+func TestHandleWebhook_WithoutSignatureCheck(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		opts      []server.Option
+		signature string
+		wantCode  int
+	}{
+		{
+			name:     "unsigned body rejected by default",
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:      "bad signature rejected by default",
+			signature: "invalid-signature",
+			wantCode:  http.StatusUnauthorized,
+		},
+		{
+			name:     "unsigned body accepted when the check is disabled",
+			opts:     []server.Option{server.WithoutSignatureCheck()},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:      "bad signature accepted when the check is disabled",
+			opts:      []server.Option{server.WithoutSignatureCheck()},
+			signature: "invalid-signature",
+			wantCode:  http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := server.NewServer("test-secret", 30*time.Second, slog.New(slog.DiscardHandler), tt.opts...)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(`{"events":[]}`))
+			if tt.signature != "" {
+				req.Header.Set("X-Line-Signature", tt.signature)
+			}
+
+			w := httptest.NewRecorder()
+			s.HandleWebhook(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
+
+func TestHandleWebhook_WithoutSignatureCheck_Logs(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	s, err := server.NewServer("test-secret", 30*time.Second, logger, server.WithoutSignatureCheck())
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "WEBHOOK SIGNATURE CHECK IS DISABLED", "startup should warn")
+
+	for range 2 {
+		req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(`{"events":[]}`))
+		w := httptest.NewRecorder()
+		s.HandleWebhook(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+
+	assert.Equal(t, 2, strings.Count(buf.String(), "webhook signature check skipped"), "every request should warn")
+}
@@ -0,0 +1,104 @@
+// Package linetext adapts LLM output for LINE, which shows message text as is.
+package linetext
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	fencePattern   = regexp.MustCompile("^\\s*```")
+	headingPattern = regexp.MustCompile(`^#{1,6}\s+(.*?)(?:\s+#+)?\s*$`)
+	bulletPattern  = regexp.MustCompile(`^(\s*)[-*+]\s+`)
+	// protectedPattern matches inline code spans and URLs, which are never rewritten.
+	protectedPattern = regexp.MustCompile("`[^`\n]+`|https?://\\S+")
+	boldPattern      = regexp.MustCompile(`\*\*(\S(?:.*?\S)?)\*\*`)
+	// Underscores and single asterisks only count at word edges, so snake_case names and 2*3*4 are kept.
+	underscoreBoldPattern = regexp.MustCompile(`(^|[^\w_])__(\S(?:.*?\S)?)__([^\w_]|$)`)
+	italicPattern         = regexp.MustCompile(`(^|[^\w*])\*(\S(?:[^*]*?\S)?)\*([^\w*]|$)`)
+	// identifierPattern matches names like __init__, which are kept even though they look like bold.
+	identifierPattern = regexp.MustCompile(`^__\w+__$`)
+)
+
+// FromMarkdown converts common Markdown in s to plain text suited to LINE.
+// Bold and italic markers are removed, heading markers are dropped,
+// "- ", "* ", and "+ " bullets become "・", and runs of blank lines collapse into one.
+// Fenced code blocks are kept verbatim without their fence lines,
+// and inline code keeps its content with the backticks removed.
+// Leading and trailing blank lines are dropped.
+// URLs and identifiers like __init__ are never rewritten, and anything else, such as numbered lists and links, is left as is.
+func FromMarkdown(s string) string {
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	out := make([]string, 0, len(lines))
+	inCode := false
+	blank := false
+	for _, line := range lines {
+		if fencePattern.MatchString(line) {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			out = append(out, line)
+			blank = false
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			if !blank && len(out) > 0 {
+				out = append(out, "")
+			}
+			blank = true
+			continue
+		}
+		blank = false
+		out = append(out, convertLine(line))
+	}
+	for len(out) > 0 && out[len(out)-1] == "" {
+		out = out[:len(out)-1]
+	}
+	return strings.Join(out, "\n")
+}
+
+// convertLine converts the Markdown in a line outside code blocks.
+func convertLine(line string) string {
+	if m := headingPattern.FindStringSubmatch(line); m != nil {
+		line = m[1]
+	}
+	line = bulletPattern.ReplaceAllString(line, "${1}・")
+
+	// Rewrite only the text between protected spans
+	var b strings.Builder
+	last := 0
+	for _, loc := range protectedPattern.FindAllStringIndex(line, -1) {
+		b.WriteString(stripEmphasis(line[last:loc[0]]))
+		span := line[loc[0]:loc[1]]
+		if strings.HasPrefix(span, "`") {
+			span = span[1 : len(span)-1]
+		}
+		b.WriteString(span)
+		last = loc[1]
+	}
+	b.WriteString(stripEmphasis(line[last:]))
+	return b.String()
+}
+
+// stripEmphasis removes bold and italic markers from text.
+func stripEmphasis(text string) string {
+	text = boldPattern.ReplaceAllString(text, "$1")
+	// Adjacent matches share an edge character, so repeat until nothing changes
+	for _, p := range []*regexp.Regexp{underscoreBoldPattern, italicPattern} {
+		for {
+			replaced := p.ReplaceAllStringFunc(text, func(match string) string {
+				m := p.FindStringSubmatch(match)
+				if identifierPattern.MatchString(strings.TrimPrefix(strings.TrimSuffix(match, m[3]), m[1])) {
+					return match
+				}
+				return m[1] + m[2] + m[3]
+			})
+			if replaced == text {
+				break
+			}
+			text = replaced
+		}
+	}
+	return text
+}
@@ -0,0 +1,79 @@
+package linetext_test
+
+import (
+	"testing"
+	"yuruppu/internal/linetext"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// =============================================================================
+// FromMarkdown Tests
+// =============================================================================
+
+func TestFromMarkdown(t *testing.T) {
+	t.Run("converts a sample reply", func(t *testing.T) {
+		in := "## 今週の予定\n" +
+			"\n" +
+			"**花見**は*土曜日*です！\n" +
+			"\n" +
+			"\n" +
+			"\n" +
+			"- 場所: 代々木公園\n" +
+			"- 持ち物: __レジャーシート__\n" +
+			"  * 飲み物\n" +
+			"\n" +
+			"詳細は https://example.com/a_b_c/**/x を見てね。\n" +
+			"\n" +
+			"```\n" +
+			"- keep *this*\n" +
+			"\n" +
+			"\n" +
+			"```\n"
+
+		got := linetext.FromMarkdown(in)
+
+		assert.Equal(t, "今週の予定\n"+
+			"\n"+
+			"花見は土曜日です！\n"+
+			"\n"+
+			"・場所: 代々木公園\n"+
+			"・持ち物: レジャーシート\n"+
+			"  ・飲み物\n"+
+			"\n"+
+			"詳細は https://example.com/a_b_c/**/x を見てね。\n"+
+			"\n"+
+			"- keep *this*", got)
+	})
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "plain text", in: "こんにちは", want: "こんにちは"},
+		{name: "bold", in: "a **b c** d", want: "a b c d"},
+		{name: "underscore bold", in: "a __b c__ d", want: "a b c d"},
+		{name: "adjacent italics", in: "*a* *b*", want: "a b"},
+		{name: "bold and italic together", in: "***both***", want: "both"},
+		{name: "keeps snake_case and dunder names", in: "use my_var_name or __init__", want: "use my_var_name or __init__"},
+		{name: "keeps multiplication", in: "2*3*4 = 24", want: "2*3*4 = 24"},
+		{name: "keeps lone asterisks", in: "5 * 3 and a* b", want: "5 * 3 and a* b"},
+		{name: "keeps inline code content", in: "run `go test ./... **x**`", want: "run go test ./... **x**"},
+		{name: "keeps URLs", in: "see https://example.com/*a*/__b__", want: "see https://example.com/*a*/__b__"},
+		{name: "strips heading markers", in: "# Title #", want: "Title"},
+		{name: "keeps hashtags", in: "#花見", want: "#花見"},
+		{name: "converts plus bullets", in: "+ one", want: "・one"},
+		{name: "keeps numbered lists", in: "1. one\n2. two", want: "1. one\n2. two"},
+		{name: "keeps dashes without space", in: "-5度", want: "-5度"},
+		{name: "drops leading and trailing blank lines", in: "\n\nhi\n\n\n", want: "hi"},
+		{name: "turns CRLF into LF", in: "a\r\n\r\n\r\nb", want: "a\n\nb"},
+		{name: "empty", in: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, linetext.FromMarkdown(tt.in))
+		})
+	}
+}
@@ -0,0 +1,317 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+	"yuruppu/internal/event"
+	"yuruppu/internal/groupprofile"
+	"yuruppu/internal/userprofile"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// defaultConcurrency is how many chat rooms a dispatch run serves at once.
+const defaultConcurrency = 4
+
+// Sender pushes a text message to a chat room.
+type Sender interface {
+	SendPush(to string, text string) error
+}
+
+// EventGetter looks up the event in a chat room.
+type EventGetter interface {
+	Get(ctx context.Context, chatRoomID string) (*event.Event, error)
+}
+
+// UserProfileGetter looks up a user's profile, including notification preferences.
+type UserProfileGetter interface {
+	GetUserProfile(ctx context.Context, userID string) (*userprofile.UserProfile, error)
+}
+
+// GroupProfileGetter looks up a group's profile, including whether the bot is still in it.
+type GroupProfileGetter interface {
+	GetGroupProfile(ctx context.Context, groupID string) (*groupprofile.GroupProfile, error)
+}
+
+// Option configures optional Dispatcher behavior.
+type Option func(*Dispatcher)
+
+// WithCreatorConfirmation makes the dispatcher DM the event creator after a reminder is pushed
+// to a chat room with an event. The DM is skipped if the creator's notification preferences
+// do not accept it at dispatch time.
+func WithCreatorConfirmation(events EventGetter, profiles UserProfileGetter) Option {
+	return func(d *Dispatcher) {
+		d.events = events
+		d.profiles = profiles
+	}
+}
+
+// WithGroupProfiles makes the dispatcher skip reminders for groups the bot has left.
+// Skipped reminders are still marked fired, so they are not pushed late if the bot is added back.
+// Chat rooms without a group profile are always pushed to.
+func WithGroupProfiles(groups GroupProfileGetter) Option {
+	return func(d *Dispatcher) {
+		d.groups = groups
+	}
+}
+
+// WithConcurrency sets how many chat rooms a dispatch run serves at once.
+// Reminders of one chat room are always pushed one after another, oldest first.
+// Defaults to 4.
+func WithConcurrency(n int) Option {
+	return func(d *Dispatcher) {
+		d.concurrency = n
+	}
+}
+
+// WithRunBudget sets how long one dispatch run may spend pushing reminders.
+// Reminders not reached within the budget stay due and are pushed by the next run.
+// Defaults to the dispatch interval, so a run never overlaps the next tick.
+func WithRunBudget(budget time.Duration) Option {
+	return func(d *Dispatcher) {
+		d.runBudget = budget
+	}
+}
+
+// Dispatcher periodically pushes due reminders.
+type Dispatcher struct {
+	service     *Service
+	sender      Sender
+	interval    time.Duration
+	concurrency int
+	runBudget   time.Duration
+	logger      *slog.Logger
+
+	// markMu serializes MarkFired, since all reminders share one conditionally written object
+	markMu sync.Mutex
+
+	events   EventGetter
+	profiles UserProfileGetter
+	groups   GroupProfileGetter
+}
+
+// NewDispatcher creates a Dispatcher that checks for due reminders every interval.
+func NewDispatcher(service *Service, sender Sender, interval time.Duration, logger *slog.Logger, opts ...Option) (*Dispatcher, error) {
+	if service == nil {
+		return nil, errors.New("service cannot be nil")
+	}
+	if sender == nil {
+		return nil, errors.New("sender cannot be nil")
+	}
+	if interval <= 0 {
+		return nil, errors.New("interval must be positive")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil")
+	}
+	d := &Dispatcher{
+		service:     service,
+		sender:      sender,
+		interval:    interval,
+		concurrency: defaultConcurrency,
+		runBudget:   interval,
+		logger:      logger,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	if d.concurrency <= 0 {
+		return nil, errors.New("concurrency must be positive")
+	}
+	if d.runBudget <= 0 {
+		return nil, errors.New("run budget must be positive")
+	}
+	if (d.events == nil) != (d.profiles == nil) {
+		return nil, errors.New("creator confirmation requires both events and profiles")
+	}
+	return d, nil
+}
+
+// Run dispatches due reminders every interval until ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := d.DispatchDue(ctx, now); err != nil {
+				d.logger.ErrorContext(ctx, "failed to dispatch reminders", slog.Any("error", err))
+			}
+		}
+	}
+}
+
+// DispatchDue pushes every reminder due at now.
+// Chat rooms are served by a pool of at most concurrency workers, each pushing one
+// chat room's reminders in order. Once the run budget is spent, no further reminder
+// is started; those left over stay due and are logged and picked up by the next run.
+// Each reminder is marked fired before it is pushed, so a reminder whose mark
+// fails (already fired, or lost a concurrent write) is skipped, and a restart
+// after the mark never sends it again. A crash between mark and push drops
+// the reminder rather than sending it twice.
+func (d *Dispatcher) DispatchDue(ctx context.Context, now time.Time) error {
+	due, err := d.service.ListDue(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(d.runBudget)
+	var (
+		mu       sync.Mutex
+		deferred int
+		rooms    []string
+	)
+	var g errgroup.Group
+	g.SetLimit(d.concurrency)
+	for _, group := range groupByChatRoom(due) {
+		g.Go(func() error {
+			if n := d.dispatchChatRoom(ctx, group, now, deadline); n > 0 {
+				mu.Lock()
+				deferred += n
+				rooms = append(rooms, group[0].ChatRoomID)
+				mu.Unlock()
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	if deferred > 0 {
+		d.logger.WarnContext(ctx, "reminder run budget exhausted, deferring to next run",
+			slog.Duration("runBudget", d.runBudget),
+			slog.Int("deferredReminders", deferred),
+			slog.Any("chatRoomIDs", rooms),
+		)
+	}
+	return nil
+}
+
+// groupByChatRoom splits reminders by chat room, keeping their order within and across rooms.
+func groupByChatRoom(reminders []*Reminder) [][]*Reminder {
+	var groups [][]*Reminder
+	index := make(map[string]int)
+	for _, r := range reminders {
+		i, ok := index[r.ChatRoomID]
+		if !ok {
+			i = len(groups)
+			index[r.ChatRoomID] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], r)
+	}
+	return groups
+}
+
+// dispatchChatRoom pushes one chat room's reminders in order until deadline passes or ctx ends.
+// Returns the number of reminders left for the next run.
+func (d *Dispatcher) dispatchChatRoom(ctx context.Context, reminders []*Reminder, now, deadline time.Time) int {
+	for i, r := range reminders {
+		if ctx.Err() != nil || time.Now().After(deadline) {
+			return len(reminders) - i
+		}
+		d.dispatch(ctx, r, now)
+	}
+	return 0
+}
+
+// dispatch marks r fired and pushes it.
+func (d *Dispatcher) dispatch(ctx context.Context, r *Reminder, now time.Time) {
+	d.markMu.Lock()
+	err := d.service.MarkFired(ctx, r.ID, now)
+	d.markMu.Unlock()
+	if err != nil {
+		if errors.As(err, new(*AlreadyFiredError)) {
+			d.logger.DebugContext(ctx, "reminder already fired, skipping", slog.String("reminderID", r.ID))
+		} else {
+			d.logger.WarnContext(ctx, "failed to mark reminder fired, will retry next tick",
+				slog.String("reminderID", r.ID),
+				slog.Any("error", err),
+			)
+		}
+		return
+	}
+
+	if d.groups != nil && d.inactiveGroup(ctx, r.ChatRoomID) {
+		d.logger.InfoContext(ctx, "bot has left the group, skipping reminder",
+			slog.String("reminderID", r.ID),
+			slog.String("chatRoomID", r.ChatRoomID),
+		)
+		return
+	}
+
+	if err := d.sender.SendPush(r.ChatRoomID, r.Text); err != nil {
+		d.logger.ErrorContext(ctx, "failed to push reminder",
+			slog.String("reminderID", r.ID),
+			slog.String("chatRoomID", r.ChatRoomID),
+			slog.Any("error", err),
+		)
+		return
+	}
+
+	d.logger.InfoContext(ctx, "reminder dispatched",
+		slog.String("reminderID", r.ID),
+		slog.String("chatRoomID", r.ChatRoomID),
+	)
+
+	if d.events != nil {
+		d.confirmToCreator(ctx, r, now)
+	}
+}
+
+// inactiveGroup reports whether chatRoomID is a group the bot has left.
+// A missing or unreadable profile counts as active, so a lookup failure never drops a reminder.
+func (d *Dispatcher) inactiveGroup(ctx context.Context, chatRoomID string) bool {
+	profile, err := d.groups.GetGroupProfile(ctx, chatRoomID)
+	if err != nil {
+		return false
+	}
+	return profile.Inactive
+}
+
+// confirmToCreator DMs the creator of the chat room's event that the reminder was pushed.
+// Failures are logged and never affect the reminder itself.
+func (d *Dispatcher) confirmToCreator(ctx context.Context, r *Reminder, now time.Time) {
+	ev, err := d.events.Get(ctx, r.ChatRoomID)
+	if err != nil {
+		if !errors.As(err, new(*event.NotFoundError)) {
+			d.logger.WarnContext(ctx, "failed to get event for reminder confirmation",
+				slog.String("reminderID", r.ID),
+				slog.Any("error", err),
+			)
+		}
+		return
+	}
+
+	profile, err := d.profiles.GetUserProfile(ctx, ev.CreatorID)
+	if err != nil {
+		d.logger.WarnContext(ctx, "failed to get creator profile for reminder confirmation",
+			slog.String("reminderID", r.ID),
+			slog.String("creatorID", ev.CreatorID),
+			slog.Any("error", err),
+		)
+		return
+	}
+	if !profile.AcceptsNotificationAt(now) {
+		d.logger.DebugContext(ctx, "reminder confirmation suppressed by creator preferences",
+			slog.String("reminderID", r.ID),
+			slog.String("creatorID", ev.CreatorID),
+		)
+		return
+	}
+
+	text := fmt.Sprintf("「%s」のリマインダーを送ったよ！", ev.Title)
+	if err := d.sender.SendPush(ev.CreatorID, text); err != nil {
+		d.logger.WarnContext(ctx, "failed to push reminder confirmation",
+			slog.String("reminderID", r.ID),
+			slog.String("creatorID", ev.CreatorID),
+			slog.Any("error", err),
+		)
+	}
+}
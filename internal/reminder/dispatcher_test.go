@@ -0,0 +1,450 @@
+package reminder_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+	"yuruppu/internal/event"
+	"yuruppu/internal/groupprofile"
+	"yuruppu/internal/reminder"
+	"yuruppu/internal/userprofile"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// NewDispatcher Tests
+// =============================================================================
+
+func TestNewDispatcher(t *testing.T) {
+	svc, err := reminder.NewService(newMockStorage())
+	require.NoError(t, err)
+	logger := slog.New(slog.DiscardHandler)
+
+	tests := []struct {
+		name     string
+		service  *reminder.Service
+		sender   reminder.Sender
+		interval time.Duration
+		logger   *slog.Logger
+		wantErr  string
+	}{
+		{name: "nil service", sender: &mockSender{}, interval: time.Minute, logger: logger, wantErr: "service cannot be nil"},
+		{name: "nil sender", service: svc, interval: time.Minute, logger: logger, wantErr: "sender cannot be nil"},
+		{name: "zero interval", service: svc, sender: &mockSender{}, logger: logger, wantErr: "interval must be positive"},
+		{name: "nil logger", service: svc, sender: &mockSender{}, interval: time.Minute, wantErr: "logger cannot be nil"},
+	}
+
+	t.Run("zero concurrency", func(t *testing.T) {
+		d, err := reminder.NewDispatcher(svc, &mockSender{}, time.Minute, logger, reminder.WithConcurrency(0))
+
+		require.Error(t, err)
+		assert.Nil(t, d)
+		assert.Contains(t, err.Error(), "concurrency must be positive")
+	})
+
+	t.Run("zero run budget", func(t *testing.T) {
+		d, err := reminder.NewDispatcher(svc, &mockSender{}, time.Minute, logger, reminder.WithRunBudget(0))
+
+		require.Error(t, err)
+		assert.Nil(t, d)
+		assert.Contains(t, err.Error(), "run budget must be positive")
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := reminder.NewDispatcher(tt.service, tt.sender, tt.interval, tt.logger)
+
+			require.Error(t, err)
+			assert.Nil(t, d)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+// =============================================================================
+// DispatchDue Tests
+// =============================================================================
+
+func TestDispatcher_DispatchDue(t *testing.T) {
+	t.Run("pushes due reminder once", func(t *testing.T) {
+		svc, err := reminder.NewService(newMockStorage())
+		require.NoError(t, err)
+		ctx := context.Background()
+		require.NoError(t, svc.Create(ctx, &reminder.Reminder{ChatRoomID: "group-1", NotifyAt: testPast, Text: "Meetup soon"}))
+		sender := &mockSender{}
+		d, err := reminder.NewDispatcher(svc, sender, time.Minute, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		require.NoError(t, d.DispatchDue(ctx, testNow))
+		require.NoError(t, d.DispatchDue(ctx, testNow.Add(time.Minute)))
+
+		require.Len(t, sender.pushes, 1)
+		assert.Equal(t, "group-1", sender.pushes[0].to)
+		assert.Equal(t, "Meetup soon", sender.pushes[0].text)
+	})
+
+	t.Run("skips fired reminder after restart", func(t *testing.T) {
+		store := newMockStorage()
+		svc, err := reminder.NewService(store)
+		require.NoError(t, err)
+		ctx := context.Background()
+		require.NoError(t, svc.Create(ctx, &reminder.Reminder{ChatRoomID: "group-1", NotifyAt: testPast}))
+		first := &mockSender{}
+		d, err := reminder.NewDispatcher(svc, first, time.Minute, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+		require.NoError(t, d.DispatchDue(ctx, testNow))
+
+		// Restart: new service and dispatcher over the same storage
+		restartedSvc, err := reminder.NewService(store)
+		require.NoError(t, err)
+		second := &mockSender{}
+		restarted, err := reminder.NewDispatcher(restartedSvc, second, time.Minute, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+		require.NoError(t, restarted.DispatchDue(ctx, testNow))
+
+		assert.Len(t, first.pushes, 1)
+		assert.Empty(t, second.pushes)
+	})
+
+	t.Run("does not push when mark fails", func(t *testing.T) {
+		store := newMockStorage()
+		svc, err := reminder.NewService(store)
+		require.NoError(t, err)
+		ctx := context.Background()
+		require.NoError(t, svc.Create(ctx, &reminder.Reminder{ChatRoomID: "group-1", NotifyAt: testPast}))
+		store.writeErr = errors.New("generation mismatch")
+		sender := &mockSender{}
+		d, err := reminder.NewDispatcher(svc, sender, time.Minute, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		err = d.DispatchDue(ctx, testNow)
+
+		require.NoError(t, err)
+		assert.Empty(t, sender.pushes)
+	})
+
+	t.Run("continues after push error", func(t *testing.T) {
+		svc, err := reminder.NewService(newMockStorage())
+		require.NoError(t, err)
+		ctx := context.Background()
+		require.NoError(t, svc.Create(ctx, &reminder.Reminder{ChatRoomID: "group-1", NotifyAt: testPast.Add(-time.Minute)}))
+		require.NoError(t, svc.Create(ctx, &reminder.Reminder{ChatRoomID: "group-2", NotifyAt: testPast}))
+		sender := &mockSender{errFor: map[string]error{"group-1": errors.New("push failed")}}
+		d, err := reminder.NewDispatcher(svc, sender, time.Minute, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		err = d.DispatchDue(ctx, testNow)
+
+		require.NoError(t, err)
+		require.Len(t, sender.pushes, 1)
+		assert.Equal(t, "group-2", sender.pushes[0].to)
+	})
+}
+
+// =============================================================================
+// Worker Pool Tests
+// =============================================================================
+
+func TestDispatcher_WorkerPool(t *testing.T) {
+	createDue := func(t *testing.T, svc *reminder.Service, chatRoomIDs ...string) {
+		t.Helper()
+		for i, chatRoomID := range chatRoomIDs {
+			require.NoError(t, svc.Create(context.Background(), &reminder.Reminder{
+				ChatRoomID: chatRoomID,
+				NotifyAt:   testPast.Add(time.Duration(i) * time.Second),
+				Text:       fmt.Sprintf("reminder %d", i),
+			}))
+		}
+	}
+
+	t.Run("limits how many chat rooms are served at once", func(t *testing.T) {
+		svc, err := reminder.NewService(newMockStorage())
+		require.NoError(t, err)
+		createDue(t, svc, "group-1", "group-2", "group-3", "group-4", "group-5", "group-6")
+		sender := &slowSender{delay: 20 * time.Millisecond}
+		d, err := reminder.NewDispatcher(svc, sender, time.Minute, slog.New(slog.DiscardHandler), reminder.WithConcurrency(2))
+		require.NoError(t, err)
+
+		require.NoError(t, d.DispatchDue(context.Background(), testNow))
+
+		assert.Len(t, sender.pushes, 6)
+		assert.Equal(t, 2, sender.maxInFlight)
+	})
+
+	t.Run("pushes one chat room's reminders in order", func(t *testing.T) {
+		svc, err := reminder.NewService(newMockStorage())
+		require.NoError(t, err)
+		createDue(t, svc, "group-1", "group-1", "group-1")
+		sender := &slowSender{delay: time.Millisecond}
+		d, err := reminder.NewDispatcher(svc, sender, time.Minute, slog.New(slog.DiscardHandler), reminder.WithConcurrency(4))
+		require.NoError(t, err)
+
+		require.NoError(t, d.DispatchDue(context.Background(), testNow))
+
+		require.Len(t, sender.pushes, 3)
+		assert.Equal(t, []string{"reminder 0", "reminder 1", "reminder 2"}, []string{sender.pushes[0].text, sender.pushes[1].text, sender.pushes[2].text})
+		assert.Equal(t, 1, sender.maxInFlight)
+	})
+
+	t.Run("defers reminders not reached within the run budget", func(t *testing.T) {
+		svc, err := reminder.NewService(newMockStorage())
+		require.NoError(t, err)
+		createDue(t, svc, "group-1", "group-2", "group-3", "group-4", "group-5", "group-6")
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&buf, nil))
+		sender := &slowSender{delay: 40 * time.Millisecond}
+		d, err := reminder.NewDispatcher(svc, sender, time.Minute, logger,
+			reminder.WithConcurrency(1),
+			reminder.WithRunBudget(60*time.Millisecond),
+		)
+		require.NoError(t, err)
+
+		require.NoError(t, d.DispatchDue(context.Background(), testNow))
+
+		pushed := len(sender.pushes)
+		assert.Positive(t, pushed)
+		assert.Less(t, pushed, 6, "the budget should stop the run early")
+		remaining, err := svc.ListDue(context.Background(), testNow)
+		require.NoError(t, err)
+		assert.Len(t, remaining, 6-pushed, "deferred reminders should stay due")
+		assert.Contains(t, buf.String(), "reminder run budget exhausted, deferring to next run")
+
+		// The next run picks up where this one stopped
+		for range 6 {
+			if len(sender.pushes) == 6 {
+				break
+			}
+			require.NoError(t, d.DispatchDue(context.Background(), testNow))
+		}
+		assert.Len(t, sender.pushes, 6)
+	})
+}
+
+// =============================================================================
+// Creator Confirmation Tests
+// =============================================================================
+
+func TestDispatcher_CreatorConfirmation(t *testing.T) {
+	newDispatcher := func(t *testing.T, sender *mockSender, events *mockEventGetter, profiles *mockProfileGetter) *reminder.Dispatcher {
+		t.Helper()
+		svc, err := reminder.NewService(newMockStorage())
+		require.NoError(t, err)
+		require.NoError(t, svc.Create(context.Background(), &reminder.Reminder{ChatRoomID: "group-1", NotifyAt: testPast, Text: "Meetup soon"}))
+		d, err := reminder.NewDispatcher(svc, sender, time.Minute, slog.New(slog.DiscardHandler),
+			reminder.WithCreatorConfirmation(events, profiles))
+		require.NoError(t, err)
+		return d
+	}
+	events := func() *mockEventGetter {
+		return &mockEventGetter{events: map[string]*event.Event{
+			"group-1": {ChatRoomID: "group-1", CreatorID: "user-creator", Title: "Go Meetup"},
+		}}
+	}
+
+	t.Run("pushes confirmation to the creator", func(t *testing.T) {
+		sender := &mockSender{}
+		profiles := &mockProfileGetter{profiles: map[string]*userprofile.UserProfile{"user-creator": {DisplayName: "Alice"}}}
+		d := newDispatcher(t, sender, events(), profiles)
+
+		require.NoError(t, d.DispatchDue(context.Background(), testNow))
+
+		require.Len(t, sender.pushes, 2)
+		assert.Equal(t, "group-1", sender.pushes[0].to)
+		assert.Equal(t, "user-creator", sender.pushes[1].to)
+		assert.Contains(t, sender.pushes[1].text, "Go Meetup")
+	})
+
+	t.Run("suppresses confirmation when notifications are disabled", func(t *testing.T) {
+		sender := &mockSender{}
+		profiles := &mockProfileGetter{profiles: map[string]*userprofile.UserProfile{
+			"user-creator": {DisplayName: "Alice", Notifications: userprofile.NotificationPrefs{Disabled: true}},
+		}}
+		d := newDispatcher(t, sender, events(), profiles)
+
+		require.NoError(t, d.DispatchDue(context.Background(), testNow))
+
+		require.Len(t, sender.pushes, 1)
+		assert.Equal(t, "group-1", sender.pushes[0].to)
+	})
+
+	t.Run("suppresses confirmation during quiet hours", func(t *testing.T) {
+		sender := &mockSender{}
+		// testNow is 19:00 in Asia/Tokyo
+		profiles := &mockProfileGetter{profiles: map[string]*userprofile.UserProfile{
+			"user-creator": {DisplayName: "Alice", Notifications: userprofile.NotificationPrefs{QuietHoursStart: 18, QuietHoursEnd: 8}},
+		}}
+		d := newDispatcher(t, sender, events(), profiles)
+
+		require.NoError(t, d.DispatchDue(context.Background(), testNow))
+
+		require.Len(t, sender.pushes, 1)
+	})
+
+	t.Run("skips confirmation when chat room has no event", func(t *testing.T) {
+		sender := &mockSender{}
+		d := newDispatcher(t, sender, &mockEventGetter{}, &mockProfileGetter{})
+
+		require.NoError(t, d.DispatchDue(context.Background(), testNow))
+
+		require.Len(t, sender.pushes, 1)
+	})
+
+	t.Run("skips confirmation when reminder push fails", func(t *testing.T) {
+		sender := &mockSender{errFor: map[string]error{"group-1": errors.New("push failed")}}
+		profiles := &mockProfileGetter{profiles: map[string]*userprofile.UserProfile{"user-creator": {DisplayName: "Alice"}}}
+		d := newDispatcher(t, sender, events(), profiles)
+
+		require.NoError(t, d.DispatchDue(context.Background(), testNow))
+
+		assert.Empty(t, sender.pushes)
+	})
+
+	t.Run("requires both events and profiles", func(t *testing.T) {
+		svc, err := reminder.NewService(newMockStorage())
+		require.NoError(t, err)
+
+		d, err := reminder.NewDispatcher(svc, &mockSender{}, time.Minute, slog.New(slog.DiscardHandler),
+			reminder.WithCreatorConfirmation(events(), nil))
+
+		require.Error(t, err)
+		assert.Nil(t, d)
+	})
+}
+
+// =============================================================================
+// Group Profile Tests
+// =============================================================================
+
+func TestDispatcher_GroupProfiles(t *testing.T) {
+	newDispatcher := func(t *testing.T, sender *mockSender, groups *mockGroupProfileGetter) *reminder.Dispatcher {
+		t.Helper()
+		svc, err := reminder.NewService(newMockStorage())
+		require.NoError(t, err)
+		ctx := context.Background()
+		require.NoError(t, svc.Create(ctx, &reminder.Reminder{ChatRoomID: "group-left", NotifyAt: testPast, Text: "Meetup soon"}))
+		require.NoError(t, svc.Create(ctx, &reminder.Reminder{ChatRoomID: "group-active", NotifyAt: testPast, Text: "Dinner soon"}))
+		d, err := reminder.NewDispatcher(svc, sender, time.Minute, slog.New(slog.DiscardHandler),
+			reminder.WithGroupProfiles(groups))
+		require.NoError(t, err)
+		return d
+	}
+
+	t.Run("skips reminders for groups the bot has left", func(t *testing.T) {
+		sender := &mockSender{}
+		d := newDispatcher(t, sender, &mockGroupProfileGetter{profiles: map[string]*groupprofile.GroupProfile{
+			"group-left":   {DisplayName: "Old Group", Inactive: true},
+			"group-active": {DisplayName: "Friends"},
+		}})
+
+		require.NoError(t, d.DispatchDue(context.Background(), testNow))
+		require.NoError(t, d.DispatchDue(context.Background(), testNow.Add(time.Minute)))
+
+		require.Len(t, sender.pushes, 1, "the skipped reminder should not be retried")
+		assert.Equal(t, "group-active", sender.pushes[0].to)
+	})
+
+	t.Run("pushes when the group profile is missing", func(t *testing.T) {
+		sender := &mockSender{}
+		d := newDispatcher(t, sender, &mockGroupProfileGetter{})
+
+		require.NoError(t, d.DispatchDue(context.Background(), testNow))
+
+		assert.Len(t, sender.pushes, 2)
+	})
+}
+
+// =============================================================================
+// Mock Sender
+// =============================================================================
+
+type push struct {
+	to   string
+	text string
+}
+
+type mockSender struct {
+	mu     sync.Mutex
+	pushes []push
+	errFor map[string]error
+}
+
+func (m *mockSender) SendPush(to string, text string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.errFor[to]; err != nil {
+		return err
+	}
+	m.pushes = append(m.pushes, push{to: to, text: text})
+	return nil
+}
+
+// slowSender takes delay per push and records the highest number of pushes in flight.
+type slowSender struct {
+	delay       time.Duration
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+	pushes      []push
+}
+
+func (s *slowSender) SendPush(to string, text string) error {
+	s.mu.Lock()
+	s.inFlight++
+	s.maxInFlight = max(s.maxInFlight, s.inFlight)
+	s.mu.Unlock()
+
+	time.Sleep(s.delay)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inFlight--
+	s.pushes = append(s.pushes, push{to: to, text: text})
+	return nil
+}
+
+// =============================================================================
+// Mock Lookups
+// =============================================================================
+
+type mockEventGetter struct {
+	events map[string]*event.Event
+}
+
+func (m *mockEventGetter) Get(ctx context.Context, chatRoomID string) (*event.Event, error) {
+	ev, ok := m.events[chatRoomID]
+	if !ok {
+		return nil, &event.NotFoundError{ChatRoomID: chatRoomID}
+	}
+	return ev, nil
+}
+
+type mockProfileGetter struct {
+	profiles map[string]*userprofile.UserProfile
+}
+
+func (m *mockProfileGetter) GetUserProfile(ctx context.Context, userID string) (*userprofile.UserProfile, error) {
+	profile, ok := m.profiles[userID]
+	if !ok {
+		return nil, errors.New("user profile not found")
+	}
+	return profile, nil
+}
+
+type mockGroupProfileGetter struct {
+	profiles map[string]*groupprofile.GroupProfile
+}
+
+func (m *mockGroupProfileGetter) GetGroupProfile(ctx context.Context, groupID string) (*groupprofile.GroupProfile, error) {
+	profile, ok := m.profiles[groupID]
+	if !ok {
+		return nil, errors.New("group profile not found")
+	}
+	return profile, nil
+}
@@ -0,0 +1,353 @@
+package reminder
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Storage defines the storage interface required by reminder service.
+type Storage interface {
+	Read(ctx context.Context, key string) (data []byte, generation int64, err error)
+	Write(ctx context.Context, key, mimetype string, data []byte, expectedGeneration int64) (newGeneration int64, err error)
+}
+
+const storageKey = "all"
+
+const (
+	// MaxSnoozes is how many times a reminder can be snoozed in a row.
+	MaxSnoozes = 3
+	// MaxSnoozeDelay bounds how far snoozing can push a reminder past its original time.
+	MaxSnoozeDelay = 24 * time.Hour
+)
+
+// NotFoundError is returned when no reminder exists for the requested ID,
+// or, with only ChatRoomID set, when no reminder for the chat room has fired.
+type NotFoundError struct {
+	ID         string
+	ChatRoomID string
+}
+
+func (e *NotFoundError) Error() string {
+	if e.ID == "" {
+		return fmt.Sprintf("no fired reminder in %s", e.ChatRoomID)
+	}
+	return fmt.Sprintf("reminder not found: %s", e.ID)
+}
+
+// AlreadyFiredError is returned when marking a reminder that has already fired.
+type AlreadyFiredError struct {
+	ID string
+}
+
+func (e *AlreadyFiredError) Error() string {
+	return fmt.Sprintf("reminder already fired: %s", e.ID)
+}
+
+// AlreadySnoozedError is returned when snoozing a reminder that was already snoozed.
+type AlreadySnoozedError struct {
+	ID string
+}
+
+func (e *AlreadySnoozedError) Error() string {
+	return fmt.Sprintf("reminder already snoozed: %s", e.ID)
+}
+
+// SnoozeLimitError is returned when a snooze would exceed MaxSnoozes or MaxSnoozeDelay.
+type SnoozeLimitError struct {
+	ID string
+}
+
+func (e *SnoozeLimitError) Error() string {
+	return fmt.Sprintf("reminder snooze limit reached: %s", e.ID)
+}
+
+// Reminder is a message to push to a chat room at a given time.
+type Reminder struct {
+	ID         string     `json:"id"`
+	ChatRoomID string     `json:"chatRoomId"`
+	NotifyAt   time.Time  `json:"notifyAt"`
+	Text       string     `json:"text"`
+	FiredAt    *time.Time `json:"firedAt,omitempty"`
+
+	SnoozeCount      int        `json:"snoozeCount,omitempty"`      // snoozes that led to this reminder
+	OriginalNotifyAt *time.Time `json:"originalNotifyAt,omitempty"` // NotifyAt before the first snooze
+	SnoozedTo        string     `json:"snoozedTo,omitempty"`        // ID of the reminder created by snoozing this one
+}
+
+// Fired reports whether the reminder has been dispatched.
+func (r *Reminder) Fired() bool {
+	return r.FiredAt != nil
+}
+
+// Service provides reminder management operations.
+type Service struct {
+	storage Storage
+}
+
+// NewService creates a new Service with the given storage backend.
+// Returns error if storage is nil.
+func NewService(s Storage) (*Service, error) {
+	if s == nil {
+		return nil, errors.New("storage cannot be nil")
+	}
+	return &Service{storage: s}, nil
+}
+
+// Create stores a new reminder, assigning its ID.
+// Reminders are unique per (ChatRoomID, NotifyAt): if one already exists, nothing is written
+// and r is overwritten with the existing reminder, so scheduling twice never pushes twice.
+// Returns error if storage operations fail.
+func (s *Service) Create(ctx context.Context, r *Reminder) error {
+	if r == nil {
+		return errors.New("reminder cannot be nil")
+	}
+	if r.ChatRoomID == "" {
+		return errors.New("chatRoomID cannot be empty")
+	}
+
+	reminders, generation, err := s.readReminders(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read reminders: %w", err)
+	}
+
+	for _, existing := range reminders {
+		if existing.ChatRoomID == r.ChatRoomID && existing.NotifyAt.Equal(r.NotifyAt) {
+			*r = *existing
+			return nil
+		}
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return fmt.Errorf("failed to generate reminder ID: %w", err)
+	}
+	r.ID = id.String()
+	reminders = append(reminders, r)
+
+	if err := s.writeReminders(ctx, reminders, generation); err != nil {
+		return fmt.Errorf("failed to write reminders: %w", err)
+	}
+	return nil
+}
+
+// ListDue returns unfired reminders whose NotifyAt is at or before now, oldest first.
+func (s *Service) ListDue(ctx context.Context, now time.Time) ([]*Reminder, error) {
+	reminders, _, err := s.readReminders(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reminders: %w", err)
+	}
+
+	due := make([]*Reminder, 0, len(reminders))
+	for _, r := range reminders {
+		if r.Fired() || r.NotifyAt.After(now) {
+			continue
+		}
+		due = append(due, r)
+	}
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].NotifyAt.Before(due[j].NotifyAt)
+	})
+	return due, nil
+}
+
+// MarkFired records that the reminder fired at firedAt.
+// The write is conditioned on the generation that was read, so of two concurrent
+// callers at most one succeeds; the other gets a storage error or *AlreadyFiredError.
+// Returns *NotFoundError if no reminder has the ID.
+func (s *Service) MarkFired(ctx context.Context, id string, firedAt time.Time) error {
+	if id == "" {
+		return errors.New("id cannot be empty")
+	}
+
+	reminders, generation, err := s.readReminders(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read reminders: %w", err)
+	}
+
+	var target *Reminder
+	for _, r := range reminders {
+		if r.ID == id {
+			target = r
+			break
+		}
+	}
+	if target == nil {
+		return &NotFoundError{ID: id}
+	}
+	if target.Fired() {
+		return &AlreadyFiredError{ID: id}
+	}
+	target.FiredAt = &firedAt
+
+	if err := s.writeReminders(ctx, reminders, generation); err != nil {
+		return fmt.Errorf("failed to write reminders: %w", err)
+	}
+	return nil
+}
+
+// LatestFired returns the most recently fired reminder for a chat room.
+// Returns *NotFoundError if no reminder for the chat room has fired.
+func (s *Service) LatestFired(ctx context.Context, chatRoomID string) (*Reminder, error) {
+	reminders, _, err := s.readReminders(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reminders: %w", err)
+	}
+
+	var latest *Reminder
+	for _, r := range reminders {
+		if r.ChatRoomID != chatRoomID || !r.Fired() {
+			continue
+		}
+		if latest == nil || r.FiredAt.After(*latest.FiredAt) {
+			latest = r
+		}
+	}
+	if latest == nil {
+		return nil, &NotFoundError{ChatRoomID: chatRoomID}
+	}
+	return latest, nil
+}
+
+// Snooze creates a fresh reminder with the same text that fires delay after now.
+// Only a fired reminder can be snoozed, and only once; the new reminder can be snoozed again.
+// Returns *AlreadySnoozedError if the reminder was already snoozed, and *SnoozeLimitError if the chain
+// would exceed MaxSnoozes or end more than MaxSnoozeDelay after the original notify time.
+// Returns *NotFoundError if no reminder has the ID.
+func (s *Service) Snooze(ctx context.Context, id string, delay time.Duration, now time.Time) (*Reminder, error) {
+	if id == "" {
+		return nil, errors.New("id cannot be empty")
+	}
+	if delay <= 0 {
+		return nil, errors.New("delay must be positive")
+	}
+
+	reminders, generation, err := s.readReminders(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reminders: %w", err)
+	}
+
+	var target *Reminder
+	for _, r := range reminders {
+		if r.ID == id {
+			target = r
+			break
+		}
+	}
+	if target == nil {
+		return nil, &NotFoundError{ID: id}
+	}
+	if !target.Fired() {
+		return nil, fmt.Errorf("reminder not fired yet: %s", id)
+	}
+	if target.SnoozedTo != "" {
+		return nil, &AlreadySnoozedError{ID: id}
+	}
+
+	original := target.NotifyAt
+	if target.OriginalNotifyAt != nil {
+		original = *target.OriginalNotifyAt
+	}
+	notifyAt := now.Add(delay)
+	if target.SnoozeCount >= MaxSnoozes || notifyAt.Sub(original) > MaxSnoozeDelay {
+		return nil, &SnoozeLimitError{ID: id}
+	}
+
+	newID, err := uuid.NewV7()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate reminder ID: %w", err)
+	}
+	snoozed := &Reminder{
+		ID:               newID.String(),
+		ChatRoomID:       target.ChatRoomID,
+		NotifyAt:         notifyAt,
+		Text:             target.Text,
+		SnoozeCount:      target.SnoozeCount + 1,
+		OriginalNotifyAt: &original,
+	}
+	target.SnoozedTo = snoozed.ID
+	reminders = append(reminders, snoozed)
+
+	if err := s.writeReminders(ctx, reminders, generation); err != nil {
+		return nil, fmt.Errorf("failed to write reminders: %w", err)
+	}
+	return snoozed, nil
+}
+
+// readReminders reads and parses reminders from storage.
+// Returns empty slice and generation 0 if no reminders exist.
+func (s *Service) readReminders(ctx context.Context) ([]*Reminder, int64, error) {
+	data, generation, err := s.storage.Read(ctx, storageKey)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if data == nil {
+		return []*Reminder{}, generation, nil
+	}
+
+	reminders, err := parseJSONL(data)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return reminders, generation, nil
+}
+
+// writeReminders serializes and writes reminders to storage with optimistic locking.
+func (s *Service) writeReminders(ctx context.Context, reminders []*Reminder, expectedGeneration int64) error {
+	data, err := serializeJSONL(reminders)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.storage.Write(ctx, storageKey, "application/jsonl", data, expectedGeneration)
+	return err
+}
+
+// parseJSONL parses JSONL data into a slice of reminders.
+func parseJSONL(data []byte) ([]*Reminder, error) {
+	var reminders []*Reminder
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		var r Reminder
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, &r)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return reminders, nil
+}
+
+// serializeJSONL serializes reminders to JSONL format.
+func serializeJSONL(reminders []*Reminder) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, r := range reminders {
+		data, err := json.Marshal(r)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
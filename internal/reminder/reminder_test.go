@@ -0,0 +1,388 @@
+package reminder_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+	"yuruppu/internal/reminder"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testNow    = time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC)
+	testPast   = testNow.Add(-time.Hour)
+	testFuture = testNow.Add(time.Hour)
+)
+
+// =============================================================================
+// NewService Tests
+// =============================================================================
+
+func TestNewService(t *testing.T) {
+	t.Run("nil storage returns error", func(t *testing.T) {
+		svc, err := reminder.NewService(nil)
+
+		require.Error(t, err)
+		assert.Nil(t, svc)
+		assert.Contains(t, err.Error(), "storage cannot be nil")
+	})
+}
+
+// =============================================================================
+// Create / ListDue Tests
+// =============================================================================
+
+func TestService_Create(t *testing.T) {
+	t.Run("assigns ID and stores reminder", func(t *testing.T) {
+		svc, err := reminder.NewService(newMockStorage())
+		require.NoError(t, err)
+
+		r := &reminder.Reminder{ChatRoomID: "group-1", NotifyAt: testPast, Text: "hi"}
+		err = svc.Create(context.Background(), r)
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, r.ID)
+		due, err := svc.ListDue(context.Background(), testNow)
+		require.NoError(t, err)
+		require.Len(t, due, 1)
+		assert.Equal(t, r.ID, due[0].ID)
+	})
+
+	t.Run("same chat room and notify time returns the existing reminder", func(t *testing.T) {
+		storage := newMockStorage()
+		svc, err := reminder.NewService(storage)
+		require.NoError(t, err)
+		ctx := context.Background()
+
+		first := &reminder.Reminder{ChatRoomID: "group-1", NotifyAt: testPast, Text: "hi"}
+		require.NoError(t, svc.Create(ctx, first))
+		generation := storage.generation["all"]
+
+		// The same instant in another zone is still a duplicate
+		second := &reminder.Reminder{ChatRoomID: "group-1", NotifyAt: testPast.In(time.FixedZone("JST", 9*60*60)), Text: "hi again"}
+		err = svc.Create(ctx, second)
+
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, second.ID)
+		assert.Equal(t, "hi", second.Text)
+		assert.Equal(t, generation, storage.generation["all"], "duplicate should not be written")
+		due, err := svc.ListDue(ctx, testNow)
+		require.NoError(t, err)
+		require.Len(t, due, 1)
+		assert.Equal(t, first.ID, due[0].ID)
+	})
+
+	t.Run("different notify time or chat room creates another reminder", func(t *testing.T) {
+		svc, err := reminder.NewService(newMockStorage())
+		require.NoError(t, err)
+		ctx := context.Background()
+
+		first := &reminder.Reminder{ChatRoomID: "group-1", NotifyAt: testPast}
+		otherOffset := &reminder.Reminder{ChatRoomID: "group-1", NotifyAt: testPast.Add(-30 * time.Minute)}
+		otherRoom := &reminder.Reminder{ChatRoomID: "group-2", NotifyAt: testPast}
+		for _, r := range []*reminder.Reminder{first, otherOffset, otherRoom} {
+			require.NoError(t, svc.Create(ctx, r))
+		}
+
+		assert.NotEqual(t, first.ID, otherOffset.ID)
+		assert.NotEqual(t, first.ID, otherRoom.ID)
+		due, err := svc.ListDue(ctx, testNow)
+		require.NoError(t, err)
+		assert.Len(t, due, 3)
+	})
+
+	t.Run("empty chatRoomID returns error", func(t *testing.T) {
+		svc, err := reminder.NewService(newMockStorage())
+		require.NoError(t, err)
+
+		err = svc.Create(context.Background(), &reminder.Reminder{NotifyAt: testPast})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "chatRoomID cannot be empty")
+	})
+}
+
+func TestService_ListDue(t *testing.T) {
+	t.Run("excludes future and fired reminders", func(t *testing.T) {
+		svc, err := reminder.NewService(newMockStorage())
+		require.NoError(t, err)
+		ctx := context.Background()
+
+		past := &reminder.Reminder{ChatRoomID: "group-1", NotifyAt: testPast}
+		fired := &reminder.Reminder{ChatRoomID: "group-2", NotifyAt: testPast}
+		future := &reminder.Reminder{ChatRoomID: "group-3", NotifyAt: testFuture}
+		for _, r := range []*reminder.Reminder{past, fired, future} {
+			require.NoError(t, svc.Create(ctx, r))
+		}
+		require.NoError(t, svc.MarkFired(ctx, fired.ID, testNow))
+
+		due, err := svc.ListDue(ctx, testNow)
+
+		require.NoError(t, err)
+		require.Len(t, due, 1)
+		assert.Equal(t, past.ID, due[0].ID)
+	})
+}
+
+// =============================================================================
+// MarkFired Tests
+// =============================================================================
+
+func TestService_MarkFired(t *testing.T) {
+	t.Run("persists FiredAt", func(t *testing.T) {
+		store := newMockStorage()
+		svc, err := reminder.NewService(store)
+		require.NoError(t, err)
+		ctx := context.Background()
+		r := &reminder.Reminder{ChatRoomID: "group-1", NotifyAt: testPast}
+		require.NoError(t, svc.Create(ctx, r))
+
+		err = svc.MarkFired(ctx, r.ID, testNow)
+
+		require.NoError(t, err)
+		assert.Contains(t, string(store.data["all"]), `"firedAt":"2026-02-01T10:00:00Z"`)
+	})
+
+	t.Run("second mark returns AlreadyFiredError", func(t *testing.T) {
+		svc, err := reminder.NewService(newMockStorage())
+		require.NoError(t, err)
+		ctx := context.Background()
+		r := &reminder.Reminder{ChatRoomID: "group-1", NotifyAt: testPast}
+		require.NoError(t, svc.Create(ctx, r))
+		require.NoError(t, svc.MarkFired(ctx, r.ID, testNow))
+
+		err = svc.MarkFired(ctx, r.ID, testNow)
+
+		require.ErrorAs(t, err, new(*reminder.AlreadyFiredError))
+	})
+
+	t.Run("unknown ID returns NotFoundError", func(t *testing.T) {
+		svc, err := reminder.NewService(newMockStorage())
+		require.NoError(t, err)
+
+		err = svc.MarkFired(context.Background(), "missing", testNow)
+
+		require.ErrorAs(t, err, new(*reminder.NotFoundError))
+	})
+
+	t.Run("concurrent marks succeed at most once", func(t *testing.T) {
+		store := newMockStorage()
+		svc, err := reminder.NewService(store)
+		require.NoError(t, err)
+		ctx := context.Background()
+		r := &reminder.Reminder{ChatRoomID: "group-1", NotifyAt: testPast}
+		require.NoError(t, svc.Create(ctx, r))
+
+		// Both callers read the same generation before either writes
+		store.holdReads(2)
+		errs := make(chan error, 2)
+		for range 2 {
+			go func() { errs <- svc.MarkFired(ctx, r.ID, testNow) }()
+		}
+
+		successes := 0
+		for range 2 {
+			if <-errs == nil {
+				successes++
+			}
+		}
+		assert.Equal(t, 1, successes)
+	})
+}
+
+// =============================================================================
+// Snooze Tests
+// =============================================================================
+
+func TestService_Snooze(t *testing.T) {
+	// createFired stores a reminder that fired at testNow and returns it.
+	createFired := func(t *testing.T, svc *reminder.Service) *reminder.Reminder {
+		t.Helper()
+		r := &reminder.Reminder{ChatRoomID: "group-1", NotifyAt: testPast, Text: "meet at 7"}
+		require.NoError(t, svc.Create(context.Background(), r))
+		require.NoError(t, svc.MarkFired(context.Background(), r.ID, testNow))
+		return r
+	}
+
+	t.Run("creates a fresh future reminder", func(t *testing.T) {
+		svc, err := reminder.NewService(newMockStorage())
+		require.NoError(t, err)
+		r := createFired(t, svc)
+
+		snoozed, err := svc.Snooze(context.Background(), r.ID, 10*time.Minute, testNow)
+
+		require.NoError(t, err)
+		assert.NotEqual(t, r.ID, snoozed.ID)
+		assert.Equal(t, "group-1", snoozed.ChatRoomID)
+		assert.Equal(t, "meet at 7", snoozed.Text)
+		assert.Equal(t, testNow.Add(10*time.Minute), snoozed.NotifyAt)
+		assert.Equal(t, 1, snoozed.SnoozeCount)
+		require.NotNil(t, snoozed.OriginalNotifyAt)
+		assert.Equal(t, testPast, *snoozed.OriginalNotifyAt)
+		due, err := svc.ListDue(context.Background(), testNow.Add(10*time.Minute))
+		require.NoError(t, err)
+		require.Len(t, due, 1)
+		assert.Equal(t, snoozed.ID, due[0].ID)
+	})
+
+	t.Run("rejects snoozing the same reminder twice", func(t *testing.T) {
+		svc, err := reminder.NewService(newMockStorage())
+		require.NoError(t, err)
+		r := createFired(t, svc)
+		_, err = svc.Snooze(context.Background(), r.ID, 10*time.Minute, testNow)
+		require.NoError(t, err)
+
+		_, err = svc.Snooze(context.Background(), r.ID, 10*time.Minute, testNow)
+
+		require.ErrorAs(t, err, new(*reminder.AlreadySnoozedError))
+	})
+
+	t.Run("rejects an unfired reminder", func(t *testing.T) {
+		svc, err := reminder.NewService(newMockStorage())
+		require.NoError(t, err)
+		r := &reminder.Reminder{ChatRoomID: "group-1", NotifyAt: testFuture}
+		require.NoError(t, svc.Create(context.Background(), r))
+
+		_, err = svc.Snooze(context.Background(), r.ID, 10*time.Minute, testNow)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "reminder not fired yet")
+	})
+
+	t.Run("rejects snoozing past MaxSnoozes", func(t *testing.T) {
+		svc, err := reminder.NewService(newMockStorage())
+		require.NoError(t, err)
+		ctx := context.Background()
+		current := createFired(t, svc)
+		now := testNow
+		for range reminder.MaxSnoozes {
+			next, err := svc.Snooze(ctx, current.ID, time.Minute, now)
+			require.NoError(t, err)
+			now = next.NotifyAt
+			require.NoError(t, svc.MarkFired(ctx, next.ID, now))
+			current = next
+		}
+
+		_, err = svc.Snooze(ctx, current.ID, time.Minute, now)
+
+		require.ErrorAs(t, err, new(*reminder.SnoozeLimitError))
+	})
+
+	t.Run("rejects snoozing beyond MaxSnoozeDelay", func(t *testing.T) {
+		svc, err := reminder.NewService(newMockStorage())
+		require.NoError(t, err)
+		r := createFired(t, svc)
+
+		_, err = svc.Snooze(context.Background(), r.ID, reminder.MaxSnoozeDelay, testNow)
+
+		require.ErrorAs(t, err, new(*reminder.SnoozeLimitError))
+	})
+
+	t.Run("unknown ID returns NotFoundError", func(t *testing.T) {
+		svc, err := reminder.NewService(newMockStorage())
+		require.NoError(t, err)
+
+		_, err = svc.Snooze(context.Background(), "missing", time.Minute, testNow)
+
+		require.ErrorAs(t, err, new(*reminder.NotFoundError))
+	})
+}
+
+func TestService_LatestFired(t *testing.T) {
+	t.Run("returns the most recently fired reminder of the chat room", func(t *testing.T) {
+		svc, err := reminder.NewService(newMockStorage())
+		require.NoError(t, err)
+		ctx := context.Background()
+		older := &reminder.Reminder{ChatRoomID: "group-1", NotifyAt: testPast.Add(-time.Hour)}
+		newer := &reminder.Reminder{ChatRoomID: "group-1", NotifyAt: testPast}
+		other := &reminder.Reminder{ChatRoomID: "group-2", NotifyAt: testPast}
+		for _, r := range []*reminder.Reminder{older, newer, other} {
+			require.NoError(t, svc.Create(ctx, r))
+		}
+		require.NoError(t, svc.MarkFired(ctx, older.ID, testNow.Add(-time.Minute)))
+		require.NoError(t, svc.MarkFired(ctx, newer.ID, testNow))
+		require.NoError(t, svc.MarkFired(ctx, other.ID, testNow.Add(time.Minute)))
+
+		got, err := svc.LatestFired(ctx, "group-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, newer.ID, got.ID)
+	})
+
+	t.Run("returns NotFoundError when nothing has fired", func(t *testing.T) {
+		svc, err := reminder.NewService(newMockStorage())
+		require.NoError(t, err)
+		require.NoError(t, svc.Create(context.Background(), &reminder.Reminder{ChatRoomID: "group-1", NotifyAt: testFuture}))
+
+		_, err = svc.LatestFired(context.Background(), "group-1")
+
+		require.ErrorAs(t, err, new(*reminder.NotFoundError))
+	})
+}
+
+// =============================================================================
+// Mock Storage
+// =============================================================================
+
+type mockStorage struct {
+	mu         sync.Mutex
+	data       map[string][]byte
+	generation map[string]int64
+	readGate   *sync.WaitGroup // when set, reads block until all held reads are in progress
+	readsHeld  int
+	writeErr   error
+}
+
+func newMockStorage() *mockStorage {
+	return &mockStorage{
+		data:       make(map[string][]byte),
+		generation: make(map[string]int64),
+	}
+}
+
+// holdReads makes the next n reads wait for each other, so they observe the same generation.
+func (m *mockStorage) holdReads(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.readGate = &sync.WaitGroup{}
+	m.readGate.Add(n)
+	m.readsHeld = n
+}
+
+func (m *mockStorage) Read(ctx context.Context, key string) ([]byte, int64, error) {
+	m.mu.Lock()
+	data, gen := m.data[key], m.generation[key]
+	gate := m.readGate
+	if gate != nil {
+		m.readsHeld--
+		if m.readsHeld == 0 {
+			m.readGate = nil
+		}
+	}
+	m.mu.Unlock()
+
+	if gate != nil {
+		gate.Done()
+		gate.Wait()
+	}
+	return data, gen, nil
+}
+
+func (m *mockStorage) Write(ctx context.Context, key, mimetype string, data []byte, expectedGeneration int64) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.writeErr != nil {
+		return 0, m.writeErr
+	}
+	if m.generation[key] != expectedGeneration {
+		return 0, errors.New("generation mismatch")
+	}
+	m.data[key] = data
+	m.generation[key] = expectedGeneration + 1
+	return m.generation[key], nil
+}
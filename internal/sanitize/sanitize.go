@@ -0,0 +1,40 @@
+// Package sanitize cleans user-provided text before it is persisted.
+package sanitize
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Text removes control characters other than newline and tab, and drops invalid UTF-8 bytes.
+// Carriage returns are removed too, so CRLF line endings become LF.
+// Everything else is kept as is, including emoji and the zero-width joiners in emoji sequences,
+// which are format characters rather than control characters.
+func Text(s string) string {
+	if isClean(s) {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range strings.ToValidUTF8(s, "") {
+		if allowed(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// isClean reports whether Text would return s unchanged.
+func isClean(s string) bool {
+	for _, r := range s {
+		if r == utf8.RuneError || !allowed(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func allowed(r rune) bool {
+	return r == '\n' || r == '\t' || !unicode.IsControl(r)
+}
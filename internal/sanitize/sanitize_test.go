@@ -0,0 +1,40 @@
+package sanitize_test
+
+import (
+	"testing"
+	"yuruppu/internal/sanitize"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// =============================================================================
+// Text Tests
+// =============================================================================
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "plain text", in: "hello", want: "hello"},
+		{name: "keeps newlines and tabs", in: "a\n\tb", want: "a\n\tb"},
+		{name: "removes null bytes", in: "a\x00b", want: "ab"},
+		{name: "removes C0 controls", in: "\x01a\x07b\x1bc\x1f", want: "abc"},
+		{name: "removes DEL and C1 controls", in: "a\x7fb\u0085c\u009f", want: "abc"},
+		{name: "turns CRLF into LF", in: "a\r\nb\rc", want: "a\nbc"},
+		{name: "drops invalid UTF-8", in: "a\xffb\xc3", want: "ab"},
+		{name: "keeps Japanese", in: "お花見\x00しよう", want: "お花見しよう"},
+		{name: "keeps emoji", in: "🎉\x00🍣", want: "🎉🍣"},
+		{name: "keeps emoji ZWJ sequences", in: "👨‍👩‍👧\x0b", want: "👨‍👩‍👧"},
+		{name: "keeps emoji variation selectors and skin tones", in: "❤️👍🏽\x00", want: "❤️👍🏽"},
+		{name: "keeps replacement character", in: "a�b", want: "a�b"},
+		{name: "empty", in: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sanitize.Text(tt.in))
+		})
+	}
+}
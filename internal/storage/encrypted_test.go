@@ -0,0 +1,201 @@
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"yuruppu/internal/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testKey = bytes.Repeat([]byte{0x42}, 32)
+
+// =============================================================================
+// NewEncryptedStorage Tests
+// =============================================================================
+
+func TestNewEncryptedStorage(t *testing.T) {
+	t.Run("returns error when inner storage is nil", func(t *testing.T) {
+		s, err := storage.NewEncryptedStorage(nil, testKey)
+
+		require.Error(t, err)
+		assert.Nil(t, s)
+	})
+
+	t.Run("returns error when key has invalid length", func(t *testing.T) {
+		s, err := storage.NewEncryptedStorage(newMemoryStorage(), []byte("short"))
+
+		require.Error(t, err)
+		assert.Nil(t, s)
+		assert.Contains(t, err.Error(), "invalid encryption key")
+	})
+}
+
+// =============================================================================
+// Read/Write Tests
+// =============================================================================
+
+func TestEncryptedStorage_ReadWrite(t *testing.T) {
+	t.Run("round-trips data", func(t *testing.T) {
+		inner := newMemoryStorage()
+		s, err := storage.NewEncryptedStorage(inner, testKey)
+		require.NoError(t, err)
+		plaintext := []byte(`{"displayName":"Alice"}`)
+
+		gen, err := s.Write(t.Context(), "user-1", "application/json", plaintext, 0)
+		require.NoError(t, err)
+		got, readGen, err := s.Read(t.Context(), "user-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, plaintext, got)
+		assert.Equal(t, gen, readGen)
+	})
+
+	t.Run("stores ciphertext in the underlying storage", func(t *testing.T) {
+		inner := newMemoryStorage()
+		s, err := storage.NewEncryptedStorage(inner, testKey)
+		require.NoError(t, err)
+		plaintext := []byte(`{"displayName":"Alice"}`)
+
+		_, err = s.Write(t.Context(), "user-1", "application/json", plaintext, 0)
+		require.NoError(t, err)
+
+		stored := inner.data["user-1"]
+		require.NotEmpty(t, stored)
+		assert.NotEqual(t, plaintext, stored)
+		assert.False(t, bytes.Contains(stored, []byte("Alice")))
+		assert.Equal(t, "application/json", inner.mimetypes["user-1"])
+	})
+
+	t.Run("encrypts the same value differently each time", func(t *testing.T) {
+		inner := newMemoryStorage()
+		s, err := storage.NewEncryptedStorage(inner, testKey)
+		require.NoError(t, err)
+
+		_, err = s.Write(t.Context(), "a", "text/plain", []byte("same"), 0)
+		require.NoError(t, err)
+		_, err = s.Write(t.Context(), "b", "text/plain", []byte("same"), 0)
+		require.NoError(t, err)
+
+		assert.NotEqual(t, inner.data["a"], inner.data["b"])
+	})
+
+	t.Run("returns nil for a missing key", func(t *testing.T) {
+		s, err := storage.NewEncryptedStorage(newMemoryStorage(), testKey)
+		require.NoError(t, err)
+
+		got, gen, err := s.Read(t.Context(), "missing")
+
+		require.NoError(t, err)
+		assert.Nil(t, got)
+		assert.Equal(t, int64(0), gen)
+	})
+
+	t.Run("passes generation preconditions through", func(t *testing.T) {
+		inner := newMemoryStorage()
+		s, err := storage.NewEncryptedStorage(inner, testKey)
+		require.NoError(t, err)
+
+		gen, err := s.Write(t.Context(), "k", "text/plain", []byte("v1"), 0)
+		require.NoError(t, err)
+		_, err = s.Write(t.Context(), "k", "text/plain", []byte("v2"), gen+1)
+
+		require.ErrorIs(t, err, errPreconditionFailed)
+	})
+
+	t.Run("fails to read data encrypted with another key", func(t *testing.T) {
+		inner := newMemoryStorage()
+		s1, err := storage.NewEncryptedStorage(inner, testKey)
+		require.NoError(t, err)
+		s2, err := storage.NewEncryptedStorage(inner, bytes.Repeat([]byte{0x24}, 32))
+		require.NoError(t, err)
+
+		_, err = s1.Write(t.Context(), "k", "text/plain", []byte("secret"), 0)
+		require.NoError(t, err)
+		_, _, err = s2.Read(t.Context(), "k")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to decrypt k")
+	})
+
+	t.Run("fails to read a value moved to another key", func(t *testing.T) {
+		inner := newMemoryStorage()
+		s, err := storage.NewEncryptedStorage(inner, testKey)
+		require.NoError(t, err)
+
+		_, err = s.Write(t.Context(), "a", "text/plain", []byte("secret"), 0)
+		require.NoError(t, err)
+		inner.data["b"] = inner.data["a"]
+		_, _, err = s.Read(t.Context(), "b")
+
+		require.Error(t, err)
+	})
+
+	t.Run("returns error when stored data is too short", func(t *testing.T) {
+		inner := newMemoryStorage()
+		s, err := storage.NewEncryptedStorage(inner, testKey)
+		require.NoError(t, err)
+		inner.data["k"] = []byte("x")
+
+		_, _, err = s.Read(t.Context(), "k")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "too short")
+	})
+}
+
+// =============================================================================
+// GetSignedURL Tests
+// =============================================================================
+
+func TestEncryptedStorage_GetSignedURL(t *testing.T) {
+	s, err := storage.NewEncryptedStorage(newMemoryStorage(), testKey)
+	require.NoError(t, err)
+
+	url, err := s.GetSignedURL(t.Context(), "k", "GET", time.Minute)
+
+	require.ErrorAs(t, err, new(*storage.SignedURLUnsupportedError))
+	assert.Empty(t, url)
+}
+
+// =============================================================================
+// Mocks
+// =============================================================================
+
+var errPreconditionFailed = errors.New("precondition failed")
+
+type memoryStorage struct {
+	data        map[string][]byte
+	mimetypes   map[string]string
+	generations map[string]int64
+}
+
+func newMemoryStorage() *memoryStorage {
+	return &memoryStorage{
+		data:        make(map[string][]byte),
+		mimetypes:   make(map[string]string),
+		generations: make(map[string]int64),
+	}
+}
+
+func (m *memoryStorage) Read(_ context.Context, key string) ([]byte, int64, error) {
+	return m.data[key], m.generations[key], nil
+}
+
+func (m *memoryStorage) Write(_ context.Context, key, mimetype string, data []byte, expectedGeneration int64) (int64, error) {
+	if m.generations[key] != expectedGeneration {
+		return 0, errPreconditionFailed
+	}
+	m.data[key] = data
+	m.mimetypes[key] = mimetype
+	m.generations[key]++
+	return m.generations[key], nil
+}
+
+func (m *memoryStorage) GetSignedURL(_ context.Context, key, _ string, _ time.Duration) (string, error) {
+	return "https://example.com/" + key, nil
+}
@@ -0,0 +1,99 @@
+package displayname
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+	"yuruppu/internal/line"
+	"yuruppu/internal/userprofile"
+)
+
+//go:embed parameters.json
+var parametersSchema []byte
+
+//go:embed response.json
+var responseSchema []byte
+
+// maxDisplayNameLength is the maximum display name length in characters.
+const maxDisplayNameLength = 20
+
+// UserProfileService provides access to user profile operations.
+type UserProfileService interface {
+	UpdateUserProfile(ctx context.Context, userID string, update func(*userprofile.UserProfile)) error
+}
+
+// Tool implements the set_display_name tool for renaming the requesting user.
+type Tool struct {
+	userProfileService UserProfileService
+	logger             *slog.Logger
+}
+
+// NewTool creates a new set_display_name tool.
+func NewTool(userProfileService UserProfileService, logger *slog.Logger) (*Tool, error) {
+	if userProfileService == nil {
+		return nil, errors.New("userProfileService cannot be nil")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil")
+	}
+	return &Tool{
+		userProfileService: userProfileService,
+		logger:             logger,
+	}, nil
+}
+
+// Name returns the tool name.
+func (t *Tool) Name() string {
+	return "set_display_name"
+}
+
+// Description returns a description for the LLM.
+func (t *Tool) Description() string {
+	return "Use this tool when the user asks to change what they are called. Only the user's own display name can be changed."
+}
+
+// ParametersJsonSchema returns the JSON Schema for input parameters.
+func (t *Tool) ParametersJsonSchema() []byte {
+	return parametersSchema
+}
+
+// ResponseJsonSchema returns the JSON Schema for the response.
+func (t *Tool) ResponseJsonSchema() []byte {
+	return responseSchema
+}
+
+// Callback updates the requesting user's display name.
+func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]any, error) {
+	userID, ok := line.UserIDFromContext(ctx)
+	if !ok {
+		t.logger.ErrorContext(ctx, "user ID not found in context")
+		return nil, errors.New("internal error")
+	}
+
+	displayName, ok := args["display_name"].(string)
+	if !ok {
+		return nil, errors.New("invalid display_name")
+	}
+	displayName = strings.TrimSpace(displayName)
+	if displayName == "" {
+		return nil, errors.New("display_name must not be empty")
+	}
+	if utf8.RuneCountInString(displayName) > maxDisplayNameLength {
+		return nil, errors.New("display_name is too long")
+	}
+
+	err := t.userProfileService.UpdateUserProfile(ctx, userID, func(p *userprofile.UserProfile) {
+		p.DisplayName = displayName
+	})
+	if err != nil {
+		t.logger.ErrorContext(ctx, "failed to update display name", slog.String("userID", userID), slog.Any("error", err))
+		return nil, errors.New("failed to update display name")
+	}
+
+	return map[string]any{
+		"display_name": displayName,
+	}, nil
+}
@@ -0,0 +1,136 @@
+package displayname_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"yuruppu/internal/line"
+	"yuruppu/internal/toolset/displayname"
+	"yuruppu/internal/userprofile"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// NewTool Tests
+// =============================================================================
+
+func TestNewTool(t *testing.T) {
+	t.Run("creates tool with dependencies", func(t *testing.T) {
+		tool, err := displayname.NewTool(&mockUserProfileService{}, slog.New(slog.DiscardHandler))
+
+		require.NoError(t, err)
+		require.NotNil(t, tool)
+		assert.Equal(t, "set_display_name", tool.Name())
+	})
+
+	t.Run("returns error when userProfileService is nil", func(t *testing.T) {
+		tool, err := displayname.NewTool(nil, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "userProfileService cannot be nil")
+	})
+
+	t.Run("returns error when logger is nil", func(t *testing.T) {
+		tool, err := displayname.NewTool(&mockUserProfileService{}, nil)
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "logger cannot be nil")
+	})
+}
+
+// =============================================================================
+// Callback Tests
+// =============================================================================
+
+func TestTool_Callback(t *testing.T) {
+	t.Run("renames the requesting user", func(t *testing.T) {
+		svc := &mockUserProfileService{profile: &userprofile.UserProfile{DisplayName: "Guest", StatusMessage: "Hi"}}
+		tool, err := displayname.NewTool(svc, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := line.WithUserID(t.Context(), "user-123")
+		result, err := tool.Callback(ctx, map[string]any{"display_name": "  Alice  "})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"display_name": "Alice"}, result)
+		assert.Equal(t, "user-123", svc.lastUserID)
+		assert.Equal(t, "Alice", svc.profile.DisplayName)
+		assert.Equal(t, "Hi", svc.profile.StatusMessage)
+	})
+
+	t.Run("rejects empty name", func(t *testing.T) {
+		svc := &mockUserProfileService{profile: &userprofile.UserProfile{DisplayName: "Guest"}}
+		tool, err := displayname.NewTool(svc, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := line.WithUserID(t.Context(), "user-123")
+		result, err := tool.Callback(ctx, map[string]any{"display_name": "   "})
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+		assert.Equal(t, "display_name must not be empty", err.Error())
+		assert.Equal(t, 0, svc.updateCount)
+	})
+
+	t.Run("rejects name that is too long", func(t *testing.T) {
+		svc := &mockUserProfileService{profile: &userprofile.UserProfile{DisplayName: "Guest"}}
+		tool, err := displayname.NewTool(svc, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := line.WithUserID(t.Context(), "user-123")
+		_, err = tool.Callback(ctx, map[string]any{"display_name": strings.Repeat("あ", 21)})
+
+		require.Error(t, err)
+		assert.Equal(t, "display_name is too long", err.Error())
+		assert.Equal(t, 0, svc.updateCount)
+	})
+
+	t.Run("returns error when update fails", func(t *testing.T) {
+		svc := &mockUserProfileService{updateErr: errors.New("generation mismatch")}
+		tool, err := displayname.NewTool(svc, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := line.WithUserID(t.Context(), "user-123")
+		_, err = tool.Callback(ctx, map[string]any{"display_name": "Alice"})
+
+		require.Error(t, err)
+		assert.Equal(t, "failed to update display name", err.Error())
+	})
+
+	t.Run("returns internal error when user ID is missing", func(t *testing.T) {
+		tool, err := displayname.NewTool(&mockUserProfileService{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		_, err = tool.Callback(t.Context(), map[string]any{"display_name": "Alice"})
+
+		require.Error(t, err)
+		assert.Equal(t, "internal error", err.Error())
+	})
+}
+
+// =============================================================================
+// Mocks
+// =============================================================================
+
+type mockUserProfileService struct {
+	profile     *userprofile.UserProfile
+	updateErr   error
+	updateCount int
+	lastUserID  string
+}
+
+func (m *mockUserProfileService) UpdateUserProfile(ctx context.Context, userID string, update func(*userprofile.UserProfile)) error {
+	m.updateCount++
+	m.lastUserID = userID
+	if m.updateErr != nil {
+		return m.updateErr
+	}
+	update(m.profile)
+	return nil
+}
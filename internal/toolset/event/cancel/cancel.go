@@ -0,0 +1,170 @@
+package cancel
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"log/slog"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/clock"
+	"yuruppu/internal/event"
+	"yuruppu/internal/line"
+	"yuruppu/internal/userprofile"
+)
+
+//go:embed parameters.json
+var parametersSchema []byte
+
+//go:embed response.json
+var responseSchema []byte
+
+// EventService provides access to event operations.
+type EventService interface {
+	Get(ctx context.Context, chatRoomID string) (*event.Event, error)
+	RemoveAttendee(ctx context.Context, chatRoomID, userID string) (string, error)
+}
+
+// Notifier pushes a text message to a user.
+type Notifier interface {
+	SendPush(to string, text string) error
+}
+
+// UserProfileService provides access to user profile operations.
+type UserProfileService interface {
+	GetUserProfiles(ctx context.Context, userIDs []string) (map[string]*userprofile.UserProfile, error)
+}
+
+// Tool implements the cancel_rsvp tool for withdrawing from an event.
+type Tool struct {
+	eventService       EventService
+	notifier           Notifier
+	userProfileService UserProfileService
+	logger             *slog.Logger
+}
+
+// New creates a new cancel_rsvp tool.
+// notifier and userProfileService are used to tell a waitlisted user who takes the freed spot.
+func New(eventService EventService, notifier Notifier, userProfileService UserProfileService, logger *slog.Logger) (*Tool, error) {
+	if eventService == nil {
+		return nil, errors.New("eventService cannot be nil")
+	}
+	if notifier == nil {
+		return nil, errors.New("notifier cannot be nil")
+	}
+	if userProfileService == nil {
+		return nil, errors.New("userProfileService cannot be nil")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil")
+	}
+	return &Tool{
+		eventService:       eventService,
+		notifier:           notifier,
+		userProfileService: userProfileService,
+		logger:             logger,
+	}, nil
+}
+
+// Name returns the tool name.
+func (t *Tool) Name() string {
+	return "cancel_rsvp"
+}
+
+// Description returns a description for the LLM.
+func (t *Tool) Description() string {
+	return "Use this tool when the user says they can no longer attend an event. Removes the user from the attendees or waitlist. If a waitlisted user takes the freed spot, they are told by direct message unless their notification settings forbid it; their user ID is returned as promoted_user and promoted_notified tells whether the message was sent."
+}
+
+// ParametersJsonSchema returns the JSON Schema for input parameters.
+func (t *Tool) ParametersJsonSchema() []byte {
+	return parametersSchema
+}
+
+// ResponseJsonSchema returns the JSON Schema for the response.
+func (t *Tool) ResponseJsonSchema() []byte {
+	return responseSchema
+}
+
+// Callback withdraws the requesting user from an event.
+func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]any, error) {
+	chatRoomID, ok := line.SourceIDFromContext(ctx)
+	if !ok {
+		t.logger.ErrorContext(ctx, "source ID not found in context")
+		return nil, agent.NewSystemError("internal error", nil)
+	}
+	if chatRoomIDArg, ok := args["chat_room_id"]; ok {
+		chatRoomID, ok = chatRoomIDArg.(string)
+		if !ok || chatRoomID == "" {
+			return agent.Invalid("invalid chat_room_id"), nil
+		}
+	}
+
+	userID, ok := line.UserIDFromContext(ctx)
+	if !ok {
+		t.logger.ErrorContext(ctx, "user ID not found in context")
+		return nil, agent.NewSystemError("internal error", nil)
+	}
+
+	promoted, err := t.eventService.RemoveAttendee(ctx, chatRoomID, userID)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*event.NotFoundError)):
+			return map[string]any{"status": "not_found"}, nil
+		case errors.As(err, new(*event.NotAttendingError)):
+			return map[string]any{"status": "not_attending"}, nil
+		}
+		t.logger.ErrorContext(ctx, "failed to remove attendee",
+			slog.String("chatRoomID", chatRoomID),
+			slog.String("userID", userID),
+			slog.Any("error", err),
+		)
+		return nil, agent.NewSystemError("failed to cancel RSVP", err)
+	}
+
+	result := map[string]any{"status": "ok"}
+	if promoted != "" {
+		result["promoted_user"] = promoted
+		result["promoted_notified"] = t.notifyPromoted(ctx, chatRoomID, promoted)
+	}
+	return result, nil
+}
+
+// notifyPromoted tells userID that they moved from the waitlist to the attendees of the event in chatRoomID.
+// The message is skipped if the user's notification preferences do not accept it now.
+// Failures are logged and never affect the cancellation. Returns whether the message was sent.
+func (t *Tool) notifyPromoted(ctx context.Context, chatRoomID, userID string) bool {
+	profiles, err := t.userProfileService.GetUserProfiles(ctx, []string{userID})
+	if err != nil {
+		t.logger.WarnContext(ctx, "failed to get promoted user profile",
+			slog.String("userID", userID),
+			slog.Any("error", err),
+		)
+		return false
+	}
+	if profile, ok := profiles[userID]; ok && !profile.AcceptsNotificationAt(clock.Now(ctx)) {
+		t.logger.DebugContext(ctx, "promotion notification suppressed by user preferences",
+			slog.String("userID", userID),
+		)
+		return false
+	}
+
+	ev, err := t.eventService.Get(ctx, chatRoomID)
+	if err != nil {
+		t.logger.WarnContext(ctx, "failed to get event for promotion notification",
+			slog.String("chatRoomID", chatRoomID),
+			slog.Any("error", err),
+		)
+		return false
+	}
+
+	text := fmt.Sprintf("キャンセル待ちだった「%s」、空きが出たので参加確定になったよ！", ev.Title)
+	if err := t.notifier.SendPush(userID, text); err != nil {
+		t.logger.WarnContext(ctx, "failed to push promotion notification",
+			slog.String("userID", userID),
+			slog.Any("error", err),
+		)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,329 @@
+package cancel_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+	"yuruppu/internal/clock"
+	"yuruppu/internal/event"
+	"yuruppu/internal/line"
+	"yuruppu/internal/toolset/event/cancel"
+	"yuruppu/internal/userprofile"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// New() Tests
+// =============================================================================
+
+func TestNew(t *testing.T) {
+	t.Run("creates tool with valid service", func(t *testing.T) {
+		tool, err := cancel.New(&mockEventService{}, &mockNotifier{}, &mockUserProfileService{}, slog.New(slog.DiscardHandler))
+
+		require.NoError(t, err)
+		require.NotNil(t, tool)
+		assert.Equal(t, "cancel_rsvp", tool.Name())
+	})
+
+	t.Run("returns error when service is nil", func(t *testing.T) {
+		tool, err := cancel.New(nil, &mockNotifier{}, &mockUserProfileService{}, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "eventService cannot be nil")
+	})
+
+	t.Run("returns error when notifier is nil", func(t *testing.T) {
+		tool, err := cancel.New(&mockEventService{}, nil, &mockUserProfileService{}, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "notifier cannot be nil")
+	})
+
+	t.Run("returns error when userProfileService is nil", func(t *testing.T) {
+		tool, err := cancel.New(&mockEventService{}, &mockNotifier{}, nil, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "userProfileService cannot be nil")
+	})
+
+	t.Run("returns error when logger is nil", func(t *testing.T) {
+		tool, err := cancel.New(&mockEventService{}, &mockNotifier{}, &mockUserProfileService{}, nil)
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "logger cannot be nil")
+	})
+}
+
+// =============================================================================
+// Callback() Tests
+// =============================================================================
+
+func withContext(ctx context.Context) context.Context {
+	ctx = line.WithSourceID(ctx, "group-123")
+	return line.WithUserID(ctx, "user-1")
+}
+
+func TestTool_Callback(t *testing.T) {
+	t.Run("withdraws the requesting user", func(t *testing.T) {
+		service := &mockEventService{}
+		tool, err := cancel.New(service, &mockNotifier{}, &mockUserProfileService{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(withContext(t.Context()), map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, "group-123", service.lastChatRoomID)
+		assert.Equal(t, "user-1", service.lastUserID)
+		assert.Equal(t, map[string]any{"status": "ok"}, result)
+	})
+
+	t.Run("returns promoted user when a waitlisted user takes the spot", func(t *testing.T) {
+		service := &mockEventService{promoted: "user-9", getEvent: &event.Event{Title: "Board games"}}
+		tool, err := cancel.New(service, &mockNotifier{}, &mockUserProfileService{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(withContext(t.Context()), map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"status": "ok", "promoted_user": "user-9", "promoted_notified": true}, result)
+	})
+
+	t.Run("returns not_attending when user was not attending", func(t *testing.T) {
+		service := &mockEventService{err: &event.NotAttendingError{ChatRoomID: "group-123", UserID: "user-1"}}
+		tool, err := cancel.New(service, &mockNotifier{}, &mockUserProfileService{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(withContext(t.Context()), map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"status": "not_attending"}, result)
+	})
+
+	t.Run("returns not_found for missing event", func(t *testing.T) {
+		service := &mockEventService{err: &event.NotFoundError{ChatRoomID: "group-123"}}
+		tool, err := cancel.New(service, &mockNotifier{}, &mockUserProfileService{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(withContext(t.Context()), map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"status": "not_found"}, result)
+	})
+
+	t.Run("uses chat_room_id argument when provided", func(t *testing.T) {
+		service := &mockEventService{}
+		tool, err := cancel.New(service, &mockNotifier{}, &mockUserProfileService{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		_, err = tool.Callback(withContext(t.Context()), map[string]any{"chat_room_id": "group-456"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "group-456", service.lastChatRoomID)
+	})
+
+	t.Run("returns error when storage fails", func(t *testing.T) {
+		service := &mockEventService{err: errors.New("storage error")}
+		tool, err := cancel.New(service, &mockNotifier{}, &mockUserProfileService{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(withContext(t.Context()), map[string]any{})
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+		assert.Equal(t, "failed to cancel RSVP", err.Error())
+	})
+
+	t.Run("returns internal error when user ID is missing", func(t *testing.T) {
+		tool, err := cancel.New(&mockEventService{}, &mockNotifier{}, &mockUserProfileService{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := line.WithSourceID(t.Context(), "group-123")
+		result, err := tool.Callback(ctx, map[string]any{})
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+		assert.Equal(t, "internal error", err.Error())
+	})
+}
+
+// =============================================================================
+// Promotion Notification Tests
+// =============================================================================
+
+func TestTool_Callback_PromotionNotification(t *testing.T) {
+	t.Run("pushes the event title to the promoted user only", func(t *testing.T) {
+		service := &mockEventService{promoted: "user-9", getEvent: &event.Event{Title: "Board games"}}
+		notifier := &mockNotifier{}
+		tool, err := cancel.New(service, notifier, &mockUserProfileService{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		_, err = tool.Callback(withContext(t.Context()), map[string]any{})
+
+		require.NoError(t, err)
+		require.Len(t, notifier.pushes, 1)
+		assert.Equal(t, "user-9", notifier.pushes[0].to)
+		assert.Contains(t, notifier.pushes[0].text, "Board games")
+		assert.Equal(t, "group-123", service.lastGetChatRoomID)
+	})
+
+	t.Run("does not push when nobody is promoted", func(t *testing.T) {
+		notifier := &mockNotifier{}
+		tool, err := cancel.New(&mockEventService{}, notifier, &mockUserProfileService{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		_, err = tool.Callback(withContext(t.Context()), map[string]any{})
+
+		require.NoError(t, err)
+		assert.Empty(t, notifier.pushes)
+	})
+
+	t.Run("respects disabled notifications", func(t *testing.T) {
+		service := &mockEventService{promoted: "user-9", getEvent: &event.Event{Title: "Board games"}}
+		notifier := &mockNotifier{}
+		profiles := &mockUserProfileService{profiles: map[string]*userprofile.UserProfile{
+			"user-9": {Notifications: userprofile.NotificationPrefs{Disabled: true}},
+		}}
+		tool, err := cancel.New(service, notifier, profiles, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(withContext(t.Context()), map[string]any{})
+
+		require.NoError(t, err)
+		assert.Empty(t, notifier.pushes)
+		assert.Equal(t, false, result["promoted_notified"])
+		assert.Equal(t, "user-9", result["promoted_user"])
+	})
+
+	t.Run("respects quiet hours", func(t *testing.T) {
+		service := &mockEventService{promoted: "user-9", getEvent: &event.Event{Title: "Board games"}}
+		notifier := &mockNotifier{}
+		profiles := &mockUserProfileService{profiles: map[string]*userprofile.UserProfile{
+			"user-9": {Timezone: "UTC", Notifications: userprofile.NotificationPrefs{QuietHoursStart: 22, QuietHoursEnd: 7}},
+		}}
+		tool, err := cancel.New(service, notifier, profiles, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := clock.WithNow(withContext(t.Context()), time.Date(2026, 3, 1, 23, 30, 0, 0, time.UTC))
+		result, err := tool.Callback(ctx, map[string]any{})
+
+		require.NoError(t, err)
+		assert.Empty(t, notifier.pushes)
+		assert.Equal(t, false, result["promoted_notified"])
+	})
+
+	t.Run("pushes outside quiet hours", func(t *testing.T) {
+		service := &mockEventService{promoted: "user-9", getEvent: &event.Event{Title: "Board games"}}
+		notifier := &mockNotifier{}
+		profiles := &mockUserProfileService{profiles: map[string]*userprofile.UserProfile{
+			"user-9": {Timezone: "UTC", Notifications: userprofile.NotificationPrefs{QuietHoursStart: 22, QuietHoursEnd: 7}},
+		}}
+		tool, err := cancel.New(service, notifier, profiles, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := clock.WithNow(withContext(t.Context()), time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+		_, err = tool.Callback(ctx, map[string]any{})
+
+		require.NoError(t, err)
+		require.Len(t, notifier.pushes, 1)
+	})
+
+	t.Run("keeps the cancellation when the push fails", func(t *testing.T) {
+		service := &mockEventService{promoted: "user-9", getEvent: &event.Event{Title: "Board games"}}
+		notifier := &mockNotifier{err: errors.New("push failed")}
+		tool, err := cancel.New(service, notifier, &mockUserProfileService{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(withContext(t.Context()), map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"status": "ok", "promoted_user": "user-9", "promoted_notified": false}, result)
+	})
+
+	t.Run("does not push when the event cannot be read", func(t *testing.T) {
+		service := &mockEventService{promoted: "user-9", getErr: errors.New("storage error")}
+		notifier := &mockNotifier{}
+		tool, err := cancel.New(service, notifier, &mockUserProfileService{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(withContext(t.Context()), map[string]any{})
+
+		require.NoError(t, err)
+		assert.Empty(t, notifier.pushes)
+		assert.Equal(t, false, result["promoted_notified"])
+	})
+}
+
+// =============================================================================
+// Mocks
+// =============================================================================
+
+type mockEventService struct {
+	promoted          string
+	err               error
+	lastChatRoomID    string
+	lastUserID        string
+	getEvent          *event.Event
+	getErr            error
+	lastGetChatRoomID string
+}
+
+func (m *mockEventService) Get(ctx context.Context, chatRoomID string) (*event.Event, error) {
+	m.lastGetChatRoomID = chatRoomID
+	if m.getErr != nil {
+		return nil, m.getErr
+	}
+	if m.getEvent == nil {
+		return nil, &event.NotFoundError{ChatRoomID: chatRoomID}
+	}
+	return m.getEvent, nil
+}
+
+func (m *mockEventService) RemoveAttendee(ctx context.Context, chatRoomID, userID string) (string, error) {
+	m.lastChatRoomID = chatRoomID
+	m.lastUserID = userID
+	return m.promoted, m.err
+}
+
+type push struct {
+	to   string
+	text string
+}
+
+type mockNotifier struct {
+	pushes []push
+	err    error
+}
+
+func (m *mockNotifier) SendPush(to string, text string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.pushes = append(m.pushes, push{to: to, text: text})
+	return nil
+}
+
+type mockUserProfileService struct {
+	profiles map[string]*userprofile.UserProfile
+	err      error
+}
+
+func (m *mockUserProfileService) GetUserProfiles(ctx context.Context, userIDs []string) (map[string]*userprofile.UserProfile, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	result := make(map[string]*userprofile.UserProfile)
+	for _, id := range userIDs {
+		if p, ok := m.profiles[id]; ok {
+			result[id] = p
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,364 @@
+// Package card renders events as a LINE Flex Message carousel.
+package card
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"text/template"
+	"time"
+	"yuruppu/internal/event"
+	"yuruppu/internal/line"
+	"yuruppu/internal/userprofile"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot/messaging_api"
+)
+
+// flexTemplate is the built-in template, used when no valid custom template is configured.
+//
+//go:embed flex.json
+var flexTemplate string
+
+// JST is Japan Standard Time location (UTC+9).
+var JST = time.FixedZone("Asia/Tokyo", 9*60*60)
+
+// flexEventData represents template data for a single event in flex message.
+type flexEventData struct {
+	Title       string
+	StartTime   string
+	EndTime     string
+	Fee         string
+	Capacity    int
+	Description string
+	ShowCreator bool
+	CreatorName string
+	ImageURL    string
+	MapsURL     string // empty when the event has no location
+	JoinData    string // postback data of the join button; empty unless the event belongs to the chat the cards are shown in
+	GroupName   string // JSON-escaped; empty unless the cards span several groups
+	Comments    []flexCommentData
+}
+
+// flexCommentData represents template data for a comment shown on an event card.
+// AuthorName and Text are JSON-escaped, since comments are free text that often contains quotes.
+type flexCommentData struct {
+	AuthorName string // empty when the author's profile is unavailable
+	Text       string
+	Time       string
+}
+
+// joinPostbackData is the postback data of the join button, before its nonce is added.
+// The agent sees it as the user's message and signs them up with join_event.
+const joinPostbackData = "action=join_event"
+
+// maxCardComments is how many of an event's latest comments its card shows.
+const maxCardComments = 3
+
+// MaxCarouselSize is the most bubbles LINE accepts in one carousel.
+const MaxCarouselSize = 12
+
+// UserProfileService provides user profile operations.
+type UserProfileService interface {
+	GetUserProfiles(ctx context.Context, userIDs []string) (map[string]*userprofile.UserProfile, error)
+}
+
+// Option configures optional Renderer behavior.
+type Option func(*options)
+
+type options struct {
+	templateText string
+	carouselSize int
+}
+
+// WithTemplate renders events with a custom text/template instead of the built-in one.
+// The template receives a slice of events with the fields Title, StartTime, EndTime, Fee,
+// Capacity, Description, ShowCreator, CreatorName, ImageURL (empty when the event has no cover image),
+// MapsURL (a URL-encoded Google Maps link, empty when the event has no location),
+// JoinData (the postback data of a join button, sealed with a nonce, empty unless the event belongs to the chat the cards are shown in),
+// GroupName (the JSON-escaped name of the event's group, empty unless the cards span several groups),
+// and Comments (the latest few, oldest first, each with JSON-escaped AuthorName and Text, and Time),
+// and must produce a Flex container (a bubble or carousel) for any number of events, including none.
+// An invalid template is logged and the built-in template is used instead.
+func WithTemplate(text string) Option {
+	return func(o *options) {
+		o.templateText = text
+	}
+}
+
+// WithCarouselSize caps how many events one carousel shows; it must be between 1 and MaxCarouselSize.
+// Defaults to MaxCarouselSize.
+func WithCarouselSize(n int) Option {
+	return func(o *options) {
+		o.carouselSize = n
+	}
+}
+
+// sampleEvents is rendered when checking a custom template.
+var sampleEvents = []flexEventData{
+	{
+		Title:       "Sample event",
+		StartTime:   "2025/01/01 10:00",
+		EndTime:     "2025/01/01 12:00",
+		Fee:         "1000円",
+		Capacity:    10,
+		Description: "Sample description",
+		ShowCreator: true,
+		CreatorName: "Sample User",
+		ImageURL:    "https://example.com/sample.jpg",
+		MapsURL:     "https://www.google.com/maps/search/?api=1&query=Shibuya+Station",
+		JoinData:    joinPostbackData + "&nonce=0123456789abcdef0123456789abcdef",
+		GroupName:   `Sample \"group\"`,
+		Comments: []flexCommentData{
+			{AuthorName: "Sample User", Text: `Is there \"parking\"?`, Time: "2024/12/31 18:00"},
+			{Text: "See you there", Time: "2024/12/31 19:00"},
+		},
+	},
+	{
+		Title:     "Another event",
+		StartTime: "2025/01/02 10:00",
+		EndTime:   "2025/01/02 12:00",
+	},
+}
+
+// Renderer renders events as a Flex Message carousel.
+type Renderer struct {
+	userProfileService UserProfileService
+	template           *template.Template // custom template, or builtin
+	builtin            *template.Template
+	carouselSize       int
+	logger             *slog.Logger
+}
+
+// NewRenderer creates a new Renderer.
+func NewRenderer(userProfileService UserProfileService, logger *slog.Logger, opts ...Option) (*Renderer, error) {
+	if userProfileService == nil {
+		return nil, errors.New("userProfileService cannot be nil")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil")
+	}
+	o := options{carouselSize: MaxCarouselSize}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.carouselSize < 1 || o.carouselSize > MaxCarouselSize {
+		return nil, fmt.Errorf("carousel size must be between 1 and %d: %d", MaxCarouselSize, o.carouselSize)
+	}
+
+	builtin, err := template.New("flex").Parse(flexTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse flex template: %w", err)
+	}
+	tmpl := builtin
+	if o.templateText != "" {
+		custom, err := parseCustomTemplate(o.templateText)
+		if err != nil {
+			logger.Warn("invalid event card template, using the built-in template", slog.Any("error", err))
+		} else {
+			tmpl = custom
+		}
+	}
+	return &Renderer{
+		userProfileService: userProfileService,
+		template:           tmpl,
+		builtin:            builtin,
+		carouselSize:       o.carouselSize,
+		logger:             logger,
+	}, nil
+}
+
+// CarouselSize returns the most events one rendered carousel shows.
+func (r *Renderer) CarouselSize() int {
+	return r.carouselSize
+}
+
+// parseCustomTemplate parses text and checks that it renders valid Flex JSON for sample data.
+func parseCustomTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("flex").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	for _, data := range [][]flexEventData{sampleEvents, nil} {
+		if _, err := execute(tmpl, data); err != nil {
+			return nil, fmt.Errorf("failed to render %d sample events: %w", len(data), err)
+		}
+	}
+	return tmpl, nil
+}
+
+// Render returns the Flex Message JSON for events.
+// Names of creators of events with ShowCreator set and of the authors of the comments shown are resolved in one batch;
+// creators are hidden and comments shown without names if the lookup fails.
+// Events of the chat in ctx get a join button whose postback is processed at most once per user.
+// Only the first CarouselSize events are shown; callers should fetch no more than that.
+func (r *Renderer) Render(ctx context.Context, events []*event.Event) ([]byte, error) {
+	return r.RenderWithGroupNames(ctx, events, nil)
+}
+
+// RenderWithGroupNames is like Render but labels each card with the name of the event's group,
+// looked up in groupNames by ChatRoomID. Events without an entry are shown unlabeled.
+func (r *Renderer) RenderWithGroupNames(ctx context.Context, events []*event.Event, groupNames map[string]string) ([]byte, error) {
+	if len(events) > r.carouselSize {
+		r.logger.WarnContext(ctx, "too many events for one carousel, dropping the rest",
+			slog.Int("count", len(events)),
+			slog.Int("carouselSize", r.carouselSize),
+		)
+		events = events[:r.carouselSize]
+	}
+	profiles := r.userProfiles(ctx, events)
+	sourceID, _ := line.SourceIDFromContext(ctx)
+
+	eventDataList := make([]flexEventData, len(events))
+	for i, ev := range events {
+		eventData := flexEventData{
+			Title:       ev.Title,
+			StartTime:   formatEventTime(ev, ev.StartTime),
+			EndTime:     formatEventTime(ev, ev.EndTime),
+			Fee:         ev.Fee,
+			Capacity:    ev.Capacity,
+			Description: ev.Description,
+			ShowCreator: ev.ShowCreator,
+			ImageURL:    ev.ImageURL,
+			MapsURL:     mapsURL(ev),
+			GroupName:   jsonEscape(groupNames[ev.ChatRoomID]),
+		}
+		if sourceID != "" && ev.ChatRoomID == sourceID {
+			joinData, err := line.AddPostbackNonce(joinPostbackData)
+			if err != nil {
+				return nil, err
+			}
+			eventData.JoinData = joinData
+		}
+
+		for _, c := range latestComments(ev) {
+			comment := flexCommentData{
+				Text: jsonEscape(c.Text),
+				Time: formatEventTime(ev, c.CreatedAt),
+			}
+			if profile, ok := profiles[c.UserID]; ok {
+				comment.AuthorName = jsonEscape(profile.DisplayName)
+			}
+			eventData.Comments = append(eventData.Comments, comment)
+		}
+
+		if ev.ShowCreator {
+			if profile, ok := profiles[ev.CreatorID]; ok {
+				eventData.CreatorName = profile.DisplayName
+			} else {
+				eventData.ShowCreator = false
+			}
+		}
+
+		eventDataList[i] = eventData
+	}
+
+	flexJSON, err := execute(r.template, eventDataList)
+	if err != nil && r.template != r.builtin {
+		r.logger.WarnContext(ctx, "event card template produced invalid flex message, using the built-in template",
+			slog.Any("error", err),
+		)
+		flexJSON, err = execute(r.builtin, eventDataList)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return flexJSON, nil
+}
+
+// execute renders tmpl with data and validates the result as a Flex container.
+func execute(tmpl *template.Template, data []flexEventData) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to execute flex template: %w", err)
+	}
+	if err := validateFlex(buf.Bytes()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// validateFlex checks that flexJSON is a bubble or carousel the LINE API can accept.
+func validateFlex(flexJSON []byte) error {
+	if !json.Valid(flexJSON) {
+		return errors.New("flex message is not valid JSON")
+	}
+	container, err := messaging_api.UnmarshalFlexContainer(flexJSON)
+	if err != nil {
+		return fmt.Errorf("invalid flex container: %w", err)
+	}
+	if unknown, ok := container.(messaging_api.UnknownFlexContainer); ok {
+		return fmt.Errorf("unsupported flex container type: %q", unknown.Type)
+	}
+	return nil
+}
+
+// latestComments returns the comments shown on ev's card, oldest first.
+func latestComments(ev *event.Event) []event.EventComment {
+	return ev.Comments[max(0, len(ev.Comments)-maxCardComments):]
+}
+
+// mapsURL returns a Google Maps link to ev's location, preferring its coordinates over its place name,
+// or "" if it has neither. The query is URL-encoded, so the link is also safe inside a JSON string.
+func mapsURL(ev *event.Event) string {
+	var query string
+	switch {
+	case ev.Coordinates != nil:
+		query = strconv.FormatFloat(ev.Coordinates.Latitude, 'f', -1, 64) + "," + strconv.FormatFloat(ev.Coordinates.Longitude, 'f', -1, 64)
+	case ev.LocationName != "":
+		query = ev.LocationName
+	default:
+		return ""
+	}
+	return "https://www.google.com/maps/search/?" + url.Values{"api": {"1"}, "query": {query}}.Encode()
+}
+
+// jsonEscape escapes s for use inside a JSON string literal.
+func jsonEscape(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b[1 : len(b)-1])
+}
+
+// userProfiles loads the profiles of creators and comment authors shown on the cards.
+// It returns nil if the lookup fails so that every creator is hidden.
+func (r *Renderer) userProfiles(ctx context.Context, events []*event.Event) map[string]*userprofile.UserProfile {
+	var userIDs []string
+	for _, ev := range events {
+		if ev.ShowCreator {
+			userIDs = append(userIDs, ev.CreatorID)
+		}
+		for _, c := range latestComments(ev) {
+			userIDs = append(userIDs, c.UserID)
+		}
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	profiles, err := r.userProfileService.GetUserProfiles(ctx, userIDs)
+	if err != nil {
+		r.logger.WarnContext(ctx, "failed to get user profiles, hiding creators", slog.Any("error", err))
+		return nil
+	}
+	return profiles
+}
+
+// FormatDisplayTime formats a time for display in flex message.
+// Format: "2006/01/02 15:04" in JST.
+func FormatDisplayTime(t time.Time) string {
+	return t.In(JST).Format("2006/01/02 15:04")
+}
+
+// formatEventTime formats t for display in ev's timezone.
+// Times of events outside the default timezone carry the zone abbreviation so they are not mistaken for JST.
+func formatEventTime(ev *event.Event, t time.Time) string {
+	if ev.Timezone == "" || ev.Timezone == event.DefaultTimezone {
+		return FormatDisplayTime(t)
+	}
+	return t.In(ev.Location()).Format("2006/01/02 15:04 MST")
+}
@@ -0,0 +1,424 @@
+package card_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+	"yuruppu/internal/event"
+	"yuruppu/internal/line"
+	"yuruppu/internal/toolset/event/card"
+	"yuruppu/internal/userprofile"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// customTemplate renders one minimal bubble per event.
+const customTemplate = `{"type": "carousel", "contents": [
+{{- range $i, $e := . }}{{if $i}},{{end}}
+  {"type": "bubble", "body": {"type": "box", "layout": "vertical", "contents": [
+    {"type": "text", "text": "{{$e.Title}} @ {{$e.StartTime}}"},
+    {"type": "text", "text": "{{if $e.ShowCreator}}{{$e.CreatorName}}{{else}}-{{end}}"}
+  ]}}
+{{- end }}]}`
+
+// =============================================================================
+// NewRenderer Tests
+// =============================================================================
+
+func TestNewRenderer_CarouselSize(t *testing.T) {
+	t.Run("defaults to LINE's limit", func(t *testing.T) {
+		r := newRenderer(t, nil)
+
+		assert.Equal(t, card.MaxCarouselSize, r.CarouselSize())
+	})
+
+	t.Run("accepts a size up to LINE's limit", func(t *testing.T) {
+		r := newRenderer(t, nil, card.WithCarouselSize(card.MaxCarouselSize))
+
+		assert.Equal(t, card.MaxCarouselSize, r.CarouselSize())
+	})
+
+	for _, size := range []int{0, -1, card.MaxCarouselSize + 1} {
+		t.Run(fmt.Sprintf("rejects size %d", size), func(t *testing.T) {
+			r, err := card.NewRenderer(&mockUserProfileService{}, slog.New(slog.DiscardHandler), card.WithCarouselSize(size))
+
+			require.Error(t, err)
+			assert.Nil(t, r)
+			assert.Contains(t, err.Error(), "carousel size must be between 1 and 12")
+		})
+	}
+}
+
+// =============================================================================
+// Render Tests
+// =============================================================================
+
+func TestRenderer_Render(t *testing.T) {
+	events := []*event.Event{
+		{
+			Title:       "Team Meeting",
+			CreatorID:   "user-1",
+			StartTime:   time.Date(2025, 1, 15, 1, 0, 0, 0, time.UTC),
+			EndTime:     time.Date(2025, 1, 15, 3, 0, 0, 0, time.UTC),
+			Fee:         "500円",
+			Capacity:    8,
+			Description: "Weekly sync",
+			ShowCreator: true,
+		},
+	}
+
+	t.Run("renders with the built-in template", func(t *testing.T) {
+		r := newRenderer(t, nil)
+
+		flexJSON, err := r.Render(context.Background(), events)
+
+		require.NoError(t, err)
+		require.True(t, json.Valid(flexJSON))
+		s := string(flexJSON)
+		assert.Contains(t, s, `"type": "carousel"`)
+		assert.Contains(t, s, `"text": "Team Meeting"`)
+		assert.Contains(t, s, `"text": "by Alice"`)
+		assert.Contains(t, s, "2025/01/15 10:00")
+		assert.Contains(t, s, "8名")
+	})
+
+	t.Run("renders times in the event timezone", func(t *testing.T) {
+		r := newRenderer(t, nil)
+		inNewYork := *events[0]
+		inNewYork.Timezone = "America/New_York"
+
+		flexJSON, err := r.Render(context.Background(), []*event.Event{&inNewYork})
+
+		require.NoError(t, err)
+		assert.Contains(t, string(flexJSON), "2025/01/14 20:00 EST")
+		assert.NotContains(t, string(flexJSON), "2025/01/15 10:00")
+	})
+
+	t.Run("renders a hero image only for events with an image", func(t *testing.T) {
+		r := newRenderer(t, nil)
+		withImage := *events[0]
+		withImage.Title = "Picnic"
+		withImage.ImageURL = "https://example.com/picnic.jpg"
+
+		flexJSON, err := r.Render(context.Background(), []*event.Event{events[0], &withImage})
+
+		require.NoError(t, err)
+		var got struct {
+			Contents []struct {
+				Hero *struct {
+					Type string `json:"type"`
+					URL  string `json:"url"`
+				} `json:"hero"`
+			} `json:"contents"`
+		}
+		require.NoError(t, json.Unmarshal(flexJSON, &got))
+		require.Len(t, got.Contents, 2)
+		assert.Nil(t, got.Contents[0].Hero, "events without an image render as before")
+		require.NotNil(t, got.Contents[1].Hero)
+		assert.Equal(t, "image", got.Contents[1].Hero.Type)
+		assert.Equal(t, "https://example.com/picnic.jpg", got.Contents[1].Hero.URL)
+	})
+
+	t.Run("renders the latest comments", func(t *testing.T) {
+		r := newRenderer(t, nil)
+		withComments := *events[0]
+		withComments.Comments = []event.EventComment{
+			{UserID: "user-2", Text: "first", CreatedAt: time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)},
+			{UserID: "user-2", Text: "second", CreatedAt: time.Date(2025, 1, 11, 0, 0, 0, 0, time.UTC)},
+			{UserID: "user-1", Text: `Bring "snacks"` + "\nplease", CreatedAt: time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC)},
+			{UserID: "user-2", Text: "fourth", CreatedAt: time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC)},
+		}
+
+		flexJSON, err := r.Render(context.Background(), []*event.Event{&withComments})
+
+		require.NoError(t, err)
+		require.True(t, json.Valid(flexJSON))
+		var got struct {
+			Contents []struct {
+				Body struct {
+					Contents []struct {
+						Text string `json:"text"`
+					} `json:"contents"`
+				} `json:"body"`
+			} `json:"contents"`
+		}
+		require.NoError(t, json.Unmarshal(flexJSON, &got))
+		require.Len(t, got.Contents, 1)
+		var texts []string
+		for _, c := range got.Contents[0].Body.Contents {
+			texts = append(texts, c.Text)
+		}
+		assert.NotContains(t, texts, "？？？: first", "only the latest comments are shown")
+		assert.Equal(t, []string{"コメント", "？？？: second", "Alice: Bring \"snacks\"\nplease", "？？？: fourth"}, texts[len(texts)-4:])
+	})
+
+	t.Run("renders no comment section for events without comments", func(t *testing.T) {
+		r := newRenderer(t, nil)
+
+		flexJSON, err := r.Render(context.Background(), events)
+
+		require.NoError(t, err)
+		assert.NotContains(t, string(flexJSON), "コメント")
+	})
+
+	t.Run("renders a maps button with an encoded place name", func(t *testing.T) {
+		r := newRenderer(t, nil)
+		withLocation := *events[0]
+		withLocation.LocationName = "Shibuya Station & Café #2"
+
+		flexJSON, err := r.Render(context.Background(), []*event.Event{&withLocation})
+
+		require.NoError(t, err)
+		require.True(t, json.Valid(flexJSON))
+		got := unmarshalFooterButtons(t, flexJSON)
+		require.Len(t, got, 1)
+		require.Len(t, got[0], 1)
+		assert.Equal(t, "地図を開く", got[0][0].Label)
+		assert.Equal(t, "https://www.google.com/maps/search/?api=1&query=Shibuya+Station+%26+Caf%C3%A9+%232", got[0][0].URI)
+	})
+
+	t.Run("prefers coordinates over the place name for the maps button", func(t *testing.T) {
+		r := newRenderer(t, nil)
+		withLocation := *events[0]
+		withLocation.LocationName = "Shibuya Station"
+		withLocation.Coordinates = &event.Coordinates{Latitude: 35.658034, Longitude: 139.701636}
+
+		flexJSON, err := r.Render(context.Background(), []*event.Event{&withLocation})
+
+		require.NoError(t, err)
+		got := unmarshalFooterButtons(t, flexJSON)
+		require.Len(t, got, 1)
+		require.Len(t, got[0], 1)
+		assert.Equal(t, "https://www.google.com/maps/search/?api=1&query=35.658034%2C139.701636", got[0][0].URI)
+	})
+
+	t.Run("renders no maps button for events without a location", func(t *testing.T) {
+		r := newRenderer(t, nil)
+		withLocation := *events[0]
+		withLocation.Title = "Picnic"
+		withLocation.LocationName = "Yoyogi Park"
+
+		flexJSON, err := r.Render(context.Background(), []*event.Event{events[0], &withLocation})
+
+		require.NoError(t, err)
+		require.True(t, json.Valid(flexJSON))
+		got := unmarshalFooterButtons(t, flexJSON)
+		require.Len(t, got, 2)
+		assert.Empty(t, got[0], "events without a location render as before")
+		assert.Len(t, got[1], 1)
+		assert.Equal(t, 1, strings.Count(string(flexJSON), "地図を開く"))
+	})
+
+	t.Run("renders a join button with a fresh nonce for events of the current chat", func(t *testing.T) {
+		r := newRenderer(t, nil)
+		inChat := *events[0]
+		inChat.ChatRoomID = "group-1"
+		inChat.LocationName = "Shibuya Station"
+		ctx := line.WithSourceID(context.Background(), "group-1")
+
+		first, err := r.Render(ctx, []*event.Event{&inChat})
+		require.NoError(t, err)
+		second, err := r.Render(ctx, []*event.Event{&inChat})
+		require.NoError(t, err)
+
+		got := unmarshalFooterButtons(t, first)
+		require.Len(t, got, 1)
+		require.Len(t, got[0], 2)
+		assert.Equal(t, "postback", got[0][0].Type)
+		assert.Equal(t, "参加する", got[0][0].Label)
+		data, nonce := line.SplitPostbackNonce(got[0][0].Data)
+		assert.Equal(t, "action=join_event", data)
+		assert.NotEmpty(t, nonce)
+		assert.Equal(t, "地図を開く", got[0][1].Label)
+		_, otherNonce := line.SplitPostbackNonce(unmarshalFooterButtons(t, second)[0][0].Data)
+		assert.NotEqual(t, nonce, otherNonce, "every rendered card gets its own nonce")
+	})
+
+	t.Run("renders no join button for events of other chats", func(t *testing.T) {
+		r := newRenderer(t, nil)
+		otherChat := *events[0]
+		otherChat.ChatRoomID = "group-2"
+
+		flexJSON, err := r.Render(line.WithSourceID(context.Background(), "group-1"), []*event.Event{&otherChat})
+
+		require.NoError(t, err)
+		assert.NotContains(t, string(flexJSON), "postback")
+	})
+
+	t.Run("labels cards with their group names", func(t *testing.T) {
+		r := newRenderer(t, nil)
+		inGroupA := *events[0]
+		inGroupA.ChatRoomID = "group-a"
+		inGroupB := *events[0]
+		inGroupB.ChatRoomID = "group-b"
+		inGroupB.Title = "Picnic"
+
+		flexJSON, err := r.RenderWithGroupNames(context.Background(), []*event.Event{&inGroupA, &inGroupB, events[0]}, map[string]string{
+			"group-a": `"Board" Games`,
+			"group-b": "Hiking",
+		})
+
+		require.NoError(t, err)
+		require.True(t, json.Valid(flexJSON))
+		var got struct {
+			Contents []struct {
+				Header struct {
+					Contents []struct {
+						Text string `json:"text"`
+					} `json:"contents"`
+				} `json:"header"`
+			} `json:"contents"`
+		}
+		require.NoError(t, json.Unmarshal(flexJSON, &got))
+		require.Len(t, got.Contents, 3)
+		assert.Equal(t, `"Board" Games`, got.Contents[0].Header.Contents[0].Text)
+		assert.Equal(t, "Hiking", got.Contents[1].Header.Contents[0].Text)
+		assert.Equal(t, "Team Meeting", got.Contents[2].Header.Contents[0].Text, "events without a group name render as before")
+	})
+
+	t.Run("renders at most the configured carousel size", func(t *testing.T) {
+		var logs bytes.Buffer
+		r := newRenderer(t, &logs, card.WithCarouselSize(2))
+		many := []*event.Event{events[0], events[0], events[0]}
+
+		flexJSON, err := r.Render(context.Background(), many)
+
+		require.NoError(t, err)
+		var got struct {
+			Contents []json.RawMessage `json:"contents"`
+		}
+		require.NoError(t, json.Unmarshal(flexJSON, &got))
+		assert.Len(t, got.Contents, 2)
+		assert.Contains(t, logs.String(), "too many events for one carousel")
+	})
+
+	t.Run("renders with a custom template", func(t *testing.T) {
+		r := newRenderer(t, nil, card.WithTemplate(customTemplate))
+
+		flexJSON, err := r.Render(context.Background(), events)
+
+		require.NoError(t, err)
+		var got struct {
+			Type     string `json:"type"`
+			Contents []struct {
+				Body struct {
+					Contents []struct {
+						Text string `json:"text"`
+					} `json:"contents"`
+				} `json:"body"`
+			} `json:"contents"`
+		}
+		require.NoError(t, json.Unmarshal(flexJSON, &got))
+		assert.Equal(t, "carousel", got.Type)
+		require.Len(t, got.Contents, 1)
+		require.Len(t, got.Contents[0].Body.Contents, 2)
+		assert.Equal(t, "Team Meeting @ 2025/01/15 10:00", got.Contents[0].Body.Contents[0].Text)
+		assert.Equal(t, "Alice", got.Contents[0].Body.Contents[1].Text)
+	})
+
+	t.Run("falls back to the built-in template when the custom one is not valid JSON", func(t *testing.T) {
+		var logs bytes.Buffer
+		r := newRenderer(t, &logs, card.WithTemplate(`{"type": "bubble", {{.}}`))
+
+		flexJSON, err := r.Render(context.Background(), events)
+
+		require.NoError(t, err)
+		assert.Contains(t, string(flexJSON), `"text": "by Alice"`)
+		assert.Contains(t, logs.String(), "invalid event card template")
+	})
+
+	t.Run("falls back to the built-in template when the custom one does not parse", func(t *testing.T) {
+		var logs bytes.Buffer
+		r := newRenderer(t, &logs, card.WithTemplate(`{{range}}`))
+
+		flexJSON, err := r.Render(context.Background(), events)
+
+		require.NoError(t, err)
+		assert.Contains(t, string(flexJSON), `"text": "Team Meeting"`)
+		assert.Contains(t, logs.String(), "invalid event card template")
+	})
+
+	t.Run("falls back to the built-in template when the custom one is not a flex container", func(t *testing.T) {
+		var logs bytes.Buffer
+		r := newRenderer(t, &logs, card.WithTemplate(`{"type": "image"}`))
+
+		flexJSON, err := r.Render(context.Background(), events)
+
+		require.NoError(t, err)
+		assert.Contains(t, string(flexJSON), `"type": "carousel"`)
+		assert.Contains(t, logs.String(), "invalid event card template")
+	})
+
+	t.Run("falls back to the built-in template when rendering real events fails validation", func(t *testing.T) {
+		var logs bytes.Buffer
+		tmpl := `{{if and . (eq (index . 0).Title "Team Meeting")}}oops{{else}}{"type": "bubble"}{{end}}`
+		r := newRenderer(t, &logs, card.WithTemplate(tmpl))
+
+		flexJSON, err := r.Render(context.Background(), events)
+
+		require.NoError(t, err)
+		assert.Contains(t, string(flexJSON), `"text": "Team Meeting"`)
+		assert.Contains(t, logs.String(), "produced invalid flex message")
+	})
+}
+
+// =============================================================================
+// Helpers
+// =============================================================================
+
+// newRenderer creates a Renderer whose creator "user-1" is named Alice. Logs go to logs if non-nil.
+func newRenderer(t *testing.T, logs *bytes.Buffer, opts ...card.Option) *card.Renderer {
+	t.Helper()
+	logger := slog.New(slog.DiscardHandler)
+	if logs != nil {
+		logger = slog.New(slog.NewTextHandler(logs, nil))
+	}
+	r, err := card.NewRenderer(&mockUserProfileService{}, logger, opts...)
+	require.NoError(t, err)
+	return r
+}
+
+// footerButton is the action of a button in a bubble footer.
+type footerButton struct {
+	Type  string `json:"type"`
+	Label string `json:"label"`
+	URI   string `json:"uri"`
+	Data  string `json:"data"`
+}
+
+// unmarshalFooterButtons returns the footer button actions of each bubble in a carousel.
+func unmarshalFooterButtons(t *testing.T, flexJSON []byte) [][]footerButton {
+	t.Helper()
+	var got struct {
+		Contents []struct {
+			Footer *struct {
+				Contents []struct {
+					Action footerButton `json:"action"`
+				} `json:"contents"`
+			} `json:"footer"`
+		} `json:"contents"`
+	}
+	require.NoError(t, json.Unmarshal(flexJSON, &got))
+	buttons := make([][]footerButton, len(got.Contents))
+	for i, bubble := range got.Contents {
+		if bubble.Footer == nil {
+			continue
+		}
+		for _, c := range bubble.Footer.Contents {
+			buttons[i] = append(buttons[i], c.Action)
+		}
+	}
+	return buttons
+}
+
+type mockUserProfileService struct{}
+
+func (m *mockUserProfileService) GetUserProfiles(ctx context.Context, userIDs []string) (map[string]*userprofile.UserProfile, error) {
+	return map[string]*userprofile.UserProfile{"user-1": {DisplayName: "Alice"}}, nil
+}
@@ -0,0 +1,204 @@
+package clone
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"log/slog"
+	"time"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/clock"
+	"yuruppu/internal/event"
+	"yuruppu/internal/line"
+)
+
+//go:embed parameters.json
+var parametersSchema []byte
+
+//go:embed response.json
+var responseSchema []byte
+
+// errRoomOccupied is returned when the current chat room already has an event, which may have ended.
+var errRoomOccupied = agent.NewUserError("this group already has an event; remove it before cloning another one here")
+
+// EventService provides access to event operations.
+type EventService interface {
+	Create(ctx context.Context, ev *event.Event) error
+	Get(ctx context.Context, chatRoomID string) (*event.Event, error)
+	List(ctx context.Context, opts event.ListOptions) ([]*event.Event, error)
+}
+
+// Tool implements the clone_event tool for creating an event from an existing one.
+type Tool struct {
+	eventService          EventService
+	maxUpcomingPerCreator int
+	minLeadTime           time.Duration
+	logger                *slog.Logger
+}
+
+// New creates a new clone_event tool.
+// maxUpcomingPerCreator caps how many upcoming events one user can have at a time across all groups; 0 means unlimited.
+// minLeadTime is how far ahead of now the copy must start; 0 only requires it to be in the future.
+func New(eventService EventService, maxUpcomingPerCreator int, minLeadTime time.Duration, logger *slog.Logger) (*Tool, error) {
+	if eventService == nil {
+		return nil, errors.New("eventService cannot be nil")
+	}
+	if maxUpcomingPerCreator < 0 {
+		return nil, errors.New("maxUpcomingPerCreator cannot be negative")
+	}
+	if minLeadTime < 0 {
+		return nil, errors.New("minLeadTime cannot be negative")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil")
+	}
+	return &Tool{
+		eventService:          eventService,
+		maxUpcomingPerCreator: maxUpcomingPerCreator,
+		minLeadTime:           minLeadTime,
+		logger:                logger,
+	}, nil
+}
+
+// Name returns the tool name.
+func (t *Tool) Name() string {
+	return "clone_event"
+}
+
+// Description returns a description for the LLM.
+func (t *Tool) Description() string {
+	return "Use this tool to create a new event in the current group chat by copying an existing event with a new date. Title, fee, capacity, description, cover image, and creator visibility are copied; attendees are not. The current group must not already have an event."
+}
+
+// ParametersJsonSchema returns the JSON Schema for input parameters.
+func (t *Tool) ParametersJsonSchema() []byte {
+	return parametersSchema
+}
+
+// ResponseJsonSchema returns the JSON Schema for the response.
+func (t *Tool) ResponseJsonSchema() []byte {
+	return responseSchema
+}
+
+// Callback creates a copy of the source event in the current chat room.
+func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]any, error) {
+	chatType, ok := line.ChatTypeFromContext(ctx)
+	if !ok {
+		t.logger.ErrorContext(ctx, "chat type not found in context")
+		return nil, agent.NewSystemError("internal error", nil)
+	}
+	chatRoomID, ok := line.SourceIDFromContext(ctx)
+	if !ok {
+		t.logger.ErrorContext(ctx, "source ID not found in context")
+		return nil, agent.NewSystemError("internal error", nil)
+	}
+	userID, ok := line.UserIDFromContext(ctx)
+	if !ok {
+		t.logger.ErrorContext(ctx, "user ID not found in context")
+		return nil, agent.NewSystemError("internal error", nil)
+	}
+
+	if chatType != line.ChatTypeGroup {
+		return nil, agent.NewUserError("events can only be created in group chats")
+	}
+
+	sourceChatRoomID, ok := args["source_chat_room_id"].(string)
+	if !ok || sourceChatRoomID == "" {
+		return agent.Invalid("invalid source_chat_room_id"), nil
+	}
+
+	startTimeStr, ok := args["start_time"].(string)
+	if !ok {
+		return agent.Invalid("invalid start_time"), nil
+	}
+	startTime, err := time.Parse(time.RFC3339, startTimeStr)
+	if err != nil {
+		return agent.Invalid("invalid start_time format"), nil
+	}
+
+	source, err := t.eventService.Get(ctx, sourceChatRoomID)
+	if err != nil {
+		if errors.As(err, new(*event.NotFoundError)) {
+			return map[string]any{"status": "not_found"}, nil
+		}
+		t.logger.ErrorContext(ctx, "failed to get event", slog.String("chatRoomID", sourceChatRoomID), slog.Any("error", err))
+		return nil, agent.NewSystemError("failed to get event", err)
+	}
+
+	// Keep the source duration unless end_time is given
+	endTime := startTime.Add(source.EndTime.Sub(source.StartTime))
+	if endTimeArg, ok := args["end_time"]; ok {
+		endTimeStr, ok := endTimeArg.(string)
+		if !ok {
+			return agent.Invalid("invalid end_time"), nil
+		}
+		endTime, err = time.Parse(time.RFC3339, endTimeStr)
+		if err != nil {
+			return agent.Invalid("invalid end_time format"), nil
+		}
+	}
+
+	now := clock.Now(ctx)
+	if err := event.CheckLeadTime(startTime, now, t.minLeadTime); err != nil {
+		return agent.Invalid(err.Error()), nil
+	}
+	if !endTime.After(startTime) {
+		return agent.Invalid("end_time must be after start_time"), nil
+	}
+
+	// Each chat room holds one event, and an ended one stays until retention removes it
+	if _, err := t.eventService.Get(ctx, chatRoomID); err == nil {
+		return nil, errRoomOccupied
+	} else if !errors.As(err, new(*event.NotFoundError)) {
+		t.logger.ErrorContext(ctx, "failed to get event", slog.String("chatRoomID", chatRoomID), slog.Any("error", err))
+		return nil, agent.NewSystemError("failed to get event", err)
+	}
+
+	if t.maxUpcomingPerCreator > 0 {
+		upcoming, err := t.eventService.List(ctx, event.ListOptions{
+			CreatorID: &userID,
+			Start:     &now,
+		})
+		if err != nil {
+			t.logger.ErrorContext(ctx, "failed to list creator events", slog.Any("error", err))
+			return nil, agent.NewSystemError("failed to clone event", err)
+		}
+		if len(upcoming) >= t.maxUpcomingPerCreator {
+			return map[string]any{"status": "limit_reached"}, nil
+		}
+	}
+
+	// Attendees, waitlist, and the creator location start empty
+	ev := &event.Event{
+		ChatRoomID:   chatRoomID,
+		CreatorID:    userID,
+		Title:        source.Title,
+		StartTime:    startTime,
+		EndTime:      endTime,
+		Fee:          source.Fee,
+		Capacity:     source.Capacity,
+		Description:  source.Description,
+		ShowCreator:  source.ShowCreator,
+		ImageURL:     source.ImageURL,
+		Timezone:     source.Timezone,
+		Venue:        source.Venue,
+		LocationName: source.LocationName,
+		Coordinates:  source.Coordinates,
+	}
+	if err := t.eventService.Create(ctx, ev); err != nil {
+		// Another event was created in the room since the check above
+		if errors.As(err, new(*event.AlreadyExistsError)) {
+			return nil, errRoomOccupied
+		}
+		t.logger.ErrorContext(ctx, "failed to create event",
+			slog.String("sourceChatRoomID", sourceChatRoomID),
+			slog.Any("error", err),
+		)
+		return nil, agent.NewSystemError("failed to clone event", err)
+	}
+
+	return map[string]any{
+		"status":       "ok",
+		"chat_room_id": chatRoomID,
+	}, nil
+}
@@ -0,0 +1,441 @@
+package clone_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/clock"
+	"yuruppu/internal/event"
+	"yuruppu/internal/line"
+	"yuruppu/internal/toolset/event/clone"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// Test Helpers
+// =============================================================================
+
+func withGroupContext(ctx context.Context, sourceID, userID string) context.Context {
+	ctx = line.WithChatType(ctx, line.ChatTypeGroup)
+	ctx = line.WithSourceID(ctx, sourceID)
+	ctx = line.WithUserID(ctx, userID)
+	return ctx
+}
+
+func newTestTool(t *testing.T, eventService *mockEventService, maxUpcomingPerCreator int) *clone.Tool {
+	t.Helper()
+	tool, err := clone.New(eventService, maxUpcomingPerCreator, 0, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+	return tool
+}
+
+// sourceEvent returns a past two-hour event with attendees and a waitlist.
+func sourceEvent() *event.Event {
+	start := time.Now().Add(-30 * 24 * time.Hour).Truncate(time.Second)
+	return &event.Event{
+		ChatRoomID:  "group-old",
+		CreatorID:   "user-other",
+		Title:       "Board Game Night",
+		StartTime:   start,
+		EndTime:     start.Add(2 * time.Hour),
+		Fee:         "500 yen",
+		Capacity:    8,
+		Description: "Bring your favorite game",
+		ShowCreator: true,
+		ImageURL:    "https://example.com/board-games.jpg",
+		Timezone:    "Europe/London",
+		Attendees:   []string{"user-1", "user-2"},
+		Waitlist:    []string{"user-3"},
+	}
+}
+
+// =============================================================================
+// New() Tests
+// =============================================================================
+
+func TestNew(t *testing.T) {
+	logger := slog.New(slog.DiscardHandler)
+
+	t.Run("creates tool with valid dependencies", func(t *testing.T) {
+		tool, err := clone.New(&mockEventService{}, 0, 0, logger)
+
+		require.NoError(t, err)
+		assert.Equal(t, "clone_event", tool.Name())
+	})
+
+	t.Run("returns error when eventService is nil", func(t *testing.T) {
+		tool, err := clone.New(nil, 0, 0, logger)
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "eventService cannot be nil")
+	})
+
+	t.Run("returns error when maxUpcomingPerCreator is negative", func(t *testing.T) {
+		tool, err := clone.New(&mockEventService{}, -1, 0, logger)
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "maxUpcomingPerCreator")
+	})
+
+	t.Run("returns error when logger is nil", func(t *testing.T) {
+		tool, err := clone.New(&mockEventService{}, 0, 0, nil)
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "logger cannot be nil")
+	})
+}
+
+// =============================================================================
+// Callback Tests
+// =============================================================================
+
+func TestTool_Callback(t *testing.T) {
+	start := time.Now().Add(7 * 24 * time.Hour).Truncate(time.Second)
+
+	t.Run("copies fields into the current chat room", func(t *testing.T) {
+		service := &mockEventService{getEvent: sourceEvent()}
+		tool := newTestTool(t, service, 0)
+
+		ctx := withGroupContext(context.Background(), "group-new", "user-456")
+		result, err := tool.Callback(ctx, map[string]any{
+			"source_chat_room_id": "group-old",
+			"start_time":          start.Format(time.RFC3339),
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "ok", result["status"])
+		assert.Equal(t, "group-new", result["chat_room_id"])
+		assert.Equal(t, []string{"group-old", "group-new"}, service.getChatRoomIDs)
+		created := service.lastCreatedEvent
+		require.NotNil(t, created)
+		assert.Equal(t, "group-new", created.ChatRoomID)
+		assert.Equal(t, "user-456", created.CreatorID)
+		assert.Equal(t, "Board Game Night", created.Title)
+		assert.Equal(t, "500 yen", created.Fee)
+		assert.Equal(t, 8, created.Capacity)
+		assert.Equal(t, "Bring your favorite game", created.Description)
+		assert.True(t, created.ShowCreator)
+		assert.Equal(t, "https://example.com/board-games.jpg", created.ImageURL)
+		assert.Equal(t, "Europe/London", created.Timezone)
+		assert.True(t, start.Equal(created.StartTime))
+		assert.True(t, start.Add(2*time.Hour).Equal(created.EndTime), "keeps the source duration")
+	})
+
+	t.Run("resets attendees and waitlist", func(t *testing.T) {
+		service := &mockEventService{getEvent: sourceEvent()}
+		tool := newTestTool(t, service, 0)
+
+		ctx := withGroupContext(context.Background(), "group-new", "user-456")
+		_, err := tool.Callback(ctx, map[string]any{
+			"source_chat_room_id": "group-old",
+			"start_time":          start.Format(time.RFC3339),
+		})
+
+		require.NoError(t, err)
+		require.NotNil(t, service.lastCreatedEvent)
+		assert.Empty(t, service.lastCreatedEvent.Attendees)
+		assert.Empty(t, service.lastCreatedEvent.Waitlist)
+		assert.Len(t, service.getEvent.Attendees, 2, "source event is left untouched")
+	})
+
+	t.Run("uses end_time when given", func(t *testing.T) {
+		service := &mockEventService{getEvent: sourceEvent()}
+		tool := newTestTool(t, service, 0)
+
+		ctx := withGroupContext(context.Background(), "group-new", "user-456")
+		end := start.Add(5 * time.Hour)
+		_, err := tool.Callback(ctx, map[string]any{
+			"source_chat_room_id": "group-old",
+			"start_time":          start.Format(time.RFC3339),
+			"end_time":            end.Format(time.RFC3339),
+		})
+
+		require.NoError(t, err)
+		assert.True(t, end.Equal(service.lastCreatedEvent.EndTime))
+	})
+
+	t.Run("returns not_found when the source event does not exist", func(t *testing.T) {
+		service := &mockEventService{getErr: &event.NotFoundError{ChatRoomID: "group-123"}}
+		tool := newTestTool(t, service, 0)
+
+		ctx := withGroupContext(context.Background(), "group-new", "user-456")
+		result, err := tool.Callback(ctx, map[string]any{
+			"source_chat_room_id": "group-missing",
+			"start_time":          start.Format(time.RFC3339),
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "not_found", result["status"])
+		assert.Nil(t, service.lastCreatedEvent)
+	})
+
+	t.Run("returns limit_reached when the creator is at the limit", func(t *testing.T) {
+		service := &mockEventService{
+			getEvent:   sourceEvent(),
+			listEvents: []*event.Event{{ChatRoomID: "group-a", CreatorID: "user-456", StartTime: start}},
+		}
+		tool := newTestTool(t, service, 1)
+
+		ctx := withGroupContext(context.Background(), "group-new", "user-456")
+		result, err := tool.Callback(ctx, map[string]any{
+			"source_chat_room_id": "group-old",
+			"start_time":          start.Format(time.RFC3339),
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "limit_reached", result["status"])
+		assert.Nil(t, service.lastCreatedEvent)
+		require.NotNil(t, service.lastListOpts.CreatorID)
+		assert.Equal(t, "user-456", *service.lastListOpts.CreatorID)
+		assert.NotNil(t, service.lastListOpts.Start)
+	})
+}
+
+func TestTool_Callback_Errors(t *testing.T) {
+	start := time.Now().Add(7 * 24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		args    map[string]any
+		service *mockEventService
+		wantErr string
+	}{
+		{
+			name:    "called from 1:1 chat",
+			ctx:     line.WithChatType(withGroupContext(context.Background(), "user-456", "user-456"), line.ChatTypeOneOnOne),
+			args:    map[string]any{"source_chat_room_id": "group-old", "start_time": start.Format(time.RFC3339)},
+			service: &mockEventService{getEvent: sourceEvent()},
+			wantErr: "group chats",
+		},
+		{
+			name:    "Get fails",
+			ctx:     withGroupContext(context.Background(), "group-new", "user-456"),
+			args:    map[string]any{"source_chat_room_id": "group-old", "start_time": start.Format(time.RFC3339)},
+			service: &mockEventService{getErr: errors.New("storage error")},
+			wantErr: "failed to get event",
+		},
+		{
+			name:    "Create fails",
+			ctx:     withGroupContext(context.Background(), "group-new", "user-456"),
+			args:    map[string]any{"source_chat_room_id": "group-old", "start_time": start.Format(time.RFC3339)},
+			service: &mockEventService{getEvent: sourceEvent(), createErr: errors.New("storage error")},
+			wantErr: "failed to clone event",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := newTestTool(t, tt.service, 0)
+
+			_, err := tool.Callback(tt.ctx, tt.args)
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestTool_Callback_RoomOccupied(t *testing.T) {
+	start := time.Now().Add(7 * 24 * time.Hour).Truncate(time.Second)
+	args := map[string]any{"source_chat_room_id": "group-old", "start_time": start.Format(time.RFC3339)}
+
+	t.Run("rejects cloning a group's own past event while it is still kept", func(t *testing.T) {
+		// The real service allows one event per chat room
+		store := newMemStorage()
+		service, err := event.NewService(store)
+		require.NoError(t, err)
+		require.NoError(t, service.Create(context.Background(), sourceEvent()))
+		tool, err := clone.New(service, 0, 0, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := withGroupContext(context.Background(), "group-old", "user-456")
+		result, err := tool.Callback(ctx, args)
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+		var userErr *agent.UserError
+		require.ErrorAs(t, err, &userErr)
+		assert.Contains(t, err.Error(), "already has an event")
+		events, err := service.List(context.Background(), event.ListOptions{})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, "user-other", events[0].CreatorID, "the existing event should be left as is")
+	})
+
+	t.Run("clones into a free room with the real service", func(t *testing.T) {
+		service, err := event.NewService(newMemStorage())
+		require.NoError(t, err)
+		require.NoError(t, service.Create(context.Background(), sourceEvent()))
+		tool, err := clone.New(service, 0, 0, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := withGroupContext(context.Background(), "group-new", "user-456")
+		result, err := tool.Callback(ctx, args)
+
+		require.NoError(t, err)
+		assert.Equal(t, "ok", result["status"])
+		created, err := service.Get(context.Background(), "group-new")
+		require.NoError(t, err)
+		assert.Equal(t, "user-456", created.CreatorID)
+	})
+
+	t.Run("reports an event created in the room after the check as a user error", func(t *testing.T) {
+		service := &mockEventService{getEvent: sourceEvent(), createErr: &event.AlreadyExistsError{ChatRoomID: "group-new"}}
+		tool := newTestTool(t, service, 0)
+
+		ctx := withGroupContext(context.Background(), "group-new", "user-456")
+		_, err := tool.Callback(ctx, args)
+
+		var userErr *agent.UserError
+		require.ErrorAs(t, err, &userErr)
+		assert.Contains(t, err.Error(), "already has an event")
+	})
+}
+
+func TestTool_Callback_InvalidArgs(t *testing.T) {
+	start := time.Now().Add(7 * 24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		args    map[string]any
+		service *mockEventService
+		want    string
+	}{
+		{
+			name:    "start_time in the past",
+			ctx:     withGroupContext(context.Background(), "group-new", "user-456"),
+			args:    map[string]any{"source_chat_room_id": "group-old", "start_time": time.Now().Add(-time.Hour).Format(time.RFC3339)},
+			service: &mockEventService{getEvent: sourceEvent()},
+			want:    "start_time must be in the future",
+		},
+		{
+			name:    "end_time before start_time",
+			ctx:     withGroupContext(context.Background(), "group-new", "user-456"),
+			args:    map[string]any{"source_chat_room_id": "group-old", "start_time": start.Format(time.RFC3339), "end_time": start.Add(-time.Hour).Format(time.RFC3339)},
+			service: &mockEventService{getEvent: sourceEvent()},
+			want:    "end_time must be after start_time",
+		},
+		{
+			name:    "invalid start_time format",
+			ctx:     withGroupContext(context.Background(), "group-new", "user-456"),
+			args:    map[string]any{"source_chat_room_id": "group-old", "start_time": "tomorrow"},
+			service: &mockEventService{getEvent: sourceEvent()},
+			want:    "invalid start_time format",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := newTestTool(t, tt.service, 0)
+
+			result, err := tool.Callback(tt.ctx, tt.args)
+
+			require.NoError(t, err)
+			assert.Equal(t, agent.Invalid(tt.want), result)
+			assert.Nil(t, tt.service.lastCreatedEvent)
+		})
+	}
+}
+
+func TestTool_Callback_MinLeadTime(t *testing.T) {
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	ctx := clock.WithNow(withGroupContext(context.Background(), "group-new", "user-456"), now)
+
+	t.Run("rejects copy starting sooner than the lead time", func(t *testing.T) {
+		service := &mockEventService{getEvent: sourceEvent()}
+		tool, err := clone.New(service, 0, time.Hour, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(ctx, map[string]any{"source_chat_room_id": "group-old", "start_time": now.Add(30 * time.Minute).Format(time.RFC3339)})
+
+		require.NoError(t, err)
+		assert.Equal(t, agent.Invalid("start_time must be at least 60 minutes from now"), result)
+		assert.Nil(t, service.lastCreatedEvent)
+	})
+
+	t.Run("clones event comfortably in the future", func(t *testing.T) {
+		service := &mockEventService{getEvent: sourceEvent()}
+		tool, err := clone.New(service, 0, time.Hour, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		_, err = tool.Callback(ctx, map[string]any{"source_chat_room_id": "group-old", "start_time": now.Add(7 * 24 * time.Hour).Format(time.RFC3339)})
+
+		require.NoError(t, err)
+		require.NotNil(t, service.lastCreatedEvent)
+	})
+}
+
+// =============================================================================
+// Mocks
+// =============================================================================
+
+type mockEventService struct {
+	getEvent         *event.Event
+	getErr           error
+	getChatRoomIDs   []string
+	listEvents       []*event.Event
+	listErr          error
+	lastListOpts     event.ListOptions
+	createErr        error
+	lastCreatedEvent *event.Event
+}
+
+func (m *mockEventService) Create(ctx context.Context, ev *event.Event) error {
+	if m.createErr != nil {
+		return m.createErr
+	}
+	m.lastCreatedEvent = ev
+	return nil
+}
+
+func (m *mockEventService) Get(ctx context.Context, chatRoomID string) (*event.Event, error) {
+	m.getChatRoomIDs = append(m.getChatRoomIDs, chatRoomID)
+	if m.getErr != nil {
+		return nil, m.getErr
+	}
+	if m.getEvent == nil || m.getEvent.ChatRoomID != chatRoomID {
+		return nil, &event.NotFoundError{ChatRoomID: chatRoomID}
+	}
+	return m.getEvent, nil
+}
+
+func (m *mockEventService) List(ctx context.Context, opts event.ListOptions) ([]*event.Event, error) {
+	m.lastListOpts = opts
+	if m.listErr != nil {
+		return nil, m.listErr
+	}
+	return m.listEvents, nil
+}
+
+// memStorage is an in-memory event.Storage that honors generations like GCS.
+type memStorage struct {
+	data       map[string][]byte
+	generation map[string]int64
+}
+
+func newMemStorage() *memStorage {
+	return &memStorage{data: make(map[string][]byte), generation: make(map[string]int64)}
+}
+
+func (m *memStorage) Read(ctx context.Context, key string) ([]byte, int64, error) {
+	return m.data[key], m.generation[key], nil
+}
+
+func (m *memStorage) Write(ctx context.Context, key, mimetype string, data []byte, expectedGeneration int64) (int64, error) {
+	if expectedGeneration != m.generation[key] {
+		return 0, errors.New("generation mismatch")
+	}
+	m.data[key] = data
+	m.generation[key]++
+	return m.generation[key], nil
+}
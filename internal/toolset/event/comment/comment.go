@@ -0,0 +1,105 @@
+package comment
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"log/slog"
+	"strings"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/clock"
+	"yuruppu/internal/event"
+	"yuruppu/internal/line"
+	"yuruppu/internal/sanitize"
+)
+
+//go:embed parameters.json
+var parametersSchema []byte
+
+//go:embed response.json
+var responseSchema []byte
+
+// EventService provides access to event operations.
+type EventService interface {
+	AddComment(ctx context.Context, chatRoomID string, comment event.EventComment) error
+}
+
+// Tool implements the add_comment tool for leaving a comment on an event.
+type Tool struct {
+	eventService EventService
+	logger       *slog.Logger
+}
+
+// New creates a new add_comment tool.
+func New(eventService EventService, logger *slog.Logger) (*Tool, error) {
+	if eventService == nil {
+		return nil, errors.New("eventService cannot be nil")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil")
+	}
+	return &Tool{
+		eventService: eventService,
+		logger:       logger,
+	}, nil
+}
+
+// Name returns the tool name.
+func (t *Tool) Name() string {
+	return "add_comment"
+}
+
+// Description returns a description for the LLM.
+func (t *Tool) Description() string {
+	return "Use this tool when a user wants to leave a comment or question on the event in the current group chat, so it stays with the event instead of getting lost in the chat. Anyone in the group can comment."
+}
+
+// ParametersJsonSchema returns the JSON Schema for input parameters.
+func (t *Tool) ParametersJsonSchema() []byte {
+	return parametersSchema
+}
+
+// ResponseJsonSchema returns the JSON Schema for the response.
+func (t *Tool) ResponseJsonSchema() []byte {
+	return responseSchema
+}
+
+// Callback appends the user's comment to the event.
+func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]any, error) {
+	sourceID, ok := line.SourceIDFromContext(ctx)
+	if !ok {
+		t.logger.ErrorContext(ctx, "source ID not found in context")
+		return nil, agent.NewSystemError("internal error", nil)
+	}
+	userID, ok := line.UserIDFromContext(ctx)
+	if !ok {
+		t.logger.ErrorContext(ctx, "user ID not found in context")
+		return nil, agent.NewSystemError("internal error", nil)
+	}
+
+	text, ok := args["text"].(string)
+	if !ok {
+		return agent.Invalid("invalid text"), nil
+	}
+	text = strings.TrimSpace(sanitize.Text(text))
+	if err := event.CheckComment(text); err != nil {
+		return agent.Invalid(err.Error()), nil
+	}
+
+	comment := event.EventComment{
+		UserID:    userID,
+		Text:      text,
+		CreatedAt: clock.Now(ctx),
+	}
+	if err := t.eventService.AddComment(ctx, sourceID, comment); err != nil {
+		if errors.As(err, new(*event.NotFoundError)) {
+			return nil, agent.NewUserError("event not found")
+		}
+		return nil, agent.NewSystemError("failed to add comment", err)
+	}
+
+	return map[string]any{
+		"chat_room_id": sourceID,
+		"text":         text,
+	}, nil
+}
@@ -0,0 +1,154 @@
+package comment_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/clock"
+	"yuruppu/internal/event"
+	"yuruppu/internal/line"
+	"yuruppu/internal/toolset/event/comment"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// Test Helpers
+// =============================================================================
+
+var now = time.Date(2026, 5, 10, 20, 0, 0, 0, time.UTC)
+
+// withEventContext creates a context with sourceID, userID, and the current time set.
+func withEventContext(ctx context.Context, sourceID, userID string) context.Context {
+	ctx = line.WithSourceID(ctx, sourceID)
+	ctx = line.WithUserID(ctx, userID)
+	return clock.WithNow(ctx, now)
+}
+
+// =============================================================================
+// New() Tests
+// =============================================================================
+
+func TestNew(t *testing.T) {
+	t.Run("creates tool with valid service", func(t *testing.T) {
+		tool, err := comment.New(&mockEventService{}, slog.New(slog.DiscardHandler))
+
+		require.NoError(t, err)
+		require.NotNil(t, tool)
+		assert.Equal(t, "add_comment", tool.Name())
+	})
+
+	t.Run("returns error when service is nil", func(t *testing.T) {
+		tool, err := comment.New(nil, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "eventService cannot be nil")
+	})
+
+	t.Run("returns error when logger is nil", func(t *testing.T) {
+		tool, err := comment.New(&mockEventService{}, nil)
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "logger cannot be nil")
+	})
+}
+
+// =============================================================================
+// Callback() Tests
+// =============================================================================
+
+func TestTool_Callback(t *testing.T) {
+	t.Run("appends the comment to the event", func(t *testing.T) {
+		service := &mockEventService{}
+		tool, err := comment.New(service, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(withEventContext(t.Context(), "group-123", "user-456"), map[string]any{"text": "  Is parking available?\x00 "})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"chat_room_id": "group-123", "text": "Is parking available?"}, result)
+		assert.Equal(t, "group-123", service.lastChatRoomID)
+		assert.Equal(t, event.EventComment{UserID: "user-456", Text: "Is parking available?", CreatedAt: now}, service.lastComment)
+	})
+
+	t.Run("rejects empty and too long comments", func(t *testing.T) {
+		service := &mockEventService{}
+		tool, err := comment.New(service, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+		ctx := withEventContext(t.Context(), "group-123", "user-456")
+
+		for _, text := range []string{"   ", strings.Repeat("a", event.MaxCommentLength+1)} {
+			result, err := tool.Callback(ctx, map[string]any{"text": text})
+
+			require.NoError(t, err)
+			assert.Equal(t, agent.StatusInvalid, result["status"])
+		}
+		result, err := tool.Callback(ctx, map[string]any{})
+		require.NoError(t, err)
+		assert.Equal(t, agent.Invalid("invalid text"), result)
+		assert.Equal(t, 0, service.callCount)
+	})
+
+	t.Run("returns user error when there is no event", func(t *testing.T) {
+		service := &mockEventService{err: &event.NotFoundError{ChatRoomID: "group-123"}}
+		tool, err := comment.New(service, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(withEventContext(t.Context(), "group-123", "user-456"), map[string]any{"text": "Hello"})
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+		assert.Equal(t, "event not found", err.Error())
+		var userErr *agent.UserError
+		assert.ErrorAs(t, err, &userErr)
+	})
+
+	t.Run("returns system error when the event service fails", func(t *testing.T) {
+		service := &mockEventService{err: errors.New("generation mismatch")}
+		tool, err := comment.New(service, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(withEventContext(t.Context(), "group-123", "user-456"), map[string]any{"text": "Hello"})
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+		assert.Equal(t, "failed to add comment", err.Error())
+		var systemErr *agent.SystemError
+		assert.ErrorAs(t, err, &systemErr)
+	})
+
+	t.Run("returns internal error when user ID is missing", func(t *testing.T) {
+		tool, err := comment.New(&mockEventService{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		_, err = tool.Callback(line.WithSourceID(t.Context(), "group-123"), map[string]any{"text": "Hello"})
+
+		require.Error(t, err)
+		assert.Equal(t, "internal error", err.Error())
+	})
+}
+
+// =============================================================================
+// Mocks
+// =============================================================================
+
+type mockEventService struct {
+	err            error
+	callCount      int
+	lastChatRoomID string
+	lastComment    event.EventComment
+}
+
+func (m *mockEventService) AddComment(ctx context.Context, chatRoomID string, comment event.EventComment) error {
+	m.callCount++
+	m.lastChatRoomID = chatRoomID
+	m.lastComment = comment
+	return m.err
+}
@@ -0,0 +1,101 @@
+package count
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"log/slog"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/event"
+	"yuruppu/internal/line"
+)
+
+//go:embed parameters.json
+var parametersSchema []byte
+
+//go:embed response.json
+var responseSchema []byte
+
+// EventService provides access to event operations.
+type EventService interface {
+	Get(ctx context.Context, chatRoomID string) (*event.Event, error)
+}
+
+// Tool implements the count_attendees tool for reporting an event headcount.
+type Tool struct {
+	eventService EventService
+	logger       *slog.Logger
+}
+
+// New creates a new count_attendees tool.
+func New(eventService EventService, logger *slog.Logger) (*Tool, error) {
+	if eventService == nil {
+		return nil, errors.New("eventService cannot be nil")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil")
+	}
+	return &Tool{
+		eventService: eventService,
+		logger:       logger,
+	}, nil
+}
+
+// Name returns the tool name.
+func (t *Tool) Name() string {
+	return "count_attendees"
+}
+
+// Description returns a description for the LLM.
+func (t *Tool) Description() string {
+	return "Use this tool to answer how many people are coming to an event. Returns the number of attendees, capacity, waitlist size, and remaining spots."
+}
+
+// ParametersJsonSchema returns the JSON Schema for input parameters.
+func (t *Tool) ParametersJsonSchema() []byte {
+	return parametersSchema
+}
+
+// ResponseJsonSchema returns the JSON Schema for the response.
+func (t *Tool) ResponseJsonSchema() []byte {
+	return responseSchema
+}
+
+// Callback returns the attendee counts for an event.
+func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]any, error) {
+	chatRoomID, ok := line.SourceIDFromContext(ctx)
+	if !ok {
+		t.logger.ErrorContext(ctx, "source ID not found in context")
+		return nil, agent.NewSystemError("internal error", nil)
+	}
+	if chatRoomIDArg, ok := args["chat_room_id"]; ok {
+		chatRoomID, ok = chatRoomIDArg.(string)
+		if !ok || chatRoomID == "" {
+			return agent.Invalid("invalid chat_room_id"), nil
+		}
+	}
+
+	ev, err := t.eventService.Get(ctx, chatRoomID)
+	if err != nil {
+		if errors.As(err, new(*event.NotFoundError)) {
+			return map[string]any{
+				"status": "not_found",
+			}, nil
+		}
+		t.logger.ErrorContext(ctx, "failed to get event", slog.String("chatRoomID", chatRoomID), slog.Any("error", err))
+		return nil, agent.NewSystemError("failed to get event", err)
+	}
+
+	attendees := len(ev.Attendees)
+	result := map[string]any{
+		"status":    "ok",
+		"attendees": attendees,
+		"capacity":  ev.Capacity,
+		"waitlist":  len(ev.Waitlist),
+	}
+	// spots_left is omitted for events without a capacity limit
+	if ev.Capacity > 0 {
+		result["spots_left"] = max(ev.Capacity-attendees, 0)
+	}
+	return result, nil
+}
@@ -0,0 +1,179 @@
+package count_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/event"
+	"yuruppu/internal/line"
+	"yuruppu/internal/toolset/event/count"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// New() Tests
+// =============================================================================
+
+func TestNew(t *testing.T) {
+	t.Run("creates tool with valid service", func(t *testing.T) {
+		tool, err := count.New(&mockEventService{}, slog.New(slog.DiscardHandler))
+
+		require.NoError(t, err)
+		require.NotNil(t, tool)
+		assert.Equal(t, "count_attendees", tool.Name())
+	})
+
+	t.Run("returns error when service is nil", func(t *testing.T) {
+		tool, err := count.New(nil, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "eventService cannot be nil")
+	})
+
+	t.Run("returns error when logger is nil", func(t *testing.T) {
+		tool, err := count.New(&mockEventService{}, nil)
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "logger cannot be nil")
+	})
+}
+
+// =============================================================================
+// Callback() Tests
+// =============================================================================
+
+func TestTool_Callback(t *testing.T) {
+	t.Run("returns counts for a populated event", func(t *testing.T) {
+		service := &mockEventService{
+			getEvent: &event.Event{
+				ChatRoomID: "group-123",
+				Capacity:   5,
+				Attendees:  []string{"user-1", "user-2", "user-3"},
+				Waitlist:   []string{"user-4"},
+			},
+		}
+		tool, err := count.New(service, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := line.WithSourceID(t.Context(), "group-123")
+		result, err := tool.Callback(ctx, map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, "group-123", service.lastGetChatRoomID)
+		assert.Equal(t, map[string]any{
+			"status":     "ok",
+			"attendees":  3,
+			"capacity":   5,
+			"waitlist":   1,
+			"spots_left": 2,
+		}, result)
+	})
+
+	t.Run("spots_left is zero when event is over capacity", func(t *testing.T) {
+		service := &mockEventService{
+			getEvent: &event.Event{
+				Capacity:  1,
+				Attendees: []string{"user-1", "user-2"},
+			},
+		}
+		tool, err := count.New(service, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := line.WithSourceID(t.Context(), "group-123")
+		result, err := tool.Callback(ctx, map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, result["spots_left"])
+	})
+
+	t.Run("omits spots_left when capacity is unlimited", func(t *testing.T) {
+		service := &mockEventService{
+			getEvent: &event.Event{
+				Capacity:  0,
+				Attendees: []string{"user-1", "user-2"},
+			},
+		}
+		tool, err := count.New(service, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := line.WithSourceID(t.Context(), "group-123")
+		result, err := tool.Callback(ctx, map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, result["capacity"])
+		assert.NotContains(t, result, "spots_left")
+	})
+
+	t.Run("uses chat_room_id argument when provided", func(t *testing.T) {
+		service := &mockEventService{getEvent: &event.Event{Capacity: 10}}
+		tool, err := count.New(service, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := line.WithSourceID(t.Context(), "group-123")
+		_, err = tool.Callback(ctx, map[string]any{"chat_room_id": "group-456"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "group-456", service.lastGetChatRoomID)
+	})
+
+	t.Run("returns not_found for missing event", func(t *testing.T) {
+		service := &mockEventService{
+			getErr: &event.NotFoundError{ChatRoomID: "group-123"},
+		}
+		tool, err := count.New(service, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := line.WithSourceID(t.Context(), "group-123")
+		result, err := tool.Callback(ctx, map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"status": "not_found"}, result)
+	})
+
+	t.Run("returns error when storage fails", func(t *testing.T) {
+		service := &mockEventService{getErr: errors.New("storage error")}
+		tool, err := count.New(service, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := line.WithSourceID(t.Context(), "group-123")
+		result, err := tool.Callback(ctx, map[string]any{})
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+		assert.Equal(t, "failed to get event", err.Error())
+		var systemErr *agent.SystemError
+		assert.ErrorAs(t, err, &systemErr)
+	})
+
+	t.Run("returns internal error when source ID is missing", func(t *testing.T) {
+		tool, err := count.New(&mockEventService{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(t.Context(), map[string]any{})
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+		assert.Equal(t, "internal error", err.Error())
+	})
+}
+
+// =============================================================================
+// Mocks
+// =============================================================================
+
+type mockEventService struct {
+	getEvent          *event.Event
+	getErr            error
+	lastGetChatRoomID string
+}
+
+func (m *mockEventService) Get(ctx context.Context, chatRoomID string) (*event.Event, error) {
+	m.lastGetChatRoomID = chatRoomID
+	return m.getEvent, m.getErr
+}
@@ -1,13 +1,21 @@
 package create
 
 import (
+	"bytes"
 	"context"
 	_ "embed"
 	"errors"
+	"fmt"
 	"log/slog"
+	"strings"
+	"text/template"
 	"time"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/clock"
 	"yuruppu/internal/event"
+	"yuruppu/internal/groupprofile"
 	"yuruppu/internal/line"
+	"yuruppu/internal/toolset/event/card"
 )
 
 //go:embed parameters.json
@@ -16,28 +24,103 @@ var parametersSchema []byte
 //go:embed response.json
 var responseSchema []byte
 
+//go:embed alt.txt
+var altTemplate string
+
 // EventService provides access to event operations.
 type EventService interface {
 	Create(ctx context.Context, ev *event.Event) error
+	List(ctx context.Context, opts event.ListOptions) ([]*event.Event, error)
+}
+
+// GroupProfileService provides access to group profile operations.
+type GroupProfileService interface {
+	GetGroupProfile(ctx context.Context, groupID string) (*groupprofile.GroupProfile, error)
+}
+
+// LineClient provides LINE messaging operations.
+type LineClient interface {
+	SendFlexPush(to string, altText string, flexJSON []byte) error
+}
+
+// Defaults holds the values applied when the LLM omits optional event fields.
+type Defaults struct {
+	Capacity int    // Used when capacity is omitted; 0 means unlimited
+	Fee      string // Used when fee is omitted or empty
 }
 
 // Tool implements the create_event tool for creating events.
 type Tool struct {
-	eventService EventService
-	logger       *slog.Logger
+	eventService          EventService
+	groupProfileService   GroupProfileService
+	lineClient            LineClient
+	renderer              *card.Renderer
+	altTemplate           *template.Template
+	defaults              Defaults
+	limits                event.TextLimits
+	maxUpcomingPerCreator int
+	minLeadTime           time.Duration
+	logger                *slog.Logger
 }
 
 // New creates a new create_event tool with the specified event service.
-func New(eventService EventService, logger *slog.Logger) (*Tool, error) {
+// groupProfileService supplies the group's default timezone for events created without one.
+// lineClient and userProfileService are used to announce new events to the group with an event card.
+// defaults fills in capacity and fee when they are not given.
+// limits bounds the title and description length.
+// maxUpcomingPerCreator caps how many upcoming events one user can have at a time across all groups; 0 means unlimited.
+// The count is not per group because a group holds only one event.
+// minLeadTime is how far ahead of now an event must start; 0 only requires it to be in the future.
+// cardOpts customize the announcement card.
+func New(eventService EventService, groupProfileService GroupProfileService, lineClient LineClient, userProfileService card.UserProfileService, defaults Defaults, limits event.TextLimits, maxUpcomingPerCreator int, minLeadTime time.Duration, logger *slog.Logger, cardOpts ...card.Option) (*Tool, error) {
 	if eventService == nil {
 		return nil, errors.New("eventService cannot be nil")
 	}
+	if groupProfileService == nil {
+		return nil, errors.New("groupProfileService cannot be nil")
+	}
+	if lineClient == nil {
+		return nil, errors.New("lineClient cannot be nil")
+	}
+	if userProfileService == nil {
+		return nil, errors.New("userProfileService cannot be nil")
+	}
+	if defaults.Capacity < 0 {
+		return nil, errors.New("default capacity cannot be negative")
+	}
+	if err := limits.Validate(); err != nil {
+		return nil, err
+	}
+	if maxUpcomingPerCreator < 0 {
+		return nil, errors.New("maxUpcomingPerCreator cannot be negative")
+	}
+	if minLeadTime < 0 {
+		return nil, errors.New("minLeadTime cannot be negative")
+	}
 	if logger == nil {
 		return nil, errors.New("logger cannot be nil")
 	}
+
+	renderer, err := card.NewRenderer(userProfileService, logger, cardOpts...)
+	if err != nil {
+		return nil, err
+	}
+	altTmpl, err := template.New("alt").Parse(altTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse alt template: %w", err)
+	}
+
 	return &Tool{
-		eventService: eventService,
-		logger:       logger,
+		eventService:          eventService,
+		groupProfileService:   groupProfileService,
+		lineClient:            lineClient,
+		renderer:              renderer,
+		altTemplate:           altTmpl,
+		defaults:              defaults,
+		limits:                limits,
+		maxUpcomingPerCreator: maxUpcomingPerCreator,
+		minLeadTime:           minLeadTime,
+		logger:                logger,
 	}, nil
 }
 
@@ -66,105 +149,334 @@ func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]an
 	chatType, ok := line.ChatTypeFromContext(ctx)
 	if !ok {
 		t.logger.ErrorContext(ctx, "chat type not found in context")
-		return nil, errors.New("internal error")
+		return nil, agent.NewSystemError("internal error", nil)
 	}
 	sourceID, ok := line.SourceIDFromContext(ctx)
 	if !ok {
 		t.logger.ErrorContext(ctx, "source ID not found in context")
-		return nil, errors.New("internal error")
+		return nil, agent.NewSystemError("internal error", nil)
 	}
 	userID, ok := line.UserIDFromContext(ctx)
 	if !ok {
 		t.logger.ErrorContext(ctx, "user ID not found in context")
-		return nil, errors.New("internal error")
+		return nil, agent.NewSystemError("internal error", nil)
 	}
 
 	// FR-003: Users can only create events from group chats
 	if chatType != line.ChatTypeGroup {
-		return nil, errors.New("events can only be created in group chats")
+		return nil, agent.NewUserError("events can only be created in group chats")
 	}
 
-	// Extract arguments (validated by schema)
+	// Constraints the schema cannot express are collected so that the model can fix them all in one retry
+	var invalid invalidArgs
+
 	title, ok := args["title"].(string)
 	if !ok {
-		return nil, errors.New("invalid title")
+		invalid = append(invalid, "invalid title")
+	} else {
+		invalid.add(t.limits.CheckTitle(title))
+	}
+
+	fee, err := t.resolveFee(args)
+	invalid.add(err)
+
+	capacity, err := t.resolveCapacity(args)
+	invalid.add(err)
+
+	description, ok := args["description"].(string)
+	if !ok {
+		invalid = append(invalid, "invalid description")
+	} else {
+		invalid.add(t.limits.CheckDescription(description))
 	}
 
-	startTimeStr, ok := args["start_time"].(string)
+	showCreator, ok := args["show_creator"].(bool)
 	if !ok {
-		return nil, errors.New("invalid start_time")
+		invalid = append(invalid, "invalid show_creator")
 	}
 
-	endTimeStr, ok := args["end_time"].(string)
+	var venue string
+	if venueArg, ok := args["venue"]; ok {
+		if venue, ok = venueArg.(string); !ok {
+			invalid = append(invalid, "invalid venue")
+		}
+		venue = strings.TrimSpace(venue)
+	}
+
+	var creatorLocation string
+	if creatorLocationArg, ok := args["creator_location"]; ok {
+		if creatorLocation, ok = creatorLocationArg.(string); !ok {
+			invalid = append(invalid, "invalid creator_location")
+		}
+		creatorLocation = strings.TrimSpace(creatorLocation)
+	}
+
+	locationName, coordinates, err := resolveLocation(args)
+	invalid.add(err)
+
+	timezone, err := t.resolveTimezone(ctx, args, chatType, sourceID)
+	invalid.add(err)
+
+	announce, err := resolveAnnounce(args, chatType)
+	invalid.add(err)
+
+	// Parse times
+	now := clock.Now(ctx)
+	startTime, startErr := t.resolveStartTime(ctx, args)
+	if startErr != nil && !errors.Is(startErr, errStartTimeMissing) {
+		invalid.add(startErr)
+	}
+	endTime, endErr := t.resolveEndTime(ctx, args)
+	invalid.add(endErr)
+	if startErr == nil {
+		// FR-008: startTime must be in the future
+		invalid.add(event.CheckLeadTime(startTime, now, t.minLeadTime))
+		// FR-008: endTime must be after startTime
+		if endErr == nil && !endTime.After(startTime) {
+			invalid = append(invalid, "end_time must be after start_time")
+		}
+	}
+
+	if len(invalid) > 0 {
+		return agent.Invalid(invalid...), nil
+	}
+	if startErr != nil {
+		return agent.NeedsInput("When does the event start?", "start_time"), nil
+	}
+
+	if err := t.checkCreatorLimit(ctx, userID, now); err != nil {
+		return nil, err
+	}
+
+	// Create event struct
+	ev := &event.Event{
+		ChatRoomID:      sourceID,
+		CreatorID:       userID,
+		Title:           title,
+		StartTime:       startTime,
+		EndTime:         endTime,
+		Fee:             fee,
+		Capacity:        capacity,
+		Description:     description,
+		ShowCreator:     showCreator,
+		Timezone:        timezone,
+		Venue:           venue,
+		LocationName:    locationName,
+		Coordinates:     coordinates,
+		CreatorLocation: creatorLocation,
+	}
+
+	// Call service to create event
+	if err := t.eventService.Create(ctx, ev); err != nil {
+		t.logger.ErrorContext(ctx, "failed to create event", slog.Any("error", err))
+		return nil, agent.NewSystemError("failed to create event", err)
+	}
+
+	announced := announce && t.announce(ctx, sourceID, ev)
+
+	return map[string]any{
+		"chat_room_id": sourceID,
+		"timezone":     timezone,
+		"announced":    announced,
+	}, nil
+}
+
+// resolveLocation returns the location name and coordinates from args.
+// Latitude and longitude must be given together; coordinates are nil when neither is given.
+func resolveLocation(args map[string]any) (string, *event.Coordinates, error) {
+	var name string
+	if nameArg, ok := args["location_name"]; ok {
+		if name, ok = nameArg.(string); !ok {
+			return "", nil, errors.New("invalid location_name")
+		}
+		name = strings.TrimSpace(name)
+	}
+
+	latArg, hasLat := args["latitude"]
+	lngArg, hasLng := args["longitude"]
+	if !hasLat && !hasLng {
+		return name, nil, nil
+	}
+	if hasLat != hasLng {
+		return "", nil, errors.New("latitude and longitude must be given together")
+	}
+	lat, ok := latArg.(float64)
+	if !ok {
+		return "", nil, errors.New("invalid latitude")
+	}
+	lng, ok := lngArg.(float64)
+	if !ok {
+		return "", nil, errors.New("invalid longitude")
+	}
+	coordinates := &event.Coordinates{Latitude: lat, Longitude: lng}
+	if err := coordinates.Check(); err != nil {
+		return "", nil, err
+	}
+	return name, coordinates, nil
+}
+
+// resolveAnnounce returns announce from args, defaulting to true in group chats.
+// One-on-one chats are never announced to; the LLM's reply is the only confirmation there.
+func resolveAnnounce(args map[string]any, chatType line.ChatType) (bool, error) {
+	if chatType != line.ChatTypeGroup {
+		return false, nil
+	}
+	announceArg, ok := args["announce"]
 	if !ok {
-		return nil, errors.New("invalid end_time")
+		return true, nil
+	}
+	announce, ok := announceArg.(bool)
+	if !ok {
+		return false, errors.New("invalid announce")
+	}
+	return announce, nil
+}
+
+// announce pushes ev's card to the group so members learn about it without listing events.
+// The event already exists, so a failure is logged and reported as not announced instead of failing the call.
+func (t *Tool) announce(ctx context.Context, groupID string, ev *event.Event) bool {
+	var altBuf bytes.Buffer
+	if err := t.altTemplate.Execute(&altBuf, ev); err != nil {
+		t.logger.ErrorContext(ctx, "failed to execute alt template", slog.Any("error", err))
+		return false
+	}
+	flexJSON, err := t.renderer.Render(ctx, []*event.Event{ev})
+	if err != nil {
+		t.logger.ErrorContext(ctx, "failed to render announcement", slog.Any("error", err))
+		return false
+	}
+	if err := t.lineClient.SendFlexPush(groupID, altBuf.String(), flexJSON); err != nil {
+		t.logger.WarnContext(ctx, "failed to announce event", slog.String("groupID", groupID), slog.Any("error", err))
+		return false
+	}
+	return true
+}
+
+// checkCreatorLimit rejects creation when the user already has maxUpcomingPerCreator upcoming events in any groups.
+// Events that have already started do not count against the limit.
+func (t *Tool) checkCreatorLimit(ctx context.Context, userID string, now time.Time) error {
+	if t.maxUpcomingPerCreator == 0 {
+		return nil
+	}
+	upcoming, err := t.eventService.List(ctx, event.ListOptions{
+		CreatorID: &userID,
+		Start:     &now,
+	})
+	if err != nil {
+		t.logger.ErrorContext(ctx, "failed to list creator events", slog.Any("error", err))
+		return agent.NewSystemError("failed to create event", err)
+	}
+	if len(upcoming) >= t.maxUpcomingPerCreator {
+		return agent.UserErrorf("you already have %d upcoming events across your groups, which is the limit; remove one of them before creating a new one", len(upcoming))
 	}
+	return nil
+}
 
-	fee, ok := args["fee"].(string)
+// resolveFee returns fee from args, falling back to the default when it is omitted or empty.
+func (t *Tool) resolveFee(args map[string]any) (string, error) {
+	feeArg, ok := args["fee"]
+	if !ok {
+		return t.defaults.Fee, nil
+	}
+	fee, ok := feeArg.(string)
 	if !ok {
-		return nil, errors.New("invalid fee")
+		return "", errors.New("invalid fee")
+	}
+	if fee == "" {
+		return t.defaults.Fee, nil
 	}
+	return fee, nil
+}
 
-	capacityFloat, ok := args["capacity"].(float64)
+// resolveCapacity returns capacity from args, falling back to the default when it is omitted.
+// An explicit 0 means unlimited and is kept as is.
+func (t *Tool) resolveCapacity(args map[string]any) (int, error) {
+	capacityArg, ok := args["capacity"]
 	if !ok {
-		return nil, errors.New("invalid capacity")
+		return t.defaults.Capacity, nil
+	}
+	capacityFloat, ok := capacityArg.(float64)
+	if !ok || capacityFloat < 0 {
+		return 0, errors.New("capacity must be a non-negative number; use 0 for unlimited")
 	}
-	capacity := int(capacityFloat)
+	return int(capacityFloat), nil
+}
 
-	description, ok := args["description"].(string)
+// errStartTimeMissing is returned by resolveStartTime when neither start_time nor a picked datetime is given.
+var errStartTimeMissing = errors.New("start_time is required")
+
+// resolveStartTime returns start_time from args, falling back to the value
+// picked with a datetime picker postback when start_time is omitted.
+func (t *Tool) resolveStartTime(ctx context.Context, args map[string]any) (time.Time, error) {
+	startTimeArg, ok := args["start_time"]
 	if !ok {
-		return nil, errors.New("invalid description")
+		if params, ok := line.PostbackParamsFromContext(ctx); ok && params.DateTime != nil {
+			return *params.DateTime, nil
+		}
+		return time.Time{}, errStartTimeMissing
 	}
 
-	showCreator, ok := args["show_creator"].(bool)
+	startTimeStr, ok := startTimeArg.(string)
 	if !ok {
-		return nil, errors.New("invalid show_creator")
+		return time.Time{}, errors.New("invalid start_time")
 	}
 
-	// Parse times
 	startTime, err := time.Parse(time.RFC3339, startTimeStr)
 	if err != nil {
 		t.logger.ErrorContext(ctx, "invalid start_time format", slog.Any("error", err))
-		return nil, errors.New("invalid start_time format")
+		return time.Time{}, errors.New("invalid start_time format")
+	}
+	return startTime, nil
+}
+
+// resolveEndTime returns end_time from args.
+func (t *Tool) resolveEndTime(ctx context.Context, args map[string]any) (time.Time, error) {
+	endTimeStr, ok := args["end_time"].(string)
+	if !ok {
+		return time.Time{}, errors.New("invalid end_time")
 	}
 
 	endTime, err := time.Parse(time.RFC3339, endTimeStr)
 	if err != nil {
 		t.logger.ErrorContext(ctx, "invalid end_time format", slog.Any("error", err))
-		return nil, errors.New("invalid end_time format")
+		return time.Time{}, errors.New("invalid end_time format")
 	}
+	return endTime, nil
+}
 
-	// FR-008: startTime must be in the future
-	now := time.Now()
-	if !startTime.After(now) {
-		return nil, errors.New("start_time must be in the future")
+// resolveTimezone returns timezone from args, falling back to the group's default timezone
+// and then to event.DefaultTimezone when it is omitted.
+// A group profile that cannot be read is treated as having no default.
+func (t *Tool) resolveTimezone(ctx context.Context, args map[string]any, chatType line.ChatType, sourceID string) (string, error) {
+	if timezoneArg, ok := args["timezone"]; ok {
+		timezone, ok := timezoneArg.(string)
+		if !ok {
+			return "", errors.New("invalid timezone")
+		}
+		timezone = strings.TrimSpace(timezone)
+		if err := event.ValidateTimezone(timezone); err != nil {
+			return "", errors.New("timezone must be an IANA time zone name such as Asia/Tokyo")
+		}
+		return timezone, nil
 	}
 
-	// FR-008: endTime must be after startTime
-	if !endTime.After(startTime) {
-		return nil, errors.New("end_time must be after start_time")
+	if chatType == line.ChatTypeGroup {
+		profile, err := t.groupProfileService.GetGroupProfile(ctx, sourceID)
+		if err != nil {
+			t.logger.WarnContext(ctx, "failed to get group profile, using the default timezone", slog.Any("error", err))
+		} else if profile.DefaultTimezone != "" && event.ValidateTimezone(profile.DefaultTimezone) == nil {
+			return profile.DefaultTimezone, nil
+		}
 	}
+	return event.DefaultTimezone, nil
+}
 
-	// Create event struct
-	ev := &event.Event{
-		ChatRoomID:  sourceID,
-		CreatorID:   userID,
-		Title:       title,
-		StartTime:   startTime,
-		EndTime:     endTime,
-		Fee:         fee,
-		Capacity:    capacity,
-		Description: description,
-		ShowCreator: showCreator,
-	}
+// invalidArgs collects the constraints the arguments break, reported together with agent.Invalid.
+type invalidArgs []string
 
-	// Call service to create event
-	if err := t.eventService.Create(ctx, ev); err != nil {
-		t.logger.ErrorContext(ctx, "failed to create event", slog.Any("error", err))
-		return nil, errors.New("failed to create event")
+// add records err's message when err is not nil.
+func (v *invalidArgs) add(err error) {
+	if err != nil {
+		*v = append(*v, err.Error())
 	}
-
-	return map[string]any{
-		"chat_room_id": sourceID,
-	}, nil
 }
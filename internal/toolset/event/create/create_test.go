@@ -4,11 +4,16 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"strings"
 	"testing"
 	"time"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/clock"
 	"yuruppu/internal/event"
+	"yuruppu/internal/groupprofile"
 	"yuruppu/internal/line"
 	"yuruppu/internal/toolset/event/create"
+	"yuruppu/internal/userprofile"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -54,7 +59,7 @@ func TestNew(t *testing.T) {
 	t.Run("creates tool with valid service", func(t *testing.T) {
 		service := &mockEventService{}
 
-		tool, err := create.New(service, slog.New(slog.DiscardHandler))
+		tool, err := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
 
 		require.NoError(t, err)
 		require.NotNil(t, tool)
@@ -62,22 +67,84 @@ func TestNew(t *testing.T) {
 	})
 
 	t.Run("returns error when service is nil", func(t *testing.T) {
-		tool, err := create.New(nil, slog.New(slog.DiscardHandler))
+		tool, err := create.New(nil, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
 
 		require.Error(t, err)
 		assert.Nil(t, tool)
 		assert.Contains(t, err.Error(), "eventService cannot be nil")
 	})
 
+	t.Run("returns error when groupProfileService is nil", func(t *testing.T) {
+		tool, err := create.New(&mockEventService{}, nil, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "groupProfileService cannot be nil")
+	})
+
+	t.Run("returns error when lineClient is nil", func(t *testing.T) {
+		tool, err := create.New(&mockEventService{}, &mockGroupProfileService{}, nil, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "lineClient cannot be nil")
+	})
+
+	t.Run("returns error when userProfileService is nil", func(t *testing.T) {
+		tool, err := create.New(&mockEventService{}, &mockGroupProfileService{}, &mockLineClient{}, nil, create.Defaults{}, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "userProfileService cannot be nil")
+	})
+
+	t.Run("returns error when text limits are invalid", func(t *testing.T) {
+		tool, err := create.New(&mockEventService{}, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.TextLimits{MaxDescription: 10}, 0, 0, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "max title length must be positive")
+	})
+
 	t.Run("returns error when logger is nil", func(t *testing.T) {
 		service := &mockEventService{}
 
-		tool, err := create.New(service, nil)
+		tool, err := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, 0, nil)
 
 		require.Error(t, err)
 		assert.Nil(t, tool)
 		assert.Contains(t, err.Error(), "logger cannot be nil")
 	})
+
+	t.Run("returns error when default capacity is negative", func(t *testing.T) {
+		service := &mockEventService{}
+
+		tool, err := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{Capacity: -1}, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "default capacity")
+	})
+
+	t.Run("returns error when maxUpcomingPerCreator is negative", func(t *testing.T) {
+		service := &mockEventService{}
+
+		tool, err := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, -1, 0, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "maxUpcomingPerCreator")
+	})
+
+	t.Run("returns error when minLeadTime is negative", func(t *testing.T) {
+		service := &mockEventService{}
+
+		tool, err := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, -time.Minute, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "minLeadTime")
+	})
 }
 
 // =============================================================================
@@ -86,7 +153,7 @@ func TestNew(t *testing.T) {
 
 func TestTool_Metadata(t *testing.T) {
 	service := &mockEventService{}
-	tool, _ := create.New(service, slog.New(slog.DiscardHandler))
+	tool, _ := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
 
 	t.Run("Name returns create_event", func(t *testing.T) {
 		assert.Equal(t, "create_event", tool.Name())
@@ -124,7 +191,7 @@ func TestTool_Metadata(t *testing.T) {
 func TestTool_Callback_Success(t *testing.T) {
 	t.Run("creates event with valid args from group chat", func(t *testing.T) {
 		service := &mockEventService{}
-		tool, _ := create.New(service, slog.New(slog.DiscardHandler))
+		tool, _ := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-123", "user-456")
 		args := validEventArgs()
@@ -147,7 +214,7 @@ func TestTool_Callback_Success(t *testing.T) {
 
 	t.Run("sets all event attributes correctly", func(t *testing.T) {
 		service := &mockEventService{}
-		tool, _ := create.New(service, slog.New(slog.DiscardHandler))
+		tool, _ := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-999", "user-888")
 		now := time.Now()
@@ -155,13 +222,18 @@ func TestTool_Callback_Success(t *testing.T) {
 		endTime := now.Add(50 * time.Hour)
 
 		args := map[string]any{
-			"title":        "Conference",
-			"start_time":   startTime.Format(time.RFC3339),
-			"end_time":     endTime.Format(time.RFC3339),
-			"fee":          "5000 yen",
-			"capacity":     float64(100),
-			"description":  "Annual tech conference",
-			"show_creator": false,
+			"title":            "Conference",
+			"start_time":       startTime.Format(time.RFC3339),
+			"end_time":         endTime.Format(time.RFC3339),
+			"fee":              "5000 yen",
+			"capacity":         float64(100),
+			"description":      "Annual tech conference",
+			"show_creator":     false,
+			"venue":            " Yokohama ",
+			"location_name":    " Osanbashi Pier ",
+			"creator_location": " Sapporo ",
+			"latitude":         35.4517,
+			"longitude":        139.6475,
 		}
 
 		result, err := tool.Callback(ctx, args)
@@ -179,6 +251,153 @@ func TestTool_Callback_Success(t *testing.T) {
 		assert.Equal(t, "5000 yen", ev.Fee)
 		assert.Equal(t, "Annual tech conference", ev.Description)
 		assert.Equal(t, false, ev.ShowCreator)
+		assert.Equal(t, "Yokohama", ev.Venue)
+		assert.Equal(t, "Osanbashi Pier", ev.LocationName)
+		assert.Equal(t, &event.Coordinates{Latitude: 35.4517, Longitude: 139.6475}, ev.Coordinates)
+		assert.Equal(t, "Sapporo", ev.CreatorLocation)
+	})
+
+	t.Run("leaves the location empty when omitted", func(t *testing.T) {
+		service := &mockEventService{}
+		tool, _ := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
+
+		_, err := tool.Callback(withEventContext(context.Background(), "group-123", "user-456"), validEventArgs())
+
+		require.NoError(t, err)
+		assert.Empty(t, service.lastCreatedEvent.LocationName)
+		assert.Nil(t, service.lastCreatedEvent.Coordinates)
+	})
+}
+
+// =============================================================================
+// Callback Tests - Postback Start Time
+// =============================================================================
+
+func TestTool_Callback_PostbackStartTime(t *testing.T) {
+	t.Run("uses picked datetime when start_time is omitted", func(t *testing.T) {
+		service := &mockEventService{}
+		tool, _ := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
+
+		picked := time.Now().Add(24 * time.Hour).Truncate(time.Minute)
+		ctx := withEventContext(context.Background(), "group-123", "user-456")
+		ctx = line.WithPostbackParams(ctx, line.PostbackParams{DateTime: &picked})
+		args := validEventArgs()
+		delete(args, "start_time")
+
+		_, err := tool.Callback(ctx, args)
+
+		require.NoError(t, err)
+		assert.True(t, picked.Equal(service.lastCreatedEvent.StartTime))
+	})
+
+	t.Run("explicit start_time takes precedence over picked datetime", func(t *testing.T) {
+		service := &mockEventService{}
+		tool, _ := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
+
+		picked := time.Now().Add(12 * time.Hour)
+		ctx := withEventContext(context.Background(), "group-123", "user-456")
+		ctx = line.WithPostbackParams(ctx, line.PostbackParams{DateTime: &picked})
+		args := validEventArgs()
+
+		_, err := tool.Callback(ctx, args)
+
+		require.NoError(t, err)
+		assert.False(t, picked.Equal(service.lastCreatedEvent.StartTime))
+	})
+
+	t.Run("asks for start_time when omitted without picked datetime", func(t *testing.T) {
+		service := &mockEventService{}
+		tool, _ := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
+
+		ctx := withEventContext(context.Background(), "group-123", "user-456")
+		args := validEventArgs()
+		delete(args, "start_time")
+
+		result, err := tool.Callback(ctx, args)
+
+		require.NoError(t, err)
+		assert.Equal(t, agent.NeedsInput("When does the event start?", "start_time"), result)
+		assert.Nil(t, service.lastCreatedEvent)
+	})
+}
+
+// =============================================================================
+// Callback Tests - Defaults
+// =============================================================================
+
+func TestTool_Callback_Defaults(t *testing.T) {
+	defaults := create.Defaults{Capacity: 20, Fee: "未定"}
+
+	t.Run("applies defaults when capacity and fee are omitted", func(t *testing.T) {
+		service := &mockEventService{}
+		tool, _ := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, defaults, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
+
+		ctx := withEventContext(context.Background(), "group-123", "user-456")
+		args := validEventArgs()
+		delete(args, "capacity")
+		delete(args, "fee")
+
+		_, err := tool.Callback(ctx, args)
+
+		require.NoError(t, err)
+		assert.Equal(t, 20, service.lastCreatedEvent.Capacity)
+		assert.Equal(t, "未定", service.lastCreatedEvent.Fee)
+	})
+
+	t.Run("applies default fee when fee is empty", func(t *testing.T) {
+		service := &mockEventService{}
+		tool, _ := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, defaults, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
+
+		ctx := withEventContext(context.Background(), "group-123", "user-456")
+		args := validEventArgs()
+		args["fee"] = ""
+
+		_, err := tool.Callback(ctx, args)
+
+		require.NoError(t, err)
+		assert.Equal(t, "未定", service.lastCreatedEvent.Fee)
+	})
+
+	t.Run("explicit values override defaults", func(t *testing.T) {
+		service := &mockEventService{}
+		tool, _ := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, defaults, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
+
+		ctx := withEventContext(context.Background(), "group-123", "user-456")
+		args := validEventArgs()
+
+		_, err := tool.Callback(ctx, args)
+
+		require.NoError(t, err)
+		assert.Equal(t, 10, service.lastCreatedEvent.Capacity)
+		assert.Equal(t, "Free", service.lastCreatedEvent.Fee)
+	})
+
+	t.Run("explicit zero capacity means unlimited, not default", func(t *testing.T) {
+		service := &mockEventService{}
+		tool, _ := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, defaults, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
+
+		ctx := withEventContext(context.Background(), "group-123", "user-456")
+		args := validEventArgs()
+		args["capacity"] = float64(0)
+
+		_, err := tool.Callback(ctx, args)
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, service.lastCreatedEvent.Capacity)
+	})
+
+	t.Run("zero default capacity leaves omitted capacity unlimited", func(t *testing.T) {
+		service := &mockEventService{}
+		tool, _ := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
+
+		ctx := withEventContext(context.Background(), "group-123", "user-456")
+		args := validEventArgs()
+		delete(args, "capacity")
+
+		_, err := tool.Callback(ctx, args)
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, service.lastCreatedEvent.Capacity)
 	})
 }
 
@@ -189,7 +408,7 @@ func TestTool_Callback_Success(t *testing.T) {
 func TestTool_Callback_ContextErrors(t *testing.T) {
 	t.Run("returns error when called from 1:1 chat", func(t *testing.T) {
 		service := &mockEventService{}
-		tool, _ := create.New(service, slog.New(slog.DiscardHandler))
+		tool, _ := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "user-123", "user-123")
 		args := validEventArgs()
@@ -203,7 +422,7 @@ func TestTool_Callback_ContextErrors(t *testing.T) {
 
 	t.Run("returns error when sourceID not in context", func(t *testing.T) {
 		service := &mockEventService{}
-		tool, _ := create.New(service, slog.New(slog.DiscardHandler))
+		tool, _ := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
 
 		ctx := line.WithUserID(context.Background(), "user-123")
 		args := validEventArgs()
@@ -216,7 +435,7 @@ func TestTool_Callback_ContextErrors(t *testing.T) {
 
 	t.Run("returns error when userID not in context", func(t *testing.T) {
 		service := &mockEventService{}
-		tool, _ := create.New(service, slog.New(slog.DiscardHandler))
+		tool, _ := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
 
 		ctx := line.WithSourceID(context.Background(), "group-123")
 		args := validEventArgs()
@@ -274,23 +493,100 @@ func TestTool_Callback_ValidationErrors(t *testing.T) {
 				args["end_time"] = "not-a-date"
 			},
 		},
+		{
+			name: "latitude without longitude",
+			modifyArgs: func(args map[string]any) {
+				args["latitude"] = 35.0
+			},
+		},
+		{
+			name: "longitude without latitude",
+			modifyArgs: func(args map[string]any) {
+				args["longitude"] = 139.0
+			},
+		},
+		{
+			name: "latitude out of range",
+			modifyArgs: func(args map[string]any) {
+				args["latitude"] = 91.0
+				args["longitude"] = 139.0
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			service := &mockEventService{}
-			tool, _ := create.New(service, slog.New(slog.DiscardHandler))
+			tool, _ := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
 
 			ctx := withEventContext(context.Background(), "group-123", "user-456")
 			args := validEventArgs()
 			tt.modifyArgs(args)
 
-			_, err := tool.Callback(ctx, args)
+			result, err := tool.Callback(ctx, args)
 
-			require.Error(t, err)
+			require.NoError(t, err)
+			assert.Equal(t, agent.StatusInvalid, result["status"])
+			assert.Len(t, result["errors"], 1)
 			assert.Equal(t, 0, service.createCount)
 		})
 	}
+
+	t.Run("bad capacity returns an invalid result without calling the service", func(t *testing.T) {
+		service := &mockEventService{}
+		tool, _ := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 1, 0, slog.New(slog.DiscardHandler))
+
+		ctx := withEventContext(context.Background(), "group-123", "user-456")
+		args := validEventArgs()
+		args["capacity"] = float64(-5)
+
+		result, err := tool.Callback(ctx, args)
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"status": "invalid",
+			"errors": []any{"capacity must be a non-negative number; use 0 for unlimited"},
+		}, result)
+		assert.Equal(t, 0, service.createCount)
+		assert.Equal(t, 0, service.listCount)
+	})
+
+	t.Run("reports every broken constraint at once", func(t *testing.T) {
+		service := &mockEventService{}
+		tool, _ := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
+
+		ctx := withEventContext(context.Background(), "group-123", "user-456")
+		args := validEventArgs()
+		args["capacity"] = float64(-5)
+		args["timezone"] = "JST"
+		args["end_time"] = "not-a-date"
+
+		result, err := tool.Callback(ctx, args)
+
+		require.NoError(t, err)
+		assert.Equal(t, []any{
+			"capacity must be a non-negative number; use 0 for unlimited",
+			"timezone must be an IANA time zone name such as Asia/Tokyo",
+			"invalid end_time format",
+		}, result["errors"])
+		assert.Equal(t, 0, service.createCount)
+	})
+
+	t.Run("reports broken constraints before asking for a missing start_time", func(t *testing.T) {
+		service := &mockEventService{}
+		tool, _ := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
+
+		ctx := withEventContext(context.Background(), "group-123", "user-456")
+		args := validEventArgs()
+		delete(args, "start_time")
+		args["capacity"] = float64(-5)
+
+		result, err := tool.Callback(ctx, args)
+
+		require.NoError(t, err)
+		assert.Equal(t, agent.StatusInvalid, result["status"])
+		assert.Equal(t, 0, service.createCount)
+	})
 }
 
 // =============================================================================
@@ -302,7 +598,7 @@ func TestTool_Callback_ServiceErrors(t *testing.T) {
 		service := &mockEventService{
 			createErr: errors.New("storage error"),
 		}
-		tool, _ := create.New(service, slog.New(slog.DiscardHandler))
+		tool, _ := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-123", "user-456")
 		args := validEventArgs()
@@ -314,6 +610,370 @@ func TestTool_Callback_ServiceErrors(t *testing.T) {
 	})
 }
 
+// =============================================================================
+// Callback Tests - Creator Limit
+// =============================================================================
+
+func TestTool_Callback_CreatorLimit(t *testing.T) {
+	now := time.Now()
+	upcoming := func(chatRoomID, creatorID string) *event.Event {
+		return &event.Event{ChatRoomID: chatRoomID, CreatorID: creatorID, StartTime: now.Add(48 * time.Hour)}
+	}
+
+	t.Run("rejects creation when the creator is at the limit", func(t *testing.T) {
+		service := &mockEventService{
+			events: []*event.Event{
+				upcoming("group-1", "user-456"),
+				upcoming("group-2", "user-456"),
+			},
+		}
+		tool, _ := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 2, 0, slog.New(slog.DiscardHandler))
+
+		ctx := withEventContext(context.Background(), "group-123", "user-456")
+		_, err := tool.Callback(ctx, validEventArgs())
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "2 upcoming events across your groups")
+		assert.Equal(t, 0, service.createCount)
+		require.NotNil(t, service.lastListOpts.CreatorID)
+		assert.Equal(t, "user-456", *service.lastListOpts.CreatorID)
+	})
+
+	t.Run("creates event when the creator is under the limit", func(t *testing.T) {
+		service := &mockEventService{
+			events: []*event.Event{
+				upcoming("group-1", "user-456"),
+				upcoming("group-2", "other-user"),
+			},
+		}
+		tool, _ := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 2, 0, slog.New(slog.DiscardHandler))
+
+		ctx := withEventContext(context.Background(), "group-123", "user-456")
+		_, err := tool.Callback(ctx, validEventArgs())
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, service.createCount)
+	})
+
+	t.Run("past events do not count against the limit", func(t *testing.T) {
+		service := &mockEventService{
+			events: []*event.Event{
+				{ChatRoomID: "group-1", CreatorID: "user-456", StartTime: now.Add(-48 * time.Hour)},
+			},
+		}
+		tool, _ := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 1, 0, slog.New(slog.DiscardHandler))
+
+		ctx := withEventContext(context.Background(), "group-123", "user-456")
+		_, err := tool.Callback(ctx, validEventArgs())
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, service.createCount)
+	})
+
+	t.Run("zero limit skips the check", func(t *testing.T) {
+		service := &mockEventService{
+			listErr: errors.New("storage error"),
+		}
+		tool, _ := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
+
+		ctx := withEventContext(context.Background(), "group-123", "user-456")
+		_, err := tool.Callback(ctx, validEventArgs())
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, service.listCount)
+	})
+
+	t.Run("returns error when service List fails", func(t *testing.T) {
+		service := &mockEventService{
+			listErr: errors.New("storage error"),
+		}
+		tool, _ := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 1, 0, slog.New(slog.DiscardHandler))
+
+		ctx := withEventContext(context.Background(), "group-123", "user-456")
+		_, err := tool.Callback(ctx, validEventArgs())
+
+		require.Error(t, err)
+		assert.Equal(t, 0, service.createCount)
+	})
+}
+
+// =============================================================================
+// Callback Tests - Minimum Lead Time
+// =============================================================================
+
+func TestTool_Callback_MinLeadTime(t *testing.T) {
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("rejects event starting in the past", func(t *testing.T) {
+		service := &mockEventService{}
+		tool, _ := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, 30*time.Minute, slog.New(slog.DiscardHandler))
+
+		ctx := clock.WithNow(withEventContext(context.Background(), "group-123", "user-456"), now)
+		args := validEventArgs()
+		args["start_time"] = now.Add(-time.Hour).Format(time.RFC3339)
+		args["end_time"] = now.Add(time.Hour).Format(time.RFC3339)
+
+		result, err := tool.Callback(ctx, args)
+
+		require.NoError(t, err)
+		assert.Equal(t, agent.Invalid("start_time must be in the future"), result)
+		assert.Equal(t, 0, service.createCount)
+	})
+
+	t.Run("rejects event starting sooner than the lead time", func(t *testing.T) {
+		service := &mockEventService{}
+		tool, _ := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, 30*time.Minute, slog.New(slog.DiscardHandler))
+
+		ctx := clock.WithNow(withEventContext(context.Background(), "group-123", "user-456"), now)
+		args := validEventArgs()
+		args["start_time"] = now.Add(10 * time.Minute).Format(time.RFC3339)
+		args["end_time"] = now.Add(time.Hour).Format(time.RFC3339)
+
+		result, err := tool.Callback(ctx, args)
+
+		require.NoError(t, err)
+		assert.Equal(t, agent.Invalid("start_time must be at least 30 minutes from now"), result)
+		assert.Equal(t, 0, service.createCount)
+	})
+
+	t.Run("creates event comfortably in the future", func(t *testing.T) {
+		service := &mockEventService{}
+		tool, _ := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, 30*time.Minute, slog.New(slog.DiscardHandler))
+
+		ctx := clock.WithNow(withEventContext(context.Background(), "group-123", "user-456"), now)
+		args := validEventArgs()
+		args["start_time"] = now.Add(3 * 24 * time.Hour).Format(time.RFC3339)
+		args["end_time"] = now.Add(3*24*time.Hour + 2*time.Hour).Format(time.RFC3339)
+
+		_, err := tool.Callback(ctx, args)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, service.createCount)
+	})
+
+	t.Run("zero lead time accepts event starting soon", func(t *testing.T) {
+		service := &mockEventService{}
+		tool, _ := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
+
+		ctx := clock.WithNow(withEventContext(context.Background(), "group-123", "user-456"), now)
+		args := validEventArgs()
+		args["start_time"] = now.Add(time.Minute).Format(time.RFC3339)
+		args["end_time"] = now.Add(time.Hour).Format(time.RFC3339)
+
+		_, err := tool.Callback(ctx, args)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, service.createCount)
+	})
+}
+
+// =============================================================================
+// Callback Tests - Length Limits
+// =============================================================================
+
+func TestTool_Callback_TextLimits(t *testing.T) {
+	limits := event.TextLimits{MaxTitle: 5, MaxDescription: 10}
+
+	t.Run("accepts title and description at the limit counting runes", func(t *testing.T) {
+		service := &mockEventService{}
+		tool, _ := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, limits, 0, 0, slog.New(slog.DiscardHandler))
+
+		ctx := withEventContext(context.Background(), "group-123", "user-456")
+		args := validEventArgs()
+		args["title"] = "花見🌸🍡!"
+		args["description"] = strings.Repeat("🎉", 10)
+		_, err := tool.Callback(ctx, args)
+
+		require.NoError(t, err)
+		require.Equal(t, 1, service.createCount)
+		assert.Equal(t, "花見🌸🍡!", service.lastCreatedEvent.Title)
+	})
+
+	t.Run("rejects a description over the limit", func(t *testing.T) {
+		service := &mockEventService{}
+		tool, _ := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, limits, 0, 0, slog.New(slog.DiscardHandler))
+
+		ctx := withEventContext(context.Background(), "group-123", "user-456")
+		args := validEventArgs()
+		args["title"] = "Hike"
+		args["description"] = strings.Repeat("あ", 11)
+		result, err := tool.Callback(ctx, args)
+
+		require.NoError(t, err)
+		assert.Equal(t, agent.Invalid("description is too long: 11 characters, the maximum is 10"), result)
+		assert.Equal(t, 0, service.createCount)
+	})
+
+	t.Run("rejects a title over the limit", func(t *testing.T) {
+		service := &mockEventService{}
+		tool, _ := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, limits, 0, 0, slog.New(slog.DiscardHandler))
+
+		ctx := withEventContext(context.Background(), "group-123", "user-456")
+		args := validEventArgs()
+		args["title"] = "Team Meeting"
+		args["description"] = "Sync"
+		result, err := tool.Callback(ctx, args)
+
+		require.NoError(t, err)
+		assert.Equal(t, agent.Invalid("title is too long: 12 characters, the maximum is 5"), result)
+		assert.Equal(t, 0, service.createCount)
+	})
+}
+
+// =============================================================================
+// Callback Tests - Timezone
+// =============================================================================
+
+func TestTool_Callback_Timezone(t *testing.T) {
+	t.Run("inherits the group default timezone", func(t *testing.T) {
+		service := &mockEventService{}
+		groups := &mockGroupProfileService{profile: &groupprofile.GroupProfile{DefaultTimezone: "America/New_York"}}
+		tool, _ := create.New(service, groups, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
+
+		ctx := withEventContext(context.Background(), "group-123", "user-456")
+		result, err := tool.Callback(ctx, validEventArgs())
+
+		require.NoError(t, err)
+		assert.Equal(t, "America/New_York", result["timezone"])
+		assert.Equal(t, "group-123", groups.lastGroupID)
+		require.NotNil(t, service.lastCreatedEvent)
+		assert.Equal(t, "America/New_York", service.lastCreatedEvent.Timezone)
+	})
+
+	t.Run("explicit timezone overrides the group default", func(t *testing.T) {
+		service := &mockEventService{}
+		groups := &mockGroupProfileService{profile: &groupprofile.GroupProfile{DefaultTimezone: "America/New_York"}}
+		tool, _ := create.New(service, groups, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
+
+		ctx := withEventContext(context.Background(), "group-123", "user-456")
+		args := validEventArgs()
+		args["timezone"] = "Europe/London"
+		result, err := tool.Callback(ctx, args)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Europe/London", result["timezone"])
+		assert.Equal(t, "Europe/London", service.lastCreatedEvent.Timezone)
+		assert.Equal(t, 0, groups.getCount, "should not look up the group default")
+	})
+
+	t.Run("uses the global default when the group has none", func(t *testing.T) {
+		service := &mockEventService{}
+		groups := &mockGroupProfileService{profile: &groupprofile.GroupProfile{}}
+		tool, _ := create.New(service, groups, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
+
+		ctx := withEventContext(context.Background(), "group-123", "user-456")
+		_, err := tool.Callback(ctx, validEventArgs())
+
+		require.NoError(t, err)
+		assert.Equal(t, event.DefaultTimezone, service.lastCreatedEvent.Timezone)
+	})
+
+	t.Run("uses the global default when the group profile cannot be read", func(t *testing.T) {
+		service := &mockEventService{}
+		groups := &mockGroupProfileService{getErr: errors.New("group profile not found")}
+		tool, _ := create.New(service, groups, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
+
+		ctx := withEventContext(context.Background(), "group-123", "user-456")
+		_, err := tool.Callback(ctx, validEventArgs())
+
+		require.NoError(t, err)
+		assert.Equal(t, event.DefaultTimezone, service.lastCreatedEvent.Timezone)
+	})
+
+	t.Run("rejects an unknown timezone", func(t *testing.T) {
+		service := &mockEventService{}
+		tool, _ := create.New(service, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
+
+		ctx := withEventContext(context.Background(), "group-123", "user-456")
+		args := validEventArgs()
+		args["timezone"] = "JST"
+		result, err := tool.Callback(ctx, args)
+
+		require.NoError(t, err)
+		assert.Equal(t, agent.Invalid("timezone must be an IANA time zone name such as Asia/Tokyo"), result)
+		assert.Equal(t, 0, service.createCount)
+	})
+}
+
+// =============================================================================
+// Callback Tests - Announcement
+// =============================================================================
+
+func TestTool_Callback_Announce(t *testing.T) {
+	t.Run("pushes the event card to the group by default", func(t *testing.T) {
+		service := &mockEventService{}
+		lineClient := &mockLineClient{}
+		tool, _ := create.New(service, &mockGroupProfileService{}, lineClient, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
+
+		ctx := withEventContext(context.Background(), "group-123", "user-456")
+		result, err := tool.Callback(ctx, validEventArgs())
+
+		require.NoError(t, err)
+		assert.Equal(t, true, result["announced"])
+		require.Len(t, lineClient.pushes, 1)
+		assert.Equal(t, "group-123", lineClient.pushes[0].to)
+		assert.Equal(t, "新しいイベント：Team Meeting", lineClient.pushes[0].altText)
+		assert.Contains(t, string(lineClient.pushes[0].flexJSON), `"text": "Team Meeting"`)
+		assert.Contains(t, string(lineClient.pushes[0].flexJSON), `"text": "by Alice"`)
+	})
+
+	t.Run("does not announce when announce is false", func(t *testing.T) {
+		service := &mockEventService{}
+		lineClient := &mockLineClient{}
+		tool, _ := create.New(service, &mockGroupProfileService{}, lineClient, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
+
+		ctx := withEventContext(context.Background(), "group-123", "user-456")
+		args := validEventArgs()
+		args["announce"] = false
+		result, err := tool.Callback(ctx, args)
+
+		require.NoError(t, err)
+		assert.Equal(t, false, result["announced"])
+		assert.Equal(t, 1, service.createCount)
+		assert.Empty(t, lineClient.pushes)
+	})
+
+	t.Run("still succeeds when the announcement fails", func(t *testing.T) {
+		service := &mockEventService{}
+		lineClient := &mockLineClient{pushErr: errors.New("push quota exceeded")}
+		tool, _ := create.New(service, &mockGroupProfileService{}, lineClient, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
+
+		ctx := withEventContext(context.Background(), "group-123", "user-456")
+		result, err := tool.Callback(ctx, validEventArgs())
+
+		require.NoError(t, err)
+		assert.Equal(t, false, result["announced"])
+		assert.Equal(t, 1, service.createCount)
+	})
+
+	t.Run("does not announce when the event is not created", func(t *testing.T) {
+		service := &mockEventService{createErr: errors.New("storage error")}
+		lineClient := &mockLineClient{}
+		tool, _ := create.New(service, &mockGroupProfileService{}, lineClient, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
+
+		ctx := withEventContext(context.Background(), "group-123", "user-456")
+		_, err := tool.Callback(ctx, validEventArgs())
+
+		require.Error(t, err)
+		assert.Empty(t, lineClient.pushes)
+	})
+
+	t.Run("only replies in one-on-one chats", func(t *testing.T) {
+		service := &mockEventService{}
+		lineClient := &mockLineClient{}
+		tool, _ := create.New(service, &mockGroupProfileService{}, lineClient, &mockUserProfileService{}, create.Defaults{}, event.DefaultTextLimits, 0, 0, slog.New(slog.DiscardHandler))
+
+		ctx := withEventContext(context.Background(), "user-456", "user-456")
+		args := validEventArgs()
+		args["announce"] = true
+		_, err := tool.Callback(ctx, args)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "group chats")
+		assert.Empty(t, lineClient.pushes, "nothing is pushed; the LLM replies instead")
+	})
+}
+
 // =============================================================================
 // Mocks
 // =============================================================================
@@ -322,6 +982,10 @@ type mockEventService struct {
 	createErr        error
 	createCount      int
 	lastCreatedEvent *event.Event
+	events           []*event.Event
+	listErr          error
+	listCount        int
+	lastListOpts     event.ListOptions
 }
 
 func (m *mockEventService) Create(ctx context.Context, ev *event.Event) error {
@@ -329,3 +993,68 @@ func (m *mockEventService) Create(ctx context.Context, ev *event.Event) error {
 	m.lastCreatedEvent = ev
 	return m.createErr
 }
+
+// List applies the creator and start filters the way event.Service does.
+func (m *mockEventService) List(ctx context.Context, opts event.ListOptions) ([]*event.Event, error) {
+	m.listCount++
+	m.lastListOpts = opts
+	if m.listErr != nil {
+		return nil, m.listErr
+	}
+	var result []*event.Event
+	for _, ev := range m.events {
+		if opts.CreatorID != nil && ev.CreatorID != *opts.CreatorID {
+			continue
+		}
+		if opts.Start != nil && ev.StartTime.Before(*opts.Start) {
+			continue
+		}
+		result = append(result, ev)
+	}
+	return result, nil
+}
+
+type mockGroupProfileService struct {
+	profile     *groupprofile.GroupProfile
+	getErr      error
+	getCount    int
+	lastGroupID string
+}
+
+func (m *mockGroupProfileService) GetGroupProfile(ctx context.Context, groupID string) (*groupprofile.GroupProfile, error) {
+	m.getCount++
+	m.lastGroupID = groupID
+	if m.getErr != nil {
+		return nil, m.getErr
+	}
+	if m.profile == nil {
+		return &groupprofile.GroupProfile{}, nil
+	}
+	return m.profile, nil
+}
+
+type flexPush struct {
+	to       string
+	altText  string
+	flexJSON []byte
+}
+
+type mockLineClient struct {
+	pushErr error
+	pushes  []flexPush
+}
+
+func (m *mockLineClient) SendFlexPush(to string, altText string, flexJSON []byte) error {
+	m.pushes = append(m.pushes, flexPush{to: to, altText: altText, flexJSON: flexJSON})
+	return m.pushErr
+}
+
+type mockUserProfileService struct{}
+
+func (m *mockUserProfileService) GetUserProfiles(ctx context.Context, userIDs []string) (map[string]*userprofile.UserProfile, error) {
+	profiles := make(map[string]*userprofile.UserProfile, len(userIDs))
+	for _, userID := range userIDs {
+		profiles[userID] = &userprofile.UserProfile{DisplayName: "Alice"}
+	}
+	return profiles, nil
+}
@@ -0,0 +1,133 @@
+package creatorweather
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"log/slog"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/event"
+	"yuruppu/internal/line"
+	"yuruppu/internal/toolset/weather"
+)
+
+//go:embed parameters.json
+var parametersSchema []byte
+
+//go:embed response.json
+var responseSchema []byte
+
+// EventService provides access to event operations.
+type EventService interface {
+	Get(ctx context.Context, chatRoomID string) (*event.Event, error)
+}
+
+// Observer provides the current weather.
+// Errors are returned from the tool as is, so they should be classified like those of weather.Provider.
+type Observer interface {
+	Current(ctx context.Context, location string) (*weather.Conditions, error)
+}
+
+// Tool implements the get_creator_weather tool for reporting the current weather where an event's creator is.
+type Tool struct {
+	eventService EventService
+	observer     Observer
+	logger       *slog.Logger
+}
+
+// New creates a new get_creator_weather tool.
+func New(eventService EventService, observer Observer, logger *slog.Logger) (*Tool, error) {
+	if eventService == nil {
+		return nil, errors.New("eventService cannot be nil")
+	}
+	if observer == nil {
+		return nil, errors.New("observer cannot be nil")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil")
+	}
+	return &Tool{
+		eventService: eventService,
+		observer:     observer,
+		logger:       logger,
+	}, nil
+}
+
+// Name returns the tool name.
+func (t *Tool) Name() string {
+	return "get_creator_weather"
+}
+
+// Description returns a description for the LLM.
+func (t *Tool) Description() string {
+	return "Use this tool to answer what the weather is like right now where an event's creator is, using the location they gave when creating the event. For the weather at the event's venue on its date, use get_event_weather instead."
+}
+
+// ParametersJsonSchema returns the JSON Schema for input parameters.
+func (t *Tool) ParametersJsonSchema() []byte {
+	return parametersSchema
+}
+
+// ResponseJsonSchema returns the JSON Schema for the response.
+func (t *Tool) ResponseJsonSchema() []byte {
+	return responseSchema
+}
+
+// Callback returns the current weather at the event creator's location.
+func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]any, error) {
+	chatRoomID, ok := line.SourceIDFromContext(ctx)
+	if !ok {
+		t.logger.ErrorContext(ctx, "source ID not found in context")
+		return nil, agent.NewSystemError("internal error", nil)
+	}
+	if chatRoomIDArg, ok := args["chat_room_id"]; ok {
+		chatRoomID, ok = chatRoomIDArg.(string)
+		if !ok || chatRoomID == "" {
+			return agent.Invalid("invalid chat_room_id"), nil
+		}
+	}
+
+	ev, err := t.eventService.Get(ctx, chatRoomID)
+	if err != nil {
+		if errors.As(err, new(*event.NotFoundError)) {
+			return map[string]any{
+				"status": "not_found",
+			}, nil
+		}
+		t.logger.ErrorContext(ctx, "failed to get event", slog.String("chatRoomID", chatRoomID), slog.Any("error", err))
+		return nil, agent.NewSystemError("failed to get event", err)
+	}
+	if ev.CreatorLocation == "" {
+		return nil, agent.NewUserError("the event creator has not given their location, so the weather there is unknown")
+	}
+
+	conditions, err := t.observer.Current(ctx, ev.CreatorLocation)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"status":           "ok",
+		"title":            ev.Title,
+		"creator_location": ev.CreatorLocation,
+		"current":          buildCurrent(conditions),
+	}, nil
+}
+
+// buildCurrent converts conditions into the response, omitting values the provider did not report.
+func buildCurrent(conditions *weather.Conditions) map[string]any {
+	current := map[string]any{}
+	for key, value := range map[string]string{
+		"temp_c":          conditions.TempC,
+		"feels_like_c":    conditions.FeelsLikeC,
+		"condition":       conditions.Condition,
+		"humidity":        conditions.Humidity,
+		"wind_speed_kmph": conditions.WindSpeedKmph,
+		"wind_direction":  conditions.WindDirection,
+	} {
+		if value != "" {
+			current[key] = value
+		}
+	}
+	return current
+}
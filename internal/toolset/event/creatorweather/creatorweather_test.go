@@ -0,0 +1,246 @@
+package creatorweather_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/event"
+	"yuruppu/internal/line"
+	"yuruppu/internal/toolset/event/creatorweather"
+	"yuruppu/internal/toolset/weather"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// New() Tests
+// =============================================================================
+
+func TestNew(t *testing.T) {
+	t.Run("creates tool with valid dependencies", func(t *testing.T) {
+		tool, err := creatorweather.New(&mockEventService{}, &mockObserver{}, slog.New(slog.DiscardHandler))
+
+		require.NoError(t, err)
+		require.NotNil(t, tool)
+		assert.Equal(t, "get_creator_weather", tool.Name())
+	})
+
+	t.Run("returns error when eventService is nil", func(t *testing.T) {
+		tool, err := creatorweather.New(nil, &mockObserver{}, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "eventService cannot be nil")
+	})
+
+	t.Run("returns error when observer is nil", func(t *testing.T) {
+		tool, err := creatorweather.New(&mockEventService{}, nil, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "observer cannot be nil")
+	})
+
+	t.Run("returns error when logger is nil", func(t *testing.T) {
+		tool, err := creatorweather.New(&mockEventService{}, &mockObserver{}, nil)
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "logger cannot be nil")
+	})
+}
+
+// =============================================================================
+// Callback() Tests
+// =============================================================================
+
+func TestTool_Callback(t *testing.T) {
+	t.Run("returns the current weather at the creator location", func(t *testing.T) {
+		service := &mockEventService{
+			getEvent: &event.Event{
+				ChatRoomID:      "group-123",
+				Title:           "BBQ",
+				Venue:           "Osaka",
+				CreatorLocation: "Sapporo",
+			},
+		}
+		observer := &mockObserver{
+			conditions: &weather.Conditions{
+				TempC:         "8",
+				FeelsLikeC:    "5",
+				Condition:     "Light snow",
+				Humidity:      "85",
+				WindSpeedKmph: "12",
+				WindDirection: "NW",
+				UVIndex:       "1",
+			},
+		}
+		tool, err := creatorweather.New(service, observer, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(withSourceID(t.Context()), map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, "group-123", service.lastGetChatRoomID)
+		assert.Equal(t, "Sapporo", observer.lastLocation)
+		assert.Equal(t, map[string]any{
+			"status":           "ok",
+			"title":            "BBQ",
+			"creator_location": "Sapporo",
+			"current": map[string]any{
+				"temp_c":          "8",
+				"feels_like_c":    "5",
+				"condition":       "Light snow",
+				"humidity":        "85",
+				"wind_speed_kmph": "12",
+				"wind_direction":  "NW",
+			},
+		}, result)
+	})
+
+	t.Run("omits conditions the provider did not report", func(t *testing.T) {
+		service := &mockEventService{
+			getEvent: &event.Event{ChatRoomID: "group-123", CreatorLocation: "Naha"},
+		}
+		observer := &mockObserver{conditions: &weather.Conditions{TempC: "27"}}
+		tool, err := creatorweather.New(service, observer, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(withSourceID(t.Context()), map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"temp_c": "27"}, result["current"])
+	})
+
+	t.Run("returns error when the creator has not given a location", func(t *testing.T) {
+		service := &mockEventService{
+			getEvent: &event.Event{ChatRoomID: "group-123", Title: "BBQ", Venue: "Osaka"},
+		}
+		observer := &mockObserver{}
+		tool, err := creatorweather.New(service, observer, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(withSourceID(t.Context()), map[string]any{})
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+		var userErr *agent.UserError
+		require.ErrorAs(t, err, &userErr)
+		assert.Equal(t, "the event creator has not given their location, so the weather there is unknown", err.Error())
+		assert.False(t, observer.called, "should not fall back to the venue")
+	})
+
+	t.Run("uses chat_room_id argument when provided", func(t *testing.T) {
+		service := &mockEventService{
+			getEvent: &event.Event{ChatRoomID: "group-456", CreatorLocation: "Fukuoka"},
+		}
+		tool, err := creatorweather.New(service, &mockObserver{conditions: &weather.Conditions{TempC: "18"}}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(withSourceID(t.Context()), map[string]any{"chat_room_id": "group-456"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "group-456", service.lastGetChatRoomID)
+		assert.Equal(t, "ok", result["status"])
+	})
+
+	t.Run("returns invalid for an empty chat_room_id", func(t *testing.T) {
+		service := &mockEventService{}
+		tool, err := creatorweather.New(service, &mockObserver{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(withSourceID(t.Context()), map[string]any{"chat_room_id": ""})
+
+		require.NoError(t, err)
+		assert.Equal(t, agent.Invalid("invalid chat_room_id"), result)
+		assert.Empty(t, service.lastGetChatRoomID)
+	})
+
+	t.Run("returns not_found when there is no event", func(t *testing.T) {
+		service := &mockEventService{getErr: &event.NotFoundError{ChatRoomID: "group-123"}}
+		tool, err := creatorweather.New(service, &mockObserver{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(withSourceID(t.Context()), map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"status": "not_found"}, result)
+	})
+
+	t.Run("returns observer error as is", func(t *testing.T) {
+		service := &mockEventService{
+			getEvent: &event.Event{ChatRoomID: "group-123", CreatorLocation: "Atlantis"},
+		}
+		observer := &mockObserver{err: errors.New("location not found")}
+		tool, err := creatorweather.New(service, observer, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(withSourceID(t.Context()), map[string]any{})
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+		assert.Equal(t, "location not found", err.Error())
+	})
+
+	t.Run("returns error when event service fails", func(t *testing.T) {
+		service := &mockEventService{getErr: errors.New("storage down")}
+		tool, err := creatorweather.New(service, &mockObserver{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(withSourceID(t.Context()), map[string]any{})
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+		assert.Equal(t, "failed to get event", err.Error())
+	})
+
+	t.Run("returns error when source ID is missing", func(t *testing.T) {
+		tool, err := creatorweather.New(&mockEventService{}, &mockObserver{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(t.Context(), map[string]any{})
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+		assert.Equal(t, "internal error", err.Error())
+	})
+}
+
+// =============================================================================
+// Helpers
+// =============================================================================
+
+func withSourceID(ctx context.Context) context.Context {
+	return line.WithSourceID(ctx, "group-123")
+}
+
+// =============================================================================
+// Mocks
+// =============================================================================
+
+type mockEventService struct {
+	getEvent          *event.Event
+	getErr            error
+	lastGetChatRoomID string
+}
+
+func (m *mockEventService) Get(ctx context.Context, chatRoomID string) (*event.Event, error) {
+	m.lastGetChatRoomID = chatRoomID
+	return m.getEvent, m.getErr
+}
+
+type mockObserver struct {
+	conditions   *weather.Conditions
+	err          error
+	called       bool
+	lastLocation string
+}
+
+func (m *mockObserver) Current(ctx context.Context, location string) (*weather.Conditions, error) {
+	m.called = true
+	m.lastLocation = location
+	return m.conditions, m.err
+}
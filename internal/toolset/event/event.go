@@ -4,11 +4,28 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"time"
 	"yuruppu/internal/agent"
 	"yuruppu/internal/event"
+	"yuruppu/internal/groupprofile"
+	"yuruppu/internal/toolset/event/cancel"
+	"yuruppu/internal/toolset/event/card"
+	"yuruppu/internal/toolset/event/clone"
+	"yuruppu/internal/toolset/event/comment"
+	"yuruppu/internal/toolset/event/count"
 	"yuruppu/internal/toolset/event/create"
+	"yuruppu/internal/toolset/event/creatorweather"
+	"yuruppu/internal/toolset/event/forecast"
+	"yuruppu/internal/toolset/event/ics"
+	"yuruppu/internal/toolset/event/image"
+	"yuruppu/internal/toolset/event/join"
 	"yuruppu/internal/toolset/event/list"
+	"yuruppu/internal/toolset/event/mine"
 	"yuruppu/internal/toolset/event/remove"
+	"yuruppu/internal/toolset/event/rsvp"
+	"yuruppu/internal/toolset/event/search"
+	"yuruppu/internal/toolset/event/showcreator"
+	"yuruppu/internal/toolset/event/transfer"
 	"yuruppu/internal/toolset/event/update"
 	"yuruppu/internal/userprofile"
 )
@@ -18,23 +35,73 @@ type EventService interface {
 	Create(ctx context.Context, ev *event.Event) error
 	Get(ctx context.Context, chatRoomID string) (*event.Event, error)
 	List(ctx context.Context, opts event.ListOptions) ([]*event.Event, error)
+	ListPage(ctx context.Context, opts event.ListOptions, cursor string) ([]*event.Event, string, error)
 	Update(ctx context.Context, chatRoomID string, description string) error
 	Remove(ctx context.Context, chatRoomID string) error
+	AddAttendee(ctx context.Context, chatRoomID, userID string) (bool, error)
+	RemoveAttendee(ctx context.Context, chatRoomID, userID string) (string, error)
+	Transfer(ctx context.Context, chatRoomID, newCreatorID string) error
+	SetImage(ctx context.Context, chatRoomID, imageURL string) error
+	AddComment(ctx context.Context, chatRoomID string, comment event.EventComment) error
+	ToggleShowCreator(ctx context.Context, chatRoomID string) (bool, error)
 }
 
 // UserProfileService provides access to user profile operations.
 type UserProfileService interface {
-	GetUserProfile(ctx context.Context, userID string) (*userprofile.UserProfile, error)
+	GetUserProfiles(ctx context.Context, userIDs []string) (map[string]*userprofile.UserProfile, error)
+}
+
+// GroupProfileService provides access to group profile operations.
+type GroupProfileService interface {
+	GetGroupProfile(ctx context.Context, groupID string) (*groupprofile.GroupProfile, error)
+	GroupIDsForUser(ctx context.Context, userID string) ([]string, error)
+}
+
+// FileStorage stores exported event files and issues download URLs for them.
+type FileStorage interface {
+	Write(ctx context.Context, key, mimetype string, data []byte, expectedGeneration int64) (newGeneration int64, err error)
+	GetSignedURL(ctx context.Context, key, method string, ttl time.Duration) (string, error)
 }
 
 // LineClient provides LINE messaging operations.
 type LineClient interface {
-	SendFlexReply(replyToken string, altText string, flexJSON []byte) error
+	SendFlex(ctx context.Context, altText string, flexJSON []byte) error
+	SendFlexPush(to string, altText string, flexJSON []byte) error
+	SendPush(to string, text string) error
+	IsGroupMember(ctx context.Context, groupID, userID string) (bool, error)
+}
+
+// Forecaster provides the daily weather forecasts used by get_event_weather
+// and the current weather used by get_creator_weather.
+type Forecaster interface {
+	forecast.Forecaster
+	creatorweather.Observer
+}
+
+// CreateDefaults holds the capacity and fee applied when create_event omits them.
+type CreateDefaults = create.Defaults
+
+// ListDefaultWindow defines which events list_events shows when neither start nor end is given.
+type ListDefaultWindow = list.DefaultWindow
+
+// TextLimits bounds the title and description length, in runes, accepted by create_event and update_event.
+type TextLimits = event.TextLimits
+
+// ToolsConfig holds event tool configuration.
+type ToolsConfig struct {
+	CreateDefaults              CreateDefaults    // capacity and fee applied when create_event omits them
+	TextLimits                  TextLimits        // title and description length accepted by create_event and update_event
+	CreateMaxUpcomingPerCreator int               // upcoming events one user can have across all groups when creating or cloning (0 = unlimited)
+	CreateMinLeadTime           time.Duration     // how far ahead of now a created or cloned event must start (0 = no limit)
+	ListMaxPeriodDays           int               // longest period list_events accepts (required)
+	ListLimit                   int               // most events list_events, search_events, and all_my_events return (required)
+	ListDefaultWindow           ListDefaultWindow // what list_events shows without filters (zero value = from today onward)
+	CardOptions                 []card.Option     // customize the event cards sent by list_events, search_events, and all_my_events and announced by create_event
 }
 
-// NewTools creates all event management tools (create, list, update, remove).
+// NewTools creates all event management tools (create, list, update, remove, count, search, join_event, cancel_rsvp, export_ics, transfer_event, clone_event, set_event_image, rsvp_status, get_event_weather, get_creator_weather, add_comment, toggle_show_creator, all_my_events).
 // Returns error if any service is nil or configuration values are invalid.
-func NewTools(eventService EventService, lineClient LineClient, userProfileService UserProfileService, listMaxPeriodDays, listLimit int, logger *slog.Logger) ([]agent.Tool, error) {
+func NewTools(eventService EventService, lineClient LineClient, userProfileService UserProfileService, groupProfileService GroupProfileService, fileStorage FileStorage, forecaster Forecaster, config ToolsConfig, logger *slog.Logger) ([]agent.Tool, error) {
 	if eventService == nil {
 		return nil, errors.New("eventService cannot be nil")
 	}
@@ -44,10 +111,19 @@ func NewTools(eventService EventService, lineClient LineClient, userProfileServi
 	if userProfileService == nil {
 		return nil, errors.New("userProfileService cannot be nil")
 	}
-	if listMaxPeriodDays <= 0 {
+	if groupProfileService == nil {
+		return nil, errors.New("groupProfileService cannot be nil")
+	}
+	if fileStorage == nil {
+		return nil, errors.New("fileStorage cannot be nil")
+	}
+	if forecaster == nil {
+		return nil, errors.New("forecaster cannot be nil")
+	}
+	if config.ListMaxPeriodDays <= 0 {
 		return nil, errors.New("listMaxPeriodDays must be positive")
 	}
-	if listLimit <= 0 {
+	if config.ListLimit <= 0 {
 		return nil, errors.New("listLimit must be positive")
 	}
 	if logger == nil {
@@ -55,19 +131,19 @@ func NewTools(eventService EventService, lineClient LineClient, userProfileServi
 	}
 
 	// Create create_event tool
-	createTool, err := create.New(eventService, logger)
+	createTool, err := create.New(eventService, groupProfileService, lineClient, userProfileService, config.CreateDefaults, config.TextLimits, config.CreateMaxUpcomingPerCreator, config.CreateMinLeadTime, logger, config.CardOptions...)
 	if err != nil {
 		return nil, err
 	}
 
 	// Create list_events tool
-	listTool, err := list.New(eventService, lineClient, userProfileService, listMaxPeriodDays, listLimit, logger)
+	listTool, err := list.New(eventService, lineClient, userProfileService, config.ListMaxPeriodDays, config.ListLimit, config.ListDefaultWindow, logger, config.CardOptions...)
 	if err != nil {
 		return nil, err
 	}
 
 	// Create update_event tool
-	updateTool, err := update.New(eventService, logger)
+	updateTool, err := update.New(eventService, config.TextLimits, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -78,5 +154,89 @@ func NewTools(eventService EventService, lineClient LineClient, userProfileServi
 		return nil, err
 	}
 
-	return []agent.Tool{createTool, listTool, updateTool, removeTool}, nil
+	// Create count_attendees tool
+	countTool, err := count.New(eventService, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create search_events tool
+	searchTool, err := search.New(eventService, lineClient, userProfileService, config.ListLimit, logger, config.CardOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create join_event tool
+	joinTool, err := join.New(eventService, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create cancel_rsvp tool
+	cancelTool, err := cancel.New(eventService, lineClient, userProfileService, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create export_ics tool
+	icsTool, err := ics.New(eventService, fileStorage, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create transfer_event tool
+	transferTool, err := transfer.New(eventService, lineClient, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create clone_event tool
+	cloneTool, err := clone.New(eventService, config.CreateMaxUpcomingPerCreator, config.CreateMinLeadTime, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create set_event_image tool
+	imageTool, err := image.New(eventService, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create rsvp_status tool
+	rsvpTool, err := rsvp.New(eventService, groupProfileService, userProfileService, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create get_event_weather tool
+	forecastTool, err := forecast.New(eventService, forecaster, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create get_creator_weather tool
+	creatorWeatherTool, err := creatorweather.New(eventService, forecaster, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create add_comment tool
+	commentTool, err := comment.New(eventService, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create toggle_show_creator tool
+	showCreatorTool, err := showcreator.New(eventService, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create all_my_events tool
+	mineTool, err := mine.New(eventService, groupProfileService, lineClient, userProfileService, config.ListLimit, logger, config.CardOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	return []agent.Tool{createTool, listTool, updateTool, removeTool, countTool, searchTool, joinTool, cancelTool, icsTool, transferTool, cloneTool, imageTool, rsvpTool, forecastTool, creatorWeatherTool, commentTool, showCreatorTool, mineTool}, nil
 }
@@ -4,9 +4,13 @@ import (
 	"context"
 	"log/slog"
 	"testing"
+	"time"
 	"yuruppu/internal/agent"
 	"yuruppu/internal/event"
+	"yuruppu/internal/groupprofile"
 	eventtoolset "yuruppu/internal/toolset/event"
+	"yuruppu/internal/toolset/event/card"
+	"yuruppu/internal/toolset/weather"
 	"yuruppu/internal/userprofile"
 
 	"github.com/stretchr/testify/assert"
@@ -32,6 +36,10 @@ func (m *mockEventService) List(ctx context.Context, opts event.ListOptions) ([]
 	return []*event.Event{}, nil
 }
 
+func (m *mockEventService) ListPage(ctx context.Context, opts event.ListOptions, cursor string) ([]*event.Event, string, error) {
+	return []*event.Event{}, "", nil
+}
+
 func (m *mockEventService) Update(ctx context.Context, chatRoomID string, description string) error {
 	return nil
 }
@@ -40,20 +48,89 @@ func (m *mockEventService) Remove(ctx context.Context, chatRoomID string) error
 	return nil
 }
 
+func (m *mockEventService) AddAttendee(ctx context.Context, chatRoomID, userID string) (bool, error) {
+	return false, nil
+}
+
+func (m *mockEventService) RemoveAttendee(ctx context.Context, chatRoomID, userID string) (string, error) {
+	return "", nil
+}
+
+func (m *mockEventService) Transfer(ctx context.Context, chatRoomID, newCreatorID string) error {
+	return nil
+}
+
+func (m *mockEventService) SetImage(ctx context.Context, chatRoomID, imageURL string) error {
+	return nil
+}
+
+func (m *mockEventService) AddComment(ctx context.Context, chatRoomID string, comment event.EventComment) error {
+	return nil
+}
+
+func (m *mockEventService) ToggleShowCreator(ctx context.Context, chatRoomID string) (bool, error) {
+	return false, nil
+}
+
 // mockProfileService is a test double for ProfileService interface.
 type mockProfileService struct{}
 
-func (m *mockProfileService) GetUserProfile(ctx context.Context, userID string) (*userprofile.UserProfile, error) {
-	return &userprofile.UserProfile{DisplayName: "Test User"}, nil
+func (m *mockProfileService) GetUserProfiles(ctx context.Context, userIDs []string) (map[string]*userprofile.UserProfile, error) {
+	return map[string]*userprofile.UserProfile{}, nil
+}
+
+// mockGroupProfileService is a test double for GroupProfileService interface.
+type mockGroupProfileService struct{}
+
+func (m *mockGroupProfileService) GetGroupProfile(ctx context.Context, groupID string) (*groupprofile.GroupProfile, error) {
+	return &groupprofile.GroupProfile{}, nil
+}
+
+func (m *mockGroupProfileService) GroupIDsForUser(ctx context.Context, userID string) ([]string, error) {
+	return nil, nil
+}
+
+// mockFileStorage is a test double for FileStorage interface.
+type mockFileStorage struct{}
+
+func (m *mockFileStorage) Write(ctx context.Context, key, mimetype string, data []byte, expectedGeneration int64) (int64, error) {
+	return 1, nil
+}
+
+func (m *mockFileStorage) GetSignedURL(ctx context.Context, key, method string, ttl time.Duration) (string, error) {
+	return "https://example.com/" + key, nil
+}
+
+// mockForecaster is a test double for Forecaster interface.
+type mockForecaster struct{}
+
+func (m *mockForecaster) Forecast(ctx context.Context, location string, days int) ([]weather.DayForecast, error) {
+	return nil, nil
+}
+
+func (m *mockForecaster) Current(ctx context.Context, location string) (*weather.Conditions, error) {
+	return &weather.Conditions{}, nil
 }
 
 // mockLineClient is a test double for LineClient interface.
 type mockLineClient struct{}
 
-func (m *mockLineClient) SendFlexReply(replyToken string, altText string, flexJSON []byte) error {
+func (m *mockLineClient) SendFlex(ctx context.Context, altText string, flexJSON []byte) error {
 	return nil
 }
 
+func (m *mockLineClient) SendFlexPush(to string, altText string, flexJSON []byte) error {
+	return nil
+}
+
+func (m *mockLineClient) SendPush(to string, text string) error {
+	return nil
+}
+
+func (m *mockLineClient) IsGroupMember(ctx context.Context, groupID, userID string) (bool, error) {
+	return true, nil
+}
+
 // =============================================================================
 // NewTools() Tests
 // =============================================================================
@@ -68,12 +145,12 @@ func TestNewTools(t *testing.T) {
 		listLimit := 5
 
 		// When: NewTools is called
-		tools, err := eventtoolset.NewTools(eventService, lineClient, profileService, listMaxPeriodDays, listLimit, slog.New(slog.DiscardHandler))
+		tools, err := eventtoolset.NewTools(eventService, lineClient, profileService, &mockGroupProfileService{}, &mockFileStorage{}, &mockForecaster{}, eventtoolset.ToolsConfig{TextLimits: eventtoolset.TextLimits{MaxTitle: 200, MaxDescription: 2000}, ListMaxPeriodDays: listMaxPeriodDays, ListLimit: listLimit}, slog.New(slog.DiscardHandler))
 
-		// Then: Should return 4 tools without error
+		// Then: Should return 18 tools without error
 		require.NoError(t, err)
 		require.NotNil(t, tools)
-		assert.Len(t, tools, 4, "should return exactly 4 tools")
+		assert.Len(t, tools, 18, "should return exactly 18 tools")
 
 		// Verify tool names
 		toolNames := make(map[string]bool)
@@ -88,6 +165,20 @@ func TestNewTools(t *testing.T) {
 		assert.True(t, toolNames["list_events"], "should include list_events tool")
 		assert.True(t, toolNames["update_event"], "should include update_event tool")
 		assert.True(t, toolNames["remove_event"], "should include remove_event tool")
+		assert.True(t, toolNames["count_attendees"], "should include count_attendees tool")
+		assert.True(t, toolNames["search_events"], "should include search_events tool")
+		assert.True(t, toolNames["join_event"], "should include join_event tool")
+		assert.True(t, toolNames["cancel_rsvp"], "should include cancel_rsvp tool")
+		assert.True(t, toolNames["export_ics"], "should include export_ics tool")
+		assert.True(t, toolNames["transfer_event"], "should include transfer_event tool")
+		assert.True(t, toolNames["clone_event"], "should include clone_event tool")
+		assert.True(t, toolNames["set_event_image"], "should include set_event_image tool")
+		assert.True(t, toolNames["rsvp_status"], "should include rsvp_status tool")
+		assert.True(t, toolNames["get_event_weather"], "should include get_event_weather tool")
+		assert.True(t, toolNames["get_creator_weather"], "should include get_creator_weather tool")
+		assert.True(t, toolNames["add_comment"], "should include add_comment tool")
+		assert.True(t, toolNames["toggle_show_creator"], "should include toggle_show_creator tool")
+		assert.True(t, toolNames["all_my_events"], "should include all_my_events tool")
 	})
 
 	t.Run("each tool has valid metadata", func(t *testing.T) {
@@ -97,7 +188,7 @@ func TestNewTools(t *testing.T) {
 		profileService := &mockProfileService{}
 
 		// When: NewTools is called
-		tools, err := eventtoolset.NewTools(eventService, lineClient, profileService, 366, 5, slog.New(slog.DiscardHandler))
+		tools, err := eventtoolset.NewTools(eventService, lineClient, profileService, &mockGroupProfileService{}, &mockFileStorage{}, &mockForecaster{}, eventtoolset.ToolsConfig{TextLimits: eventtoolset.TextLimits{MaxTitle: 200, MaxDescription: 2000}, ListMaxPeriodDays: 366, ListLimit: 5}, slog.New(slog.DiscardHandler))
 
 		// Then: Each tool should have valid metadata
 		require.NoError(t, err)
@@ -116,83 +207,143 @@ func TestNewTools(t *testing.T) {
 
 func TestNewTools_ErrorCases(t *testing.T) {
 	tests := []struct {
-		name              string
-		eventService      eventtoolset.EventService
-		lineClient        eventtoolset.LineClient
-		profileService    eventtoolset.UserProfileService
-		listMaxPeriodDays int
-		listLimit         int
-		expectError       string
+		name                string
+		eventService        eventtoolset.EventService
+		lineClient          eventtoolset.LineClient
+		profileService      eventtoolset.UserProfileService
+		groupProfileService eventtoolset.GroupProfileService
+		fileStorage         eventtoolset.FileStorage
+		forecaster          eventtoolset.Forecaster
+		listMaxPeriodDays   int
+		listLimit           int
+		expectError         string
 	}{
 		{
-			name:              "returns error when eventService is nil",
-			eventService:      nil,
-			lineClient:        &mockLineClient{},
-			profileService:    &mockProfileService{},
-			listMaxPeriodDays: 366,
-			listLimit:         5,
-			expectError:       "eventService",
+			name:                "returns error when eventService is nil",
+			eventService:        nil,
+			lineClient:          &mockLineClient{},
+			profileService:      &mockProfileService{},
+			groupProfileService: &mockGroupProfileService{},
+			fileStorage:         &mockFileStorage{},
+			forecaster:          &mockForecaster{},
+			listMaxPeriodDays:   366,
+			listLimit:           5,
+			expectError:         "eventService",
+		},
+		{
+			name:                "returns error when lineClient is nil",
+			eventService:        &mockEventService{},
+			lineClient:          nil,
+			profileService:      &mockProfileService{},
+			groupProfileService: &mockGroupProfileService{},
+			fileStorage:         &mockFileStorage{},
+			forecaster:          &mockForecaster{},
+			listMaxPeriodDays:   366,
+			listLimit:           5,
+			expectError:         "lineClient",
+		},
+		{
+			name:                "returns error when profileService is nil",
+			eventService:        &mockEventService{},
+			lineClient:          &mockLineClient{},
+			profileService:      nil,
+			groupProfileService: &mockGroupProfileService{},
+			fileStorage:         &mockFileStorage{},
+			forecaster:          &mockForecaster{},
+			listMaxPeriodDays:   366,
+			listLimit:           5,
+			expectError:         "userProfileService",
+		},
+		{
+			name:                "returns error when groupProfileService is nil",
+			eventService:        &mockEventService{},
+			lineClient:          &mockLineClient{},
+			profileService:      &mockProfileService{},
+			groupProfileService: nil,
+			fileStorage:         &mockFileStorage{},
+			forecaster:          &mockForecaster{},
+			listMaxPeriodDays:   366,
+			listLimit:           5,
+			expectError:         "groupProfileService",
 		},
 		{
-			name:              "returns error when lineClient is nil",
-			eventService:      &mockEventService{},
-			lineClient:        nil,
-			profileService:    &mockProfileService{},
-			listMaxPeriodDays: 366,
-			listLimit:         5,
-			expectError:       "lineClient",
+			name:                "returns error when fileStorage is nil",
+			eventService:        &mockEventService{},
+			lineClient:          &mockLineClient{},
+			profileService:      &mockProfileService{},
+			groupProfileService: &mockGroupProfileService{},
+			fileStorage:         nil,
+			forecaster:          &mockForecaster{},
+			listMaxPeriodDays:   366,
+			listLimit:           5,
+			expectError:         "fileStorage",
 		},
 		{
-			name:              "returns error when profileService is nil",
-			eventService:      &mockEventService{},
-			lineClient:        &mockLineClient{},
-			profileService:    nil,
-			listMaxPeriodDays: 366,
-			listLimit:         5,
-			expectError:       "userProfileService",
+			name:                "returns error when forecaster is nil",
+			eventService:        &mockEventService{},
+			lineClient:          &mockLineClient{},
+			profileService:      &mockProfileService{},
+			groupProfileService: &mockGroupProfileService{},
+			fileStorage:         &mockFileStorage{},
+			forecaster:          nil,
+			listMaxPeriodDays:   366,
+			listLimit:           5,
+			expectError:         "forecaster",
 		},
 		{
-			name:              "returns error when listMaxPeriodDays is zero",
-			eventService:      &mockEventService{},
-			lineClient:        &mockLineClient{},
-			profileService:    &mockProfileService{},
-			listMaxPeriodDays: 0,
-			listLimit:         5,
-			expectError:       "listMaxPeriodDays",
+			name:                "returns error when listMaxPeriodDays is zero",
+			eventService:        &mockEventService{},
+			lineClient:          &mockLineClient{},
+			profileService:      &mockProfileService{},
+			groupProfileService: &mockGroupProfileService{},
+			fileStorage:         &mockFileStorage{},
+			forecaster:          &mockForecaster{},
+			listMaxPeriodDays:   0,
+			listLimit:           5,
+			expectError:         "listMaxPeriodDays",
 		},
 		{
-			name:              "returns error when listMaxPeriodDays is negative",
-			eventService:      &mockEventService{},
-			lineClient:        &mockLineClient{},
-			profileService:    &mockProfileService{},
-			listMaxPeriodDays: -1,
-			listLimit:         5,
-			expectError:       "listMaxPeriodDays",
+			name:                "returns error when listMaxPeriodDays is negative",
+			eventService:        &mockEventService{},
+			lineClient:          &mockLineClient{},
+			profileService:      &mockProfileService{},
+			groupProfileService: &mockGroupProfileService{},
+			fileStorage:         &mockFileStorage{},
+			forecaster:          &mockForecaster{},
+			listMaxPeriodDays:   -1,
+			listLimit:           5,
+			expectError:         "listMaxPeriodDays",
 		},
 		{
-			name:              "returns error when listLimit is zero",
-			eventService:      &mockEventService{},
-			lineClient:        &mockLineClient{},
-			profileService:    &mockProfileService{},
-			listMaxPeriodDays: 366,
-			listLimit:         0,
-			expectError:       "listLimit",
+			name:                "returns error when listLimit is zero",
+			eventService:        &mockEventService{},
+			lineClient:          &mockLineClient{},
+			profileService:      &mockProfileService{},
+			groupProfileService: &mockGroupProfileService{},
+			fileStorage:         &mockFileStorage{},
+			forecaster:          &mockForecaster{},
+			listMaxPeriodDays:   366,
+			listLimit:           0,
+			expectError:         "listLimit",
 		},
 		{
-			name:              "returns error when listLimit is negative",
-			eventService:      &mockEventService{},
-			lineClient:        &mockLineClient{},
-			profileService:    &mockProfileService{},
-			listMaxPeriodDays: 366,
-			listLimit:         -1,
-			expectError:       "listLimit",
+			name:                "returns error when listLimit is negative",
+			eventService:        &mockEventService{},
+			lineClient:          &mockLineClient{},
+			profileService:      &mockProfileService{},
+			groupProfileService: &mockGroupProfileService{},
+			fileStorage:         &mockFileStorage{},
+			forecaster:          &mockForecaster{},
+			listMaxPeriodDays:   366,
+			listLimit:           -1,
+			expectError:         "listLimit",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// When: NewTools is called with invalid parameters
-			tools, err := eventtoolset.NewTools(tt.eventService, tt.lineClient, tt.profileService, tt.listMaxPeriodDays, tt.listLimit, slog.New(slog.DiscardHandler))
+			tools, err := eventtoolset.NewTools(tt.eventService, tt.lineClient, tt.profileService, tt.groupProfileService, tt.fileStorage, tt.forecaster, eventtoolset.ToolsConfig{TextLimits: eventtoolset.TextLimits{MaxTitle: 200, MaxDescription: 2000}, ListMaxPeriodDays: tt.listMaxPeriodDays, ListLimit: tt.listLimit}, slog.New(slog.DiscardHandler))
 
 			// Then: Should return error and nil tools
 			require.Error(t, err)
@@ -206,7 +357,7 @@ func TestNewTools_ErrorCases(t *testing.T) {
 		lineClient := &mockLineClient{}
 		profileService := &mockProfileService{}
 
-		tools, err := eventtoolset.NewTools(eventService, lineClient, profileService, 366, 5, nil)
+		tools, err := eventtoolset.NewTools(eventService, lineClient, profileService, &mockGroupProfileService{}, &mockFileStorage{}, &mockForecaster{}, eventtoolset.ToolsConfig{TextLimits: eventtoolset.TextLimits{MaxTitle: 200, MaxDescription: 2000}, ListMaxPeriodDays: 366, ListLimit: 5}, nil)
 
 		require.Error(t, err)
 		assert.Nil(t, tools)
@@ -228,27 +379,35 @@ func TestNewTools_EdgeCases(t *testing.T) {
 		listLimit := 1
 
 		// When: NewTools is called
-		tools, err := eventtoolset.NewTools(eventService, lineClient, profileService, listMaxPeriodDays, listLimit, slog.New(slog.DiscardHandler))
+		tools, err := eventtoolset.NewTools(eventService, lineClient, profileService, &mockGroupProfileService{}, &mockFileStorage{}, &mockForecaster{}, eventtoolset.ToolsConfig{TextLimits: eventtoolset.TextLimits{MaxTitle: 200, MaxDescription: 2000}, ListMaxPeriodDays: listMaxPeriodDays, ListLimit: listLimit}, slog.New(slog.DiscardHandler))
 
 		// Then: Should succeed
 		require.NoError(t, err)
-		assert.Len(t, tools, 4)
+		assert.Len(t, tools, 18)
 	})
 
 	t.Run("accepts large configuration values", func(t *testing.T) {
-		// Given: Large valid values
+		// Given: Large valid values (the limit is bounded by the carousel size)
 		eventService := &mockEventService{}
 		lineClient := &mockLineClient{}
 		profileService := &mockProfileService{}
 		listMaxPeriodDays := 10000
-		listLimit := 1000
+		listLimit := card.MaxCarouselSize
 
 		// When: NewTools is called
-		tools, err := eventtoolset.NewTools(eventService, lineClient, profileService, listMaxPeriodDays, listLimit, slog.New(slog.DiscardHandler))
+		tools, err := eventtoolset.NewTools(eventService, lineClient, profileService, &mockGroupProfileService{}, &mockFileStorage{}, &mockForecaster{}, eventtoolset.ToolsConfig{TextLimits: eventtoolset.TextLimits{MaxTitle: 200, MaxDescription: 2000}, ListMaxPeriodDays: listMaxPeriodDays, ListLimit: listLimit}, slog.New(slog.DiscardHandler))
 
 		// Then: Should succeed
 		require.NoError(t, err)
-		assert.Len(t, tools, 4)
+		assert.Len(t, tools, 18)
+	})
+
+	t.Run("rejects a list limit above the configured carousel size", func(t *testing.T) {
+		tools, err := eventtoolset.NewTools(&mockEventService{}, &mockLineClient{}, &mockProfileService{}, &mockGroupProfileService{}, &mockFileStorage{}, &mockForecaster{}, eventtoolset.ToolsConfig{TextLimits: eventtoolset.TextLimits{MaxTitle: 200, MaxDescription: 2000}, ListMaxPeriodDays: 366, ListLimit: 5, CardOptions: []card.Option{card.WithCarouselSize(4)}}, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tools)
+		assert.Contains(t, err.Error(), "limit cannot exceed the carousel size (4)")
 	})
 }
 
@@ -264,7 +423,7 @@ func TestNewTools_ToolInterfaceCompliance(t *testing.T) {
 		profileService := &mockProfileService{}
 
 		// When: NewTools is called
-		tools, err := eventtoolset.NewTools(eventService, lineClient, profileService, 366, 5, slog.New(slog.DiscardHandler))
+		tools, err := eventtoolset.NewTools(eventService, lineClient, profileService, &mockGroupProfileService{}, &mockFileStorage{}, &mockForecaster{}, eventtoolset.ToolsConfig{TextLimits: eventtoolset.TextLimits{MaxTitle: 200, MaxDescription: 2000}, ListMaxPeriodDays: 366, ListLimit: 5}, slog.New(slog.DiscardHandler))
 
 		// Then: All tools should implement the agent.Tool interface
 		require.NoError(t, err)
@@ -274,23 +433,23 @@ func TestNewTools_ToolInterfaceCompliance(t *testing.T) {
 		}
 	})
 
-	t.Run("only list_events implements agent.FinalAction interface", func(t *testing.T) {
+	t.Run("only list_events, search_events, and all_my_events implement agent.FinalAction interface", func(t *testing.T) {
 		// Given: Valid configuration
 		eventService := &mockEventService{}
 		lineClient := &mockLineClient{}
 		profileService := &mockProfileService{}
 
 		// When: NewTools is called
-		tools, err := eventtoolset.NewTools(eventService, lineClient, profileService, 366, 5, slog.New(slog.DiscardHandler))
+		tools, err := eventtoolset.NewTools(eventService, lineClient, profileService, &mockGroupProfileService{}, &mockFileStorage{}, &mockForecaster{}, eventtoolset.ToolsConfig{TextLimits: eventtoolset.TextLimits{MaxTitle: 200, MaxDescription: 2000}, ListMaxPeriodDays: 366, ListLimit: 5}, slog.New(slog.DiscardHandler))
 
-		// Then: Only list_events should implement agent.FinalAction
+		// Then: Only tools that send a Flex Message should implement agent.FinalAction
 		// Others require a follow-up reply tool call
 		require.NoError(t, err)
 		for _, tool := range tools {
 			_, implementsFinalAction := tool.(agent.FinalAction)
-			if tool.Name() == "list_events" {
+			if tool.Name() == "list_events" || tool.Name() == "search_events" || tool.Name() == "all_my_events" {
 				assert.True(t, implementsFinalAction,
-					"list_events should implement agent.FinalAction interface")
+					"tool %s should implement agent.FinalAction interface", tool.Name())
 			} else {
 				assert.False(t, implementsFinalAction,
 					"tool %s should NOT implement agent.FinalAction interface", tool.Name())
@@ -311,36 +470,36 @@ func TestNewTools_ReturnOrder(t *testing.T) {
 		profileService := &mockProfileService{}
 
 		// When: NewTools is called multiple times
-		tools1, err1 := eventtoolset.NewTools(eventService, lineClient, profileService, 366, 5, slog.New(slog.DiscardHandler))
+		tools1, err1 := eventtoolset.NewTools(eventService, lineClient, profileService, &mockGroupProfileService{}, &mockFileStorage{}, &mockForecaster{}, eventtoolset.ToolsConfig{TextLimits: eventtoolset.TextLimits{MaxTitle: 200, MaxDescription: 2000}, ListMaxPeriodDays: 366, ListLimit: 5}, slog.New(slog.DiscardHandler))
 		require.NoError(t, err1)
 
-		tools2, err2 := eventtoolset.NewTools(eventService, lineClient, profileService, 366, 5, slog.New(slog.DiscardHandler))
+		tools2, err2 := eventtoolset.NewTools(eventService, lineClient, profileService, &mockGroupProfileService{}, &mockFileStorage{}, &mockForecaster{}, eventtoolset.ToolsConfig{TextLimits: eventtoolset.TextLimits{MaxTitle: 200, MaxDescription: 2000}, ListMaxPeriodDays: 366, ListLimit: 5}, slog.New(slog.DiscardHandler))
 		require.NoError(t, err2)
 
 		// Then: Tools should be returned in the same order
-		require.Len(t, tools1, 4)
-		require.Len(t, tools2, 4)
-		for i := range 4 {
+		require.Len(t, tools1, 18)
+		require.Len(t, tools2, 18)
+		for i := range 18 {
 			assert.Equal(t, tools1[i].Name(), tools2[i].Name(),
 				"tool at index %d should have the same name", i)
 		}
 	})
 
-	t.Run("expected tool order is create, list, update, remove", func(t *testing.T) {
+	t.Run("expected tool order is create, list, update, remove, count, search, join, cancel, export, transfer, clone", func(t *testing.T) {
 		// Given: Valid configuration
 		eventService := &mockEventService{}
 		lineClient := &mockLineClient{}
 		profileService := &mockProfileService{}
 
 		// When: NewTools is called
-		tools, err := eventtoolset.NewTools(eventService, lineClient, profileService, 366, 5, slog.New(slog.DiscardHandler))
+		tools, err := eventtoolset.NewTools(eventService, lineClient, profileService, &mockGroupProfileService{}, &mockFileStorage{}, &mockForecaster{}, eventtoolset.ToolsConfig{TextLimits: eventtoolset.TextLimits{MaxTitle: 200, MaxDescription: 2000}, ListMaxPeriodDays: 366, ListLimit: 5}, slog.New(slog.DiscardHandler))
 
 		// Then: Tools should follow the expected order
 		require.NoError(t, err)
-		require.Len(t, tools, 4)
+		require.Len(t, tools, 18)
 
 		// Expected order based on implementation
-		expectedOrder := []string{"create_event", "list_events", "update_event", "remove_event"}
+		expectedOrder := []string{"create_event", "list_events", "update_event", "remove_event", "count_attendees", "search_events", "join_event", "cancel_rsvp", "export_ics", "transfer_event", "clone_event", "set_event_image", "rsvp_status", "get_event_weather", "get_creator_weather", "add_comment", "toggle_show_creator", "all_my_events"}
 		for i, expectedName := range expectedOrder {
 			assert.Equal(t, expectedName, tools[i].Name(),
 				"tool at index %d should be %s", i, expectedName)
@@ -0,0 +1,194 @@
+package forecast
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/clock"
+	"yuruppu/internal/event"
+	"yuruppu/internal/line"
+	"yuruppu/internal/toolset/weather"
+)
+
+//go:embed parameters.json
+var parametersSchema []byte
+
+//go:embed response.json
+var responseSchema []byte
+
+// dateLayout is the layout of dates in the response and in weather.DayForecast.
+const dateLayout = "2006-01-02"
+
+// EventService provides access to event operations.
+type EventService interface {
+	Get(ctx context.Context, chatRoomID string) (*event.Event, error)
+}
+
+// Forecaster provides daily weather forecasts.
+// Errors are returned from the tool as is, so they should be classified like those of weather.Provider.
+type Forecaster interface {
+	Forecast(ctx context.Context, location string, days int) ([]weather.DayForecast, error)
+}
+
+// Tool implements the get_event_weather tool for forecasting the weather on an event's date at its venue.
+type Tool struct {
+	eventService EventService
+	forecaster   Forecaster
+	logger       *slog.Logger
+}
+
+// New creates a new get_event_weather tool.
+func New(eventService EventService, forecaster Forecaster, logger *slog.Logger) (*Tool, error) {
+	if eventService == nil {
+		return nil, errors.New("eventService cannot be nil")
+	}
+	if forecaster == nil {
+		return nil, errors.New("forecaster cannot be nil")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil")
+	}
+	return &Tool{
+		eventService: eventService,
+		forecaster:   forecaster,
+		logger:       logger,
+	}, nil
+}
+
+// Name returns the tool name.
+func (t *Tool) Name() string {
+	return "get_event_weather"
+}
+
+// Description returns a description for the LLM.
+func (t *Tool) Description() string {
+	return "Use this tool to answer what the weather will be like for an event. Returns the forecast at the event's venue on the day it starts, or reports that the date is too far ahead to forecast yet."
+}
+
+// ParametersJsonSchema returns the JSON Schema for input parameters.
+func (t *Tool) ParametersJsonSchema() []byte {
+	return parametersSchema
+}
+
+// ResponseJsonSchema returns the JSON Schema for the response.
+func (t *Tool) ResponseJsonSchema() []byte {
+	return responseSchema
+}
+
+// Callback returns the forecast for an event's date and venue.
+func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]any, error) {
+	chatRoomID, ok := line.SourceIDFromContext(ctx)
+	if !ok {
+		t.logger.ErrorContext(ctx, "source ID not found in context")
+		return nil, agent.NewSystemError("internal error", nil)
+	}
+	if chatRoomIDArg, ok := args["chat_room_id"]; ok {
+		chatRoomID, ok = chatRoomIDArg.(string)
+		if !ok || chatRoomID == "" {
+			return agent.Invalid("invalid chat_room_id"), nil
+		}
+	}
+
+	ev, err := t.eventService.Get(ctx, chatRoomID)
+	if err != nil {
+		if errors.As(err, new(*event.NotFoundError)) {
+			return map[string]any{
+				"status": "not_found",
+			}, nil
+		}
+		t.logger.ErrorContext(ctx, "failed to get event", slog.String("chatRoomID", chatRoomID), slog.Any("error", err))
+		return nil, agent.NewSystemError("failed to get event", err)
+	}
+	if ev.Venue == "" {
+		return nil, agent.NewUserError("the event has no venue, so its weather cannot be forecast")
+	}
+
+	// Days are counted in the event's timezone so that the date matches the one on its card
+	loc := ev.Location()
+	start := ev.StartTime.In(loc)
+	eventDate := dateOf(start)
+	// Rounded because a day is not 24 hours across a daylight saving change
+	daysAhead := int(math.Round(eventDate.Sub(dateOf(clock.Now(ctx).In(loc))).Hours() / 24))
+	if daysAhead < 0 {
+		return nil, agent.NewUserError("the event has already taken place")
+	}
+
+	result := map[string]any{
+		"title":      ev.Title,
+		"venue":      ev.Venue,
+		"event_date": eventDate.Format(dateLayout),
+	}
+	beyondHorizon := func() map[string]any {
+		result["status"] = "beyond_horizon"
+		result["forecast_available_from"] = eventDate.AddDate(0, 0, 1-weather.MaxForecastDays).Format(dateLayout)
+		return result
+	}
+	if daysAhead >= weather.MaxForecastDays {
+		return beyondHorizon(), nil
+	}
+
+	days, err := t.forecaster.Forecast(ctx, ev.Venue, daysAhead+1)
+	if err != nil {
+		return nil, err
+	}
+	for _, day := range days {
+		if day.Date == eventDate.Format(dateLayout) {
+			result["status"] = "ok"
+			result["forecast"] = buildForecast(day, start)
+			return result, nil
+		}
+	}
+
+	// The provider's day boundaries follow the venue, which can lag the event's timezone
+	t.logger.WarnContext(ctx, "forecast does not cover the event date",
+		slog.String("chatRoomID", chatRoomID),
+		slog.String("eventDate", eventDate.Format(dateLayout)),
+		slog.Int("days", len(days)),
+	)
+	return beyondHorizon(), nil
+}
+
+// dateOf returns midnight of t's date in t's location.
+func dateOf(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// buildForecast converts day into the response, adding the hourly conditions closest before start.
+func buildForecast(day weather.DayForecast, start time.Time) map[string]any {
+	forecast := map[string]any{
+		"max_temp_c": day.MaxTempC,
+		"min_temp_c": day.MinTempC,
+		"avg_temp_c": day.AvgTempC,
+	}
+
+	startHHMM := start.Hour()*100 + start.Minute()
+	var atStart *weather.Conditions
+	atStartHHMM := -1
+	for i := range day.Hourly {
+		hhmm, err := strconv.Atoi(day.Hourly[i].Time)
+		if err != nil || hhmm > startHHMM || hhmm <= atStartHHMM {
+			continue
+		}
+		atStart, atStartHHMM = &day.Hourly[i], hhmm
+	}
+	if atStart != nil {
+		conditions := map[string]any{
+			"time":   atStart.Time,
+			"temp_c": atStart.TempC,
+		}
+		if atStart.Condition != "" {
+			conditions["condition"] = atStart.Condition
+		}
+		if atStart.RainChance != "" {
+			conditions["rain_chance"] = atStart.RainChance
+		}
+		forecast["at_start"] = conditions
+	}
+	return forecast
+}
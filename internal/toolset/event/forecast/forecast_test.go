@@ -0,0 +1,337 @@
+package forecast_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+	"yuruppu/internal/clock"
+	"yuruppu/internal/event"
+	"yuruppu/internal/line"
+	"yuruppu/internal/toolset/event/forecast"
+	"yuruppu/internal/toolset/weather"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// jst is the default event timezone as a fixed zone.
+var jst = time.FixedZone("Asia/Tokyo", 9*60*60)
+
+// =============================================================================
+// New() Tests
+// =============================================================================
+
+func TestNew(t *testing.T) {
+	t.Run("creates tool with valid dependencies", func(t *testing.T) {
+		tool, err := forecast.New(&mockEventService{}, &mockForecaster{}, slog.New(slog.DiscardHandler))
+
+		require.NoError(t, err)
+		require.NotNil(t, tool)
+		assert.Equal(t, "get_event_weather", tool.Name())
+	})
+
+	t.Run("returns error when eventService is nil", func(t *testing.T) {
+		tool, err := forecast.New(nil, &mockForecaster{}, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "eventService cannot be nil")
+	})
+
+	t.Run("returns error when forecaster is nil", func(t *testing.T) {
+		tool, err := forecast.New(&mockEventService{}, nil, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "forecaster cannot be nil")
+	})
+
+	t.Run("returns error when logger is nil", func(t *testing.T) {
+		tool, err := forecast.New(&mockEventService{}, &mockForecaster{}, nil)
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "logger cannot be nil")
+	})
+}
+
+// =============================================================================
+// Callback() Tests
+// =============================================================================
+
+func TestTool_Callback(t *testing.T) {
+	now := time.Date(2026, 5, 10, 20, 0, 0, 0, jst)
+
+	t.Run("returns the forecast for an event within the forecast horizon", func(t *testing.T) {
+		service := &mockEventService{
+			getEvent: &event.Event{
+				ChatRoomID: "group-123",
+				Title:      "BBQ",
+				Venue:      "Osaka",
+				StartTime:  time.Date(2026, 5, 11, 13, 30, 0, 0, jst),
+			},
+		}
+		forecaster := &mockForecaster{
+			days: []weather.DayForecast{
+				{Date: "2026-05-10", MaxTempC: "20"},
+				{
+					Date:     "2026-05-11",
+					MaxTempC: "24",
+					MinTempC: "15",
+					AvgTempC: "19",
+					Hourly: []weather.Conditions{
+						{Time: "900", TempC: "18", Condition: "Cloudy"},
+						{Time: "1200", TempC: "22", Condition: "Sunny", RainChance: "10"},
+						{Time: "1500", TempC: "23", Condition: "Rain", RainChance: "80"},
+					},
+				},
+			},
+		}
+		tool, err := forecast.New(service, forecaster, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(withContext(t.Context(), now), map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Osaka", forecaster.lastLocation)
+		assert.Equal(t, 2, forecaster.lastDays)
+		assert.Equal(t, map[string]any{
+			"status":     "ok",
+			"title":      "BBQ",
+			"venue":      "Osaka",
+			"event_date": "2026-05-11",
+			"forecast": map[string]any{
+				"max_temp_c": "24",
+				"min_temp_c": "15",
+				"avg_temp_c": "19",
+				"at_start": map[string]any{
+					"time":        "1200",
+					"temp_c":      "22",
+					"condition":   "Sunny",
+					"rain_chance": "10",
+				},
+			},
+		}, result)
+	})
+
+	t.Run("counts days in the event's timezone", func(t *testing.T) {
+		// 16:00 UTC on May 10 is already May 11 in Tokyo, so the event is today
+		service := &mockEventService{
+			getEvent: &event.Event{
+				ChatRoomID: "group-123",
+				Venue:      "Tokyo",
+				StartTime:  time.Date(2026, 5, 11, 10, 0, 0, 0, jst),
+			},
+		}
+		forecaster := &mockForecaster{
+			days: []weather.DayForecast{{Date: "2026-05-11", MaxTempC: "21"}},
+		}
+		tool, err := forecast.New(service, forecaster, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(withContext(t.Context(), time.Date(2026, 5, 10, 16, 0, 0, 0, time.UTC)), map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, forecaster.lastDays)
+		assert.Equal(t, "ok", result["status"])
+		assert.NotContains(t, result["forecast"], "at_start")
+	})
+
+	t.Run("returns beyond_horizon for an event too far ahead", func(t *testing.T) {
+		service := &mockEventService{
+			getEvent: &event.Event{
+				ChatRoomID: "group-123",
+				Title:      "Hike",
+				Venue:      "Nagano",
+				StartTime:  time.Date(2026, 5, 20, 9, 0, 0, 0, jst),
+			},
+		}
+		forecaster := &mockForecaster{}
+		tool, err := forecast.New(service, forecaster, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(withContext(t.Context(), now), map[string]any{})
+
+		require.NoError(t, err)
+		assert.False(t, forecaster.called, "should not fetch a forecast that cannot cover the date")
+		assert.Equal(t, map[string]any{
+			"status":                  "beyond_horizon",
+			"title":                   "Hike",
+			"venue":                   "Nagano",
+			"event_date":              "2026-05-20",
+			"forecast_available_from": "2026-05-18",
+		}, result)
+	})
+
+	t.Run("returns beyond_horizon when the forecast does not cover the event date", func(t *testing.T) {
+		service := &mockEventService{
+			getEvent: &event.Event{
+				ChatRoomID: "group-123",
+				Venue:      "Honolulu",
+				StartTime:  time.Date(2026, 5, 12, 9, 0, 0, 0, jst),
+			},
+		}
+		forecaster := &mockForecaster{
+			days: []weather.DayForecast{{Date: "2026-05-09"}, {Date: "2026-05-10"}, {Date: "2026-05-11"}},
+		}
+		tool, err := forecast.New(service, forecaster, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(withContext(t.Context(), now), map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, "beyond_horizon", result["status"])
+		assert.Equal(t, "2026-05-10", result["forecast_available_from"])
+	})
+
+	t.Run("uses chat_room_id argument when provided", func(t *testing.T) {
+		service := &mockEventService{
+			getEvent: &event.Event{
+				ChatRoomID: "group-456",
+				Venue:      "Tokyo",
+				StartTime:  time.Date(2026, 5, 10, 21, 0, 0, 0, jst),
+			},
+		}
+		forecaster := &mockForecaster{
+			days: []weather.DayForecast{{Date: "2026-05-10"}},
+		}
+		tool, err := forecast.New(service, forecaster, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(withContext(t.Context(), now), map[string]any{"chat_room_id": "group-456"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "group-456", service.lastGetChatRoomID)
+		assert.Equal(t, "ok", result["status"])
+	})
+
+	t.Run("returns not_found when there is no event", func(t *testing.T) {
+		service := &mockEventService{getErr: &event.NotFoundError{ChatRoomID: "group-123"}}
+		tool, err := forecast.New(service, &mockForecaster{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(withContext(t.Context(), now), map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"status": "not_found"}, result)
+	})
+
+	t.Run("returns error when the event has no venue", func(t *testing.T) {
+		service := &mockEventService{
+			getEvent: &event.Event{
+				ChatRoomID: "group-123",
+				StartTime:  time.Date(2026, 5, 11, 13, 0, 0, 0, jst),
+			},
+		}
+		forecaster := &mockForecaster{}
+		tool, err := forecast.New(service, forecaster, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(withContext(t.Context(), now), map[string]any{})
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+		assert.Contains(t, err.Error(), "no venue")
+		assert.False(t, forecaster.called)
+	})
+
+	t.Run("returns error when the event has already taken place", func(t *testing.T) {
+		service := &mockEventService{
+			getEvent: &event.Event{
+				ChatRoomID: "group-123",
+				Venue:      "Tokyo",
+				StartTime:  time.Date(2026, 5, 9, 13, 0, 0, 0, jst),
+			},
+		}
+		tool, err := forecast.New(service, &mockForecaster{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(withContext(t.Context(), now), map[string]any{})
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+		assert.Contains(t, err.Error(), "already taken place")
+	})
+
+	t.Run("returns forecaster error as is", func(t *testing.T) {
+		service := &mockEventService{
+			getEvent: &event.Event{
+				ChatRoomID: "group-123",
+				Venue:      "Atlantis",
+				StartTime:  time.Date(2026, 5, 11, 13, 0, 0, 0, jst),
+			},
+		}
+		forecaster := &mockForecaster{err: errors.New("location not found")}
+		tool, err := forecast.New(service, forecaster, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(withContext(t.Context(), now), map[string]any{})
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+		assert.Equal(t, "location not found", err.Error())
+	})
+
+	t.Run("returns error when event service fails", func(t *testing.T) {
+		service := &mockEventService{getErr: errors.New("storage down")}
+		tool, err := forecast.New(service, &mockForecaster{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(withContext(t.Context(), now), map[string]any{})
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+		assert.Equal(t, "failed to get event", err.Error())
+	})
+
+	t.Run("returns error when source ID is missing", func(t *testing.T) {
+		tool, err := forecast.New(&mockEventService{}, &mockForecaster{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(t.Context(), map[string]any{})
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+		assert.Equal(t, "internal error", err.Error())
+	})
+}
+
+// =============================================================================
+// Helpers
+// =============================================================================
+
+func withContext(ctx context.Context, now time.Time) context.Context {
+	return clock.WithNow(line.WithSourceID(ctx, "group-123"), now)
+}
+
+// =============================================================================
+// Mocks
+// =============================================================================
+
+type mockEventService struct {
+	getEvent          *event.Event
+	getErr            error
+	lastGetChatRoomID string
+}
+
+func (m *mockEventService) Get(ctx context.Context, chatRoomID string) (*event.Event, error) {
+	m.lastGetChatRoomID = chatRoomID
+	return m.getEvent, m.getErr
+}
+
+type mockForecaster struct {
+	days         []weather.DayForecast
+	err          error
+	called       bool
+	lastLocation string
+	lastDays     int
+}
+
+func (m *mockForecaster) Forecast(ctx context.Context, location string, days int) ([]weather.DayForecast, error) {
+	m.called = true
+	m.lastLocation = location
+	m.lastDays = days
+	return m.days, m.err
+}
@@ -0,0 +1,127 @@
+package ics
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"log/slog"
+	"time"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/clock"
+	"yuruppu/internal/event"
+	"yuruppu/internal/line"
+
+	"github.com/google/uuid"
+)
+
+//go:embed parameters.json
+var parametersSchema []byte
+
+//go:embed response.json
+var responseSchema []byte
+
+// downloadURLTTL is how long the returned download URL stays valid.
+const downloadURLTTL = 24 * time.Hour
+
+// EventService provides access to event operations.
+type EventService interface {
+	Get(ctx context.Context, chatRoomID string) (*event.Event, error)
+}
+
+// Storage stores exported files and issues download URLs for them.
+type Storage interface {
+	Write(ctx context.Context, key, mimetype string, data []byte, expectedGeneration int64) (newGeneration int64, err error)
+	GetSignedURL(ctx context.Context, key, method string, ttl time.Duration) (string, error)
+}
+
+// Tool implements the export_ics tool for adding events to a calendar app.
+type Tool struct {
+	eventService EventService
+	storage      Storage
+	logger       *slog.Logger
+}
+
+// New creates a new export_ics tool.
+func New(eventService EventService, storage Storage, logger *slog.Logger) (*Tool, error) {
+	if eventService == nil {
+		return nil, errors.New("eventService cannot be nil")
+	}
+	if storage == nil {
+		return nil, errors.New("storage cannot be nil")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil")
+	}
+	return &Tool{
+		eventService: eventService,
+		storage:      storage,
+		logger:       logger,
+	}, nil
+}
+
+// Name returns the tool name.
+func (t *Tool) Name() string {
+	return "export_ics"
+}
+
+// Description returns a description for the LLM.
+func (t *Tool) Description() string {
+	return "Use this tool when the user wants to add an event to their calendar. Returns a download URL of an .ics file."
+}
+
+// ParametersJsonSchema returns the JSON Schema for input parameters.
+func (t *Tool) ParametersJsonSchema() []byte {
+	return parametersSchema
+}
+
+// ResponseJsonSchema returns the JSON Schema for the response.
+func (t *Tool) ResponseJsonSchema() []byte {
+	return responseSchema
+}
+
+// Callback renders the event as an .ics file, stores it, and returns a download URL.
+func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]any, error) {
+	chatRoomID, ok := line.SourceIDFromContext(ctx)
+	if !ok {
+		t.logger.ErrorContext(ctx, "source ID not found in context")
+		return nil, agent.NewSystemError("internal error", nil)
+	}
+	if chatRoomIDArg, ok := args["chat_room_id"]; ok {
+		chatRoomID, ok = chatRoomIDArg.(string)
+		if !ok || chatRoomID == "" {
+			return agent.Invalid("invalid chat_room_id"), nil
+		}
+	}
+
+	ev, err := t.eventService.Get(ctx, chatRoomID)
+	if err != nil {
+		if errors.As(err, new(*event.NotFoundError)) {
+			return map[string]any{"status": "not_found"}, nil
+		}
+		t.logger.ErrorContext(ctx, "failed to get event", slog.String("chatRoomID", chatRoomID), slog.Any("error", err))
+		return nil, agent.NewSystemError("failed to get event", err)
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		t.logger.ErrorContext(ctx, "failed to generate file ID", slog.Any("error", err))
+		return nil, agent.NewSystemError("internal error", err)
+	}
+	key := id.String() + ".ics"
+
+	if _, err := t.storage.Write(ctx, key, "text/calendar", Render(ev, clock.Now(ctx)), 0); err != nil {
+		t.logger.ErrorContext(ctx, "failed to write ics file", slog.String("key", key), slog.Any("error", err))
+		return nil, agent.NewSystemError("failed to export event", err)
+	}
+
+	url, err := t.storage.GetSignedURL(ctx, key, "GET", downloadURLTTL)
+	if err != nil {
+		t.logger.ErrorContext(ctx, "failed to get signed URL", slog.String("key", key), slog.Any("error", err))
+		return nil, agent.NewSystemError("failed to export event", err)
+	}
+
+	return map[string]any{
+		"status": "ok",
+		"url":    url,
+	}, nil
+}
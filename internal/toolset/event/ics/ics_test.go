@@ -0,0 +1,198 @@
+package ics_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+	"yuruppu/internal/event"
+	"yuruppu/internal/line"
+	"yuruppu/internal/toolset/event/ics"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// New() Tests
+// =============================================================================
+
+func TestNew(t *testing.T) {
+	t.Run("creates tool with valid dependencies", func(t *testing.T) {
+		tool, err := ics.New(&mockEventService{}, &mockStorage{}, slog.New(slog.DiscardHandler))
+
+		require.NoError(t, err)
+		assert.Equal(t, "export_ics", tool.Name())
+	})
+
+	t.Run("returns error when service is nil", func(t *testing.T) {
+		tool, err := ics.New(nil, &mockStorage{}, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "eventService cannot be nil")
+	})
+
+	t.Run("returns error when storage is nil", func(t *testing.T) {
+		tool, err := ics.New(&mockEventService{}, nil, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "storage cannot be nil")
+	})
+
+	t.Run("returns error when logger is nil", func(t *testing.T) {
+		tool, err := ics.New(&mockEventService{}, &mockStorage{}, nil)
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "logger cannot be nil")
+	})
+}
+
+// =============================================================================
+// Callback() Tests
+// =============================================================================
+
+func TestTool_Callback(t *testing.T) {
+	t.Run("stores the ics file and returns a signed URL", func(t *testing.T) {
+		service := &mockEventService{event: testEvent()}
+		storage := &mockStorage{}
+		tool, err := ics.New(service, storage, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := line.WithSourceID(t.Context(), "group-123")
+		result, err := tool.Callback(ctx, map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, "group-123", service.lastChatRoomID)
+		assert.Equal(t, "ok", result["status"])
+		assert.Equal(t, "https://example.com/"+storage.lastKey, result["url"])
+		assert.True(t, strings.HasSuffix(storage.lastKey, ".ics"))
+		assert.Equal(t, "text/calendar", storage.lastMimeType)
+		assert.Equal(t, int64(0), storage.lastExpectedGen)
+		assert.Contains(t, string(storage.lastData), "DTSTART:20260401T010000Z")
+		assert.Equal(t, "GET", storage.lastMethod)
+		assert.Equal(t, 24*time.Hour, storage.lastTTL)
+	})
+
+	t.Run("uses chat_room_id argument when given", func(t *testing.T) {
+		service := &mockEventService{event: testEvent()}
+		tool, err := ics.New(service, &mockStorage{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := line.WithSourceID(t.Context(), "user-1")
+		_, err = tool.Callback(ctx, map[string]any{"chat_room_id": "group-123"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "group-123", service.lastChatRoomID)
+	})
+
+	t.Run("returns not_found without writing when the event does not exist", func(t *testing.T) {
+		service := &mockEventService{err: &event.NotFoundError{ChatRoomID: "group-123"}}
+		storage := &mockStorage{}
+		tool, err := ics.New(service, storage, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := line.WithSourceID(t.Context(), "group-123")
+		result, err := tool.Callback(ctx, map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"status": "not_found"}, result)
+		assert.Empty(t, storage.lastKey)
+	})
+
+	t.Run("returns error when the event lookup fails", func(t *testing.T) {
+		service := &mockEventService{err: errors.New("storage error")}
+		tool, err := ics.New(service, &mockStorage{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := line.WithSourceID(t.Context(), "group-123")
+		_, err = tool.Callback(ctx, map[string]any{})
+
+		require.Error(t, err)
+		assert.Equal(t, "failed to get event", err.Error())
+	})
+
+	t.Run("returns error when the write fails", func(t *testing.T) {
+		service := &mockEventService{event: testEvent()}
+		tool, err := ics.New(service, &mockStorage{writeErr: errors.New("write error")}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := line.WithSourceID(t.Context(), "group-123")
+		_, err = tool.Callback(ctx, map[string]any{})
+
+		require.Error(t, err)
+		assert.Equal(t, "failed to export event", err.Error())
+	})
+
+	t.Run("returns error when signing the URL fails", func(t *testing.T) {
+		service := &mockEventService{event: testEvent()}
+		tool, err := ics.New(service, &mockStorage{urlErr: errors.New("sign error")}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := line.WithSourceID(t.Context(), "group-123")
+		_, err = tool.Callback(ctx, map[string]any{})
+
+		require.Error(t, err)
+		assert.Equal(t, "failed to export event", err.Error())
+	})
+
+	t.Run("returns error when source ID is missing", func(t *testing.T) {
+		tool, err := ics.New(&mockEventService{}, &mockStorage{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		_, err = tool.Callback(t.Context(), map[string]any{})
+
+		require.Error(t, err)
+		assert.Equal(t, "internal error", err.Error())
+	})
+}
+
+// =============================================================================
+// Mocks
+// =============================================================================
+
+type mockEventService struct {
+	event          *event.Event
+	err            error
+	lastChatRoomID string
+}
+
+func (m *mockEventService) Get(ctx context.Context, chatRoomID string) (*event.Event, error) {
+	m.lastChatRoomID = chatRoomID
+	return m.event, m.err
+}
+
+type mockStorage struct {
+	writeErr        error
+	urlErr          error
+	lastKey         string
+	lastMimeType    string
+	lastData        []byte
+	lastExpectedGen int64
+	lastMethod      string
+	lastTTL         time.Duration
+}
+
+func (m *mockStorage) Write(ctx context.Context, key, mimetype string, data []byte, expectedGeneration int64) (int64, error) {
+	if m.writeErr != nil {
+		return 0, m.writeErr
+	}
+	m.lastKey = key
+	m.lastMimeType = mimetype
+	m.lastData = data
+	m.lastExpectedGen = expectedGeneration
+	return 1, nil
+}
+
+func (m *mockStorage) GetSignedURL(ctx context.Context, key, method string, ttl time.Duration) (string, error) {
+	m.lastMethod = method
+	m.lastTTL = ttl
+	if m.urlErr != nil {
+		return "", m.urlErr
+	}
+	return "https://example.com/" + key, nil
+}
@@ -0,0 +1,86 @@
+package ics
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"yuruppu/internal/event"
+)
+
+// maxLineOctets is the line length limit from RFC 5545 section 3.1, excluding CRLF.
+const maxLineOctets = 75
+
+// utcFormat is the RFC 5545 UTC date-time format.
+const utcFormat = "20060102T150405Z"
+
+// EventUID returns a UID for ev that is stable across exports of the same event.
+// It is derived from the chat room and start time, so a new event in the same chat room gets a new UID.
+func EventUID(ev *event.Event) string {
+	sum := sha256.Sum256([]byte(ev.ChatRoomID + "\x00" + strconv.FormatInt(ev.StartTime.Unix(), 10)))
+	return hex.EncodeToString(sum[:16]) + "@yuruppu"
+}
+
+// Render returns ev as an iCalendar object with a single VEVENT.
+// Times are written in UTC; the calendar is tagged with the time zone the event is held in.
+// now is used for DTSTAMP.
+func Render(ev *event.Event, now time.Time) []byte {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Yuruppu//Events//JA",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-TIMEZONE:" + ev.Location().String(),
+		"BEGIN:VEVENT",
+		"UID:" + EventUID(ev),
+		"DTSTAMP:" + now.UTC().Format(utcFormat),
+		"DTSTART:" + ev.StartTime.UTC().Format(utcFormat),
+		"DTEND:" + ev.EndTime.UTC().Format(utcFormat),
+		"SUMMARY:" + escapeText(ev.Title),
+	}
+	if ev.Description != "" {
+		lines = append(lines, "DESCRIPTION:"+escapeText(ev.Description))
+	}
+	lines = append(lines, "END:VEVENT", "END:VCALENDAR")
+
+	var b strings.Builder
+	for _, line := range lines {
+		writeFolded(&b, line)
+	}
+	return []byte(b.String())
+}
+
+// escapeText escapes a TEXT property value (RFC 5545 section 3.3.11).
+func escapeText(s string) string {
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	)
+	return r.Replace(s)
+}
+
+// writeFolded writes line with CRLF, folding it into continuation lines of at most maxLineOctets octets.
+// Lines are only split between UTF-8 characters.
+func writeFolded(b *strings.Builder, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		fmt.Fprintf(b, "%s\r\n ", line[:cut])
+		line = line[cut:]
+		// Continuation lines start with a space, which counts toward the limit
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
@@ -0,0 +1,136 @@
+package ics_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+	"yuruppu/internal/event"
+	"yuruppu/internal/toolset/event/ics"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var jst = time.FixedZone("JST", 9*60*60)
+
+func testEvent() *event.Event {
+	return &event.Event{
+		ChatRoomID:  "group-123",
+		Title:       "花見",
+		StartTime:   time.Date(2026, 4, 1, 10, 0, 0, 0, jst),
+		EndTime:     time.Date(2026, 4, 1, 12, 30, 0, 0, jst),
+		Description: "上野公園で集合",
+	}
+}
+
+// =============================================================================
+// Render Tests
+// =============================================================================
+
+func TestRender(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("writes DTSTART and DTEND in UTC", func(t *testing.T) {
+		out := string(ics.Render(testEvent(), now))
+
+		assert.Contains(t, out, "DTSTART:20260401T010000Z\r\n")
+		assert.Contains(t, out, "DTEND:20260401T033000Z\r\n")
+		assert.Contains(t, out, "DTSTAMP:20260301T000000Z\r\n")
+	})
+
+	t.Run("wraps a single VEVENT in a VCALENDAR", func(t *testing.T) {
+		out := string(ics.Render(testEvent(), now))
+
+		assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+		assert.True(t, strings.HasSuffix(out, "END:VEVENT\r\nEND:VCALENDAR\r\n"))
+		assert.Equal(t, 1, strings.Count(out, "BEGIN:VEVENT"))
+		assert.Contains(t, out, "SUMMARY:花見\r\n")
+		assert.Contains(t, out, "DESCRIPTION:上野公園で集合\r\n")
+		assert.Contains(t, out, "X-WR-TIMEZONE:Asia/Tokyo\r\n")
+	})
+
+	t.Run("tags the calendar with the event timezone", func(t *testing.T) {
+		ev := testEvent()
+		ev.Timezone = "Europe/London"
+
+		out := string(ics.Render(ev, now))
+
+		assert.Contains(t, out, "X-WR-TIMEZONE:Europe/London\r\n")
+		assert.Contains(t, out, "DTSTART:20260401T010000Z\r\n")
+	})
+
+	t.Run("includes the event UID", func(t *testing.T) {
+		ev := testEvent()
+
+		out := string(ics.Render(ev, now))
+
+		assert.Contains(t, out, "UID:"+ics.EventUID(ev)+"\r\n")
+	})
+
+	t.Run("omits DESCRIPTION when empty", func(t *testing.T) {
+		ev := testEvent()
+		ev.Description = ""
+
+		out := string(ics.Render(ev, now))
+
+		assert.NotContains(t, out, "DESCRIPTION")
+	})
+
+	t.Run("escapes special characters in text", func(t *testing.T) {
+		ev := testEvent()
+		ev.Title = `a,b;c\d`
+		ev.Description = "line1\nline2"
+
+		out := string(ics.Render(ev, now))
+
+		assert.Contains(t, out, `SUMMARY:a\,b\;c\\d`+"\r\n")
+		assert.Contains(t, out, `DESCRIPTION:line1\nline2`+"\r\n")
+	})
+
+	t.Run("folds long lines without splitting characters", func(t *testing.T) {
+		ev := testEvent()
+		ev.Description = strings.Repeat("あ", 100)
+
+		out := string(ics.Render(ev, now))
+
+		for line := range strings.SplitSeq(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+			assert.LessOrEqual(t, len(line), 75, "line too long: %q", line)
+			assert.True(t, utf8.ValidString(line), "line splits a character: %q", line)
+		}
+		unfolded := strings.ReplaceAll(out, "\r\n ", "")
+		assert.Contains(t, unfolded, "DESCRIPTION:"+strings.Repeat("あ", 100)+"\r\n")
+	})
+}
+
+// =============================================================================
+// EventUID Tests
+// =============================================================================
+
+func TestEventUID(t *testing.T) {
+	t.Run("is stable for the same event", func(t *testing.T) {
+		ev := testEvent()
+		updated := testEvent()
+		updated.Description = "changed"
+
+		assert.Equal(t, ics.EventUID(ev), ics.EventUID(testEvent()))
+		assert.Equal(t, ics.EventUID(ev), ics.EventUID(updated))
+	})
+
+	t.Run("differs for another chat room or start time", func(t *testing.T) {
+		other := testEvent()
+		other.ChatRoomID = "group-456"
+		later := testEvent()
+		later.StartTime = later.StartTime.Add(24 * time.Hour)
+
+		assert.NotEqual(t, ics.EventUID(testEvent()), ics.EventUID(other))
+		assert.NotEqual(t, ics.EventUID(testEvent()), ics.EventUID(later))
+	})
+
+	t.Run("has a domain suffix", func(t *testing.T) {
+		uid := ics.EventUID(testEvent())
+
+		require.True(t, strings.HasSuffix(uid, "@yuruppu"))
+		assert.Len(t, strings.TrimSuffix(uid, "@yuruppu"), 32)
+	})
+}
@@ -0,0 +1,134 @@
+package image
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"log/slog"
+	"net/url"
+	"strings"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/event"
+	"yuruppu/internal/line"
+)
+
+//go:embed parameters.json
+var parametersSchema []byte
+
+//go:embed response.json
+var responseSchema []byte
+
+// maxImageURLLength is the longest image URL LINE accepts in a Flex Message.
+const maxImageURLLength = 2000
+
+// EventService provides access to event operations.
+type EventService interface {
+	Get(ctx context.Context, chatRoomID string) (*event.Event, error)
+	SetImage(ctx context.Context, chatRoomID, imageURL string) error
+}
+
+// Tool implements the set_event_image tool for setting an event's cover image.
+type Tool struct {
+	eventService EventService
+	logger       *slog.Logger
+}
+
+// New creates a new set_event_image tool.
+func New(eventService EventService, logger *slog.Logger) (*Tool, error) {
+	if eventService == nil {
+		return nil, errors.New("eventService cannot be nil")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil")
+	}
+	return &Tool{
+		eventService: eventService,
+		logger:       logger,
+	}, nil
+}
+
+// Name returns the tool name.
+func (t *Tool) Name() string {
+	return "set_event_image"
+}
+
+// Description returns a description for the LLM.
+func (t *Tool) Description() string {
+	return "Use this tool to set the cover image shown on the event card in the current group chat. The image must be an HTTPS URL. Only the event creator can set the image."
+}
+
+// ParametersJsonSchema returns the JSON Schema for input parameters.
+func (t *Tool) ParametersJsonSchema() []byte {
+	return parametersSchema
+}
+
+// ResponseJsonSchema returns the JSON Schema for the response.
+func (t *Tool) ResponseJsonSchema() []byte {
+	return responseSchema
+}
+
+// Callback sets the cover image of the event in the current chat room.
+func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]any, error) {
+	sourceID, ok := line.SourceIDFromContext(ctx)
+	if !ok {
+		t.logger.ErrorContext(ctx, "source ID not found in context")
+		return nil, agent.NewSystemError("internal error", nil)
+	}
+	userID, ok := line.UserIDFromContext(ctx)
+	if !ok {
+		t.logger.ErrorContext(ctx, "user ID not found in context")
+		return nil, agent.NewSystemError("internal error", nil)
+	}
+
+	imageURL, ok := args["image_url"].(string)
+	if !ok {
+		return agent.Invalid("invalid image_url"), nil
+	}
+	imageURL = strings.TrimSpace(imageURL)
+	if !isHTTPSURL(imageURL) {
+		return agent.Invalid("image_url must be an HTTPS URL"), nil
+	}
+
+	// Get existing event to check authorization
+	ev, err := t.eventService.Get(ctx, sourceID)
+	if err != nil {
+		if errors.As(err, new(*event.NotFoundError)) {
+			return nil, agent.NewUserError("event not found")
+		}
+		t.logger.ErrorContext(ctx, "failed to get event", slog.String("chatRoomID", sourceID), slog.Any("error", err))
+		return nil, agent.NewSystemError("failed to get event", err)
+	}
+
+	// Check authorization
+	if ev.CreatorID != userID {
+		return nil, agent.NewUserError("only the event creator can set the event image")
+	}
+
+	if err := t.eventService.SetImage(ctx, sourceID, imageURL); err != nil {
+		t.logger.ErrorContext(ctx, "failed to set event image", slog.Any("error", err))
+		return nil, agent.NewSystemError("failed to set event image", err)
+	}
+
+	return map[string]any{
+		"chat_room_id": sourceID,
+	}, nil
+}
+
+// isHTTPSURL reports whether s is an absolute HTTPS URL that can be embedded in a Flex Message as is.
+// Quotes, backslashes, whitespace, and control characters are rejected because the card templates
+// insert the URL into JSON without escaping.
+func isHTTPSURL(s string) bool {
+	if s == "" || len(s) > maxImageURLLength {
+		return false
+	}
+	for _, r := range s {
+		if r <= ' ' || r == '"' || r == '\\' || r == 0x7f {
+			return false
+		}
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return false
+	}
+	return u.Scheme == "https" && u.Host != "" && u.User == nil
+}
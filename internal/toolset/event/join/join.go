@@ -0,0 +1,96 @@
+package join
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"log/slog"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/event"
+	"yuruppu/internal/line"
+)
+
+//go:embed parameters.json
+var parametersSchema []byte
+
+//go:embed response.json
+var responseSchema []byte
+
+// EventService provides access to event operations.
+type EventService interface {
+	AddAttendee(ctx context.Context, chatRoomID, userID string) (bool, error)
+}
+
+// Tool implements the join_event tool for signing up for an event.
+type Tool struct {
+	eventService EventService
+	logger       *slog.Logger
+}
+
+// New creates a new join_event tool.
+func New(eventService EventService, logger *slog.Logger) (*Tool, error) {
+	if eventService == nil {
+		return nil, errors.New("eventService cannot be nil")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil")
+	}
+	return &Tool{
+		eventService: eventService,
+		logger:       logger,
+	}, nil
+}
+
+// Name returns the tool name.
+func (t *Tool) Name() string {
+	return "join_event"
+}
+
+// Description returns a description for the LLM.
+func (t *Tool) Description() string {
+	return "Use this tool when the user says they will attend the event in the current group chat. Adds the user to the attendees, or to the waitlist when the event is full. Signing up again keeps the user's current place."
+}
+
+// ParametersJsonSchema returns the JSON Schema for input parameters.
+func (t *Tool) ParametersJsonSchema() []byte {
+	return parametersSchema
+}
+
+// ResponseJsonSchema returns the JSON Schema for the response.
+func (t *Tool) ResponseJsonSchema() []byte {
+	return responseSchema
+}
+
+// Callback signs the requesting user up for the event in the current chat room.
+// Only the current chat room is accepted so that users cannot sign up for other groups' events.
+func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]any, error) {
+	chatRoomID, ok := line.SourceIDFromContext(ctx)
+	if !ok {
+		t.logger.ErrorContext(ctx, "source ID not found in context")
+		return nil, agent.NewSystemError("internal error", nil)
+	}
+
+	userID, ok := line.UserIDFromContext(ctx)
+	if !ok {
+		t.logger.ErrorContext(ctx, "user ID not found in context")
+		return nil, agent.NewSystemError("internal error", nil)
+	}
+
+	waitlisted, err := t.eventService.AddAttendee(ctx, chatRoomID, userID)
+	if err != nil {
+		if errors.As(err, new(*event.NotFoundError)) {
+			return map[string]any{"status": "not_found"}, nil
+		}
+		t.logger.ErrorContext(ctx, "failed to add attendee",
+			slog.String("chatRoomID", chatRoomID),
+			slog.String("userID", userID),
+			slog.Any("error", err),
+		)
+		return nil, agent.NewSystemError("failed to join event", err)
+	}
+
+	if waitlisted {
+		return map[string]any{"status": "waitlisted"}, nil
+	}
+	return map[string]any{"status": "attending"}, nil
+}
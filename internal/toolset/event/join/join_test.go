@@ -0,0 +1,201 @@
+package join_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+	"yuruppu/internal/event"
+	"yuruppu/internal/line"
+	"yuruppu/internal/toolset/event/join"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// New() Tests
+// =============================================================================
+
+func TestNew(t *testing.T) {
+	t.Run("creates tool with valid service", func(t *testing.T) {
+		tool, err := join.New(&mockEventService{}, slog.New(slog.DiscardHandler))
+
+		require.NoError(t, err)
+		require.NotNil(t, tool)
+		assert.Equal(t, "join_event", tool.Name())
+	})
+
+	t.Run("returns error when service is nil", func(t *testing.T) {
+		tool, err := join.New(nil, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "eventService cannot be nil")
+	})
+
+	t.Run("returns error when logger is nil", func(t *testing.T) {
+		tool, err := join.New(&mockEventService{}, nil)
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "logger cannot be nil")
+	})
+}
+
+// =============================================================================
+// Callback() Tests
+// =============================================================================
+
+func withContext(ctx context.Context) context.Context {
+	ctx = line.WithSourceID(ctx, "group-123")
+	return line.WithUserID(ctx, "user-1")
+}
+
+func TestTool_Callback(t *testing.T) {
+	t.Run("signs the requesting user up for the current group's event", func(t *testing.T) {
+		service := &mockEventService{}
+		tool, err := join.New(service, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(withContext(t.Context()), map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, "group-123", service.lastChatRoomID)
+		assert.Equal(t, "user-1", service.lastUserID)
+		assert.Equal(t, map[string]any{"status": "attending"}, result)
+	})
+
+	t.Run("returns waitlisted when the event is full", func(t *testing.T) {
+		service := &mockEventService{waitlisted: true}
+		tool, err := join.New(service, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(withContext(t.Context()), map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"status": "waitlisted"}, result)
+	})
+
+	t.Run("returns not_found when the group has no event", func(t *testing.T) {
+		service := &mockEventService{err: &event.NotFoundError{ChatRoomID: "group-123"}}
+		tool, err := join.New(service, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(withContext(t.Context()), map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"status": "not_found"}, result)
+	})
+
+	t.Run("returns error when storage fails", func(t *testing.T) {
+		service := &mockEventService{err: errors.New("storage error")}
+		tool, err := join.New(service, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(withContext(t.Context()), map[string]any{})
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+		assert.Equal(t, "failed to join event", err.Error())
+	})
+
+	t.Run("returns internal error when source ID is missing", func(t *testing.T) {
+		tool, err := join.New(&mockEventService{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := line.WithUserID(t.Context(), "user-1")
+		result, err := tool.Callback(ctx, map[string]any{})
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+		assert.Equal(t, "internal error", err.Error())
+	})
+
+	t.Run("returns internal error when user ID is missing", func(t *testing.T) {
+		tool, err := join.New(&mockEventService{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := line.WithSourceID(t.Context(), "group-123")
+		result, err := tool.Callback(ctx, map[string]any{})
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+		assert.Equal(t, "internal error", err.Error())
+	})
+}
+
+// =============================================================================
+// Integration Tests
+// =============================================================================
+
+func TestTool_Callback_WithEventService(t *testing.T) {
+	t.Run("fills the event and then the waitlist", func(t *testing.T) {
+		svc, err := event.NewService(newMemStorage())
+		require.NoError(t, err)
+		require.NoError(t, svc.Create(t.Context(), &event.Event{
+			ChatRoomID: "group-123",
+			CreatorID:  "user-0",
+			Title:      "Board games",
+			StartTime:  time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+			EndTime:    time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+			Capacity:   1,
+		}))
+		tool, err := join.New(svc, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		first, err := tool.Callback(withContext(t.Context()), map[string]any{})
+		require.NoError(t, err)
+		ctx := line.WithUserID(line.WithSourceID(t.Context(), "group-123"), "user-2")
+		second, err := tool.Callback(ctx, map[string]any{})
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]any{"status": "attending"}, first)
+		assert.Equal(t, map[string]any{"status": "waitlisted"}, second)
+		ev, err := svc.Get(t.Context(), "group-123")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"user-1"}, ev.Attendees)
+		assert.Equal(t, []string{"user-2"}, ev.Waitlist)
+	})
+}
+
+// =============================================================================
+// Mocks
+// =============================================================================
+
+type mockEventService struct {
+	waitlisted     bool
+	err            error
+	lastChatRoomID string
+	lastUserID     string
+}
+
+func (m *mockEventService) AddAttendee(ctx context.Context, chatRoomID, userID string) (bool, error) {
+	m.lastChatRoomID = chatRoomID
+	m.lastUserID = userID
+	return m.waitlisted, m.err
+}
+
+// memStorage is an in-memory event.Storage that honors generations like GCS.
+type memStorage struct {
+	data       map[string][]byte
+	generation map[string]int64
+}
+
+func newMemStorage() *memStorage {
+	return &memStorage{data: make(map[string][]byte), generation: make(map[string]int64)}
+}
+
+func (m *memStorage) Read(ctx context.Context, key string) ([]byte, int64, error) {
+	return m.data[key], m.generation[key], nil
+}
+
+func (m *memStorage) Write(ctx context.Context, key, mimetype string, data []byte, expectedGeneration int64) (int64, error) {
+	if expectedGeneration != m.generation[key] {
+		return 0, errors.New("generation mismatch")
+	}
+	m.data[key] = data
+	m.generation[key]++
+	return m.generation[key], nil
+}
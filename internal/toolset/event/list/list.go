@@ -5,11 +5,15 @@ import (
 	"context"
 	_ "embed"
 	"errors"
+	"fmt"
 	"log/slog"
 	"text/template"
 	"time"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/clock"
 	"yuruppu/internal/event"
 	"yuruppu/internal/line"
+	"yuruppu/internal/toolset/event/card"
 	"yuruppu/internal/userprofile"
 )
 
@@ -22,51 +26,46 @@ var responseSchema []byte
 //go:embed alt.txt
 var altTemplate string
 
-//go:embed flex.json
-var flexTemplate string
-
 // JST is Japan Standard Time location (UTC+9).
 var JST = time.FixedZone("Asia/Tokyo", 9*60*60)
 
-// flexEventData represents template data for a single event in flex message.
-type flexEventData struct {
-	Title       string
-	StartTime   string
-	EndTime     string
-	Fee         string
-	Capacity    int
-	Description string
-	ShowCreator bool
-	CreatorName string
-}
-
 // EventService provides access to event list operations.
 type EventService interface {
-	List(ctx context.Context, opts event.ListOptions) ([]*event.Event, error)
+	ListPage(ctx context.Context, opts event.ListOptions, cursor string) ([]*event.Event, string, error)
 }
 
 // LineClient provides LINE messaging operations.
 type LineClient interface {
-	SendFlexReply(replyToken string, altText string, flexJSON []byte) error
+	SendFlex(ctx context.Context, altText string, flexJSON []byte) error
 }
 
 // UserProfileService provides user profile operations.
 type UserProfileService interface {
-	GetUserProfile(ctx context.Context, userID string) (*userprofile.UserProfile, error)
+	GetUserProfiles(ctx context.Context, userIDs []string) (map[string]*userprofile.UserProfile, error)
+}
+
+// DefaultWindow defines which events list_events shows when neither start nor end is given.
+// The zero value shows events from today 00:00 JST onward.
+type DefaultWindow struct {
+	StartOffsetDays int // Days from today to the window start; -1 starts yesterday
+	SpanDays        int // Days the window covers; 0 leaves it open-ended with the usual limit
 }
 
 // Tool implements the list_events tool for retrieving filtered event lists.
 type Tool struct {
-	eventService       EventService
-	lineClient         LineClient
-	userProfileService UserProfileService
-	maxPeriodDays      int
-	limit              int
-	logger             *slog.Logger
+	eventService  EventService
+	lineClient    LineClient
+	renderer      *card.Renderer
+	maxPeriodDays int
+	limit         int
+	defaultWindow DefaultWindow
+	logger        *slog.Logger
 }
 
 // New creates a new list_events tool with the specified service and configuration.
-func New(eventService EventService, lineClient LineClient, userProfileService UserProfileService, maxPeriodDays, limit int, logger *slog.Logger) (*Tool, error) {
+// defaultWindow applies when the LLM gives neither start nor end; its span cannot exceed maxPeriodDays.
+// cardOpts customize the event cards sent as the result.
+func New(eventService EventService, lineClient LineClient, userProfileService UserProfileService, maxPeriodDays, limit int, defaultWindow DefaultWindow, logger *slog.Logger, cardOpts ...card.Option) (*Tool, error) {
 	if eventService == nil {
 		return nil, errors.New("eventService cannot be nil")
 	}
@@ -82,16 +81,30 @@ func New(eventService EventService, lineClient LineClient, userProfileService Us
 	if limit <= 0 {
 		return nil, errors.New("limit must be positive")
 	}
+	if defaultWindow.SpanDays < 0 {
+		return nil, errors.New("defaultWindow.SpanDays cannot be negative")
+	}
+	if defaultWindow.SpanDays > maxPeriodDays {
+		return nil, errors.New("defaultWindow.SpanDays cannot exceed maxPeriodDays")
+	}
 	if logger == nil {
 		return nil, errors.New("logger cannot be nil")
 	}
+	renderer, err := card.NewRenderer(userProfileService, logger, cardOpts...)
+	if err != nil {
+		return nil, err
+	}
+	if limit > renderer.CarouselSize() {
+		return nil, fmt.Errorf("limit cannot exceed the carousel size (%d)", renderer.CarouselSize())
+	}
 	return &Tool{
-		eventService:       eventService,
-		lineClient:         lineClient,
-		userProfileService: userProfileService,
-		maxPeriodDays:      maxPeriodDays,
-		limit:              limit,
-		logger:             logger,
+		eventService:  eventService,
+		lineClient:    lineClient,
+		renderer:      renderer,
+		maxPeriodDays: maxPeriodDays,
+		limit:         limit,
+		defaultWindow: defaultWindow,
+		logger:        logger,
 	}, nil
 }
 
@@ -102,7 +115,7 @@ func (t *Tool) Name() string {
 
 // Description returns a description for the LLM.
 func (t *Tool) Description() string {
-	return "Sends a Flex Message with full event details directly to the chat. When neither 'start' nor 'end' is specified, defaults to showing events from today."
+	return "Sends a Flex Message with full event details directly to the chat. When neither 'start' nor 'end' is specified, defaults to showing " + t.defaultWindow.describe() + "."
 }
 
 // ParametersJsonSchema returns the JSON Schema for input parameters.
@@ -121,14 +134,13 @@ func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]an
 	userID, ok := line.UserIDFromContext(ctx)
 	if !ok {
 		t.logger.ErrorContext(ctx, "user ID not found in context")
-		return nil, errors.New("internal error")
-	}
-	replyToken, ok := line.ReplyTokenFromContext(ctx)
-	if !ok {
-		t.logger.ErrorContext(ctx, "reply token not found in context")
-		return nil, errors.New("internal error")
+		return nil, agent.NewSystemError("internal error", nil)
 	}
 
+	// Resolve "today" once so that every default and parameter in this call agrees
+	now := clock.Now(ctx).In(JST)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, JST)
+
 	// Build ListOptions
 	opts := event.ListOptions{}
 
@@ -136,7 +148,7 @@ func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]an
 	if createdByMeArg, ok := args["created_by_me"]; ok {
 		createdByMe, ok := createdByMeArg.(bool)
 		if !ok {
-			return nil, errors.New("invalid created_by_me")
+			return agent.Invalid("invalid created_by_me"), nil
 		}
 		if createdByMe {
 			opts.CreatorID = &userID
@@ -148,12 +160,12 @@ func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]an
 	if startArg, ok := args["start"]; ok {
 		startStr, ok := startArg.(string)
 		if !ok {
-			return nil, errors.New("invalid start")
+			return agent.Invalid("invalid start"), nil
 		}
-		parsedStart, err := parseTimeParameter(startStr)
+		parsedStart, err := parseTimeParameter(startStr, today)
 		if err != nil {
 			t.logger.ErrorContext(ctx, "invalid start time", slog.Any("error", err))
-			return nil, errors.New("invalid start")
+			return agent.Invalid("invalid start"), nil
 		}
 		start = &parsedStart
 	}
@@ -163,21 +175,25 @@ func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]an
 	if endArg, ok := args["end"]; ok {
 		endStr, ok := endArg.(string)
 		if !ok {
-			return nil, errors.New("invalid end")
+			return agent.Invalid("invalid end"), nil
 		}
-		parsedEnd, err := parseTimeParameter(endStr)
+		parsedEnd, err := parseTimeParameter(endStr, today)
 		if err != nil {
 			t.logger.ErrorContext(ctx, "invalid end time", slog.Any("error", err))
-			return nil, errors.New("invalid end")
+			return agent.Invalid("invalid end"), nil
 		}
 		end = &parsedEnd
 	}
 
-	// FR-012a: Default to today when neither specified
+	// FR-012a: Default to the configured window (today onward unless configured) when neither specified
 	if start == nil && end == nil {
-		now := time.Now().In(JST)
-		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, JST)
-		start = &today
+		windowStart := today.AddDate(0, 0, t.defaultWindow.StartOffsetDays)
+		start = &windowStart
+		if t.defaultWindow.SpanDays > 0 {
+			// end is inclusive, so stop just before the day after the window
+			windowEnd := windowStart.AddDate(0, 0, t.defaultWindow.SpanDays).Add(-time.Nanosecond)
+			end = &windowEnd
+		}
 	}
 
 	opts.Start = start
@@ -187,26 +203,26 @@ func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]an
 	if start != nil && end != nil {
 		// Check end is after start
 		if end.Before(*start) {
-			return nil, errors.New("end time must be after start time")
+			return agent.Invalid("end time must be after start time"), nil
 		}
 		// Check period doesn't exceed maxPeriodDays
 		duration := end.Sub(*start)
 		maxDuration := time.Duration(t.maxPeriodDays) * 24 * time.Hour
 		if duration > maxDuration {
-			return nil, errors.New("period is too long")
+			return agent.Invalid("period is too long"), nil
 		}
-		// No limit when both start and end specified
-		opts.Limit = 0
+		// Show as many as fit in one carousel when both start and end specified
+		opts.Limit = t.renderer.CarouselSize()
 	} else {
 		// Apply limit when only start or end (or neither) specified
 		opts.Limit = t.limit
 	}
 
-	// Retrieve events from service
-	events, err := t.eventService.List(ctx, opts)
+	// Retrieve the first page of events from service
+	events, next, err := t.eventService.ListPage(ctx, opts, "")
 	if err != nil {
 		t.logger.ErrorContext(ctx, "failed to list events", slog.Any("error", err))
-		return nil, errors.New("failed to list events")
+		return nil, agent.NewSystemError("failed to list events", err)
 	}
 
 	// If no events, return no_events status without sending message
@@ -216,69 +232,36 @@ func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]an
 		}, nil
 	}
 
-	// Build template data for each event
-	eventDataList := make([]flexEventData, len(events))
-	for i, ev := range events {
-		eventData := flexEventData{
-			Title:       ev.Title,
-			StartTime:   formatDisplayTime(ev.StartTime),
-			EndTime:     formatDisplayTime(ev.EndTime),
-			Fee:         ev.Fee,
-			Capacity:    ev.Capacity,
-			Description: ev.Description,
-			ShowCreator: ev.ShowCreator,
-		}
-
-		// Fetch creator name if ShowCreator is true
-		if ev.ShowCreator {
-			profile, err := t.userProfileService.GetUserProfile(ctx, ev.CreatorID)
-			if err != nil {
-				t.logger.WarnContext(ctx, "failed to get user profile, hiding creator", slog.String("user_id", ev.CreatorID), slog.Any("error", err))
-				eventData.ShowCreator = false
-			} else {
-				eventData.CreatorName = profile.DisplayName
-			}
-		}
-
-		eventDataList[i] = eventData
-	}
-
 	// Render alt text template
 	altTmpl, err := template.New("alt").Parse(altTemplate)
 	if err != nil {
 		t.logger.ErrorContext(ctx, "failed to parse alt template", slog.Any("error", err))
-		return nil, errors.New("internal error")
+		return nil, agent.NewSystemError("internal error", err)
 	}
 
 	var altBuf bytes.Buffer
 	if err := altTmpl.Execute(&altBuf, map[string]int{"Count": len(events)}); err != nil {
 		t.logger.ErrorContext(ctx, "failed to execute alt template", slog.Any("error", err))
-		return nil, errors.New("internal error")
+		return nil, agent.NewSystemError("internal error", err)
 	}
 	altText := altBuf.String()
 
-	// Render flex template
-	flexTmpl, err := template.New("flex").Parse(flexTemplate)
+	// Render flex message
+	flexJSON, err := t.renderer.Render(ctx, events)
 	if err != nil {
-		t.logger.ErrorContext(ctx, "failed to parse flex template", slog.Any("error", err))
-		return nil, errors.New("internal error")
-	}
-
-	var flexBuf bytes.Buffer
-	if err := flexTmpl.Execute(&flexBuf, eventDataList); err != nil {
-		t.logger.ErrorContext(ctx, "failed to execute flex template", slog.Any("error", err))
-		return nil, errors.New("internal error")
+		t.logger.ErrorContext(ctx, "failed to render flex message", slog.Any("error", err))
+		return nil, agent.NewSystemError("internal error", err)
 	}
-	flexJSON := flexBuf.Bytes()
 
 	// Send flex message
-	if err := t.lineClient.SendFlexReply(replyToken, altText, flexJSON); err != nil {
+	if err := t.lineClient.SendFlex(ctx, altText, flexJSON); err != nil {
 		t.logger.ErrorContext(ctx, "failed to send flex message", slog.Any("error", err))
-		return nil, errors.New("failed to send flex message")
+		return nil, agent.NewSystemError("failed to send flex message", err)
 	}
 
 	return map[string]any{
-		"status": "sent",
+		"status":   "sent",
+		"has_more": next != "",
 	}, nil
 }
 
@@ -290,21 +273,31 @@ func (t *Tool) IsFinal(validatedResult map[string]any) bool {
 	return ok && status == "sent"
 }
 
+// describe returns the window in words for the tool description.
+func (w DefaultWindow) describe() string {
+	var from string
+	switch {
+	case w.StartOffsetDays == 0:
+		from = "today"
+	case w.StartOffsetDays == -1:
+		from = "yesterday"
+	case w.StartOffsetDays < 0:
+		from = fmt.Sprintf("%d days ago", -w.StartOffsetDays)
+	default:
+		from = fmt.Sprintf("%d days from today", w.StartOffsetDays)
+	}
+	if w.SpanDays == 0 {
+		return "events from " + from
+	}
+	return fmt.Sprintf("events in the %d days starting %s", w.SpanDays, from)
+}
+
 // parseTimeParameter parses a time parameter that can be either "today" or RFC3339 format.
-// "today" resolves to current date 00:00:00 in JST.
-func parseTimeParameter(s string) (time.Time, error) {
+// "today" resolves to today, the current date 00:00:00 in JST.
+func parseTimeParameter(s string, today time.Time) (time.Time, error) {
 	if s == "today" {
-		// Get current time in JST
-		now := time.Now().In(JST)
-		// Set to 00:00:00
-		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, JST), nil
+		return today, nil
 	}
 	// Parse as RFC3339
 	return time.Parse(time.RFC3339, s)
 }
-
-// formatDisplayTime formats a time for display in flex message.
-// Format: "2006/01/02 15:04" in JST.
-func formatDisplayTime(t time.Time) string {
-	return t.In(JST).Format("2006/01/02 15:04")
-}
@@ -4,10 +4,15 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"slices"
+	"strings"
 	"testing"
 	"time"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/clock"
 	"yuruppu/internal/event"
 	"yuruppu/internal/line"
+	"yuruppu/internal/toolset/event/card"
 	"yuruppu/internal/toolset/event/list"
 	"yuruppu/internal/userprofile"
 
@@ -73,7 +78,7 @@ func TestNew(t *testing.T) {
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
 
-		tool, err := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, err := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		require.NoError(t, err)
 		require.NotNil(t, tool)
@@ -84,7 +89,7 @@ func TestNew(t *testing.T) {
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
 
-		tool, err := list.New(nil, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, err := list.New(nil, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		require.Error(t, err)
 		assert.Nil(t, tool)
@@ -95,7 +100,7 @@ func TestNew(t *testing.T) {
 		eventService := &mockEventService{}
 		userProfileService := &mockUserProfileService{}
 
-		tool, err := list.New(eventService, nil, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, err := list.New(eventService, nil, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		require.Error(t, err)
 		assert.Nil(t, tool)
@@ -106,7 +111,7 @@ func TestNew(t *testing.T) {
 		eventService := &mockEventService{}
 		lineClient := &mockLineClient{}
 
-		tool, err := list.New(eventService, lineClient, nil, 366, 5, slog.New(slog.DiscardHandler))
+		tool, err := list.New(eventService, lineClient, nil, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		require.Error(t, err)
 		assert.Nil(t, tool)
@@ -118,7 +123,7 @@ func TestNew(t *testing.T) {
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
 
-		tool, err := list.New(eventService, lineClient, userProfileService, 0, 5, slog.New(slog.DiscardHandler))
+		tool, err := list.New(eventService, lineClient, userProfileService, 0, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		require.Error(t, err)
 		assert.Nil(t, tool)
@@ -130,7 +135,7 @@ func TestNew(t *testing.T) {
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
 
-		tool, err := list.New(eventService, lineClient, userProfileService, -1, 5, slog.New(slog.DiscardHandler))
+		tool, err := list.New(eventService, lineClient, userProfileService, -1, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		require.Error(t, err)
 		assert.Nil(t, tool)
@@ -142,7 +147,7 @@ func TestNew(t *testing.T) {
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
 
-		tool, err := list.New(eventService, lineClient, userProfileService, 366, 0, slog.New(slog.DiscardHandler))
+		tool, err := list.New(eventService, lineClient, userProfileService, 366, 0, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		require.Error(t, err)
 		assert.Nil(t, tool)
@@ -154,19 +159,51 @@ func TestNew(t *testing.T) {
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
 
-		tool, err := list.New(eventService, lineClient, userProfileService, 366, -1, slog.New(slog.DiscardHandler))
+		tool, err := list.New(eventService, lineClient, userProfileService, 366, -1, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		require.Error(t, err)
 		assert.Nil(t, tool)
 		assert.Contains(t, err.Error(), "limit must be positive")
 	})
 
+	t.Run("returns error when limit exceeds the carousel size", func(t *testing.T) {
+		tool, err := list.New(&mockEventService{}, &mockLineClient{}, &mockUserProfileService{}, 366, 6, list.DefaultWindow{}, slog.New(slog.DiscardHandler), card.WithCarouselSize(5))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "limit cannot exceed the carousel size (5)")
+	})
+
+	t.Run("returns error when carousel size exceeds LINE's limit", func(t *testing.T) {
+		tool, err := list.New(&mockEventService{}, &mockLineClient{}, &mockUserProfileService{}, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler), card.WithCarouselSize(card.MaxCarouselSize+1))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "carousel size must be between 1 and 12")
+	})
+
+	t.Run("returns error when default window span is negative", func(t *testing.T) {
+		tool, err := list.New(&mockEventService{}, &mockLineClient{}, &mockUserProfileService{}, 366, 5, list.DefaultWindow{SpanDays: -1}, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "SpanDays cannot be negative")
+	})
+
+	t.Run("returns error when default window span exceeds maxPeriodDays", func(t *testing.T) {
+		tool, err := list.New(&mockEventService{}, &mockLineClient{}, &mockUserProfileService{}, 30, 5, list.DefaultWindow{SpanDays: 31}, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "SpanDays cannot exceed maxPeriodDays")
+	})
+
 	t.Run("returns error when logger is nil", func(t *testing.T) {
 		eventService := &mockEventService{}
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
 
-		tool, err := list.New(eventService, lineClient, userProfileService, 366, 5, nil)
+		tool, err := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, nil)
 
 		require.Error(t, err)
 		assert.Nil(t, tool)
@@ -182,7 +219,7 @@ func TestTool_Metadata(t *testing.T) {
 	eventService := &mockEventService{}
 	lineClient := &mockLineClient{}
 	userProfileService := &mockUserProfileService{}
-	tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+	tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 	t.Run("Name returns list_events", func(t *testing.T) {
 		assert.Equal(t, "list_events", tool.Name())
@@ -234,7 +271,7 @@ func TestTool_Callback_BasicListing(t *testing.T) {
 				DisplayName: "Test User",
 			},
 		}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-999", "user-1", "test-reply-token")
 		args := map[string]any{}
@@ -281,7 +318,7 @@ func TestTool_Callback_BasicListing(t *testing.T) {
 				DisplayName: "Test User",
 			},
 		}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-999", "user-1", "test-reply-token")
 		args := map[string]any{}
@@ -311,7 +348,7 @@ func TestTool_Callback_BasicListing(t *testing.T) {
 		}
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-999", "user-1", "test-reply-token")
 		args := map[string]any{}
@@ -350,7 +387,7 @@ func TestTool_Callback_CreatorFilter(t *testing.T) {
 				DisplayName: "Test User",
 			},
 		}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-999", "user-1", "test-reply-token")
 		args := map[string]any{
@@ -381,7 +418,7 @@ func TestTool_Callback_CreatorFilter(t *testing.T) {
 		}
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-999", "user-1", "test-reply-token")
 		args := map[string]any{
@@ -396,20 +433,21 @@ func TestTool_Callback_CreatorFilter(t *testing.T) {
 		assert.Nil(t, eventService.lastOpts.CreatorID)
 	})
 
-	t.Run("returns error when created_by_me is not boolean", func(t *testing.T) {
+	t.Run("returns invalid when created_by_me is not boolean", func(t *testing.T) {
 		eventService := &mockEventService{}
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-999", "user-1", "test-reply-token")
 		args := map[string]any{
 			"created_by_me": "yes",
 		}
 
-		_, err := tool.Callback(ctx, args)
+		result, err := tool.Callback(ctx, args)
 
-		require.Error(t, err)
+		require.NoError(t, err)
+		assert.Equal(t, agent.StatusInvalid, result["status"])
 
 		// Service should not be called
 		assert.Equal(t, 0, eventService.listCount)
@@ -419,7 +457,7 @@ func TestTool_Callback_CreatorFilter(t *testing.T) {
 		eventService := &mockEventService{}
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := line.WithSourceID(context.Background(), "group-123")
 		args := map[string]any{
@@ -447,7 +485,7 @@ func TestTool_Callback_PeriodFilter_Before(t *testing.T) {
 		}
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-999", "user-1", "test-reply-token")
 		args := map[string]any{
@@ -476,7 +514,7 @@ func TestTool_Callback_PeriodFilter_Before(t *testing.T) {
 		}
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-999", "user-1", "test-reply-token")
 		startTime := "2026-03-01T00:00:00+09:00"
@@ -502,7 +540,7 @@ func TestTool_Callback_PeriodFilter_After(t *testing.T) {
 		}
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-999", "user-1", "test-reply-token")
 		args := map[string]any{
@@ -531,7 +569,7 @@ func TestTool_Callback_PeriodFilter_After(t *testing.T) {
 		}
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-999", "user-1", "test-reply-token")
 		endTime := "2026-02-01T00:00:00+09:00"
@@ -561,7 +599,7 @@ func TestTool_Callback_PeriodFilter_Range(t *testing.T) {
 		}
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-999", "user-1", "test-reply-token")
 		startTime := "2026-03-01T00:00:00+09:00"
@@ -580,15 +618,15 @@ func TestTool_Callback_PeriodFilter_Range(t *testing.T) {
 		require.NotNil(t, eventService.lastOpts.End)
 		assert.Equal(t, parseTime(startTime), *eventService.lastOpts.Start)
 		assert.Equal(t, parseTime(endTime), *eventService.lastOpts.End)
-		assert.Equal(t, 0, eventService.lastOpts.Limit) // No limit when both specified
+		assert.Equal(t, card.MaxCarouselSize, eventService.lastOpts.Limit) // One carousel when both specified
 	})
 
 	// FR-012: Period validation (max 1 year when both specified)
-	t.Run("returns error when range exceeds maxPeriodDays", func(t *testing.T) {
+	t.Run("returns invalid when range exceeds maxPeriodDays", func(t *testing.T) {
 		eventService := &mockEventService{}
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-999", "user-1", "test-reply-token")
 		args := map[string]any{
@@ -596,9 +634,10 @@ func TestTool_Callback_PeriodFilter_Range(t *testing.T) {
 			"end":   "2027-01-03T00:00:00+09:00",
 		}
 
-		_, err := tool.Callback(ctx, args)
+		result, err := tool.Callback(ctx, args)
 
-		require.Error(t, err)
+		require.NoError(t, err)
+		assert.Equal(t, agent.StatusInvalid, result["status"])
 
 		// Service should not be called
 		assert.Equal(t, 0, eventService.listCount)
@@ -610,7 +649,7 @@ func TestTool_Callback_PeriodFilter_Range(t *testing.T) {
 		}
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-999", "user-1", "test-reply-token")
 		args := map[string]any{
@@ -624,11 +663,11 @@ func TestTool_Callback_PeriodFilter_Range(t *testing.T) {
 		assert.Equal(t, 1, eventService.listCount)
 	})
 
-	t.Run("returns error when end is before start", func(t *testing.T) {
+	t.Run("returns invalid when end is before start", func(t *testing.T) {
 		eventService := &mockEventService{}
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-999", "user-1", "test-reply-token")
 		args := map[string]any{
@@ -636,9 +675,10 @@ func TestTool_Callback_PeriodFilter_Range(t *testing.T) {
 			"end":   "2026-02-01T00:00:00+09:00",
 		}
 
-		_, err := tool.Callback(ctx, args)
+		result, err := tool.Callback(ctx, args)
 
-		require.Error(t, err)
+		require.NoError(t, err)
+		assert.Equal(t, agent.StatusInvalid, result["status"])
 
 		// Service should not be called
 		assert.Equal(t, 0, eventService.listCount)
@@ -657,7 +697,7 @@ func TestTool_Callback_CombinedFilters(t *testing.T) {
 		}
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-999", "user-1", "test-reply-token")
 		args := map[string]any{
@@ -683,7 +723,7 @@ func TestTool_Callback_CombinedFilters(t *testing.T) {
 		}
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-999", "user-1", "test-reply-token")
 		args := map[string]any{
@@ -708,7 +748,7 @@ func TestTool_Callback_CombinedFilters(t *testing.T) {
 		}
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-999", "user-1", "test-reply-token")
 		args := map[string]any{
@@ -740,7 +780,7 @@ func TestTool_Callback_SortOrder(t *testing.T) {
 		}
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-999", "user-1", "test-reply-token")
 		args := map[string]any{
@@ -762,7 +802,7 @@ func TestTool_Callback_SortOrder(t *testing.T) {
 		}
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-999", "user-1", "test-reply-token")
 		args := map[string]any{
@@ -785,7 +825,7 @@ func TestTool_Callback_SortOrder(t *testing.T) {
 		}
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-999", "user-1", "test-reply-token")
 		args := map[string]any{
@@ -814,7 +854,7 @@ func TestTool_Callback_LimitEnforcement(t *testing.T) {
 		}
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-999", "user-1", "test-reply-token")
 		args := map[string]any{
@@ -833,7 +873,7 @@ func TestTool_Callback_LimitEnforcement(t *testing.T) {
 		}
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-999", "user-1", "test-reply-token")
 		args := map[string]any{
@@ -846,13 +886,13 @@ func TestTool_Callback_LimitEnforcement(t *testing.T) {
 		assert.Equal(t, 5, eventService.lastOpts.Limit)
 	})
 
-	t.Run("does not apply limit when both start and end specified", func(t *testing.T) {
+	t.Run("applies the carousel size when both start and end specified", func(t *testing.T) {
 		eventService := &mockEventService{
 			listEvents: []*event.Event{},
 		}
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-999", "user-1", "test-reply-token")
 		args := map[string]any{
@@ -863,7 +903,7 @@ func TestTool_Callback_LimitEnforcement(t *testing.T) {
 		_, err := tool.Callback(ctx, args)
 
 		require.NoError(t, err)
-		assert.Equal(t, 0, eventService.lastOpts.Limit) // No limit
+		assert.Equal(t, card.MaxCarouselSize, eventService.lastOpts.Limit) // One carousel
 	})
 
 	t.Run("applies limit when no filters specified", func(t *testing.T) {
@@ -872,7 +912,7 @@ func TestTool_Callback_LimitEnforcement(t *testing.T) {
 		}
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-999", "user-1", "test-reply-token")
 		args := map[string]any{}
@@ -882,6 +922,41 @@ func TestTool_Callback_LimitEnforcement(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, 5, eventService.lastOpts.Limit)
 	})
+
+	t.Run("shows the first page of a configured carousel size and reports more", func(t *testing.T) {
+		var events []*event.Event
+		for i := range 3 {
+			start := fixedNow.Add(time.Duration(24+i) * time.Hour)
+			events = append(events, testEventWithShowCreator("group-"+string(rune('a'+i)), "user-1", "Event", start, start.Add(time.Hour), false))
+		}
+		eventService := &mockEventService{listEvents: events, listNext: "next-page"}
+		lineClient := &mockLineClient{}
+		tool, err := list.New(eventService, lineClient, &mockUserProfileService{}, 366, 2, list.DefaultWindow{}, slog.New(slog.DiscardHandler), card.WithCarouselSize(3))
+		require.NoError(t, err)
+
+		ctx := withEventContext(context.Background(), "group-999", "user-1", "test-reply-token")
+		result, err := tool.Callback(ctx, map[string]any{
+			"start": "2026-03-01T00:00:00+09:00",
+			"end":   "2026-03-31T23:59:59+09:00",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, eventService.lastOpts.Limit)
+		assert.Empty(t, eventService.lastCursor, "fetches the first page")
+		assert.Equal(t, map[string]any{"status": "sent", "has_more": true}, result)
+		assert.Equal(t, 3, strings.Count(string(lineClient.lastFlexJSON), `"type": "bubble"`))
+	})
+
+	t.Run("reports no more events on the last page", func(t *testing.T) {
+		eventService := &mockEventService{listEvents: []*event.Event{testEventWithShowCreator("group-1", "user-1", "Event", fixedNow.Add(24*time.Hour), fixedNow.Add(26*time.Hour), false)}}
+		tool, err := list.New(eventService, &mockLineClient{}, &mockUserProfileService{}, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(withEventContext(context.Background(), "group-999", "user-1", "test-reply-token"), map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"status": "sent", "has_more": false}, result)
+	})
 }
 
 // =============================================================================
@@ -905,7 +980,7 @@ func TestTool_Callback_TimeFormat(t *testing.T) {
 				DisplayName: "Test User",
 			},
 		}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-999", "user-1", "test-reply-token")
 		args := map[string]any{}
@@ -950,7 +1025,7 @@ func TestTool_Callback_FlexMessage(t *testing.T) {
 				DisplayName: "Test User",
 			},
 		}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-1", "user-1", "test-reply-token")
 		args := map[string]any{}
@@ -959,7 +1034,7 @@ func TestTool_Callback_FlexMessage(t *testing.T) {
 
 		require.NoError(t, err)
 
-		// Expected: LineClient.SendFlexReply is called once
+		// Expected: LineClient.SendFlex is called once
 		assert.Equal(t, 1, lineClient.sendFlexReplyCount)
 		assert.Equal(t, "test-reply-token", lineClient.lastReplyToken)
 		assert.NotEmpty(t, lineClient.lastAltText)
@@ -984,7 +1059,7 @@ func TestTool_Callback_FlexMessage(t *testing.T) {
 				DisplayName: "Creator Name",
 			},
 		}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-1", "user-2", "test-reply-token")
 		args := map[string]any{}
@@ -993,9 +1068,9 @@ func TestTool_Callback_FlexMessage(t *testing.T) {
 
 		require.NoError(t, err)
 
-		// Expected: UserProfileService.GetUserProfile is called
-		assert.Equal(t, 1, userProfileService.getUserProfileCount)
-		assert.Equal(t, "user-1", userProfileService.lastUserID)
+		// Expected: UserProfileService.GetUserProfiles is called once for the creator
+		assert.Equal(t, 1, userProfileService.getUserProfilesCount)
+		assert.Equal(t, []string{"user-1"}, userProfileService.lastUserIDs)
 
 		// Expected: Flex JSON contains creator name
 		assert.Contains(t, string(lineClient.lastFlexJSON), "Creator Name")
@@ -1020,7 +1095,7 @@ func TestTool_Callback_FlexMessage(t *testing.T) {
 				DisplayName: "Creator Name",
 			},
 		}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-1", "user-2", "test-reply-token")
 		args := map[string]any{}
@@ -1029,8 +1104,8 @@ func TestTool_Callback_FlexMessage(t *testing.T) {
 
 		require.NoError(t, err)
 
-		// Expected: UserProfileService.GetUserProfile is NOT called for this event
-		assert.Equal(t, 0, userProfileService.getUserProfileCount)
+		// Expected: UserProfileService.GetUserProfiles is NOT called for this event
+		assert.Equal(t, 0, userProfileService.getUserProfilesCount)
 
 		// Expected: Flex JSON contains "？？？" instead of creator name
 		assert.Contains(t, string(lineClient.lastFlexJSON), "？？？")
@@ -1056,7 +1131,7 @@ func TestTool_Callback_FlexMessage(t *testing.T) {
 				DisplayName: "Test User",
 			},
 		}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-1", "user-3", "test-reply-token")
 		args := map[string]any{}
@@ -1065,13 +1140,14 @@ func TestTool_Callback_FlexMessage(t *testing.T) {
 
 		require.NoError(t, err)
 
-		// Expected: LineClient.SendFlexReply is called once with carousel
+		// Expected: LineClient.SendFlex is called once with carousel
 		assert.Equal(t, 1, lineClient.sendFlexReplyCount)
 		assert.Contains(t, string(lineClient.lastFlexJSON), "Event A")
 		assert.Contains(t, string(lineClient.lastFlexJSON), "Event B")
 
-		// Expected: UserProfileService.GetUserProfile is called for both creators
-		assert.Equal(t, 2, userProfileService.getUserProfileCount)
+		// Expected: UserProfileService.GetUserProfiles is called once for both creators
+		assert.Equal(t, 1, userProfileService.getUserProfilesCount)
+		assert.Equal(t, []string{"user-1", "user-2"}, userProfileService.lastUserIDs)
 
 		// Expected: Result has {"status": "sent"}
 		status, ok := result["status"].(string)
@@ -1092,7 +1168,7 @@ func TestTool_Callback_FlexMessage(t *testing.T) {
 				DisplayName: "Test User",
 			},
 		}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-1", "user-1", "custom-reply-token")
 		args := map[string]any{}
@@ -1101,7 +1177,7 @@ func TestTool_Callback_FlexMessage(t *testing.T) {
 
 		require.NoError(t, err)
 
-		// Expected: LineClient.SendFlexReply receives correct replyToken
+		// Expected: LineClient.SendFlex receives correct replyToken
 		assert.Equal(t, "custom-reply-token", lineClient.lastReplyToken)
 
 		// Expected: Result has {"status": "sent"}
@@ -1110,7 +1186,7 @@ func TestTool_Callback_FlexMessage(t *testing.T) {
 		assert.Equal(t, "sent", status)
 	})
 
-	t.Run("returns error when replyToken not in context", func(t *testing.T) {
+	t.Run("sends without replyToken in context", func(t *testing.T) {
 		// Setup: context without replyToken
 		event1 := testEvent("group-1", "user-1", "Test Event", fixedNow.Add(24*time.Hour), fixedNow.Add(26*time.Hour))
 
@@ -1118,24 +1194,29 @@ func TestTool_Callback_FlexMessage(t *testing.T) {
 			listEvents: []*event.Event{event1},
 		}
 		lineClient := &mockLineClient{}
-		userProfileService := &mockUserProfileService{}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		userProfileService := &mockUserProfileService{
+			getUserProfileResult: &userprofile.UserProfile{
+				DisplayName: "Test User",
+			},
+		}
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := line.WithSourceID(context.Background(), "group-1")
 		ctx = line.WithUserID(ctx, "user-1")
 		args := map[string]any{}
 
-		_, err := tool.Callback(ctx, args)
+		result, err := tool.Callback(ctx, args)
 
-		// Expected: Error is returned
-		require.Error(t, err)
+		require.NoError(t, err)
 
-		// Expected: LineClient.SendFlexReply is NOT called
-		assert.Equal(t, 0, lineClient.sendFlexReplyCount)
+		// Expected: LineClient.SendFlex is called without a replyToken (the client pushes instead)
+		assert.Equal(t, 1, lineClient.sendFlexReplyCount)
+		assert.Empty(t, lineClient.lastReplyToken)
+		assert.Equal(t, "sent", result["status"])
 	})
 
-	t.Run("returns error when SendFlexReply fails", func(t *testing.T) {
-		// Setup: LineClient.SendFlexReply returns error
+	t.Run("returns error when SendFlex fails", func(t *testing.T) {
+		// Setup: LineClient.SendFlex returns error
 		event1 := testEvent("group-1", "user-1", "Test Event", fixedNow.Add(24*time.Hour), fixedNow.Add(26*time.Hour))
 
 		eventService := &mockEventService{
@@ -1149,7 +1230,7 @@ func TestTool_Callback_FlexMessage(t *testing.T) {
 				DisplayName: "Test User",
 			},
 		}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-1", "user-1", "test-reply-token")
 		args := map[string]any{}
@@ -1169,7 +1250,7 @@ func TestTool_Callback_FlexMessage(t *testing.T) {
 		}
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-1", "user-1", "test-reply-token")
 		args := map[string]any{}
@@ -1178,7 +1259,7 @@ func TestTool_Callback_FlexMessage(t *testing.T) {
 
 		require.NoError(t, err)
 
-		// Expected: LineClient.SendFlexReply is NOT called
+		// Expected: LineClient.SendFlex is NOT called
 		assert.Equal(t, 0, lineClient.sendFlexReplyCount)
 
 		// Expected: Result has {"status": "no_events"}
@@ -1187,8 +1268,8 @@ func TestTool_Callback_FlexMessage(t *testing.T) {
 		assert.Equal(t, "no_events", status)
 	})
 
-	t.Run("hides creator when GetUserProfile fails", func(t *testing.T) {
-		// Setup: UserProfileService.GetUserProfile returns error
+	t.Run("hides creators when GetUserProfiles fails", func(t *testing.T) {
+		// Setup: UserProfileService.GetUserProfiles returns error
 		event1 := testEvent("group-1", "user-1", "Test Event", fixedNow.Add(24*time.Hour), fixedNow.Add(26*time.Hour))
 
 		eventService := &mockEventService{
@@ -1198,7 +1279,7 @@ func TestTool_Callback_FlexMessage(t *testing.T) {
 		userProfileService := &mockUserProfileService{
 			getUserProfileErr: errors.New("profile fetch error"),
 		}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-1", "user-2", "test-reply-token")
 		args := map[string]any{}
@@ -1213,9 +1294,38 @@ func TestTool_Callback_FlexMessage(t *testing.T) {
 		require.True(t, ok)
 		assert.Equal(t, "sent", status)
 
-		// Expected: LineClient.SendFlexReply is called
+		// Expected: LineClient.SendFlex is called
 		assert.Equal(t, 1, lineClient.sendFlexReplyCount)
 	})
+
+	t.Run("hides only creators whose profile is missing", func(t *testing.T) {
+		// Setup: user-2 has no profile
+		event1 := testEventWithShowCreator("group-1", "user-1", "Event A", fixedNow.Add(24*time.Hour), fixedNow.Add(26*time.Hour), true)
+		event2 := testEventWithShowCreator("group-1", "user-2", "Event B", fixedNow.Add(48*time.Hour), fixedNow.Add(50*time.Hour), true)
+
+		eventService := &mockEventService{
+			listEvents: []*event.Event{event1, event2},
+		}
+		lineClient := &mockLineClient{}
+		userProfileService := &mockUserProfileService{
+			getUserProfileResult: &userprofile.UserProfile{
+				DisplayName: "Creator Name",
+			},
+			missingUserIDs: []string{"user-2"},
+		}
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
+
+		ctx := withEventContext(context.Background(), "group-1", "user-3", "test-reply-token")
+
+		_, err := tool.Callback(ctx, map[string]any{})
+
+		require.NoError(t, err)
+
+		// Expected: One creator is shown and the other is hidden
+		flexJSON := string(lineClient.lastFlexJSON)
+		assert.Equal(t, 1, strings.Count(flexJSON, "Creator Name"))
+		assert.Equal(t, 1, strings.Count(flexJSON, "？？？"))
+	})
 }
 
 // =============================================================================
@@ -1230,7 +1340,7 @@ func TestTool_Callback_TodayResolution(t *testing.T) {
 		}
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-999", "user-1", "test-reply-token")
 		args := map[string]any{
@@ -1257,7 +1367,7 @@ func TestTool_Callback_TodayResolution(t *testing.T) {
 		}
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-999", "user-1", "test-reply-token")
 		args := map[string]any{
@@ -1279,6 +1389,147 @@ func TestTool_Callback_TodayResolution(t *testing.T) {
 	})
 }
 
+// TestTool_Callback_InjectedClock verifies that "today" follows the clock in the context, not the real clock.
+func TestTool_Callback_InjectedClock(t *testing.T) {
+	tests := []struct {
+		name      string
+		now       time.Time
+		wantToday time.Time
+	}{
+		{
+			name:      "just before midnight JST",
+			now:       time.Date(2026, 2, 15, 23, 59, 59, 0, JST),
+			wantToday: time.Date(2026, 2, 15, 0, 0, 0, 0, JST),
+		},
+		{
+			name:      "just after midnight JST",
+			now:       time.Date(2026, 2, 16, 0, 0, 1, 0, JST),
+			wantToday: time.Date(2026, 2, 16, 0, 0, 0, 0, JST),
+		},
+		{
+			name:      "UTC date differs from JST date",
+			now:       time.Date(2026, 2, 15, 16, 30, 0, 0, time.UTC),
+			wantToday: time.Date(2026, 2, 16, 0, 0, 0, 0, JST),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eventService := &mockEventService{listEvents: []*event.Event{}}
+			tool, err := list.New(eventService, &mockLineClient{}, &mockUserProfileService{}, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
+			require.NoError(t, err)
+			ctx := clock.WithNow(withEventContext(context.Background(), "group-999", "user-1", "test-reply-token"), tt.now)
+
+			// Default period starts today
+			_, err = tool.Callback(ctx, map[string]any{})
+			require.NoError(t, err)
+			require.NotNil(t, eventService.lastOpts.Start)
+			assert.True(t, tt.wantToday.Equal(*eventService.lastOpts.Start), "default start: got %v", *eventService.lastOpts.Start)
+
+			// "today" as start and end resolves to the same boundary
+			_, err = tool.Callback(ctx, map[string]any{"start": "today", "end": "today"})
+			require.NoError(t, err)
+			require.NotNil(t, eventService.lastOpts.Start)
+			require.NotNil(t, eventService.lastOpts.End)
+			assert.True(t, tt.wantToday.Equal(*eventService.lastOpts.Start), "start: got %v", *eventService.lastOpts.Start)
+			assert.True(t, tt.wantToday.Equal(*eventService.lastOpts.End), "end: got %v", *eventService.lastOpts.End)
+		})
+	}
+}
+
+// =============================================================================
+// Callback Tests - Default Window
+// =============================================================================
+
+// TestTool_Callback_DefaultWindow verifies that a configured default window changes the boundaries used without filters.
+func TestTool_Callback_DefaultWindow(t *testing.T) {
+	now := time.Date(2026, 2, 15, 20, 0, 0, 0, JST)
+
+	tests := []struct {
+		name      string
+		window    list.DefaultWindow
+		wantStart time.Time
+		wantEnd   time.Time // Zero means no end
+		wantLimit int
+	}{
+		{
+			name:      "zero value starts today with no end",
+			window:    list.DefaultWindow{},
+			wantStart: time.Date(2026, 2, 15, 0, 0, 0, 0, JST),
+			wantLimit: 5,
+		},
+		{
+			name:      "negative offset starts yesterday",
+			window:    list.DefaultWindow{StartOffsetDays: -1},
+			wantStart: time.Date(2026, 2, 14, 0, 0, 0, 0, JST),
+			wantLimit: 5,
+		},
+		{
+			name:      "positive offset starts later",
+			window:    list.DefaultWindow{StartOffsetDays: 2},
+			wantStart: time.Date(2026, 2, 17, 0, 0, 0, 0, JST),
+			wantLimit: 5,
+		},
+		{
+			name:      "span bounds the window and shows one carousel",
+			window:    list.DefaultWindow{SpanDays: 7},
+			wantStart: time.Date(2026, 2, 15, 0, 0, 0, 0, JST),
+			wantEnd:   time.Date(2026, 2, 22, 0, 0, 0, 0, JST).Add(-time.Nanosecond),
+			wantLimit: card.MaxCarouselSize,
+		},
+		{
+			name:      "offset and span combine",
+			window:    list.DefaultWindow{StartOffsetDays: -1, SpanDays: 2},
+			wantStart: time.Date(2026, 2, 14, 0, 0, 0, 0, JST),
+			wantEnd:   time.Date(2026, 2, 16, 0, 0, 0, 0, JST).Add(-time.Nanosecond),
+			wantLimit: card.MaxCarouselSize,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eventService := &mockEventService{listEvents: []*event.Event{}}
+			tool, err := list.New(eventService, &mockLineClient{}, &mockUserProfileService{}, 366, 5, tt.window, slog.New(slog.DiscardHandler))
+			require.NoError(t, err)
+			ctx := clock.WithNow(withEventContext(context.Background(), "group-999", "user-1", "test-reply-token"), now)
+
+			_, err = tool.Callback(ctx, map[string]any{})
+
+			require.NoError(t, err)
+			require.NotNil(t, eventService.lastOpts.Start)
+			assert.True(t, tt.wantStart.Equal(*eventService.lastOpts.Start), "start: got %v", *eventService.lastOpts.Start)
+			if tt.wantEnd.IsZero() {
+				assert.Nil(t, eventService.lastOpts.End)
+			} else {
+				require.NotNil(t, eventService.lastOpts.End)
+				assert.True(t, tt.wantEnd.Equal(*eventService.lastOpts.End), "end: got %v", *eventService.lastOpts.End)
+			}
+			assert.Equal(t, tt.wantLimit, eventService.lastOpts.Limit)
+		})
+	}
+
+	t.Run("explicit filters ignore the default window", func(t *testing.T) {
+		eventService := &mockEventService{listEvents: []*event.Event{}}
+		tool, err := list.New(eventService, &mockLineClient{}, &mockUserProfileService{}, 366, 5, list.DefaultWindow{StartOffsetDays: -3, SpanDays: 7}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+		ctx := clock.WithNow(withEventContext(context.Background(), "group-999", "user-1", "test-reply-token"), now)
+
+		_, err = tool.Callback(ctx, map[string]any{"start": "today"})
+
+		require.NoError(t, err)
+		require.NotNil(t, eventService.lastOpts.Start)
+		assert.True(t, time.Date(2026, 2, 15, 0, 0, 0, 0, JST).Equal(*eventService.lastOpts.Start))
+		assert.Nil(t, eventService.lastOpts.End)
+	})
+
+	t.Run("description reflects the window", func(t *testing.T) {
+		tool, err := list.New(&mockEventService{}, &mockLineClient{}, &mockUserProfileService{}, 366, 5, list.DefaultWindow{StartOffsetDays: -1, SpanDays: 7}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		assert.Contains(t, tool.Description(), "events in the 7 days starting yesterday")
+	})
+}
+
 // =============================================================================
 // Callback Tests - Error Cases
 // =============================================================================
@@ -1290,7 +1541,7 @@ func TestTool_Callback_Errors(t *testing.T) {
 		}
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-999", "user-1", "test-reply-token")
 		args := map[string]any{}
@@ -1301,77 +1552,81 @@ func TestTool_Callback_Errors(t *testing.T) {
 		assert.Equal(t, 1, eventService.listCount)
 	})
 
-	t.Run("returns error when start is invalid RFC3339", func(t *testing.T) {
+	t.Run("returns invalid when start is invalid RFC3339", func(t *testing.T) {
 		eventService := &mockEventService{}
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-999", "user-1", "test-reply-token")
 		args := map[string]any{
 			"start": "not-a-date",
 		}
 
-		_, err := tool.Callback(ctx, args)
+		result, err := tool.Callback(ctx, args)
 
-		require.Error(t, err)
+		require.NoError(t, err)
+		assert.Equal(t, agent.StatusInvalid, result["status"])
 
 		// Service should not be called
 		assert.Equal(t, 0, eventService.listCount)
 	})
 
-	t.Run("returns error when end is invalid RFC3339", func(t *testing.T) {
+	t.Run("returns invalid when end is invalid RFC3339", func(t *testing.T) {
 		eventService := &mockEventService{}
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-999", "user-1", "test-reply-token")
 		args := map[string]any{
 			"end": "2026-13-40T25:61:61+09:00",
 		}
 
-		_, err := tool.Callback(ctx, args)
+		result, err := tool.Callback(ctx, args)
 
-		require.Error(t, err)
+		require.NoError(t, err)
+		assert.Equal(t, agent.StatusInvalid, result["status"])
 
 		// Service should not be called
 		assert.Equal(t, 0, eventService.listCount)
 	})
 
-	t.Run("returns error when start is not a string", func(t *testing.T) {
+	t.Run("returns invalid when start is not a string", func(t *testing.T) {
 		eventService := &mockEventService{}
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-999", "user-1", "test-reply-token")
 		args := map[string]any{
 			"start": 123,
 		}
 
-		_, err := tool.Callback(ctx, args)
+		result, err := tool.Callback(ctx, args)
 
-		require.Error(t, err)
+		require.NoError(t, err)
+		assert.Equal(t, agent.StatusInvalid, result["status"])
 
 		// Service should not be called
 		assert.Equal(t, 0, eventService.listCount)
 	})
 
-	t.Run("returns error when end is not a string", func(t *testing.T) {
+	t.Run("returns invalid when end is not a string", func(t *testing.T) {
 		eventService := &mockEventService{}
 		lineClient := &mockLineClient{}
 		userProfileService := &mockUserProfileService{}
-		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+		tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-999", "user-1", "test-reply-token")
 		args := map[string]any{
 			"end": true,
 		}
 
-		_, err := tool.Callback(ctx, args)
+		result, err := tool.Callback(ctx, args)
 
-		require.Error(t, err)
+		require.NoError(t, err)
+		assert.Equal(t, agent.StatusInvalid, result["status"])
 
 		// Service should not be called
 		assert.Equal(t, 0, eventService.listCount)
@@ -1386,7 +1641,7 @@ func TestTool_IsFinal(t *testing.T) {
 	eventService := &mockEventService{}
 	lineClient := &mockLineClient{}
 	userProfileService := &mockUserProfileService{}
-	tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, slog.New(slog.DiscardHandler))
+	tool, _ := list.New(eventService, lineClient, userProfileService, 366, 5, list.DefaultWindow{}, slog.New(slog.DiscardHandler))
 
 	t.Run("returns true when status is sent", func(t *testing.T) {
 		result := map[string]any{"status": "sent"}
@@ -1415,15 +1670,18 @@ func TestTool_IsFinal(t *testing.T) {
 
 type mockEventService struct {
 	listEvents []*event.Event
+	listNext   string
 	listErr    error
 	listCount  int
 	lastOpts   event.ListOptions
+	lastCursor string
 }
 
-func (m *mockEventService) List(ctx context.Context, opts event.ListOptions) ([]*event.Event, error) {
+func (m *mockEventService) ListPage(ctx context.Context, opts event.ListOptions, cursor string) ([]*event.Event, string, error) {
 	m.listCount++
 	m.lastOpts = opts
-	return m.listEvents, m.listErr
+	m.lastCursor = cursor
+	return m.listEvents, m.listNext, m.listErr
 }
 
 type mockLineClient struct {
@@ -1434,9 +1692,9 @@ type mockLineClient struct {
 	lastFlexJSON       []byte
 }
 
-func (m *mockLineClient) SendFlexReply(replyToken string, altText string, flexJSON []byte) error {
+func (m *mockLineClient) SendFlex(ctx context.Context, altText string, flexJSON []byte) error {
 	m.sendFlexReplyCount++
-	m.lastReplyToken = replyToken
+	m.lastReplyToken, _ = line.ReplyTokenFromContext(ctx)
 	m.lastAltText = altText
 	m.lastFlexJSON = flexJSON
 	return m.sendFlexReplyErr
@@ -1445,12 +1703,22 @@ func (m *mockLineClient) SendFlexReply(replyToken string, altText string, flexJS
 type mockUserProfileService struct {
 	getUserProfileResult *userprofile.UserProfile
 	getUserProfileErr    error
-	getUserProfileCount  int
-	lastUserID           string
+	missingUserIDs       []string
+	getUserProfilesCount int
+	lastUserIDs          []string
 }
 
-func (m *mockUserProfileService) GetUserProfile(ctx context.Context, userID string) (*userprofile.UserProfile, error) {
-	m.getUserProfileCount++
-	m.lastUserID = userID
-	return m.getUserProfileResult, m.getUserProfileErr
+func (m *mockUserProfileService) GetUserProfiles(ctx context.Context, userIDs []string) (map[string]*userprofile.UserProfile, error) {
+	m.getUserProfilesCount++
+	m.lastUserIDs = userIDs
+	if m.getUserProfileErr != nil {
+		return nil, m.getUserProfileErr
+	}
+	profiles := make(map[string]*userprofile.UserProfile, len(userIDs))
+	for _, id := range userIDs {
+		if !slices.Contains(m.missingUserIDs, id) {
+			profiles[id] = m.getUserProfileResult
+		}
+	}
+	return profiles, nil
 }
@@ -0,0 +1,233 @@
+package mine
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"text/template"
+	"time"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/clock"
+	"yuruppu/internal/event"
+	"yuruppu/internal/groupprofile"
+	"yuruppu/internal/line"
+	"yuruppu/internal/toolset/event/card"
+	"yuruppu/internal/userprofile"
+)
+
+//go:embed parameters.json
+var parametersSchema []byte
+
+//go:embed response.json
+var responseSchema []byte
+
+//go:embed alt.txt
+var altTemplate string
+
+// EventService provides access to event list operations.
+type EventService interface {
+	List(ctx context.Context, opts event.ListOptions) ([]*event.Event, error)
+}
+
+// GroupProfileService provides access to group profiles and the groups a user belongs to.
+type GroupProfileService interface {
+	GetGroupProfile(ctx context.Context, groupID string) (*groupprofile.GroupProfile, error)
+	GroupIDsForUser(ctx context.Context, userID string) ([]string, error)
+}
+
+// LineClient provides LINE messaging operations.
+type LineClient interface {
+	SendFlex(ctx context.Context, altText string, flexJSON []byte) error
+}
+
+// UserProfileService provides user profile operations.
+type UserProfileService interface {
+	GetUserProfiles(ctx context.Context, userIDs []string) (map[string]*userprofile.UserProfile, error)
+}
+
+// Tool implements the all_my_events tool for showing upcoming events across every group the user belongs to.
+type Tool struct {
+	eventService        EventService
+	groupProfileService GroupProfileService
+	lineClient          LineClient
+	renderer            *card.Renderer
+	limit               int
+	logger              *slog.Logger
+}
+
+// New creates a new all_my_events tool.
+// limit is the maximum number of events shown, as in list_events.
+// cardOpts customize the event cards sent as the result.
+func New(eventService EventService, groupProfileService GroupProfileService, lineClient LineClient, userProfileService UserProfileService, limit int, logger *slog.Logger, cardOpts ...card.Option) (*Tool, error) {
+	if eventService == nil {
+		return nil, errors.New("eventService cannot be nil")
+	}
+	if groupProfileService == nil {
+		return nil, errors.New("groupProfileService cannot be nil")
+	}
+	if lineClient == nil {
+		return nil, errors.New("lineClient cannot be nil")
+	}
+	if userProfileService == nil {
+		return nil, errors.New("userProfileService cannot be nil")
+	}
+	if limit <= 0 {
+		return nil, errors.New("limit must be positive")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil")
+	}
+	renderer, err := card.NewRenderer(userProfileService, logger, cardOpts...)
+	if err != nil {
+		return nil, err
+	}
+	if limit > renderer.CarouselSize() {
+		return nil, fmt.Errorf("limit cannot exceed the carousel size (%d)", renderer.CarouselSize())
+	}
+	return &Tool{
+		eventService:        eventService,
+		groupProfileService: groupProfileService,
+		lineClient:          lineClient,
+		renderer:            renderer,
+		limit:               limit,
+		logger:              logger,
+	}, nil
+}
+
+// Name returns the tool name.
+func (t *Tool) Name() string {
+	return "all_my_events"
+}
+
+// Description returns a description for the LLM.
+func (t *Tool) Description() string {
+	return "Sends a Flex Message directly to the chat with the upcoming events of every group the user belongs to, earliest first, each labeled with its group name. Use this when the user asks about their events across groups rather than in this chat."
+}
+
+// ParametersJsonSchema returns the JSON Schema for input parameters.
+func (t *Tool) ParametersJsonSchema() []byte {
+	return parametersSchema
+}
+
+// ResponseJsonSchema returns the JSON Schema for the response.
+func (t *Tool) ResponseJsonSchema() []byte {
+	return responseSchema
+}
+
+// Callback collects the upcoming events of the user's groups and sends them as a Flex Message.
+func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]any, error) {
+	userID, ok := line.UserIDFromContext(ctx)
+	if !ok {
+		t.logger.ErrorContext(ctx, "user ID not found in context")
+		return nil, agent.NewSystemError("internal error", nil)
+	}
+
+	groupNames, err := t.groupNames(ctx, userID)
+	if err != nil {
+		t.logger.ErrorContext(ctx, "failed to look up groups", slog.String("userID", userID), slog.Any("error", err))
+		return nil, agent.NewSystemError("failed to look up groups", err)
+	}
+	if len(groupNames) == 0 {
+		return map[string]any{
+			"status": "no_events",
+		}, nil
+	}
+
+	// Show events from today, as list_events does by default
+	now := clock.Now(ctx).In(card.JST)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, card.JST)
+	events, err := t.eventService.List(ctx, event.ListOptions{Start: &today})
+	if err != nil {
+		t.logger.ErrorContext(ctx, "failed to list events", slog.Any("error", err))
+		return nil, agent.NewSystemError("failed to list events", err)
+	}
+
+	mine := make([]*event.Event, 0, len(events))
+	for _, ev := range events {
+		if _, ok := groupNames[ev.ChatRoomID]; ok {
+			mine = append(mine, ev)
+		}
+	}
+	slices.SortStableFunc(mine, func(a, b *event.Event) int {
+		return a.StartTime.Compare(b.StartTime)
+	})
+	hasMore := len(mine) > t.limit
+	if hasMore {
+		mine = mine[:t.limit]
+	}
+
+	// If no events, return no_events status without sending message
+	if len(mine) == 0 {
+		return map[string]any{
+			"status": "no_events",
+		}, nil
+	}
+
+	// Render alt text template
+	altTmpl, err := template.New("alt").Parse(altTemplate)
+	if err != nil {
+		t.logger.ErrorContext(ctx, "failed to parse alt template", slog.Any("error", err))
+		return nil, agent.NewSystemError("internal error", err)
+	}
+
+	var altBuf bytes.Buffer
+	if err := altTmpl.Execute(&altBuf, map[string]int{"Count": len(mine)}); err != nil {
+		t.logger.ErrorContext(ctx, "failed to execute alt template", slog.Any("error", err))
+		return nil, agent.NewSystemError("internal error", err)
+	}
+
+	// Render flex message
+	flexJSON, err := t.renderer.RenderWithGroupNames(ctx, mine, groupNames)
+	if err != nil {
+		t.logger.ErrorContext(ctx, "failed to render flex message", slog.Any("error", err))
+		return nil, agent.NewSystemError("internal error", err)
+	}
+
+	// Send flex message
+	if err := t.lineClient.SendFlex(ctx, altBuf.String(), flexJSON); err != nil {
+		t.logger.ErrorContext(ctx, "failed to send flex message", slog.Any("error", err))
+		return nil, agent.NewSystemError("failed to send flex message", err)
+	}
+
+	return map[string]any{
+		"status":   "sent",
+		"has_more": hasMore,
+	}, nil
+}
+
+// IsFinal returns true if the flex message was sent successfully.
+// When status is "no_events", the LLM should continue with a follow-up response.
+func (t *Tool) IsFinal(validatedResult map[string]any) bool {
+	status, ok := validatedResult["status"].(string)
+	return ok && status == "sent"
+}
+
+// groupNames returns the display names of the active groups userID is a member of, keyed by group ID.
+// Groups whose profile cannot be read are skipped, as are groups the user is indexed in but has since left.
+func (t *Tool) groupNames(ctx context.Context, userID string) (map[string]string, error) {
+	groupIDs, err := t.groupProfileService.GroupIDsForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string, len(groupIDs))
+	for _, groupID := range groupIDs {
+		profile, err := t.groupProfileService.GetGroupProfile(ctx, groupID)
+		if err != nil {
+			t.logger.WarnContext(ctx, "failed to get group profile, skipping its events",
+				slog.String("groupID", groupID),
+				slog.Any("error", err),
+			)
+			continue
+		}
+		if profile.Inactive || !slices.Contains(profile.MemberIDs, userID) {
+			continue
+		}
+		names[groupID] = profile.DisplayName
+	}
+	return names, nil
+}
@@ -0,0 +1,302 @@
+package mine_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+	"yuruppu/internal/event"
+	"yuruppu/internal/groupprofile"
+	"yuruppu/internal/line"
+	"yuruppu/internal/toolset/event/card"
+	"yuruppu/internal/toolset/event/mine"
+	"yuruppu/internal/userprofile"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// Test Helpers
+// =============================================================================
+
+func withUserContext(ctx context.Context) context.Context {
+	ctx = line.WithSourceID(ctx, "user-123")
+	ctx = line.WithUserID(ctx, "user-123")
+	return line.WithReplyToken(ctx, "reply-token")
+}
+
+func testEvent(groupID, title string, startsIn time.Duration) *event.Event {
+	start := time.Now().Add(startsIn)
+	return &event.Event{
+		ChatRoomID: groupID,
+		CreatorID:  "creator-1",
+		Title:      title,
+		StartTime:  start,
+		EndTime:    start.Add(2 * time.Hour),
+	}
+}
+
+// twoGroups returns a group profile service where user-123 belongs to Group A and Group B.
+func twoGroups() *mockGroupProfileService {
+	return &mockGroupProfileService{
+		groupIDs: map[string][]string{"user-123": {"group-a", "group-b"}},
+		profiles: map[string]*groupprofile.GroupProfile{
+			"group-a": {DisplayName: "Group A", MemberIDs: []string{"user-123", "user-456"}},
+			"group-b": {DisplayName: "Group B", MemberIDs: []string{"user-123"}},
+			"group-c": {DisplayName: "Group C", MemberIDs: []string{"user-456"}},
+		},
+	}
+}
+
+func newTool(t *testing.T, eventService *mockEventService, groupProfileService *mockGroupProfileService, lineClient *mockLineClient, limit int) *mine.Tool {
+	t.Helper()
+	tool, err := mine.New(eventService, groupProfileService, lineClient, &mockUserProfileService{}, limit, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+	return tool
+}
+
+// cardHeaders returns the texts in each bubble's header of a carousel.
+func cardHeaders(t *testing.T, flexJSON []byte) [][]string {
+	t.Helper()
+	var carousel struct {
+		Contents []struct {
+			Header struct {
+				Contents []struct {
+					Text string `json:"text"`
+				} `json:"contents"`
+			} `json:"header"`
+		} `json:"contents"`
+	}
+	require.NoError(t, json.Unmarshal(flexJSON, &carousel))
+	headers := make([][]string, len(carousel.Contents))
+	for i, bubble := range carousel.Contents {
+		for _, c := range bubble.Header.Contents {
+			headers[i] = append(headers[i], c.Text)
+		}
+	}
+	return headers
+}
+
+// =============================================================================
+// New() Tests
+// =============================================================================
+
+func TestNew(t *testing.T) {
+	t.Run("creates tool with valid dependencies", func(t *testing.T) {
+		tool, err := mine.New(&mockEventService{}, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, 5, slog.New(slog.DiscardHandler))
+
+		require.NoError(t, err)
+		assert.Equal(t, "all_my_events", tool.Name())
+	})
+
+	t.Run("returns error when groupProfileService is nil", func(t *testing.T) {
+		tool, err := mine.New(&mockEventService{}, nil, &mockLineClient{}, &mockUserProfileService{}, 5, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "groupProfileService cannot be nil")
+	})
+
+	t.Run("returns error when limit is not positive", func(t *testing.T) {
+		tool, err := mine.New(&mockEventService{}, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, 0, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "limit must be positive")
+	})
+
+	t.Run("returns error when limit exceeds the carousel size", func(t *testing.T) {
+		tool, err := mine.New(&mockEventService{}, &mockGroupProfileService{}, &mockLineClient{}, &mockUserProfileService{}, 4, slog.New(slog.DiscardHandler), card.WithCarouselSize(3))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "limit cannot exceed the carousel size (3)")
+	})
+}
+
+// =============================================================================
+// Callback Tests
+// =============================================================================
+
+func TestTool_Callback(t *testing.T) {
+	t.Run("merges events of the user's groups chronologically", func(t *testing.T) {
+		eventService := &mockEventService{listEvents: []*event.Event{
+			testEvent("group-a", "Board Games", 72*time.Hour),
+			testEvent("group-c", "Karaoke", 12*time.Hour),
+			testEvent("group-b", "Hiking", 48*time.Hour),
+			testEvent("group-a", "Picnic", 24*time.Hour),
+			testEvent("group-b", "Cafe", 96*time.Hour),
+		}}
+		lineClient := &mockLineClient{}
+		tool := newTool(t, eventService, twoGroups(), lineClient, 5)
+
+		result, err := tool.Callback(withUserContext(t.Context()), map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"status": "sent", "has_more": false}, result)
+		assert.True(t, tool.IsFinal(result))
+		assert.Equal(t, "参加グループのイベント（4件）", lineClient.lastAltText)
+		assert.Equal(t, [][]string{
+			{"Group A", "Picnic", "by ？？？"},
+			{"Group B", "Hiking", "by ？？？"},
+			{"Group A", "Board Games", "by ？？？"},
+			{"Group B", "Cafe", "by ？？？"},
+		}, cardHeaders(t, lineClient.lastFlexJSON))
+		require.NotNil(t, eventService.lastOpts.Start)
+	})
+
+	t.Run("applies limit and reports more", func(t *testing.T) {
+		eventService := &mockEventService{listEvents: []*event.Event{
+			testEvent("group-a", "Picnic", 24*time.Hour),
+			testEvent("group-b", "Hiking", 48*time.Hour),
+			testEvent("group-a", "Board Games", 72*time.Hour),
+		}}
+		lineClient := &mockLineClient{}
+		tool := newTool(t, eventService, twoGroups(), lineClient, 2)
+
+		result, err := tool.Callback(withUserContext(t.Context()), map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"status": "sent", "has_more": true}, result)
+		assert.Equal(t, "参加グループのイベント（2件）", lineClient.lastAltText)
+		assert.NotContains(t, string(lineClient.lastFlexJSON), "Board Games")
+	})
+
+	t.Run("skips groups the user has left or the bot is no longer in", func(t *testing.T) {
+		groups := twoGroups()
+		groups.groupIDs["user-123"] = append(groups.groupIDs["user-123"], "group-c", "group-d")
+		groups.profiles["group-d"] = &groupprofile.GroupProfile{DisplayName: "Group D", MemberIDs: []string{"user-123"}, Inactive: true}
+		eventService := &mockEventService{listEvents: []*event.Event{
+			testEvent("group-c", "Karaoke", 12*time.Hour),
+			testEvent("group-d", "Bowling", 18*time.Hour),
+			testEvent("group-a", "Picnic", 24*time.Hour),
+		}}
+		lineClient := &mockLineClient{}
+		tool := newTool(t, eventService, groups, lineClient, 5)
+
+		_, err := tool.Callback(withUserContext(t.Context()), map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"Group A", "Picnic", "by ？？？"}}, cardHeaders(t, lineClient.lastFlexJSON))
+	})
+
+	t.Run("skips groups whose profile cannot be read", func(t *testing.T) {
+		groups := twoGroups()
+		delete(groups.profiles, "group-b")
+		eventService := &mockEventService{listEvents: []*event.Event{
+			testEvent("group-b", "Hiking", 12*time.Hour),
+			testEvent("group-a", "Picnic", 24*time.Hour),
+		}}
+		lineClient := &mockLineClient{}
+		tool := newTool(t, eventService, groups, lineClient, 5)
+
+		_, err := tool.Callback(withUserContext(t.Context()), map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"Group A", "Picnic", "by ？？？"}}, cardHeaders(t, lineClient.lastFlexJSON))
+	})
+
+	t.Run("returns no_events when the user is in no group", func(t *testing.T) {
+		eventService := &mockEventService{listEvents: []*event.Event{
+			testEvent("group-a", "Picnic", 24*time.Hour),
+		}}
+		lineClient := &mockLineClient{}
+		tool := newTool(t, eventService, &mockGroupProfileService{}, lineClient, 5)
+
+		result, err := tool.Callback(withUserContext(t.Context()), map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"status": "no_events"}, result)
+		assert.False(t, tool.IsFinal(result))
+		assert.Equal(t, 0, lineClient.sendFlexReplyCount)
+	})
+
+	t.Run("returns no_events when the user's groups have no events", func(t *testing.T) {
+		eventService := &mockEventService{listEvents: []*event.Event{
+			testEvent("group-c", "Karaoke", 12*time.Hour),
+		}}
+		lineClient := &mockLineClient{}
+		tool := newTool(t, eventService, twoGroups(), lineClient, 5)
+
+		result, err := tool.Callback(withUserContext(t.Context()), map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"status": "no_events"}, result)
+		assert.Equal(t, 0, lineClient.sendFlexReplyCount)
+	})
+
+	t.Run("returns error when the group lookup fails", func(t *testing.T) {
+		groups := &mockGroupProfileService{groupIDsErr: errors.New("storage error")}
+		tool := newTool(t, &mockEventService{}, groups, &mockLineClient{}, 5)
+
+		_, err := tool.Callback(withUserContext(t.Context()), map[string]any{})
+
+		require.Error(t, err)
+		assert.Equal(t, "failed to look up groups", err.Error())
+	})
+
+	t.Run("returns error when list fails", func(t *testing.T) {
+		eventService := &mockEventService{listErr: errors.New("storage error")}
+		tool := newTool(t, eventService, twoGroups(), &mockLineClient{}, 5)
+
+		_, err := tool.Callback(withUserContext(t.Context()), map[string]any{})
+
+		require.Error(t, err)
+		assert.Equal(t, "failed to list events", err.Error())
+	})
+}
+
+// =============================================================================
+// Mocks
+// =============================================================================
+
+type mockEventService struct {
+	listEvents []*event.Event
+	listErr    error
+	lastOpts   event.ListOptions
+}
+
+func (m *mockEventService) List(ctx context.Context, opts event.ListOptions) ([]*event.Event, error) {
+	m.lastOpts = opts
+	return m.listEvents, m.listErr
+}
+
+type mockGroupProfileService struct {
+	groupIDs    map[string][]string
+	groupIDsErr error
+	profiles    map[string]*groupprofile.GroupProfile
+}
+
+func (m *mockGroupProfileService) GetGroupProfile(ctx context.Context, groupID string) (*groupprofile.GroupProfile, error) {
+	profile, ok := m.profiles[groupID]
+	if !ok {
+		return nil, errors.New("group profile not found")
+	}
+	return profile, nil
+}
+
+func (m *mockGroupProfileService) GroupIDsForUser(ctx context.Context, userID string) ([]string, error) {
+	return m.groupIDs[userID], m.groupIDsErr
+}
+
+type mockLineClient struct {
+	sendFlexReplyCount int
+	lastAltText        string
+	lastFlexJSON       []byte
+}
+
+func (m *mockLineClient) SendFlex(ctx context.Context, altText string, flexJSON []byte) error {
+	m.sendFlexReplyCount++
+	m.lastAltText = altText
+	m.lastFlexJSON = flexJSON
+	return nil
+}
+
+type mockUserProfileService struct{}
+
+func (m *mockUserProfileService) GetUserProfiles(ctx context.Context, userIDs []string) (map[string]*userprofile.UserProfile, error) {
+	return map[string]*userprofile.UserProfile{}, nil
+}
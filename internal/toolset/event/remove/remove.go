@@ -5,6 +5,7 @@ import (
 	_ "embed"
 	"errors"
 	"log/slog"
+	"yuruppu/internal/agent"
 	"yuruppu/internal/event"
 	"yuruppu/internal/line"
 )
@@ -66,30 +67,33 @@ func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]an
 	sourceID, ok := line.SourceIDFromContext(ctx)
 	if !ok {
 		t.logger.ErrorContext(ctx, "source ID not found in context")
-		return nil, errors.New("internal error")
+		return nil, agent.NewSystemError("internal error", nil)
 	}
 	userID, ok := line.UserIDFromContext(ctx)
 	if !ok {
 		t.logger.ErrorContext(ctx, "user ID not found in context")
-		return nil, errors.New("internal error")
+		return nil, agent.NewSystemError("internal error", nil)
 	}
 
 	// Get existing event to check authorization
 	ev, err := t.eventService.Get(ctx, sourceID)
 	if err != nil {
-		t.logger.ErrorContext(ctx, "event not found", slog.String("chatRoomID", sourceID), slog.Any("error", err))
-		return nil, errors.New("event not found")
+		if errors.As(err, new(*event.NotFoundError)) {
+			return nil, agent.NewUserError("event not found")
+		}
+		t.logger.ErrorContext(ctx, "failed to get event", slog.String("chatRoomID", sourceID), slog.Any("error", err))
+		return nil, agent.NewSystemError("failed to get event", err)
 	}
 
 	// Check authorization
 	if ev.CreatorID != userID {
-		return nil, errors.New("only the event creator can remove the event")
+		return nil, agent.NewUserError("only the event creator can remove the event")
 	}
 
 	// Remove event
 	if err := t.eventService.Remove(ctx, sourceID); err != nil {
 		t.logger.ErrorContext(ctx, "failed to remove event", slog.Any("error", err))
-		return nil, errors.New("failed to remove event")
+		return nil, agent.NewSystemError("failed to remove event", err)
 	}
 
 	return map[string]any{
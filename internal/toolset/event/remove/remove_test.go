@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log/slog"
 	"testing"
+	"yuruppu/internal/agent"
 	"yuruppu/internal/event"
 	"yuruppu/internal/line"
 	"yuruppu/internal/toolset/event/remove"
@@ -223,7 +224,7 @@ func TestTool_Callback_NotFoundError(t *testing.T) {
 	// AC-006: イベント削除（イベントが存在しない）[FR-010]
 	t.Run("returns error when event does not exist in current chat room", func(t *testing.T) {
 		service := &mockEventService{
-			getErr: errors.New("event not found: group-123"),
+			getErr: &event.NotFoundError{ChatRoomID: "group-123"},
 		}
 		tool, _ := remove.New(service, slog.New(slog.DiscardHandler))
 
@@ -234,6 +235,8 @@ func TestTool_Callback_NotFoundError(t *testing.T) {
 
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "event not found")
+		var userErr *agent.UserError
+		assert.ErrorAs(t, err, &userErr)
 
 		// Get should be called
 		assert.Equal(t, 1, service.getCount)
@@ -259,7 +262,9 @@ func TestTool_Callback_ServiceErrors(t *testing.T) {
 		_, err := tool.Callback(ctx, args)
 
 		require.Error(t, err)
-		assert.Contains(t, err.Error(), "event not found")
+		assert.Equal(t, "failed to get event", err.Error())
+		var systemErr *agent.SystemError
+		assert.ErrorAs(t, err, &systemErr)
 		assert.Equal(t, 1, service.getCount)
 		assert.Equal(t, 0, service.removeCount)
 	})
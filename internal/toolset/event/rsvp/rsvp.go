@@ -0,0 +1,162 @@
+package rsvp
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"log/slog"
+	"slices"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/event"
+	"yuruppu/internal/groupprofile"
+	"yuruppu/internal/line"
+	"yuruppu/internal/userprofile"
+)
+
+//go:embed parameters.json
+var parametersSchema []byte
+
+//go:embed response.json
+var responseSchema []byte
+
+// EventService provides access to event operations.
+type EventService interface {
+	Get(ctx context.Context, chatRoomID string) (*event.Event, error)
+}
+
+// GroupProfileService provides access to group profile operations.
+type GroupProfileService interface {
+	GetGroupProfile(ctx context.Context, groupID string) (*groupprofile.GroupProfile, error)
+}
+
+// UserProfileService provides access to user profile operations.
+type UserProfileService interface {
+	GetUserProfiles(ctx context.Context, userIDs []string) (map[string]*userprofile.UserProfile, error)
+}
+
+// Tool implements the rsvp_status tool for showing who has and hasn't responded to an event.
+type Tool struct {
+	eventService        EventService
+	groupProfileService GroupProfileService
+	userProfileService  UserProfileService
+	logger              *slog.Logger
+}
+
+// New creates a new rsvp_status tool.
+func New(eventService EventService, groupProfileService GroupProfileService, userProfileService UserProfileService, logger *slog.Logger) (*Tool, error) {
+	if eventService == nil {
+		return nil, errors.New("eventService cannot be nil")
+	}
+	if groupProfileService == nil {
+		return nil, errors.New("groupProfileService cannot be nil")
+	}
+	if userProfileService == nil {
+		return nil, errors.New("userProfileService cannot be nil")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil")
+	}
+	return &Tool{
+		eventService:        eventService,
+		groupProfileService: groupProfileService,
+		userProfileService:  userProfileService,
+		logger:              logger,
+	}, nil
+}
+
+// Name returns the tool name.
+func (t *Tool) Name() string {
+	return "rsvp_status"
+}
+
+// Description returns a description for the LLM.
+func (t *Tool) Description() string {
+	return "Use this tool when someone asks who is going to the event in the current group chat, who is on the waitlist, or who in the group has not responded yet. Returns the attendees, the waitlist, and the known group members without a response, with display names. Group members who have not joined or spoken since the bot arrived are not known and are missing from the no-response list."
+}
+
+// ParametersJsonSchema returns the JSON Schema for input parameters.
+func (t *Tool) ParametersJsonSchema() []byte {
+	return parametersSchema
+}
+
+// ResponseJsonSchema returns the JSON Schema for the response.
+func (t *Tool) ResponseJsonSchema() []byte {
+	return responseSchema
+}
+
+// Callback returns the event's group members split into going, waitlisted, and no-response.
+// Only the current chat room is accepted so that users cannot list other groups' members.
+func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]any, error) {
+	chatRoomID, ok := line.SourceIDFromContext(ctx)
+	if !ok {
+		t.logger.ErrorContext(ctx, "source ID not found in context")
+		return nil, agent.NewSystemError("internal error", nil)
+	}
+
+	ev, err := t.eventService.Get(ctx, chatRoomID)
+	if err != nil {
+		if errors.As(err, new(*event.NotFoundError)) {
+			return map[string]any{
+				"status": "not_found",
+			}, nil
+		}
+		t.logger.ErrorContext(ctx, "failed to get event", slog.String("chatRoomID", chatRoomID), slog.Any("error", err))
+		return nil, agent.NewSystemError("failed to get event", err)
+	}
+
+	profile, err := t.groupProfileService.GetGroupProfile(ctx, ev.ChatRoomID)
+	if err != nil {
+		t.logger.ErrorContext(ctx, "failed to get group profile", slog.String("chatRoomID", ev.ChatRoomID), slog.Any("error", err))
+		return nil, agent.NewSystemError("failed to get group members", err)
+	}
+
+	var noResponse []string
+	for _, memberID := range profile.MemberIDs {
+		if !slices.Contains(ev.Attendees, memberID) && !slices.Contains(ev.Waitlist, memberID) {
+			noResponse = append(noResponse, memberID)
+		}
+	}
+
+	names := t.displayNames(ctx, slices.Concat(ev.Attendees, ev.Waitlist, noResponse))
+	return map[string]any{
+		"status":      "ok",
+		"going":       members(ev.Attendees, names),
+		"waitlisted":  members(ev.Waitlist, names),
+		"no_response": members(noResponse, names),
+	}, nil
+}
+
+// displayNames resolves the display names of userIDs with one batch lookup.
+// It returns nil if the lookup fails so that the lists carry user IDs only.
+func (t *Tool) displayNames(ctx context.Context, userIDs []string) map[string]string {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	profiles, err := t.userProfileService.GetUserProfiles(ctx, userIDs)
+	if err != nil {
+		t.logger.WarnContext(ctx, "failed to get user profiles, omitting display names", slog.Any("error", err))
+		return nil
+	}
+	names := make(map[string]string, len(profiles))
+	for userID, p := range profiles {
+		if p != nil && p.DisplayName != "" {
+			names[userID] = p.DisplayName
+		}
+	}
+	return names
+}
+
+// members builds the response entries for userIDs.
+// The entries are collected in []any so that the response validates as a JSON array.
+func members(userIDs []string, names map[string]string) []any {
+	result := make([]any, 0, len(userIDs))
+	for _, userID := range userIDs {
+		entry := map[string]any{"user_id": userID}
+		if name, ok := names[userID]; ok {
+			entry["display_name"] = name
+		}
+		result = append(result, entry)
+	}
+	return result
+}
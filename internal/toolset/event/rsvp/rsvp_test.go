@@ -0,0 +1,276 @@
+package rsvp_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"yuruppu/internal/event"
+	"yuruppu/internal/groupprofile"
+	"yuruppu/internal/line"
+	"yuruppu/internal/toolset/event/rsvp"
+	"yuruppu/internal/userprofile"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// New() Tests
+// =============================================================================
+
+func TestNew(t *testing.T) {
+	t.Run("creates tool with valid services", func(t *testing.T) {
+		tool, err := rsvp.New(&mockEventService{}, &mockGroupProfileService{}, &mockUserProfileService{}, slog.New(slog.DiscardHandler))
+
+		require.NoError(t, err)
+		require.NotNil(t, tool)
+		assert.Equal(t, "rsvp_status", tool.Name())
+	})
+
+	t.Run("returns error when eventService is nil", func(t *testing.T) {
+		tool, err := rsvp.New(nil, &mockGroupProfileService{}, &mockUserProfileService{}, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "eventService cannot be nil")
+	})
+
+	t.Run("returns error when groupProfileService is nil", func(t *testing.T) {
+		tool, err := rsvp.New(&mockEventService{}, nil, &mockUserProfileService{}, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "groupProfileService cannot be nil")
+	})
+
+	t.Run("returns error when userProfileService is nil", func(t *testing.T) {
+		tool, err := rsvp.New(&mockEventService{}, &mockGroupProfileService{}, nil, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "userProfileService cannot be nil")
+	})
+
+	t.Run("returns error when logger is nil", func(t *testing.T) {
+		tool, err := rsvp.New(&mockEventService{}, &mockGroupProfileService{}, &mockUserProfileService{}, nil)
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "logger cannot be nil")
+	})
+}
+
+// =============================================================================
+// Callback() Tests
+// =============================================================================
+
+func TestTool_Callback(t *testing.T) {
+	t.Run("splits group members into going, waitlisted, and no response", func(t *testing.T) {
+		eventService := &mockEventService{
+			getEvent: &event.Event{
+				ChatRoomID: "group-123",
+				Capacity:   2,
+				Attendees:  []string{"user-1", "user-2"},
+				Waitlist:   []string{"user-3"},
+			},
+		}
+		groupProfileService := &mockGroupProfileService{
+			profile: &groupprofile.GroupProfile{
+				MemberIDs: []string{"user-4", "user-1", "user-2", "user-3", "user-5"},
+			},
+		}
+		userProfileService := &mockUserProfileService{
+			profiles: map[string]*userprofile.UserProfile{
+				"user-1": {DisplayName: "Alice"},
+				"user-2": {DisplayName: "Bob"},
+				"user-3": {DisplayName: "Carol"},
+				"user-4": {DisplayName: "Dave"},
+			},
+		}
+		tool, err := rsvp.New(eventService, groupProfileService, userProfileService, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := line.WithSourceID(t.Context(), "group-123")
+		result, err := tool.Callback(ctx, map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, "group-123", eventService.lastGetChatRoomID)
+		assert.Equal(t, "group-123", groupProfileService.lastGroupID)
+		assert.Equal(t, map[string]any{
+			"status": "ok",
+			"going": []any{
+				map[string]any{"user_id": "user-1", "display_name": "Alice"},
+				map[string]any{"user_id": "user-2", "display_name": "Bob"},
+			},
+			"waitlisted": []any{
+				map[string]any{"user_id": "user-3", "display_name": "Carol"},
+			},
+			"no_response": []any{
+				map[string]any{"user_id": "user-4", "display_name": "Dave"},
+				map[string]any{"user_id": "user-5"},
+			},
+		}, result)
+		assert.Len(t, userProfileService.calls, 1, "display names should be resolved in one batch")
+		assert.ElementsMatch(t, []string{"user-1", "user-2", "user-3", "user-4", "user-5"}, userProfileService.calls[0])
+	})
+
+	t.Run("keeps attendees who are not recorded as members", func(t *testing.T) {
+		eventService := &mockEventService{
+			getEvent: &event.Event{
+				ChatRoomID: "group-123",
+				Attendees:  []string{"user-1"},
+			},
+		}
+		groupProfileService := &mockGroupProfileService{
+			profile: &groupprofile.GroupProfile{MemberIDs: []string{"user-2"}},
+		}
+		tool, err := rsvp.New(eventService, groupProfileService, &mockUserProfileService{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := line.WithSourceID(t.Context(), "group-123")
+		result, err := tool.Callback(ctx, map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, []any{map[string]any{"user_id": "user-1"}}, result["going"])
+		assert.Equal(t, []any{}, result["waitlisted"])
+		assert.Equal(t, []any{map[string]any{"user_id": "user-2"}}, result["no_response"])
+	})
+
+	t.Run("omits display names when the profile lookup fails", func(t *testing.T) {
+		eventService := &mockEventService{
+			getEvent: &event.Event{
+				ChatRoomID: "group-123",
+				Attendees:  []string{"user-1"},
+			},
+		}
+		groupProfileService := &mockGroupProfileService{
+			profile: &groupprofile.GroupProfile{MemberIDs: []string{"user-1", "user-2"}},
+		}
+		userProfileService := &mockUserProfileService{err: errors.New("storage error")}
+		tool, err := rsvp.New(eventService, groupProfileService, userProfileService, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := line.WithSourceID(t.Context(), "group-123")
+		result, err := tool.Callback(ctx, map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, []any{map[string]any{"user_id": "user-1"}}, result["going"])
+		assert.Equal(t, []any{map[string]any{"user_id": "user-2"}}, result["no_response"])
+	})
+
+	t.Run("only looks up the current chat's event", func(t *testing.T) {
+		eventService := &mockEventService{
+			getEvent: &event.Event{ChatRoomID: "group-123"},
+		}
+		groupProfileService := &mockGroupProfileService{profile: &groupprofile.GroupProfile{}}
+		tool, err := rsvp.New(eventService, groupProfileService, &mockUserProfileService{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := line.WithSourceID(t.Context(), "group-123")
+		result, err := tool.Callback(ctx, map[string]any{"chat_room_id": "group-other"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "ok", result["status"])
+		assert.Equal(t, "group-123", eventService.lastGetChatRoomID)
+		assert.Equal(t, "group-123", groupProfileService.lastGroupID)
+	})
+
+	t.Run("returns not_found when event does not exist", func(t *testing.T) {
+		eventService := &mockEventService{
+			getErr: &event.NotFoundError{ChatRoomID: "group-123"},
+		}
+		tool, err := rsvp.New(eventService, &mockGroupProfileService{}, &mockUserProfileService{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := line.WithSourceID(t.Context(), "group-123")
+		result, err := tool.Callback(ctx, map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"status": "not_found"}, result)
+	})
+
+	t.Run("returns error when event lookup fails", func(t *testing.T) {
+		eventService := &mockEventService{getErr: errors.New("storage error")}
+		tool, err := rsvp.New(eventService, &mockGroupProfileService{}, &mockUserProfileService{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := line.WithSourceID(t.Context(), "group-123")
+		result, err := tool.Callback(ctx, map[string]any{})
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+		assert.Equal(t, "failed to get event", err.Error())
+	})
+
+	t.Run("returns error when group profile lookup fails", func(t *testing.T) {
+		eventService := &mockEventService{getEvent: &event.Event{ChatRoomID: "group-123"}}
+		groupProfileService := &mockGroupProfileService{err: errors.New("storage error")}
+		tool, err := rsvp.New(eventService, groupProfileService, &mockUserProfileService{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := line.WithSourceID(t.Context(), "group-123")
+		result, err := tool.Callback(ctx, map[string]any{})
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+		assert.Equal(t, "failed to get group members", err.Error())
+	})
+
+	t.Run("returns error when source ID is missing", func(t *testing.T) {
+		tool, err := rsvp.New(&mockEventService{}, &mockGroupProfileService{}, &mockUserProfileService{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(t.Context(), map[string]any{})
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+		assert.Equal(t, "internal error", err.Error())
+	})
+}
+
+// =============================================================================
+// Mocks
+// =============================================================================
+
+type mockEventService struct {
+	getEvent          *event.Event
+	getErr            error
+	lastGetChatRoomID string
+}
+
+func (m *mockEventService) Get(ctx context.Context, chatRoomID string) (*event.Event, error) {
+	m.lastGetChatRoomID = chatRoomID
+	return m.getEvent, m.getErr
+}
+
+type mockGroupProfileService struct {
+	profile     *groupprofile.GroupProfile
+	err         error
+	lastGroupID string
+}
+
+func (m *mockGroupProfileService) GetGroupProfile(ctx context.Context, groupID string) (*groupprofile.GroupProfile, error) {
+	m.lastGroupID = groupID
+	return m.profile, m.err
+}
+
+type mockUserProfileService struct {
+	profiles map[string]*userprofile.UserProfile
+	err      error
+	calls    [][]string
+}
+
+func (m *mockUserProfileService) GetUserProfiles(ctx context.Context, userIDs []string) (map[string]*userprofile.UserProfile, error) {
+	m.calls = append(m.calls, userIDs)
+	if m.err != nil {
+		return nil, m.err
+	}
+	result := make(map[string]*userprofile.UserProfile)
+	for _, id := range userIDs {
+		if p, ok := m.profiles[id]; ok {
+			result[id] = p
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,182 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"text/template"
+	"time"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/clock"
+	"yuruppu/internal/event"
+	"yuruppu/internal/toolset/event/card"
+	"yuruppu/internal/userprofile"
+)
+
+//go:embed parameters.json
+var parametersSchema []byte
+
+//go:embed response.json
+var responseSchema []byte
+
+//go:embed alt.txt
+var altTemplate string
+
+// EventService provides access to event list operations.
+type EventService interface {
+	List(ctx context.Context, opts event.ListOptions) ([]*event.Event, error)
+}
+
+// LineClient provides LINE messaging operations.
+type LineClient interface {
+	SendFlex(ctx context.Context, altText string, flexJSON []byte) error
+}
+
+// UserProfileService provides user profile operations.
+type UserProfileService interface {
+	GetUserProfiles(ctx context.Context, userIDs []string) (map[string]*userprofile.UserProfile, error)
+}
+
+// Tool implements the search_events tool for finding events by keyword.
+type Tool struct {
+	eventService EventService
+	lineClient   LineClient
+	renderer     *card.Renderer
+	limit        int
+	logger       *slog.Logger
+}
+
+// New creates a new search_events tool.
+// limit is the maximum number of matches shown, as in list_events.
+// cardOpts customize the event cards sent as the result.
+func New(eventService EventService, lineClient LineClient, userProfileService UserProfileService, limit int, logger *slog.Logger, cardOpts ...card.Option) (*Tool, error) {
+	if eventService == nil {
+		return nil, errors.New("eventService cannot be nil")
+	}
+	if lineClient == nil {
+		return nil, errors.New("lineClient cannot be nil")
+	}
+	if userProfileService == nil {
+		return nil, errors.New("userProfileService cannot be nil")
+	}
+	if limit <= 0 {
+		return nil, errors.New("limit must be positive")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil")
+	}
+	renderer, err := card.NewRenderer(userProfileService, logger, cardOpts...)
+	if err != nil {
+		return nil, err
+	}
+	if limit > renderer.CarouselSize() {
+		return nil, fmt.Errorf("limit cannot exceed the carousel size (%d)", renderer.CarouselSize())
+	}
+	return &Tool{
+		eventService: eventService,
+		lineClient:   lineClient,
+		renderer:     renderer,
+		limit:        limit,
+		logger:       logger,
+	}, nil
+}
+
+// Name returns the tool name.
+func (t *Tool) Name() string {
+	return "search_events"
+}
+
+// Description returns a description for the LLM.
+func (t *Tool) Description() string {
+	return "Searches upcoming events by keyword in the title or description and sends matches as a Flex Message directly to the chat."
+}
+
+// ParametersJsonSchema returns the JSON Schema for input parameters.
+func (t *Tool) ParametersJsonSchema() []byte {
+	return parametersSchema
+}
+
+// ResponseJsonSchema returns the JSON Schema for the response.
+func (t *Tool) ResponseJsonSchema() []byte {
+	return responseSchema
+}
+
+// Callback searches events and sends matches as a Flex Message.
+func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]any, error) {
+
+	query, ok := args["query"].(string)
+	if !ok {
+		return agent.Invalid("invalid query"), nil
+	}
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return agent.Invalid("invalid query"), nil
+	}
+
+	// Search events from today, as list_events does by default
+	now := clock.Now(ctx).In(card.JST)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, card.JST)
+	events, err := t.eventService.List(ctx, event.ListOptions{Start: &today})
+	if err != nil {
+		t.logger.ErrorContext(ctx, "failed to list events", slog.Any("error", err))
+		return nil, agent.NewSystemError("failed to list events", err)
+	}
+
+	matches := make([]*event.Event, 0, len(events))
+	for _, ev := range events {
+		if strings.Contains(strings.ToLower(ev.Title), query) || strings.Contains(strings.ToLower(ev.Description), query) {
+			matches = append(matches, ev)
+		}
+	}
+	if len(matches) > t.limit {
+		matches = matches[:t.limit]
+	}
+
+	// If no matches, return no_events status without sending message
+	if len(matches) == 0 {
+		return map[string]any{
+			"status": "no_events",
+		}, nil
+	}
+
+	// Render alt text template
+	altTmpl, err := template.New("alt").Parse(altTemplate)
+	if err != nil {
+		t.logger.ErrorContext(ctx, "failed to parse alt template", slog.Any("error", err))
+		return nil, agent.NewSystemError("internal error", err)
+	}
+
+	var altBuf bytes.Buffer
+	if err := altTmpl.Execute(&altBuf, map[string]int{"Count": len(matches)}); err != nil {
+		t.logger.ErrorContext(ctx, "failed to execute alt template", slog.Any("error", err))
+		return nil, agent.NewSystemError("internal error", err)
+	}
+
+	// Render flex message
+	flexJSON, err := t.renderer.Render(ctx, matches)
+	if err != nil {
+		t.logger.ErrorContext(ctx, "failed to render flex message", slog.Any("error", err))
+		return nil, agent.NewSystemError("internal error", err)
+	}
+
+	// Send flex message
+	if err := t.lineClient.SendFlex(ctx, altBuf.String(), flexJSON); err != nil {
+		t.logger.ErrorContext(ctx, "failed to send flex message", slog.Any("error", err))
+		return nil, agent.NewSystemError("failed to send flex message", err)
+	}
+
+	return map[string]any{
+		"status": "sent",
+	}, nil
+}
+
+// IsFinal returns true if the flex message was sent successfully.
+// When status is "no_events", the LLM should continue with a follow-up response.
+func (t *Tool) IsFinal(validatedResult map[string]any) bool {
+	status, ok := validatedResult["status"].(string)
+	return ok && status == "sent"
+}
@@ -0,0 +1,201 @@
+package search_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+	"yuruppu/internal/event"
+	"yuruppu/internal/line"
+	"yuruppu/internal/toolset/event/card"
+	"yuruppu/internal/toolset/event/search"
+	"yuruppu/internal/userprofile"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// Test Helpers
+// =============================================================================
+
+func withSearchContext(ctx context.Context, replyToken string) context.Context {
+	ctx = line.WithSourceID(ctx, "group-123")
+	ctx = line.WithUserID(ctx, "user-123")
+	ctx = line.WithReplyToken(ctx, replyToken)
+	return ctx
+}
+
+func testEvent(title, description string) *event.Event {
+	start := time.Now().Add(24 * time.Hour)
+	return &event.Event{
+		ChatRoomID:  "room-" + title,
+		CreatorID:   "creator-1",
+		Title:       title,
+		StartTime:   start,
+		EndTime:     start.Add(2 * time.Hour),
+		Fee:         "1000円",
+		Capacity:    10,
+		Description: description,
+	}
+}
+
+func newTool(t *testing.T, eventService *mockEventService, lineClient *mockLineClient, limit int) *search.Tool {
+	t.Helper()
+	tool, err := search.New(eventService, lineClient, &mockUserProfileService{}, limit, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+	return tool
+}
+
+// =============================================================================
+// New() Tests
+// =============================================================================
+
+func TestNew(t *testing.T) {
+	t.Run("creates tool with valid dependencies", func(t *testing.T) {
+		tool, err := search.New(&mockEventService{}, &mockLineClient{}, &mockUserProfileService{}, 5, slog.New(slog.DiscardHandler))
+
+		require.NoError(t, err)
+		assert.Equal(t, "search_events", tool.Name())
+	})
+
+	t.Run("returns error when limit is not positive", func(t *testing.T) {
+		tool, err := search.New(&mockEventService{}, &mockLineClient{}, &mockUserProfileService{}, 0, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "limit must be positive")
+	})
+
+	t.Run("returns error when limit exceeds the carousel size", func(t *testing.T) {
+		tool, err := search.New(&mockEventService{}, &mockLineClient{}, &mockUserProfileService{}, 4, slog.New(slog.DiscardHandler), card.WithCarouselSize(3))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "limit cannot exceed the carousel size (3)")
+	})
+}
+
+// =============================================================================
+// Callback() Tests
+// =============================================================================
+
+func TestTool_Callback(t *testing.T) {
+	t.Run("matches title case-insensitively", func(t *testing.T) {
+		eventService := &mockEventService{listEvents: []*event.Event{
+			testEvent("Board Game Night", "Bring snacks"),
+			testEvent("Hiking", "Mt. Takao"),
+		}}
+		lineClient := &mockLineClient{}
+		tool := newTool(t, eventService, lineClient, 5)
+
+		result, err := tool.Callback(withSearchContext(t.Context(), "reply-token"), map[string]any{"query": "board GAME"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "sent", result["status"])
+		assert.True(t, tool.IsFinal(result))
+		assert.Equal(t, "reply-token", lineClient.lastReplyToken)
+		assert.Equal(t, "検索結果（1件）", lineClient.lastAltText)
+		assert.Contains(t, string(lineClient.lastFlexJSON), "Board Game Night")
+		assert.NotContains(t, string(lineClient.lastFlexJSON), "Hiking")
+		require.NotNil(t, eventService.lastOpts.Start)
+	})
+
+	t.Run("matches description", func(t *testing.T) {
+		eventService := &mockEventService{listEvents: []*event.Event{
+			testEvent("Board Game Night", "Bring snacks"),
+			testEvent("Hiking", "Meet at Mt. Takao station"),
+		}}
+		lineClient := &mockLineClient{}
+		tool := newTool(t, eventService, lineClient, 5)
+
+		result, err := tool.Callback(withSearchContext(t.Context(), "reply-token"), map[string]any{"query": "takao"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "sent", result["status"])
+		assert.Contains(t, string(lineClient.lastFlexJSON), "Hiking")
+		assert.NotContains(t, string(lineClient.lastFlexJSON), "Board Game Night")
+	})
+
+	t.Run("applies limit to matches", func(t *testing.T) {
+		eventService := &mockEventService{listEvents: []*event.Event{
+			testEvent("Party A", ""),
+			testEvent("Party B", ""),
+			testEvent("Party C", ""),
+		}}
+		lineClient := &mockLineClient{}
+		tool := newTool(t, eventService, lineClient, 2)
+
+		_, err := tool.Callback(withSearchContext(t.Context(), "reply-token"), map[string]any{"query": "party"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "検索結果（2件）", lineClient.lastAltText)
+		assert.NotContains(t, string(lineClient.lastFlexJSON), "Party C")
+	})
+
+	t.Run("returns no_events when nothing matches", func(t *testing.T) {
+		eventService := &mockEventService{listEvents: []*event.Event{
+			testEvent("Board Game Night", "Bring snacks"),
+		}}
+		lineClient := &mockLineClient{}
+		tool := newTool(t, eventService, lineClient, 5)
+
+		result, err := tool.Callback(withSearchContext(t.Context(), "reply-token"), map[string]any{"query": "karaoke"})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"status": "no_events"}, result)
+		assert.False(t, tool.IsFinal(result))
+		assert.Equal(t, 0, lineClient.sendFlexReplyCount)
+	})
+
+	t.Run("returns error when list fails", func(t *testing.T) {
+		eventService := &mockEventService{listErr: errors.New("storage error")}
+		tool := newTool(t, eventService, &mockLineClient{}, 5)
+
+		_, err := tool.Callback(withSearchContext(t.Context(), "reply-token"), map[string]any{"query": "party"})
+
+		require.Error(t, err)
+		assert.Equal(t, "failed to list events", err.Error())
+	})
+}
+
+// =============================================================================
+// Mocks
+// =============================================================================
+
+type mockEventService struct {
+	listEvents []*event.Event
+	listErr    error
+	lastOpts   event.ListOptions
+}
+
+func (m *mockEventService) List(ctx context.Context, opts event.ListOptions) ([]*event.Event, error) {
+	m.lastOpts = opts
+	return m.listEvents, m.listErr
+}
+
+type mockLineClient struct {
+	sendFlexReplyCount int
+	lastReplyToken     string
+	lastAltText        string
+	lastFlexJSON       []byte
+}
+
+func (m *mockLineClient) SendFlex(ctx context.Context, altText string, flexJSON []byte) error {
+	m.sendFlexReplyCount++
+	m.lastReplyToken, _ = line.ReplyTokenFromContext(ctx)
+	m.lastAltText = altText
+	m.lastFlexJSON = flexJSON
+	return nil
+}
+
+type mockUserProfileService struct{}
+
+func (m *mockUserProfileService) GetUserProfiles(ctx context.Context, userIDs []string) (map[string]*userprofile.UserProfile, error) {
+	profiles := make(map[string]*userprofile.UserProfile, len(userIDs))
+	for _, id := range userIDs {
+		profiles[id] = &userprofile.UserProfile{DisplayName: "Creator"}
+	}
+	return profiles, nil
+}
@@ -0,0 +1,114 @@
+package showcreator
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"log/slog"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/event"
+	"yuruppu/internal/line"
+)
+
+//go:embed parameters.json
+var parametersSchema []byte
+
+//go:embed response.json
+var responseSchema []byte
+
+// EventService provides access to event operations.
+type EventService interface {
+	Get(ctx context.Context, chatRoomID string) (*event.Event, error)
+	ToggleShowCreator(ctx context.Context, chatRoomID string) (bool, error)
+}
+
+// Tool implements the toggle_show_creator tool for hiding or revealing an event's creator on its cards.
+type Tool struct {
+	eventService EventService
+	logger       *slog.Logger
+}
+
+// New creates a new toggle_show_creator tool.
+func New(eventService EventService, logger *slog.Logger) (*Tool, error) {
+	if eventService == nil {
+		return nil, errors.New("eventService cannot be nil")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil")
+	}
+	return &Tool{
+		eventService: eventService,
+		logger:       logger,
+	}, nil
+}
+
+// Name returns the tool name.
+func (t *Tool) Name() string {
+	return "toggle_show_creator"
+}
+
+// Description returns a description for the LLM.
+func (t *Tool) Description() string {
+	return "Use this tool when the event creator wants to hide or reveal their name on the event cards. Each call flips the current setting, so call it only when the requested setting differs from the current one, and tell the user the resulting show_creator. Only the event creator can change it."
+}
+
+// ParametersJsonSchema returns the JSON Schema for input parameters.
+func (t *Tool) ParametersJsonSchema() []byte {
+	return parametersSchema
+}
+
+// ResponseJsonSchema returns the JSON Schema for the response.
+func (t *Tool) ResponseJsonSchema() []byte {
+	return responseSchema
+}
+
+// Callback flips whether an event shows its creator.
+func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]any, error) {
+	chatRoomID, ok := line.SourceIDFromContext(ctx)
+	if !ok {
+		t.logger.ErrorContext(ctx, "source ID not found in context")
+		return nil, agent.NewSystemError("internal error", nil)
+	}
+	if chatRoomIDArg, ok := args["chat_room_id"]; ok {
+		chatRoomID, ok = chatRoomIDArg.(string)
+		if !ok || chatRoomID == "" {
+			return agent.Invalid("invalid chat_room_id"), nil
+		}
+	}
+
+	userID, ok := line.UserIDFromContext(ctx)
+	if !ok {
+		t.logger.ErrorContext(ctx, "user ID not found in context")
+		return nil, agent.NewSystemError("internal error", nil)
+	}
+
+	ev, err := t.eventService.Get(ctx, chatRoomID)
+	if err != nil {
+		if errors.As(err, new(*event.NotFoundError)) {
+			return map[string]any{"status": "not_found"}, nil
+		}
+		t.logger.ErrorContext(ctx, "failed to get event", slog.String("chatRoomID", chatRoomID), slog.Any("error", err))
+		return nil, agent.NewSystemError("failed to get event", err)
+	}
+
+	if ev.CreatorID != userID {
+		return map[string]any{"status": "forbidden"}, nil
+	}
+
+	showCreator, err := t.eventService.ToggleShowCreator(ctx, chatRoomID)
+	if err != nil {
+		if errors.As(err, new(*event.NotFoundError)) {
+			return map[string]any{"status": "not_found"}, nil
+		}
+		t.logger.ErrorContext(ctx, "failed to toggle show creator",
+			slog.String("chatRoomID", chatRoomID),
+			slog.Any("error", err),
+		)
+		return nil, agent.NewSystemError("failed to change show creator", err)
+	}
+
+	return map[string]any{
+		"status":       "ok",
+		"show_creator": showCreator,
+	}, nil
+}
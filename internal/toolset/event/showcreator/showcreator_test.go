@@ -0,0 +1,188 @@
+package showcreator_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/event"
+	"yuruppu/internal/line"
+	"yuruppu/internal/toolset/event/showcreator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// Test Helpers
+// =============================================================================
+
+func withEventContext(ctx context.Context, sourceID, userID string) context.Context {
+	ctx = line.WithSourceID(ctx, sourceID)
+	ctx = line.WithUserID(ctx, userID)
+	return ctx
+}
+
+func newTestTool(t *testing.T, eventService *mockEventService) *showcreator.Tool {
+	t.Helper()
+	tool, err := showcreator.New(eventService, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+	return tool
+}
+
+func testEvent() *event.Event {
+	return &event.Event{ChatRoomID: "group-123", CreatorID: "user-creator", Title: "Team Meeting", ShowCreator: true}
+}
+
+// =============================================================================
+// New() Tests
+// =============================================================================
+
+func TestNew(t *testing.T) {
+	logger := slog.New(slog.DiscardHandler)
+
+	t.Run("creates tool with valid dependencies", func(t *testing.T) {
+		tool, err := showcreator.New(&mockEventService{}, logger)
+
+		require.NoError(t, err)
+		assert.Equal(t, "toggle_show_creator", tool.Name())
+	})
+
+	t.Run("returns error when eventService is nil", func(t *testing.T) {
+		tool, err := showcreator.New(nil, logger)
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "eventService cannot be nil")
+	})
+
+	t.Run("returns error when logger is nil", func(t *testing.T) {
+		tool, err := showcreator.New(&mockEventService{}, nil)
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "logger cannot be nil")
+	})
+}
+
+// =============================================================================
+// Callback Tests
+// =============================================================================
+
+func TestTool_Callback(t *testing.T) {
+	t.Run("creator hides themselves", func(t *testing.T) {
+		eventService := &mockEventService{getEvent: testEvent(), toggleResult: false}
+		tool := newTestTool(t, eventService)
+
+		result, err := tool.Callback(withEventContext(t.Context(), "group-123", "user-creator"), map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"status": "ok", "show_creator": false}, result)
+		require.Equal(t, 1, eventService.toggleCount)
+		assert.Equal(t, "group-123", eventService.lastToggleChatRoomID)
+	})
+
+	t.Run("uses chat_room_id argument when provided", func(t *testing.T) {
+		eventService := &mockEventService{getEvent: testEvent()}
+		tool := newTestTool(t, eventService)
+
+		result, err := tool.Callback(withEventContext(t.Context(), "user-creator", "user-creator"), map[string]any{
+			"chat_room_id": "group-123",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "ok", result["status"])
+		assert.Equal(t, "group-123", eventService.lastGetChatRoomID)
+		assert.Equal(t, "group-123", eventService.lastToggleChatRoomID)
+	})
+
+	t.Run("rejects a user who is not the creator", func(t *testing.T) {
+		eventService := &mockEventService{getEvent: testEvent()}
+		tool := newTestTool(t, eventService)
+
+		result, err := tool.Callback(withEventContext(t.Context(), "group-123", "user-other"), map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"status": "forbidden"}, result)
+		assert.Equal(t, 0, eventService.toggleCount)
+	})
+
+	t.Run("returns not_found when event does not exist", func(t *testing.T) {
+		eventService := &mockEventService{getErr: &event.NotFoundError{ChatRoomID: "group-123"}}
+		tool := newTestTool(t, eventService)
+
+		result, err := tool.Callback(withEventContext(t.Context(), "group-123", "user-creator"), map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"status": "not_found"}, result)
+		assert.Equal(t, 0, eventService.toggleCount)
+	})
+}
+
+func TestTool_Callback_Errors(t *testing.T) {
+	t.Run("returns error when sourceID not in context", func(t *testing.T) {
+		tool := newTestTool(t, &mockEventService{})
+
+		_, err := tool.Callback(line.WithUserID(t.Context(), "user-creator"), map[string]any{})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "internal error")
+	})
+
+	t.Run("returns error when userID not in context", func(t *testing.T) {
+		tool := newTestTool(t, &mockEventService{})
+
+		_, err := tool.Callback(line.WithSourceID(t.Context(), "group-123"), map[string]any{})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "internal error")
+	})
+
+	t.Run("returns invalid when chat_room_id is invalid", func(t *testing.T) {
+		tool := newTestTool(t, &mockEventService{})
+
+		result, err := tool.Callback(withEventContext(t.Context(), "group-123", "user-creator"), map[string]any{
+			"chat_room_id": 123,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, agent.Invalid("invalid chat_room_id"), result)
+	})
+
+	t.Run("returns error when toggle fails", func(t *testing.T) {
+		eventService := &mockEventService{getEvent: testEvent(), toggleErr: errors.New("generation mismatch")}
+		tool := newTestTool(t, eventService)
+
+		_, err := tool.Callback(withEventContext(t.Context(), "group-123", "user-creator"), map[string]any{})
+
+		require.Error(t, err)
+		assert.Equal(t, "failed to change show creator", err.Error())
+	})
+}
+
+// =============================================================================
+// Mocks
+// =============================================================================
+
+type mockEventService struct {
+	getEvent          *event.Event
+	getErr            error
+	lastGetChatRoomID string
+
+	toggleResult         bool
+	toggleErr            error
+	toggleCount          int
+	lastToggleChatRoomID string
+}
+
+func (m *mockEventService) Get(ctx context.Context, chatRoomID string) (*event.Event, error) {
+	m.lastGetChatRoomID = chatRoomID
+	return m.getEvent, m.getErr
+}
+
+func (m *mockEventService) ToggleShowCreator(ctx context.Context, chatRoomID string) (bool, error) {
+	m.toggleCount++
+	m.lastToggleChatRoomID = chatRoomID
+	return m.toggleResult, m.toggleErr
+}
@@ -0,0 +1,153 @@
+package suggest
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"text/template"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/event"
+)
+
+//go:embed parameters.json
+var parametersSchema []byte
+
+//go:embed response.json
+var responseSchema []byte
+
+// SystemPrompt is the system prompt for the agent that writes the suggestions.
+// That agent needs no tools; it only turns a title and keywords into a paragraph.
+//
+//go:embed system_prompt.txt
+var SystemPrompt string
+
+//go:embed request.txt
+var requestTemplateText string
+var requestTemplate = template.Must(template.New("request").Parse(requestTemplateText))
+
+// Generator writes the suggestion for a request.
+// It is satisfied by an agent created with SystemPrompt.
+type Generator interface {
+	Generate(ctx context.Context, history []agent.Message) (*agent.AssistantMessage, error)
+}
+
+// Tool implements the suggest_description tool for drafting an event description from a title and keywords.
+type Tool struct {
+	generator Generator
+	limits    event.TextLimits
+	logger    *slog.Logger
+}
+
+// New creates a new suggest_description tool.
+// limits caps the suggestion at the description length create_event and update_event accept.
+func New(generator Generator, limits event.TextLimits, logger *slog.Logger) (*Tool, error) {
+	if generator == nil {
+		return nil, errors.New("generator cannot be nil")
+	}
+	if err := limits.Validate(); err != nil {
+		return nil, err
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil")
+	}
+	return &Tool{
+		generator: generator,
+		limits:    limits,
+		logger:    logger,
+	}, nil
+}
+
+// Name returns the tool name.
+func (t *Tool) Name() string {
+	return "suggest_description"
+}
+
+// Description returns a description for the LLM.
+func (t *Tool) Description() string {
+	return "Drafts a friendly one-paragraph event description from a title and a few keywords. Use this when a creator asks for help writing a description or gives only a terse one. The draft is not saved: show it to the creator and use it with create_event or update_event only after they accept or edit it."
+}
+
+// ParametersJsonSchema returns the JSON Schema for input parameters.
+func (t *Tool) ParametersJsonSchema() []byte {
+	return parametersSchema
+}
+
+// ResponseJsonSchema returns the JSON Schema for the response.
+func (t *Tool) ResponseJsonSchema() []byte {
+	return responseSchema
+}
+
+// Callback asks the generator for a description and returns it without saving it.
+func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]any, error) {
+	title, ok := args["title"].(string)
+	if !ok {
+		return agent.Invalid("invalid title"), nil
+	}
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return agent.Invalid("title cannot be empty"), nil
+	}
+
+	keywordArgs, ok := args["keywords"].([]any)
+	if !ok {
+		return agent.Invalid("invalid keywords"), nil
+	}
+	keywords := make([]string, 0, len(keywordArgs))
+	for _, arg := range keywordArgs {
+		keyword, ok := arg.(string)
+		if !ok {
+			return agent.Invalid("invalid keywords"), nil
+		}
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			keywords = append(keywords, keyword)
+		}
+	}
+	if len(keywords) == 0 {
+		return agent.Invalid("give at least one keyword to describe the event"), nil
+	}
+
+	var request bytes.Buffer
+	if err := requestTemplate.Execute(&request, map[string]any{
+		"Title":     title,
+		"Keywords":  keywords,
+		"MaxLength": t.limits.MaxDescription,
+	}); err != nil {
+		t.logger.ErrorContext(ctx, "failed to execute request template", slog.Any("error", err))
+		return nil, agent.NewSystemError("internal error", err)
+	}
+
+	response, err := t.generator.Generate(ctx, []agent.Message{
+		&agent.UserMessage{Parts: []agent.UserPart{&agent.UserTextPart{Text: request.String()}}},
+	})
+	if err != nil {
+		t.logger.ErrorContext(ctx, "failed to generate description", slog.Any("error", err))
+		return nil, agent.NewSystemError("failed to suggest a description", err)
+	}
+
+	description := responseText(response)
+	if description == "" {
+		return nil, agent.NewSystemError("failed to suggest a description", errors.New("the generator returned no text"))
+	}
+	if err := t.limits.CheckDescription(description); err != nil {
+		return nil, agent.NewSystemError("failed to suggest a description", fmt.Errorf("suggestion is unusable: %w", err))
+	}
+
+	return map[string]any{
+		"description": description,
+	}, nil
+}
+
+// responseText joins the visible text parts of response, skipping thoughts.
+func responseText(response *agent.AssistantMessage) string {
+	var texts []string
+	for _, part := range response.Parts {
+		if p, ok := part.(*agent.AssistantTextPart); ok && !p.Thought {
+			texts = append(texts, p.Text)
+		}
+	}
+	return strings.TrimSpace(strings.Join(texts, ""))
+}
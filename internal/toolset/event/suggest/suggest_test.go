@@ -0,0 +1,188 @@
+package suggest_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/event"
+	"yuruppu/internal/toolset/event/suggest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fixedSuggestion = "Let's enjoy the cherry blossoms together! Bring snacks and a picnic sheet. Beginners are welcome."
+
+// =============================================================================
+// New Tests
+// =============================================================================
+
+func TestNew(t *testing.T) {
+	t.Run("creates tool with dependencies", func(t *testing.T) {
+		tool, err := suggest.New(&mockGenerator{}, event.DefaultTextLimits, slog.New(slog.DiscardHandler))
+
+		require.NoError(t, err)
+		require.NotNil(t, tool)
+		assert.Equal(t, "suggest_description", tool.Name())
+	})
+
+	t.Run("returns error when generator is nil", func(t *testing.T) {
+		tool, err := suggest.New(nil, event.DefaultTextLimits, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "generator cannot be nil")
+	})
+
+	t.Run("returns error when limits are invalid", func(t *testing.T) {
+		tool, err := suggest.New(&mockGenerator{}, event.TextLimits{MaxTitle: 200}, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "max description length must be positive")
+	})
+
+	t.Run("returns error when logger is nil", func(t *testing.T) {
+		tool, err := suggest.New(&mockGenerator{}, event.DefaultTextLimits, nil)
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "logger cannot be nil")
+	})
+}
+
+// =============================================================================
+// Callback Tests
+// =============================================================================
+
+func TestTool_Callback(t *testing.T) {
+	t.Run("returns the generated suggestion", func(t *testing.T) {
+		generator := &mockGenerator{response: textResponse(fixedSuggestion)}
+		tool, err := suggest.New(generator, event.DefaultTextLimits, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(t.Context(), map[string]any{
+			"title":    " Hanami ",
+			"keywords": []any{"bring snacks", " ", "beginners welcome"},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"description": fixedSuggestion}, result)
+		require.Equal(t, 1, generator.callCount)
+		assert.Contains(t, generator.lastRequest, "Title: Hanami\n")
+		assert.Contains(t, generator.lastRequest, "- bring snacks\n- beginners welcome")
+		assert.Contains(t, generator.lastRequest, "at most 2000 characters")
+	})
+
+	t.Run("skips thoughts in the response", func(t *testing.T) {
+		generator := &mockGenerator{response: &agent.AssistantMessage{Parts: []agent.AssistantPart{
+			&agent.AssistantTextPart{Text: "The user wants a picnic blurb.", Thought: true},
+			&agent.AssistantTextPart{Text: fixedSuggestion + "\n"},
+		}}}
+		tool, err := suggest.New(generator, event.DefaultTextLimits, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(t.Context(), map[string]any{"title": "Hanami", "keywords": []any{"picnic"}})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"description": fixedSuggestion}, result)
+	})
+
+	t.Run("rejects empty inputs without calling the generator", func(t *testing.T) {
+		tests := []struct {
+			name string
+			args map[string]any
+			want string
+		}{
+			{name: "blank title", args: map[string]any{"title": "   ", "keywords": []any{"picnic"}}, want: "title cannot be empty"},
+			{name: "no keywords", args: map[string]any{"title": "Hanami", "keywords": []any{}}, want: "give at least one keyword to describe the event"},
+			{name: "blank keywords", args: map[string]any{"title": "Hanami", "keywords": []any{"", "  "}}, want: "give at least one keyword to describe the event"},
+			{name: "missing title", args: map[string]any{"keywords": []any{"picnic"}}, want: "invalid title"},
+			{name: "missing keywords", args: map[string]any{"title": "Hanami"}, want: "invalid keywords"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				generator := &mockGenerator{response: textResponse(fixedSuggestion)}
+				tool, err := suggest.New(generator, event.DefaultTextLimits, slog.New(slog.DiscardHandler))
+				require.NoError(t, err)
+
+				result, err := tool.Callback(t.Context(), tt.args)
+
+				require.NoError(t, err)
+				assert.Equal(t, agent.Invalid(tt.want), result)
+				assert.Equal(t, 0, generator.callCount)
+			})
+		}
+	})
+
+	t.Run("returns system error when generation fails", func(t *testing.T) {
+		generator := &mockGenerator{err: errors.New("quota exceeded")}
+		tool, err := suggest.New(generator, event.DefaultTextLimits, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		_, err = tool.Callback(t.Context(), map[string]any{"title": "Hanami", "keywords": []any{"picnic"}})
+
+		require.Error(t, err)
+		assert.Equal(t, "failed to suggest a description", err.Error())
+		var systemErr *agent.SystemError
+		assert.ErrorAs(t, err, &systemErr)
+	})
+
+	t.Run("returns system error when the suggestion is empty", func(t *testing.T) {
+		generator := &mockGenerator{response: textResponse("  ")}
+		tool, err := suggest.New(generator, event.DefaultTextLimits, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		_, err = tool.Callback(t.Context(), map[string]any{"title": "Hanami", "keywords": []any{"picnic"}})
+
+		require.Error(t, err)
+		var systemErr *agent.SystemError
+		assert.ErrorAs(t, err, &systemErr)
+	})
+
+	t.Run("returns system error when the suggestion is over the description limit", func(t *testing.T) {
+		generator := &mockGenerator{response: textResponse(strings.Repeat("あ", 11))}
+		tool, err := suggest.New(generator, event.TextLimits{MaxTitle: 200, MaxDescription: 10}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		_, err = tool.Callback(t.Context(), map[string]any{"title": "Hanami", "keywords": []any{"picnic"}})
+
+		require.Error(t, err)
+		assert.Equal(t, "failed to suggest a description", err.Error())
+		assert.Contains(t, generator.lastRequest, "at most 10 characters")
+	})
+}
+
+// =============================================================================
+// Mocks
+// =============================================================================
+
+func textResponse(text string) *agent.AssistantMessage {
+	return &agent.AssistantMessage{Parts: []agent.AssistantPart{&agent.AssistantTextPart{Text: text}}}
+}
+
+type mockGenerator struct {
+	response    *agent.AssistantMessage
+	err         error
+	callCount   int
+	lastRequest string
+}
+
+func (m *mockGenerator) Generate(ctx context.Context, history []agent.Message) (*agent.AssistantMessage, error) {
+	m.callCount++
+	if msg, ok := history[len(history)-1].(*agent.UserMessage); ok {
+		for _, part := range msg.Parts {
+			if p, ok := part.(*agent.UserTextPart); ok {
+				m.lastRequest += p.Text
+			}
+		}
+	}
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.response, nil
+}
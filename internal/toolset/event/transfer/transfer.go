@@ -0,0 +1,139 @@
+package transfer
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"log/slog"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/event"
+	"yuruppu/internal/line"
+)
+
+//go:embed parameters.json
+var parametersSchema []byte
+
+//go:embed response.json
+var responseSchema []byte
+
+// EventService provides access to event operations.
+type EventService interface {
+	Get(ctx context.Context, chatRoomID string) (*event.Event, error)
+	Transfer(ctx context.Context, chatRoomID, newCreatorID string) error
+}
+
+// MemberChecker checks group membership.
+type MemberChecker interface {
+	IsGroupMember(ctx context.Context, groupID, userID string) (bool, error)
+}
+
+// Tool implements the transfer_event tool for handing an event over to another organizer.
+type Tool struct {
+	eventService  EventService
+	memberChecker MemberChecker
+	logger        *slog.Logger
+}
+
+// New creates a new transfer_event tool.
+func New(eventService EventService, memberChecker MemberChecker, logger *slog.Logger) (*Tool, error) {
+	if eventService == nil {
+		return nil, errors.New("eventService cannot be nil")
+	}
+	if memberChecker == nil {
+		return nil, errors.New("memberChecker cannot be nil")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil")
+	}
+	return &Tool{
+		eventService:  eventService,
+		memberChecker: memberChecker,
+		logger:        logger,
+	}, nil
+}
+
+// Name returns the tool name.
+func (t *Tool) Name() string {
+	return "transfer_event"
+}
+
+// Description returns a description for the LLM.
+func (t *Tool) Description() string {
+	return "Use this tool when the event creator wants to hand the event over to another group member, for example because they cannot attend. Only the event creator can transfer the event, and the new creator must be a member of the group."
+}
+
+// ParametersJsonSchema returns the JSON Schema for input parameters.
+func (t *Tool) ParametersJsonSchema() []byte {
+	return parametersSchema
+}
+
+// ResponseJsonSchema returns the JSON Schema for the response.
+func (t *Tool) ResponseJsonSchema() []byte {
+	return responseSchema
+}
+
+// Callback makes new_creator the creator of an event.
+func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]any, error) {
+	chatRoomID, ok := line.SourceIDFromContext(ctx)
+	if !ok {
+		t.logger.ErrorContext(ctx, "source ID not found in context")
+		return nil, agent.NewSystemError("internal error", nil)
+	}
+	if chatRoomIDArg, ok := args["chat_room_id"]; ok {
+		chatRoomID, ok = chatRoomIDArg.(string)
+		if !ok || chatRoomID == "" {
+			return agent.Invalid("invalid chat_room_id"), nil
+		}
+	}
+
+	userID, ok := line.UserIDFromContext(ctx)
+	if !ok {
+		t.logger.ErrorContext(ctx, "user ID not found in context")
+		return nil, agent.NewSystemError("internal error", nil)
+	}
+
+	newCreatorID, ok := args["new_creator"].(string)
+	if !ok || newCreatorID == "" {
+		return agent.Invalid("invalid new_creator"), nil
+	}
+
+	ev, err := t.eventService.Get(ctx, chatRoomID)
+	if err != nil {
+		if errors.As(err, new(*event.NotFoundError)) {
+			return map[string]any{"status": "not_found"}, nil
+		}
+		t.logger.ErrorContext(ctx, "failed to get event", slog.String("chatRoomID", chatRoomID), slog.Any("error", err))
+		return nil, agent.NewSystemError("failed to get event", err)
+	}
+
+	if ev.CreatorID != userID {
+		return map[string]any{"status": "forbidden"}, nil
+	}
+
+	isMember, err := t.memberChecker.IsGroupMember(ctx, chatRoomID, newCreatorID)
+	if err != nil {
+		t.logger.ErrorContext(ctx, "failed to check group membership",
+			slog.String("chatRoomID", chatRoomID),
+			slog.String("userID", newCreatorID),
+			slog.Any("error", err),
+		)
+		return nil, agent.NewSystemError("failed to check group membership", err)
+	}
+	if !isMember {
+		return map[string]any{"status": "not_member"}, nil
+	}
+
+	if err := t.eventService.Transfer(ctx, chatRoomID, newCreatorID); err != nil {
+		if errors.As(err, new(*event.NotFoundError)) {
+			return map[string]any{"status": "not_found"}, nil
+		}
+		t.logger.ErrorContext(ctx, "failed to transfer event",
+			slog.String("chatRoomID", chatRoomID),
+			slog.String("newCreatorID", newCreatorID),
+			slog.Any("error", err),
+		)
+		return nil, agent.NewSystemError("failed to transfer event", err)
+	}
+
+	return map[string]any{"status": "ok"}, nil
+}
@@ -0,0 +1,272 @@
+package transfer_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/event"
+	"yuruppu/internal/line"
+	"yuruppu/internal/toolset/event/transfer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// Test Helpers
+// =============================================================================
+
+func withEventContext(ctx context.Context, sourceID, userID string) context.Context {
+	ctx = line.WithSourceID(ctx, sourceID)
+	ctx = line.WithUserID(ctx, userID)
+	return ctx
+}
+
+func newTestTool(t *testing.T, eventService *mockEventService, memberChecker *mockMemberChecker) *transfer.Tool {
+	t.Helper()
+	tool, err := transfer.New(eventService, memberChecker, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+	return tool
+}
+
+func testEvent() *event.Event {
+	return &event.Event{ChatRoomID: "group-123", CreatorID: "user-creator", Title: "Team Meeting"}
+}
+
+// =============================================================================
+// New() Tests
+// =============================================================================
+
+func TestNew(t *testing.T) {
+	logger := slog.New(slog.DiscardHandler)
+
+	t.Run("creates tool with valid dependencies", func(t *testing.T) {
+		tool, err := transfer.New(&mockEventService{}, &mockMemberChecker{}, logger)
+
+		require.NoError(t, err)
+		assert.Equal(t, "transfer_event", tool.Name())
+	})
+
+	t.Run("returns error when eventService is nil", func(t *testing.T) {
+		tool, err := transfer.New(nil, &mockMemberChecker{}, logger)
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "eventService cannot be nil")
+	})
+
+	t.Run("returns error when memberChecker is nil", func(t *testing.T) {
+		tool, err := transfer.New(&mockEventService{}, nil, logger)
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "memberChecker cannot be nil")
+	})
+
+	t.Run("returns error when logger is nil", func(t *testing.T) {
+		tool, err := transfer.New(&mockEventService{}, &mockMemberChecker{}, nil)
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "logger cannot be nil")
+	})
+}
+
+// =============================================================================
+// Callback Tests
+// =============================================================================
+
+func TestTool_Callback(t *testing.T) {
+	t.Run("creator transfers to a group member", func(t *testing.T) {
+		eventService := &mockEventService{getEvent: testEvent()}
+		memberChecker := &mockMemberChecker{members: []string{"user-new"}}
+		tool := newTestTool(t, eventService, memberChecker)
+
+		result, err := tool.Callback(withEventContext(t.Context(), "group-123", "user-creator"), map[string]any{
+			"new_creator": "user-new",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "ok", result["status"])
+		require.Equal(t, 1, eventService.transferCount)
+		assert.Equal(t, "group-123", eventService.lastTransferChatRoomID)
+		assert.Equal(t, "user-new", eventService.lastTransferNewCreatorID)
+		assert.Equal(t, "group-123", memberChecker.lastGroupID)
+	})
+
+	t.Run("uses chat_room_id argument when provided", func(t *testing.T) {
+		eventService := &mockEventService{getEvent: testEvent()}
+		tool := newTestTool(t, eventService, &mockMemberChecker{members: []string{"user-new"}})
+
+		result, err := tool.Callback(withEventContext(t.Context(), "user-creator", "user-creator"), map[string]any{
+			"chat_room_id": "group-123",
+			"new_creator":  "user-new",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "ok", result["status"])
+		assert.Equal(t, "group-123", eventService.lastGetChatRoomID)
+		assert.Equal(t, "group-123", eventService.lastTransferChatRoomID)
+	})
+
+	t.Run("rejects a user who is not the creator", func(t *testing.T) {
+		eventService := &mockEventService{getEvent: testEvent()}
+		memberChecker := &mockMemberChecker{members: []string{"user-new"}}
+		tool := newTestTool(t, eventService, memberChecker)
+
+		result, err := tool.Callback(withEventContext(t.Context(), "group-123", "user-other"), map[string]any{
+			"new_creator": "user-new",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "forbidden", result["status"])
+		assert.Equal(t, 0, memberChecker.callCount)
+		assert.Equal(t, 0, eventService.transferCount)
+	})
+
+	t.Run("rejects a target who is not a group member", func(t *testing.T) {
+		eventService := &mockEventService{getEvent: testEvent()}
+		tool := newTestTool(t, eventService, &mockMemberChecker{members: []string{"user-new"}})
+
+		result, err := tool.Callback(withEventContext(t.Context(), "group-123", "user-creator"), map[string]any{
+			"new_creator": "user-outsider",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "not_member", result["status"])
+		assert.Equal(t, 0, eventService.transferCount)
+	})
+
+	t.Run("returns not_found when event does not exist", func(t *testing.T) {
+		eventService := &mockEventService{getErr: &event.NotFoundError{ChatRoomID: "group-123"}}
+		tool := newTestTool(t, eventService, &mockMemberChecker{})
+
+		result, err := tool.Callback(withEventContext(t.Context(), "group-123", "user-creator"), map[string]any{
+			"new_creator": "user-new",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "not_found", result["status"])
+		assert.Equal(t, 0, eventService.transferCount)
+	})
+}
+
+func TestTool_Callback_Errors(t *testing.T) {
+	t.Run("returns error when sourceID not in context", func(t *testing.T) {
+		tool := newTestTool(t, &mockEventService{}, &mockMemberChecker{})
+
+		_, err := tool.Callback(line.WithUserID(t.Context(), "user-creator"), map[string]any{"new_creator": "user-new"})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "internal error")
+	})
+
+	t.Run("returns error when userID not in context", func(t *testing.T) {
+		tool := newTestTool(t, &mockEventService{}, &mockMemberChecker{})
+
+		_, err := tool.Callback(line.WithSourceID(t.Context(), "group-123"), map[string]any{"new_creator": "user-new"})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "internal error")
+	})
+
+	t.Run("returns invalid when new_creator is missing", func(t *testing.T) {
+		eventService := &mockEventService{getEvent: testEvent()}
+		tool := newTestTool(t, eventService, &mockMemberChecker{})
+
+		result, err := tool.Callback(withEventContext(t.Context(), "group-123", "user-creator"), map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, agent.Invalid("invalid new_creator"), result)
+		assert.Equal(t, 0, eventService.getCount)
+	})
+
+	t.Run("returns invalid when chat_room_id is invalid", func(t *testing.T) {
+		tool := newTestTool(t, &mockEventService{}, &mockMemberChecker{})
+
+		result, err := tool.Callback(withEventContext(t.Context(), "group-123", "user-creator"), map[string]any{
+			"chat_room_id": 123,
+			"new_creator":  "user-new",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, agent.Invalid("invalid chat_room_id"), result)
+	})
+
+	t.Run("returns error when membership check fails", func(t *testing.T) {
+		eventService := &mockEventService{getEvent: testEvent()}
+		tool := newTestTool(t, eventService, &mockMemberChecker{err: errors.New("api error")})
+
+		_, err := tool.Callback(withEventContext(t.Context(), "group-123", "user-creator"), map[string]any{
+			"new_creator": "user-new",
+		})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to check group membership")
+		assert.Equal(t, 0, eventService.transferCount)
+	})
+
+	t.Run("returns error when transfer fails", func(t *testing.T) {
+		eventService := &mockEventService{getEvent: testEvent(), transferErr: errors.New("generation mismatch")}
+		tool := newTestTool(t, eventService, &mockMemberChecker{members: []string{"user-new"}})
+
+		_, err := tool.Callback(withEventContext(t.Context(), "group-123", "user-creator"), map[string]any{
+			"new_creator": "user-new",
+		})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to transfer event")
+	})
+}
+
+// =============================================================================
+// Mocks
+// =============================================================================
+
+type mockEventService struct {
+	getEvent          *event.Event
+	getErr            error
+	getCount          int
+	lastGetChatRoomID string
+
+	transferErr              error
+	transferCount            int
+	lastTransferChatRoomID   string
+	lastTransferNewCreatorID string
+}
+
+func (m *mockEventService) Get(ctx context.Context, chatRoomID string) (*event.Event, error) {
+	m.getCount++
+	m.lastGetChatRoomID = chatRoomID
+	return m.getEvent, m.getErr
+}
+
+func (m *mockEventService) Transfer(ctx context.Context, chatRoomID, newCreatorID string) error {
+	m.transferCount++
+	m.lastTransferChatRoomID = chatRoomID
+	m.lastTransferNewCreatorID = newCreatorID
+	return m.transferErr
+}
+
+type mockMemberChecker struct {
+	members     []string
+	err         error
+	callCount   int
+	lastGroupID string
+}
+
+func (m *mockMemberChecker) IsGroupMember(ctx context.Context, groupID, userID string) (bool, error) {
+	m.callCount++
+	m.lastGroupID = groupID
+	if m.err != nil {
+		return false, m.err
+	}
+	for _, member := range m.members {
+		if member == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -5,6 +5,7 @@ import (
 	_ "embed"
 	"errors"
 	"log/slog"
+	"yuruppu/internal/agent"
 	"yuruppu/internal/event"
 	"yuruppu/internal/line"
 )
@@ -24,19 +25,25 @@ type EventService interface {
 // Tool implements the update_event tool for updating event description.
 type Tool struct {
 	eventService EventService
+	limits       event.TextLimits
 	logger       *slog.Logger
 }
 
 // New creates a new update_event tool.
-func New(eventService EventService, logger *slog.Logger) (*Tool, error) {
+// limits bounds the description length.
+func New(eventService EventService, limits event.TextLimits, logger *slog.Logger) (*Tool, error) {
 	if eventService == nil {
 		return nil, errors.New("eventService cannot be nil")
 	}
+	if err := limits.Validate(); err != nil {
+		return nil, err
+	}
 	if logger == nil {
 		return nil, errors.New("logger cannot be nil")
 	}
 	return &Tool{
 		eventService: eventService,
+		limits:       limits,
 		logger:       logger,
 	}, nil
 }
@@ -66,35 +73,41 @@ func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]an
 	sourceID, ok := line.SourceIDFromContext(ctx)
 	if !ok {
 		t.logger.ErrorContext(ctx, "source ID not found in context")
-		return nil, errors.New("internal error")
+		return nil, agent.NewSystemError("internal error", nil)
 	}
 	userID, ok := line.UserIDFromContext(ctx)
 	if !ok {
 		t.logger.ErrorContext(ctx, "user ID not found in context")
-		return nil, errors.New("internal error")
+		return nil, agent.NewSystemError("internal error", nil)
 	}
 
 	description, ok := args["description"].(string)
 	if !ok {
-		return nil, errors.New("invalid description")
+		return agent.Invalid("invalid description"), nil
+	}
+	if err := t.limits.CheckDescription(description); err != nil {
+		return agent.Invalid(err.Error()), nil
 	}
 
 	// Get existing event to check authorization
 	ev, err := t.eventService.Get(ctx, sourceID)
 	if err != nil {
-		t.logger.ErrorContext(ctx, "event not found", slog.String("chatRoomID", sourceID), slog.Any("error", err))
-		return nil, errors.New("event not found")
+		if errors.As(err, new(*event.NotFoundError)) {
+			return nil, agent.NewUserError("event not found")
+		}
+		t.logger.ErrorContext(ctx, "failed to get event", slog.String("chatRoomID", sourceID), slog.Any("error", err))
+		return nil, agent.NewSystemError("failed to get event", err)
 	}
 
 	// Check authorization
 	if ev.CreatorID != userID {
-		return nil, errors.New("only the event creator can update the event")
+		return nil, agent.NewUserError("only the event creator can update the event")
 	}
 
 	// Update event
 	if err := t.eventService.Update(ctx, sourceID, description); err != nil {
 		t.logger.ErrorContext(ctx, "failed to update event", slog.Any("error", err))
-		return nil, errors.New("failed to update event")
+		return nil, agent.NewSystemError("failed to update event", err)
 	}
 
 	return map[string]any{
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"strings"
 	"testing"
+	"yuruppu/internal/agent"
 	"yuruppu/internal/event"
 	"yuruppu/internal/line"
 	"yuruppu/internal/toolset/event/update"
@@ -39,7 +41,7 @@ func TestNew(t *testing.T) {
 	t.Run("creates tool with valid service", func(t *testing.T) {
 		service := &mockEventService{}
 
-		tool, err := update.New(service, slog.New(slog.DiscardHandler))
+		tool, err := update.New(service, event.DefaultTextLimits, slog.New(slog.DiscardHandler))
 
 		require.NoError(t, err)
 		require.NotNil(t, tool)
@@ -47,17 +49,25 @@ func TestNew(t *testing.T) {
 	})
 
 	t.Run("returns error when service is nil", func(t *testing.T) {
-		tool, err := update.New(nil, slog.New(slog.NewTextHandler(nil, nil)))
+		tool, err := update.New(nil, event.DefaultTextLimits, slog.New(slog.NewTextHandler(nil, nil)))
 
 		require.Error(t, err)
 		assert.Nil(t, tool)
 		assert.Contains(t, err.Error(), "eventService cannot be nil")
 	})
 
+	t.Run("returns error when text limits are invalid", func(t *testing.T) {
+		tool, err := update.New(&mockEventService{}, event.TextLimits{MaxTitle: 200}, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "max description length must be positive")
+	})
+
 	t.Run("returns error when logger is nil", func(t *testing.T) {
 		service := &mockEventService{}
 
-		tool, err := update.New(service, nil)
+		tool, err := update.New(service, event.DefaultTextLimits, nil)
 
 		require.Error(t, err)
 		assert.Nil(t, tool)
@@ -71,7 +81,7 @@ func TestNew(t *testing.T) {
 
 func TestTool_Metadata(t *testing.T) {
 	service := &mockEventService{}
-	tool, _ := update.New(service, slog.New(slog.DiscardHandler))
+	tool, _ := update.New(service, event.DefaultTextLimits, slog.New(slog.DiscardHandler))
 
 	t.Run("Name returns update_event", func(t *testing.T) {
 		assert.Equal(t, "update_event", tool.Name())
@@ -113,7 +123,7 @@ func TestTool_Callback_Success(t *testing.T) {
 				Description: "Old description",
 			},
 		}
-		tool, _ := update.New(service, slog.New(slog.DiscardHandler))
+		tool, _ := update.New(service, event.DefaultTextLimits, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-123", "user-456")
 		args := validUpdateArgs()
@@ -140,7 +150,7 @@ func TestTool_Callback_Success(t *testing.T) {
 				Description: "Original content",
 			},
 		}
-		tool, _ := update.New(service, slog.New(slog.DiscardHandler))
+		tool, _ := update.New(service, event.DefaultTextLimits, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-999", "user-888")
 		args := map[string]any{
@@ -170,7 +180,7 @@ func TestTool_Callback_AuthorizationError(t *testing.T) {
 				Description: "Some description",
 			},
 		}
-		tool, _ := update.New(service, slog.New(slog.DiscardHandler))
+		tool, _ := update.New(service, event.DefaultTextLimits, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-123", "user-999") // Different user
 		args := validUpdateArgs()
@@ -195,7 +205,7 @@ func TestTool_Callback_ContextErrors(t *testing.T) {
 	// FR-005: Update can only be executed from the group chat where the event exists
 	t.Run("returns error when sourceID not in context", func(t *testing.T) {
 		service := &mockEventService{}
-		tool, _ := update.New(service, slog.New(slog.DiscardHandler))
+		tool, _ := update.New(service, event.DefaultTextLimits, slog.New(slog.DiscardHandler))
 
 		ctx := line.WithUserID(context.Background(), "user-123")
 		args := validUpdateArgs()
@@ -210,7 +220,7 @@ func TestTool_Callback_ContextErrors(t *testing.T) {
 
 	t.Run("returns error when userID not in context", func(t *testing.T) {
 		service := &mockEventService{}
-		tool, _ := update.New(service, slog.New(slog.DiscardHandler))
+		tool, _ := update.New(service, event.DefaultTextLimits, slog.New(slog.DiscardHandler))
 
 		ctx := line.WithSourceID(context.Background(), "group-123")
 		args := validUpdateArgs()
@@ -232,9 +242,9 @@ func TestTool_Callback_NotFoundError(t *testing.T) {
 	// AC-003: イベント更新（イベントが存在しない） [FR-006]
 	t.Run("returns error when event does not exist in current chat room", func(t *testing.T) {
 		service := &mockEventService{
-			getErr: errors.New("event not found: group-123"),
+			getErr: &event.NotFoundError{ChatRoomID: "group-123"},
 		}
-		tool, _ := update.New(service, slog.New(slog.DiscardHandler))
+		tool, _ := update.New(service, event.DefaultTextLimits, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-123", "user-456")
 		args := validUpdateArgs()
@@ -243,6 +253,8 @@ func TestTool_Callback_NotFoundError(t *testing.T) {
 
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "event not found")
+		var userErr *agent.UserError
+		assert.ErrorAs(t, err, &userErr)
 
 		// Get should be called
 		assert.Equal(t, 1, service.getCount)
@@ -256,7 +268,7 @@ func TestTool_Callback_NotFoundError(t *testing.T) {
 // =============================================================================
 
 func TestTool_Callback_ValidationErrors(t *testing.T) {
-	t.Run("returns error when description is not a string", func(t *testing.T) {
+	t.Run("returns invalid when description is not a string", func(t *testing.T) {
 		service := &mockEventService{
 			getEvent: &event.Event{
 				ChatRoomID:  "group-123",
@@ -265,21 +277,21 @@ func TestTool_Callback_ValidationErrors(t *testing.T) {
 				Description: "Old description",
 			},
 		}
-		tool, _ := update.New(service, slog.New(slog.DiscardHandler))
+		tool, _ := update.New(service, event.DefaultTextLimits, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-123", "user-456")
 		args := map[string]any{
 			"description": 12345, // Invalid type
 		}
 
-		_, err := tool.Callback(ctx, args)
+		result, err := tool.Callback(ctx, args)
 
-		require.Error(t, err)
-		assert.Contains(t, err.Error(), "invalid description")
+		require.NoError(t, err)
+		assert.Equal(t, agent.Invalid("invalid description"), result)
 		assert.Equal(t, 0, service.updateCount)
 	})
 
-	t.Run("returns error when description is missing", func(t *testing.T) {
+	t.Run("returns invalid when description is missing", func(t *testing.T) {
 		service := &mockEventService{
 			getEvent: &event.Event{
 				ChatRoomID:  "group-123",
@@ -288,15 +300,47 @@ func TestTool_Callback_ValidationErrors(t *testing.T) {
 				Description: "Old description",
 			},
 		}
-		tool, _ := update.New(service, slog.New(slog.DiscardHandler))
+		tool, _ := update.New(service, event.DefaultTextLimits, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-123", "user-456")
 		args := map[string]any{} // Missing description
 
-		_, err := tool.Callback(ctx, args)
+		result, err := tool.Callback(ctx, args)
 
-		require.Error(t, err)
-		assert.Contains(t, err.Error(), "invalid description")
+		require.NoError(t, err)
+		assert.Equal(t, agent.Invalid("invalid description"), result)
+		assert.Equal(t, 0, service.updateCount)
+	})
+}
+
+// =============================================================================
+// Callback Tests - Length Limits
+// =============================================================================
+
+func TestTool_Callback_DescriptionLimit(t *testing.T) {
+	limits := event.TextLimits{MaxTitle: 200, MaxDescription: 10}
+
+	t.Run("accepts a description at the limit counting runes", func(t *testing.T) {
+		service := &mockEventService{getEvent: &event.Event{ChatRoomID: "group-123", CreatorID: "user-456"}}
+		tool, _ := update.New(service, limits, slog.New(slog.DiscardHandler))
+
+		ctx := withEventContext(context.Background(), "group-123", "user-456")
+		description := strings.Repeat("🌸", 5) + "お花見しよ"
+		_, err := tool.Callback(ctx, map[string]any{"description": description})
+
+		require.NoError(t, err)
+		assert.Equal(t, description, service.lastUpdateDescription)
+	})
+
+	t.Run("rejects a description over the limit", func(t *testing.T) {
+		service := &mockEventService{getEvent: &event.Event{ChatRoomID: "group-123", CreatorID: "user-456"}}
+		tool, _ := update.New(service, limits, slog.New(slog.DiscardHandler))
+
+		ctx := withEventContext(context.Background(), "group-123", "user-456")
+		result, err := tool.Callback(ctx, map[string]any{"description": strings.Repeat("あ", 11)})
+
+		require.NoError(t, err)
+		assert.Equal(t, agent.Invalid("description is too long: 11 characters, the maximum is 10"), result)
 		assert.Equal(t, 0, service.updateCount)
 	})
 }
@@ -310,7 +354,7 @@ func TestTool_Callback_ServiceErrors(t *testing.T) {
 		service := &mockEventService{
 			getErr: errors.New("storage error"),
 		}
-		tool, _ := update.New(service, slog.New(slog.DiscardHandler))
+		tool, _ := update.New(service, event.DefaultTextLimits, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-123", "user-456")
 		args := validUpdateArgs()
@@ -318,7 +362,9 @@ func TestTool_Callback_ServiceErrors(t *testing.T) {
 		_, err := tool.Callback(ctx, args)
 
 		require.Error(t, err)
-		assert.Contains(t, err.Error(), "event not found")
+		assert.Equal(t, "failed to get event", err.Error())
+		var systemErr *agent.SystemError
+		assert.ErrorAs(t, err, &systemErr)
 		assert.Equal(t, 1, service.getCount)
 		assert.Equal(t, 0, service.updateCount)
 	})
@@ -333,7 +379,7 @@ func TestTool_Callback_ServiceErrors(t *testing.T) {
 			},
 			updateErr: errors.New("storage write error"),
 		}
-		tool, _ := update.New(service, slog.New(slog.DiscardHandler))
+		tool, _ := update.New(service, event.DefaultTextLimits, slog.New(slog.DiscardHandler))
 
 		ctx := withEventContext(context.Background(), "group-123", "user-456")
 		args := validUpdateArgs()
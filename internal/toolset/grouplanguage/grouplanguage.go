@@ -0,0 +1,110 @@
+package grouplanguage
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"log/slog"
+	"strings"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/groupprofile"
+	"yuruppu/internal/line"
+
+	"golang.org/x/text/language"
+)
+
+//go:embed parameters.json
+var parametersSchema []byte
+
+//go:embed response.json
+var responseSchema []byte
+
+// GroupProfileService provides access to group profile operations.
+type GroupProfileService interface {
+	UpdateGroupProfile(ctx context.Context, groupID string, update func(*groupprofile.GroupProfile)) error
+}
+
+// Tool implements the set_group_language tool for choosing the language the bot replies in throughout a group.
+type Tool struct {
+	groupProfileService GroupProfileService
+	logger              *slog.Logger
+}
+
+// NewTool creates a new set_group_language tool.
+func NewTool(groupProfileService GroupProfileService, logger *slog.Logger) (*Tool, error) {
+	if groupProfileService == nil {
+		return nil, errors.New("groupProfileService cannot be nil")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil")
+	}
+	return &Tool{
+		groupProfileService: groupProfileService,
+		logger:              logger,
+	}, nil
+}
+
+// Name returns the tool name.
+func (t *Tool) Name() string {
+	return "set_group_language"
+}
+
+// Description returns a description for the LLM.
+func (t *Tool) Description() string {
+	return "Use this tool when a user asks you to always reply in a particular language in this group, or to go back to each member's own language. Only available in group chats."
+}
+
+// ParametersJsonSchema returns the JSON Schema for input parameters.
+func (t *Tool) ParametersJsonSchema() []byte {
+	return parametersSchema
+}
+
+// ResponseJsonSchema returns the JSON Schema for the response.
+func (t *Tool) ResponseJsonSchema() []byte {
+	return responseSchema
+}
+
+// Callback saves the group's language, or clears it when the language is empty.
+func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]any, error) {
+	chatType, ok := line.ChatTypeFromContext(ctx)
+	if !ok {
+		return nil, agent.NewSystemError("internal error", errors.New("chat type not found in context"))
+	}
+	sourceID, ok := line.SourceIDFromContext(ctx)
+	if !ok {
+		return nil, agent.NewSystemError("internal error", errors.New("source ID not found in context"))
+	}
+
+	if chatType != line.ChatTypeGroup {
+		return nil, agent.NewUserError("the group language can only be set in group chats")
+	}
+
+	arg, ok := args["language"].(string)
+	if !ok {
+		return nil, agent.NewUserError("invalid language")
+	}
+	lang := strings.TrimSpace(arg)
+	if lang != "" {
+		tag, err := language.Parse(lang)
+		if err != nil {
+			return nil, agent.NewUserError("language must be a BCP 47 language tag such as ja or en")
+		}
+		lang = tag.String()
+	}
+
+	err := t.groupProfileService.UpdateGroupProfile(ctx, sourceID, func(p *groupprofile.GroupProfile) {
+		p.Language = lang
+	})
+	if err != nil {
+		return nil, agent.NewSystemError("failed to set group language", err)
+	}
+
+	t.logger.InfoContext(ctx, "group language changed",
+		slog.String("groupID", sourceID),
+		slog.String("language", lang),
+	)
+
+	return map[string]any{
+		"language": lang,
+	}, nil
+}
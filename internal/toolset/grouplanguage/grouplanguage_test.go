@@ -0,0 +1,182 @@
+package grouplanguage_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/groupprofile"
+	"yuruppu/internal/line"
+	"yuruppu/internal/toolset/grouplanguage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// NewTool Tests
+// =============================================================================
+
+func TestNewTool(t *testing.T) {
+	t.Run("creates tool with dependencies", func(t *testing.T) {
+		tool, err := grouplanguage.NewTool(&mockGroupProfileService{}, slog.New(slog.DiscardHandler))
+
+		require.NoError(t, err)
+		require.NotNil(t, tool)
+		assert.Equal(t, "set_group_language", tool.Name())
+	})
+
+	t.Run("returns error when groupProfileService is nil", func(t *testing.T) {
+		tool, err := grouplanguage.NewTool(nil, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "groupProfileService cannot be nil")
+	})
+
+	t.Run("returns error when logger is nil", func(t *testing.T) {
+		tool, err := grouplanguage.NewTool(&mockGroupProfileService{}, nil)
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "logger cannot be nil")
+	})
+}
+
+// =============================================================================
+// Callback Tests
+// =============================================================================
+
+// groupContext returns a context for a message in group-123.
+func groupContext(ctx context.Context) context.Context {
+	ctx = line.WithChatType(ctx, line.ChatTypeGroup)
+	return line.WithSourceID(ctx, "group-123")
+}
+
+func TestTool_Callback(t *testing.T) {
+	t.Run("sets the group language", func(t *testing.T) {
+		svc := &mockGroupProfileService{profile: &groupprofile.GroupProfile{DisplayName: "Group A"}}
+		tool, err := grouplanguage.NewTool(svc, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(groupContext(t.Context()), map[string]any{"language": " en "})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"language": "en"}, result)
+		assert.Equal(t, "group-123", svc.lastGroupID)
+		assert.Equal(t, "en", svc.profile.Language)
+		assert.Equal(t, "Group A", svc.profile.DisplayName)
+	})
+
+	t.Run("normalizes the language tag", func(t *testing.T) {
+		svc := &mockGroupProfileService{profile: &groupprofile.GroupProfile{}}
+		tool, err := grouplanguage.NewTool(svc, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(groupContext(t.Context()), map[string]any{"language": "ZH-tw"})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"language": "zh-TW"}, result)
+		assert.Equal(t, "zh-TW", svc.profile.Language)
+	})
+
+	t.Run("clears the group language", func(t *testing.T) {
+		svc := &mockGroupProfileService{profile: &groupprofile.GroupProfile{Language: "en"}}
+		tool, err := grouplanguage.NewTool(svc, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(groupContext(t.Context()), map[string]any{"language": ""})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"language": ""}, result)
+		assert.Empty(t, svc.profile.Language)
+	})
+
+	t.Run("rejects invalid language", func(t *testing.T) {
+		svc := &mockGroupProfileService{profile: &groupprofile.GroupProfile{}}
+		tool, err := grouplanguage.NewTool(svc, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		for _, lang := range []string{"English", "日本語", "e"} {
+			_, err = tool.Callback(groupContext(t.Context()), map[string]any{"language": lang})
+
+			require.Error(t, err, lang)
+			assert.Contains(t, err.Error(), "BCP 47 language tag", lang)
+			var userErr *agent.UserError
+			assert.ErrorAs(t, err, &userErr, lang)
+		}
+		assert.Equal(t, 0, svc.updateCount)
+	})
+
+	t.Run("rejects missing language", func(t *testing.T) {
+		svc := &mockGroupProfileService{profile: &groupprofile.GroupProfile{}}
+		tool, err := grouplanguage.NewTool(svc, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		_, err = tool.Callback(groupContext(t.Context()), map[string]any{})
+
+		require.Error(t, err)
+		assert.Equal(t, "invalid language", err.Error())
+		assert.Equal(t, 0, svc.updateCount)
+	})
+
+	t.Run("rejects one-on-one chats", func(t *testing.T) {
+		svc := &mockGroupProfileService{profile: &groupprofile.GroupProfile{}}
+		tool, err := grouplanguage.NewTool(svc, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := line.WithChatType(t.Context(), line.ChatTypeOneOnOne)
+		ctx = line.WithSourceID(ctx, "user-123")
+		_, err = tool.Callback(ctx, map[string]any{"language": "en"})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "only be set in group chats")
+		assert.Equal(t, 0, svc.updateCount)
+	})
+
+	t.Run("returns system error when update fails", func(t *testing.T) {
+		svc := &mockGroupProfileService{updateErr: errors.New("generation mismatch")}
+		tool, err := grouplanguage.NewTool(svc, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		_, err = tool.Callback(groupContext(t.Context()), map[string]any{"language": "en"})
+
+		require.Error(t, err)
+		assert.Equal(t, "failed to set group language", err.Error())
+		var systemErr *agent.SystemError
+		assert.ErrorAs(t, err, &systemErr)
+	})
+
+	t.Run("returns internal error when source ID is missing", func(t *testing.T) {
+		tool, err := grouplanguage.NewTool(&mockGroupProfileService{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := line.WithChatType(t.Context(), line.ChatTypeGroup)
+		_, err = tool.Callback(ctx, map[string]any{"language": "en"})
+
+		require.Error(t, err)
+		assert.Equal(t, "internal error", err.Error())
+	})
+}
+
+// =============================================================================
+// Mocks
+// =============================================================================
+
+type mockGroupProfileService struct {
+	profile     *groupprofile.GroupProfile
+	updateErr   error
+	updateCount int
+	lastGroupID string
+}
+
+func (m *mockGroupProfileService) UpdateGroupProfile(ctx context.Context, groupID string, update func(*groupprofile.GroupProfile)) error {
+	m.updateCount++
+	m.lastGroupID = groupID
+	if m.updateErr != nil {
+		return m.updateErr
+	}
+	update(m.profile)
+	return nil
+}
@@ -0,0 +1,100 @@
+package groupreplymode
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"log/slog"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/groupprofile"
+	"yuruppu/internal/line"
+)
+
+//go:embed parameters.json
+var parametersSchema []byte
+
+//go:embed response.json
+var responseSchema []byte
+
+// GroupProfileService provides access to group profile operations.
+type GroupProfileService interface {
+	UpdateGroupProfile(ctx context.Context, groupID string, update func(*groupprofile.GroupProfile)) error
+}
+
+// Tool implements the set_group_reply_mode tool for choosing which group messages the bot answers.
+type Tool struct {
+	groupProfileService GroupProfileService
+	logger              *slog.Logger
+}
+
+// NewTool creates a new set_group_reply_mode tool.
+func NewTool(groupProfileService GroupProfileService, logger *slog.Logger) (*Tool, error) {
+	if groupProfileService == nil {
+		return nil, errors.New("groupProfileService cannot be nil")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil")
+	}
+	return &Tool{
+		groupProfileService: groupProfileService,
+		logger:              logger,
+	}, nil
+}
+
+// Name returns the tool name.
+func (t *Tool) Name() string {
+	return "set_group_reply_mode"
+}
+
+// Description returns a description for the LLM.
+func (t *Tool) Description() string {
+	return "Use this tool when a user asks you to reply only when mentioned in this group, or to reply to every message again. Only available in group chats."
+}
+
+// ParametersJsonSchema returns the JSON Schema for input parameters.
+func (t *Tool) ParametersJsonSchema() []byte {
+	return parametersSchema
+}
+
+// ResponseJsonSchema returns the JSON Schema for the response.
+func (t *Tool) ResponseJsonSchema() []byte {
+	return responseSchema
+}
+
+// Callback saves the group's reply mode.
+func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]any, error) {
+	chatType, ok := line.ChatTypeFromContext(ctx)
+	if !ok {
+		return nil, agent.NewSystemError("internal error", errors.New("chat type not found in context"))
+	}
+	sourceID, ok := line.SourceIDFromContext(ctx)
+	if !ok {
+		return nil, agent.NewSystemError("internal error", errors.New("source ID not found in context"))
+	}
+
+	if chatType != line.ChatTypeGroup {
+		return nil, agent.NewUserError("the reply mode can only be set in group chats")
+	}
+
+	modeArg, _ := args["reply_mode"].(string)
+	mode := groupprofile.ReplyMode(modeArg)
+	if mode != groupprofile.ReplyModeAlways && mode != groupprofile.ReplyModeMentionOnly {
+		return nil, agent.NewUserError("reply_mode must be always or mention_only")
+	}
+
+	err := t.groupProfileService.UpdateGroupProfile(ctx, sourceID, func(p *groupprofile.GroupProfile) {
+		p.ReplyMode = mode
+	})
+	if err != nil {
+		return nil, agent.NewSystemError("failed to set group reply mode", err)
+	}
+
+	t.logger.InfoContext(ctx, "group reply mode changed",
+		slog.String("groupID", sourceID),
+		slog.String("replyMode", string(mode)),
+	)
+
+	return map[string]any{
+		"reply_mode": string(mode),
+	}, nil
+}
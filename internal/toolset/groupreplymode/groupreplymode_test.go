@@ -0,0 +1,157 @@
+package groupreplymode_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/groupprofile"
+	"yuruppu/internal/line"
+	"yuruppu/internal/toolset/groupreplymode"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// NewTool Tests
+// =============================================================================
+
+func TestNewTool(t *testing.T) {
+	t.Run("creates tool with dependencies", func(t *testing.T) {
+		tool, err := groupreplymode.NewTool(&mockGroupProfileService{}, slog.New(slog.DiscardHandler))
+
+		require.NoError(t, err)
+		require.NotNil(t, tool)
+		assert.Equal(t, "set_group_reply_mode", tool.Name())
+	})
+
+	t.Run("returns error when groupProfileService is nil", func(t *testing.T) {
+		tool, err := groupreplymode.NewTool(nil, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "groupProfileService cannot be nil")
+	})
+
+	t.Run("returns error when logger is nil", func(t *testing.T) {
+		tool, err := groupreplymode.NewTool(&mockGroupProfileService{}, nil)
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "logger cannot be nil")
+	})
+}
+
+// =============================================================================
+// Callback Tests
+// =============================================================================
+
+// groupContext returns a context for a message in group-123.
+func groupContext(ctx context.Context) context.Context {
+	ctx = line.WithChatType(ctx, line.ChatTypeGroup)
+	return line.WithSourceID(ctx, "group-123")
+}
+
+func TestTool_Callback(t *testing.T) {
+	t.Run("sets the group reply mode", func(t *testing.T) {
+		svc := &mockGroupProfileService{profile: &groupprofile.GroupProfile{DisplayName: "Group A"}}
+		tool, err := groupreplymode.NewTool(svc, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(groupContext(t.Context()), map[string]any{"reply_mode": "mention_only"})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"reply_mode": "mention_only"}, result)
+		assert.Equal(t, "group-123", svc.lastGroupID)
+		assert.Equal(t, groupprofile.ReplyModeMentionOnly, svc.profile.ReplyMode)
+		assert.Equal(t, "Group A", svc.profile.DisplayName)
+	})
+
+	t.Run("switches back to always", func(t *testing.T) {
+		svc := &mockGroupProfileService{profile: &groupprofile.GroupProfile{ReplyMode: groupprofile.ReplyModeMentionOnly}}
+		tool, err := groupreplymode.NewTool(svc, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		_, err = tool.Callback(groupContext(t.Context()), map[string]any{"reply_mode": "always"})
+
+		require.NoError(t, err)
+		assert.Equal(t, groupprofile.ReplyModeAlways, svc.profile.ReplyMode)
+	})
+
+	t.Run("rejects unknown reply mode", func(t *testing.T) {
+		svc := &mockGroupProfileService{profile: &groupprofile.GroupProfile{}}
+		tool, err := groupreplymode.NewTool(svc, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		for _, args := range []map[string]any{{"reply_mode": "never"}, {"reply_mode": ""}, {}} {
+			_, err = tool.Callback(groupContext(t.Context()), args)
+
+			require.Error(t, err, args)
+			assert.Equal(t, "reply_mode must be always or mention_only", err.Error(), args)
+			var userErr *agent.UserError
+			assert.ErrorAs(t, err, &userErr, args)
+		}
+		assert.Equal(t, 0, svc.updateCount)
+	})
+
+	t.Run("rejects one-on-one chats", func(t *testing.T) {
+		svc := &mockGroupProfileService{profile: &groupprofile.GroupProfile{}}
+		tool, err := groupreplymode.NewTool(svc, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := line.WithChatType(t.Context(), line.ChatTypeOneOnOne)
+		ctx = line.WithSourceID(ctx, "user-123")
+		_, err = tool.Callback(ctx, map[string]any{"reply_mode": "mention_only"})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "only be set in group chats")
+		assert.Equal(t, 0, svc.updateCount)
+	})
+
+	t.Run("returns system error when update fails", func(t *testing.T) {
+		svc := &mockGroupProfileService{updateErr: errors.New("generation mismatch")}
+		tool, err := groupreplymode.NewTool(svc, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		_, err = tool.Callback(groupContext(t.Context()), map[string]any{"reply_mode": "always"})
+
+		require.Error(t, err)
+		assert.Equal(t, "failed to set group reply mode", err.Error())
+		var systemErr *agent.SystemError
+		assert.ErrorAs(t, err, &systemErr)
+	})
+
+	t.Run("returns internal error when source ID is missing", func(t *testing.T) {
+		tool, err := groupreplymode.NewTool(&mockGroupProfileService{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := line.WithChatType(t.Context(), line.ChatTypeGroup)
+		_, err = tool.Callback(ctx, map[string]any{"reply_mode": "always"})
+
+		require.Error(t, err)
+		assert.Equal(t, "internal error", err.Error())
+	})
+}
+
+// =============================================================================
+// Mocks
+// =============================================================================
+
+type mockGroupProfileService struct {
+	profile     *groupprofile.GroupProfile
+	updateErr   error
+	updateCount int
+	lastGroupID string
+}
+
+func (m *mockGroupProfileService) UpdateGroupProfile(ctx context.Context, groupID string, update func(*groupprofile.GroupProfile)) error {
+	m.updateCount++
+	m.lastGroupID = groupID
+	if m.updateErr != nil {
+		return m.updateErr
+	}
+	update(m.profile)
+	return nil
+}
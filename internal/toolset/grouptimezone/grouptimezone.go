@@ -0,0 +1,102 @@
+package grouptimezone
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"log/slog"
+	"strings"
+	"yuruppu/internal/event"
+	"yuruppu/internal/groupprofile"
+	"yuruppu/internal/line"
+)
+
+//go:embed parameters.json
+var parametersSchema []byte
+
+//go:embed response.json
+var responseSchema []byte
+
+// GroupProfileService provides access to group profile operations.
+type GroupProfileService interface {
+	UpdateGroupProfile(ctx context.Context, groupID string, update func(*groupprofile.GroupProfile)) error
+}
+
+// Tool implements the set_group_timezone tool for choosing the timezone new events in a group default to.
+type Tool struct {
+	groupProfileService GroupProfileService
+	logger              *slog.Logger
+}
+
+// NewTool creates a new set_group_timezone tool.
+func NewTool(groupProfileService GroupProfileService, logger *slog.Logger) (*Tool, error) {
+	if groupProfileService == nil {
+		return nil, errors.New("groupProfileService cannot be nil")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil")
+	}
+	return &Tool{
+		groupProfileService: groupProfileService,
+		logger:              logger,
+	}, nil
+}
+
+// Name returns the tool name.
+func (t *Tool) Name() string {
+	return "set_group_timezone"
+}
+
+// Description returns a description for the LLM.
+func (t *Tool) Description() string {
+	return "Use this tool when a user asks to change the timezone this group's events default to. Only available in group chats."
+}
+
+// ParametersJsonSchema returns the JSON Schema for input parameters.
+func (t *Tool) ParametersJsonSchema() []byte {
+	return parametersSchema
+}
+
+// ResponseJsonSchema returns the JSON Schema for the response.
+func (t *Tool) ResponseJsonSchema() []byte {
+	return responseSchema
+}
+
+// Callback saves the group's default timezone.
+func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]any, error) {
+	chatType, ok := line.ChatTypeFromContext(ctx)
+	if !ok {
+		t.logger.ErrorContext(ctx, "chat type not found in context")
+		return nil, errors.New("internal error")
+	}
+	sourceID, ok := line.SourceIDFromContext(ctx)
+	if !ok {
+		t.logger.ErrorContext(ctx, "source ID not found in context")
+		return nil, errors.New("internal error")
+	}
+
+	if chatType != line.ChatTypeGroup {
+		return nil, errors.New("the default timezone can only be set in group chats")
+	}
+
+	timezone, ok := args["timezone"].(string)
+	if !ok {
+		return nil, errors.New("invalid timezone")
+	}
+	timezone = strings.TrimSpace(timezone)
+	if err := event.ValidateTimezone(timezone); err != nil {
+		return nil, errors.New("timezone must be an IANA time zone name such as Asia/Tokyo")
+	}
+
+	err := t.groupProfileService.UpdateGroupProfile(ctx, sourceID, func(p *groupprofile.GroupProfile) {
+		p.DefaultTimezone = timezone
+	})
+	if err != nil {
+		t.logger.ErrorContext(ctx, "failed to update group timezone", slog.String("groupID", sourceID), slog.Any("error", err))
+		return nil, errors.New("failed to set group timezone")
+	}
+
+	return map[string]any{
+		"timezone": timezone,
+	}, nil
+}
@@ -0,0 +1,140 @@
+package grouptimezone_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"yuruppu/internal/groupprofile"
+	"yuruppu/internal/line"
+	"yuruppu/internal/toolset/grouptimezone"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// NewTool Tests
+// =============================================================================
+
+func TestNewTool(t *testing.T) {
+	t.Run("creates tool with dependencies", func(t *testing.T) {
+		tool, err := grouptimezone.NewTool(&mockGroupProfileService{}, slog.New(slog.DiscardHandler))
+
+		require.NoError(t, err)
+		require.NotNil(t, tool)
+		assert.Equal(t, "set_group_timezone", tool.Name())
+	})
+
+	t.Run("returns error when groupProfileService is nil", func(t *testing.T) {
+		tool, err := grouptimezone.NewTool(nil, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "groupProfileService cannot be nil")
+	})
+
+	t.Run("returns error when logger is nil", func(t *testing.T) {
+		tool, err := grouptimezone.NewTool(&mockGroupProfileService{}, nil)
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "logger cannot be nil")
+	})
+}
+
+// =============================================================================
+// Callback Tests
+// =============================================================================
+
+// groupContext returns a context for a message in group-123.
+func groupContext(ctx context.Context) context.Context {
+	ctx = line.WithChatType(ctx, line.ChatTypeGroup)
+	return line.WithSourceID(ctx, "group-123")
+}
+
+func TestTool_Callback(t *testing.T) {
+	t.Run("sets the group default timezone", func(t *testing.T) {
+		svc := &mockGroupProfileService{profile: &groupprofile.GroupProfile{DisplayName: "Group A"}}
+		tool, err := grouptimezone.NewTool(svc, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(groupContext(t.Context()), map[string]any{"timezone": " America/New_York "})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"timezone": "America/New_York"}, result)
+		assert.Equal(t, "group-123", svc.lastGroupID)
+		assert.Equal(t, "America/New_York", svc.profile.DefaultTimezone)
+		assert.Equal(t, "Group A", svc.profile.DisplayName)
+	})
+
+	t.Run("rejects unknown timezone", func(t *testing.T) {
+		svc := &mockGroupProfileService{profile: &groupprofile.GroupProfile{}}
+		tool, err := grouptimezone.NewTool(svc, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		for _, tz := range []string{"JST", "Mars/Olympus_Mons", "Local", " "} {
+			_, err = tool.Callback(groupContext(t.Context()), map[string]any{"timezone": tz})
+
+			require.Error(t, err, tz)
+			assert.Contains(t, err.Error(), "IANA time zone name", tz)
+		}
+		assert.Equal(t, 0, svc.updateCount)
+	})
+
+	t.Run("rejects one-on-one chats", func(t *testing.T) {
+		svc := &mockGroupProfileService{profile: &groupprofile.GroupProfile{}}
+		tool, err := grouptimezone.NewTool(svc, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := line.WithSourceID(line.WithChatType(t.Context(), line.ChatTypeOneOnOne), "user-123")
+		_, err = tool.Callback(ctx, map[string]any{"timezone": "Asia/Tokyo"})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "only be set in group chats")
+		assert.Equal(t, 0, svc.updateCount)
+	})
+
+	t.Run("returns error when update fails", func(t *testing.T) {
+		svc := &mockGroupProfileService{updateErr: errors.New("generation mismatch")}
+		tool, err := grouptimezone.NewTool(svc, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		_, err = tool.Callback(groupContext(t.Context()), map[string]any{"timezone": "Asia/Tokyo"})
+
+		require.Error(t, err)
+		assert.Equal(t, "failed to set group timezone", err.Error())
+	})
+
+	t.Run("returns internal error when source ID is missing", func(t *testing.T) {
+		tool, err := grouptimezone.NewTool(&mockGroupProfileService{}, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		ctx := line.WithChatType(t.Context(), line.ChatTypeGroup)
+		_, err = tool.Callback(ctx, map[string]any{"timezone": "Asia/Tokyo"})
+
+		require.Error(t, err)
+		assert.Equal(t, "internal error", err.Error())
+	})
+}
+
+// =============================================================================
+// Mocks
+// =============================================================================
+
+type mockGroupProfileService struct {
+	profile     *groupprofile.GroupProfile
+	updateErr   error
+	updateCount int
+	lastGroupID string
+}
+
+func (m *mockGroupProfileService) UpdateGroupProfile(ctx context.Context, groupID string, update func(*groupprofile.GroupProfile)) error {
+	m.updateCount++
+	m.lastGroupID = groupID
+	if m.updateErr != nil {
+		return m.updateErr
+	}
+	update(m.profile)
+	return nil
+}
@@ -0,0 +1,143 @@
+package remind
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/clock"
+	"yuruppu/internal/line"
+	"yuruppu/internal/reminder"
+	"yuruppu/internal/sanitize"
+)
+
+//go:embed parameters.json
+var parametersSchema []byte
+
+//go:embed response.json
+var responseSchema []byte
+
+const (
+	// maxLeadTime bounds how far ahead a reminder can be scheduled.
+	maxLeadTime = 365 * 24 * time.Hour
+	// maxTextLength bounds the reminder text, in runes.
+	maxTextLength = 500
+)
+
+// ReminderService provides access to reminder operations.
+type ReminderService interface {
+	Create(ctx context.Context, r *reminder.Reminder) error
+}
+
+// Tool implements the set_reminder tool for scheduling a message to the current chat.
+type Tool struct {
+	reminderService ReminderService
+	logger          *slog.Logger
+}
+
+// NewTool creates a new set_reminder tool.
+func NewTool(reminderService ReminderService, logger *slog.Logger) (*Tool, error) {
+	if reminderService == nil {
+		return nil, errors.New("reminderService cannot be nil")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil")
+	}
+	return &Tool{
+		reminderService: reminderService,
+		logger:          logger,
+	}, nil
+}
+
+// Name returns the tool name.
+func (t *Tool) Name() string {
+	return "set_reminder"
+}
+
+// Description returns a description for the LLM.
+func (t *Tool) Description() string {
+	return "Use this tool when the user asks to be reminded of something at a given time. The reminder text is sent to the current chat at notify_at; after it fires, snooze_reminder can postpone it."
+}
+
+// ParametersJsonSchema returns the JSON Schema for input parameters.
+func (t *Tool) ParametersJsonSchema() []byte {
+	return parametersSchema
+}
+
+// ResponseJsonSchema returns the JSON Schema for the response.
+func (t *Tool) ResponseJsonSchema() []byte {
+	return responseSchema
+}
+
+// Callback schedules a reminder for the current chat room.
+// A chat room has at most one reminder per time, so scheduling the same time again keeps the existing one.
+func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]any, error) {
+	chatRoomID, ok := line.SourceIDFromContext(ctx)
+	if !ok {
+		t.logger.ErrorContext(ctx, "source ID not found in context")
+		return nil, agent.NewSystemError("internal error", nil)
+	}
+
+	notifyAtArg, ok := args["notify_at"].(string)
+	if !ok {
+		return agent.Invalid("invalid notify_at"), nil
+	}
+	notifyAt, err := time.Parse(time.RFC3339, notifyAtArg)
+	if err != nil {
+		return agent.Invalid("notify_at must be in RFC3339 format"), nil
+	}
+	now := clock.Now(ctx)
+	if !notifyAt.After(now) {
+		return nil, agent.NewUserError("the reminder time must be in the future")
+	}
+	if notifyAt.Sub(now) > maxLeadTime {
+		return nil, agent.NewUserError("reminders can be set at most a year ahead")
+	}
+
+	textArg, ok := args["text"].(string)
+	if !ok {
+		return agent.Invalid("invalid text"), nil
+	}
+	text := strings.TrimSpace(sanitize.Text(textArg))
+	if text == "" {
+		return agent.Invalid("text cannot be empty"), nil
+	}
+	if utf8.RuneCountInString(text) > maxTextLength {
+		return nil, agent.UserErrorf("the reminder text must be at most %d characters", maxTextLength)
+	}
+
+	r := &reminder.Reminder{
+		ChatRoomID: chatRoomID,
+		NotifyAt:   notifyAt,
+		Text:       text,
+	}
+	if err := t.reminderService.Create(ctx, r); err != nil {
+		t.logger.ErrorContext(ctx, "failed to create reminder",
+			slog.String("chatRoomID", chatRoomID),
+			slog.Any("error", err),
+		)
+		return nil, agent.NewSystemError("failed to set reminder", err)
+	}
+
+	// Create replaces r with the reminder already scheduled at that time, if any
+	status := "ok"
+	if r.Text != text {
+		status = "already_scheduled"
+	}
+
+	t.logger.InfoContext(ctx, "reminder scheduled",
+		slog.String("chatRoomID", chatRoomID),
+		slog.String("reminderID", r.ID),
+		slog.Time("notifyAt", r.NotifyAt),
+	)
+
+	return map[string]any{
+		"status":    status,
+		"notify_at": r.NotifyAt.In(notifyAt.Location()).Format(time.RFC3339),
+		"text":      r.Text,
+	}, nil
+}
@@ -0,0 +1,269 @@
+package remind_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/clock"
+	"yuruppu/internal/line"
+	"yuruppu/internal/reminder"
+	"yuruppu/internal/toolset/remind"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// NewTool Tests
+// =============================================================================
+
+func TestNewTool(t *testing.T) {
+	t.Run("creates tool with valid dependencies", func(t *testing.T) {
+		tool, err := remind.NewTool(&mockReminderService{}, slog.New(slog.DiscardHandler))
+
+		require.NoError(t, err)
+		assert.Equal(t, "set_reminder", tool.Name())
+	})
+
+	t.Run("returns error when reminderService is nil", func(t *testing.T) {
+		tool, err := remind.NewTool(nil, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "reminderService cannot be nil")
+	})
+
+	t.Run("returns error when logger is nil", func(t *testing.T) {
+		tool, err := remind.NewTool(&mockReminderService{}, nil)
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "logger cannot be nil")
+	})
+}
+
+// =============================================================================
+// Callback Tests
+// =============================================================================
+
+func TestTool_Callback(t *testing.T) {
+	now := time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC)
+	ctx := func(t *testing.T) context.Context {
+		return clock.WithNow(line.WithSourceID(t.Context(), "group-1"), now)
+	}
+
+	t.Run("schedules a reminder for the current chat", func(t *testing.T) {
+		svc := &mockReminderService{}
+		tool := newTestTool(t, svc)
+
+		result, err := tool.Callback(ctx(t), map[string]any{
+			"notify_at": "2026-02-01T19:30:00+09:00",
+			"text":      "  Bring snacks\x00  ",
+		})
+
+		require.NoError(t, err)
+		require.NotNil(t, svc.created)
+		assert.Equal(t, "group-1", svc.created.ChatRoomID)
+		assert.True(t, svc.created.NotifyAt.Equal(time.Date(2026, 2, 1, 10, 30, 0, 0, time.UTC)))
+		assert.Equal(t, "Bring snacks", svc.created.Text)
+		assert.Equal(t, map[string]any{
+			"status":    "ok",
+			"notify_at": "2026-02-01T19:30:00+09:00",
+			"text":      "Bring snacks",
+		}, result)
+	})
+
+	t.Run("keeps the reminder already scheduled at that time", func(t *testing.T) {
+		svc := &mockReminderService{existing: &reminder.Reminder{
+			ID:         "rem-1",
+			ChatRoomID: "group-1",
+			NotifyAt:   time.Date(2026, 2, 1, 10, 30, 0, 0, time.UTC),
+			Text:       "Book the room",
+		}}
+		tool := newTestTool(t, svc)
+
+		result, err := tool.Callback(ctx(t), map[string]any{
+			"notify_at": "2026-02-01T19:30:00+09:00",
+			"text":      "Bring snacks",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"status":    "already_scheduled",
+			"notify_at": "2026-02-01T19:30:00+09:00",
+			"text":      "Book the room",
+		}, result)
+	})
+
+	t.Run("rejects a time in the past", func(t *testing.T) {
+		svc := &mockReminderService{}
+		tool := newTestTool(t, svc)
+
+		result, err := tool.Callback(ctx(t), map[string]any{
+			"notify_at": "2026-02-01T08:00:00Z",
+			"text":      "Bring snacks",
+		})
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+		var userErr *agent.UserError
+		require.ErrorAs(t, err, &userErr)
+		assert.Equal(t, "the reminder time must be in the future", err.Error())
+		assert.Nil(t, svc.created)
+	})
+
+	t.Run("rejects a time more than a year ahead", func(t *testing.T) {
+		svc := &mockReminderService{}
+		tool := newTestTool(t, svc)
+
+		result, err := tool.Callback(ctx(t), map[string]any{
+			"notify_at": "2027-02-02T09:00:00Z",
+			"text":      "Bring snacks",
+		})
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+		assert.Equal(t, "reminders can be set at most a year ahead", err.Error())
+		assert.Nil(t, svc.created)
+	})
+
+	t.Run("returns invalid for a malformed notify_at", func(t *testing.T) {
+		svc := &mockReminderService{}
+		tool := newTestTool(t, svc)
+
+		result, err := tool.Callback(ctx(t), map[string]any{
+			"notify_at": "tomorrow evening",
+			"text":      "Bring snacks",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, agent.Invalid("notify_at must be in RFC3339 format"), result)
+		assert.Nil(t, svc.created)
+	})
+
+	t.Run("returns invalid for text that is empty after sanitizing", func(t *testing.T) {
+		svc := &mockReminderService{}
+		tool := newTestTool(t, svc)
+
+		result, err := tool.Callback(ctx(t), map[string]any{
+			"notify_at": "2026-02-01T19:30:00+09:00",
+			"text":      " \x07 ",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, agent.Invalid("text cannot be empty"), result)
+		assert.Nil(t, svc.created)
+	})
+
+	t.Run("returns error when creating fails", func(t *testing.T) {
+		svc := &mockReminderService{err: errors.New("storage down")}
+		tool := newTestTool(t, svc)
+
+		result, err := tool.Callback(ctx(t), map[string]any{
+			"notify_at": "2026-02-01T19:30:00+09:00",
+			"text":      "Bring snacks",
+		})
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+		assert.Equal(t, "failed to set reminder", err.Error())
+	})
+
+	t.Run("returns error when source ID is missing", func(t *testing.T) {
+		tool := newTestTool(t, &mockReminderService{})
+
+		_, err := tool.Callback(clock.WithNow(t.Context(), now), map[string]any{
+			"notify_at": "2026-02-01T19:30:00+09:00",
+			"text":      "Bring snacks",
+		})
+
+		require.Error(t, err)
+		assert.Equal(t, "internal error", err.Error())
+	})
+}
+
+// =============================================================================
+// Integration Tests
+// =============================================================================
+
+func TestTool_Callback_WithReminderService(t *testing.T) {
+	t.Run("scheduled reminder becomes due at notify_at", func(t *testing.T) {
+		svc, err := reminder.NewService(newMemStorage())
+		require.NoError(t, err)
+		tool := newTestTool(t, svc)
+		now := time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC)
+		ctx := clock.WithNow(line.WithSourceID(t.Context(), "group-1"), now)
+
+		_, err = tool.Callback(ctx, map[string]any{"notify_at": "2026-02-01T10:00:00Z", "text": "Bring snacks"})
+		require.NoError(t, err)
+
+		early, err := svc.ListDue(t.Context(), now.Add(59*time.Minute))
+		require.NoError(t, err)
+		assert.Empty(t, early)
+		due, err := svc.ListDue(t.Context(), now.Add(time.Hour))
+		require.NoError(t, err)
+		require.Len(t, due, 1)
+		assert.Equal(t, "group-1", due[0].ChatRoomID)
+		assert.Equal(t, "Bring snacks", due[0].Text)
+	})
+}
+
+// =============================================================================
+// Helpers
+// =============================================================================
+
+func newTestTool(t *testing.T, svc remind.ReminderService) *remind.Tool {
+	t.Helper()
+	tool, err := remind.NewTool(svc, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+	return tool
+}
+
+// =============================================================================
+// Mocks
+// =============================================================================
+
+type mockReminderService struct {
+	existing *reminder.Reminder
+	err      error
+	created  *reminder.Reminder
+}
+
+func (m *mockReminderService) Create(ctx context.Context, r *reminder.Reminder) error {
+	if m.err != nil {
+		return m.err
+	}
+	if m.existing != nil {
+		*r = *m.existing
+		return nil
+	}
+	r.ID = "rem-new"
+	m.created = r
+	return nil
+}
+
+// memStorage is an in-memory reminder.Storage that honors generations like GCS.
+type memStorage struct {
+	data       map[string][]byte
+	generation map[string]int64
+}
+
+func newMemStorage() *memStorage {
+	return &memStorage{data: make(map[string][]byte), generation: make(map[string]int64)}
+}
+
+func (m *memStorage) Read(ctx context.Context, key string) ([]byte, int64, error) {
+	return m.data[key], m.generation[key], nil
+}
+
+func (m *memStorage) Write(ctx context.Context, key, mimetype string, data []byte, expectedGeneration int64) (int64, error) {
+	if expectedGeneration != m.generation[key] {
+		return 0, errors.New("generation mismatch")
+	}
+	m.data[key] = data
+	m.generation[key]++
+	return m.generation[key], nil
+}
@@ -9,6 +9,7 @@ import (
 	"yuruppu/internal/agent"
 	"yuruppu/internal/history"
 	"yuruppu/internal/line"
+	"yuruppu/internal/linetext"
 )
 
 //go:embed parameters.json
@@ -19,24 +20,37 @@ var responseSchema []byte
 
 // LineClient provides access to LINE API.
 type LineClient interface {
-	SendReply(replyToken string, text string) error
+	Send(ctx context.Context, text string) error
 }
 
 // HistoryService provides access to conversation history.
 type HistoryService interface {
 	GetHistory(ctx context.Context, sourceID string) ([]history.Message, int64, error)
-	PutHistory(ctx context.Context, sourceID string, messages []history.Message, expectedGeneration int64) (int64, error)
+	Append(ctx context.Context, sourceID string, messages ...history.Message) ([]history.Message, error)
 }
 
 // Tool implements the reply tool for sending LINE messages.
 type Tool struct {
-	lineClient LineClient
-	history    HistoryService
-	logger     *slog.Logger
+	lineClient      LineClient
+	history         HistoryService
+	convertMarkdown bool
+	logger          *slog.Logger
+}
+
+// Option configures optional Tool behavior.
+type Option func(*Tool)
+
+// WithMarkdownConversion converts Markdown in replies to plain text before they are sent,
+// since LINE shows markers like ** as is. The converted text is what is saved to history.
+// Off by default.
+func WithMarkdownConversion() Option {
+	return func(t *Tool) {
+		t.convertMarkdown = true
+	}
 }
 
 // NewTool creates a new reply tool with the specified dependencies.
-func NewTool(lineClient LineClient, historySvc HistoryService, logger *slog.Logger) (*Tool, error) {
+func NewTool(lineClient LineClient, historySvc HistoryService, logger *slog.Logger, opts ...Option) (*Tool, error) {
 	if lineClient == nil {
 		return nil, errors.New("lineClient cannot be nil")
 	}
@@ -46,11 +60,15 @@ func NewTool(lineClient LineClient, historySvc HistoryService, logger *slog.Logg
 	if logger == nil {
 		return nil, errors.New("logger cannot be nil")
 	}
-	return &Tool{
+	t := &Tool{
 		lineClient: lineClient,
 		history:    historySvc,
 		logger:     logger,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t, nil
 }
 
 // Name returns the tool name.
@@ -80,13 +98,6 @@ func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]an
 		return nil, errors.New("invalid message")
 	}
 
-	// Get replyToken and sourceID from context
-	replyToken, ok := line.ReplyTokenFromContext(ctx)
-	if !ok {
-		t.logger.ErrorContext(ctx, "reply token not found in context")
-		return nil, errors.New("internal error")
-	}
-
 	sourceID, ok := line.SourceIDFromContext(ctx)
 	if !ok {
 		t.logger.ErrorContext(ctx, "source ID not found in context")
@@ -99,9 +110,8 @@ func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]an
 		return nil, errors.New("internal error")
 	}
 
-	// Load history
-	hist, gen, err := t.history.GetHistory(ctx, sourceID)
-	if err != nil {
+	// Check the history loads before sending a reply it could not be saved to
+	if _, _, err := t.history.GetHistory(ctx, sourceID); err != nil {
 		t.logger.ErrorContext(ctx, "failed to load history",
 			slog.String("sourceID", sourceID),
 			slog.Any("error", err),
@@ -109,8 +119,15 @@ func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]an
 		return nil, errors.New("failed to load conversation")
 	}
 
-	// Send reply
-	if err := t.lineClient.SendReply(replyToken, message); err != nil {
+	// Keep the original if nothing but Markdown syntax would be left to send
+	if t.convertMarkdown {
+		if converted := linetext.FromMarkdown(message); converted != "" {
+			message = converted
+		}
+	}
+
+	// Send reply (pushed instead when the reply token is missing or stale)
+	if err := t.lineClient.Send(ctx, message); err != nil {
 		t.logger.ErrorContext(ctx, "failed to send reply",
 			slog.String("sourceID", sourceID),
 			slog.Any("error", err),
@@ -118,16 +135,15 @@ func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]an
 		return nil, errors.New("failed to send reply")
 	}
 
-	// Append assistant message to history
+	// Build assistant message
 	assistantMsg := &history.AssistantMessage{
 		ModelName: modelName,
 		Parts:     []history.AssistantPart{&history.AssistantTextPart{Text: message}},
 		Timestamp: time.Now(),
 	}
-	hist = append(hist, assistantMsg)
 
-	// Save history
-	if _, err := t.history.PutHistory(ctx, sourceID, hist, gen); err != nil {
+	// Save history, keeping any message that arrived while the reply was being sent
+	if _, err := t.history.Append(ctx, sourceID, assistantMsg); err != nil {
 		t.logger.ErrorContext(ctx, "failed to save history",
 			slog.String("sourceID", sourceID),
 			slog.Any("error", err),
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"slices"
 	"testing"
 	"yuruppu/internal/agent"
 	"yuruppu/internal/history"
@@ -91,7 +92,7 @@ func TestTool_Callback(t *testing.T) {
 		assert.Equal(t, map[string]any{"status": "sent"}, result)
 		assert.Equal(t, "reply-token", sender.lastReplyToken)
 		assert.Equal(t, "Hello!", sender.lastText)
-		assert.Equal(t, 1, historyRepo.putCount)
+		assert.Equal(t, 1, historyRepo.appendCount)
 	})
 
 	t.Run("error - invalid message (missing)", func(t *testing.T) {
@@ -123,20 +124,22 @@ func TestTool_Callback(t *testing.T) {
 		assert.Contains(t, err.Error(), "invalid message")
 	})
 
-	t.Run("error - reply token not in context", func(t *testing.T) {
+	t.Run("success - sends without reply token in context", func(t *testing.T) {
 		sender := &mockSender{}
 		historyRepo := &mockHistoryRepo{}
 		tool, _ := reply.NewTool(sender, historyRepo, slog.New(slog.DiscardHandler))
 
-		// Only set sourceID, not replyToken
+		// Only set sourceID and modelName, not replyToken; the client pushes instead
 		ctx := line.WithSourceID(t.Context(), "source-123")
+		ctx = agent.WithModelName(ctx, "gemini-2.0-flash")
 		result, err := tool.Callback(ctx, map[string]any{
 			"message": "Hello!",
 		})
 
-		require.Error(t, err)
-		assert.Nil(t, result)
-		assert.Contains(t, err.Error(), "internal error")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"status": "sent"}, result)
+		assert.Empty(t, sender.lastReplyToken)
+		assert.Equal(t, "Hello!", sender.lastText)
 	})
 
 	t.Run("error - source ID not in context", func(t *testing.T) {
@@ -205,13 +208,13 @@ func TestTool_Callback(t *testing.T) {
 		require.Error(t, err)
 		assert.Nil(t, result)
 		assert.Contains(t, err.Error(), "failed to send reply")
-		assert.Equal(t, 0, historyRepo.putCount)
+		assert.Equal(t, 0, historyRepo.appendCount)
 	})
 
 	t.Run("error - history save fails", func(t *testing.T) {
 		sender := &mockSender{}
 		historyRepo := &mockHistoryRepo{
-			putErr: errors.New("storage error"),
+			appendErr: errors.New("storage error"),
 		}
 		tool, _ := reply.NewTool(sender, historyRepo, slog.New(slog.DiscardHandler))
 
@@ -227,6 +230,46 @@ func TestTool_Callback(t *testing.T) {
 		assert.Equal(t, 1, sender.callCount)
 	})
 
+	t.Run("converts markdown before sending and saving when enabled", func(t *testing.T) {
+		sender := &mockSender{}
+		historyRepo := &mockHistoryRepo{}
+		tool, _ := reply.NewTool(sender, historyRepo, slog.New(slog.DiscardHandler), reply.WithMarkdownConversion())
+
+		ctx := withToolContext(t.Context(), "reply-token", "source-123", "gemini-2.0-flash")
+		_, err := tool.Callback(ctx, map[string]any{
+			"message": "**持ち物**\n\n\n- お弁当\n- `my_file.txt`",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "持ち物\n\n・お弁当\n・my_file.txt", sender.lastText)
+		require.Len(t, historyRepo.lastSavedHistory, 1)
+		assistantMsg, ok := historyRepo.lastSavedHistory[0].(*history.AssistantMessage)
+		require.True(t, ok)
+		assert.Equal(t, sender.lastText, assistantMsg.Parts[0].(*history.AssistantTextPart).Text)
+	})
+
+	t.Run("sends markdown as is when conversion is disabled", func(t *testing.T) {
+		sender := &mockSender{}
+		tool, _ := reply.NewTool(sender, &mockHistoryRepo{}, slog.New(slog.DiscardHandler))
+
+		ctx := withToolContext(t.Context(), "reply-token", "source-123", "gemini-2.0-flash")
+		_, err := tool.Callback(ctx, map[string]any{"message": "**持ち物**"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "**持ち物**", sender.lastText)
+	})
+
+	t.Run("sends the original when conversion leaves nothing", func(t *testing.T) {
+		sender := &mockSender{}
+		tool, _ := reply.NewTool(sender, &mockHistoryRepo{}, slog.New(slog.DiscardHandler), reply.WithMarkdownConversion())
+
+		ctx := withToolContext(t.Context(), "reply-token", "source-123", "gemini-2.0-flash")
+		_, err := tool.Callback(ctx, map[string]any{"message": "```"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "```", sender.lastText)
+	})
+
 	t.Run("appends assistant message to existing history", func(t *testing.T) {
 		sender := &mockSender{}
 		existingHistory := []history.Message{
@@ -247,13 +290,13 @@ func TestTool_Callback(t *testing.T) {
 
 		require.NoError(t, err)
 		// Verify history has both messages
-		require.Len(t, historyRepo.lastPutMessages, 2)
+		require.Len(t, historyRepo.lastSavedHistory, 2)
 		// First message is user message
-		userMsg, ok := historyRepo.lastPutMessages[0].(*history.UserMessage)
+		userMsg, ok := historyRepo.lastSavedHistory[0].(*history.UserMessage)
 		require.True(t, ok)
 		assert.Equal(t, "user-1", userMsg.UserID)
 		// Second message is assistant message
-		assistantMsg, ok := historyRepo.lastPutMessages[1].(*history.AssistantMessage)
+		assistantMsg, ok := historyRepo.lastSavedHistory[1].(*history.AssistantMessage)
 		require.True(t, ok)
 		assert.Equal(t, "gemini-2.0-flash", assistantMsg.ModelName)
 		require.Len(t, assistantMsg.Parts, 1)
@@ -274,21 +317,21 @@ type mockSender struct {
 	callCount      int
 }
 
-func (m *mockSender) SendReply(replyToken string, text string) error {
+func (m *mockSender) Send(ctx context.Context, text string) error {
 	m.callCount++
-	m.lastReplyToken = replyToken
+	m.lastReplyToken, _ = line.ReplyTokenFromContext(ctx)
 	m.lastText = text
 	return m.err
 }
 
 type mockHistoryRepo struct {
-	history         []history.Message
-	generation      int64
-	getErr          error
-	putErr          error
-	getCount        int
-	putCount        int
-	lastPutMessages []history.Message
+	history          []history.Message
+	generation       int64
+	getErr           error
+	appendErr        error
+	getCount         int
+	appendCount      int
+	lastSavedHistory []history.Message
 }
 
 func (m *mockHistoryRepo) GetHistory(ctx context.Context, sourceID string) ([]history.Message, int64, error) {
@@ -299,11 +342,11 @@ func (m *mockHistoryRepo) GetHistory(ctx context.Context, sourceID string) ([]hi
 	return m.history, m.generation, nil
 }
 
-func (m *mockHistoryRepo) PutHistory(ctx context.Context, sourceID string, messages []history.Message, expectedGeneration int64) (int64, error) {
-	m.putCount++
-	m.lastPutMessages = messages
-	if m.putErr != nil {
-		return 0, m.putErr
+func (m *mockHistoryRepo) Append(ctx context.Context, sourceID string, messages ...history.Message) ([]history.Message, error) {
+	m.appendCount++
+	m.lastSavedHistory = append(slices.Clip(m.history), messages...)
+	if m.appendErr != nil {
+		return nil, m.appendErr
 	}
-	return m.generation + 1, nil
+	return m.lastSavedHistory, nil
 }
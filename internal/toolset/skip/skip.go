@@ -5,6 +5,8 @@ import (
 	_ "embed"
 	"errors"
 	"log/slog"
+	"sync"
+	"yuruppu/internal/agent"
 )
 
 //go:embed parameters.json
@@ -13,9 +15,33 @@ var parametersSchema []byte
 //go:embed response.json
 var responseSchema []byte
 
+// ReasonCode categorizes why the bot chose not to reply.
+type ReasonCode string
+
+const (
+	ReasonNotDirected  ReasonCode = "not_directed"
+	ReasonAcknowledged ReasonCode = "acknowledged"
+	ReasonIncomplete   ReasonCode = "incomplete"
+	ReasonOffTopic     ReasonCode = "off_topic"
+	ReasonOther        ReasonCode = "other"
+)
+
+// validReasonCodes mirrors the reason_code enum in parameters.json.
+var validReasonCodes = map[ReasonCode]bool{
+	ReasonNotDirected:  true,
+	ReasonAcknowledged: true,
+	ReasonIncomplete:   true,
+	ReasonOffTopic:     true,
+	ReasonOther:        true,
+}
+
 // Tool implements the skip tool for explicitly not replying.
+// It counts skips per reason code so group behavior can be tuned from the logs.
 type Tool struct {
 	logger *slog.Logger
+
+	mu     sync.Mutex
+	counts map[ReasonCode]int
 }
 
 // NewTool creates a new skip tool.
@@ -25,6 +51,7 @@ func NewTool(logger *slog.Logger) (*Tool, error) {
 	}
 	return &Tool{
 		logger: logger,
+		counts: make(map[ReasonCode]int),
 	}, nil
 }
 
@@ -48,15 +75,42 @@ func (t *Tool) ResponseJsonSchema() []byte {
 	return responseSchema
 }
 
-// Callback does nothing and returns success.
+// Callback records the reason code and returns success.
+// Returns a user error if reason_code is missing or not in the enum.
 func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]any, error) {
+	codeArg, _ := args["reason_code"].(string)
+	code := ReasonCode(codeArg)
+	if !validReasonCodes[code] {
+		return nil, agent.NewUserError("reason_code must be one of not_directed, acknowledged, incomplete, off_topic, other")
+	}
 	reason, _ := args["reason"].(string)
-	t.logger.DebugContext(ctx, "skip tool called", slog.String("reason", reason))
+
+	t.mu.Lock()
+	t.counts[code]++
+	count := t.counts[code]
+	t.mu.Unlock()
+
+	t.logger.InfoContext(ctx, "skip tool called",
+		slog.String("reasonCode", string(code)),
+		slog.String("reason", reason),
+		slog.Int("count", count),
+	)
 	return map[string]any{
 		"status": "skipped",
 	}, nil
 }
 
+// Counts returns how many times each reason code has been recorded since the tool was created.
+func (t *Tool) Counts() map[ReasonCode]int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	counts := make(map[ReasonCode]int, len(t.counts))
+	for code, n := range t.counts {
+		counts[code] = n
+	}
+	return counts
+}
+
 // IsFinal returns true if the skip was successful.
 func (t *Tool) IsFinal(validatedResult map[string]any) bool {
 	status, ok := validatedResult["status"].(string)
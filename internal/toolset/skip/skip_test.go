@@ -0,0 +1,124 @@
+package skip_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/toolset/skip"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// NewTool Tests
+// =============================================================================
+
+func TestNewTool(t *testing.T) {
+	t.Run("creates tool with logger", func(t *testing.T) {
+		tool, err := skip.NewTool(slog.New(slog.DiscardHandler))
+
+		require.NoError(t, err)
+		require.NotNil(t, tool)
+		assert.Equal(t, "skip", tool.Name())
+	})
+
+	t.Run("returns error when logger is nil", func(t *testing.T) {
+		tool, err := skip.NewTool(nil)
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "logger cannot be nil")
+	})
+}
+
+// =============================================================================
+// Tool Metadata Tests
+// =============================================================================
+
+func TestTool_ParametersJsonSchema(t *testing.T) {
+	tool, err := skip.NewTool(slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+
+	var schema struct {
+		Properties struct {
+			ReasonCode struct {
+				Enum []string `json:"enum"`
+			} `json:"reason_code"`
+		} `json:"properties"`
+		Required []string `json:"required"`
+	}
+	require.NoError(t, json.Unmarshal(tool.ParametersJsonSchema(), &schema))
+
+	assert.Equal(t, []string{"not_directed", "acknowledged", "incomplete", "off_topic", "other"}, schema.Properties.ReasonCode.Enum)
+	assert.Equal(t, []string{"reason_code"}, schema.Required, "free-text reason stays optional")
+}
+
+// =============================================================================
+// Callback Tests
+// =============================================================================
+
+func TestTool_Callback(t *testing.T) {
+	t.Run("records valid reason codes", func(t *testing.T) {
+		var logs bytes.Buffer
+		tool, err := skip.NewTool(slog.New(slog.NewJSONHandler(&logs, nil)))
+		require.NoError(t, err)
+
+		for _, args := range []map[string]any{
+			{"reason_code": "not_directed", "reason": "talking to each other"},
+			{"reason_code": "acknowledged"},
+			{"reason_code": "not_directed"},
+		} {
+			result, err := tool.Callback(t.Context(), args)
+			require.NoError(t, err)
+			assert.Equal(t, map[string]any{"status": "skipped"}, result)
+		}
+
+		assert.Equal(t, map[skip.ReasonCode]int{
+			skip.ReasonNotDirected:  2,
+			skip.ReasonAcknowledged: 1,
+		}, tool.Counts())
+		assert.Contains(t, logs.String(), `"reasonCode":"not_directed","reason":"talking to each other","count":1`)
+		assert.Contains(t, logs.String(), `"reasonCode":"acknowledged","reason":"","count":1`)
+		assert.Contains(t, logs.String(), `"reasonCode":"not_directed","reason":"","count":2`)
+	})
+
+	t.Run("rejects an invalid reason code", func(t *testing.T) {
+		tool, err := skip.NewTool(slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(t.Context(), map[string]any{"reason_code": "bored", "reason": "nothing to add"})
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+		var userErr *agent.UserError
+		assert.ErrorAs(t, err, &userErr)
+		assert.Contains(t, err.Error(), "reason_code must be one of")
+		assert.Empty(t, tool.Counts())
+	})
+
+	t.Run("rejects a missing reason code", func(t *testing.T) {
+		tool, err := skip.NewTool(slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(t.Context(), map[string]any{"reason": "nothing to add"})
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+		assert.Empty(t, tool.Counts())
+	})
+}
+
+// =============================================================================
+// IsFinal Tests
+// =============================================================================
+
+func TestTool_IsFinal(t *testing.T) {
+	tool, err := skip.NewTool(slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+
+	assert.True(t, tool.IsFinal(map[string]any{"status": "skipped"}))
+	assert.False(t, tool.IsFinal(map[string]any{}))
+}
@@ -0,0 +1,119 @@
+package snooze
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"log/slog"
+	"time"
+	"yuruppu/internal/clock"
+	"yuruppu/internal/line"
+	"yuruppu/internal/reminder"
+)
+
+//go:embed parameters.json
+var parametersSchema []byte
+
+//go:embed response.json
+var responseSchema []byte
+
+// jst is the timezone notify_at is reported in.
+var jst = time.FixedZone("Asia/Tokyo", 9*60*60)
+
+// ReminderService provides access to reminder operations.
+type ReminderService interface {
+	LatestFired(ctx context.Context, chatRoomID string) (*reminder.Reminder, error)
+	Snooze(ctx context.Context, id string, delay time.Duration, now time.Time) (*reminder.Reminder, error)
+}
+
+// Tool implements the snooze_reminder tool for postponing a reminder that has just fired.
+type Tool struct {
+	reminderService ReminderService
+	logger          *slog.Logger
+}
+
+// NewTool creates a new snooze_reminder tool.
+func NewTool(reminderService ReminderService, logger *slog.Logger) (*Tool, error) {
+	if reminderService == nil {
+		return nil, errors.New("reminderService cannot be nil")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil")
+	}
+	return &Tool{
+		reminderService: reminderService,
+		logger:          logger,
+	}, nil
+}
+
+// Name returns the tool name.
+func (t *Tool) Name() string {
+	return "snooze_reminder"
+}
+
+// Description returns a description for the LLM.
+func (t *Tool) Description() string {
+	return "Use this tool when a reminder has just been sent to the chat and the user asks to be reminded again later. The most recent reminder in the chat fires again after the given number of minutes. A reminder can only be snoozed a few times and not more than a day past its original time."
+}
+
+// ParametersJsonSchema returns the JSON Schema for input parameters.
+func (t *Tool) ParametersJsonSchema() []byte {
+	return parametersSchema
+}
+
+// ResponseJsonSchema returns the JSON Schema for the response.
+func (t *Tool) ResponseJsonSchema() []byte {
+	return responseSchema
+}
+
+// Callback reschedules the latest fired reminder of a chat room.
+func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]any, error) {
+	chatRoomID, ok := line.SourceIDFromContext(ctx)
+	if !ok {
+		t.logger.ErrorContext(ctx, "source ID not found in context")
+		return nil, errors.New("internal error")
+	}
+	if chatRoomIDArg, ok := args["chat_room_id"]; ok {
+		chatRoomID, ok = chatRoomIDArg.(string)
+		if !ok || chatRoomID == "" {
+			return nil, errors.New("invalid chat_room_id")
+		}
+	}
+
+	minutes, ok := args["minutes"].(float64)
+	if !ok || minutes < 1 {
+		return nil, errors.New("invalid minutes")
+	}
+
+	latest, err := t.reminderService.LatestFired(ctx, chatRoomID)
+	if err != nil {
+		if errors.As(err, new(*reminder.NotFoundError)) {
+			return map[string]any{"status": "not_found"}, nil
+		}
+		t.logger.ErrorContext(ctx, "failed to get latest fired reminder",
+			slog.String("chatRoomID", chatRoomID),
+			slog.Any("error", err),
+		)
+		return nil, errors.New("failed to get reminder")
+	}
+
+	snoozed, err := t.reminderService.Snooze(ctx, latest.ID, time.Duration(minutes)*time.Minute, clock.Now(ctx))
+	switch {
+	case errors.As(err, new(*reminder.AlreadySnoozedError)):
+		return map[string]any{"status": "already_snoozed"}, nil
+	case errors.As(err, new(*reminder.SnoozeLimitError)):
+		return map[string]any{"status": "limit_reached"}, nil
+	case err != nil:
+		t.logger.ErrorContext(ctx, "failed to snooze reminder",
+			slog.String("chatRoomID", chatRoomID),
+			slog.String("reminderID", latest.ID),
+			slog.Any("error", err),
+		)
+		return nil, errors.New("failed to snooze reminder")
+	}
+
+	return map[string]any{
+		"status":    "ok",
+		"notify_at": snoozed.NotifyAt.In(jst).Format("2006/01/02 15:04"),
+	}, nil
+}
@@ -0,0 +1,183 @@
+package snooze_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+	"yuruppu/internal/line"
+	"yuruppu/internal/reminder"
+	"yuruppu/internal/toolset/snooze"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// NewTool Tests
+// =============================================================================
+
+func TestNewTool(t *testing.T) {
+	t.Run("creates tool with valid dependencies", func(t *testing.T) {
+		tool, err := snooze.NewTool(&mockReminderService{}, slog.New(slog.DiscardHandler))
+
+		require.NoError(t, err)
+		assert.Equal(t, "snooze_reminder", tool.Name())
+	})
+
+	t.Run("returns error when reminderService is nil", func(t *testing.T) {
+		tool, err := snooze.NewTool(nil, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "reminderService cannot be nil")
+	})
+
+	t.Run("returns error when logger is nil", func(t *testing.T) {
+		tool, err := snooze.NewTool(&mockReminderService{}, nil)
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "logger cannot be nil")
+	})
+}
+
+// =============================================================================
+// Callback Tests
+// =============================================================================
+
+func TestTool_Callback(t *testing.T) {
+	notifyAt := time.Date(2026, 2, 1, 10, 15, 0, 0, time.UTC)
+
+	t.Run("snoozes the latest fired reminder of the current chat", func(t *testing.T) {
+		svc := &mockReminderService{
+			latest:  &reminder.Reminder{ID: "rem-1", ChatRoomID: "group-1"},
+			snoozed: &reminder.Reminder{ID: "rem-2", ChatRoomID: "group-1", NotifyAt: notifyAt},
+		}
+		tool := newTestTool(t, svc)
+
+		result, err := tool.Callback(line.WithSourceID(t.Context(), "group-1"), map[string]any{"minutes": float64(15)})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"status": "ok", "notify_at": "2026/02/01 19:15"}, result)
+		assert.Equal(t, "group-1", svc.lastChatRoomID)
+		assert.Equal(t, "rem-1", svc.lastSnoozeID)
+		assert.Equal(t, 15*time.Minute, svc.lastDelay)
+	})
+
+	t.Run("uses chat_room_id when given", func(t *testing.T) {
+		svc := &mockReminderService{
+			latest:  &reminder.Reminder{ID: "rem-1", ChatRoomID: "group-2"},
+			snoozed: &reminder.Reminder{ID: "rem-2", NotifyAt: notifyAt},
+		}
+		tool := newTestTool(t, svc)
+
+		_, err := tool.Callback(line.WithSourceID(t.Context(), "user-1"), map[string]any{"chat_room_id": "group-2", "minutes": float64(5)})
+
+		require.NoError(t, err)
+		assert.Equal(t, "group-2", svc.lastChatRoomID)
+	})
+
+	t.Run("returns limit_reached when the snooze bound is exceeded", func(t *testing.T) {
+		svc := &mockReminderService{
+			latest:    &reminder.Reminder{ID: "rem-1"},
+			snoozeErr: &reminder.SnoozeLimitError{ID: "rem-1"},
+		}
+		tool := newTestTool(t, svc)
+
+		result, err := tool.Callback(line.WithSourceID(t.Context(), "group-1"), map[string]any{"minutes": float64(60)})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"status": "limit_reached"}, result)
+	})
+
+	t.Run("returns already_snoozed when the reminder was snoozed before", func(t *testing.T) {
+		svc := &mockReminderService{
+			latest:    &reminder.Reminder{ID: "rem-1"},
+			snoozeErr: &reminder.AlreadySnoozedError{ID: "rem-1"},
+		}
+		tool := newTestTool(t, svc)
+
+		result, err := tool.Callback(line.WithSourceID(t.Context(), "group-1"), map[string]any{"minutes": float64(10)})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"status": "already_snoozed"}, result)
+	})
+
+	t.Run("returns not_found when no reminder has fired", func(t *testing.T) {
+		svc := &mockReminderService{latestErr: &reminder.NotFoundError{ChatRoomID: "group-1"}}
+		tool := newTestTool(t, svc)
+
+		result, err := tool.Callback(line.WithSourceID(t.Context(), "group-1"), map[string]any{"minutes": float64(10)})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"status": "not_found"}, result)
+		assert.Empty(t, svc.lastSnoozeID)
+	})
+
+	t.Run("returns error for invalid minutes", func(t *testing.T) {
+		svc := &mockReminderService{}
+		tool := newTestTool(t, svc)
+
+		_, err := tool.Callback(line.WithSourceID(t.Context(), "group-1"), map[string]any{"minutes": float64(0)})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid minutes")
+	})
+
+	t.Run("returns error when snoozing fails", func(t *testing.T) {
+		svc := &mockReminderService{
+			latest:    &reminder.Reminder{ID: "rem-1"},
+			snoozeErr: errors.New("storage down"),
+		}
+		tool := newTestTool(t, svc)
+
+		_, err := tool.Callback(line.WithSourceID(t.Context(), "group-1"), map[string]any{"minutes": float64(10)})
+
+		require.Error(t, err)
+		assert.Equal(t, "failed to snooze reminder", err.Error())
+	})
+
+	t.Run("returns error when source ID is missing", func(t *testing.T) {
+		tool := newTestTool(t, &mockReminderService{})
+
+		_, err := tool.Callback(t.Context(), map[string]any{"minutes": float64(10)})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "internal error")
+	})
+}
+
+// =============================================================================
+// Helpers
+// =============================================================================
+
+func newTestTool(t *testing.T, svc *mockReminderService) *snooze.Tool {
+	t.Helper()
+	tool, err := snooze.NewTool(svc, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+	return tool
+}
+
+type mockReminderService struct {
+	latest    *reminder.Reminder
+	latestErr error
+	snoozed   *reminder.Reminder
+	snoozeErr error
+
+	lastChatRoomID string
+	lastSnoozeID   string
+	lastDelay      time.Duration
+}
+
+func (m *mockReminderService) LatestFired(ctx context.Context, chatRoomID string) (*reminder.Reminder, error) {
+	m.lastChatRoomID = chatRoomID
+	return m.latest, m.latestErr
+}
+
+func (m *mockReminderService) Snooze(ctx context.Context, id string, delay time.Duration, now time.Time) (*reminder.Reminder, error) {
+	m.lastSnoozeID = id
+	m.lastDelay = delay
+	return m.snoozed, m.snoozeErr
+}
@@ -3,13 +3,11 @@ package weather
 import (
 	"context"
 	_ "embed"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"log/slog"
-	"net/http"
-	"net/url"
+	"slices"
+	"yuruppu/internal/agent"
 )
 
 //go:embed parameters.json
@@ -18,33 +16,87 @@ var parametersSchema []byte
 //go:embed response.json
 var responseSchema []byte
 
-const (
-	wttrURL         = "https://wttr.in/%s?format=j1"
-	maxResponseSize = 1 << 20 // 1MB
-)
+// ProviderWttr is the name of the wttr.in provider, the default.
+const ProviderWttr = "wttr"
+
+// MaxForecastDays is how many days, starting today, a Provider forecasts.
+const MaxForecastDays = 3
+
+// Conditions are the weather conditions at one point in time.
+// Values are kept as the strings reported by the provider. Time and RainChance are empty for current conditions.
+type Conditions struct {
+	Time          string // HHMM without leading zeros (e.g., 0, 300, 1500)
+	TempC         string
+	FeelsLikeC    string
+	Condition     string // e.g., Sunny, Cloudy, Rain; empty if unknown
+	Humidity      string
+	WindSpeedKmph string
+	WindDirection string // 16-point compass (e.g., N, SW)
+	RainChance    string
+	UVIndex       string
+	Pressure      string
+	Visibility    string
+	CloudCover    string
+}
+
+// DayForecast is the forecast for one day.
+type DayForecast struct {
+	Date     string // YYYY-MM-DD
+	MaxTempC string
+	MinTempC string
+	AvgTempC string
+	Sunrise  string
+	Sunset   string
+	Hourly   []Conditions // 3-hourly breakdown
+}
 
-// HTTPClient is an interface for HTTP requests.
-type HTTPClient interface {
-	Do(req *http.Request) (*http.Response, error)
+// Provider fetches weather data from an upstream source.
+// Errors are returned from the tool as is, so they must be short, must not leak internals,
+// and should be classified with agent.NewUserError or agent.NewSystemError.
+type Provider interface {
+	// Current returns the observed conditions at location.
+	Current(ctx context.Context, location string) (*Conditions, error)
+	// Forecast returns up to days daily forecasts for location, starting today.
+	Forecast(ctx context.Context, location string, days int) ([]DayForecast, error)
 }
 
-// Tool implements the weather forecast tool using wttr.in API.
+// ProviderNames returns the names accepted by NewProvider.
+func ProviderNames() []string {
+	return []string{ProviderWttr}
+}
+
+// NewProvider creates the provider with the given name.
+// Returns error if the name is unknown.
+func NewProvider(name string, httpClient HTTPClient, logger *slog.Logger) (Provider, error) {
+	switch name {
+	case ProviderWttr:
+		provider, err := NewWttrProvider(httpClient, logger)
+		if err != nil {
+			return nil, err
+		}
+		return provider, nil
+	default:
+		return nil, fmt.Errorf("unknown weather provider: %s", name)
+	}
+}
+
+// Tool implements the weather forecast tool on top of a Provider.
 type Tool struct {
-	httpClient HTTPClient
-	logger     *slog.Logger
+	provider Provider
+	logger   *slog.Logger
 }
 
-// NewTool creates a new weather tool with the specified HTTP client and logger.
-func NewTool(httpClient HTTPClient, logger *slog.Logger) (*Tool, error) {
-	if httpClient == nil {
-		return nil, errors.New("httpClient cannot be nil")
+// NewTool creates a new weather tool with the specified provider and logger.
+func NewTool(provider Provider, logger *slog.Logger) (*Tool, error) {
+	if provider == nil {
+		return nil, errors.New("provider cannot be nil")
 	}
 	if logger == nil {
 		return nil, errors.New("logger cannot be nil")
 	}
 	return &Tool{
-		httpClient: httpClient,
-		logger:     logger,
+		provider: provider,
+		logger:   logger,
 	}, nil
 }
 
@@ -68,11 +120,18 @@ func (t *Tool) ResponseJsonSchema() []byte {
 	return responseSchema
 }
 
+// dateIndexMap maps the date parameter values to day offsets from today.
+var dateIndexMap = map[string]int{
+	"today":              0,
+	"tomorrow":           1,
+	"day_after_tomorrow": 2,
+}
+
 // Callback fetches weather data for the specified location.
 func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]any, error) {
 	location, ok := args["location"].(string)
 	if !ok {
-		return nil, errors.New("invalid location")
+		return nil, agent.NewUserError("invalid location")
 	}
 
 	dates := []string{"today"}
@@ -95,164 +154,139 @@ func (t *Tool) Callback(ctx context.Context, args map[string]any) (map[string]an
 		hourly = h
 	}
 
-	wttrResp, err := t.fetchWeather(ctx, location)
-	if err != nil {
-		return nil, err
+	days := 0
+	for _, dateKey := range dates {
+		if idx, ok := dateIndexMap[dateKey]; ok {
+			days = max(days, idx+1)
+		}
 	}
-
-	forecasts, err := t.buildForecasts(wttrResp, dates, detail, hourly)
-	if err != nil {
-		return nil, err
+	if days == 0 {
+		return nil, agent.NewUserError("no forecast data for requested dates")
 	}
 
-	return map[string]any{
-		"location":  location,
-		"forecasts": forecasts,
-	}, nil
-}
-
-func (t *Tool) fetchWeather(ctx context.Context, location string) (*wttrResponse, error) {
-	encodedLocation := url.PathEscape(location)
-	requestURL := fmt.Sprintf(wttrURL, encodedLocation)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	dayForecasts, err := t.provider.Forecast(ctx, location, days)
 	if err != nil {
-		t.logger.Error("failed to create request", slog.Any("error", err))
-		return nil, errors.New("failed to create request")
+		return nil, err
 	}
-
-	resp, err := t.httpClient.Do(req)
-	if err != nil {
-		t.logger.Error("API request failed", slog.Any("error", err), slog.String("location", location))
-		return nil, errors.New("API request failed")
+	if len(dayForecasts) == 0 {
+		return nil, agent.NewSystemError("no weather data available", nil)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		t.logger.Error("API returned error status", slog.Int("status", resp.StatusCode), slog.String("location", location))
-		return nil, errors.New("API returned error status")
+	// Today's forecast is overlaid with the observed conditions when they are available.
+	var current *Conditions
+	if slices.Contains(dates, "today") {
+		current, err = t.provider.Current(ctx, location)
+		if err != nil {
+			t.logger.WarnContext(ctx, "failed to get current conditions, using forecast only",
+				slog.String("location", location),
+				slog.Any("error", err),
+			)
+		}
 	}
 
-	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	forecasts, err := t.buildForecasts(dayForecasts, current, dates, detail, hourly)
 	if err != nil {
-		t.logger.Error("failed to read response", slog.Any("error", err))
-		return nil, errors.New("failed to read response")
-	}
-
-	var wttrResp wttrResponse
-	if err := json.Unmarshal(body, &wttrResp); err != nil {
-		t.logger.Error("failed to parse response", slog.Any("error", err))
-		return nil, errors.New("failed to parse response")
+		return nil, err
 	}
 
-	return &wttrResp, nil
+	return map[string]any{
+		"location":  location,
+		"forecasts": forecasts,
+	}, nil
 }
 
-func (t *Tool) buildForecasts(resp *wttrResponse, dates []string, detail string, hourly bool) ([]any, error) {
-	if len(resp.Weather) == 0 {
-		return nil, errors.New("no weather data available")
-	}
-
-	dateIndexMap := map[string]int{
-		"today":              0,
-		"tomorrow":           1,
-		"day_after_tomorrow": 2,
-	}
-
+func (t *Tool) buildForecasts(days []DayForecast, current *Conditions, dates []string, detail string, hourly bool) ([]any, error) {
 	forecasts := make([]any, 0, len(dates))
 	for _, dateKey := range dates {
 		idx, ok := dateIndexMap[dateKey]
-		if !ok || idx >= len(resp.Weather) {
+		if !ok || idx >= len(days) {
 			continue
 		}
 
-		weather := resp.Weather[idx]
-		forecast := t.buildForecast(resp, weather, idx, detail)
+		day := days[idx]
+		var cur *Conditions
+		if idx == 0 {
+			cur = current
+		}
+		forecast := t.buildForecast(day, cur, detail)
 
 		if hourly {
-			forecast["hourly"] = t.buildHourly(weather, detail)
+			forecast["hourly"] = t.buildHourly(day, detail)
 		}
 
 		forecasts = append(forecasts, forecast)
 	}
 
 	if len(forecasts) == 0 {
-		return nil, errors.New("no forecast data for requested dates")
+		return nil, agent.NewSystemError("no forecast data for requested dates", nil)
 	}
 
 	return forecasts, nil
 }
 
-func (t *Tool) buildForecast(resp *wttrResponse, weather wttrWeather, idx int, detail string) map[string]any {
+// buildForecast builds the forecast entry for a day. current, if non-nil, overrides the forecast values.
+func (t *Tool) buildForecast(day DayForecast, current *Conditions, detail string) map[string]any {
 	condition := "unknown"
-	if len(weather.Hourly) > 0 && len(weather.Hourly[0].WeatherDesc) > 0 {
-		condition = weather.Hourly[0].WeatherDesc[0].Value
+	if len(day.Hourly) > 0 && day.Hourly[0].Condition != "" {
+		condition = day.Hourly[0].Condition
 	}
 
-	tempC := weather.AvgTempC
-	if idx == 0 && len(resp.CurrentCondition) > 0 {
-		tempC = resp.CurrentCondition[0].TempC
-		if len(resp.CurrentCondition[0].WeatherDesc) > 0 {
-			condition = resp.CurrentCondition[0].WeatherDesc[0].Value
+	tempC := day.AvgTempC
+	if current != nil {
+		tempC = current.TempC
+		if current.Condition != "" {
+			condition = current.Condition
 		}
 	}
 
 	forecast := map[string]any{
-		"date":       weather.Date,
+		"date":       day.Date,
 		"temp_c":     tempC,
 		"condition":  condition,
-		"max_temp_c": weather.MaxTempC,
-		"min_temp_c": weather.MinTempC,
+		"max_temp_c": day.MaxTempC,
+		"min_temp_c": day.MinTempC,
 	}
 
-	if detail == "detailed" || detail == "full" {
-		if idx == 0 && len(resp.CurrentCondition) > 0 {
-			cur := resp.CurrentCondition[0]
-			forecast["humidity"] = cur.Humidity
-			forecast["wind_speed_kmph"] = cur.WindspeedKmph
-			forecast["wind_direction"] = cur.Winddir16Point
-			forecast["feels_like_c"] = cur.FeelsLikeC
-			if len(weather.Hourly) > 0 {
-				forecast["rain_chance"] = weather.Hourly[0].ChanceOfRain
-			}
-		} else if len(weather.Hourly) > 0 {
-			h := weather.Hourly[0]
-			forecast["humidity"] = h.Humidity
-			forecast["wind_speed_kmph"] = h.WindspeedKmph
-			forecast["wind_direction"] = h.Winddir16Point
-			forecast["feels_like_c"] = h.FeelsLikeC
-			forecast["rain_chance"] = h.ChanceOfRain
+	// Detail fields come from the current conditions, or else from the first hourly slot.
+	var source *Conditions
+	if current != nil {
+		source = current
+	} else if len(day.Hourly) > 0 {
+		source = &day.Hourly[0]
+	}
+
+	if (detail == "detailed" || detail == "full") && source != nil {
+		forecast["humidity"] = source.Humidity
+		forecast["wind_speed_kmph"] = source.WindSpeedKmph
+		forecast["wind_direction"] = source.WindDirection
+		forecast["feels_like_c"] = source.FeelsLikeC
+		if len(day.Hourly) > 0 {
+			forecast["rain_chance"] = day.Hourly[0].RainChance
 		}
 	}
 
 	if detail == "full" {
-		if idx == 0 && len(resp.CurrentCondition) > 0 {
-			cur := resp.CurrentCondition[0]
-			forecast["uv_index"] = cur.UVIndex
-			forecast["pressure"] = cur.Pressure
-			forecast["visibility"] = cur.Visibility
-			forecast["cloud_cover"] = cur.CloudCover
-		} else if len(weather.Hourly) > 0 {
-			h := weather.Hourly[0]
-			forecast["uv_index"] = h.UVIndex
-			forecast["pressure"] = h.Pressure
-			forecast["visibility"] = h.Visibility
-			forecast["cloud_cover"] = h.CloudCover
+		if source != nil {
+			forecast["uv_index"] = source.UVIndex
+			forecast["pressure"] = source.Pressure
+			forecast["visibility"] = source.Visibility
+			forecast["cloud_cover"] = source.CloudCover
 		}
-		if len(weather.Astronomy) > 0 {
-			forecast["sunrise"] = weather.Astronomy[0].Sunrise
-			forecast["sunset"] = weather.Astronomy[0].Sunset
+		if day.Sunrise != "" || day.Sunset != "" {
+			forecast["sunrise"] = day.Sunrise
+			forecast["sunset"] = day.Sunset
 		}
 	}
 
 	return forecast
 }
 
-func (t *Tool) buildHourly(weather wttrWeather, detail string) []any {
-	hourlyData := make([]any, 0, len(weather.Hourly))
-	for _, h := range weather.Hourly {
+func (t *Tool) buildHourly(day DayForecast, detail string) []any {
+	hourlyData := make([]any, 0, len(day.Hourly))
+	for _, h := range day.Hourly {
 		condition := "unknown"
-		if len(h.WeatherDesc) > 0 {
-			condition = h.WeatherDesc[0].Value
+		if h.Condition != "" {
+			condition = h.Condition
 		}
 
 		entry := map[string]any{
@@ -263,10 +297,10 @@ func (t *Tool) buildHourly(weather wttrWeather, detail string) []any {
 
 		if detail == "detailed" || detail == "full" {
 			entry["humidity"] = h.Humidity
-			entry["wind_speed_kmph"] = h.WindspeedKmph
-			entry["wind_direction"] = h.Winddir16Point
+			entry["wind_speed_kmph"] = h.WindSpeedKmph
+			entry["wind_direction"] = h.WindDirection
 			entry["feels_like_c"] = h.FeelsLikeC
-			entry["rain_chance"] = h.ChanceOfRain
+			entry["rain_chance"] = h.RainChance
 		}
 
 		if detail == "full" {
@@ -280,53 +314,3 @@ func (t *Tool) buildHourly(weather wttrWeather, detail string) []any {
 	}
 	return hourlyData
 }
-
-// wttrResponse represents the wttr.in API response structure.
-type wttrResponse struct {
-	CurrentCondition []wttrCurrentCondition `json:"current_condition"`
-	Weather          []wttrWeather          `json:"weather"`
-}
-
-type wttrCurrentCondition struct {
-	TempC          string `json:"temp_C"`
-	FeelsLikeC     string `json:"FeelsLikeC"`
-	Humidity       string `json:"humidity"`
-	WindspeedKmph  string `json:"windspeedKmph"`
-	Winddir16Point string `json:"winddir16Point"`
-	UVIndex        string `json:"uvIndex"`
-	Pressure       string `json:"pressure"`
-	Visibility     string `json:"visibility"`
-	CloudCover     string `json:"cloudcover"`
-	WeatherDesc    []struct {
-		Value string `json:"value"`
-	} `json:"weatherDesc"`
-}
-
-type wttrWeather struct {
-	Date      string `json:"date"`
-	MaxTempC  string `json:"maxtempC"`
-	MinTempC  string `json:"mintempC"`
-	AvgTempC  string `json:"avgtempC"`
-	Astronomy []struct {
-		Sunrise string `json:"sunrise"`
-		Sunset  string `json:"sunset"`
-	} `json:"astronomy"`
-	Hourly []wttrHourly `json:"hourly"`
-}
-
-type wttrHourly struct {
-	Time           string `json:"time"`
-	TempC          string `json:"tempC"`
-	FeelsLikeC     string `json:"FeelsLikeC"`
-	Humidity       string `json:"humidity"`
-	WindspeedKmph  string `json:"windspeedKmph"`
-	Winddir16Point string `json:"winddir16Point"`
-	ChanceOfRain   string `json:"chanceofrain"`
-	UVIndex        string `json:"uvIndex"`
-	Pressure       string `json:"pressure"`
-	Visibility     string `json:"visibility"`
-	CloudCover     string `json:"cloudcover"`
-	WeatherDesc    []struct {
-		Value string `json:"value"`
-	} `json:"weatherDesc"`
-}
@@ -16,7 +16,8 @@ import (
 )
 
 func TestTool_Integration_Callback_Tokyo(t *testing.T) {
-	tool, _ := weather.NewTool(&http.Client{Timeout: 30 * time.Second}, slog.Default())
+	provider, _ := weather.NewWttrProvider(&http.Client{Timeout: 30 * time.Second}, slog.Default())
+	tool, _ := weather.NewTool(provider, slog.Default())
 	ctx := context.Background()
 
 	result, err := tool.Callback(ctx, map[string]any{"location": "Tokyo"})
@@ -37,7 +38,8 @@ func TestTool_Integration_Callback_Tokyo(t *testing.T) {
 }
 
 func TestTool_Integration_Callback_MultipleDates(t *testing.T) {
-	tool, _ := weather.NewTool(&http.Client{Timeout: 30 * time.Second}, slog.Default())
+	provider, _ := weather.NewWttrProvider(&http.Client{Timeout: 30 * time.Second}, slog.Default())
+	tool, _ := weather.NewTool(provider, slog.Default())
 	ctx := context.Background()
 
 	result, err := tool.Callback(ctx, map[string]any{
@@ -56,7 +58,8 @@ func TestTool_Integration_Callback_MultipleDates(t *testing.T) {
 }
 
 func TestTool_Integration_Callback_DetailedWithHourly(t *testing.T) {
-	tool, _ := weather.NewTool(&http.Client{Timeout: 30 * time.Second}, slog.Default())
+	provider, _ := weather.NewWttrProvider(&http.Client{Timeout: 30 * time.Second}, slog.Default())
+	tool, _ := weather.NewTool(provider, slog.Default())
 	ctx := context.Background()
 
 	result, err := tool.Callback(ctx, map[string]any{
@@ -90,7 +93,8 @@ func TestTool_Integration_Callback_DetailedWithHourly(t *testing.T) {
 }
 
 func TestTool_Integration_Callback_LocationWithSpace(t *testing.T) {
-	tool, _ := weather.NewTool(&http.Client{Timeout: 30 * time.Second}, slog.Default())
+	provider, _ := weather.NewWttrProvider(&http.Client{Timeout: 30 * time.Second}, slog.Default())
+	tool, _ := weather.NewTool(provider, slog.Default())
 	ctx := context.Background()
 
 	result, err := tool.Callback(ctx, map[string]any{"location": "New York"})
@@ -110,7 +114,8 @@ func TestTool_Integration_Callback_LocationWithSpace(t *testing.T) {
 }
 
 func TestTool_Integration_Callback_Timeout(t *testing.T) {
-	tool, _ := weather.NewTool(&http.Client{Timeout: 1 * time.Nanosecond}, slog.Default())
+	provider, _ := weather.NewWttrProvider(&http.Client{Timeout: 1 * time.Nanosecond}, slog.Default())
+	tool, _ := weather.NewTool(provider, slog.Default())
 	ctx := context.Background()
 
 	_, err := tool.Callback(ctx, map[string]any{"location": "Tokyo"})
@@ -7,20 +7,41 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
+	"time"
 	"yuruppu/internal/toolset/weather"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// mockHTTPClient answers every request with the same status and body.
 type mockHTTPClient struct {
-	response *http.Response
-	err      error
+	status int
+	body   string
+	err    error
 }
 
 func (m *mockHTTPClient) Do(req *http.Request) (*http.Response, error) {
-	return m.response, m.err
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &http.Response{
+		StatusCode: m.status,
+		Body:       io.NopCloser(bytes.NewBufferString(m.body)),
+	}, nil
+}
+
+// newWttrTool creates a weather tool backed by the wttr.in provider using client.
+func newWttrTool(t *testing.T, client weather.HTTPClient) *weather.Tool {
+	t.Helper()
+	provider, err := weather.NewWttrProvider(client, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+	tool, err := weather.NewTool(provider, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+	return tool
 }
 
 func TestCallback(t *testing.T) {
@@ -159,19 +180,9 @@ func TestCallback(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			var client *mockHTTPClient
-			if tt.httpErr != nil {
-				client = &mockHTTPClient{err: tt.httpErr}
-			} else {
-				client = &mockHTTPClient{
-					response: &http.Response{
-						StatusCode: tt.responseStatus,
-						Body:       io.NopCloser(bytes.NewBufferString(tt.responseBody)),
-					},
-				}
-			}
+			client := &mockHTTPClient{status: tt.responseStatus, body: tt.responseBody, err: tt.httpErr}
 
-			tool, _ := weather.NewTool(client, slog.Default())
+			tool := newWttrTool(t, client)
 			result, err := tool.Callback(context.Background(), tt.args)
 
 			if tt.wantErr {
@@ -185,3 +196,198 @@ func TestCallback(t *testing.T) {
 		})
 	}
 }
+
+// redirectTransport sends every request to target, keeping the original path and query.
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (rt redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = rt.target.Scheme
+	req.URL.Host = rt.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestCallback_HonorsClientTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	target, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	client := &http.Client{
+		Transport: redirectTransport{target: target},
+		Timeout:   50 * time.Millisecond,
+	}
+	tool := newWttrTool(t, client)
+
+	start := time.Now()
+	result, err := tool.Callback(t.Context(), map[string]any{"location": "Tokyo"})
+
+	require.Error(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, "API request failed", err.Error())
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
+
+// =============================================================================
+// Provider Tests
+// =============================================================================
+
+// stubProvider is a Provider returning canned data and recording calls.
+type stubProvider struct {
+	current     *weather.Conditions
+	currentErr  error
+	forecast    []weather.DayForecast
+	forecastErr error
+
+	currentCalls  int
+	forecastCalls int
+	lastLocation  string
+	lastDays      int
+}
+
+func (s *stubProvider) Current(ctx context.Context, location string) (*weather.Conditions, error) {
+	s.currentCalls++
+	s.lastLocation = location
+	return s.current, s.currentErr
+}
+
+func (s *stubProvider) Forecast(ctx context.Context, location string, days int) ([]weather.DayForecast, error) {
+	s.forecastCalls++
+	s.lastLocation = location
+	s.lastDays = days
+	return s.forecast, s.forecastErr
+}
+
+func stubForecast() []weather.DayForecast {
+	return []weather.DayForecast{
+		{Date: "2026-01-02", MaxTempC: "18", MinTempC: "10", AvgTempC: "14", Sunrise: "06:50 AM", Sunset: "04:40 PM", Hourly: []weather.Conditions{
+			{Time: "0", TempC: "12", Condition: "Clear", Humidity: "60", RainChance: "10"},
+		}},
+		{Date: "2026-01-03", MaxTempC: "20", MinTempC: "12", AvgTempC: "16", Hourly: []weather.Conditions{
+			{Time: "0", TempC: "14", Condition: "Cloudy", Humidity: "70", RainChance: "40"},
+		}},
+	}
+}
+
+func TestTool_DelegatesToProvider(t *testing.T) {
+	t.Run("formats today from current conditions", func(t *testing.T) {
+		provider := &stubProvider{
+			current:  &weather.Conditions{TempC: "15", Condition: "Sunny", Humidity: "50"},
+			forecast: stubForecast(),
+		}
+		tool, err := weather.NewTool(provider, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(t.Context(), map[string]any{"location": "Osaka", "detail": "detailed"})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, provider.currentCalls)
+		assert.Equal(t, 1, provider.forecastCalls)
+		assert.Equal(t, "Osaka", provider.lastLocation)
+		assert.Equal(t, 1, provider.lastDays)
+		assert.Equal(t, "Osaka", result["location"])
+		forecasts := result["forecasts"].([]any)
+		require.Len(t, forecasts, 1)
+		assert.Equal(t, map[string]any{
+			"date":            "2026-01-02",
+			"temp_c":          "15",
+			"condition":       "Sunny",
+			"max_temp_c":      "18",
+			"min_temp_c":      "10",
+			"humidity":        "50",
+			"wind_speed_kmph": "",
+			"wind_direction":  "",
+			"feels_like_c":    "",
+			"rain_chance":     "10",
+		}, forecasts[0])
+	})
+
+	t.Run("skips current conditions when today is not requested", func(t *testing.T) {
+		provider := &stubProvider{forecast: stubForecast()}
+		tool, err := weather.NewTool(provider, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(t.Context(), map[string]any{"location": "Osaka", "date": []any{"tomorrow"}})
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, provider.currentCalls)
+		assert.Equal(t, 2, provider.lastDays)
+		forecasts := result["forecasts"].([]any)
+		require.Len(t, forecasts, 1)
+		f0 := forecasts[0].(map[string]any)
+		assert.Equal(t, "2026-01-03", f0["date"])
+		assert.Equal(t, "16", f0["temp_c"])
+		assert.Equal(t, "Cloudy", f0["condition"])
+	})
+
+	t.Run("falls back to forecast when current conditions fail", func(t *testing.T) {
+		provider := &stubProvider{currentErr: errors.New("unavailable"), forecast: stubForecast()}
+		tool, err := weather.NewTool(provider, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(t.Context(), map[string]any{"location": "Osaka", "detail": "full"})
+
+		require.NoError(t, err)
+		f0 := result["forecasts"].([]any)[0].(map[string]any)
+		assert.Equal(t, "14", f0["temp_c"])
+		assert.Equal(t, "Clear", f0["condition"])
+		assert.Equal(t, "60", f0["humidity"])
+		assert.Equal(t, "06:50 AM", f0["sunrise"])
+	})
+
+	t.Run("returns forecast errors as is", func(t *testing.T) {
+		provider := &stubProvider{forecastErr: errors.New("API request failed")}
+		tool, err := weather.NewTool(provider, slog.New(slog.DiscardHandler))
+		require.NoError(t, err)
+
+		result, err := tool.Callback(t.Context(), map[string]any{"location": "Osaka"})
+
+		require.EqualError(t, err, "API request failed")
+		assert.Nil(t, result)
+		assert.Equal(t, 0, provider.currentCalls)
+	})
+}
+
+func TestNewTool(t *testing.T) {
+	t.Run("returns error when provider is nil", func(t *testing.T) {
+		tool, err := weather.NewTool(nil, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "provider cannot be nil")
+	})
+
+	t.Run("returns error when logger is nil", func(t *testing.T) {
+		tool, err := weather.NewTool(&stubProvider{}, nil)
+
+		require.Error(t, err)
+		assert.Nil(t, tool)
+		assert.Contains(t, err.Error(), "logger cannot be nil")
+	})
+}
+
+func TestNewProvider(t *testing.T) {
+	t.Run("creates the wttr provider", func(t *testing.T) {
+		provider, err := weather.NewProvider(weather.ProviderWttr, &mockHTTPClient{}, slog.New(slog.DiscardHandler))
+
+		require.NoError(t, err)
+		assert.IsType(t, &weather.WttrProvider{}, provider)
+	})
+
+	t.Run("returns error for an unknown provider", func(t *testing.T) {
+		provider, err := weather.NewProvider("openweather", &mockHTTPClient{}, slog.New(slog.DiscardHandler))
+
+		require.Error(t, err)
+		assert.Nil(t, provider)
+		assert.Contains(t, err.Error(), "unknown weather provider")
+	})
+}
@@ -0,0 +1,207 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"yuruppu/internal/agent"
+)
+
+const (
+	wttrURL         = "https://wttr.in/%s?format=j1"
+	maxResponseSize = 1 << 20 // 1MB
+)
+
+// HTTPClient is an interface for HTTP requests.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// WttrProvider is a Provider backed by the wttr.in API.
+type WttrProvider struct {
+	httpClient HTTPClient
+	logger     *slog.Logger
+}
+
+// NewWttrProvider creates a wttr.in provider with the specified HTTP client and logger.
+func NewWttrProvider(httpClient HTTPClient, logger *slog.Logger) (*WttrProvider, error) {
+	if httpClient == nil {
+		return nil, errors.New("httpClient cannot be nil")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil")
+	}
+	return &WttrProvider{
+		httpClient: httpClient,
+		logger:     logger,
+	}, nil
+}
+
+// Current returns the observed conditions at location.
+func (p *WttrProvider) Current(ctx context.Context, location string) (*Conditions, error) {
+	resp, err := p.fetch(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.CurrentCondition) == 0 {
+		return nil, agent.NewSystemError("no current conditions available", nil)
+	}
+	cur := resp.CurrentCondition[0]
+	return &Conditions{
+		TempC:         cur.TempC,
+		FeelsLikeC:    cur.FeelsLikeC,
+		Condition:     firstDesc(cur.WeatherDesc),
+		Humidity:      cur.Humidity,
+		WindSpeedKmph: cur.WindspeedKmph,
+		WindDirection: cur.Winddir16Point,
+		UVIndex:       cur.UVIndex,
+		Pressure:      cur.Pressure,
+		Visibility:    cur.Visibility,
+		CloudCover:    cur.CloudCover,
+	}, nil
+}
+
+// Forecast returns up to days daily forecasts for location, starting today.
+// wttr.in provides at most 3 days.
+func (p *WttrProvider) Forecast(ctx context.Context, location string, days int) ([]DayForecast, error) {
+	resp, err := p.fetch(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Weather) == 0 {
+		return nil, agent.NewSystemError("no weather data available", nil)
+	}
+
+	weathers := resp.Weather[:min(days, len(resp.Weather))]
+	forecasts := make([]DayForecast, 0, len(weathers))
+	for _, w := range weathers {
+		day := DayForecast{
+			Date:     w.Date,
+			MaxTempC: w.MaxTempC,
+			MinTempC: w.MinTempC,
+			AvgTempC: w.AvgTempC,
+			Hourly:   make([]Conditions, 0, len(w.Hourly)),
+		}
+		if len(w.Astronomy) > 0 {
+			day.Sunrise = w.Astronomy[0].Sunrise
+			day.Sunset = w.Astronomy[0].Sunset
+		}
+		for _, h := range w.Hourly {
+			day.Hourly = append(day.Hourly, Conditions{
+				Time:          h.Time,
+				TempC:         h.TempC,
+				FeelsLikeC:    h.FeelsLikeC,
+				Condition:     firstDesc(h.WeatherDesc),
+				Humidity:      h.Humidity,
+				WindSpeedKmph: h.WindspeedKmph,
+				WindDirection: h.Winddir16Point,
+				RainChance:    h.ChanceOfRain,
+				UVIndex:       h.UVIndex,
+				Pressure:      h.Pressure,
+				Visibility:    h.Visibility,
+				CloudCover:    h.CloudCover,
+			})
+		}
+		forecasts = append(forecasts, day)
+	}
+	return forecasts, nil
+}
+
+func (p *WttrProvider) fetch(ctx context.Context, location string) (*wttrResponse, error) {
+	encodedLocation := url.PathEscape(location)
+	requestURL := fmt.Sprintf(wttrURL, encodedLocation)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		p.logger.Error("failed to create request", slog.Any("error", err))
+		return nil, agent.NewSystemError("failed to create request", err)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		p.logger.Error("API request failed", slog.Any("error", err), slog.String("location", location))
+		return nil, agent.NewSystemError("API request failed", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		p.logger.Error("API returned error status", slog.Int("status", resp.StatusCode), slog.String("location", location))
+		return nil, agent.NewSystemError("API returned error status", nil)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		p.logger.Error("failed to read response", slog.Any("error", err))
+		return nil, agent.NewSystemError("failed to read response", err)
+	}
+
+	var wttrResp wttrResponse
+	if err := json.Unmarshal(body, &wttrResp); err != nil {
+		p.logger.Error("failed to parse response", slog.Any("error", err))
+		return nil, agent.NewSystemError("failed to parse response", err)
+	}
+
+	return &wttrResp, nil
+}
+
+// firstDesc returns the first weather description, or empty if there is none.
+func firstDesc(descs []wttrDesc) string {
+	if len(descs) == 0 {
+		return ""
+	}
+	return descs[0].Value
+}
+
+// wttrResponse represents the wttr.in API response structure.
+type wttrResponse struct {
+	CurrentCondition []wttrCurrentCondition `json:"current_condition"`
+	Weather          []wttrWeather          `json:"weather"`
+}
+
+type wttrDesc struct {
+	Value string `json:"value"`
+}
+
+type wttrCurrentCondition struct {
+	TempC          string     `json:"temp_C"`
+	FeelsLikeC     string     `json:"FeelsLikeC"`
+	Humidity       string     `json:"humidity"`
+	WindspeedKmph  string     `json:"windspeedKmph"`
+	Winddir16Point string     `json:"winddir16Point"`
+	UVIndex        string     `json:"uvIndex"`
+	Pressure       string     `json:"pressure"`
+	Visibility     string     `json:"visibility"`
+	CloudCover     string     `json:"cloudcover"`
+	WeatherDesc    []wttrDesc `json:"weatherDesc"`
+}
+
+type wttrWeather struct {
+	Date      string `json:"date"`
+	MaxTempC  string `json:"maxtempC"`
+	MinTempC  string `json:"mintempC"`
+	AvgTempC  string `json:"avgtempC"`
+	Astronomy []struct {
+		Sunrise string `json:"sunrise"`
+		Sunset  string `json:"sunset"`
+	} `json:"astronomy"`
+	Hourly []wttrHourly `json:"hourly"`
+}
+
+type wttrHourly struct {
+	Time           string     `json:"time"`
+	TempC          string     `json:"tempC"`
+	FeelsLikeC     string     `json:"FeelsLikeC"`
+	Humidity       string     `json:"humidity"`
+	WindspeedKmph  string     `json:"windspeedKmph"`
+	Winddir16Point string     `json:"winddir16Point"`
+	ChanceOfRain   string     `json:"chanceofrain"`
+	UVIndex        string     `json:"uvIndex"`
+	Pressure       string     `json:"pressure"`
+	Visibility     string     `json:"visibility"`
+	CloudCover     string     `json:"cloudcover"`
+	WeatherDesc    []wttrDesc `json:"weatherDesc"`
+}
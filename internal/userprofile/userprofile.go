@@ -7,6 +7,9 @@ import (
 	"fmt"
 	"log/slog"
 	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
 )
 
 // Storage defines the storage interface required by user profile service.
@@ -21,28 +24,99 @@ type UserProfile struct {
 	PictureURL      string `json:"pictureUrl,omitempty"`
 	PictureMIMEType string `json:"pictureMimeType,omitempty"`
 	StatusMessage   string `json:"statusMessage,omitempty"`
+	Language        string `json:"language,omitempty"`
+	Timezone        string `json:"timezone,omitempty"`
+
+	Notifications NotificationPrefs `json:"notifications,omitzero"`
+}
+
+// NotificationPrefs controls direct messages the bot pushes to the user on its own initiative.
+type NotificationPrefs struct {
+	Disabled bool `json:"disabled,omitempty"`
+	// QuietHoursStart and QuietHoursEnd are hours (0-23) in the user's timezone during which
+	// notifications are suppressed. The range may wrap midnight; equal values mean no quiet hours.
+	QuietHoursStart int `json:"quietHoursStart,omitempty"`
+	QuietHoursEnd   int `json:"quietHoursEnd,omitempty"`
+}
+
+// defaultLocation is used for quiet hours when the user's timezone is unknown.
+var defaultLocation = time.FixedZone("Asia/Tokyo", 9*60*60)
+
+// AcceptsNotificationAt reports whether the user allows a notification at t,
+// considering whether notifications are disabled and the quiet hours in the user's timezone.
+func (p *UserProfile) AcceptsNotificationAt(t time.Time) bool {
+	prefs := p.Notifications
+	if prefs.Disabled {
+		return false
+	}
+	if prefs.QuietHoursStart == prefs.QuietHoursEnd {
+		return true
+	}
+
+	loc := defaultLocation
+	if p.Timezone != "" {
+		if l, err := time.LoadLocation(p.Timezone); err == nil {
+			loc = l
+		}
+	}
+	hour := t.In(loc).Hour()
+	if prefs.QuietHoursStart < prefs.QuietHoursEnd {
+		return hour < prefs.QuietHoursStart || hour >= prefs.QuietHoursEnd
+	}
+	return hour < prefs.QuietHoursStart && hour >= prefs.QuietHoursEnd
+}
+
+// ProfileFetcher fetches a user's display name and picture URL from LINE.
+type ProfileFetcher interface {
+	GetProfile(ctx context.Context, userID string) (string, string, error)
+}
+
+// Option configures optional Service behavior.
+type Option func(*Service)
+
+// WithBackfill makes the service fetch profiles missing from storage via fetcher.
+// Fetched profiles (and failed fetches) are cached for ttl and are not written to storage,
+// so users who have not onboarded still get a name without being treated as onboarded.
+func WithBackfill(fetcher ProfileFetcher, ttl time.Duration) Option {
+	return func(s *Service) {
+		s.fetcher = fetcher
+		s.backfillTTL = ttl
+	}
 }
 
 // Service provides user profile management with caching and persistence.
 type Service struct {
-	storage Storage
-	logger  *slog.Logger
+	storage     Storage
+	fetcher     ProfileFetcher
+	backfillTTL time.Duration
+	logger      *slog.Logger
+
+	cache      sync.Map // userID -> *UserProfile
+	backfilled sync.Map // userID -> backfillEntry
+}
 
-	cache sync.Map // userID -> *UserProfile
+// backfillEntry is a cached backfill result. profile is nil if the fetch failed.
+type backfillEntry struct {
+	profile   *UserProfile
+	expiresAt time.Time
 }
 
 // NewService creates a new user profile service.
-func NewService(storage Storage, logger *slog.Logger) (*Service, error) {
+func NewService(storage Storage, logger *slog.Logger, opts ...Option) (*Service, error) {
 	if storage == nil {
 		return nil, errors.New("storage cannot be nil")
 	}
 	if logger == nil {
 		return nil, errors.New("logger cannot be nil")
 	}
-	return &Service{
+	s := &Service{
 		storage: storage,
 		logger:  logger,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
 }
 
 // GetUserProfile retrieves user profile from cache or storage.
@@ -60,6 +134,9 @@ func (s *Service) GetUserProfile(ctx context.Context, userID string) (*UserProfi
 		return nil, fmt.Errorf("failed to read user profile: %w", err)
 	}
 	if data == nil {
+		if profile := s.backfill(ctx, userID); profile != nil {
+			return profile, nil
+		}
 		return nil, fmt.Errorf("user profile not found: %s", userID)
 	}
 
@@ -72,6 +149,97 @@ func (s *Service) GetUserProfile(ctx context.Context, userID string) (*UserProfi
 	return &profile, nil
 }
 
+// maxConcurrentReads bounds storage reads issued by GetUserProfiles.
+const maxConcurrentReads = 8
+
+// GetUserProfiles retrieves the profiles for userIDs in one call.
+// Duplicate IDs are read once, cached profiles are not re-read, and the remaining
+// reads run concurrently. Users without a stored or backfilled profile are absent from the result.
+func (s *Service) GetUserProfiles(ctx context.Context, userIDs []string) (map[string]*UserProfile, error) {
+	profiles := make(map[string]*UserProfile, len(userIDs))
+	seen := make(map[string]bool, len(userIDs))
+	var toRead []string
+	for _, userID := range userIDs {
+		if seen[userID] {
+			continue
+		}
+		seen[userID] = true
+		if cached, ok := s.cache.Load(userID); ok {
+			if profile, ok := cached.(*UserProfile); ok {
+				profiles[userID] = profile
+				continue
+			}
+			s.cache.Delete(userID)
+		}
+		toRead = append(toRead, userID)
+	}
+
+	read := make([]*UserProfile, len(toRead))
+	backfilled := make([]bool, len(toRead))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(maxConcurrentReads)
+	for i, userID := range toRead {
+		g.Go(func() error {
+			data, _, err := s.storage.Read(gctx, userID)
+			if err != nil {
+				return fmt.Errorf("failed to read user profile %s: %w", userID, err)
+			}
+			if data == nil {
+				read[i] = s.backfill(gctx, userID)
+				backfilled[i] = true
+				return nil
+			}
+			var profile UserProfile
+			if err := json.Unmarshal(data, &profile); err != nil {
+				return fmt.Errorf("failed to unmarshal user profile %s: %w", userID, err)
+			}
+			read[i] = &profile
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	for i, profile := range read {
+		if profile == nil {
+			continue
+		}
+		if !backfilled[i] {
+			s.cache.Store(toRead[i], profile)
+		}
+		profiles[toRead[i]] = profile
+	}
+	return profiles, nil
+}
+
+// backfill returns the profile fetched from LINE for a user without a stored profile.
+// Returns nil if backfill is disabled or the fetch fails.
+func (s *Service) backfill(ctx context.Context, userID string) *UserProfile {
+	if s.fetcher == nil {
+		return nil
+	}
+	now := time.Now()
+	if cached, ok := s.backfilled.Load(userID); ok {
+		if entry, ok := cached.(backfillEntry); ok && now.Before(entry.expiresAt) {
+			return entry.profile
+		}
+	}
+
+	var profile *UserProfile
+	displayName, pictureURL, err := s.fetcher.GetProfile(ctx, userID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to backfill user profile",
+			slog.String("userID", userID),
+			slog.Any("error", err),
+		)
+	} else {
+		profile = &UserProfile{DisplayName: displayName, PictureURL: pictureURL}
+	}
+	s.backfilled.Store(userID, backfillEntry{profile: profile, expiresAt: now.Add(s.backfillTTL)})
+	return profile
+}
+
 // SetUserProfile stores user profile to cache and storage.
 func (s *Service) SetUserProfile(ctx context.Context, userID string, profile *UserProfile) error {
 	if profile == nil {
@@ -92,3 +260,41 @@ func (s *Service) SetUserProfile(ctx context.Context, userID string, profile *Us
 	s.cache.Store(userID, profile)
 	return nil
 }
+
+// UpdateUserProfile applies update to the stored profile and writes it back.
+// The write is conditioned on the generation that was read (optimistic locking).
+// Returns error if the profile does not exist or was modified concurrently.
+func (s *Service) UpdateUserProfile(ctx context.Context, userID string, update func(*UserProfile)) error {
+	if update == nil {
+		return errors.New("update cannot be nil")
+	}
+
+	data, generation, err := s.storage.Read(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to read user profile: %w", err)
+	}
+	if data == nil {
+		return fmt.Errorf("user profile not found: %s", userID)
+	}
+
+	var profile UserProfile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return fmt.Errorf("failed to unmarshal user profile: %w", err)
+	}
+
+	update(&profile)
+
+	data, err = json.Marshal(&profile)
+	if err != nil {
+		return fmt.Errorf("failed to marshal user profile: %w", err)
+	}
+
+	_, err = s.storage.Write(ctx, userID, "application/json", data, generation)
+	if err != nil {
+		return fmt.Errorf("failed to write user profile: %w", err)
+	}
+
+	// Update cache only after successful storage write
+	s.cache.Store(userID, &profile)
+	return nil
+}
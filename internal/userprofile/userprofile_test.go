@@ -5,8 +5,13 @@ import (
 	"encoding/json"
 	"errors"
 	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
+	"yuruppu/internal/line"
+	lineclient "yuruppu/internal/line/client"
 	"yuruppu/internal/userprofile"
 
 	"github.com/stretchr/testify/assert"
@@ -160,11 +165,340 @@ func TestService_SetUserProfile(t *testing.T) {
 	})
 }
 
+// =============================================================================
+// UpdateUserProfile Tests
+// =============================================================================
+
+func TestService_UpdateUserProfile(t *testing.T) {
+	t.Run("updates stored profile with generation precondition", func(t *testing.T) {
+		store := newMockStorage()
+		store.data["user-123"] = []byte(`{"displayName":"Alice","statusMessage":"Hello!"}`)
+		svc, _ := userprofile.NewService(store, slog.New(slog.DiscardHandler))
+
+		err := svc.UpdateUserProfile(t.Context(), "user-123", func(p *userprofile.UserProfile) {
+			p.DisplayName = "Alicia"
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), store.lastWriteGen)
+		var stored userprofile.UserProfile
+		require.NoError(t, json.Unmarshal(store.lastWriteData, &stored))
+		assert.Equal(t, "Alicia", stored.DisplayName)
+		assert.Equal(t, "Hello!", stored.StatusMessage)
+	})
+
+	t.Run("updates cache after write", func(t *testing.T) {
+		store := newMockStorage()
+		store.data["user-123"] = []byte(`{"displayName":"Alice"}`)
+		svc, _ := userprofile.NewService(store, slog.New(slog.DiscardHandler))
+		_, err := svc.GetUserProfile(t.Context(), "user-123")
+		require.NoError(t, err)
+
+		err = svc.UpdateUserProfile(t.Context(), "user-123", func(p *userprofile.UserProfile) {
+			p.DisplayName = "Alicia"
+		})
+		require.NoError(t, err)
+
+		got, err := svc.GetUserProfile(t.Context(), "user-123")
+		require.NoError(t, err)
+		assert.Equal(t, "Alicia", got.DisplayName)
+	})
+
+	t.Run("returns error when profile does not exist", func(t *testing.T) {
+		store := newMockStorage()
+		svc, _ := userprofile.NewService(store, slog.New(slog.DiscardHandler))
+
+		err := svc.UpdateUserProfile(t.Context(), "user-123", func(p *userprofile.UserProfile) {})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "user profile not found")
+		assert.Equal(t, 0, store.writeCallCount)
+	})
+
+	t.Run("does not update cache when storage write fails", func(t *testing.T) {
+		store := newMockStorage()
+		store.data["user-123"] = []byte(`{"displayName":"Alice"}`)
+		svc, _ := userprofile.NewService(store, slog.New(slog.DiscardHandler))
+		_, err := svc.GetUserProfile(t.Context(), "user-123")
+		require.NoError(t, err)
+		store.writeErr = errors.New("generation mismatch")
+
+		err = svc.UpdateUserProfile(t.Context(), "user-123", func(p *userprofile.UserProfile) {
+			p.DisplayName = "Alicia"
+		})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to write user profile")
+		got, err := svc.GetUserProfile(t.Context(), "user-123")
+		require.NoError(t, err)
+		assert.Equal(t, "Alice", got.DisplayName)
+	})
+}
+
+// =============================================================================
+// GetUserProfiles Tests
+// =============================================================================
+
+func TestService_GetUserProfiles(t *testing.T) {
+	storeProfile := func(t *testing.T, store *mockStorage, userID, name string) {
+		t.Helper()
+		data, err := json.Marshal(&userprofile.UserProfile{DisplayName: name})
+		require.NoError(t, err)
+		store.data[userID] = data
+	}
+
+	t.Run("returns profiles keyed by user ID", func(t *testing.T) {
+		store := newMockStorage()
+		storeProfile(t, store, "user-1", "Alice")
+		storeProfile(t, store, "user-2", "Bob")
+		svc, _ := userprofile.NewService(store, slog.New(slog.DiscardHandler))
+
+		got, err := svc.GetUserProfiles(t.Context(), []string{"user-1", "user-2"})
+
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		assert.Equal(t, "Alice", got["user-1"].DisplayName)
+		assert.Equal(t, "Bob", got["user-2"].DisplayName)
+	})
+
+	t.Run("reads duplicate IDs once", func(t *testing.T) {
+		store := newMockStorage()
+		storeProfile(t, store, "user-1", "Alice")
+		svc, _ := userprofile.NewService(store, slog.New(slog.DiscardHandler))
+
+		got, err := svc.GetUserProfiles(t.Context(), []string{"user-1", "user-1", "user-1"})
+
+		require.NoError(t, err)
+		assert.Len(t, got, 1)
+		assert.Equal(t, 1, store.readCallCount)
+	})
+
+	t.Run("omits missing profiles without error", func(t *testing.T) {
+		store := newMockStorage()
+		storeProfile(t, store, "user-1", "Alice")
+		svc, _ := userprofile.NewService(store, slog.New(slog.DiscardHandler))
+
+		got, err := svc.GetUserProfiles(t.Context(), []string{"user-1", "user-404"})
+
+		require.NoError(t, err)
+		assert.Len(t, got, 1)
+		assert.NotContains(t, got, "user-404")
+	})
+
+	t.Run("serves cached profiles without reading storage", func(t *testing.T) {
+		store := newMockStorage()
+		svc, _ := userprofile.NewService(store, slog.New(slog.DiscardHandler))
+		require.NoError(t, svc.SetUserProfile(t.Context(), "user-1", &userprofile.UserProfile{DisplayName: "Alice"}))
+		store.readCallCount = 0
+
+		got, err := svc.GetUserProfiles(t.Context(), []string{"user-1"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Alice", got["user-1"].DisplayName)
+		assert.Equal(t, 0, store.readCallCount)
+	})
+
+	t.Run("returns error when storage read fails", func(t *testing.T) {
+		store := newMockStorage()
+		store.readErr = errors.New("storage error")
+		svc, _ := userprofile.NewService(store, slog.New(slog.DiscardHandler))
+
+		got, err := svc.GetUserProfiles(t.Context(), []string{"user-1"})
+
+		require.Error(t, err)
+		assert.Nil(t, got)
+		assert.Contains(t, err.Error(), "failed to read user profile user-1")
+	})
+
+	t.Run("empty input returns empty map", func(t *testing.T) {
+		svc, _ := userprofile.NewService(newMockStorage(), slog.New(slog.DiscardHandler))
+
+		got, err := svc.GetUserProfiles(t.Context(), nil)
+
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+}
+
+// =============================================================================
+// Backfill Tests
+// =============================================================================
+
+func TestService_Backfill(t *testing.T) {
+	t.Run("GetUserProfile backfills a missing profile", func(t *testing.T) {
+		fetcher := &mockFetcher{names: map[string]string{"user-1": "Alice"}}
+		svc, _ := userprofile.NewService(newMockStorage(), slog.New(slog.DiscardHandler), userprofile.WithBackfill(fetcher, time.Minute))
+
+		got, err := svc.GetUserProfile(t.Context(), "user-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, "Alice", got.DisplayName)
+		assert.Equal(t, "https://example.com/user-1.png", got.PictureURL)
+	})
+
+	t.Run("does not write backfilled profiles to storage", func(t *testing.T) {
+		store := newMockStorage()
+		fetcher := &mockFetcher{names: map[string]string{"user-1": "Alice"}}
+		svc, _ := userprofile.NewService(store, slog.New(slog.DiscardHandler), userprofile.WithBackfill(fetcher, time.Minute))
+
+		_, err := svc.GetUserProfile(t.Context(), "user-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, store.writeCallCount)
+	})
+
+	t.Run("stored profiles take precedence over backfill", func(t *testing.T) {
+		store := newMockStorage()
+		fetcher := &mockFetcher{names: map[string]string{"user-1": "From LINE"}}
+		svc, _ := userprofile.NewService(store, slog.New(slog.DiscardHandler), userprofile.WithBackfill(fetcher, time.Minute))
+		require.NoError(t, svc.SetUserProfile(t.Context(), "user-1", &userprofile.UserProfile{DisplayName: "Stored"}))
+
+		got, err := svc.GetUserProfile(t.Context(), "user-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, "Stored", got.DisplayName)
+		assert.Equal(t, 0, fetcher.count())
+	})
+
+	t.Run("caches backfill results within the TTL", func(t *testing.T) {
+		fetcher := &mockFetcher{names: map[string]string{"user-1": "Alice"}}
+		svc, _ := userprofile.NewService(newMockStorage(), slog.New(slog.DiscardHandler), userprofile.WithBackfill(fetcher, time.Minute))
+
+		_, err1 := svc.GetUserProfile(t.Context(), "user-1")
+		_, err2 := svc.GetUserProfile(t.Context(), "user-1")
+
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		assert.Equal(t, 1, fetcher.count())
+	})
+
+	t.Run("refetches after the TTL expires", func(t *testing.T) {
+		fetcher := &mockFetcher{names: map[string]string{"user-1": "Alice"}}
+		svc, _ := userprofile.NewService(newMockStorage(), slog.New(slog.DiscardHandler), userprofile.WithBackfill(fetcher, time.Nanosecond))
+
+		_, err := svc.GetUserProfile(t.Context(), "user-1")
+		require.NoError(t, err)
+		time.Sleep(time.Millisecond)
+		_, err = svc.GetUserProfile(t.Context(), "user-1")
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, fetcher.count())
+	})
+
+	t.Run("caches failed fetches and reports not found", func(t *testing.T) {
+		fetcher := &mockFetcher{}
+		svc, _ := userprofile.NewService(newMockStorage(), slog.New(slog.DiscardHandler), userprofile.WithBackfill(fetcher, time.Minute))
+
+		_, err1 := svc.GetUserProfile(t.Context(), "user-404")
+		_, err2 := svc.GetUserProfile(t.Context(), "user-404")
+
+		require.Error(t, err1)
+		require.Error(t, err2)
+		assert.Contains(t, err1.Error(), "user profile not found")
+		assert.Equal(t, 1, fetcher.count())
+	})
+
+	t.Run("GetUserProfiles backfills only missing profiles", func(t *testing.T) {
+		store := newMockStorage()
+		data, err := json.Marshal(&userprofile.UserProfile{DisplayName: "Stored"})
+		require.NoError(t, err)
+		store.data["user-1"] = data
+		fetcher := &mockFetcher{names: map[string]string{"user-2": "Bob"}}
+		svc, _ := userprofile.NewService(store, slog.New(slog.DiscardHandler), userprofile.WithBackfill(fetcher, time.Minute))
+
+		got, err := svc.GetUserProfiles(t.Context(), []string{"user-1", "user-2", "user-404"})
+
+		require.NoError(t, err)
+		assert.Len(t, got, 2)
+		assert.Equal(t, "Stored", got["user-1"].DisplayName)
+		assert.Equal(t, "Bob", got["user-2"].DisplayName)
+		assert.Equal(t, 2, fetcher.count())
+	})
+
+	t.Run("backfills from a LINE endpoint", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if r.URL.Path != "/v2/bot/group/group-1/member/user-1" {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"userId":"user-1","displayName":"Alice","pictureUrl":"https://example.com/a.png"}`))
+		}))
+		t.Cleanup(srv.Close)
+		lineClient, err := lineclient.NewClient("test-token", slog.New(slog.DiscardHandler), lineclient.WithAPIEndpoint(srv.URL))
+		require.NoError(t, err)
+		svc, _ := userprofile.NewService(newMockStorage(), slog.New(slog.DiscardHandler), userprofile.WithBackfill(lineClient, time.Minute))
+		ctx := line.WithChatType(t.Context(), line.ChatTypeGroup)
+		ctx = line.WithSourceID(ctx, "group-1")
+
+		got, err := svc.GetUserProfiles(ctx, []string{"user-1"})
+
+		require.NoError(t, err)
+		require.Contains(t, got, "user-1")
+		assert.Equal(t, "Alice", got["user-1"].DisplayName)
+		assert.Equal(t, "https://example.com/a.png", got["user-1"].PictureURL)
+	})
+}
+
+// =============================================================================
+// Notification Preference Tests
+// =============================================================================
+
+func TestUserProfile_AcceptsNotificationAt(t *testing.T) {
+	// 2026-02-01 13:00 UTC is 22:00 in Asia/Tokyo
+	at := time.Date(2026, 2, 1, 13, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		profile userprofile.UserProfile
+		want    bool
+	}{
+		{name: "accepts by default", profile: userprofile.UserProfile{}, want: true},
+		{name: "rejects when disabled", profile: userprofile.UserProfile{Notifications: userprofile.NotificationPrefs{Disabled: true}}, want: false},
+		{name: "rejects inside wrapping quiet hours", profile: userprofile.UserProfile{Notifications: userprofile.NotificationPrefs{QuietHoursStart: 21, QuietHoursEnd: 7}}, want: false},
+		{name: "accepts outside wrapping quiet hours", profile: userprofile.UserProfile{Notifications: userprofile.NotificationPrefs{QuietHoursStart: 23, QuietHoursEnd: 7}}, want: true},
+		{name: "rejects inside daytime quiet hours", profile: userprofile.UserProfile{Notifications: userprofile.NotificationPrefs{QuietHoursStart: 20, QuietHoursEnd: 23}}, want: false},
+		{name: "accepts at end of quiet hours", profile: userprofile.UserProfile{Notifications: userprofile.NotificationPrefs{QuietHoursStart: 18, QuietHoursEnd: 22}}, want: true},
+		{name: "uses the user's timezone", profile: userprofile.UserProfile{Timezone: "UTC", Notifications: userprofile.NotificationPrefs{QuietHoursStart: 21, QuietHoursEnd: 7}}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.profile.AcceptsNotificationAt(at))
+		})
+	}
+}
+
 // =============================================================================
 // Mocks
 // =============================================================================
 
+type mockFetcher struct {
+	mu         sync.Mutex
+	names      map[string]string
+	fetchCount int
+}
+
+func (m *mockFetcher) GetProfile(ctx context.Context, userID string) (string, string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.fetchCount++
+	name, ok := m.names[userID]
+	if !ok {
+		return "", "", errors.New("not found")
+	}
+	return name, "https://example.com/" + userID + ".png", nil
+}
+
+func (m *mockFetcher) count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.fetchCount
+}
+
 type mockStorage struct {
+	mu                sync.Mutex
 	data              map[string][]byte
 	readErr           error
 	writeErr          error
@@ -173,6 +507,7 @@ type mockStorage struct {
 	lastWriteKey      string
 	lastWriteMIMEType string
 	lastWriteData     []byte
+	lastWriteGen      int64
 }
 
 func newMockStorage() *mockStorage {
@@ -182,6 +517,8 @@ func newMockStorage() *mockStorage {
 }
 
 func (m *mockStorage) Read(ctx context.Context, key string) ([]byte, int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.readCallCount++
 	if m.readErr != nil {
 		return nil, 0, m.readErr
@@ -194,10 +531,13 @@ func (m *mockStorage) Read(ctx context.Context, key string) ([]byte, int64, erro
 }
 
 func (m *mockStorage) Write(ctx context.Context, key, mimeType string, data []byte, expectedGen int64) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.writeCallCount++
 	m.lastWriteKey = key
 	m.lastWriteMIMEType = mimeType
 	m.lastWriteData = data
+	m.lastWriteGen = expectedGen
 	if m.writeErr != nil {
 		return 0, m.writeErr
 	}
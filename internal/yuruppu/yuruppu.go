@@ -2,14 +2,68 @@
 package yuruppu
 
 import (
+	"bytes"
 	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
 	"yuruppu/internal/bot"
 )
 
+// DefaultBotName is the character name used when no name is configured.
+const DefaultBotName = "ゆるっぷくん"
+
 //go:embed character.txt
-var CharacterPrompt string
+var characterTemplateText string
+var characterTemplate = template.Must(template.New("character").Option("missingkey=error").Parse(characterTemplateText))
+
+// EventCardTemplate is the Flex Message template for event lists in Yuruppu's look.
+// Edit eventcard.json.tmpl to tune the layout; card.WithTemplate documents the data it receives.
+//
+//go:embed eventcard.json.tmpl
+var EventCardTemplate string
+
+// PromptVars holds the deployment-specific values rendered into the character prompt.
+type PromptVars struct {
+	BotName       string   // character name (required)
+	PersonaTraits []string // extra personality traits appended to the character description
+}
+
+// GetSystemPrompt renders the character prompt with vars and injects it into the system prompt.
+func GetSystemPrompt(vars PromptVars) (string, error) {
+	characterPrompt, err := renderCharacterPrompt(vars)
+	if err != nil {
+		return "", err
+	}
+	return bot.BuildSystemPrompt(characterPrompt)
+}
+
+// renderCharacterPrompt validates vars and executes the character template.
+func renderCharacterPrompt(vars PromptVars) (string, error) {
+	botName := strings.TrimSpace(vars.BotName)
+	if botName == "" {
+		return "", errors.New("prompt variable BotName is required")
+	}
+
+	traits := make([]string, 0, len(vars.PersonaTraits))
+	for _, trait := range vars.PersonaTraits {
+		if trait = strings.TrimSpace(trait); trait != "" {
+			traits = append(traits, trait)
+		}
+	}
+
+	data := struct {
+		BotName       string
+		PersonaTraits []string
+	}{
+		BotName:       botName,
+		PersonaTraits: traits,
+	}
 
-// GetSystemPrompt returns the system prompt with the character prompt injected.
-func GetSystemPrompt() (string, error) {
-	return bot.BuildSystemPrompt(CharacterPrompt)
+	var buf bytes.Buffer
+	if err := characterTemplate.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("execute character prompt template: %w", err)
+	}
+	return buf.String(), nil
 }
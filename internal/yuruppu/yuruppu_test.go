@@ -0,0 +1,102 @@
+package yuruppu_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"yuruppu/internal/event"
+	"yuruppu/internal/toolset/event/card"
+	"yuruppu/internal/userprofile"
+	"yuruppu/internal/yuruppu"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// GetSystemPrompt Tests
+// =============================================================================
+
+func TestGetSystemPrompt(t *testing.T) {
+	t.Run("substitutes variables", func(t *testing.T) {
+		prompt, err := yuruppu.GetSystemPrompt(yuruppu.PromptVars{
+			BotName:       "テストくん",
+			PersonaTraits: []string{"甘いものが大好き", "  ", "歌が得意"},
+		})
+
+		require.NoError(t, err)
+		assert.Contains(t, prompt, "- 名前：テストくん")
+		assert.Contains(t, prompt, "- 甘いものが大好き\n- 歌が得意\n")
+		// The date comes from each turn's context, so the cached prompt cannot go stale
+		assert.NotContains(t, prompt, "今日の日付")
+		assert.NotContains(t, prompt, "{{")
+	})
+
+	t.Run("renders without persona traits", func(t *testing.T) {
+		prompt, err := yuruppu.GetSystemPrompt(yuruppu.PromptVars{
+			BotName: yuruppu.DefaultBotName,
+		})
+
+		require.NoError(t, err)
+		assert.Contains(t, prompt, "- 名前：ゆるっぷくん")
+		assert.Contains(t, prompt, "- たまに眠そうな雰囲気を出す\n\n")
+	})
+
+	t.Run("returns error when BotName is missing", func(t *testing.T) {
+		prompt, err := yuruppu.GetSystemPrompt(yuruppu.PromptVars{})
+
+		require.Error(t, err)
+		assert.Empty(t, prompt)
+		assert.Contains(t, err.Error(), "BotName is required")
+	})
+}
+
+// =============================================================================
+// EventCardTemplate Tests
+// =============================================================================
+
+func TestEventCardTemplate(t *testing.T) {
+	t.Run("is accepted by the card renderer without falling back", func(t *testing.T) {
+		var logs bytes.Buffer
+		r, err := card.NewRenderer(noProfiles{}, slog.New(slog.NewTextHandler(&logs, nil)), card.WithTemplate(yuruppu.EventCardTemplate))
+		require.NoError(t, err)
+
+		for _, events := range [][]*event.Event{nil, {{Title: "Picnic", Capacity: 5}, {Title: "Hike", ImageURL: "https://example.com/hike.jpg"}}} {
+			_, err := r.Render(context.Background(), events)
+			require.NoError(t, err)
+		}
+		assert.Empty(t, logs.String())
+	})
+
+	t.Run("renders the cover image as a hero", func(t *testing.T) {
+		r, err := card.NewRenderer(noProfiles{}, slog.New(slog.DiscardHandler), card.WithTemplate(yuruppu.EventCardTemplate))
+		require.NoError(t, err)
+
+		flexJSON, err := r.Render(context.Background(), []*event.Event{{Title: "Hike", ImageURL: "https://example.com/hike.jpg"}})
+
+		require.NoError(t, err)
+		assert.Contains(t, string(flexJSON), `"hero"`)
+		assert.Contains(t, string(flexJSON), `"url": "https://example.com/hike.jpg"`)
+	})
+
+	t.Run("renders a maps button only for events with a location", func(t *testing.T) {
+		var logs bytes.Buffer
+		r, err := card.NewRenderer(noProfiles{}, slog.New(slog.NewTextHandler(&logs, nil)), card.WithTemplate(yuruppu.EventCardTemplate))
+		require.NoError(t, err)
+
+		flexJSON, err := r.Render(context.Background(), []*event.Event{{Title: "Picnic"}, {Title: "Hike", LocationName: "Mt. Takao & Café"}})
+
+		require.NoError(t, err)
+		assert.Empty(t, logs.String())
+		assert.Equal(t, 1, strings.Count(string(flexJSON), `"footer"`))
+		assert.Contains(t, string(flexJSON), `"uri": "https://www.google.com/maps/search/?api=1&query=Mt.+Takao+%26+Caf%C3%A9"`)
+	})
+}
+
+type noProfiles struct{}
+
+func (noProfiles) GetUserProfiles(ctx context.Context, userIDs []string) (map[string]*userprofile.UserProfile, error) {
+	return map[string]*userprofile.UserProfile{}, nil
+}
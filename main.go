@@ -2,12 +2,18 @@ package main
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"log/slog"
+	"maps"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
 	"strconv"
 	"strings"
 	"syscall"
@@ -19,10 +25,19 @@ import (
 	lineclient "yuruppu/internal/line/client"
 	lineserver "yuruppu/internal/line/server"
 	"yuruppu/internal/media"
+	"yuruppu/internal/reminder"
 	"yuruppu/internal/storage"
+	"yuruppu/internal/toolset/displayname"
 	"yuruppu/internal/toolset/event"
+	"yuruppu/internal/toolset/event/card"
+	"yuruppu/internal/toolset/event/suggest"
+	"yuruppu/internal/toolset/grouplanguage"
+	"yuruppu/internal/toolset/groupreplymode"
+	"yuruppu/internal/toolset/grouptimezone"
+	"yuruppu/internal/toolset/remind"
 	"yuruppu/internal/toolset/reply"
 	"yuruppu/internal/toolset/skip"
+	"yuruppu/internal/toolset/snooze"
 	"yuruppu/internal/toolset/weather"
 	"yuruppu/internal/userprofile"
 	"yuruppu/internal/yuruppu"
@@ -40,16 +55,51 @@ type Config struct {
 	Port                          string     // Server port (default: 8080)
 	ChannelSecret                 string
 	ChannelAccessToken            string
-	GCPProjectID                  string // Optional: auto-detected on Cloud Run
-	GCPRegion                     string // Optional: auto-detected on Cloud Run
-	LLMModel                      string // Required: LLM model name
-	LLMCacheTTLMinutes            int    // LLM cache TTL in minutes (default: 60)
-	LLMTimeoutSeconds             int    // LLM API timeout in seconds (default: 30)
-	BucketName                    string // GCS bucket for storage
-	TypingIndicatorDelaySeconds   int    // Delay before showing typing indicator (default: 3)
-	TypingIndicatorTimeoutSeconds int    // Typing indicator display duration (default: 30, range: 5-60)
-	EventListMaxPeriodDays        int    // Max period in days for list_events
-	EventListLimit                int    // Max items for list_events (default: 5)
+	GCPProjectID                  string            // Optional: auto-detected on Cloud Run
+	GCPRegion                     string            // Optional: auto-detected on Cloud Run
+	LLMModel                      string            // Required: LLM model name
+	LLMCacheTTLMinutes            int               // LLM cache TTL in minutes (default: 60)
+	LLMTimeoutSeconds             int               // LLM API timeout in seconds (default: 30)
+	LLMBreakerThreshold           int               // Consecutive LLM failures that open the circuit breaker (default: 5, 0 disables)
+	LLMBreakerCooldownSeconds     int               // How long the open breaker fails fast before a trial request (default: 30)
+	LLMMaxSystemPromptLength      int               // Maximum system prompt length in characters (default: 32000)
+	LLMLabels                     map[string]string // GCP labels attached to every LLM request for billing breakdown (default: none)
+	BucketName                    string            // GCS bucket for storage
+	TypingIndicatorDelaySeconds   int               // Delay before showing typing indicator (default: 3)
+	TypingIndicatorTimeoutSeconds int               // Typing indicator display duration (default: 30, range: 5-60)
+	EventListMaxPeriodDays        int               // Max period in days for list_events
+	EventListLimit                int               // Max items for list_events (default: 5)
+	EventListDefaultStartOffset   int               // Days from today where list_events starts without filters (default: 0, today)
+	EventListDefaultSpanDays      int               // Days list_events covers without filters (default: 0, open-ended)
+	EventCarouselSize             int               // Max event cards in one carousel (default: 12, LINE's limit)
+	EventDefaultCapacity          int               // Capacity for create_event when omitted (default: 0, unlimited)
+	EventDefaultFee               string            // Fee for create_event when omitted (default: empty)
+	EventMaxUpcomingPerCreator    int               // Max upcoming events one user can have across all groups (default: 0, unlimited)
+	EventMinLeadMinutes           int               // Minutes ahead of now a new event must start (default: 0, only in the future)
+	EventMaxTitleLength           int               // Max event title length in characters (default: 200)
+	EventMaxDescriptionLength     int               // Max event description length in characters (default: 2000)
+	EventRetentionDays            int               // Days an ended event is kept before it is removed (default: 0, kept forever)
+	MaxConcurrentHandlers         int               // Max handler invocations running at once (default: 10)
+	OutboundTimeoutSeconds        int               // Request timeout for tools calling external APIs (default: 10)
+	OutboundMaxIdleConns          int               // Max idle connections kept for external APIs (default: 100)
+	OutboundMaxIdleConnsPerHost   int               // Max idle connections kept per external host (default: 10)
+	ReminderIntervalSeconds       int               // How often due reminders are dispatched (default: 60)
+	ShutdownTimeoutSeconds        int               // How long shutdown waits for in-flight turns and background jobs to drain (default: 30)
+	BotName                       string            // Character name rendered into the system prompt (default: ゆるっぷくん)
+	PersonaTraits                 []string          // Extra personality traits for the system prompt (default: none)
+	StorageEncryptionKey          []byte            // AES key for history and profile storage (default: none, stored unencrypted)
+	HistoryKeying                 history.Keying    // Whether group history is shared or per user (default: shared)
+	DebugLLM                      bool              // Log full LLM prompts and responses at DEBUG level; may contain PII (default: false)
+	DisableSignatureCheck         bool              // Accept unsigned webhooks for local development; never enable in production (default: false)
+	MaxToolCallsPerTurn           int               // Max tool invocations per conversation turn (default: 0, unlimited)
+	MaxHistoryTurns               int               // Most recent turns of stored history sent to the LLM (default: 0, all)
+	ToolSystemErrorRetries        int               // Extra attempts for a tool call failing with a system error (default: 0, no retries)
+	WeatherProvider               string            // Upstream used by get_weather (default: wttr)
+	ReminderCreatorConfirmation   bool              // DM the event creator after a reminder is pushed (default: false)
+	ReplyConvertMarkdown          bool              // Convert Markdown in replies to plain text before sending (default: true)
+	BotPresenceCheck              bool              // Ignore messages in groups the bot is recorded as having left (default: true)
+	EmptyResponseReply            string            // Reply sent when the LLM ends a turn with no output (default: ごめん、うまく答えられなかった)
+	SafetyBlockedReply            string            // Reply sent when a safety filter blocks the LLM output (default: ごめんね、その話にはうまく答えられないんだ)
 }
 
 const (
@@ -62,6 +112,12 @@ const (
 	// defaultLLMTimeoutSeconds is the default LLM API timeout in seconds.
 	defaultLLMTimeoutSeconds = 30
 
+	// defaultLLMBreakerThreshold is the number of consecutive LLM failures that open the circuit breaker.
+	defaultLLMBreakerThreshold = 5
+
+	// defaultLLMBreakerCooldownSeconds is how long the open circuit breaker fails fast.
+	defaultLLMBreakerCooldownSeconds = 30
+
 	// defaultTypingIndicatorDelaySeconds is the delay before showing typing indicator.
 	defaultTypingIndicatorDelaySeconds = 5
 
@@ -73,8 +129,35 @@ const (
 
 	// defaultEventListLimit is the max items for list_events.
 	defaultEventListLimit = 5
+
+	// defaultMaxConcurrentHandlers is the max handler invocations running at once.
+	defaultMaxConcurrentHandlers = 10
+
+	// defaultOutboundTimeoutSeconds is the request timeout for tools calling external APIs.
+	defaultOutboundTimeoutSeconds = 10
+
+	// defaultOutboundMaxIdleConns is the max idle connections kept for external APIs.
+	defaultOutboundMaxIdleConns = 100
+
+	// defaultOutboundMaxIdleConnsPerHost is the max idle connections kept per external host.
+	defaultOutboundMaxIdleConnsPerHost = 10
+
+	// defaultReminderIntervalSeconds is how often the reminder dispatcher checks for due reminders.
+	defaultReminderIntervalSeconds = 60
+
+	// defaultShutdownTimeoutSeconds is how long shutdown waits for in-flight work to drain.
+	defaultShutdownTimeoutSeconds = 30
 )
 
+// eventSweepInterval is how often ended events are checked against the retention window.
+const eventSweepInterval = time.Hour
+
+// healthEndpoint serves readiness for startup probes.
+const healthEndpoint = "/healthz"
+
+// profileBackfillTTL is how long profiles fetched from LINE for users without a stored profile are cached.
+const profileBackfillTTL = 10 * time.Minute
+
 // parsePositiveInt parses an environment variable as a positive integer.
 // Returns the default value if the environment variable is not set.
 // Returns an error if the value is invalid or not positive.
@@ -90,8 +173,82 @@ func parsePositiveInt(envName string, defaultValue int) (int, error) {
 	return parsed, nil
 }
 
+// parseInt parses an environment variable as an integer, which may be negative.
+// Returns the default value if the environment variable is not set.
+// Returns an error if the value is not an integer.
+func parseInt(envName string, defaultValue int) (int, error) {
+	env := os.Getenv(envName)
+	if env == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.Atoi(env)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be an integer: %s", envName, env)
+	}
+	return parsed, nil
+}
+
+// parseNonNegativeInt parses an environment variable as a non-negative integer.
+// Returns the default value if the environment variable is not set.
+// Returns an error if the value is invalid or negative.
+func parseNonNegativeInt(envName string, defaultValue int) (int, error) {
+	env := os.Getenv(envName)
+	if env == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.Atoi(env)
+	if err != nil || parsed < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer: %s", envName, env)
+	}
+	return parsed, nil
+}
+
+// parseLabels parses an environment variable as comma-separated key=value pairs.
+// Whitespace around pairs, keys, and values is trimmed and empty pairs are skipped.
+// Returns nil if the environment variable is not set.
+// Returns an error if a pair has no "=", a key repeats, or a label violates GCP's label constraints.
+func parseLabels(envName string) (map[string]string, error) {
+	var labels map[string]string
+	for pair := range strings.SplitSeq(os.Getenv(envName), ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s must be comma-separated key=value pairs: %s", envName, pair)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if _, dup := labels[key]; dup {
+			return nil, fmt.Errorf("%s has a duplicate key: %s", envName, key)
+		}
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[key] = value
+	}
+	if err := agent.ValidateLabels(labels); err != nil {
+		return nil, fmt.Errorf("%s: %w", envName, err)
+	}
+	return labels, nil
+}
+
+// parseBool parses an environment variable as a boolean (1, t, true, 0, f, false, ...).
+// Returns the default value if the environment variable is not set.
+func parseBool(envName string, defaultValue bool) (bool, error) {
+	env := strings.TrimSpace(os.Getenv(envName))
+	if env == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.ParseBool(env)
+	if err != nil {
+		return false, fmt.Errorf("%s must be a boolean: %s", envName, env)
+	}
+	return parsed, nil
+}
+
 // loadConfig loads configuration from environment variables.
-// It reads LOG_LEVEL, ENDPOINT, PORT, LINE_CHANNEL_SECRET, LINE_CHANNEL_ACCESS_TOKEN, GCP_PROJECT_ID, GCP_REGION, LLM_MODEL, LLM_CACHE_TTL_MINUTES, LLM_TIMEOUT_SECONDS, and BUCKET_NAME from environment.
+// All settings are validated here so a misconfigured deployment fails at startup rather than in the middle of a conversation;
+// the Config struct is the authoritative list of what can be set.
 // Returns error if required environment variables (ENDPOINT, LINE credentials, LLM_MODEL, BUCKET_NAME) are missing or empty after trimming whitespace.
 // GCP_PROJECT_ID and GCP_REGION are optional (auto-detected on Cloud Run).
 // LOG_LEVEL is optional (default: INFO, valid values: DEBUG, INFO, WARN, ERROR).
@@ -158,6 +315,28 @@ func loadConfig() (*Config, error) {
 		return nil, err
 	}
 
+	// Parse LLM circuit breaker settings (threshold 0 disables the breaker)
+	llmBreakerThreshold, err := parseNonNegativeInt("LLM_BREAKER_THRESHOLD", defaultLLMBreakerThreshold)
+	if err != nil {
+		return nil, err
+	}
+	llmBreakerCooldownSeconds, err := parsePositiveInt("LLM_BREAKER_COOLDOWN_SECONDS", defaultLLMBreakerCooldownSeconds)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse LLM_MAX_SYSTEM_PROMPT_LENGTH (optional, default 32000)
+	llmMaxSystemPromptLength, err := parsePositiveInt("LLM_MAX_SYSTEM_PROMPT_LENGTH", agent.DefaultMaxSystemPromptLength)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse LLM_LABELS (optional, default none)
+	llmLabels, err := parseLabels("LLM_LABELS")
+	if err != nil {
+		return nil, err
+	}
+
 	// Load and validate BUCKET_NAME (required)
 	bucketName := strings.TrimSpace(os.Getenv("BUCKET_NAME"))
 	if bucketName == "" {
@@ -186,11 +365,201 @@ func loadConfig() (*Config, error) {
 		return nil, err
 	}
 
+	// Parse event carousel size (LINE rejects larger carousels)
+	eventCarouselSize, err := parsePositiveInt("EVENT_CAROUSEL_SIZE", card.MaxCarouselSize)
+	if err != nil {
+		return nil, err
+	}
+	if eventCarouselSize > card.MaxCarouselSize {
+		return nil, fmt.Errorf("EVENT_CAROUSEL_SIZE must not exceed LINE's carousel limit (%d): %d", card.MaxCarouselSize, eventCarouselSize)
+	}
+
 	// Parse event list limit
 	eventListLimit, err := parsePositiveInt("EVENT_LIST_LIMIT", defaultEventListLimit)
 	if err != nil {
 		return nil, err
 	}
+	if eventListLimit > eventCarouselSize {
+		return nil, fmt.Errorf("EVENT_LIST_LIMIT must not exceed EVENT_CAROUSEL_SIZE (%d): %d", eventCarouselSize, eventListLimit)
+	}
+
+	// Parse list_events default window (span 0 means open-ended)
+	eventListDefaultStartOffset, err := parseInt("EVENT_LIST_DEFAULT_START_OFFSET_DAYS", 0)
+	if err != nil {
+		return nil, err
+	}
+	eventListDefaultSpanDays, err := parseNonNegativeInt("EVENT_LIST_DEFAULT_SPAN_DAYS", 0)
+	if err != nil {
+		return nil, err
+	}
+	if eventListDefaultSpanDays > eventListMaxPeriodDays {
+		return nil, fmt.Errorf("EVENT_LIST_DEFAULT_SPAN_DAYS must not exceed EVENT_LIST_MAX_PERIOD_DAYS (%d): %d", eventListMaxPeriodDays, eventListDefaultSpanDays)
+	}
+
+	// Parse create_event defaults (capacity 0 means unlimited)
+	eventDefaultCapacity, err := parseNonNegativeInt("EVENT_DEFAULT_CAPACITY", 0)
+	if err != nil {
+		return nil, err
+	}
+	eventDefaultFee := strings.TrimSpace(os.Getenv("EVENT_DEFAULT_FEE"))
+
+	// Parse max upcoming events per creator (0 means unlimited)
+	eventMaxUpcomingPerCreator, err := parseNonNegativeInt("EVENT_MAX_UPCOMING_PER_CREATOR", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse minimum lead time for new events (0 disables it)
+	eventMinLeadMinutes, err := parseNonNegativeInt("EVENT_MIN_LEAD_MINUTES", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse event text length limits (counted in characters)
+	eventMaxTitleLength, err := parsePositiveInt("EVENT_MAX_TITLE_LENGTH", eventdomain.DefaultTextLimits.MaxTitle)
+	if err != nil {
+		return nil, err
+	}
+	eventMaxDescriptionLength, err := parsePositiveInt("EVENT_MAX_DESCRIPTION_LENGTH", eventdomain.DefaultTextLimits.MaxDescription)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse ended event retention (0 disables the sweeper, since removed events cannot be restored)
+	eventRetentionDays, err := parseNonNegativeInt("EVENT_RETENTION_DAYS", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse max concurrent handlers
+	maxConcurrentHandlers, err := parsePositiveInt("MAX_CONCURRENT_HANDLERS", defaultMaxConcurrentHandlers)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse outbound HTTP client settings
+	outboundTimeoutSeconds, err := parsePositiveInt("OUTBOUND_TIMEOUT_SECONDS", defaultOutboundTimeoutSeconds)
+	if err != nil {
+		return nil, err
+	}
+	outboundMaxIdleConns, err := parsePositiveInt("OUTBOUND_MAX_IDLE_CONNS", defaultOutboundMaxIdleConns)
+	if err != nil {
+		return nil, err
+	}
+	outboundMaxIdleConnsPerHost, err := parsePositiveInt("OUTBOUND_MAX_IDLE_CONNS_PER_HOST", defaultOutboundMaxIdleConnsPerHost)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse reminder dispatch interval
+	reminderIntervalSeconds, err := parsePositiveInt("REMINDER_INTERVAL_SECONDS", defaultReminderIntervalSeconds)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse graceful shutdown timeout
+	shutdownTimeoutSeconds, err := parsePositiveInt("SHUTDOWN_TIMEOUT_SECONDS", defaultShutdownTimeoutSeconds)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load system prompt variables
+	botName := strings.TrimSpace(os.Getenv("BOT_NAME"))
+	if botName == "" {
+		botName = yuruppu.DefaultBotName
+	}
+	var personaTraits []string
+	for trait := range strings.SplitSeq(os.Getenv("BOT_PERSONA_TRAITS"), ",") {
+		if trait = strings.TrimSpace(trait); trait != "" {
+			personaTraits = append(personaTraits, trait)
+		}
+	}
+
+	// Decode storage encryption key (base64, 16/24/32 bytes)
+	var storageEncryptionKey []byte
+	if env := strings.TrimSpace(os.Getenv("STORAGE_ENCRYPTION_KEY")); env != "" {
+		key, err := base64.StdEncoding.DecodeString(env)
+		if err != nil || !slices.Contains([]int{16, 24, 32}, len(key)) {
+			return nil, errors.New("STORAGE_ENCRYPTION_KEY must be a base64-encoded 16, 24, or 32 byte key")
+		}
+		storageEncryptionKey = key
+	}
+
+	// Parse history keying strategy
+	historyKeying := history.KeyingShared
+	if env := strings.TrimSpace(os.Getenv("HISTORY_KEYING")); env != "" {
+		historyKeying, err = history.ParseKeying(env)
+		if err != nil {
+			return nil, fmt.Errorf("HISTORY_KEYING is invalid: %w", err)
+		}
+	}
+
+	// Parse LLM payload logging toggle
+	debugLLM, err := parseBool("DEBUG_LLM", false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse webhook signature check bypass (local development only)
+	disableSignatureCheck, err := parseBool("DISABLE_SIGNATURE_CHECK", false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse per-turn tool call budget (0 means unlimited)
+	maxToolCallsPerTurn, err := parseNonNegativeInt("MAX_TOOL_CALLS_PER_TURN", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse how many recent history turns reach the LLM (0 means all)
+	maxHistoryTurns, err := parseNonNegativeInt("MAX_HISTORY_TURNS", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse retries for tool calls failing with a system error (0 disables them)
+	toolSystemErrorRetries, err := parseNonNegativeInt("TOOL_SYSTEM_ERROR_RETRIES", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load weather provider name
+	weatherProvider := strings.TrimSpace(os.Getenv("WEATHER_PROVIDER"))
+	if weatherProvider == "" {
+		weatherProvider = weather.ProviderWttr
+	}
+	if !slices.Contains(weather.ProviderNames(), weatherProvider) {
+		return nil, fmt.Errorf("WEATHER_PROVIDER must be one of %s: %s", strings.Join(weather.ProviderNames(), ", "), weatherProvider)
+	}
+
+	// Parse reminder confirmation toggle
+	reminderCreatorConfirmation, err := parseBool("REMINDER_CREATOR_CONFIRMATION", false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse reply Markdown conversion toggle
+	replyConvertMarkdown, err := parseBool("REPLY_CONVERT_MARKDOWN", true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse group bot presence check toggle
+	botPresenceCheck, err := parseBool("BOT_PRESENCE_CHECK", true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load fallback replies for empty or safety-blocked LLM output
+	emptyResponseReply := strings.TrimSpace(os.Getenv("EMPTY_RESPONSE_REPLY"))
+	if emptyResponseReply == "" {
+		emptyResponseReply = bot.DefaultEmptyResponseReply
+	}
+	safetyBlockedReply := strings.TrimSpace(os.Getenv("SAFETY_BLOCKED_REPLY"))
+	if safetyBlockedReply == "" {
+		safetyBlockedReply = bot.DefaultSafetyBlockedReply
+	}
 
 	return &Config{
 		LogLevel:                      logLevel,
@@ -203,14 +572,84 @@ func loadConfig() (*Config, error) {
 		LLMModel:                      llmModel,
 		LLMCacheTTLMinutes:            llmCacheTTLMinutes,
 		LLMTimeoutSeconds:             llmTimeoutSeconds,
+		LLMBreakerThreshold:           llmBreakerThreshold,
+		LLMBreakerCooldownSeconds:     llmBreakerCooldownSeconds,
+		LLMMaxSystemPromptLength:      llmMaxSystemPromptLength,
+		LLMLabels:                     llmLabels,
 		BucketName:                    bucketName,
 		TypingIndicatorDelaySeconds:   typingIndicatorDelaySeconds,
 		TypingIndicatorTimeoutSeconds: typingIndicatorTimeoutSeconds,
 		EventListMaxPeriodDays:        eventListMaxPeriodDays,
 		EventListLimit:                eventListLimit,
+		EventListDefaultStartOffset:   eventListDefaultStartOffset,
+		EventListDefaultSpanDays:      eventListDefaultSpanDays,
+		EventCarouselSize:             eventCarouselSize,
+		EventDefaultCapacity:          eventDefaultCapacity,
+		EventMaxUpcomingPerCreator:    eventMaxUpcomingPerCreator,
+		EventMinLeadMinutes:           eventMinLeadMinutes,
+		EventMaxTitleLength:           eventMaxTitleLength,
+		EventMaxDescriptionLength:     eventMaxDescriptionLength,
+		EventRetentionDays:            eventRetentionDays,
+		EventDefaultFee:               eventDefaultFee,
+		MaxConcurrentHandlers:         maxConcurrentHandlers,
+		OutboundTimeoutSeconds:        outboundTimeoutSeconds,
+		OutboundMaxIdleConns:          outboundMaxIdleConns,
+		OutboundMaxIdleConnsPerHost:   outboundMaxIdleConnsPerHost,
+		ReminderIntervalSeconds:       reminderIntervalSeconds,
+		ShutdownTimeoutSeconds:        shutdownTimeoutSeconds,
+		BotName:                       botName,
+		PersonaTraits:                 personaTraits,
+		StorageEncryptionKey:          storageEncryptionKey,
+		HistoryKeying:                 historyKeying,
+		DebugLLM:                      debugLLM,
+		DisableSignatureCheck:         disableSignatureCheck,
+		MaxToolCallsPerTurn:           maxToolCallsPerTurn,
+		MaxHistoryTurns:               maxHistoryTurns,
+		ToolSystemErrorRetries:        toolSystemErrorRetries,
+		WeatherProvider:               weatherProvider,
+		ReminderCreatorConfirmation:   reminderCreatorConfirmation,
+		ReplyConvertMarkdown:          replyConvertMarkdown,
+		BotPresenceCheck:              botPresenceCheck,
+		EmptyResponseReply:            emptyResponseReply,
+		SafetyBlockedReply:            safetyBlockedReply,
 	}, nil
 }
 
+// newPersonalDataStorage creates GCS storage for data that contains personal information.
+// Values are encrypted at rest when a storage encryption key is configured.
+func newPersonalDataStorage(client *gcsstorage.Client, config *Config, keyPrefix string) (storage.Storage, error) {
+	gcs, err := storage.NewGCSStorage(client, config.BucketName, keyPrefix)
+	if err != nil {
+		return nil, err
+	}
+	if config.StorageEncryptionKey == nil {
+		return gcs, nil
+	}
+	return storage.NewEncryptedStorage(gcs, config.StorageEncryptionKey)
+}
+
+// newOutboundHTTPClient creates the shared HTTP client for tools that call external APIs.
+// Connections are pooled across tool calls and every request is bounded by the configured timeout.
+func newOutboundHTTPClient(config *Config) *http.Client {
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   5 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          config.OutboundMaxIdleConns,
+		MaxIdleConnsPerHost:   config.OutboundMaxIdleConnsPerHost,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+	return &http.Client{
+		Transport: transport,
+		Timeout:   time.Duration(config.OutboundTimeoutSeconds) * time.Second,
+	}
+}
+
 func getProjectIDAndRegion(ctx context.Context) (string, string, error) {
 	if !metadata.OnGCE() {
 		return "", "", errors.New("not running on GCE")
@@ -223,11 +662,215 @@ func getProjectIDAndRegion(ctx context.Context) (string, string, error) {
 	return projectID, zone[:len(zone)-2], nil
 }
 
+// configCheck is a lightweight connectivity check run by -check-config.
+type configCheck struct {
+	name string
+	run  func(ctx context.Context, config *Config) error
+}
+
+// defaultConfigChecks returns the connectivity checks for the deployed environment.
+func defaultConfigChecks() []configCheck {
+	return []configCheck{
+		{name: "metadata server", run: func(ctx context.Context, config *Config) error {
+			if _, _, err := getProjectIDAndRegion(ctx); err != nil {
+				if config.GCPProjectID == "" || config.GCPRegion == "" {
+					return fmt.Errorf("%w (and GCP_PROJECT_ID/GCP_REGION fallback is not set)", err)
+				}
+			}
+			return nil
+		}},
+		{name: "bucket existence", run: func(ctx context.Context, config *Config) error {
+			client, err := gcsstorage.NewClient(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to create GCS client: %w", err)
+			}
+			defer func() { _ = client.Close() }()
+			_, err = client.Bucket(config.BucketName).Attrs(ctx)
+			return err
+		}},
+	}
+}
+
+// redact hides a secret value in the -check-config report.
+func redact(value string) string {
+	if value == "" {
+		return "(not set)"
+	}
+	return "(redacted)"
+}
+
+// writeConfigReport loads the configuration, runs checks, and writes a pass/fail line per setting and check to w.
+// Secret values are redacted. Returns false if loading the configuration or any check fails.
+func writeConfigReport(ctx context.Context, w io.Writer, checks []configCheck) bool {
+	config, err := loadConfig()
+	if err != nil {
+		_, _ = fmt.Fprintf(w, "FAIL config: %v\n", err)
+		return false
+	}
+
+	historyKeying := "shared"
+	if config.HistoryKeying == history.KeyingPerUser {
+		historyKeying = "per_user"
+	}
+	labelPairs := make([]string, 0, len(config.LLMLabels))
+	for _, key := range slices.Sorted(maps.Keys(config.LLMLabels)) {
+		labelPairs = append(labelPairs, key+"="+config.LLMLabels[key])
+	}
+	llmLabels := strings.Join(labelPairs, ",")
+	settings := []struct{ name, value string }{
+		{"LOG_LEVEL", config.LogLevel.String()},
+		{"ENDPOINT", config.Endpoint},
+		{"PORT", config.Port},
+		{"LINE_CHANNEL_SECRET", redact(config.ChannelSecret)},
+		{"LINE_CHANNEL_ACCESS_TOKEN", redact(config.ChannelAccessToken)},
+		{"GCP_PROJECT_ID", config.GCPProjectID},
+		{"GCP_REGION", config.GCPRegion},
+		{"LLM_MODEL", config.LLMModel},
+		{"LLM_CACHE_TTL_MINUTES", strconv.Itoa(config.LLMCacheTTLMinutes)},
+		{"LLM_TIMEOUT_SECONDS", strconv.Itoa(config.LLMTimeoutSeconds)},
+		{"LLM_BREAKER_THRESHOLD", strconv.Itoa(config.LLMBreakerThreshold)},
+		{"LLM_BREAKER_COOLDOWN_SECONDS", strconv.Itoa(config.LLMBreakerCooldownSeconds)},
+		{"LLM_MAX_SYSTEM_PROMPT_LENGTH", strconv.Itoa(config.LLMMaxSystemPromptLength)},
+		{"LLM_LABELS", llmLabels},
+		{"BUCKET_NAME", config.BucketName},
+		{"TYPING_INDICATOR_DELAY_SECONDS", strconv.Itoa(config.TypingIndicatorDelaySeconds)},
+		{"TYPING_INDICATOR_TIMEOUT_SECONDS", strconv.Itoa(config.TypingIndicatorTimeoutSeconds)},
+		{"EVENT_LIST_MAX_PERIOD_DAYS", strconv.Itoa(config.EventListMaxPeriodDays)},
+		{"EVENT_LIST_LIMIT", strconv.Itoa(config.EventListLimit)},
+		{"EVENT_LIST_DEFAULT_START_OFFSET_DAYS", strconv.Itoa(config.EventListDefaultStartOffset)},
+		{"EVENT_LIST_DEFAULT_SPAN_DAYS", strconv.Itoa(config.EventListDefaultSpanDays)},
+		{"EVENT_CAROUSEL_SIZE", strconv.Itoa(config.EventCarouselSize)},
+		{"EVENT_DEFAULT_CAPACITY", strconv.Itoa(config.EventDefaultCapacity)},
+		{"EVENT_DEFAULT_FEE", config.EventDefaultFee},
+		{"EVENT_MAX_UPCOMING_PER_CREATOR", strconv.Itoa(config.EventMaxUpcomingPerCreator)},
+		{"EVENT_MIN_LEAD_MINUTES", strconv.Itoa(config.EventMinLeadMinutes)},
+		{"EVENT_MAX_TITLE_LENGTH", strconv.Itoa(config.EventMaxTitleLength)},
+		{"EVENT_MAX_DESCRIPTION_LENGTH", strconv.Itoa(config.EventMaxDescriptionLength)},
+		{"EVENT_RETENTION_DAYS", strconv.Itoa(config.EventRetentionDays)},
+		{"MAX_CONCURRENT_HANDLERS", strconv.Itoa(config.MaxConcurrentHandlers)},
+		{"OUTBOUND_TIMEOUT_SECONDS", strconv.Itoa(config.OutboundTimeoutSeconds)},
+		{"OUTBOUND_MAX_IDLE_CONNS", strconv.Itoa(config.OutboundMaxIdleConns)},
+		{"OUTBOUND_MAX_IDLE_CONNS_PER_HOST", strconv.Itoa(config.OutboundMaxIdleConnsPerHost)},
+		{"REMINDER_INTERVAL_SECONDS", strconv.Itoa(config.ReminderIntervalSeconds)},
+		{"SHUTDOWN_TIMEOUT_SECONDS", strconv.Itoa(config.ShutdownTimeoutSeconds)},
+		{"BOT_NAME", config.BotName},
+		{"BOT_PERSONA_TRAITS", strings.Join(config.PersonaTraits, ",")},
+		{"STORAGE_ENCRYPTION_KEY", redact(string(config.StorageEncryptionKey))},
+		{"HISTORY_KEYING", historyKeying},
+		{"DEBUG_LLM", strconv.FormatBool(config.DebugLLM)},
+		{"DISABLE_SIGNATURE_CHECK", strconv.FormatBool(config.DisableSignatureCheck)},
+		{"MAX_TOOL_CALLS_PER_TURN", strconv.Itoa(config.MaxToolCallsPerTurn)},
+		{"MAX_HISTORY_TURNS", strconv.Itoa(config.MaxHistoryTurns)},
+		{"TOOL_SYSTEM_ERROR_RETRIES", strconv.Itoa(config.ToolSystemErrorRetries)},
+		{"WEATHER_PROVIDER", config.WeatherProvider},
+		{"REMINDER_CREATOR_CONFIRMATION", strconv.FormatBool(config.ReminderCreatorConfirmation)},
+		{"REPLY_CONVERT_MARKDOWN", strconv.FormatBool(config.ReplyConvertMarkdown)},
+		{"BOT_PRESENCE_CHECK", strconv.FormatBool(config.BotPresenceCheck)},
+		{"EMPTY_RESPONSE_REPLY", config.EmptyResponseReply},
+		{"SAFETY_BLOCKED_REPLY", config.SafetyBlockedReply},
+	}
+	for _, s := range settings {
+		_, _ = fmt.Fprintf(w, "PASS %s=%s\n", s.name, s.value)
+	}
+
+	ok := true
+	for _, check := range checks {
+		if err := check.run(ctx, config); err != nil {
+			_, _ = fmt.Fprintf(w, "FAIL %s: %v\n", check.name, err)
+			ok = false
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "PASS %s\n", check.name)
+	}
+	return ok
+}
+
+// startupSteps logs each initialization step at INFO with how long it took,
+// so a failed start can be pinned to the step that was in progress.
+type startupSteps struct {
+	logger *slog.Logger
+	name   string    // the step in progress
+	start  time.Time // when the step in progress began
+}
+
+// begin marks the start of the step called name.
+func (s *startupSteps) begin(name string) {
+	s.name = name
+	s.start = time.Now()
+}
+
+// done logs that the step in progress completed, with attrs describing its outcome.
+func (s *startupSteps) done(attrs ...slog.Attr) {
+	attrs = append([]slog.Attr{
+		slog.String("step", s.name),
+		slog.Duration("duration", time.Since(s.start)),
+	}, attrs...)
+	s.logger.LogAttrs(context.Background(), slog.LevelInfo, "startup step completed", attrs...)
+}
+
+// failed logs err against the step in progress and returns it.
+func (s *startupSteps) failed(err error) error {
+	s.logger.Error("startup step failed",
+		slog.String("step", s.name),
+		slog.Duration("duration", time.Since(s.start)),
+		slog.Any("error", err),
+	)
+	return err
+}
+
+// closableAgent is the agent the message handler uses, closed on shutdown.
+type closableAgent interface {
+	bot.Agent
+	Close(ctx context.Context) error
+}
+
+// closeAgent closes a, giving it at most timeout, and logs a failure under name.
+func closeAgent(a closableAgent, name string, timeout time.Duration, logger *slog.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := a.Close(ctx); err != nil {
+		logger.Error("failed to close "+name, slog.Any("error", err))
+	}
+}
+
+// startupDeps holds the constructors run uses to reach external services, so tests can replace them.
+type startupDeps struct {
+	listen          func(network, address string) (net.Listener, error)
+	lineClientOpts  []lineclient.Option
+	newGCSClient    func(ctx context.Context) (*gcsstorage.Client, error)
+	resolveMetadata func(ctx context.Context) (projectID, region string, err error)
+	newAgent        func(ctx context.Context, cfg agent.GeminiConfig, logger *slog.Logger) (closableAgent, error)
+}
+
+// defaultStartupDeps returns the constructors for the deployed environment.
+func defaultStartupDeps() startupDeps {
+	return startupDeps{
+		listen: net.Listen,
+		newGCSClient: func(ctx context.Context) (*gcsstorage.Client, error) {
+			return gcsstorage.NewClient(ctx)
+		},
+		resolveMetadata: getProjectIDAndRegion,
+		newAgent: func(ctx context.Context, cfg agent.GeminiConfig, logger *slog.Logger) (closableAgent, error) {
+			return agent.NewGeminiAgent(ctx, cfg, logger)
+		},
+	}
+}
+
 func main() {
+	checkConfigOnly := flag.Bool("check-config", false, "validate configuration and connectivity, print a report, and exit")
+	flag.Parse()
+	if *checkConfigOnly {
+		if !writeConfigReport(context.Background(), os.Stdout, defaultConfigChecks()) {
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Load configuration
+	configStart := time.Now()
 	config, err := loadConfig()
 	if err != nil {
-		slog.Error("failed to load configuration", slog.Any("error", err))
+		slog.Error("startup step failed", slog.String("step", "config loaded"), slog.Any("error", err))
 		os.Exit(1)
 	}
 
@@ -235,192 +878,415 @@ func main() {
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
 		Level: config.LogLevel,
 	}))
+	logger.Info("startup step completed",
+		slog.String("step", "config loaded"),
+		slog.Duration("duration", time.Since(configStart)),
+	)
+
+	// Run until a shutdown signal arrives
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := run(ctx, config, logger, defaultStartupDeps()); err != nil {
+		os.Exit(1)
+	}
+	logger.Info("graceful shutdown completed")
+}
+
+// run initializes every component, serves webhooks until ctx is done or the server fails, and then shuts down gracefully,
+// closing the GCS client last.
+// Each initialization step is logged as it completes; a failing step is logged and its error returned.
+// A server failure is returned after the shutdown.
+func run(ctx context.Context, config *Config, logger *slog.Logger, deps startupDeps) error {
+	steps := &startupSteps{logger: logger}
 
 	// Initialize components
+	steps.begin("LINE client ready")
+	lineClientOpts := append([]lineclient.Option{
+		lineclient.WithDeadLetterSink(lineclient.NewLogDeadLetterSink(logger)),
+	}, deps.lineClientOpts...)
+	lineClient, err := lineclient.NewClient(config.ChannelAccessToken, logger, lineClientOpts...)
+	if err != nil {
+		return steps.failed(fmt.Errorf("failed to initialize client: %w", err))
+	}
+
 	llmTimeout := time.Duration(config.LLMTimeoutSeconds) * time.Second
-	lineServer, err := lineserver.NewServer(config.ChannelSecret, llmTimeout, logger)
+	serverOpts := []lineserver.Option{
+		lineserver.WithMaxConcurrency(config.MaxConcurrentHandlers),
+		lineserver.WithReadinessGate(),
+		lineserver.WithReplyTokenObserver(lineClient.RegisterReplySource),
+	}
+	if config.DisableSignatureCheck {
+		serverOpts = append(serverOpts, lineserver.WithoutSignatureCheck())
+	}
+	// The bot's user ID identifies mentions of the bot; without it only the isSelf flag is used
+	botUserID, err := lineClient.GetBotUserID(ctx)
 	if err != nil {
-		logger.Error("failed to initialize server", slog.Any("error", err))
-		os.Exit(1)
+		logger.Warn("failed to fetch bot user ID", slog.Any("error", err))
+	} else {
+		serverOpts = append(serverOpts, lineserver.WithBotUserID(botUserID))
 	}
+	lineServer, err := lineserver.NewServer(config.ChannelSecret, llmTimeout, logger, serverOpts...)
+	if err != nil {
+		return steps.failed(fmt.Errorf("failed to initialize server: %w", err))
+	}
+	steps.done(slog.Bool("botUserIDKnown", botUserID != ""))
 
-	lineClient, err := lineclient.NewClient(config.ChannelAccessToken, logger)
+	// Start HTTP server early; webhooks get 503 until startup completes so LINE retries them
+	mux := http.NewServeMux()
+	mux.HandleFunc(config.Endpoint, lineServer.HandleWebhook)
+	mux.HandleFunc(healthEndpoint, lineServer.HandleHealth)
+	httpServer := &http.Server{
+		Addr:              ":" + config.Port,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second, // Prevent Slowloris attacks
+	}
+	steps.begin("server listening")
+	listener, err := deps.listen("tcp", httpServer.Addr)
 	if err != nil {
-		logger.Error("failed to initialize client", slog.Any("error", err))
-		os.Exit(1)
+		return steps.failed(fmt.Errorf("failed to listen: %w", err))
 	}
+	steps.done(slog.String("address", listener.Addr().String()))
+	serverDone := make(chan struct{})
+	serveErr := make(chan error, 1)
+	go func() {
+		defer close(serverDone)
+		logger.Info("server starting",
+			slog.String("endpoint", config.Endpoint),
+			slog.String("port", config.Port),
+		)
+		if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serveErr <- err
+		}
+	}()
+	defer func() {
+		// Stops the server when a later step fails; after the graceful shutdown below it only waits
+		_ = httpServer.Close()
+		<-serverDone
+	}()
 
 	// Resolve project ID and region from Cloud Run metadata with env var fallback
-	projectID, region, err := getProjectIDAndRegion(context.Background())
+	steps.begin("metadata resolved")
+	metadataSource := "metadata server"
+	projectID, region, err := deps.resolveMetadata(ctx)
 	if err != nil {
 		logger.Warn("failed to get metadata from GCP, using fallback", slog.Any("error", err))
+		metadataSource = "environment"
 		projectID = config.GCPProjectID
 		region = config.GCPRegion
 	}
+	steps.done(
+		slog.String("projectID", projectID),
+		slog.String("region", region),
+		slog.String("source", metadataSource),
+	)
 
-	// Create tools
-	weatherTool, err := weather.NewTool(&http.Client{Timeout: 30 * time.Second}, logger)
+	// Create shared GCS client
+	steps.begin("storage ready")
+	gcsClient, err := deps.newGCSClient(context.Background())
 	if err != nil {
-		logger.Error("failed to create weather tool", slog.Any("error", err))
-		os.Exit(1)
+		return steps.failed(fmt.Errorf("failed to create GCS client: %w", err))
 	}
+	defer func() {
+		if err := gcsClient.Close(); err != nil {
+			logger.Error("failed to close GCS client", slog.Any("error", err))
+		}
+	}()
+	steps.done(
+		slog.String("bucket", config.BucketName),
+		slog.Bool("encrypted", config.StorageEncryptionKey != nil),
+	)
 
-	// Create shared GCS client
-	gcsClient, err := gcsstorage.NewClient(context.Background())
+	// Create history repository (needed by reply tool and handler)
+	steps.begin("history enabled")
+	historyStorage, err := newPersonalDataStorage(gcsClient, config, "history/")
 	if err != nil {
-		logger.Error("failed to create GCS client", slog.Any("error", err))
-		os.Exit(1)
+		return steps.failed(fmt.Errorf("failed to create history storage: %w", err))
+	}
+	historySvc, err := history.NewService(historyStorage, history.WithKeying(config.HistoryKeying))
+	if err != nil {
+		return steps.failed(fmt.Errorf("failed to create history service: %w", err))
 	}
+	historyKeying := "shared"
+	if config.HistoryKeying == history.KeyingPerUser {
+		historyKeying = "per_user"
+	}
+	steps.done(slog.String("keying", historyKeying))
 
-	// Create history repository (needed by reply tool and handler)
-	historyStorage, err := storage.NewGCSStorage(gcsClient, config.BucketName, "history/")
+	// Create tools (tools calling external APIs share a pooled HTTP client)
+	steps.begin("tools ready")
+	outboundHTTPClient := newOutboundHTTPClient(config)
+	weatherProvider, err := weather.NewProvider(config.WeatherProvider, outboundHTTPClient, logger)
 	if err != nil {
-		logger.Error("failed to create history storage", slog.Any("error", err))
-		os.Exit(1)
+		return steps.failed(fmt.Errorf("failed to create weather provider: %w", err))
 	}
-	historySvc, err := history.NewService(historyStorage)
+	weatherTool, err := weather.NewTool(weatherProvider, logger)
 	if err != nil {
-		logger.Error("failed to create history service", slog.Any("error", err))
-		os.Exit(1)
+		return steps.failed(fmt.Errorf("failed to create weather tool: %w", err))
 	}
 
 	// Create reply tool
-	replyTool, err := reply.NewTool(lineClient, historySvc, logger)
+	var replyOpts []reply.Option
+	if config.ReplyConvertMarkdown {
+		replyOpts = append(replyOpts, reply.WithMarkdownConversion())
+	}
+	replyTool, err := reply.NewTool(lineClient, historySvc, logger, replyOpts...)
 	if err != nil {
-		logger.Error("failed to create reply tool", slog.Any("error", err))
-		os.Exit(1)
+		return steps.failed(fmt.Errorf("failed to create reply tool: %w", err))
 	}
 
 	// Create skip tool
 	skipTool, err := skip.NewTool(logger)
 	if err != nil {
-		logger.Error("failed to create skip tool", slog.Any("error", err))
-		os.Exit(1)
+		return steps.failed(fmt.Errorf("failed to create skip tool: %w", err))
 	}
 
 	// Create user profile service (needed by event tools and handler)
-	userProfileStorage, err := storage.NewGCSStorage(gcsClient, config.BucketName, "userprofile/")
+	userProfileStorage, err := newPersonalDataStorage(gcsClient, config, "userprofile/")
 	if err != nil {
-		logger.Error("failed to create user profile storage", slog.Any("error", err))
-		os.Exit(1)
+		return steps.failed(fmt.Errorf("failed to create user profile storage: %w", err))
 	}
-	userProfileService, err := userprofile.NewService(userProfileStorage, logger)
+	userProfileService, err := userprofile.NewService(userProfileStorage, logger, userprofile.WithBackfill(lineClient, profileBackfillTTL))
 	if err != nil {
-		logger.Error("failed to create user profile service", slog.Any("error", err))
-		os.Exit(1)
+		return steps.failed(fmt.Errorf("failed to create user profile service: %w", err))
 	}
 
 	// Create group profile service
-	groupProfileStorage, err := storage.NewGCSStorage(gcsClient, config.BucketName, "groupprofile/")
+	groupProfileStorage, err := newPersonalDataStorage(gcsClient, config, "groupprofile/")
 	if err != nil {
-		logger.Error("failed to create group profile storage", slog.Any("error", err))
-		os.Exit(1)
+		return steps.failed(fmt.Errorf("failed to create group profile storage: %w", err))
 	}
-	groupProfileService, err := groupprofile.NewService(groupProfileStorage, logger)
+	groupMembershipStorage, err := newPersonalDataStorage(gcsClient, config, "groupmembership/")
 	if err != nil {
-		logger.Error("failed to create group profile service", slog.Any("error", err))
-		os.Exit(1)
+		return steps.failed(fmt.Errorf("failed to create group membership storage: %w", err))
+	}
+	groupProfileService, err := groupprofile.NewService(groupProfileStorage, logger, groupprofile.WithMembershipIndex(groupMembershipStorage))
+	if err != nil {
+		return steps.failed(fmt.Errorf("failed to create group profile service: %w", err))
 	}
 
 	// Create event service and tools
 	eventStorage, err := storage.NewGCSStorage(gcsClient, config.BucketName, "event/")
 	if err != nil {
-		logger.Error("failed to create event storage", slog.Any("error", err))
-		os.Exit(1)
+		return steps.failed(fmt.Errorf("failed to create event storage: %w", err))
 	}
-	eventService, err := eventdomain.NewService(eventStorage)
+	eventService, err := eventdomain.NewService(eventStorage, eventdomain.WithLogger(logger))
 	if err != nil {
-		logger.Error("failed to create event service", slog.Any("error", err))
-		os.Exit(1)
+		return steps.failed(fmt.Errorf("failed to create event service: %w", err))
 	}
-	eventTools, err := event.NewTools(eventService, lineClient, userProfileService, config.EventListMaxPeriodDays, config.EventListLimit, logger)
+	icsStorage, err := storage.NewGCSStorage(gcsClient, config.BucketName, "ics/")
 	if err != nil {
-		logger.Error("failed to create event tools", slog.Any("error", err))
-		os.Exit(1)
+		return steps.failed(fmt.Errorf("failed to create ics storage: %w", err))
+	}
+	eventTools, err := event.NewTools(eventService, lineClient, userProfileService, groupProfileService, icsStorage, weatherProvider, event.ToolsConfig{
+		CreateDefaults: event.CreateDefaults{
+			Capacity: config.EventDefaultCapacity,
+			Fee:      config.EventDefaultFee,
+		},
+		TextLimits: event.TextLimits{
+			MaxTitle:       config.EventMaxTitleLength,
+			MaxDescription: config.EventMaxDescriptionLength,
+		},
+		CreateMaxUpcomingPerCreator: config.EventMaxUpcomingPerCreator,
+		CreateMinLeadTime:           time.Duration(config.EventMinLeadMinutes) * time.Minute,
+		ListMaxPeriodDays:           config.EventListMaxPeriodDays,
+		ListLimit:                   config.EventListLimit,
+		ListDefaultWindow: event.ListDefaultWindow{
+			StartOffsetDays: config.EventListDefaultStartOffset,
+			SpanDays:        config.EventListDefaultSpanDays,
+		},
+		CardOptions: []card.Option{card.WithTemplate(yuruppu.EventCardTemplate), card.WithCarouselSize(config.EventCarouselSize)},
+	}, logger)
+	if err != nil {
+		return steps.failed(fmt.Errorf("failed to create event tools: %w", err))
 	}
 
-	// Collect all tools
-	toolset := append([]agent.Tool{weatherTool, replyTool, skipTool}, eventTools...)
+	// Create set_display_name tool
+	displayNameTool, err := displayname.NewTool(userProfileService, logger)
+	if err != nil {
+		return steps.failed(fmt.Errorf("failed to create set_display_name tool: %w", err))
+	}
 
-	// Create Gemini agent with Yuruppu system prompt
-	systemPrompt, err := yuruppu.GetSystemPrompt()
+	// Create set_group_timezone tool
+	groupTimezoneTool, err := grouptimezone.NewTool(groupProfileService, logger)
 	if err != nil {
-		logger.Error("failed to get system prompt", slog.Any("error", err))
-		os.Exit(1)
+		return steps.failed(fmt.Errorf("failed to create set_group_timezone tool: %w", err))
+	}
+
+	// Create set_group_reply_mode tool
+	groupReplyModeTool, err := groupreplymode.NewTool(groupProfileService, logger)
+	if err != nil {
+		return steps.failed(fmt.Errorf("failed to create set_group_reply_mode tool: %w", err))
+	}
+
+	// Create set_group_language tool
+	groupLanguageTool, err := grouplanguage.NewTool(groupProfileService, logger)
+	if err != nil {
+		return steps.failed(fmt.Errorf("failed to create set_group_language tool: %w", err))
+	}
+
+	// Create reminder service and the set_reminder and snooze_reminder tools (the dispatcher starts after the handler)
+	reminderStorage, err := storage.NewGCSStorage(gcsClient, config.BucketName, "reminder/")
+	if err != nil {
+		return steps.failed(fmt.Errorf("failed to create reminder storage: %w", err))
+	}
+	reminderService, err := reminder.NewService(reminderStorage)
+	if err != nil {
+		return steps.failed(fmt.Errorf("failed to create reminder service: %w", err))
+	}
+	remindTool, err := remind.NewTool(reminderService, logger)
+	if err != nil {
+		return steps.failed(fmt.Errorf("failed to create set_reminder tool: %w", err))
 	}
+	snoozeTool, err := snooze.NewTool(reminderService, logger)
+	if err != nil {
+		return steps.failed(fmt.Errorf("failed to create snooze_reminder tool: %w", err))
+	}
+
+	// Create suggest_description tool, backed by its own agent without tools
 	llmCacheTTL := time.Duration(config.LLMCacheTTLMinutes) * time.Minute
-	geminiAgent, err := agent.NewGeminiAgent(context.Background(), agent.GeminiConfig{
+	writerAgent, err := deps.newAgent(context.Background(), agent.GeminiConfig{
 		ProjectID:        projectID,
 		Region:           region,
 		Model:            config.LLMModel,
-		SystemPrompt:     systemPrompt,
-		Tools:            toolset,
-		FunctionCallOnly: true,
-		CacheDisplayName: "yuruppu-system-prompt",
+		SystemPrompt:     suggest.SystemPrompt,
+		CacheDisplayName: "yuruppu-description-writer",
 		CacheTTL:         llmCacheTTL,
+		LogPayloads:      config.DebugLLM,
+		BreakerThreshold: config.LLMBreakerThreshold,
+		BreakerCooldown:  time.Duration(config.LLMBreakerCooldownSeconds) * time.Second,
+		Labels:           config.LLMLabels,
 	}, logger)
 	if err != nil {
-		logger.Error("failed to initialize Gemini agent", slog.Any("error", err))
-		os.Exit(1)
+		return steps.failed(fmt.Errorf("failed to initialize description writer agent: %w", err))
 	}
+	// Deferred so that a later startup failure also releases its cache; on shutdown it runs after the main agent, which calls it, is closed
+	defer closeAgent(writerAgent, "description writer agent", time.Duration(config.ShutdownTimeoutSeconds)*time.Second, logger)
+	suggestTool, err := suggest.New(writerAgent, event.TextLimits{
+		MaxTitle:       config.EventMaxTitleLength,
+		MaxDescription: config.EventMaxDescriptionLength,
+	}, logger)
+	if err != nil {
+		return steps.failed(fmt.Errorf("failed to create suggest_description tool: %w", err))
+	}
+
+	// Collect all tools
+	toolset := append([]agent.Tool{weatherTool, replyTool, skipTool, displayNameTool, groupTimezoneTool, groupReplyModeTool, groupLanguageTool, remindTool, snoozeTool, suggestTool}, eventTools...)
+	steps.done(slog.Int("count", len(toolset)))
+
+	// Create Gemini agent with Yuruppu system prompt
+	steps.begin("agent ready")
+	systemPrompt, err := yuruppu.GetSystemPrompt(yuruppu.PromptVars{
+		BotName:       config.BotName,
+		PersonaTraits: config.PersonaTraits,
+	})
+	if err != nil {
+		return steps.failed(fmt.Errorf("failed to get system prompt: %w", err))
+	}
+	geminiAgent, err := deps.newAgent(context.Background(), agent.GeminiConfig{
+		ProjectID:             projectID,
+		Region:                region,
+		Model:                 config.LLMModel,
+		SystemPrompt:          systemPrompt,
+		Tools:                 toolset,
+		FunctionCallOnly:      true,
+		AnswerTools:           []string{"reply"},
+		CacheDisplayName:      "yuruppu-system-prompt",
+		CacheTTL:              llmCacheTTL,
+		LogPayloads:           config.DebugLLM,
+		MaxToolCallsPerTurn:   config.MaxToolCallsPerTurn,
+		ToolRetries:           config.ToolSystemErrorRetries,
+		BreakerThreshold:      config.LLMBreakerThreshold,
+		BreakerCooldown:       time.Duration(config.LLMBreakerCooldownSeconds) * time.Second,
+		MaxSystemPromptLength: config.LLMMaxSystemPromptLength,
+		Labels:                config.LLMLabels,
+	}, logger)
+	if err != nil {
+		return steps.failed(fmt.Errorf("failed to initialize Gemini agent: %w", err))
+	}
+	// Closing waits for in-flight generations, then cleans up the cache and API connections
+	defer closeAgent(geminiAgent, "Gemini agent", time.Duration(config.ShutdownTimeoutSeconds)*time.Second, logger)
+	steps.done(slog.String("model", config.LLMModel))
 
 	// Create media service
+	steps.begin("handler ready")
 	mediaStorage, err := storage.NewGCSStorage(gcsClient, config.BucketName, "media/")
 	if err != nil {
-		logger.Error("failed to create media storage", slog.Any("error", err))
-		os.Exit(1)
+		return steps.failed(fmt.Errorf("failed to create media storage: %w", err))
 	}
 	mediaSvc, err := media.NewService(mediaStorage, logger)
 	if err != nil {
-		logger.Error("failed to create media service", slog.Any("error", err))
-		os.Exit(1)
+		return steps.failed(fmt.Errorf("failed to create media service: %w", err))
 	}
 
 	// Create message handler
 	handlerConfig := bot.HandlerConfig{
 		TypingIndicatorDelay:   time.Duration(config.TypingIndicatorDelaySeconds) * time.Second,
 		TypingIndicatorTimeout: time.Duration(config.TypingIndicatorTimeoutSeconds) * time.Second,
+		EmptyResponseReply:     config.EmptyResponseReply,
+		SafetyBlockedReply:     config.SafetyBlockedReply,
+		SkipInactiveGroups:     config.BotPresenceCheck,
+		MaxHistoryTurns:        config.MaxHistoryTurns,
 	}
 	messageHandler, err := bot.NewHandler(lineClient, userProfileService, groupProfileService, historySvc, mediaSvc, geminiAgent, handlerConfig, logger)
 	if err != nil {
-		logger.Error("failed to create message handler", slog.Any("error", err))
-		os.Exit(1)
+		return steps.failed(fmt.Errorf("failed to create message handler: %w", err))
 	}
 
 	// Register message handler
 	lineServer.RegisterHandler(messageHandler)
+	steps.done()
 
-	// Create HTTP server with graceful shutdown support
-	mux := http.NewServeMux()
-	mux.HandleFunc(config.Endpoint, lineServer.HandleWebhook)
-	httpServer := &http.Server{
-		Addr:              ":" + config.Port,
-		Handler:           mux,
-		ReadHeaderTimeout: 10 * time.Second, // Prevent Slowloris attacks
+	// Start the reminder dispatcher
+	steps.begin("background jobs started")
+	dispatcherOpts := []reminder.Option{reminder.WithGroupProfiles(groupProfileService)}
+	if config.ReminderCreatorConfirmation {
+		dispatcherOpts = append(dispatcherOpts, reminder.WithCreatorConfirmation(eventService, userProfileService))
+	}
+	reminderDispatcher, err := reminder.NewDispatcher(reminderService, lineClient, time.Duration(config.ReminderIntervalSeconds)*time.Second, logger, dispatcherOpts...)
+	if err != nil {
+		return steps.failed(fmt.Errorf("failed to create reminder dispatcher: %w", err))
 	}
 
-	// Setup signal handling for graceful shutdown
-	shutdown := make(chan os.Signal, 1)
-	signal.Notify(shutdown, os.Interrupt, syscall.SIGTERM)
+	// Create the expired event sweeper only when a retention is configured
+	var eventSweeper *eventdomain.Sweeper
+	if config.EventRetentionDays > 0 {
+		eventSweeper, err = eventdomain.NewSweeper(eventService, time.Duration(config.EventRetentionDays)*24*time.Hour, eventSweepInterval, logger)
+		if err != nil {
+			return steps.failed(fmt.Errorf("failed to create event sweeper: %w", err))
+		}
+	}
 
-	// Start HTTP server in a goroutine
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	dispatcherDone := make(chan struct{})
 	go func() {
-		logger.Info("server starting",
-			slog.String("endpoint", config.Endpoint),
-			slog.String("port", config.Port),
-		)
-		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			logger.Error("server failed", slog.Any("error", err))
-			os.Exit(1)
+		defer close(dispatcherDone)
+		reminderDispatcher.Run(jobsCtx)
+	}()
+	sweeperDone := make(chan struct{})
+	go func() {
+		defer close(sweeperDone)
+		if eventSweeper != nil {
+			eventSweeper.Run(jobsCtx)
 		}
 	}()
+	steps.done()
+
+	// Accept webhooks now that the handler is in place
+	lineServer.MarkReady()
+	logger.Info("server ready")
 
-	// Wait for shutdown signal
-	<-shutdown
-	logger.Info("shutdown signal received, initiating graceful shutdown")
+	// Wait for shutdown signal, or shut down the same way if the server stops serving
+	var runErr error
+	select {
+	case <-ctx.Done():
+		logger.Info("shutdown signal received, initiating graceful shutdown")
+	case err := <-serveErr:
+		logger.Error("server failed, initiating graceful shutdown", slog.Any("error", err))
+		runErr = fmt.Errorf("server failed: %w", err)
+	}
 
 	// Create context with timeout for graceful shutdown
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Duration(config.ShutdownTimeoutSeconds)*time.Second)
 	defer cancel()
 
 	// Shutdown HTTP server gracefully
@@ -428,15 +1294,10 @@ func main() {
 		logger.Error("failed to shutdown HTTP server gracefully", slog.Any("error", err))
 	}
 
-	// Close Gemini agent (cleans up cache and API connections)
-	if err := geminiAgent.Close(shutdownCtx); err != nil {
-		logger.Error("failed to close Gemini agent", slog.Any("error", err))
-	}
+	// Stop reminder dispatcher and event sweeper
+	stopJobs()
+	<-dispatcherDone
+	<-sweeperDone
 
-	// Close GCS client
-	if err := gcsClient.Close(); err != nil {
-		logger.Error("failed to close GCS client", slog.Any("error", err))
-	}
-
-	logger.Info("graceful shutdown completed")
+	return runErr
 }
@@ -1,12 +1,32 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+	"yuruppu/internal/agent"
+	"yuruppu/internal/history"
+	lineclient "yuruppu/internal/line/client"
+	"yuruppu/internal/toolset/event/card"
 
+	gcsstorage "cloud.google.com/go/storage"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/api/option"
 )
 
 // setRequiredEnvVars sets all required environment variables for loadConfig tests.
@@ -1359,3 +1379,1495 @@ func TestLoadConfig_BucketName_TrimsWhitespace(t *testing.T) {
 		})
 	}
 }
+
+// =============================================================================
+// MAX_CONCURRENT_HANDLERS Tests
+// =============================================================================
+
+// TestLoadConfig_MaxConcurrentHandlers tests handler concurrency limit loading.
+func TestLoadConfig_MaxConcurrentHandlers(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         string
+		expected    int
+		wantErrMsg  string
+		expectError bool
+	}{
+		{
+			name:     "default is 10 when not set",
+			env:      "",
+			expected: 10,
+		},
+		{
+			name:     "custom value from environment variable",
+			env:      "1",
+			expected: 1,
+		},
+		{
+			name:        "zero value returns error",
+			env:         "0",
+			wantErrMsg:  "MAX_CONCURRENT_HANDLERS must be a positive integer",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: Set required environment variables
+			setRequiredEnvVars(t)
+			if tt.env != "" {
+				t.Setenv("MAX_CONCURRENT_HANDLERS", tt.env)
+			} else {
+				os.Unsetenv("MAX_CONCURRENT_HANDLERS")
+			}
+
+			// When: Load configuration
+			config, err := loadConfig()
+
+			// Then
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Nil(t, config)
+				assert.Contains(t, err.Error(), tt.wantErrMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, config.MaxConcurrentHandlers)
+		})
+	}
+}
+
+// =============================================================================
+// Outbound HTTP Client Tests
+// =============================================================================
+
+// TestLoadConfig_OutboundHTTP tests outbound HTTP client settings loading.
+func TestLoadConfig_OutboundHTTP(t *testing.T) {
+	t.Run("defaults when not set", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		os.Unsetenv("OUTBOUND_TIMEOUT_SECONDS")
+		os.Unsetenv("OUTBOUND_MAX_IDLE_CONNS")
+		os.Unsetenv("OUTBOUND_MAX_IDLE_CONNS_PER_HOST")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, 10, config.OutboundTimeoutSeconds)
+		assert.Equal(t, 100, config.OutboundMaxIdleConns)
+		assert.Equal(t, 10, config.OutboundMaxIdleConnsPerHost)
+	})
+
+	t.Run("custom values from environment variables", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("OUTBOUND_TIMEOUT_SECONDS", "3")
+		t.Setenv("OUTBOUND_MAX_IDLE_CONNS", "20")
+		t.Setenv("OUTBOUND_MAX_IDLE_CONNS_PER_HOST", "4")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, config.OutboundTimeoutSeconds)
+		assert.Equal(t, 20, config.OutboundMaxIdleConns)
+		assert.Equal(t, 4, config.OutboundMaxIdleConnsPerHost)
+	})
+
+	t.Run("invalid value returns error", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("OUTBOUND_MAX_IDLE_CONNS_PER_HOST", "0")
+
+		config, err := loadConfig()
+
+		require.Error(t, err)
+		assert.Nil(t, config)
+		assert.Contains(t, err.Error(), "OUTBOUND_MAX_IDLE_CONNS_PER_HOST must be a positive integer")
+	})
+}
+
+// TestNewOutboundHTTPClient tests that the client applies configured pooling and timeout.
+func TestNewOutboundHTTPClient(t *testing.T) {
+	client := newOutboundHTTPClient(&Config{
+		OutboundTimeoutSeconds:      3,
+		OutboundMaxIdleConns:        20,
+		OutboundMaxIdleConnsPerHost: 4,
+	})
+
+	assert.Equal(t, 3*time.Second, client.Timeout)
+	transport, ok := client.Transport.(*http.Transport)
+	require.True(t, ok)
+	assert.Equal(t, 20, transport.MaxIdleConns)
+	assert.Equal(t, 4, transport.MaxIdleConnsPerHost)
+}
+
+// =============================================================================
+// REMINDER_INTERVAL_SECONDS Tests
+// =============================================================================
+
+// TestLoadConfig_ReminderIntervalSeconds tests reminder dispatch interval loading.
+func TestLoadConfig_ReminderIntervalSeconds(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         string
+		expected    int
+		wantErrMsg  string
+		expectError bool
+	}{
+		{
+			name:     "default is 60 when not set",
+			env:      "",
+			expected: 60,
+		},
+		{
+			name:     "custom value from environment variable",
+			env:      "15",
+			expected: 15,
+		},
+		{
+			name:        "zero value returns error",
+			env:         "0",
+			wantErrMsg:  "REMINDER_INTERVAL_SECONDS must be a positive integer",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: Set required environment variables
+			setRequiredEnvVars(t)
+			if tt.env != "" {
+				t.Setenv("REMINDER_INTERVAL_SECONDS", tt.env)
+			} else {
+				os.Unsetenv("REMINDER_INTERVAL_SECONDS")
+			}
+
+			// When: Load configuration
+			config, err := loadConfig()
+
+			// Then
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Nil(t, config)
+				assert.Contains(t, err.Error(), tt.wantErrMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, config.ReminderIntervalSeconds)
+		})
+	}
+}
+
+func TestLoadConfig_ShutdownTimeoutSeconds(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         string
+		expected    int
+		wantErrMsg  string
+		expectError bool
+	}{
+		{
+			name:     "default is 30 when not set",
+			env:      "",
+			expected: 30,
+		},
+		{
+			name:     "custom value from environment variable",
+			env:      "120",
+			expected: 120,
+		},
+		{
+			name:        "zero value returns error",
+			env:         "0",
+			wantErrMsg:  "SHUTDOWN_TIMEOUT_SECONDS must be a positive integer",
+			expectError: true,
+		},
+		{
+			name:        "negative value returns error",
+			env:         "-5",
+			wantErrMsg:  "SHUTDOWN_TIMEOUT_SECONDS must be a positive integer",
+			expectError: true,
+		},
+		{
+			name:        "non-integer value returns error",
+			env:         "30s",
+			wantErrMsg:  "SHUTDOWN_TIMEOUT_SECONDS",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: Set required environment variables
+			setRequiredEnvVars(t)
+			if tt.env != "" {
+				t.Setenv("SHUTDOWN_TIMEOUT_SECONDS", tt.env)
+			} else {
+				os.Unsetenv("SHUTDOWN_TIMEOUT_SECONDS")
+			}
+
+			// When: Load configuration
+			config, err := loadConfig()
+
+			// Then
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Nil(t, config)
+				assert.Contains(t, err.Error(), tt.wantErrMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, config.ShutdownTimeoutSeconds)
+		})
+	}
+}
+
+// =============================================================================
+// BOT_NAME / BOT_PERSONA_TRAITS Tests
+// =============================================================================
+
+// TestLoadConfig_PromptVars tests system prompt variable loading.
+func TestLoadConfig_PromptVars(t *testing.T) {
+	t.Run("defaults when not set", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		os.Unsetenv("BOT_NAME")
+		os.Unsetenv("BOT_PERSONA_TRAITS")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, "ゆるっぷくん", config.BotName)
+		assert.Empty(t, config.PersonaTraits)
+	})
+
+	t.Run("custom values are trimmed and split", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("BOT_NAME", "  テストくん  ")
+		t.Setenv("BOT_PERSONA_TRAITS", "甘いものが大好き, ,歌が得意 ")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, "テストくん", config.BotName)
+		assert.Equal(t, []string{"甘いものが大好き", "歌が得意"}, config.PersonaTraits)
+	})
+}
+
+// =============================================================================
+// Event Default Configuration Tests
+// =============================================================================
+
+func TestLoadConfig_EventDefaults(t *testing.T) {
+	t.Run("defaults to unlimited capacity and empty fee", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		os.Unsetenv("EVENT_DEFAULT_CAPACITY")
+		os.Unsetenv("EVENT_DEFAULT_FEE")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, config.EventDefaultCapacity)
+		assert.Empty(t, config.EventDefaultFee)
+	})
+
+	t.Run("reads values from environment variables", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("EVENT_DEFAULT_CAPACITY", "30")
+		t.Setenv("EVENT_DEFAULT_FEE", "  無料  ")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, 30, config.EventDefaultCapacity)
+		assert.Equal(t, "無料", config.EventDefaultFee)
+	})
+
+	t.Run("negative capacity returns error", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("EVENT_DEFAULT_CAPACITY", "-1")
+
+		config, err := loadConfig()
+
+		require.Error(t, err)
+		assert.Nil(t, config)
+		assert.Contains(t, err.Error(), "EVENT_DEFAULT_CAPACITY must be a non-negative integer")
+	})
+
+	t.Run("defaults to unlimited upcoming events per creator", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		os.Unsetenv("EVENT_MAX_UPCOMING_PER_CREATOR")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, config.EventMaxUpcomingPerCreator)
+	})
+
+	t.Run("reads max events per creator from environment variable", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("EVENT_MAX_UPCOMING_PER_CREATOR", "3")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, config.EventMaxUpcomingPerCreator)
+	})
+
+	t.Run("negative max events per creator returns error", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("EVENT_MAX_UPCOMING_PER_CREATOR", "-1")
+
+		config, err := loadConfig()
+
+		require.Error(t, err)
+		assert.Nil(t, config)
+		assert.Contains(t, err.Error(), "EVENT_MAX_UPCOMING_PER_CREATOR must be a non-negative integer")
+	})
+
+	t.Run("defaults to no minimum lead time", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		os.Unsetenv("EVENT_MIN_LEAD_MINUTES")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, config.EventMinLeadMinutes)
+	})
+
+	t.Run("reads minimum lead time from environment variable", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("EVENT_MIN_LEAD_MINUTES", "30")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, 30, config.EventMinLeadMinutes)
+	})
+
+	t.Run("negative minimum lead time returns error", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("EVENT_MIN_LEAD_MINUTES", "-5")
+
+		config, err := loadConfig()
+
+		require.Error(t, err)
+		assert.Nil(t, config)
+		assert.Contains(t, err.Error(), "EVENT_MIN_LEAD_MINUTES must be a non-negative integer")
+	})
+
+	t.Run("defaults list window to today onward", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		os.Unsetenv("EVENT_LIST_DEFAULT_START_OFFSET_DAYS")
+		os.Unsetenv("EVENT_LIST_DEFAULT_SPAN_DAYS")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, config.EventListDefaultStartOffset)
+		assert.Equal(t, 0, config.EventListDefaultSpanDays)
+	})
+
+	t.Run("reads list window from environment variables", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("EVENT_LIST_DEFAULT_START_OFFSET_DAYS", "-1")
+		t.Setenv("EVENT_LIST_DEFAULT_SPAN_DAYS", "7")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, -1, config.EventListDefaultStartOffset)
+		assert.Equal(t, 7, config.EventListDefaultSpanDays)
+	})
+
+	t.Run("non-integer list window offset returns error", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("EVENT_LIST_DEFAULT_START_OFFSET_DAYS", "yesterday")
+
+		config, err := loadConfig()
+
+		require.Error(t, err)
+		assert.Nil(t, config)
+		assert.Contains(t, err.Error(), "EVENT_LIST_DEFAULT_START_OFFSET_DAYS must be an integer")
+	})
+
+	t.Run("list window span longer than the max period returns error", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("EVENT_LIST_MAX_PERIOD_DAYS", "30")
+		t.Setenv("EVENT_LIST_DEFAULT_SPAN_DAYS", "31")
+
+		config, err := loadConfig()
+
+		require.Error(t, err)
+		assert.Nil(t, config)
+		assert.Contains(t, err.Error(), "EVENT_LIST_DEFAULT_SPAN_DAYS must not exceed EVENT_LIST_MAX_PERIOD_DAYS")
+	})
+
+	t.Run("defaults carousel size to LINE's limit", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		os.Unsetenv("EVENT_CAROUSEL_SIZE")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, card.MaxCarouselSize, config.EventCarouselSize)
+	})
+
+	t.Run("reads carousel size from environment variable", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("EVENT_CAROUSEL_SIZE", "8")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, 8, config.EventCarouselSize)
+	})
+
+	t.Run("carousel size above LINE's limit returns error", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("EVENT_CAROUSEL_SIZE", "13")
+
+		config, err := loadConfig()
+
+		require.Error(t, err)
+		assert.Nil(t, config)
+		assert.Contains(t, err.Error(), "EVENT_CAROUSEL_SIZE must not exceed LINE's carousel limit (12): 13")
+	})
+
+	t.Run("list limit above the carousel size returns error", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("EVENT_CAROUSEL_SIZE", "4")
+		t.Setenv("EVENT_LIST_LIMIT", "5")
+
+		config, err := loadConfig()
+
+		require.Error(t, err)
+		assert.Nil(t, config)
+		assert.Contains(t, err.Error(), "EVENT_LIST_LIMIT must not exceed EVENT_CAROUSEL_SIZE (4): 5")
+	})
+
+	t.Run("defaults to generous text length limits", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		os.Unsetenv("EVENT_MAX_TITLE_LENGTH")
+		os.Unsetenv("EVENT_MAX_DESCRIPTION_LENGTH")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, 200, config.EventMaxTitleLength)
+		assert.Equal(t, 2000, config.EventMaxDescriptionLength)
+	})
+
+	t.Run("reads text length limits from environment variables", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("EVENT_MAX_TITLE_LENGTH", "50")
+		t.Setenv("EVENT_MAX_DESCRIPTION_LENGTH", "500")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, 50, config.EventMaxTitleLength)
+		assert.Equal(t, 500, config.EventMaxDescriptionLength)
+	})
+
+	t.Run("zero description length limit returns error", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("EVENT_MAX_DESCRIPTION_LENGTH", "0")
+
+		config, err := loadConfig()
+
+		require.Error(t, err)
+		assert.Nil(t, config)
+		assert.Contains(t, err.Error(), "EVENT_MAX_DESCRIPTION_LENGTH")
+	})
+
+	t.Run("keeps ended events by default", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		os.Unsetenv("EVENT_RETENTION_DAYS")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, config.EventRetentionDays)
+	})
+
+	t.Run("loads the retention for ended events", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("EVENT_RETENTION_DAYS", "30")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, 30, config.EventRetentionDays)
+	})
+
+	t.Run("negative retention returns error", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("EVENT_RETENTION_DAYS", "-1")
+
+		config, err := loadConfig()
+
+		require.Error(t, err)
+		assert.Nil(t, config)
+		assert.Contains(t, err.Error(), "EVENT_RETENTION_DAYS must be a non-negative integer")
+	})
+}
+
+// =============================================================================
+// Storage Encryption Configuration Tests
+// =============================================================================
+
+func TestLoadConfig_StorageEncryptionKey(t *testing.T) {
+	t.Run("no key by default", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		os.Unsetenv("STORAGE_ENCRYPTION_KEY")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Nil(t, config.StorageEncryptionKey)
+	})
+
+	t.Run("decodes a base64 key", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		key := bytes.Repeat([]byte{0x01}, 32)
+		t.Setenv("STORAGE_ENCRYPTION_KEY", base64.StdEncoding.EncodeToString(key))
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, key, config.StorageEncryptionKey)
+	})
+
+	t.Run("invalid base64 returns error", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("STORAGE_ENCRYPTION_KEY", "not base64!")
+
+		config, err := loadConfig()
+
+		require.Error(t, err)
+		assert.Nil(t, config)
+		assert.Contains(t, err.Error(), "STORAGE_ENCRYPTION_KEY")
+	})
+
+	t.Run("wrong key length returns error", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("STORAGE_ENCRYPTION_KEY", base64.StdEncoding.EncodeToString([]byte("too short")))
+
+		config, err := loadConfig()
+
+		require.Error(t, err)
+		assert.Nil(t, config)
+		assert.Contains(t, err.Error(), "16, 24, or 32 byte key")
+	})
+}
+
+// =============================================================================
+// History Keying Configuration Tests
+// =============================================================================
+
+func TestLoadConfig_HistoryKeying(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         string
+		expected    history.Keying
+		expectError bool
+	}{
+		{name: "default is shared", env: "", expected: history.KeyingShared},
+		{name: "shared", env: "shared", expected: history.KeyingShared},
+		{name: "per_user", env: "per_user", expected: history.KeyingPerUser},
+		{name: "unknown value returns error", env: "per_group", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRequiredEnvVars(t)
+			if tt.env != "" {
+				t.Setenv("HISTORY_KEYING", tt.env)
+			} else {
+				os.Unsetenv("HISTORY_KEYING")
+			}
+
+			config, err := loadConfig()
+
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Nil(t, config)
+				assert.Contains(t, err.Error(), "HISTORY_KEYING")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, config.HistoryKeying)
+		})
+	}
+}
+
+// =============================================================================
+// Config Check Report Tests
+// =============================================================================
+
+func TestWriteConfigReport(t *testing.T) {
+	passing := configCheck{name: "bucket existence", run: func(ctx context.Context, config *Config) error { return nil }}
+	failing := configCheck{name: "metadata server", run: func(ctx context.Context, config *Config) error { return errors.New("not running on GCE") }}
+
+	t.Run("valid config passes and redacts secrets", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("STORAGE_ENCRYPTION_KEY", base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{0x01}, 32)))
+		var buf bytes.Buffer
+
+		ok := writeConfigReport(context.Background(), &buf, []configCheck{passing})
+
+		assert.True(t, ok)
+		out := buf.String()
+		assert.Contains(t, out, "PASS ENDPOINT=/webhook\n")
+		assert.Contains(t, out, "PASS BUCKET_NAME=test-bucket\n")
+		assert.Contains(t, out, "PASS LINE_CHANNEL_SECRET=(redacted)\n")
+		assert.Contains(t, out, "PASS LINE_CHANNEL_ACCESS_TOKEN=(redacted)\n")
+		assert.Contains(t, out, "PASS STORAGE_ENCRYPTION_KEY=(redacted)\n")
+		assert.Contains(t, out, "PASS bucket existence\n")
+		assert.NotContains(t, out, "test-secret")
+		assert.NotContains(t, out, "test-token")
+		assert.NotContains(t, out, "FAIL")
+	})
+
+	t.Run("missing required var fails", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("BUCKET_NAME", "")
+		var buf bytes.Buffer
+
+		ok := writeConfigReport(context.Background(), &buf, []configCheck{passing})
+
+		assert.False(t, ok)
+		assert.Equal(t, "FAIL config: BUCKET_NAME is required\n", buf.String())
+	})
+
+	t.Run("failing check fails the report", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		var buf bytes.Buffer
+
+		ok := writeConfigReport(context.Background(), &buf, []configCheck{failing, passing})
+
+		assert.False(t, ok)
+		out := buf.String()
+		assert.Contains(t, out, "FAIL metadata server: not running on GCE\n")
+		assert.Contains(t, out, "PASS bucket existence\n")
+	})
+}
+
+// =============================================================================
+// DEBUG_LLM Configuration Tests
+// =============================================================================
+
+func TestLoadConfig_DebugLLM(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         string
+		expected    bool
+		expectError bool
+	}{
+		{name: "off by default", env: "", expected: false},
+		{name: "true", env: "true", expected: true},
+		{name: "1", env: "1", expected: true},
+		{name: "false", env: "false", expected: false},
+		{name: "invalid value returns error", env: "yes please", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRequiredEnvVars(t)
+			if tt.env != "" {
+				t.Setenv("DEBUG_LLM", tt.env)
+			} else {
+				os.Unsetenv("DEBUG_LLM")
+			}
+
+			config, err := loadConfig()
+
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Nil(t, config)
+				assert.Contains(t, err.Error(), "DEBUG_LLM")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, config.DebugLLM)
+		})
+	}
+}
+
+func TestLoadConfig_DisableSignatureCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         string
+		expected    bool
+		expectError bool
+	}{
+		{name: "off by default", env: "", expected: false},
+		{name: "true", env: "true", expected: true},
+		{name: "false", env: "false", expected: false},
+		{name: "invalid value returns error", env: "maybe", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRequiredEnvVars(t)
+			if tt.env != "" {
+				t.Setenv("DISABLE_SIGNATURE_CHECK", tt.env)
+			} else {
+				os.Unsetenv("DISABLE_SIGNATURE_CHECK")
+			}
+
+			config, err := loadConfig()
+
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Nil(t, config)
+				assert.Contains(t, err.Error(), "DISABLE_SIGNATURE_CHECK")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, config.DisableSignatureCheck)
+		})
+	}
+}
+
+// =============================================================================
+// MAX_TOOL_CALLS_PER_TURN Configuration Tests
+// =============================================================================
+
+func TestLoadConfig_MaxToolCallsPerTurn(t *testing.T) {
+	t.Run("defaults to unlimited", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		os.Unsetenv("MAX_TOOL_CALLS_PER_TURN")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, config.MaxToolCallsPerTurn)
+	})
+
+	t.Run("reads value from environment variable", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("MAX_TOOL_CALLS_PER_TURN", "8")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, 8, config.MaxToolCallsPerTurn)
+	})
+
+	t.Run("negative value returns error", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("MAX_TOOL_CALLS_PER_TURN", "-1")
+
+		config, err := loadConfig()
+
+		require.Error(t, err)
+		assert.Nil(t, config)
+		assert.Contains(t, err.Error(), "MAX_TOOL_CALLS_PER_TURN must be a non-negative integer")
+	})
+}
+
+func TestLoadConfig_MaxHistoryTurns(t *testing.T) {
+	t.Run("defaults to the whole history", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		os.Unsetenv("MAX_HISTORY_TURNS")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, config.MaxHistoryTurns)
+	})
+
+	t.Run("reads value from environment variable", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("MAX_HISTORY_TURNS", "20")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, 20, config.MaxHistoryTurns)
+	})
+
+	t.Run("negative value returns error", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("MAX_HISTORY_TURNS", "-1")
+
+		config, err := loadConfig()
+
+		require.Error(t, err)
+		assert.Nil(t, config)
+		assert.Contains(t, err.Error(), "MAX_HISTORY_TURNS must be a non-negative integer")
+	})
+}
+
+func TestLoadConfig_ToolSystemErrorRetries(t *testing.T) {
+	t.Run("defaults to no retries", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		os.Unsetenv("TOOL_SYSTEM_ERROR_RETRIES")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, config.ToolSystemErrorRetries)
+	})
+
+	t.Run("reads value from environment variable", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("TOOL_SYSTEM_ERROR_RETRIES", "2")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, config.ToolSystemErrorRetries)
+	})
+
+	t.Run("negative value returns error", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("TOOL_SYSTEM_ERROR_RETRIES", "-1")
+
+		config, err := loadConfig()
+
+		require.Error(t, err)
+		assert.Nil(t, config)
+		assert.Contains(t, err.Error(), "TOOL_SYSTEM_ERROR_RETRIES must be a non-negative integer")
+	})
+}
+
+// =============================================================================
+// LLM Circuit Breaker Configuration Tests
+// =============================================================================
+
+func TestLoadConfig_LLMBreaker(t *testing.T) {
+	t.Run("uses defaults", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		os.Unsetenv("LLM_BREAKER_THRESHOLD")
+		os.Unsetenv("LLM_BREAKER_COOLDOWN_SECONDS")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, 5, config.LLMBreakerThreshold)
+		assert.Equal(t, 30, config.LLMBreakerCooldownSeconds)
+	})
+
+	t.Run("reads values from environment variables", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("LLM_BREAKER_THRESHOLD", "3")
+		t.Setenv("LLM_BREAKER_COOLDOWN_SECONDS", "120")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, config.LLMBreakerThreshold)
+		assert.Equal(t, 120, config.LLMBreakerCooldownSeconds)
+	})
+
+	t.Run("zero threshold disables the breaker", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("LLM_BREAKER_THRESHOLD", "0")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, config.LLMBreakerThreshold)
+	})
+
+	t.Run("negative threshold returns error", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("LLM_BREAKER_THRESHOLD", "-1")
+
+		config, err := loadConfig()
+
+		require.Error(t, err)
+		assert.Nil(t, config)
+		assert.Contains(t, err.Error(), "LLM_BREAKER_THRESHOLD must be a non-negative integer")
+	})
+
+	t.Run("zero cooldown returns error", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("LLM_BREAKER_COOLDOWN_SECONDS", "0")
+
+		config, err := loadConfig()
+
+		require.Error(t, err)
+		assert.Nil(t, config)
+		assert.Contains(t, err.Error(), "LLM_BREAKER_COOLDOWN_SECONDS must be a positive integer")
+	})
+}
+
+func TestLoadConfig_LLMMaxSystemPromptLength(t *testing.T) {
+	t.Run("defaults to 32000", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		os.Unsetenv("LLM_MAX_SYSTEM_PROMPT_LENGTH")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, 32000, config.LLMMaxSystemPromptLength)
+	})
+
+	t.Run("custom value", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("LLM_MAX_SYSTEM_PROMPT_LENGTH", "50000")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, 50000, config.LLMMaxSystemPromptLength)
+	})
+
+	t.Run("zero returns error", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("LLM_MAX_SYSTEM_PROMPT_LENGTH", "0")
+
+		config, err := loadConfig()
+
+		require.Error(t, err)
+		assert.Nil(t, config)
+		assert.Contains(t, err.Error(), "LLM_MAX_SYSTEM_PROMPT_LENGTH must be a positive integer")
+	})
+}
+
+func TestLoadConfig_LLMLabels(t *testing.T) {
+	t.Run("no labels by default", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		os.Unsetenv("LLM_LABELS")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Nil(t, config.LLMLabels)
+	})
+
+	t.Run("parses trimmed key=value pairs", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("LLM_LABELS", " environment = prod ,, deployment=blue-2")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"environment": "prod", "deployment": "blue-2"}, config.LLMLabels)
+	})
+
+	t.Run("pair without = returns error", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("LLM_LABELS", "environment")
+
+		config, err := loadConfig()
+
+		require.Error(t, err)
+		assert.Nil(t, config)
+		assert.Contains(t, err.Error(), "LLM_LABELS must be comma-separated key=value pairs")
+	})
+
+	t.Run("duplicate key returns error", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("LLM_LABELS", "environment=prod,environment=dev")
+
+		config, err := loadConfig()
+
+		require.Error(t, err)
+		assert.Nil(t, config)
+		assert.Contains(t, err.Error(), "LLM_LABELS has a duplicate key: environment")
+	})
+
+	t.Run("label violating GCP constraints returns error", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("LLM_LABELS", "Environment=prod")
+
+		config, err := loadConfig()
+
+		require.Error(t, err)
+		assert.Nil(t, config)
+		assert.Contains(t, err.Error(), `LLM_LABELS: invalid label key "Environment"`)
+	})
+}
+
+// =============================================================================
+// WEATHER_PROVIDER Configuration Tests
+// =============================================================================
+
+func TestLoadConfig_WeatherProvider(t *testing.T) {
+	t.Run("defaults to wttr", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		os.Unsetenv("WEATHER_PROVIDER")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, "wttr", config.WeatherProvider)
+	})
+
+	t.Run("accepts a known provider", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("WEATHER_PROVIDER", " wttr ")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, "wttr", config.WeatherProvider)
+	})
+
+	t.Run("unknown provider returns error", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("WEATHER_PROVIDER", "openweather")
+
+		config, err := loadConfig()
+
+		require.Error(t, err)
+		assert.Nil(t, config)
+		assert.Contains(t, err.Error(), "WEATHER_PROVIDER")
+	})
+}
+
+// =============================================================================
+// REMINDER_CREATOR_CONFIRMATION Configuration Tests
+// =============================================================================
+
+func TestLoadConfig_ReminderCreatorConfirmation(t *testing.T) {
+	t.Run("off by default", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		os.Unsetenv("REMINDER_CREATOR_CONFIRMATION")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.False(t, config.ReminderCreatorConfirmation)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("REMINDER_CREATOR_CONFIRMATION", "true")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.True(t, config.ReminderCreatorConfirmation)
+	})
+
+	t.Run("invalid value returns error", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("REMINDER_CREATOR_CONFIRMATION", "sometimes")
+
+		config, err := loadConfig()
+
+		require.Error(t, err)
+		assert.Nil(t, config)
+		assert.Contains(t, err.Error(), "REMINDER_CREATOR_CONFIRMATION")
+	})
+}
+
+// =============================================================================
+// REPLY_CONVERT_MARKDOWN Configuration Tests
+// =============================================================================
+
+func TestLoadConfig_ReplyConvertMarkdown(t *testing.T) {
+	t.Run("on by default", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		os.Unsetenv("REPLY_CONVERT_MARKDOWN")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.True(t, config.ReplyConvertMarkdown)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("REPLY_CONVERT_MARKDOWN", "false")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.False(t, config.ReplyConvertMarkdown)
+	})
+
+	t.Run("invalid value returns error", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("REPLY_CONVERT_MARKDOWN", "maybe")
+
+		config, err := loadConfig()
+
+		require.Error(t, err)
+		assert.Nil(t, config)
+		assert.Contains(t, err.Error(), "REPLY_CONVERT_MARKDOWN")
+	})
+}
+
+// =============================================================================
+// BOT_PRESENCE_CHECK Configuration Tests
+// =============================================================================
+
+func TestLoadConfig_BotPresenceCheck(t *testing.T) {
+	t.Run("on by default", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		os.Unsetenv("BOT_PRESENCE_CHECK")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.True(t, config.BotPresenceCheck)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("BOT_PRESENCE_CHECK", "false")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.False(t, config.BotPresenceCheck)
+	})
+
+	t.Run("invalid value returns error", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("BOT_PRESENCE_CHECK", "maybe")
+
+		config, err := loadConfig()
+
+		require.Error(t, err)
+		assert.Nil(t, config)
+		assert.Contains(t, err.Error(), "BOT_PRESENCE_CHECK")
+	})
+}
+
+// =============================================================================
+// EMPTY_RESPONSE_REPLY Configuration Tests
+// =============================================================================
+
+func TestLoadConfig_EmptyResponseReply(t *testing.T) {
+	t.Run("defaults when not set", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		os.Unsetenv("EMPTY_RESPONSE_REPLY")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, "ごめん、うまく答えられなかった", config.EmptyResponseReply)
+	})
+
+	t.Run("custom value is trimmed", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("EMPTY_RESPONSE_REPLY", "  もう一回言って？  ")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, "もう一回言って？", config.EmptyResponseReply)
+	})
+}
+
+// =============================================================================
+// SAFETY_BLOCKED_REPLY Configuration Tests
+// =============================================================================
+
+func TestLoadConfig_SafetyBlockedReply(t *testing.T) {
+	t.Run("defaults when not set", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		os.Unsetenv("SAFETY_BLOCKED_REPLY")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, "ごめんね、その話にはうまく答えられないんだ", config.SafetyBlockedReply)
+	})
+
+	t.Run("custom value is trimmed", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("SAFETY_BLOCKED_REPLY", "  別の話をしよう！  ")
+
+		config, err := loadConfig()
+
+		require.NoError(t, err)
+		assert.Equal(t, "別の話をしよう！", config.SafetyBlockedReply)
+	})
+}
+
+// =============================================================================
+// run Startup Tests
+// =============================================================================
+
+func TestRun_StartupSteps(t *testing.T) {
+	t.Run("logs every step in order on success", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("PORT", "0")
+		config, err := loadConfig()
+		require.NoError(t, err)
+
+		deps := fakeStartupDeps(t)
+		var logs bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+		// A done context makes run shut down as soon as startup completes
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+		err = run(ctx, config, logger, deps)
+
+		require.NoError(t, err)
+		var steps []string
+		var messages []string
+		for line := range strings.Lines(logs.String()) {
+			var entry struct {
+				Msg  string `json:"msg"`
+				Step string `json:"step"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(line), &entry))
+			messages = append(messages, entry.Msg)
+			if entry.Msg == "startup step completed" {
+				steps = append(steps, entry.Step)
+			}
+		}
+		assert.Equal(t, []string{
+			"LINE client ready",
+			"server listening",
+			"metadata resolved",
+			"storage ready",
+			"history enabled",
+			"tools ready",
+			"agent ready",
+			"handler ready",
+			"background jobs started",
+		}, steps)
+		assert.Contains(t, messages, "server ready")
+		assert.NotContains(t, messages, "startup step failed")
+	})
+
+	t.Run("logs the failing step and returns its error", func(t *testing.T) {
+		setRequiredEnvVars(t)
+		t.Setenv("PORT", "0")
+		config, err := loadConfig()
+		require.NoError(t, err)
+
+		deps := startupDeps{
+			listen:         net.Listen,
+			lineClientOpts: []lineclient.Option{lineclient.WithAPIEndpoint("http://127.0.0.1:0")},
+			newGCSClient: func(ctx context.Context) (*gcsstorage.Client, error) {
+				return nil, errors.New("no credentials")
+			},
+			resolveMetadata: func(ctx context.Context) (string, string, error) {
+				return "", "", errors.New("not running on GCE")
+			},
+		}
+		var logs bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+		err = run(t.Context(), config, logger, deps)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to create GCS client")
+		assert.Contains(t, logs.String(), `"msg":"startup step failed","step":"storage ready"`)
+		assert.Contains(t, logs.String(), `"step":"metadata resolved"`)
+	})
+}
+
+func TestRun_ClosesAgentsOnStartupFailure(t *testing.T) {
+	setRequiredEnvVars(t)
+	t.Setenv("PORT", "0")
+	config, err := loadConfig()
+	require.NoError(t, err)
+
+	// The description writer is created first; the main agent then fails to start
+	deps := fakeStartupDeps(t)
+	writer := &stubAgent{}
+	calls := 0
+	deps.newAgent = func(ctx context.Context, cfg agent.GeminiConfig, logger *slog.Logger) (closableAgent, error) {
+		calls++
+		if calls == 1 {
+			return writer, nil
+		}
+		return nil, errors.New("model not found")
+	}
+
+	err = run(t.Context(), config, slog.New(slog.DiscardHandler), deps)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to initialize Gemini agent")
+	assert.True(t, writer.closed.Load(), "writer agent should be closed when a later step fails")
+}
+
+func TestRun_ReturnsServerFailure(t *testing.T) {
+	setRequiredEnvVars(t)
+	config, err := loadConfig()
+	require.NoError(t, err)
+
+	deps := fakeStartupDeps(t)
+	deps.listen = func(network, address string) (net.Listener, error) {
+		ln, err := net.Listen(network, "127.0.0.1:0")
+		if err != nil {
+			return nil, err
+		}
+		return failingListener{ln}, nil
+	}
+	stub := &stubAgent{}
+	deps.newAgent = func(ctx context.Context, cfg agent.GeminiConfig, logger *slog.Logger) (closableAgent, error) {
+		return stub, nil
+	}
+	var logs syncBuffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+	runErr := make(chan error, 1)
+	go func() { runErr <- run(t.Context(), config, logger, deps) }()
+
+	select {
+	case err := <-runErr:
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "server failed")
+		assert.Contains(t, err.Error(), "accept failed")
+	case <-time.After(5 * time.Second):
+		t.Fatal("run did not return after the server failed")
+	}
+	assert.True(t, stub.closed.Load(), "agents should be closed after a server failure")
+	assert.Contains(t, logs.String(), `"msg":"server failed, initiating graceful shutdown"`)
+}
+
+func TestRun_ServesAndShutsDown(t *testing.T) {
+	setRequiredEnvVars(t)
+	config, err := loadConfig()
+	require.NoError(t, err)
+
+	deps := fakeStartupDeps(t)
+	addrCh := make(chan string, 1)
+	deps.listen = func(network, address string) (net.Listener, error) {
+		ln, err := net.Listen(network, "127.0.0.1:0")
+		if err == nil {
+			addrCh <- ln.Addr().String()
+		}
+		return ln, err
+	}
+	stub := &stubAgent{}
+	deps.newAgent = func(ctx context.Context, cfg agent.GeminiConfig, logger *slog.Logger) (closableAgent, error) {
+		return stub, nil
+	}
+	var logs syncBuffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	runErr := make(chan error, 1)
+	go func() { runErr <- run(ctx, config, logger, deps) }()
+
+	var addr string
+	select {
+	case addr = <-addrCh:
+	case err := <-runErr:
+		t.Fatalf("run returned before listening: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("run did not start listening")
+	}
+	baseURL := "http://" + addr
+	// Without keep-alives no idle connection can hold up the graceful shutdown
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+
+	// Wait until startup completes and the health check reports ready
+	require.Eventually(t, func() bool {
+		resp, err := client.Get(baseURL + "/healthz")
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode == http.StatusOK
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// A correctly signed webhook with no events is accepted
+	body := []byte(`{"destination":"Ubot","events":[]}`)
+	req, err := http.NewRequest(http.MethodPost, baseURL+config.Endpoint, bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("X-Line-Signature", sign(config.ChannelSecret, body))
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// A webhook with a bad signature is rejected
+	req, err = http.NewRequest(http.MethodPost, baseURL+config.Endpoint, bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("X-Line-Signature", "invalid")
+	resp, err = client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	cancel()
+	select {
+	case err := <-runErr:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("run did not return after shutdown")
+	}
+
+	assert.True(t, stub.closed.Load(), "agent should be closed on shutdown")
+	assert.Contains(t, logs.String(), `"msg":"shutdown signal received, initiating graceful shutdown"`)
+	assert.NotContains(t, logs.String(), "failed to shutdown HTTP server")
+	assert.NotContains(t, logs.String(), "failed to close Gemini agent")
+	_, err = net.DialTimeout("tcp", addr, time.Second)
+	assert.Error(t, err, "server should no longer accept connections")
+}
+
+// fakeStartupDeps returns startup dependencies backed by a local fake API server,
+// so run can start without LINE, GCS, or Gemini credentials.
+func fakeStartupDeps(t *testing.T) startupDeps {
+	t.Helper()
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"userId":"Ubot","displayName":"Yuruppu"}`))
+	}))
+	t.Cleanup(api.Close)
+	return startupDeps{
+		listen:         net.Listen,
+		lineClientOpts: []lineclient.Option{lineclient.WithAPIEndpoint(api.URL)},
+		newGCSClient: func(ctx context.Context) (*gcsstorage.Client, error) {
+			return gcsstorage.NewClient(ctx, option.WithoutAuthentication(), option.WithEndpoint(api.URL))
+		},
+		resolveMetadata: func(ctx context.Context) (string, string, error) {
+			return "test-project", "asia-northeast1", nil
+		},
+		newAgent: func(ctx context.Context, cfg agent.GeminiConfig, logger *slog.Logger) (closableAgent, error) {
+			return &stubAgent{}, nil
+		},
+	}
+}
+
+// sign computes the X-Line-Signature header value for body.
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent log writes.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// failingListener makes Serve fail as if the socket broke.
+type failingListener struct {
+	net.Listener
+}
+
+func (l failingListener) Accept() (net.Conn, error) {
+	return nil, errors.New("accept failed")
+}
+
+// stubAgent stands in for the Gemini agent in run tests.
+type stubAgent struct {
+	closed atomic.Bool
+}
+
+func (a *stubAgent) Generate(ctx context.Context, hist []agent.Message) (*agent.AssistantMessage, error) {
+	return &agent.AssistantMessage{}, nil
+}
+
+func (a *stubAgent) Close(ctx context.Context) error {
+	a.closed.Store(true)
+	return nil
+}